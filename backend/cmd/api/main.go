@@ -10,12 +10,15 @@ import (
 	"time"
 
 	"github.com/jagadeesh/grainlify/backend/internal/api"
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
 	"github.com/jagadeesh/grainlify/backend/internal/bus"
 	"github.com/jagadeesh/grainlify/backend/internal/bus/natsbus"
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/dryrun"
+	"github.com/jagadeesh/grainlify/backend/internal/jobs"
 	"github.com/jagadeesh/grainlify/backend/internal/migrate"
-	"github.com/jagadeesh/grainlify/backend/internal/syncjobs"
+	"github.com/jagadeesh/grainlify/backend/internal/selfcheck"
 )
 
 func main() {
@@ -31,6 +34,31 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
+	if config.HasSecretRefs() {
+		go config.WatchSecrets(context.Background(), time.Duration(cfg.SecretsRefreshMinutes)*time.Minute)
+	}
+
+	// `api check` validates config, the database and the GitHub Apps, prints what to fix and exits
+	// non-zero on any failure. Use it as a deploy pre-flight.
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		failed := selfcheck.Print(os.Stdout, selfcheck.Online(ctx, cfg))
+		cancel()
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	for _, r := range selfcheck.Static(cfg) {
+		switch r.Status {
+		case selfcheck.Fail:
+			slog.Error("config check failed", "check", r.Check, "detail", r.Detail, "fix", r.Fix)
+		case selfcheck.Warn:
+			slog.Warn("config check warning", "check", r.Check, "detail", r.Detail, "fix", r.Fix)
+		}
+	}
+
 	// Log configuration (mask sensitive values)
 	slog.Info("configuration loaded", "step", "3", "action", "configuration_loaded",
 		"env", cfg.Env,
@@ -62,7 +90,7 @@ func main() {
 		slog.Info("parsing db url", "step", "4.1", "action", "parsing_db_url", "db_url_length", len(cfg.DBURL))
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		slog.Info("attempting db connection", "step", "4.2", "action", "attempting_db_connection", "timeout", "10s")
-		d, err := db.Connect(ctx, cfg.DBURL)
+		d, err := db.ConnectRotating(ctx, cfg.DatabaseURL)
 		cancel()
 		if err != nil {
 			slog.Error("db connection failed", "step", "4", "action", "db_connection_failed",
@@ -138,23 +166,33 @@ func main() {
 	app := api.New(cfg, api.Deps{DB: database, Bus: eventBus})
 	slog.Info("api initialized", "step", "7", "action", "api_initialized")
 
-	// Background workers (dev convenience). In production we run `cmd/worker` instead.
-	// If NATS is configured, prefer the external worker process.
-	if cfg.NATSURL == "" && database != nil && database.Pool != nil {
-		slog.Info("starting background worker", "step", "8", "action", "starting_background_worker")
-		worker := syncjobs.New(cfg, database.Pool)
+	if database != nil {
+		dryrun.Configure(cfg.GitHubDryRun, database.Pool)
+	} else {
+		dryrun.Configure(cfg.GitHubDryRun, nil)
+	}
+	if cfg.GitHubDryRun {
+		slog.Warn("GITHUB_DRY_RUN is on: GitHub mutations are recorded but not performed")
+	}
+
+	// GitHub API usage counters are collected by every process that calls GitHub, so flush them regardless of role.
+	if database != nil && database.Pool != nil {
+		usageFlusher := apiusage.New(database.Pool)
 		go func() {
-			slog.Info("background worker started")
-			_ = worker.Run(context.Background())
+			_ = usageFlusher.Run(context.Background())
 		}()
+	}
 
-		// GitHub App cleanup is now handled via webhooks (installation.deleted events)
-		// No need for periodic polling
+	// Background jobs run in exactly one kind of process. With NATS configured, the API publishes webhooks
+	// for `cmd/worker`, which also runs the jobs; without it, the API runs them itself.
+	if cfg.NATSURL == "" && database != nil && database.Pool != nil {
+		slog.Info("starting background worker", "step", "8", "action", "starting_background_worker")
+		jobs.Start(context.Background(), cfg, database)
 	} else {
 		slog.Info("background worker skipped", "step", "8", "action", "background_worker_skipped",
 			"reason", func() string {
 				if cfg.NATSURL != "" {
-					return "NATS configured (use external worker)"
+					return "NATS configured (jobs run in cmd/worker)"
 				}
 				if database == nil {
 					return "database not available"
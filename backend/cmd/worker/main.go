@@ -1,14 +1,80 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/dryrun"
+	"github.com/jagadeesh/grainlify/backend/internal/ingest"
+	"github.com/jagadeesh/grainlify/backend/internal/jobs"
+	"github.com/jagadeesh/grainlify/backend/internal/worker"
 )
 
-// Worker entrypoint placeholder.
-//
-// This repo currently does not ship a worker binary in this workspace snapshot,
-// but Go tooling expects a valid package in ./cmd/worker.
+// The worker runs the background jobs (see package jobs) and ingests the GitHub webhooks the API publishes
+// to NATS. Deployments with NATS_URL set run one worker next to the API processes, which then leave the
+// jobs to it; without NATS the API runs the jobs itself and this binary isn't needed.
 func main() {
-	log.Println("worker is not implemented in this build")
-}
+	config.LoadDotenv()
+	cfg := config.Load()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: cfg.LogLevel()})))
+	slog.Info("=== Grainlify worker starting ===", "env", cfg.Env, "nats_url_set", cfg.NATSURL != "")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if config.HasSecretRefs() {
+		go config.WatchSecrets(ctx, time.Duration(cfg.SecretsRefreshMinutes)*time.Minute)
+	}
+	if cfg.DBURL == "" {
+		slog.Error("DB_URL is required for the worker")
+		os.Exit(1)
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	database, err := db.ConnectRotating(connectCtx, cfg.DatabaseURL)
+	cancel()
+	if err != nil {
+		slog.Error("db connection failed", "error", err, "error_type", fmt.Sprintf("%T", err))
+		os.Exit(1)
+	}
+	defer database.Close()
 
+	dryrun.Configure(cfg.GitHubDryRun, database.Pool)
+	if cfg.GitHubDryRun {
+		slog.Warn("GITHUB_DRY_RUN is on: GitHub mutations are recorded but not performed")
+	}
+	usageFlusher := apiusage.New(database.Pool)
+	go func() {
+		_ = usageFlusher.Run(ctx)
+	}()
+
+	if cfg.NATSURL != "" {
+		nc, err := nats.Connect(cfg.NATSURL, nats.Name("grainlify-worker"), nats.Timeout(5*time.Second), nats.RetryOnFailedConnect(true))
+		if err != nil {
+			slog.Error("nats connection failed", "error", err)
+			os.Exit(1)
+		}
+		defer nc.Close()
+		consumer := &worker.GitHubWebhookConsumer{Ingest: &ingest.GitHubWebhookIngestor{Pool: database.Pool}}
+		if err := consumer.Subscribe(ctx, nc, ""); err != nil {
+			slog.Error("webhook subscription failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("consuming github webhooks from nats")
+	}
+
+	jobs.Start(ctx, cfg, database)
+	slog.Info("=== Grainlify worker started ===")
+
+	<-ctx.Done()
+	slog.Info("shutdown signal received, worker stopping")
+}
@@ -0,0 +1,223 @@
+// Package activity turns raw GitHub webhook events (and Grainlify's own application records) into a
+// curated, human-readable feed: who did what to which issue or pull request.
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Feed item types.
+const (
+	IssueOpened           = "issue_opened"
+	IssueClosed           = "issue_closed"
+	IssueReopened         = "issue_reopened"
+	ApplicationReceived   = "application_received" // not for private applications (projects.application_visibility)
+	ContributorAssigned   = "contributor_assigned"
+	ContributorUnassigned = "contributor_unassigned"
+	PROpened              = "pr_opened"
+	PRMerged              = "pr_merged"
+	PRClosed              = "pr_closed"
+	PRReviewed            = "pr_reviewed"
+)
+
+// applicationEvent is the synthetic event name used for rows coming from issue_applications.
+const applicationEvent = "grainlify_application"
+
+// typeConditions selects the github_events rows (aliased ge) for each feed type.
+var typeConditions = map[string]string{
+	IssueOpened:           `ge.event = 'issues' AND ge.action = 'opened'`,
+	IssueClosed:           `ge.event = 'issues' AND ge.action = 'closed'`,
+	IssueReopened:         `ge.event = 'issues' AND ge.action = 'reopened'`,
+	ContributorAssigned:   `ge.event = 'issues' AND ge.action = 'assigned'`,
+	ContributorUnassigned: `ge.event = 'issues' AND ge.action = 'unassigned'`,
+	PROpened:              `ge.event = 'pull_request' AND ge.action = 'opened'`,
+	PRMerged:              `ge.event = 'pull_request' AND ge.action = 'closed' AND COALESCE((ge.payload->'pull_request'->>'merged')::boolean, false)`,
+	PRClosed:              `ge.event = 'pull_request' AND ge.action = 'closed' AND NOT COALESCE((ge.payload->'pull_request'->>'merged')::boolean, false)`,
+	PRReviewed:            `ge.event = 'pull_request_review' AND ge.action = 'submitted'`,
+}
+
+// Types lists every feed type in display order.
+var Types = []string{
+	IssueOpened, ApplicationReceived, ContributorAssigned, ContributorUnassigned,
+	PROpened, PRReviewed, PRMerged, PRClosed, IssueClosed, IssueReopened,
+}
+
+// ValidType reports whether t is a known feed type.
+func ValidType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor is a normalized GitHub user.
+type Actor struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Object is the issue or pull request an item is about.
+type Object struct {
+	Kind   string `json:"kind"` // issue | pull_request
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+}
+
+// Item is one entry of the activity feed.
+type Item struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	ProjectID    string    `json:"project_id"`
+	RepoFullName string    `json:"repo_full_name"`
+	Actor        *Actor    `json:"actor"`
+	Subject      *Actor    `json:"subject,omitempty"` // the assignee for (un)assignments
+	Object       *Object   `json:"object"`
+	Summary      string    `json:"summary"`
+	At           time.Time `json:"at"`
+}
+
+type ghUser struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type ghObject struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+}
+
+type payload struct {
+	Sender      *ghUser   `json:"sender"`
+	Assignee    *ghUser   `json:"assignee"`
+	Issue       *ghObject `json:"issue"`
+	PullRequest *ghObject `json:"pull_request"`
+	Review      *struct {
+		State string `json:"state"`
+	} `json:"review"`
+}
+
+// Normalize maps one stored event to a feed item. ok is false for events that are not part of the feed.
+func Normalize(event, action string, raw []byte) (Item, bool) {
+	var p payload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return Item{}, false
+	}
+
+	var it Item
+	switch {
+	case event == "issues" && p.Issue != nil:
+		it.Object = object("issue", p.Issue)
+		switch action {
+		case "opened":
+			it.Type = IssueOpened
+		case "closed":
+			it.Type = IssueClosed
+		case "reopened":
+			it.Type = IssueReopened
+		case "assigned":
+			it.Type = ContributorAssigned
+		case "unassigned":
+			it.Type = ContributorUnassigned
+		default:
+			return Item{}, false
+		}
+	case event == "pull_request" && p.PullRequest != nil:
+		it.Object = object("pull_request", p.PullRequest)
+		switch {
+		case action == "opened":
+			it.Type = PROpened
+		case action == "closed" && p.PullRequest.Merged:
+			it.Type = PRMerged
+		case action == "closed":
+			it.Type = PRClosed
+		default:
+			return Item{}, false
+		}
+	case event == "pull_request_review" && action == "submitted" && p.PullRequest != nil:
+		it.Type = PRReviewed
+		it.Object = object("pull_request", p.PullRequest)
+	case event == applicationEvent && p.Issue != nil:
+		it.Type = ApplicationReceived
+		it.Object = object("issue", p.Issue)
+	default:
+		return Item{}, false
+	}
+
+	it.Actor = actor(p.Sender)
+	if it.Type == ContributorAssigned || it.Type == ContributorUnassigned {
+		it.Subject = actor(p.Assignee)
+	}
+	reviewState := ""
+	if p.Review != nil {
+		reviewState = p.Review.State
+	}
+	it.Summary = summary(it, reviewState)
+	return it, true
+}
+
+func object(kind string, o *ghObject) *Object {
+	return &Object{Kind: kind, Number: o.Number, Title: o.Title, URL: o.HTMLURL}
+}
+
+func actor(u *ghUser) *Actor {
+	if u == nil || u.Login == "" {
+		return nil
+	}
+	avatar := u.AvatarURL
+	if avatar == "" {
+		avatar = fmt.Sprintf("https://github.com/%s.png?size=200", u.Login)
+	}
+	return &Actor{Login: u.Login, AvatarURL: avatar}
+}
+
+func summary(it Item, reviewState string) string {
+	who := "Someone"
+	if it.Actor != nil {
+		who = "@" + it.Actor.Login
+	}
+	ref := fmt.Sprintf("#%d", it.Object.Number)
+	if it.Object.Title != "" {
+		ref += " " + it.Object.Title
+	}
+	subject := "a contributor"
+	if it.Subject != nil {
+		subject = "@" + it.Subject.Login
+	}
+
+	switch it.Type {
+	case IssueOpened:
+		return fmt.Sprintf("%s opened issue %s", who, ref)
+	case IssueClosed:
+		return fmt.Sprintf("%s closed issue %s", who, ref)
+	case IssueReopened:
+		return fmt.Sprintf("%s reopened issue %s", who, ref)
+	case ApplicationReceived:
+		return fmt.Sprintf("%s applied to work on %s", who, ref)
+	case ContributorAssigned:
+		return fmt.Sprintf("%s was assigned to %s", subject, ref)
+	case ContributorUnassigned:
+		return fmt.Sprintf("%s was unassigned from %s", subject, ref)
+	case PROpened:
+		return fmt.Sprintf("%s opened pull request %s", who, ref)
+	case PRMerged:
+		return fmt.Sprintf("%s merged pull request %s", who, ref)
+	case PRClosed:
+		return fmt.Sprintf("%s closed pull request %s without merging", who, ref)
+	case PRReviewed:
+		switch reviewState {
+		case "approved":
+			return fmt.Sprintf("%s approved pull request %s", who, ref)
+		case "changes_requested":
+			return fmt.Sprintf("%s requested changes on pull request %s", who, ref)
+		}
+		return fmt.Sprintf("%s reviewed pull request %s", who, ref)
+	}
+	return ""
+}
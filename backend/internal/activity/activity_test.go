@@ -0,0 +1,42 @@
+package activity
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		event, action, payload string
+		wantType, wantSummary  string
+	}{
+		{"issues", "opened", `{"sender":{"login":"ada"},"issue":{"number":7,"title":"Fix docs"}}`,
+			IssueOpened, "@ada opened issue #7 Fix docs"},
+		{"issues", "assigned", `{"sender":{"login":"maint"},"assignee":{"login":"bob"},"issue":{"number":3}}`,
+			ContributorAssigned, "@bob was assigned to #3"},
+		{"pull_request", "closed", `{"sender":{"login":"maint"},"pull_request":{"number":9,"title":"Add x","merged":true}}`,
+			PRMerged, "@maint merged pull request #9 Add x"},
+		{"pull_request", "closed", `{"sender":{"login":"maint"},"pull_request":{"number":9,"merged":false}}`,
+			PRClosed, "@maint closed pull request #9 without merging"},
+		{"pull_request_review", "submitted", `{"sender":{"login":"rev"},"review":{"state":"approved"},"pull_request":{"number":2}}`,
+			PRReviewed, "@rev approved pull request #2"},
+		{applicationEvent, "received", `{"sender":{"login":"ada"},"issue":{"number":4,"title":""}}`,
+			ApplicationReceived, "@ada applied to work on #4"},
+	}
+	for _, tc := range cases {
+		it, ok := Normalize(tc.event, tc.action, []byte(tc.payload))
+		if !ok {
+			t.Errorf("%s/%s: not normalized", tc.event, tc.action)
+			continue
+		}
+		if it.Type != tc.wantType || it.Summary != tc.wantSummary {
+			t.Errorf("%s/%s = (%q, %q), want (%q, %q)", tc.event, tc.action, it.Type, it.Summary, tc.wantType, tc.wantSummary)
+		}
+	}
+
+	if it, _ := Normalize("issues", "opened", []byte(`{"sender":{"login":"ada"},"issue":{"number":1}}`)); it.Actor.AvatarURL != "https://github.com/ada.png?size=200" {
+		t.Errorf("avatar fallback = %q", it.Actor.AvatarURL)
+	}
+	for _, skip := range [][2]string{{"issues", "labeled"}, {"push", ""}, {"pull_request", "synchronize"}} {
+		if _, ok := Normalize(skip[0], skip[1], []byte(`{"issue":{"number":1},"pull_request":{"number":1}}`)); ok {
+			t.Errorf("%s/%s should not be part of the feed", skip[0], skip[1])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Filter scopes a feed query. Exactly one of ProjectID and EcosystemID should be set.
+type Filter struct {
+	ProjectID   *uuid.UUID
+	EcosystemID *uuid.UUID
+	Types       []string // empty means every type
+	Limit       int
+	Offset      int
+}
+
+// List returns one page of the feed, newest first, and whether more items follow.
+func List(ctx context.Context, pool *pgxpool.Pool, f Filter) ([]Item, bool, error) {
+	var scope string
+	var scopeID uuid.UUID
+	switch {
+	case f.ProjectID != nil:
+		scope, scopeID = "p.id = $1", *f.ProjectID
+	case f.EcosystemID != nil:
+		scope, scopeID = "p.ecosystem_id = $1 AND p.status = 'verified'", *f.EcosystemID
+	default:
+		return nil, false, fmt.Errorf("activity: project or ecosystem required")
+	}
+
+	types := f.Types
+	if len(types) == 0 {
+		types = Types
+	}
+	var conds []string
+	withApplications := false
+	for _, t := range types {
+		if t == ApplicationReceived {
+			withApplications = true
+		} else if c, ok := typeConditions[t]; ok {
+			conds = append(conds, "("+c+")")
+		}
+	}
+
+	var parts []string
+	if len(conds) > 0 {
+		parts = append(parts, `
+SELECT ge.delivery_id AS id, ge.project_id, COALESCE(ge.repo_full_name, p.github_full_name) AS repo, ge.event, COALESCE(ge.action, '') AS action,
+       ge.payload, ge.received_at AS at
+FROM github_events ge
+JOIN projects p ON p.id = ge.project_id
+WHERE p.deleted_at IS NULL AND `+scope+` AND (`+strings.Join(conds, " OR ")+`)`)
+	}
+	if withApplications {
+		parts = append(parts, `
+SELECT 'application:' || a.id::text, a.project_id, p.github_full_name, '`+applicationEvent+`', 'received',
+       jsonb_build_object(
+         'sender', jsonb_build_object('login', a.github_login),
+         'issue', jsonb_build_object('number', a.issue_number, 'title', COALESCE(gi.title, ''), 'html_url', COALESCE(gi.url, ''))
+       ),
+       a.created_at
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id
+LEFT JOIN github_issues gi ON gi.project_id = a.project_id AND gi.number = a.issue_number
+WHERE p.deleted_at IS NULL AND p.application_visibility <> 'private' AND `+scope)
+	}
+	if len(parts) == 0 {
+		return []Item{}, false, nil
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	offset := f.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := pool.Query(ctx, `
+SELECT id, project_id, repo, event, action, payload, at FROM (`+strings.Join(parts, "\nUNION ALL")+`
+) feed
+ORDER BY at DESC, id DESC
+LIMIT $2 OFFSET $3
+`, scopeID, limit+1, offset)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	items := []Item{}
+	for rows.Next() {
+		var id, repo, event, action string
+		var projectID uuid.UUID
+		var payload []byte
+		var at time.Time
+		if err := rows.Scan(&id, &projectID, &repo, &event, &action, &payload, &at); err != nil {
+			return nil, false, err
+		}
+		it, ok := Normalize(event, action, payload)
+		if !ok {
+			continue
+		}
+		it.ID, it.ProjectID, it.RepoFullName, it.At = id, projectID.String(), repo, at
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	return items, hasMore, nil
+}
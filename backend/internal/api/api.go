@@ -1,29 +1,61 @@
 package api
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/jagadeesh/grainlify/backend/internal/auth"
 	"github.com/jagadeesh/grainlify/backend/internal/bus"
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
 	"github.com/jagadeesh/grainlify/backend/internal/handlers"
+	"github.com/jagadeesh/grainlify/backend/internal/httpcache"
+	"github.com/jagadeesh/grainlify/backend/internal/publicstats"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
 )
 
 type Deps struct {
 	DB  *db.DB
 	Bus bus.Bus
+
+	// GitHub clients for handlers. Nil means the real ones (github.NewClient / github.NewApps).
+	GitHub     github.API
+	GitHubApps github.Apps
 }
 
 func New(cfg config.Config, deps Deps) *fiber.App {
+	if deps.GitHub == nil {
+		deps.GitHub = github.NewClient()
+	}
+	if deps.GitHubApps == nil {
+		var pool *pgxpool.Pool
+		if deps.DB != nil {
+			pool = deps.DB.Pool
+		}
+		deps.GitHubApps = github.NewApps(cfg, pool)
+	}
+
+	var tenants *tenant.Resolver
+	if deps.DB != nil && deps.DB.Pool != nil {
+		tenants = tenant.NewResolver(deps.DB.Pool)
+		// Warm the cache so CORS knows tenant hosts from the first preflight.
+		if _, err := tenants.Default(context.Background()); err != nil {
+			slog.Warn("failed to load tenants", "error", err)
+		}
+	}
+
 	slog.Info("initializing Fiber app",
 		"app_name", "grainlify-api",
 	)
@@ -57,9 +89,14 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 
 	app.Use(recover.New())
 
+	// Compress responses (issue lists embed whole comment threads) and answer conditional GETs with 304.
+	app.Use(compress.New(compress.Config{Level: compress.LevelBestSpeed}))
+	app.Use(httpcache.New())
+
 	// Configure CORS from environment variables
 	corsConfig := cors.Config{
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Admin-Bootstrap-Token",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Admin-Bootstrap-Token, If-Match, If-None-Match, If-Modified-Since",
+		ExposeHeaders:    "ETag, Last-Modified",
 		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 		AllowCredentials: true,
 	}
@@ -103,6 +140,11 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 			return true
 		}
 
+		// Tenants' own domains
+		if tenants.AllowsOrigin(origin) {
+			return true
+		}
+
 		// If FrontendBaseURL is set, allow it (exact match or with path)
 		if cfg.FrontendBaseURL != "" {
 			frontendBase := strings.TrimSuffix(cfg.FrontendBaseURL, "/")
@@ -116,6 +158,7 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 
 	app.Use(cors.New(corsConfig))
 	app.Use(logger.New())
+	app.Use(tenants.Middleware())
 
 	// Routes.
 	// Root handler - also handle POST requests to catch misconfigured webhooks
@@ -143,13 +186,44 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	app.Get("/health", handlers.Health())
 	app.Get("/ready", handlers.Ready(deps.DB))
 
-	authHandler := handlers.NewAuthHandler(cfg, deps.DB)
+	tenantsHandler := handlers.NewTenantsHandler(cfg, deps.DB, tenants)
+	app.Get("/tenant", tenantsHandler.Current())
+
+	authHandler := handlers.NewAuthHandler(cfg, deps.DB, deps.GitHub)
 	authGroup := app.Group("/auth")
 	app.Get("/me", auth.RequireAuth(cfg.JWTSecret), authHandler.Me())
 	app.Post("/me/github/resync", auth.RequireAuth(cfg.JWTSecret), authHandler.ResyncGitHubProfile())
 
+	// Dashboard setup checklist (GitHub linked, App installed, first application, wallet added).
+	onboarding := handlers.NewOnboardingHandler(deps.DB)
+	app.Get("/me/onboarding", auth.RequireAuth(cfg.JWTSecret), onboarding.Status())
+
+	// iCalendar feed of assignment deadlines and campaign dates; calendar apps subscribe with a feed token.
+	calendar := handlers.NewCalendarHandler(cfg, deps.DB)
+	app.Get("/me/deadlines.ics", calendar.Deadlines())
+	app.Post("/me/calendar-feed", auth.RequireAuth(cfg.JWTSecret), calendar.CreateFeed())
+	app.Delete("/me/calendar-feed", auth.RequireAuth(cfg.JWTSecret), calendar.DeleteFeed())
+
+	// Issue recommendations, recomputed periodically from each contributor's history (internal/recommend).
+	recommendations := handlers.NewRecommendationsHandler(deps.DB)
+	app.Get("/me/recommended-issues", auth.RequireAuth(cfg.JWTSecret), recommendations.Issues())
+
+	// Signed statements of completed contributions for grant or job applications (internal/statements).
+	contributionStatements := handlers.NewContributionStatementsHandler(cfg, deps.DB)
+	app.Post("/me/contribution-statements", auth.RequireAuth(cfg.JWTSecret), contributionStatements.Create())
+	app.Get("/me/contribution-statements", auth.RequireAuth(cfg.JWTSecret), contributionStatements.Mine())
+	app.Delete("/me/contribution-statements/:id", auth.RequireAuth(cfg.JWTSecret), contributionStatements.Revoke())
+	app.Get("/verify/contributions/:id", contributionStatements.Verify())
+
+	// Time from assignment to merge, as percentiles per complexity, label or project.
+	completionAnalytics := handlers.NewCompletionAnalyticsHandler(deps.DB)
+	app.Get("/analytics/time-to-complete", auth.RequireAuth(cfg.JWTSecret), completionAnalytics.TimeToComplete())
+
+	// Controlled vocabulary for the skills contributors declare on their profile (internal/skills).
+	app.Get("/skills", handlers.SkillVocabulary())
+
 	// User profile endpoints
-	userProfile := handlers.NewUserProfileHandler(cfg, deps.DB)
+	userProfile := handlers.NewUserProfileHandler(cfg, deps.DB, deps.GitHub)
 	app.Get("/profile", auth.RequireAuth(cfg.JWTSecret), userProfile.Profile())
 	app.Get("/profile/public", userProfile.PublicProfile()) // Public profile endpoint (no auth required)
 	app.Get("/profile/calendar", auth.RequireAuth(cfg.JWTSecret), userProfile.ContributionCalendar())
@@ -158,8 +232,15 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	app.Get("/profile/projects-led", auth.RequireAuth(cfg.JWTSecret), userProfile.ProjectsLed())
 	app.Put("/profile/update", auth.RequireAuth(cfg.JWTSecret), userProfile.UpdateProfile())
 	app.Put("/profile/avatar", auth.RequireAuth(cfg.JWTSecret), userProfile.UpdateAvatar())
-
-	ghOAuth := handlers.NewGitHubOAuthHandler(cfg, deps.DB)
+	app.Get("/profile/availability", auth.RequireAuth(cfg.JWTSecret), userProfile.Availability())
+	app.Put("/profile/availability", auth.RequireAuth(cfg.JWTSecret), userProfile.UpdateAvailability())
+	app.Get("/profile/reminders", auth.RequireAuth(cfg.JWTSecret), userProfile.ReminderPreferences())
+	app.Put("/profile/reminders", auth.RequireAuth(cfg.JWTSecret), userProfile.UpdateReminderPreferences())
+	app.Get("/profile/pause", auth.RequireAuth(cfg.JWTSecret), userProfile.PauseStatus())
+	app.Put("/profile/pause", auth.RequireAuth(cfg.JWTSecret), userProfile.Pause())
+	app.Delete("/profile/pause", auth.RequireAuth(cfg.JWTSecret), userProfile.Resume())
+
+	ghOAuth := handlers.NewGitHubOAuthHandler(cfg, deps.DB, deps.GitHub)
 	// GitHub-only login/signup:
 	authGroup.Get("/github/login/start", ghOAuth.LoginStart())
 	// Alias to unified callback (for backwards compatibility with older callback URLs).
@@ -170,8 +251,20 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	authGroup.Get("/github/callback", ghOAuth.CallbackUnified())
 	authGroup.Get("/github/status", auth.RequireAuth(cfg.JWTSecret), ghOAuth.Status())
 
+	// Google and emailed-link sign-in for admins and observers without GitHub (applying still needs GitHub).
+	identityLogin := handlers.NewIdentityLoginHandler(cfg, deps.DB)
+	authGroup.Get("/google/login/start", identityLogin.GoogleLoginStart())
+	authGroup.Get("/google/login/callback", identityLogin.GoogleLoginCallback())
+	authGroup.Post("/email/start", identityLogin.MagicLinkStart())
+	authGroup.Get("/email/callback", identityLogin.MagicLinkCallback())
+
+	// Per-tenant OpenID Connect SSO; tenants can require it for their admins.
+	sso := handlers.NewSSOHandler(cfg, deps.DB)
+	authGroup.Get("/sso/start", sso.Start())
+	authGroup.Get("/sso/callback", sso.Callback())
+
 	// GitHub App installation endpoints
-	ghApp := handlers.NewGitHubAppHandler(cfg, deps.DB)
+	ghApp := handlers.NewGitHubAppHandler(cfg, deps.DB, deps.GitHubApps)
 	authGroup.Post("/github/app/install/start", auth.RequireAuth(cfg.JWTSecret), ghApp.StartInstallation())
 	app.Get("/auth/github/app/install/callback", ghApp.HandleInstallationCallback())
 
@@ -185,6 +278,15 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	app.Get("/ecosystems", ecosystems.ListActive())
 	app.Get("/ecosystems/:id", ecosystems.GetByID())
 
+	// Curated activity feeds (issues opened, applications, assignments, PRs merged...).
+	activityFeed := handlers.NewActivityHandler(deps.DB)
+	app.Get("/ecosystems/:id/activity", activityFeed.Ecosystem())
+	app.Get("/projects/:id/activity", activityFeed.Project())
+	ghDiscussions := handlers.NewGitHubDiscussionsHandler(cfg, deps.DB)
+	app.Get("/ecosystems/:id/discussion-answers", ghDiscussions.EcosystemAnswers())
+	app.Get("/projects/:id/github-discussions", ghDiscussions.List())
+	app.Put("/projects/:id/github-discussions", auth.RequireAuth(cfg.JWTSecret), ghDiscussions.SetTracking())
+
 	// Open Source Week (public)
 	osw := handlers.NewOpenSourceWeekHandler(deps.DB)
 	app.Get("/open-source-week/events", osw.ListPublic())
@@ -193,18 +295,55 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	// Public leaderboard
 	leaderboard := handlers.NewLeaderboardHandler(deps.DB)
 	app.Get("/leaderboard", leaderboard.Leaderboard())
+	app.Get("/leaderboard/reviewers", leaderboard.Reviewers())
+
+	// Teams (campaign participation + team leaderboard)
+	teams := handlers.NewTeamsHandler(deps.DB)
+	app.Get("/leaderboard/teams", teams.Leaderboard())
+	app.Post("/teams", auth.RequireAuth(cfg.JWTSecret), teams.Create())
+	app.Get("/teams/mine", auth.RequireAuth(cfg.JWTSecret), teams.Mine())
+	app.Get("/teams/invites", auth.RequireAuth(cfg.JWTSecret), teams.MyInvites())
+	app.Post("/teams/invites/:inviteId/accept", auth.RequireAuth(cfg.JWTSecret), teams.RespondInvite(true))
+	app.Post("/teams/invites/:inviteId/decline", auth.RequireAuth(cfg.JWTSecret), teams.RespondInvite(false))
+	app.Get("/teams/:id", teams.Get())
+	app.Post("/teams/:id/invites", auth.RequireAuth(cfg.JWTSecret), teams.Invite())
+	app.Post("/teams/:id/join", auth.RequireAuth(cfg.JWTSecret), teams.Join())
+	app.Post("/teams/:id/leave", auth.RequireAuth(cfg.JWTSecret), teams.Leave())
+	app.Post("/teams/:id/events/:eventId/register", auth.RequireAuth(cfg.JWTSecret), teams.RegisterForEvent())
 
 	// Public landing stats
 	landingStats := handlers.NewLandingStatsHandler(deps.DB)
 	app.Get("/stats/landing", landingStats.Get())
 
+	// Public stats and README badges (no auth; cached, rate limited per IP).
+	statsCache := publicstats.NewCache(5 * time.Minute)
+	publicStats := handlers.NewPublicStatsHandler(deps.DB, statsCache)
+	publicStatsLimit := limiter.New(limiter.Config{
+		Max:        120,
+		Expiration: time.Minute,
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "rate_limited"})
+		},
+	})
+	app.Get("/public/stats/projects/:owner/:repo", publicStatsLimit, publicStats.Project())
+	app.Get("/public/stats/ecosystems/:slug", publicStatsLimit, publicStats.Ecosystem())
+	app.Get("/badge/projects/:id/open-bounties.svg", publicStatsLimit, publicStats.OpenBountiesBadge("svg"))
+	app.Get("/badge/projects/:id/open-bounties.json", publicStatsLimit, publicStats.OpenBountiesBadge("json"))
+
+	// Sitemap, page data (with OpenGraph fields) for the marketing site, and Atom feeds of open issues.
+	seoHandler := handlers.NewSEOHandler(cfg, deps.DB, statsCache)
+	app.Get("/sitemap.xml", seoHandler.Sitemap())
+	app.Get("/public/pages/projects/:owner/:repo", publicStatsLimit, seoHandler.Project())
+	app.Get("/public/pages/ecosystems/:slug", publicStatsLimit, seoHandler.Ecosystem())
+	app.Get("/ecosystems/:id/issues.atom", publicStatsLimit, seoHandler.EcosystemIssuesFeed())
+
 	// Public projects list with filtering
-	projectsPublic := handlers.NewProjectsPublicHandler(cfg, deps.DB)
+	projectsPublic := handlers.NewProjectsPublicHandler(cfg, deps.DB, deps.GitHub, deps.GitHubApps)
 	app.Get("/projects", projectsPublic.List())
 	app.Get("/projects/recommended", projectsPublic.Recommended())
 	app.Get("/projects/filters", projectsPublic.FilterOptions())
 
-	projects := handlers.NewProjectsHandler(cfg, deps.DB)
+	projects := handlers.NewProjectsHandler(cfg, deps.DB, deps.GitHub)
 	app.Post("/projects", auth.RequireAuth(cfg.JWTSecret), projects.Create())
 	// IMPORTANT: /projects/mine and /projects/pending-setup must come BEFORE /projects/:id to avoid route conflict
 	app.Get("/projects/mine", auth.RequireAuth(cfg.JWTSecret), projects.Mine())
@@ -213,45 +352,283 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	// These routes with :id must come AFTER specific routes like /projects/mine
 	app.Get("/projects/:id", projectsPublic.Get())
 	app.Put("/projects/:id/metadata", auth.RequireAuth(cfg.JWTSecret), projects.UpdateMetadata())
+	app.Get("/projects/:id/metadata", auth.RequireAuth(cfg.JWTSecret), projects.GetMetadata())
+	app.Patch("/projects/:id/metadata", auth.RequireAuth(cfg.JWTSecret), projects.PatchMetadata())
+	app.Get("/projects/:id/subprojects", projects.ListSubProjects())
+	app.Post("/projects/:id/subprojects", auth.RequireAuth(cfg.JWTSecret), projects.CreateSubProject())
+	app.Patch("/projects/:id/subprojects/:subId", auth.RequireAuth(cfg.JWTSecret), projects.UpdateSubProject())
+	app.Delete("/projects/:id/subprojects/:subId", auth.RequireAuth(cfg.JWTSecret), projects.DeleteSubProject())
+	app.Put("/projects/:id/subprojects/:subId/maintainers", auth.RequireAuth(cfg.JWTSecret), projects.SetSubProjectMaintainers())
+	app.Get("/projects/:id/subprojects/:slug/issues", projects.SubProjectIssues())
+	app.Get("/projects/:id/pr-flags", auth.RequireAuth(cfg.JWTSecret), projects.PRFlags())
+	app.Post("/projects/:id/pr-flags/:number/review", auth.RequireAuth(cfg.JWTSecret), projects.ReviewPRFlags())
+	app.Get("/projects/:id/pr-similarity", auth.RequireAuth(cfg.JWTSecret), projects.PRSimilarity())
+	app.Post("/projects/:id/pr-similarity/:number/:similar/review", auth.RequireAuth(cfg.JWTSecret), projects.ReviewPRSimilarity())
+	app.Put("/projects/:id/quick-apply", auth.RequireAuth(cfg.JWTSecret), projects.SetQuickApply())
+	app.Put("/projects/:id/application-visibility", auth.RequireAuth(cfg.JWTSecret), projects.SetApplicationVisibility())
+	botMessages := handlers.NewBotMessagesHandler(cfg, deps.DB)
+	app.Get("/projects/:id/bot-messages", auth.RequireAuth(cfg.JWTSecret), botMessages.Project())
+	app.Put("/projects/:id/bot-messages", auth.RequireAuth(cfg.JWTSecret), botMessages.UpdateProject())
+	app.Post("/projects/:id/reactivate", auth.RequireAuth(cfg.JWTSecret), projects.Reactivate())
 	app.Get("/projects/:id/issues/public", projectsPublic.IssuesPublic())
 	app.Get("/projects/:id/prs/public", projectsPublic.PRsPublic())
+	// Issues from many projects in one call, for dashboards listing a contributor's applications.
+	app.Post("/issues/batch", publicStatsLimit, projectsPublic.IssuesBatch())
+	app.Get("/projects/:id/reviewers", leaderboard.Reviewers())
+	app.Get("/projects/:id/health", projectsPublic.Health())
+	app.Get("/projects/:id/docs", projectsPublic.Docs())
 	app.Post("/projects/:id/verify", auth.RequireAuth(cfg.JWTSecret), projects.Verify())
 
 	sync := handlers.NewSyncHandler(deps.DB)
 	app.Post("/projects/:id/sync", auth.RequireAuth(cfg.JWTSecret), sync.EnqueueFullSync())
 	app.Get("/projects/:id/sync/jobs", auth.RequireAuth(cfg.JWTSecret), sync.JobsForProject())
+	app.Post("/projects/:id/reconcile", auth.RequireAuth(cfg.JWTSecret), projects.Reconcile())
 
 	data := handlers.NewProjectDataHandler(deps.DB)
 	app.Get("/projects/:id/issues", auth.RequireAuth(cfg.JWTSecret), data.Issues())
 	app.Get("/projects/:id/prs", auth.RequireAuth(cfg.JWTSecret), data.PRs())
 	app.Get("/projects/:id/events", auth.RequireAuth(cfg.JWTSecret), data.Events())
 
-	issueApps := handlers.NewIssueApplicationsHandler(cfg, deps.DB)
+	issueApps := handlers.NewIssueApplicationsHandler(cfg, deps.DB, deps.GitHub, deps.GitHubApps)
+	dryRun := handlers.NewGitHubDryRunHandler(deps.DB)
 	app.Post("/projects/:id/issues/:number/apply", auth.RequireAuth(cfg.JWTSecret), issueApps.Apply())
+	app.Post("/projects/:id/issues/:number/apply/preview", auth.RequireAuth(cfg.JWTSecret), issueApps.ApplyPreview())
 	app.Post("/projects/:id/issues/:number/bot-comment", auth.RequireAuth(cfg.JWTSecret), issueApps.PostBotComment())
-	app.Post("/projects/:id/issues/:number/withdraw", auth.RequireAuth(cfg.JWTSecret), issueApps.Withdraw())
-	app.Post("/projects/:id/issues/:number/assign", auth.RequireAuth(cfg.JWTSecret), issueApps.Assign())
-	app.Post("/projects/:id/issues/:number/unassign", auth.RequireAuth(cfg.JWTSecret), issueApps.Unassign())
-	app.Post("/projects/:id/issues/:number/reject", auth.RequireAuth(cfg.JWTSecret), issueApps.Reject())
+	app.Put("/projects/:id/issues/:number/bot-comment/:commentId", auth.RequireAuth(cfg.JWTSecret), issueApps.UpdateBotComment())
+	app.Delete("/projects/:id/issues/:number/bot-comment/:commentId", auth.RequireAuth(cfg.JWTSecret), issueApps.DeleteBotComment())
+	app.Put("/projects/:id/issues/:number/status", auth.RequireAuth(cfg.JWTSecret), issueApps.UpdateIssueStatus())
+	// Suggested complexity/points from labels, description, code areas and similar completed issues.
+	app.Get("/projects/:id/issues/:number/estimate", auth.RequireAuth(cfg.JWTSecret), issueApps.Estimate())
+	app.Post("/projects/:id/issues/:number/estimate/accept", auth.RequireAuth(cfg.JWTSecret), issueApps.AcceptEstimate())
+	// Issue rewards, escrowed against the ecosystem budget while the issue is assigned.
+	app.Get("/projects/:id/issues/:number/reward", issueApps.IssueReward())
+	app.Put("/projects/:id/issues/:number/reward", auth.RequireAuth(cfg.JWTSecret), issueApps.SetIssueReward())
+	app.Post("/projects/:id/issues/:number/withdraw", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.Withdraw())
+	app.Post("/projects/:id/issues/:number/assign", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.Assign())
+	app.Post("/projects/:id/issues/:number/unassign", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.Unassign())
+	app.Post("/projects/:id/issues/:number/reject", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.Reject())
+	app.Post("/projects/:id/issues/:number/close", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.CloseIssue())
+	app.Post("/projects/:id/issues/:number/reopen", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.ReopenIssue())
+	app.Post("/projects/:id/issues/:number/labels", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.AddIssueLabels())
+	app.Delete("/projects/:id/issues/:number/labels/:name", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.RemoveIssueLabel())
+	app.Post("/projects/:id/labels/standard", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.SetupStandardLabels())
+	app.Get("/projects/:id/tasks", issueApps.ListTasks())
+	app.Post("/projects/:id/tasks", auth.RequireAuth(cfg.JWTSecret), issueApps.CreateTask())
+	app.Patch("/projects/:id/tasks/:number", auth.RequireAuth(cfg.JWTSecret), issueApps.UpdateTask())
+	app.Get("/projects/:id/issue-drafts", auth.RequireAuth(cfg.JWTSecret), issueApps.ListIssueDrafts())
+	app.Post("/projects/:id/issue-drafts", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.CreateIssueDraft())
+	app.Patch("/projects/:id/issue-drafts/:draftId", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.UpdateIssueDraft())
+	app.Delete("/projects/:id/issue-drafts/:draftId", auth.RequireAuth(cfg.JWTSecret), issueApps.DeleteIssueDraft())
+	app.Post("/projects/:id/issue-drafts/:draftId/publish", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.PublishIssueDraft())
+	app.Get("/projects/:id/issue-templates", auth.RequireAuth(cfg.JWTSecret), issueApps.ProjectIssueTemplates())
+	app.Post("/projects/:id/issue-templates/:key/draft", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.DraftFromTemplate())
+	app.Post("/projects/:id/issues/:number/promote", auth.RequireAuth(cfg.JWTSecret), issueApps.PromoteIssue())
+	app.Delete("/projects/:id/issues/:number/promote", auth.RequireAuth(cfg.JWTSecret), issueApps.UnpromoteIssue())
+	app.Get("/projects/:id/issues/:number/promotions", auth.RequireAuth(cfg.JWTSecret), issueApps.IssuePromotions())
+	// Undoable assign/reject (body "undoable": true) wait handlers.UndoWindow before reaching GitHub.
+	app.Get("/projects/:id/issues/:number/pending-actions", auth.RequireAuth(cfg.JWTSecret), issueApps.PendingActions())
+	app.Delete("/projects/:id/issues/:number/pending-actions/:actionId", auth.RequireAuth(cfg.JWTSecret), issueApps.UndoPendingAction())
+	// Assigns, rejects and bot comments that failed on GitHub's side are queued for retry (202 + queued).
+	app.Get("/me/pending-actions", auth.RequireAuth(cfg.JWTSecret), issueApps.MyPendingActions())
+	app.Post("/applications/bulk", auth.RequireAuth(cfg.JWTSecret), dryRun.Mark(), issueApps.BulkApplications())
+	app.Get("/projects/:id/issues/:number/applications", auth.RequireAuth(cfg.JWTSecret), issueApps.Applicants())
+	app.Get("/projects/:id/issues/:number/applications/:applicationId/stages", auth.RequireAuth(cfg.JWTSecret), issueApps.ApplicationStages())
+	app.Post("/projects/:id/issues/:number/applications/:applicationId/stage", auth.RequireAuth(cfg.JWTSecret), issueApps.SetApplicationStage())
+	app.Get("/projects/:id/issues/:number/waitlist", auth.RequireAuth(cfg.JWTSecret), issueApps.Waitlist())
+	app.Post("/projects/:id/issues/:number/waitlist", auth.RequireAuth(cfg.JWTSecret), issueApps.JoinWaitlist())
+	app.Delete("/projects/:id/issues/:number/waitlist", auth.RequireAuth(cfg.JWTSecret), issueApps.LeaveWaitlist())
+	app.Get("/projects/:id/issues/:number/extension-requests", auth.RequireAuth(cfg.JWTSecret), issueApps.ListExtensions())
+	app.Post("/projects/:id/issues/:number/extension-requests", auth.RequireAuth(cfg.JWTSecret), issueApps.RequestExtension())
+	app.Post("/projects/:id/issues/:number/extension-requests/:requestId/decision", auth.RequireAuth(cfg.JWTSecret), issueApps.DecideExtension())
+	app.Get("/projects/:id/issues/:number/handoffs", auth.RequireAuth(cfg.JWTSecret), issueApps.ListHandoffs())
+	app.Post("/projects/:id/issues/:number/handoffs", auth.RequireAuth(cfg.JWTSecret), issueApps.RequestHandoff())
+	app.Post("/projects/:id/issues/:number/handoffs/:handoffId/decision", auth.RequireAuth(cfg.JWTSecret), issueApps.DecideHandoff())
+	app.Post("/projects/:id/issues/:number/handoffs/:handoffId/cancel", auth.RequireAuth(cfg.JWTSecret), issueApps.CancelHandoff())
+	app.Get("/projects/:id/issues/:number/credit", auth.RequireAuth(cfg.JWTSecret), issueApps.Credit())
+	app.Put("/projects/:id/issues/:number/credit", auth.RequireAuth(cfg.JWTSecret), issueApps.SetCredit())
+
+	// Data exports (CSV/JSON). Large ones are queued; the download link is signed, so it works without a session.
+	exportsHandler := handlers.NewExportsHandler(cfg, deps.DB)
+	app.Get("/exports/:dataset", auth.RequireAuth(cfg.JWTSecret), exportsHandler.Export())
+	app.Get("/export-jobs", auth.RequireAuth(cfg.JWTSecret), exportsHandler.Jobs())
+	app.Get("/export-jobs/:id", auth.RequireAuth(cfg.JWTSecret), exportsHandler.Job())
+	app.Get("/export-jobs/:id/download", exportsHandler.Download())
+
+	// Bot comment templates and scheduled bot comments
+	botTemplates := handlers.NewBotTemplatesHandler(deps.DB)
+	app.Get("/projects/:id/bot-templates", auth.RequireAuth(cfg.JWTSecret), botTemplates.List())
+	app.Post("/projects/:id/bot-templates", auth.RequireAuth(cfg.JWTSecret), botTemplates.Create())
+	app.Put("/projects/:id/bot-templates/:templateId", auth.RequireAuth(cfg.JWTSecret), botTemplates.Update())
+	app.Delete("/projects/:id/bot-templates/:templateId", auth.RequireAuth(cfg.JWTSecret), botTemplates.Delete())
+	app.Get("/projects/:id/scheduled-comments", auth.RequireAuth(cfg.JWTSecret), botTemplates.Scheduled())
+	app.Delete("/projects/:id/scheduled-comments/:scheduledId", auth.RequireAuth(cfg.JWTSecret), botTemplates.CancelScheduled())
+
+	// Q&A checklist applicants answer before assignment
+	questions := handlers.NewApplicationQuestionsHandler(deps.DB)
+	app.Get("/projects/:id/application-questions", questions.List())
+	app.Post("/projects/:id/application-questions", auth.RequireAuth(cfg.JWTSecret), questions.Create())
+	app.Put("/projects/:id/application-questions/:questionId", auth.RequireAuth(cfg.JWTSecret), questions.Update())
+	app.Delete("/projects/:id/application-questions/:questionId", auth.RequireAuth(cfg.JWTSecret), questions.Delete())
+	app.Get("/projects/:id/issues/:number/answers", auth.RequireAuth(cfg.JWTSecret), questions.Answers())
+	app.Put("/projects/:id/issues/:number/answers", auth.RequireAuth(cfg.JWTSecret), questions.SubmitAnswers())
+
+	// Private (Grainlify-only) discussion threads between maintainers and applicants
+	discussions := handlers.NewIssueDiscussionsHandler(deps.DB)
+	app.Get("/projects/:id/issues/:number/discussion", auth.RequireAuth(cfg.JWTSecret), discussions.List())
+	app.Post("/projects/:id/issues/:number/discussion", auth.RequireAuth(cfg.JWTSecret), discussions.Post())
+	app.Put("/projects/:id/issues/:number/discussion/:commentId", auth.RequireAuth(cfg.JWTSecret), discussions.Edit())
+	app.Delete("/projects/:id/issues/:number/discussion/:commentId", auth.RequireAuth(cfg.JWTSecret), discussions.Delete())
+	app.Post("/projects/:id/issues/:number/discussion/:commentId/moderate", auth.RequireAuth(cfg.JWTSecret), discussions.Moderate())
+
+	notifications := handlers.NewNotificationsHandler(deps.DB)
+	app.Get("/notifications", auth.RequireAuth(cfg.JWTSecret), notifications.List())
+	app.Post("/notifications/:id/read", auth.RequireAuth(cfg.JWTSecret), notifications.MarkRead())
+
+	mentorship := handlers.NewMentorshipHandler(deps.DB)
+	app.Put("/projects/:id/issues/:number/mentored", auth.RequireAuth(cfg.JWTSecret), mentorship.SetIssueMentored())
+	app.Post("/mentorships", auth.RequireAuth(cfg.JWTSecret), mentorship.Request())
+	app.Get("/mentorships/mine", auth.RequireAuth(cfg.JWTSecret), mentorship.Mine())
+	app.Post("/mentorships/:id/accept", auth.RequireAuth(cfg.JWTSecret), mentorship.Transition("active"))
+	app.Post("/mentorships/:id/decline", auth.RequireAuth(cfg.JWTSecret), mentorship.Transition("declined"))
+	app.Post("/mentorships/:id/complete", auth.RequireAuth(cfg.JWTSecret), mentorship.Transition("completed"))
+	app.Get("/mentorships/:id/notes", auth.RequireAuth(cfg.JWTSecret), mentorship.Notes())
+	app.Post("/mentorships/:id/notes", auth.RequireAuth(cfg.JWTSecret), mentorship.AddNote())
 
 	admin := handlers.NewAdminHandler(cfg, deps.DB)
-	adminGroup := app.Group("/admin", auth.RequireAuth(cfg.JWTSecret))
+	adminGroup := app.Group("/admin", auth.RequireAuth(cfg.JWTSecret), sso.RequireForAdmins())
 	adminGroup.Post("/bootstrap", admin.BootstrapAdmin())
-	adminGroup.Get("/users", auth.RequireRole("admin"), admin.ListUsers())
-	adminGroup.Put("/users/:id/role", auth.RequireRole("admin"), admin.SetUserRole())
-
-	ecosystemsAdmin := handlers.NewEcosystemsAdminHandler(deps.DB)
-	adminGroup.Get("/ecosystems", auth.RequireRole("admin"), ecosystemsAdmin.List())
-	adminGroup.Get("/ecosystems/:id", auth.RequireRole("admin"), ecosystemsAdmin.GetByID())
-	adminGroup.Post("/ecosystems", auth.RequireRole("admin"), ecosystemsAdmin.Create())
-	adminGroup.Put("/ecosystems/:id", auth.RequireRole("admin"), ecosystemsAdmin.Update())
-	adminGroup.Delete("/ecosystems/:id", auth.RequireRole("admin"), ecosystemsAdmin.Delete())
+	adminGroup.Get("/sso", auth.RequireRole("admin"), sso.Config())
+	adminGroup.Put("/sso", auth.RequireRole("admin"), sso.UpdateConfig())
+	adminGroup.Delete("/sso", auth.RequireRole("admin"), sso.DeleteConfig())
+	// Admins hold every scope; staff get only the scopes granted to them (see auth.Scopes).
+	adminGroup.Get("/users", admin.RequireScope(auth.ScopeUsersRead), admin.ListUsers())
+	adminGroup.Put("/users/:id/role", admin.RequireScope(auth.ScopeUsersWrite), admin.SetUserRole())
+	adminGroup.Put("/users/:id/scopes", auth.RequireRole("admin"), admin.SetUserScopes())
+
+	ecosystemsAdmin := handlers.NewEcosystemsAdminHandler(cfg, deps.DB)
+	adminGroup.Get("/ecosystems", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.List())
+	// Bulk export/import (sync environments, seed deployments); must come before /ecosystems/:id.
+	adminGroup.Get("/ecosystems/export", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.Export())
+	adminGroup.Post("/ecosystems/import", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.Import())
+	adminGroup.Get("/ecosystems/:id", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.GetByID())
+	adminGroup.Post("/ecosystems", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.Create())
+	adminGroup.Patch("/ecosystems/:id", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.Update())
+	adminGroup.Put("/ecosystems/:id", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.Update())
+	adminGroup.Get("/github-apps", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.GitHubApps())
+	// Versioned page content: edit a draft, publish it, browse history and roll back.
+	adminGroup.Get("/ecosystems/:id/content", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.Content())
+	adminGroup.Put("/ecosystems/:id/content/draft", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.SaveDraft())
+	adminGroup.Delete("/ecosystems/:id/content/draft", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.DiscardDraft())
+	adminGroup.Post("/ecosystems/:id/content/publish", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.PublishDraft())
+	adminGroup.Get("/ecosystems/:id/content/versions", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.ContentHistory())
+	adminGroup.Get("/ecosystems/:id/content/versions/:version", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.ContentVersion())
+	adminGroup.Post("/ecosystems/:id/content/versions/:version/rollback", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.RollbackContent())
+	// Reward budget per currency and its ledger; issue rewards are locked against it while assigned.
+	adminGroup.Get("/ecosystems/:id/budget", admin.RequireScope(auth.ScopePayoutsRead), ecosystemsAdmin.Budget())
+	adminGroup.Post("/ecosystems/:id/budget", admin.RequireScope(auth.ScopePayoutsWrite), ecosystemsAdmin.FundBudget())
+	// Per-locale description/about/key areas, served to public requests by Accept-Language.
+	adminGroup.Get("/ecosystems/:id/translations", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemsAdmin.Translations())
+	adminGroup.Put("/ecosystems/:id/translations/:locale", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.PutTranslation())
+	adminGroup.Delete("/ecosystems/:id/translations/:locale", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.DeleteTranslation())
+
+	// Image uploads (internal/assets). Stored URLs point at /assets/<key>, which redirects to a signed URL.
+	assetsHandler := handlers.NewAssetsHandler(cfg, deps.DB)
+	adminGroup.Post("/assets", admin.RequireScope(auth.ScopeEcosystemsWrite), assetsHandler.Upload())
+	adminGroup.Post("/ecosystems/:id/logo", admin.RequireScope(auth.ScopeEcosystemsWrite), assetsHandler.EcosystemLogo())
+	app.Post("/projects/:id/logo", auth.RequireAuth(cfg.JWTSecret), assetsHandler.ProjectLogo())
+	app.Get("/assets/*", assetsHandler.Serve())
+
+	// Currency registry (fiat and tokens) and exchange-rate snapshots for reporting payouts in one unit.
+	currencies := handlers.NewCurrenciesHandler(cfg, deps.DB)
+	app.Get("/currencies", currencies.List())
+	adminGroup.Get("/currencies", admin.RequireScope(auth.ScopePayoutsRead), currencies.AdminList())
+	adminGroup.Post("/currencies", admin.RequireScope(auth.ScopePayoutsWrite), currencies.Create())
+	adminGroup.Get("/currencies/payouts-report", admin.RequireScope(auth.ScopePayoutsRead), currencies.PayoutsReport())
+	adminGroup.Patch("/currencies/:code", admin.RequireScope(auth.ScopePayoutsWrite), currencies.Update())
+	adminGroup.Get("/currencies/:code/rates", admin.RequireScope(auth.ScopePayoutsRead), currencies.Rates())
+	adminGroup.Post("/currencies/:code/rates", admin.RequireScope(auth.ScopePayoutsWrite), currencies.AddRate())
+
+	// Payouts owed to contributors; approved ones are executed when a payout provider is configured.
+	payoutsHandler := handlers.NewPayoutsHandler(cfg, deps.DB)
+	app.Get("/me/payouts", auth.RequireAuth(cfg.JWTSecret), payoutsHandler.Mine())
+	adminGroup.Get("/payouts", admin.RequireScope(auth.ScopePayoutsRead), payoutsHandler.List())
+	adminGroup.Post("/payouts", admin.RequireScope(auth.ScopePayoutsWrite), payoutsHandler.Create())
+	adminGroup.Post("/payouts/:id/approve", admin.RequireScope(auth.ScopePayoutsWrite), payoutsHandler.Approve())
+	adminGroup.Post("/payouts/:id/cancel", admin.RequireScope(auth.ScopePayoutsWrite), payoutsHandler.Cancel())
+	adminGroup.Post("/payouts/:id/retry", admin.RequireScope(auth.ScopePayoutsWrite), payoutsHandler.Retry())
+
+	// Tax information for ecosystems paying fiat; exporting it decrypted needs the tax:read scope.
+	taxInfo := handlers.NewTaxInfoHandler(cfg, deps.DB)
+	app.Get("/me/tax-info", auth.RequireAuth(cfg.JWTSecret), taxInfo.Mine())
+	app.Put("/me/tax-info", auth.RequireAuth(cfg.JWTSecret), taxInfo.Submit())
+	app.Delete("/me/tax-info", auth.RequireAuth(cfg.JWTSecret), taxInfo.Delete())
+	adminGroup.Get("/tax-info", admin.RequireScope(auth.ScopePayoutsRead), taxInfo.List())
+	adminGroup.Get("/tax-info/export", admin.RequireScope(auth.ScopeTaxRead), taxInfo.Export())
+	adminGroup.Post("/tax-info/:user_id/review", admin.RequireScope(auth.ScopePayoutsWrite), taxInfo.Review())
+
+	// Disputes: contributors' recourse against rejections and non-payment, worked by assigned staff.
+	disputes := handlers.NewDisputesHandler(cfg, deps.DB)
+	app.Get("/me/disputes", auth.RequireAuth(cfg.JWTSecret), disputes.Mine())
+	app.Post("/me/disputes", auth.RequireAuth(cfg.JWTSecret), disputes.Open())
+	app.Get("/me/disputes/:id", auth.RequireAuth(cfg.JWTSecret), disputes.Get())
+	app.Post("/me/disputes/:id/messages", auth.RequireAuth(cfg.JWTSecret), disputes.AddEvidence())
+	app.Post("/me/disputes/:id/withdraw", auth.RequireAuth(cfg.JWTSecret), disputes.Withdraw())
+	adminGroup.Get("/disputes", admin.RequireScope(auth.ScopeDisputesRead), disputes.List())
+	adminGroup.Get("/disputes/:id", admin.RequireScope(auth.ScopeDisputesRead), disputes.AdminGet())
+	adminGroup.Post("/disputes/:id/assign", admin.RequireScope(auth.ScopeDisputesWrite), disputes.Assign())
+	adminGroup.Post("/disputes/:id/messages", admin.RequireScope(auth.ScopeDisputesWrite), disputes.Reply())
+	adminGroup.Post("/disputes/:id/resolve", admin.RequireScope(auth.ScopeDisputesWrite), disputes.Resolve())
+
+	// Double-entry ledger behind points, budgets and rewards owed.
+	ledgerHandler := handlers.NewLedgerHandler(cfg, deps.DB)
+	app.Get("/me/balances", auth.RequireAuth(cfg.JWTSecret), ledgerHandler.MyBalances())
+	adminGroup.Get("/ledger/accounts", admin.RequireScope(auth.ScopePayoutsRead), ledgerHandler.Accounts())
+	adminGroup.Get("/ledger/transactions", admin.RequireScope(auth.ScopePayoutsRead), ledgerHandler.Transactions())
+	adminGroup.Get("/ledger/check", admin.RequireScope(auth.ScopePayoutsRead), ledgerHandler.Check())
+
+	eventArchives := handlers.NewEventArchivesHandler(cfg, deps.DB)
+	adminGroup.Get("/event-archives", auth.RequireRole("admin"), eventArchives.List())
+	adminGroup.Get("/event-archives/:id/events", auth.RequireRole("admin"), eventArchives.Events())
+
+	githubAPIUsage := handlers.NewGitHubAPIUsageHandler(deps.DB)
+	adminGroup.Get("/github-api-usage", auth.RequireRole("admin"), githubAPIUsage.Report())
+	adminGroup.Get("/github-dry-run", auth.RequireRole("admin"), dryRun.List())
+	dataQuality := handlers.NewDataQualityHandler(deps.DB)
+	adminGroup.Get("/data-quality", auth.RequireRole("admin"), dataQuality.Report())
+	adminGroup.Post("/data-quality/:kind/reconcile", auth.RequireRole("admin"), dataQuality.Reconcile())
+	queryStats := handlers.NewQueryStatsHandler(deps.DB)
+	adminGroup.Get("/query-stats", auth.RequireRole("admin"), queryStats.Report())
+	adminGroup.Post("/query-stats/reset", auth.RequireRole("admin"), queryStats.Reset())
+	adminGroup.Get("/tenants", auth.RequireRole("admin"), tenantsHandler.RequireDefaultTenant(), tenantsHandler.List())
+	adminGroup.Post("/tenants", auth.RequireRole("admin"), tenantsHandler.RequireDefaultTenant(), tenantsHandler.Create())
+	adminGroup.Put("/tenants/:id", auth.RequireRole("admin"), tenantsHandler.RequireDefaultTenant(), tenantsHandler.Update())
+	adminGroup.Delete("/ecosystems/:id", admin.RequireScope(auth.ScopeEcosystemsWrite), ecosystemsAdmin.Delete())
+	adminGroup.Get("/ecosystems/:id/bot-messages", admin.RequireScope(auth.ScopeEcosystemsRead), botMessages.Ecosystem())
+	adminGroup.Put("/ecosystems/:id/bot-messages", admin.RequireScope(auth.ScopeEcosystemsWrite), botMessages.UpdateEcosystem())
+	issueTemplates := handlers.NewIssueTemplatesHandler(deps.DB)
+	adminGroup.Get("/ecosystems/:id/issue-templates", admin.RequireScope(auth.ScopeEcosystemsRead), issueTemplates.Ecosystem())
+	adminGroup.Put("/ecosystems/:id/issue-templates/:key", admin.RequireScope(auth.ScopeEcosystemsWrite), issueTemplates.UpsertEcosystem())
+	adminGroup.Delete("/ecosystems/:id/issue-templates/:key", admin.RequireScope(auth.ScopeEcosystemsWrite), issueTemplates.DeleteEcosystem())
+	promotionChannels := handlers.NewPromotionChannelsHandler(cfg, deps.DB)
+	adminGroup.Get("/ecosystems/:id/promotion-channels", admin.RequireScope(auth.ScopeEcosystemsRead), promotionChannels.List())
+	adminGroup.Post("/ecosystems/:id/promotion-channels", admin.RequireScope(auth.ScopeEcosystemsWrite), promotionChannels.Create())
+	adminGroup.Patch("/ecosystems/:id/promotion-channels/:channelId", admin.RequireScope(auth.ScopeEcosystemsWrite), promotionChannels.Update())
+	adminGroup.Delete("/ecosystems/:id/promotion-channels/:channelId", admin.RequireScope(auth.ScopeEcosystemsWrite), promotionChannels.Delete())
+
+	// Weekly ecosystem reports (see internal/digests). The unsubscribe link in the email is signed.
+	ecosystemReports := handlers.NewEcosystemReportsHandler(cfg, deps.DB)
+	adminGroup.Get("/ecosystems/:id/report-subscribers", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemReports.Subscribers())
+	adminGroup.Put("/ecosystems/:id/report-subscription", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemReports.Subscribe())
+	adminGroup.Delete("/ecosystems/:id/report-subscription", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemReports.Unsubscribe())
+	adminGroup.Get("/ecosystems/:id/report-preview", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemReports.Preview())
+	adminGroup.Get("/ecosystems/:id/reports", admin.RequireScope(auth.ScopeEcosystemsRead), ecosystemReports.History())
+	app.Get("/reports/unsubscribe", ecosystemReports.UnsubscribeLink())
 
 	// Open Source Week (admin)
 	oswAdmin := handlers.NewOpenSourceWeekAdminHandler(deps.DB)
-	adminGroup.Get("/open-source-week/events", auth.RequireRole("admin"), oswAdmin.List())
-	adminGroup.Post("/open-source-week/events", auth.RequireRole("admin"), oswAdmin.Create())
-	adminGroup.Delete("/open-source-week/events/:id", auth.RequireRole("admin"), oswAdmin.Delete())
+	adminGroup.Get("/open-source-week/events", admin.RequireScope(auth.ScopeEcosystemsRead), oswAdmin.List())
+	adminGroup.Post("/open-source-week/events", admin.RequireScope(auth.ScopeEcosystemsWrite), oswAdmin.Create())
+	adminGroup.Delete("/open-source-week/events/:id", admin.RequireScope(auth.ScopeEcosystemsWrite), oswAdmin.Delete())
 
 	webhooks := handlers.NewGitHubWebhooksHandler(cfg, deps.DB, deps.Bus)
 	// Register webhook endpoint with explicit OPTIONS support for CORS
@@ -269,6 +646,8 @@ func New(cfg config.Config, deps Deps) *fiber.App {
 	diditWebhook := handlers.NewDiditWebhookHandler(cfg, deps.DB)
 	app.Get("/webhooks/didit", diditWebhook.Receive())
 	app.Post("/webhooks/didit", diditWebhook.Receive())
+	// Webhook of the KYC provider configured instead of Didit (see package kyc)
+	app.Post("/webhooks/kyc", kyc.Webhook())
 
 	// Add catch-all 404 handler to log unmatched routes (helps debug routing issues)
 	app.Use(func(c *fiber.Ctx) error {
@@ -0,0 +1,32 @@
+package apiusage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Remaining returns how many core API calls the identity has left before its rate limit resets, as far as
+// we know: from the Default recorder's unflushed calls, else from the last flushed hour whose window
+// hasn't reset. Both results are nil when nothing recent was observed.
+func Remaining(ctx context.Context, pool *pgxpool.Pool, kind, id string) (*int, *time.Time, error) {
+	if remaining, resetAt := Default.Remaining(kind, id); remaining != nil {
+		return remaining, resetAt, nil
+	}
+	var remaining *int
+	var resetAt *time.Time
+	err := pool.QueryRow(ctx, `
+SELECT min_remaining, reset_at
+FROM github_api_usage
+WHERE identity_kind = $1 AND identity_id = $2 AND min_remaining IS NOT NULL AND reset_at > now()
+ORDER BY bucket_start DESC
+LIMIT 1
+`, kind, id).Scan(&remaining, &resetAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, nil
+	}
+	return remaining, resetAt, err
+}
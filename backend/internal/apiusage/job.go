@@ -0,0 +1,80 @@
+package apiusage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RetentionDays is how long hourly usage rows are kept.
+const RetentionDays = 30
+
+// Job periodically flushes the Default recorder to github_api_usage.
+type Job struct {
+	pool *pgxpool.Pool
+	rec  *Recorder
+}
+
+func New(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool, rec: Default}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		select {
+		case <-ctx.Done():
+			// Best-effort final flush so a clean shutdown doesn't lose the last minute.
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_ = j.Flush(flushCtx)
+			cancel()
+			return ctx.Err()
+		case <-t.C:
+		}
+		if err := j.Flush(ctx); err != nil {
+			slog.Warn("github api usage flush failed", "error", err)
+		}
+		if time.Since(lastCleanup) > 24*time.Hour {
+			_, _ = j.pool.Exec(ctx, `DELETE FROM github_api_usage WHERE bucket_start < now() - make_interval(days => $1)`, RetentionDays)
+			lastCleanup = time.Now()
+		}
+	}
+}
+
+// Flush writes the accumulated counters, adding to rows other processes may have written for the same hour.
+func (j *Job) Flush(ctx context.Context) error {
+	list := j.rec.Drain()
+	if len(list) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, u := range list {
+		batch.Queue(`
+INSERT INTO github_api_usage (bucket_start, identity_kind, identity_id, calls, errors, rate_limited, min_remaining, rate_limit, reset_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (bucket_start, identity_kind, identity_id) DO UPDATE SET
+  calls = github_api_usage.calls + EXCLUDED.calls,
+  errors = github_api_usage.errors + EXCLUDED.errors,
+  rate_limited = github_api_usage.rate_limited + EXCLUDED.rate_limited,
+  min_remaining = LEAST(github_api_usage.min_remaining, EXCLUDED.min_remaining),
+  rate_limit = COALESCE(EXCLUDED.rate_limit, github_api_usage.rate_limit),
+  reset_at = COALESCE(EXCLUDED.reset_at, github_api_usage.reset_at),
+  updated_at = now()
+`, u.Bucket, u.Kind, u.ID, u.Calls, u.Errors, u.RateLimited, u.MinRemaining, u.Limit, u.ResetAt)
+	}
+	if err := j.pool.SendBatch(ctx, batch).Close(); err != nil {
+		j.rec.Restore(list)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// Package apiusage instruments outgoing GitHub API calls: per identity (App installation, user
+// token, App JWT) and hour it counts calls, errors and throttled responses and keeps the latest rate
+// limit headers. Counters are kept in memory and flushed to github_api_usage by the Job.
+package apiusage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Identity kinds.
+const (
+	KindInstallation = "installation"
+	KindUser         = "user"
+	KindApp          = "app"
+	KindToken        = "token" // a token we never labeled (e.g. issued before a restart)
+	KindAnonymous    = "anonymous"
+)
+
+// labelTTL is how long a token label is kept; installation tokens are valid for an hour.
+const labelTTL = 2 * time.Hour
+
+type label struct {
+	kind, id string
+	seen     time.Time
+}
+
+type key struct {
+	bucket   time.Time
+	kind, id string
+}
+
+// Counter is the usage of one identity within one hour.
+type Counter struct {
+	Calls       int
+	Errors      int
+	RateLimited int
+	// Latest core rate limit observed; Remaining is the lowest seen in the hour.
+	MinRemaining *int
+	Limit        *int
+	ResetAt      *time.Time
+}
+
+// Recorder accumulates usage until it is drained.
+type Recorder struct {
+	mu       sync.Mutex
+	labels   map[string]label
+	counters map[key]*Counter
+	now      func() time.Time
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{labels: map[string]label{}, counters: map[key]*Counter{}, now: time.Now}
+}
+
+// Default is the process-wide recorder used by the GitHub clients.
+var Default = NewRecorder()
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Label associates a token with the identity it acts as, so its calls are attributed correctly.
+// Only a hash of the token is kept.
+func (r *Recorder) Label(token, kind, id string) {
+	if strings.TrimSpace(token) == "" {
+		return
+	}
+	r.mu.Lock()
+	r.labels[tokenHash(token)] = label{kind: kind, id: id, seen: r.now()}
+	r.mu.Unlock()
+}
+
+// Label records a token label on the Default recorder.
+func Label(token, kind, id string) {
+	Default.Label(token, kind, id)
+}
+
+// identify resolves the identity behind an Authorization header value.
+func (r *Recorder) identify(authorization string) (string, string) {
+	token := strings.TrimSpace(authorization)
+	for _, prefix := range []string{"Bearer ", "bearer ", "token "} {
+		token = strings.TrimPrefix(token, prefix)
+	}
+	if token == "" {
+		return KindAnonymous, ""
+	}
+	h := tokenHash(token)
+	if l, ok := r.labels[h]; ok {
+		return l.kind, l.id
+	}
+	return KindToken, h
+}
+
+// Observation is what the transport learned from one call.
+type Observation struct {
+	Authorization string
+	Status        int // 0 when the request failed before a response
+	Resource      string
+	Remaining     *int
+	Limit         *int
+	ResetUnix     *int64
+}
+
+// Record adds one call to the current hour's counter of the calling identity.
+func (r *Recorder) Record(o Observation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kind, id := r.identify(o.Authorization)
+	k := key{bucket: r.now().UTC().Truncate(time.Hour), kind: kind, id: id}
+	c := r.counters[k]
+	if c == nil {
+		c = &Counter{}
+		r.counters[k] = c
+	}
+	c.Calls++
+	if o.Status == 0 || o.Status >= 400 {
+		c.Errors++
+	}
+	throttled := o.Status == 429 || (o.Status == 403 && o.Remaining != nil && *o.Remaining == 0)
+	if throttled {
+		c.RateLimited++
+	}
+	// Only the core limit is comparable across calls; search and GraphQL have their own budgets.
+	if o.Resource == "" || o.Resource == "core" {
+		if o.Remaining != nil && (c.MinRemaining == nil || *o.Remaining < *c.MinRemaining) {
+			v := *o.Remaining
+			c.MinRemaining = &v
+		}
+		if o.Limit != nil {
+			v := *o.Limit
+			c.Limit = &v
+		}
+		if o.ResetUnix != nil {
+			t := time.Unix(*o.ResetUnix, 0).UTC()
+			c.ResetAt = &t
+		}
+	}
+}
+
+// Usage is one drained counter.
+type Usage struct {
+	Bucket time.Time
+	Kind   string
+	ID     string
+	Counter
+}
+
+// Drain returns and clears the accumulated counters, and forgets expired token labels.
+func (r *Recorder) Drain() []Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]Usage, 0, len(r.counters))
+	for k, c := range r.counters {
+		out = append(out, Usage{Bucket: k.bucket, Kind: k.kind, ID: k.id, Counter: *c})
+	}
+	r.counters = map[key]*Counter{}
+
+	cutoff := r.now().Add(-labelTTL)
+	for h, l := range r.labels {
+		if l.seen.Before(cutoff) {
+			delete(r.labels, h)
+		}
+	}
+	return out
+}
+
+// Restore puts usage that could not be flushed back, so it is retried with the next flush.
+func (r *Recorder) Restore(list []Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range list {
+		k := key{bucket: u.Bucket, kind: u.Kind, id: u.ID}
+		c := r.counters[k]
+		if c == nil {
+			c = &Counter{}
+			r.counters[k] = c
+		}
+		c.Calls += u.Calls
+		c.Errors += u.Errors
+		c.RateLimited += u.RateLimited
+		if u.MinRemaining != nil && (c.MinRemaining == nil || *u.MinRemaining < *c.MinRemaining) {
+			c.MinRemaining = u.MinRemaining
+		}
+		if c.Limit == nil {
+			c.Limit = u.Limit
+		}
+		if c.ResetAt == nil {
+			c.ResetAt = u.ResetAt
+		}
+	}
+}
+
+func headerInt(v string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func headerInt64(v string) *int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// Remaining returns the lowest core rate limit remaining that the identity's unflushed calls saw, and
+// when that window resets. Both are nil when nothing was observed or the window has reset since.
+func (r *Recorder) Remaining(kind, id string) (*int, *time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	var remaining *int
+	var resetAt *time.Time
+	for k, c := range r.counters {
+		if k.kind != kind || k.id != id || c.MinRemaining == nil || c.ResetAt == nil || !c.ResetAt.After(now) {
+			continue
+		}
+		if remaining == nil || *c.MinRemaining < *remaining {
+			v, t := *c.MinRemaining, *c.ResetAt
+			remaining, resetAt = &v, &t
+		}
+	}
+	return remaining, resetAt
+}
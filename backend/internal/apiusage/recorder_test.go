@@ -0,0 +1,80 @@
+package apiusage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 30, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time { return now }
+
+	r.Label("inst-token", KindInstallation, "42")
+	remaining := func(n int) *int { return &n }
+
+	r.Record(Observation{Authorization: "Bearer inst-token", Status: 200, Remaining: remaining(4000), Limit: remaining(5000)})
+	r.Record(Observation{Authorization: "Bearer inst-token", Status: 403, Remaining: remaining(0)})
+	r.Record(Observation{Authorization: "token inst-token", Status: 200, Resource: "search", Remaining: remaining(3)})
+	r.Record(Observation{Authorization: "Bearer unknown", Status: 0})
+	r.Record(Observation{Status: 200})
+
+	got := map[string]Usage{}
+	for _, u := range r.Drain() {
+		got[u.Kind] = u
+	}
+	inst := got[KindInstallation]
+	if inst.ID != "42" || inst.Calls != 3 || inst.Errors != 1 || inst.RateLimited != 1 {
+		t.Errorf("installation usage = %+v", inst)
+	}
+	if inst.MinRemaining == nil || *inst.MinRemaining != 0 || inst.Limit == nil || *inst.Limit != 5000 {
+		t.Errorf("installation rate limit = %v / %v, want 0 / 5000 (search budget ignored)", inst.MinRemaining, inst.Limit)
+	}
+	if !inst.Bucket.Equal(time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("bucket = %v", inst.Bucket)
+	}
+	if tok := got[KindToken]; tok.Calls != 1 || tok.Errors != 1 || tok.ID == "unknown" {
+		t.Errorf("unlabeled token usage = %+v (the raw token must not be kept)", tok)
+	}
+	if got[KindAnonymous].Calls != 1 {
+		t.Errorf("anonymous usage = %+v", got[KindAnonymous])
+	}
+	if left := r.Drain(); len(left) != 0 {
+		t.Errorf("drain should clear counters, %d left", len(left))
+	}
+
+	// Labels expire, after which the token shows up as unlabeled.
+	now = now.Add(3 * time.Hour)
+	r.Drain()
+	r.Record(Observation{Authorization: "Bearer inst-token", Status: 200})
+	if u := r.Drain(); len(u) != 1 || u[0].Kind != KindToken {
+		t.Errorf("expired label usage = %+v", u)
+	}
+}
+
+func TestRecorderRemaining(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 30, 0, 0, time.UTC)
+	r := NewRecorder()
+	r.now = func() time.Time { return now }
+	r.Label("user-token", KindUser, "octocat")
+	n := func(v int) *int { return &v }
+	reset := now.Add(20 * time.Minute).Unix()
+
+	if got, _ := r.Remaining(KindUser, "octocat"); got != nil {
+		t.Fatalf("remaining before any call = %d, want nil", *got)
+	}
+	r.Record(Observation{Authorization: "Bearer user-token", Status: 200, Remaining: n(900), ResetUnix: &reset})
+	r.Record(Observation{Authorization: "Bearer user-token", Status: 200, Remaining: n(850), ResetUnix: &reset})
+	got, resetAt := r.Remaining(KindUser, "octocat")
+	if got == nil || *got != 850 || resetAt == nil || resetAt.Unix() != reset {
+		t.Errorf("remaining = %v at %v, want 850 at %d", got, resetAt, reset)
+	}
+	if got, _ := r.Remaining(KindUser, "someone-else"); got != nil {
+		t.Errorf("another identity's budget leaked: %d", *got)
+	}
+
+	now = now.Add(21 * time.Minute)
+	if got, _ := r.Remaining(KindUser, "octocat"); got != nil {
+		t.Errorf("remaining after the window reset = %d, want nil", *got)
+	}
+}
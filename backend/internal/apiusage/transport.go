@@ -0,0 +1,32 @@
+package apiusage
+
+import (
+	"net/http"
+)
+
+// Transport wraps base (http.DefaultTransport when nil) so every request is recorded on Default.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, rec: Default}
+}
+
+type transport struct {
+	base http.RoundTripper
+	rec  *Recorder
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	o := Observation{Authorization: req.Header.Get("Authorization")}
+	if err == nil {
+		o.Status = resp.StatusCode
+		o.Resource = resp.Header.Get("X-RateLimit-Resource")
+		o.Remaining = headerInt(resp.Header.Get("X-RateLimit-Remaining"))
+		o.Limit = headerInt(resp.Header.Get("X-RateLimit-Limit"))
+		o.ResetUnix = headerInt64(resp.Header.Get("X-RateLimit-Reset"))
+	}
+	t.rec.Record(o)
+	return resp, err
+}
@@ -0,0 +1,65 @@
+// Package appstage is the optional stage between applying for an issue and being assigned to it: the
+// maintainer can ask an applicant for a small trial task or a call before deciding. The application stays
+// pending throughout, so it can be assigned or rejected from any stage.
+package appstage
+
+// Stages.
+const (
+	Applied            = "applied"
+	TrialRequested     = "trial_requested"
+	TrialSubmitted     = "trial_submitted"
+	InterviewRequested = "interview_requested"
+	InterviewDone      = "interview_done"
+)
+
+// Who moves an application between stages.
+const (
+	Maintainer = "maintainer"
+	Applicant  = "applicant"
+)
+
+// transitions maps each stage to the stages it can move to, and who makes that move. A maintainer can
+// ask for a (revised) trial task or a call at any point, and takes the application back to applied to
+// drop the request; the applicant hands in the trial task; the maintainer records that the call happened.
+var transitions = map[string]map[string]string{
+	Applied: {
+		TrialRequested:     Maintainer,
+		InterviewRequested: Maintainer,
+	},
+	TrialRequested: {
+		TrialSubmitted:     Applicant,
+		InterviewRequested: Maintainer,
+		Applied:            Maintainer,
+	},
+	TrialSubmitted: {
+		TrialRequested:     Maintainer,
+		InterviewRequested: Maintainer,
+		Applied:            Maintainer,
+	},
+	InterviewRequested: {
+		InterviewDone:  Maintainer,
+		TrialRequested: Maintainer,
+		Applied:        Maintainer,
+	},
+	InterviewDone: {
+		TrialRequested:     Maintainer,
+		InterviewRequested: Maintainer,
+		Applied:            Maintainer,
+	},
+}
+
+// Valid reports whether s is a stage.
+func Valid(s string) bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Actor returns who may move an application from one stage to another, or "" if the move isn't allowed.
+func Actor(from, to string) string {
+	return transitions[from][to]
+}
+
+// Requested reports whether stage waits on the applicant (a trial task or a call the maintainer asked for).
+func Requested(stage string) bool {
+	return stage == TrialRequested || stage == InterviewRequested
+}
@@ -0,0 +1,36 @@
+package appstage
+
+import "testing"
+
+func TestActor(t *testing.T) {
+	for _, tc := range []struct {
+		from, to, want string
+	}{
+		{Applied, TrialRequested, Maintainer},
+		{Applied, InterviewRequested, Maintainer},
+		{TrialRequested, TrialSubmitted, Applicant},
+		{TrialSubmitted, TrialRequested, Maintainer},
+		{InterviewRequested, InterviewDone, Maintainer},
+		{InterviewDone, Applied, Maintainer},
+		{Applied, TrialSubmitted, ""},
+		{Applied, InterviewDone, ""},
+		{Applied, Applied, ""},
+		{InterviewRequested, TrialSubmitted, ""},
+		{"assigned", Applied, ""},
+	} {
+		if got := Actor(tc.from, tc.to); got != tc.want {
+			t.Errorf("Actor(%q, %q) = %q, want %q", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range []string{Applied, TrialRequested, TrialSubmitted, InterviewRequested, InterviewDone} {
+		if !Valid(s) {
+			t.Errorf("Valid(%q) = false", s)
+		}
+	}
+	if Valid("pending") {
+		t.Error(`Valid("pending") = true`)
+	}
+}
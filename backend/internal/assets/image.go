@@ -0,0 +1,113 @@
+package assets
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	// MaxUploadBytes caps an uploaded image before processing.
+	MaxUploadBytes = 3 << 20
+	// maxSourcePixels guards against decompression bombs (a tiny file declaring a huge canvas).
+	maxSourcePixels = 4096 * 4096
+)
+
+var (
+	ErrTooLarge    = errors.New("image too large")
+	ErrUnsupported = errors.New("unsupported image format")
+)
+
+// Image is a processed upload, ready to store.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Process validates an uploaded PNG, JPEG or GIF, scales it down to fit maxDim×maxDim (never up), and
+// re-encodes it: JPEGs stay JPEG, everything else becomes PNG (GIFs keep their first frame). Re-encoding
+// also drops metadata such as EXIF location. SVG and other formats are rejected.
+func Process(data []byte, maxDim int) (Image, error) {
+	if len(data) > MaxUploadBytes {
+		return Image{}, ErrTooLarge
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrUnsupported
+	}
+	if format != "png" && format != "jpeg" && format != "gif" {
+		return Image{}, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return Image{}, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, ErrUnsupported
+	}
+
+	img := Resize(src, maxDim)
+	var buf bytes.Buffer
+	out := Image{ContentType: "image/png", Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if format == "jpeg" {
+		out.ContentType = "image/jpeg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	out.Data = buf.Bytes()
+	return out, nil
+}
+
+// Resize scales src down to fit within maxDim×maxDim, keeping its aspect ratio, by averaging the source
+// pixels each target pixel covers. Images that already fit are returned as RGBA copies.
+func Resize(src image.Image, maxDim int) *image.RGBA {
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	sw, sh := b.Dx(), b.Dy()
+	if maxDim <= 0 || (sw <= maxDim && sh <= maxDim) {
+		return rgba
+	}
+	dw, dh := maxDim, maxDim
+	if sw > sh {
+		dh = max(1, sh*maxDim/sw)
+	} else {
+		dw = max(1, sw*maxDim/sh)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[i])
+					g += uint64(rgba.Pix[i+1])
+					bl += uint64(rgba.Pix[i+2])
+					a += uint64(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(bl / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
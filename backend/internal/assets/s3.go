@@ -0,0 +1,220 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jagadeesh/grainlify/backend/internal/secrets"
+)
+
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+// s3Store talks to S3 (virtual-hosted requests) or, with an endpoint, an S3-compatible service such as
+// GCS's XML API or MinIO (path-style requests). Both use AWS Signature Version 4.
+type s3Store struct {
+	bucket   string
+	prefix   string
+	region   string
+	endpoint *url.URL
+	keyID    string
+	secret   string
+
+	mu        sync.Mutex
+	creds     credentials
+	credsTill time.Time
+}
+
+type credentials struct {
+	keyID, secret, token string
+}
+
+func (s *s3Store) objectURL(key string) *url.URL {
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+	escaped := make([]string, 0)
+	for _, seg := range strings.Split(key, "/") {
+		escaped = append(escaped, uriEncode(seg))
+	}
+	path := "/" + strings.Join(escaped, "/")
+	if s.endpoint != nil {
+		u := *s.endpoint
+		u.RawPath = strings.TrimRight(u.EscapedPath(), "/") + "/" + uriEncode(s.bucket) + path
+		u.Path, _ = url.PathUnescape(u.RawPath)
+		return &u
+	}
+	host := s.bucket + ".s3." + s.region + ".amazonaws.com"
+	if s.region == "us-east-1" {
+		host = s.bucket + ".s3.amazonaws.com"
+	}
+	p, _ := url.PathUnescape(path)
+	return &url.URL{Scheme: "https", Host: host, Path: p, RawPath: path}
+}
+
+// credentials are the configured HMAC key, else the AWS credential chain (cached for a few minutes,
+// since signing a URL happens on every asset request).
+func (s *s3Store) credentials(ctx context.Context) (credentials, error) {
+	if s.keyID != "" {
+		return credentials{keyID: s.keyID, secret: s.secret}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Now().Before(s.credsTill) {
+		return s.creds, nil
+	}
+	id, secret, token, err := secrets.AWSCredentials(ctx)
+	if err != nil {
+		return credentials{}, err
+	}
+	s.creds = credentials{keyID: id, secret: secret, token: token}
+	s.credsTill = time.Now().Add(5 * time.Minute)
+	return s.creds, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	sum := sha256.Sum256(data)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	signRequest(req, u, creds, s.region, time.Now().UTC())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("asset PUT %s: status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *s3Store) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return presign(http.MethodGet, s.objectURL(key), creds, s.region, time.Now().UTC(), ttl), nil
+}
+
+// signRequest adds a SigV4 Authorization header covering host and every header already set.
+func signRequest(req *http.Request, u *url.URL, creds credentials, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.token != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.token)
+	}
+	headers := map[string]string{"host": u.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	canonHeaders, signed := canonicalHeaders(headers)
+	canonical := strings.Join([]string{req.Method, u.EscapedPath(), "", canonHeaders, signed,
+		req.Header.Get("X-Amz-Content-Sha256")}, "\n")
+	scope, sig := signature(canonical, creds.secret, region, now)
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.keyID+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+sig)
+}
+
+// presign returns u with SigV4 query authentication valid for ttl (at most seven days).
+func presign(method string, u *url.URL, creds credentials, region string, now time.Time, ttl time.Duration) string {
+	ttl = min(ttl, 7*24*time.Hour)
+	scope := now.Format("20060102") + "/" + region + "/s3/aws4_request"
+	q := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    creds.keyID + "/" + scope,
+		"X-Amz-Date":          now.Format("20060102T150405Z"),
+		"X-Amz-Expires":       strconv.Itoa(int(ttl.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if creds.token != "" {
+		q["X-Amz-Security-Token"] = creds.token
+	}
+	query := canonicalQuery(q)
+	canonHeaders, signed := canonicalHeaders(map[string]string{"host": u.Host})
+	canonical := strings.Join([]string{method, u.EscapedPath(), query, canonHeaders, signed, "UNSIGNED-PAYLOAD"}, "\n")
+	_, sig := signature(canonical, creds.secret, region, now)
+	out := *u
+	out.RawQuery = query + "&X-Amz-Signature=" + sig
+	return out.String()
+}
+
+func canonicalHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k + ":" + headers[k] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+func canonicalQuery(q map[string]string) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, uriEncode(k)+"="+uriEncode(q[k]))
+	}
+	return strings.Join(parts, "&")
+}
+
+func signature(canonical, secret, region string, now time.Time) (scope, sig string) {
+	date := now.Format("20060102")
+	scope = date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return scope, hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// uriEncode is SigV4's URI encoding: everything but unreserved characters is percent-encoded.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,187 @@
+// Package assets stores uploaded images (ecosystem and project logos) and hands out short-lived signed
+// URLs for them. Backends are a local directory, S3 (or any S3-compatible service) and Google Cloud
+// Storage through its S3-compatible XML API; see NewStore.
+//
+// Stored URLs point at the API (/assets/<key>), which redirects to a freshly signed URL, so buckets stay
+// private and a logo_url never expires.
+package assets
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Open for keys that were never stored.
+var ErrNotFound = errors.New("asset not found")
+
+// Store holds asset objects by key.
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// SignedURL returns a URL the object can be fetched from until ttl passes.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Options configure NewStore. PublicBaseURL and SigningKey are used by the local backend, whose signed
+// URLs are served by the API itself; KeyID and Secret are the S3/GCS HMAC credentials.
+type Options struct {
+	PublicBaseURL string
+	SigningKey    []byte
+	KeyID         string
+	Secret        string
+}
+
+// NewStore builds a Store from a storage URL:
+//
+//	file:///var/lib/grainlify/assets             local (or mounted) directory
+//	s3://bucket/prefix?region=eu-west-1           AWS S3; credentials from KeyID/Secret or the AWS chain
+//	s3://bucket?endpoint=https://minio:9000       S3-compatible service (path-style requests)
+//	gs://bucket/prefix                            Google Cloud Storage with HMAC keys in KeyID/Secret
+//
+// An empty URL means asset uploads are disabled.
+func NewStore(rawURL string, opts Options) (Store, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset storage url: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("asset storage url %q has no path", rawURL)
+		}
+		if len(opts.SigningKey) == 0 {
+			return nil, fmt.Errorf("local asset storage needs a signing key")
+		}
+		return &LocalStore{root: u.Path, base: strings.TrimRight(opts.PublicBaseURL, "/"), key: opts.SigningKey}, nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("asset storage url %q has no bucket", rawURL)
+		}
+		q := u.Query()
+		region := q.Get("region")
+		if region == "" {
+			region = "us-east-1"
+		}
+		s := &s3Store{bucket: u.Host, prefix: prefix, region: region, keyID: opts.KeyID, secret: opts.Secret}
+		if ep := q.Get("endpoint"); ep != "" {
+			e, err := url.Parse(ep)
+			if err != nil || (e.Scheme != "http" && e.Scheme != "https") || e.Host == "" {
+				return nil, fmt.Errorf("invalid s3 endpoint %q", ep)
+			}
+			s.endpoint = e
+		}
+		return s, nil
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("asset storage url %q has no bucket", rawURL)
+		}
+		if opts.KeyID == "" || opts.Secret == "" {
+			return nil, fmt.Errorf("gs:// asset storage needs an HMAC key (ASSET_STORAGE_KEY_ID / ASSET_STORAGE_SECRET)")
+		}
+		endpoint, _ := url.Parse("https://storage.googleapis.com")
+		return &s3Store{bucket: u.Host, prefix: prefix, region: "auto", endpoint: endpoint, keyID: opts.KeyID, secret: opts.Secret}, nil
+	default:
+		return nil, fmt.Errorf("unsupported asset storage url scheme %q", u.Scheme)
+	}
+}
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*\.(png|jpg)$`)
+
+// ValidKey reports whether key looks like one NewKey produces (and is safe to use as a path).
+func ValidKey(key string) bool {
+	return len(key) <= 200 && keyPattern.MatchString(key)
+}
+
+// NewKey is the content-addressed key for processed image data under kind (e.g. "ecosystem-logos").
+func NewKey(kind string, data []byte, contentType string) string {
+	sum := sha256.Sum256(data)
+	ext := "png"
+	if contentType == "image/jpeg" {
+		ext = "jpg"
+	}
+	return kind + "/" + hex.EncodeToString(sum[:16]) + "." + ext
+}
+
+// ContentType is the content type of a stored key, from its extension.
+func ContentType(key string) string {
+	if strings.HasSuffix(key, ".jpg") {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// LocalStore keeps assets in a directory. Its signed URLs point at the API's /assets route, which
+// checks them with Verify and streams the file from Open.
+type LocalStore struct {
+	root string
+	base string
+	key  []byte
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s *LocalStore) Put(_ context.Context, key, _ string, data []byte) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("invalid asset key %q", key)
+	}
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// Open returns the stored object; callers must check the key with ValidKey first.
+func (s *LocalStore) Open(key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return s.signedURL(key, time.Now().Add(ttl)), nil
+}
+
+func (s *LocalStore) signedURL(key string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return s.base + "/assets/" + key + "?expires=" + exp + "&sig=" + s.sign(key, exp)
+}
+
+// Verify checks the expires/sig query parameters of a signed URL for key.
+func (s *LocalStore) Verify(key, expires, sig string, now time.Time) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.sign(key, expires)))
+}
+
+func (s *LocalStore) sign(key, expires string) string {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(m.Sum(nil))
+}
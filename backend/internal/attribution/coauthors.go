@@ -0,0 +1,147 @@
+// Package attribution works out who contributed to a pull request from its commits,
+// including co-authors credited with Co-authored-by trailers, and how credit is split.
+package attribution
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	RoleAuthor   = "author"
+	RoleCoAuthor = "co_author"
+)
+
+// Commit is the attribution-relevant part of a PR commit.
+type Commit struct {
+	AuthorLogin string // GitHub account of the commit author, if GitHub could match one
+	AuthorName  string
+	AuthorEmail string
+	Message     string
+}
+
+// Contributor is one person credited on a PR.
+type Contributor struct {
+	Login   string
+	Name    string
+	Email   string
+	Role    string
+	Commits int
+}
+
+// Identity is the stable key for a contributor: the lower-cased login, or the email when there is no login.
+func (c Contributor) Identity() string {
+	if c.Login != "" {
+		return strings.ToLower(c.Login)
+	}
+	return strings.ToLower(c.Email)
+}
+
+var trailerPattern = regexp.MustCompile(`(?im)^co-authored-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// noreplyPattern matches GitHub noreply addresses: "123+login@users.noreply.github.com" or "login@users.noreply.github.com".
+var noreplyPattern = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z0-9](?:[a-z0-9-]*[a-z0-9])?)@users\.noreply\.github\.com$`)
+
+// CoAuthor is a name/email pair from a Co-authored-by trailer.
+type CoAuthor struct {
+	Name  string
+	Email string
+}
+
+// ParseCoAuthors returns the Co-authored-by trailers of a commit message.
+func ParseCoAuthors(message string) []CoAuthor {
+	var out []CoAuthor
+	for _, m := range trailerPattern.FindAllStringSubmatch(message, -1) {
+		email := strings.TrimSpace(m[2])
+		if email == "" {
+			continue
+		}
+		out = append(out, CoAuthor{Name: strings.TrimSpace(m[1]), Email: email})
+	}
+	return out
+}
+
+// LoginFromEmail extracts the GitHub login from a noreply address, or returns "".
+func LoginFromEmail(email string) string {
+	m := noreplyPattern.FindStringSubmatch(strings.TrimSpace(email))
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// Contributors lists everyone credited on a PR: the PR author first, then commit authors and
+// trailer co-authors ordered by commit count. Bot accounts are skipped.
+func Contributors(prAuthor string, commits []Commit) []Contributor {
+	byID := map[string]*Contributor{}
+	var order []string
+	add := func(c Contributor) *Contributor {
+		if c.Login == "" {
+			c.Login = LoginFromEmail(c.Email)
+		}
+		if strings.HasSuffix(strings.ToLower(c.Login), "[bot]") {
+			return nil
+		}
+		id := c.Identity()
+		if id == "" {
+			return nil
+		}
+		if existing, ok := byID[id]; ok {
+			return existing
+		}
+		if strings.EqualFold(c.Login, prAuthor) {
+			c.Role = RoleAuthor
+		} else {
+			c.Role = RoleCoAuthor
+		}
+		byID[id] = &c
+		order = append(order, id)
+		return byID[id]
+	}
+
+	if prAuthor != "" {
+		add(Contributor{Login: prAuthor})
+	}
+	for _, cm := range commits {
+		seen := map[string]bool{}
+		if c := add(Contributor{Login: cm.AuthorLogin, Name: cm.AuthorName, Email: cm.AuthorEmail}); c != nil {
+			c.Commits++
+			seen[c.Identity()] = true
+		}
+		for _, ca := range ParseCoAuthors(cm.Message) {
+			if c := add(Contributor{Name: ca.Name, Email: ca.Email}); c != nil && !seen[c.Identity()] {
+				c.Commits++
+				seen[c.Identity()] = true
+			}
+		}
+	}
+
+	out := make([]Contributor, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byID[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Role == RoleAuthor) != (out[j].Role == RoleAuthor) {
+			return out[i].Role == RoleAuthor
+		}
+		return out[i].Commits > out[j].Commits
+	})
+	return out
+}
+
+// SplitPoints divides points evenly between n contributors; the remainder goes to the first ones
+// (the PR author comes first), so the parts always add up to points.
+func SplitPoints(points, n int) []int {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]int, n)
+	for i := range out {
+		out[i] = points / n
+		if i < points%n {
+			out[i]++
+		}
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package attribution
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	msg := "Add parser\n\nCo-authored-by: Jane Doe <jane@example.com>\nco-authored-by: bob <123+bob@users.noreply.github.com>\n"
+	got := ParseCoAuthors(msg)
+	want := []CoAuthor{{Name: "Jane Doe", Email: "jane@example.com"}, {Name: "bob", Email: "123+bob@users.noreply.github.com"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseCoAuthors = %#v, want %#v", got, want)
+	}
+}
+
+func TestLoginFromEmail(t *testing.T) {
+	cases := map[string]string{
+		"123+bob@users.noreply.github.com": "bob",
+		"alice@users.noreply.github.com":   "alice",
+		"alice@example.com":                "",
+	}
+	for in, want := range cases {
+		if got := LoginFromEmail(in); got != want {
+			t.Errorf("LoginFromEmail(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestContributors(t *testing.T) {
+	commits := []Commit{
+		{AuthorLogin: "alice", Message: "one\n\nCo-authored-by: Bob <1+bob@users.noreply.github.com>"},
+		{AuthorLogin: "Alice", Message: "two"},
+		{AuthorLogin: "bob", Message: "three\n\nCo-authored-by: Carol <carol@example.com>"},
+		{AuthorLogin: "dependabot[bot]", Message: "bump"},
+	}
+	got := Contributors("alice", commits)
+	if len(got) != 3 {
+		t.Fatalf("got %d contributors, want 3: %#v", len(got), got)
+	}
+	if got[0].Login != "alice" || got[0].Role != RoleAuthor || got[0].Commits != 2 {
+		t.Errorf("author = %#v", got[0])
+	}
+	if got[1].Login != "bob" || got[1].Role != RoleCoAuthor || got[1].Commits != 2 {
+		t.Errorf("bob = %#v", got[1])
+	}
+	if got[2].Email != "carol@example.com" || got[2].Login != "" || got[2].Commits != 1 {
+		t.Errorf("carol = %#v", got[2])
+	}
+}
+
+func TestSplitPoints(t *testing.T) {
+	if got := SplitPoints(100, 3); !reflect.DeepEqual(got, []int{34, 33, 33}) {
+		t.Errorf("SplitPoints(100, 3) = %v", got)
+	}
+	if got := SplitPoints(5, 0); got != nil {
+		t.Errorf("SplitPoints(5, 0) = %v", got)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInvalidCalendarToken = errors.New("invalid_calendar_token")
+
+// RotateCalendarToken gives userID a new calendar feed token, replacing (and so revoking) any previous one.
+func RotateCalendarToken(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (string, error) {
+	if pool == nil {
+		return "", fmt.Errorf("db not configured")
+	}
+	token := randomNonce(32)
+	ct, err := pool.Exec(ctx, `UPDATE users SET calendar_token_hash = $2, updated_at = now() WHERE id = $1`, userID, hashToken(token))
+	if err != nil {
+		return "", err
+	}
+	if ct.RowsAffected() == 0 {
+		return "", pgx.ErrNoRows
+	}
+	return token, nil
+}
+
+// RevokeCalendarToken turns off userID's calendar feed.
+func RevokeCalendarToken(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) error {
+	if pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	_, err := pool.Exec(ctx, `UPDATE users SET calendar_token_hash = NULL, updated_at = now() WHERE id = $1`, userID)
+	return err
+}
+
+// CalendarTokenUser returns the user a calendar feed token belongs to.
+func CalendarTokenUser(ctx context.Context, pool *pgxpool.Pool, token string) (uuid.UUID, error) {
+	if pool == nil {
+		return uuid.Nil, fmt.Errorf("db not configured")
+	}
+	var userID uuid.UUID
+	err := pool.QueryRow(ctx, `SELECT id FROM users WHERE calendar_token_hash = $1`, hashToken(token)).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrInvalidCalendarToken
+	}
+	return userID, err
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Google OpenID Connect endpoints.
+var (
+	googleAuthorizeEndpoint = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenEndpoint     = "https://oauth2.googleapis.com/token"
+	googleUserInfoEndpoint  = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+var googleHTTP = &http.Client{Timeout: 10 * time.Second}
+
+// GoogleUser is the signed-in Google account.
+type GoogleUser struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// GoogleAuthorizeURL is where the browser is sent to sign in with Google.
+func GoogleAuthorizeURL(clientID, redirectURL, state string) (string, error) {
+	if clientID == "" || redirectURL == "" {
+		return "", fmt.Errorf("google oauth not configured")
+	}
+	u, _ := url.Parse(googleAuthorizeEndpoint)
+	q := u.Query()
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURL)
+	q.Set("response_type", "code")
+	q.Set("scope", "openid email profile")
+	q.Set("state", state)
+	q.Set("prompt", "select_account")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// GoogleUserFromCode exchanges an authorization code and returns the account it belongs to.
+func GoogleUserFromCode(ctx context.Context, clientID, clientSecret, redirectURL, code string) (GoogleUser, error) {
+	form := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"redirect_uri":  {redirectURL},
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleTokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return GoogleUser{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := googleJSON(req, &tok); err != nil {
+		return GoogleUser{}, fmt.Errorf("google token exchange: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return GoogleUser{}, fmt.Errorf("google token exchange: %s", tok.Error)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, googleUserInfoEndpoint, nil)
+	if err != nil {
+		return GoogleUser{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	var u GoogleUser
+	if err := googleJSON(req, &u); err != nil {
+		return GoogleUser{}, fmt.Errorf("google userinfo: %w", err)
+	}
+	if u.Subject == "" {
+		return GoogleUser{}, fmt.Errorf("google userinfo: missing subject")
+	}
+	return u, nil
+}
+
+func googleJSON(req *http.Request, out any) error {
+	resp, err := googleHTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Sign-in providers besides GitHub and wallets.
+const (
+	ProviderGoogle = "google"
+	ProviderEmail  = "email"
+	ProviderOIDC   = "oidc"
+)
+
+// MagicLinkTTL is how long an emailed sign-in link works.
+const MagicLinkTTL = 15 * time.Minute
+
+// maxMagicLinksPerHour limits links sent to one address.
+const maxMagicLinksPerHour = 5
+
+var (
+	ErrInvalidEmail         = errors.New("invalid_email")
+	ErrTooManyMagicLinks    = errors.New("too_many_magic_links")
+	ErrInvalidOrExpiredLink = errors.New("invalid_or_expired_link")
+)
+
+// NormalizeEmail returns the bare, lowercased address.
+func NormalizeEmail(s string) (string, error) {
+	a, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil || !strings.Contains(a.Address, "@") || len(a.Address) > 254 {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(a.Address), nil
+}
+
+// UpsertIdentityUser signs in the user behind a provider identity, creating the user on first sign-in. The
+// email must be verified by the provider: a new Google or email identity whose email another Google or
+// email identity already uses joins that user rather than creating a second account. OIDC identities never
+// join by email, nor are joined: a tenant's IdP vouches for addresses it doesn't own, so an SSO sign-in
+// with someone else's address must not reach their account.
+func UpsertIdentityUser(ctx context.Context, pool *pgxpool.Pool, provider, subject, email string) (User, error) {
+	if pool == nil {
+		return User{}, fmt.Errorf("db not configured")
+	}
+	var u User
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+UPDATE user_identities ui SET email = $3, last_login_at = now()
+FROM users u
+WHERE u.id = ui.user_id AND ui.provider = $1 AND ui.subject = $2
+RETURNING u.id, u.role
+`, provider, subject, email).Scan(&u.ID, &u.Role)
+		if err == nil || !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		err = pgx.ErrNoRows
+		if provider != ProviderOIDC {
+			err = tx.QueryRow(ctx, `
+SELECT u.id, u.role
+FROM user_identities ui
+JOIN users u ON u.id = ui.user_id
+WHERE LOWER(ui.email) = LOWER($1) AND ui.provider <> $2
+ORDER BY ui.created_at
+LIMIT 1
+`, email, ProviderOIDC).Scan(&u.ID, &u.Role)
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = tx.QueryRow(ctx, `INSERT INTO users DEFAULT VALUES RETURNING id, role`).Scan(&u.ID, &u.Role)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+INSERT INTO user_identities (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)
+`, u.ID, provider, subject, email)
+		return err
+	})
+	return u, err
+}
+
+// CreateMagicLink stores a single-use sign-in token for email and returns it.
+func CreateMagicLink(ctx context.Context, pool *pgxpool.Pool, email, redirectURI string) (string, error) {
+	if pool == nil {
+		return "", fmt.Errorf("db not configured")
+	}
+	var recent int
+	if err := pool.QueryRow(ctx, `
+SELECT COUNT(*) FROM magic_links WHERE LOWER(email) = LOWER($1) AND created_at > now() - interval '1 hour'
+`, email).Scan(&recent); err != nil {
+		return "", err
+	}
+	if recent >= maxMagicLinksPerHour {
+		return "", ErrTooManyMagicLinks
+	}
+	token := randomNonce(32)
+	_, err := pool.Exec(ctx, `
+INSERT INTO magic_links (token_hash, email, redirect_uri, expires_at) VALUES ($1, $2, $3, $4)
+`, hashToken(token), email, nullIfEmpty(redirectURI), time.Now().UTC().Add(MagicLinkTTL))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeMagicLink marks a token used and returns the email and redirect it was issued for.
+func ConsumeMagicLink(ctx context.Context, pool *pgxpool.Pool, token string) (email, redirectURI string, err error) {
+	if pool == nil {
+		return "", "", fmt.Errorf("db not configured")
+	}
+	var redirect *string
+	err = pool.QueryRow(ctx, `
+UPDATE magic_links SET used_at = now()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING email, redirect_uri
+`, hashToken(token)).Scan(&email, &redirect)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrInvalidOrExpiredLink
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if redirect != nil {
+		redirectURI = *redirect
+	}
+	return email, redirectURI, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
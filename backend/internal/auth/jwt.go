@@ -13,6 +13,8 @@ type Claims struct {
 	Role       string `json:"role"`
 	WalletType string `json:"wallet_type,omitempty"`
 	Address    string `json:"address,omitempty"`
+	// SSOTenant is the tenant whose single sign-on issued the session, if any.
+	SSOTenant string `json:"sso_tenant,omitempty"`
 }
 
 func IssueJWT(secret string, userID uuid.UUID, role string, walletType WalletType, address string, ttl time.Duration) (string, error) {
@@ -39,6 +41,24 @@ func IssueJWT(secret string, userID uuid.UUID, role string, walletType WalletTyp
 	return t.SignedString([]byte(secret))
 }
 
+// IssueSSOJWT issues a session signed in through a tenant's single sign-on.
+func IssueSSOJWT(secret string, userID uuid.UUID, role string, tenantID uuid.UUID, ttl time.Duration) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET is required")
+	}
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role:      role,
+		SSOTenant: tenantID.String(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
 func ParseJWT(secret string, tokenString string) (*Claims, error) {
 	if secret == "" {
 		return nil, fmt.Errorf("JWT_SECRET is required")
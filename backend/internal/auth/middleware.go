@@ -10,6 +10,8 @@ import (
 const (
 	LocalUserID = "user_id"
 	LocalRole   = "role"
+	// LocalSSOTenant holds the tenant id when the session came from that tenant's single sign-on.
+	LocalSSOTenant = "sso_tenant"
 )
 
 func RequireAuth(jwtSecret string) fiber.Handler {
@@ -54,6 +56,7 @@ func RequireAuth(jwtSecret string) fiber.Handler {
 
 		c.Locals(LocalUserID, claims.Subject)
 		c.Locals(LocalRole, claims.Role)
+		c.Locals(LocalSSOTenant, claims.SSOTenant)
 		return c.Next()
 	}
 }
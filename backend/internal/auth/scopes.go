@@ -0,0 +1,55 @@
+package auth
+
+import "strings"
+
+// Admin scopes grant part of the admin surface to users who aren't admins. A write scope includes the
+// matching read scope; the admin role holds every scope.
+const (
+	ScopeEcosystemsRead  = "ecosystems:read"
+	ScopeEcosystemsWrite = "ecosystems:write"
+	ScopeUsersRead       = "users:read"
+	ScopeUsersWrite      = "users:write"
+	ScopePayoutsRead     = "payouts:read"
+	ScopePayoutsWrite    = "payouts:write"
+	// ScopeTaxRead allows exporting contributors' decrypted tax information. Reviewing tax status only
+	// needs the payouts scopes.
+	ScopeTaxRead = "tax:read"
+	// Holders of disputes:write are also who new disputes get assigned to.
+	ScopeDisputesRead  = "disputes:read"
+	ScopeDisputesWrite = "disputes:write"
+)
+
+// Scopes lists every admin scope.
+var Scopes = []string{
+	ScopeEcosystemsRead, ScopeEcosystemsWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopePayoutsRead, ScopePayoutsWrite,
+	ScopeTaxRead,
+	ScopeDisputesRead, ScopeDisputesWrite,
+}
+
+// ValidScope reports whether s is a known admin scope.
+func ValidScope(s string) bool {
+	for _, v := range Scopes {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether a user with role and granted scopes may use want.
+func HasScope(role string, granted []string, want string) bool {
+	if role == "admin" {
+		return true
+	}
+	for _, g := range granted {
+		if g == want {
+			return true
+		}
+		if area, ok := strings.CutSuffix(want, ":read"); ok && g == area+":write" {
+			return true
+		}
+	}
+	return false
+}
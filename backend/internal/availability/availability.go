@@ -0,0 +1,207 @@
+// Package availability models when a contributor can work: a time zone, weekly windows of availability,
+// and dated periods away. Maintainers see it next to applications, and assignment deadlines given in days
+// count only the days the assignee is available.
+package availability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Day keys, in time.Weekday order.
+var Days = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+const (
+	maxWindowsPerDay = 4
+	maxPeriods       = 20
+	dateLayout       = "2006-01-02"
+	clockLayout      = "15:04"
+	// maxScanDays bounds the deadline search when almost nothing is available.
+	maxScanDays = 730
+)
+
+// Window is a daily time range in the schedule's time zone, "HH:MM" to "HH:MM".
+type Window struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Period is a run of days away, inclusive, as "YYYY-MM-DD" dates in the schedule's time zone.
+type Period struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type Schedule struct {
+	TimeZone    string              `json:"timezone"`
+	Weekly      map[string][]Window `json:"weekly"`
+	Unavailable []Period            `json:"unavailable"`
+}
+
+// Default is assumed for contributors who never set availability: weekdays, in UTC.
+func Default() Schedule {
+	weekly := map[string][]Window{}
+	for _, d := range []string{"mon", "tue", "wed", "thu", "fri"} {
+		weekly[d] = []Window{{Start: "09:00", End: "17:00"}}
+	}
+	return Schedule{TimeZone: "UTC", Weekly: weekly, Unavailable: []Period{}}
+}
+
+// Validate checks s and normalises it in place: a known IANA time zone, sorted non-overlapping windows,
+// and periods that end on or after they start. Past periods are dropped.
+func (s *Schedule) Validate(now time.Time) error {
+	s.TimeZone = strings.TrimSpace(s.TimeZone)
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return fmt.Errorf("unknown time zone %q", s.TimeZone)
+	}
+	weekly := map[string][]Window{}
+	for day, windows := range s.Weekly {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if dayIndex(day) < 0 {
+			return fmt.Errorf("unknown day %q", day)
+		}
+		if len(windows) > maxWindowsPerDay {
+			return fmt.Errorf("%s: at most %d windows", day, maxWindowsPerDay)
+		}
+		for i := range windows {
+			w := &windows[i]
+			w.Start, w.End = strings.TrimSpace(w.Start), strings.TrimSpace(w.End)
+			start, err1 := time.Parse(clockLayout, w.Start)
+			end, err2 := time.Parse(clockLayout, w.End)
+			if err1 != nil || err2 != nil || (!end.After(start) && w.End != "00:00") {
+				return fmt.Errorf("%s: invalid window %s-%s", day, w.Start, w.End)
+			}
+		}
+		sort.Slice(windows, func(i, j int) bool { return windows[i].Start < windows[j].Start })
+		for i := 1; i < len(windows); i++ {
+			if windows[i].Start < windows[i-1].End || windows[i-1].End == "00:00" {
+				return fmt.Errorf("%s: overlapping windows", day)
+			}
+		}
+		if len(windows) > 0 {
+			weekly[day] = windows
+		}
+	}
+	s.Weekly = weekly
+
+	today := now.In(loc).Format(dateLayout)
+	periods := []Period{}
+	for _, p := range s.Unavailable {
+		p.From, p.To, p.Reason = strings.TrimSpace(p.From), strings.TrimSpace(p.To), strings.TrimSpace(p.Reason)
+		if p.To == "" {
+			p.To = p.From
+		}
+		if _, err := time.Parse(dateLayout, p.From); err != nil {
+			return fmt.Errorf("invalid date %q", p.From)
+		}
+		if _, err := time.Parse(dateLayout, p.To); err != nil || p.To < p.From {
+			return fmt.Errorf("invalid period %s to %s", p.From, p.To)
+		}
+		if len(p.Reason) > 200 {
+			return fmt.Errorf("reason too long")
+		}
+		if p.To >= today {
+			periods = append(periods, p)
+		}
+	}
+	if len(periods) > maxPeriods {
+		return fmt.Errorf("at most %d periods", maxPeriods)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].From < periods[j].From })
+	s.Unavailable = periods
+	return nil
+}
+
+func dayIndex(day string) int {
+	for i, d := range Days {
+		if d == day {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s Schedule) location() *time.Location {
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// HoursPerWeek sums the weekly windows.
+func (s Schedule) HoursPerWeek() float64 {
+	var total time.Duration
+	for _, windows := range s.Weekly {
+		for _, w := range windows {
+			start, _ := time.Parse(clockLayout, w.Start)
+			end, _ := time.Parse(clockLayout, w.End)
+			if w.End == "00:00" {
+				end = end.Add(24 * time.Hour)
+			}
+			total += end.Sub(start)
+		}
+	}
+	return total.Hours()
+}
+
+// AvailableOn reports whether the contributor works on the calendar day of t (in their time zone).
+func (s Schedule) AvailableOn(t time.Time) bool {
+	local := t.In(s.location())
+	if len(s.Weekly[Days[local.Weekday()]]) == 0 {
+		return false
+	}
+	return s.awayOn(local.Format(dateLayout)) == nil
+}
+
+func (s Schedule) awayOn(date string) *Period {
+	for i, p := range s.Unavailable {
+		if date >= p.From && date <= p.To {
+			return &s.Unavailable[i]
+		}
+	}
+	return nil
+}
+
+// Away returns the period covering t, if any.
+func (s Schedule) Away(t time.Time) *Period {
+	return s.awayOn(t.In(s.location()).Format(dateLayout))
+}
+
+// NextAway returns the first period that has not ended by t.
+func (s Schedule) NextAway(t time.Time) *Period {
+	today := t.In(s.location()).Format(dateLayout)
+	for i, p := range s.Unavailable {
+		if p.To >= today {
+			return &s.Unavailable[i]
+		}
+	}
+	return nil
+}
+
+// Deadline returns the end (midnight, in the contributor's time zone) of the days-th available day after
+// start. The day of start itself is not counted. A schedule without any weekly availability counts
+// calendar days, skipping only periods away.
+func (s Schedule) Deadline(start time.Time, days int) time.Time {
+	loc := s.location()
+	local := start.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	anyDay := len(s.Weekly) == 0
+	counted := 0
+	for i := 0; i < maxScanDays && counted < days; i++ {
+		day = day.AddDate(0, 0, 1)
+		if s.awayOn(day.Format(dateLayout)) != nil {
+			continue
+		}
+		if anyDay || len(s.Weekly[Days[day.Weekday()]]) > 0 {
+			counted++
+		}
+	}
+	return day.AddDate(0, 0, 1).UTC()
+}
@@ -0,0 +1,72 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	s := Schedule{
+		TimeZone: " Europe/Berlin ",
+		Weekly: map[string][]Window{
+			"Mon": {{Start: "13:00", End: "17:00"}, {Start: "08:00", End: "12:00"}},
+			"sat": {{Start: "20:00", End: "00:00"}},
+			"sun": {},
+		},
+		Unavailable: []Period{
+			{From: "2026-07-01", To: "2026-07-14", Reason: " holiday "},
+			{From: "2026-01-01", To: "2026-01-02"},
+			{From: "2026-06-20"},
+		},
+	}
+	if err := s.Validate(now); err != nil {
+		t.Fatal(err)
+	}
+	if s.TimeZone != "Europe/Berlin" || len(s.Weekly) != 2 || s.Weekly["mon"][0].Start != "08:00" {
+		t.Errorf("normalised = %+v", s)
+	}
+	if len(s.Unavailable) != 2 || s.Unavailable[0].To != "2026-06-20" || s.Unavailable[1].Reason != "holiday" {
+		t.Errorf("periods = %+v", s.Unavailable)
+	}
+	if got := s.HoursPerWeek(); got != 12 {
+		t.Errorf("hours = %v", got)
+	}
+
+	for name, bad := range map[string]Schedule{
+		"zone":    {TimeZone: "Mars/Olympus"},
+		"day":     {Weekly: map[string][]Window{"funday": {{Start: "09:00", End: "10:00"}}}},
+		"window":  {Weekly: map[string][]Window{"mon": {{Start: "10:00", End: "09:00"}}}},
+		"overlap": {Weekly: map[string][]Window{"mon": {{Start: "09:00", End: "12:00"}, {Start: "11:00", End: "13:00"}}}},
+		"period":  {Unavailable: []Period{{From: "2026-07-10", To: "2026-07-01"}}},
+	} {
+		if err := bad.Validate(now); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	// Friday 2026-06-12, 15:00 UTC.
+	start := time.Date(2026, 6, 12, 15, 0, 0, 0, time.UTC)
+
+	// Weekdays only: 3 available days after Friday are Mon, Tue, Wed; the deadline is the end of Wednesday.
+	if got, want := Default().Deadline(start, 3), time.Date(2026, 6, 18, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("default = %s, want %s", got, want)
+	}
+
+	s := Default()
+	s.Unavailable = []Period{{From: "2026-06-15", To: "2026-06-16"}}
+	if got, want := s.Deadline(start, 3), time.Date(2026, 6, 20, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("with time off = %s, want %s", got, want)
+	}
+
+	// Midnight in the contributor's zone.
+	s = Schedule{TimeZone: "America/New_York"}
+	if got, want := s.Deadline(start, 1), time.Date(2026, 6, 14, 4, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("calendar days = %s, want %s", got, want)
+	}
+	if !Default().AvailableOn(start) || Default().AvailableOn(start.AddDate(0, 0, 1)) {
+		t.Error("AvailableOn")
+	}
+}
@@ -0,0 +1,94 @@
+package botcomments
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+const (
+	// MaxPerIssuePerHour caps the bot comments posted on one issue in an hour.
+	MaxPerIssuePerHour = 5
+	// CoalesceWindow is how soon after a bot comment the next one is folded into the status comment
+	// rather than posted, so a burst of actions doesn't produce a burst of notifications.
+	CoalesceWindow = 2 * time.Minute
+	// maxStatusUpdates is how many folded messages the status comment shows.
+	maxStatusUpdates = 5
+)
+
+// shouldCoalesce decides whether a new bot comment is folded into the status comment, given when the
+// last one was posted and how many were posted in the past hour.
+func shouldCoalesce(lastPosted *time.Time, postedLastHour int, now time.Time) bool {
+	if postedLastHour >= MaxPerIssuePerHour {
+		return true
+	}
+	return lastPosted != nil && now.Sub(*lastPosted) < CoalesceWindow
+}
+
+// Post posts a bot comment on an issue and records it on the issue row, unless the issue is over its bot
+// comment limit. Then the message is recorded for the status comment instead and coalesced is true;
+// callers refresh the status comment afterwards (see RefreshStatusComment).
+func Post(ctx context.Context, pool *pgxpool.Pool, gh github.API, token, fullName string, projectID uuid.UUID, issueNumber int, body string) (com github.IssueComment, coalesced bool, err error) {
+	var lastPosted *time.Time
+	var postedLastHour int
+	if err := pool.QueryRow(ctx, `
+SELECT MAX(created_at), COUNT(*)
+FROM bot_comment_log
+WHERE project_id = $1 AND issue_number = $2 AND NOT coalesced AND created_at > now() - interval '1 hour'
+`, projectID, issueNumber).Scan(&lastPosted, &postedLastHour); err != nil {
+		return github.IssueComment{}, false, err
+	}
+
+	if shouldCoalesce(lastPosted, postedLastHour, time.Now()) {
+		_, err := pool.Exec(ctx, `
+INSERT INTO bot_comment_log (project_id, issue_number, body, coalesced) VALUES ($1, $2, $3, true)
+`, projectID, issueNumber, body)
+		return github.IssueComment{}, true, err
+	}
+
+	com, err = gh.CreateIssueComment(ctx, token, fullName, issueNumber, body)
+	if err != nil {
+		return github.IssueComment{}, false, err
+	}
+	commentJSON, _ := json.Marshal(com)
+	_, _ = pool.Exec(ctx, `
+UPDATE github_issues SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
+  comments_count = COALESCE(comments_count, 0) + 1, updated_at_github = $4, last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, commentJSON, com.UpdatedAt)
+	_, _ = pool.Exec(ctx, `
+INSERT INTO bot_comment_log (project_id, issue_number, github_comment_id, body) VALUES ($1, $2, $3, $4)
+`, projectID, issueNumber, com.ID, body)
+	_, _ = pool.Exec(ctx, `
+DELETE FROM bot_comment_log WHERE project_id = $1 AND issue_number = $2 AND created_at < now() - interval '1 day'
+`, projectID, issueNumber)
+	return com, false, nil
+}
+
+// recentUpdates returns the messages folded into the status comment in the past hour, newest first.
+func recentUpdates(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) []StatusUpdate {
+	rows, err := pool.Query(ctx, `
+SELECT body, created_at
+FROM bot_comment_log
+WHERE project_id = $1 AND issue_number = $2 AND coalesced AND created_at > now() - interval '1 hour'
+ORDER BY created_at DESC
+LIMIT $3
+`, projectID, issueNumber, maxStatusUpdates)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var out []StatusUpdate
+	for rows.Next() {
+		var u StatusUpdate
+		if rows.Scan(&u.Body, &u.At) == nil {
+			out = append(out, u)
+		}
+	}
+	return out
+}
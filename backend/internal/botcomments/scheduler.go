@@ -0,0 +1,237 @@
+package botcomments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/mdsanitize"
+	"github.com/jagadeesh/grainlify/backend/internal/pause"
+)
+
+const maxAttempts = 5
+
+// Scheduler posts scheduled bot comments once they are due.
+type Scheduler struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+	gh   *github.Client
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Scheduler {
+	return &Scheduler{cfg: cfg, pool: pool, gh: github.NewClient()}
+}
+
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(15 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			for {
+				err := s.processOne(ctx)
+				if errors.Is(err, pgx.ErrNoRows) {
+					break
+				}
+				if err != nil {
+					slog.Error("bot comment scheduler error", "error", err)
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *Scheduler) processOne(ctx context.Context) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id, projectID uuid.UUID
+	var issueNumber, attempts int
+	var body string
+	var varsJSON []byte
+	err = tx.QueryRow(ctx, `
+SELECT id, project_id, issue_number, body, variables, attempts
+FROM scheduled_bot_comments
+WHERE status = 'pending'
+  AND send_at <= now()
+ORDER BY send_at ASC
+FOR UPDATE SKIP LOCKED
+LIMIT 1
+`).Scan(&id, &projectID, &issueNumber, &body, &varsJSON, &attempts)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+UPDATE scheduled_bot_comments SET status = 'sending', updated_at = now() WHERE id = $1
+`, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	var vars map[string]string
+	_ = json.Unmarshal(varsJSON, &vars)
+
+	// Reminders to a contributor on vacation wait until they are back.
+	if applicant := strings.TrimSpace(vars["applicant"]); applicant != "" {
+		if until, err := pause.UntilForLogin(ctx, s.pool, applicant); err == nil && until != nil {
+			_, _ = s.pool.Exec(ctx, `
+UPDATE scheduled_bot_comments SET status = 'pending', send_at = $2, updated_at = now() WHERE id = $1
+`, id, *until)
+			return nil
+		}
+	}
+
+	commentID, sendErr := s.send(ctx, projectID, issueNumber, body, vars)
+	switch {
+	case errors.Is(sendErr, errIssueClosed):
+		_, _ = s.pool.Exec(ctx, `
+UPDATE scheduled_bot_comments
+SET status = 'cancelled', last_error = 'issue_closed', updated_at = now()
+WHERE id = $1
+`, id)
+	case sendErr != nil:
+		// Retry with backoff until maxAttempts, then give up.
+		status := "pending"
+		if attempts+1 >= maxAttempts {
+			status = "failed"
+		}
+		backoff := time.Duration(1<<attempts) * time.Minute
+		_, _ = s.pool.Exec(ctx, `
+UPDATE scheduled_bot_comments
+SET status = $2, attempts = attempts + 1, last_error = $3, send_at = now() + $4::interval, updated_at = now()
+WHERE id = $1
+`, id, status, sendErr.Error(), fmt.Sprintf("%d seconds", int(backoff.Seconds())))
+		slog.Warn("scheduled bot comment failed", "id", id, "project_id", projectID, "issue_number", issueNumber, "error", sendErr)
+	default:
+		_, _ = s.pool.Exec(ctx, `
+UPDATE scheduled_bot_comments
+SET status = 'sent', attempts = attempts + 1, github_comment_id = $2, sent_at = now(), last_error = NULL, updated_at = now()
+WHERE id = $1
+`, id, commentID)
+	}
+	return nil
+}
+
+var errIssueClosed = errors.New("issue closed")
+
+func (s *Scheduler) send(ctx context.Context, projectID uuid.UUID, issueNumber int, body string, vars map[string]string) (int64, error) {
+	if strings.TrimSpace(s.cfg.GitHubAppID) == "" || strings.TrimSpace(s.cfg.GitHubAppPrivateKey) == "" {
+		return 0, fmt.Errorf("github app not configured")
+	}
+
+	var fullName, installationID string
+	err := s.pool.QueryRow(ctx, `
+SELECT github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&fullName, &installationID)
+	if err != nil {
+		return 0, fmt.Errorf("project lookup: %w", err)
+	}
+	if installationID == "" {
+		return 0, fmt.Errorf("project has no github app installation")
+	}
+
+	builtins, state, err := IssueVariables(ctx, s.pool, projectID, issueNumber)
+	if err != nil {
+		return 0, err
+	}
+	if state != "open" {
+		return 0, errIssueClosed
+	}
+	merged := bottemplate.Merge(builtins, vars)
+	rendered := SanitizeBody(bottemplate.Render(body, merged), merged)
+
+	appClient, err := github.NewInstallationAppClient(ctx, s.pool, s.cfg, installationID)
+	if err != nil {
+		return 0, err
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		return 0, fmt.Errorf("installation token: %w", err)
+	}
+
+	ghComment, err := s.gh.CreateIssueComment(ctx, token, fullName, issueNumber, rendered)
+	if err != nil {
+		return 0, err
+	}
+
+	commentJSON, _ := json.Marshal(ghComment)
+	_, _ = s.pool.Exec(ctx, `
+UPDATE github_issues
+SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
+    comments_count = COALESCE(comments_count, 0) + 1,
+    updated_at_github = $4,
+    last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
+
+	return ghComment.ID, nil
+}
+
+// IssueVariables returns the built-in template variables for an issue along with its state.
+// Built-ins: applicant (first assignee), issue_number, issue_title, issue_url, project.
+// mentionBudget is how many people a maintainer's bot comment may @-mention besides the applicant.
+const mentionBudget = 5
+
+// SanitizeBody cleans a rendered bot comment: the applicant may always be mentioned, other
+// mentions are limited to mentionBudget, and hidden HTML comments are dropped.
+func SanitizeBody(body string, vars map[string]string) string {
+	var allow []string
+	if a := strings.TrimSpace(vars["applicant"]); a != "" {
+		allow = append(allow, a)
+	}
+	return mdsanitize.Sanitize(body, mdsanitize.Options{AllowMentions: allow, MaxMentions: mentionBudget})
+}
+
+func IssueVariables(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) (map[string]string, string, error) {
+	var fullName, title, state, issueURL string
+	var assigneesJSON []byte
+	err := pool.QueryRow(ctx, `
+SELECT p.github_full_name, gi.title, gi.state, COALESCE(gi.url, ''), gi.assignees
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE gi.project_id = $1 AND gi.number = $2
+`, projectID, issueNumber).Scan(&fullName, &title, &state, &issueURL, &assigneesJSON)
+	if err != nil {
+		return nil, "", fmt.Errorf("issue lookup: %w", err)
+	}
+
+	vars := map[string]string{
+		"issue_number": strconv.Itoa(issueNumber),
+		"issue_title":  title,
+		"issue_url":    issueURL,
+		"project":      fullName,
+	}
+	var assignees []struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(assigneesJSON, &assignees); err == nil && len(assignees) > 0 {
+		vars["applicant"] = assignees[0].Login
+	}
+	return vars, strings.ToLower(strings.TrimSpace(state)), nil
+}
@@ -0,0 +1,217 @@
+package botcomments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+)
+
+// ApplicationMarker is the header of application comments posted on behalf of applicants.
+// It is used to recognise applications among an issue's stored comments.
+const ApplicationMarker = "**📋 Grainlify Application**"
+
+// statusMarker is a hidden tag identifying the bot's status comment on an issue.
+const statusMarker = "<!-- grainlify:status -->"
+
+// IssueStatus is the data rendered into the status comment.
+type IssueStatus struct {
+	Applicants []string
+	Assignee   string
+	Deadline   *time.Time
+	Points     *int
+	State      string
+	// Updates are bot messages folded into the status comment by the rate limit (see Post).
+	Updates []StatusUpdate
+}
+
+// StatusUpdate is a bot message shown in the status comment instead of being posted.
+type StatusUpdate struct {
+	Body string
+	At   time.Time
+}
+
+// StatusBody renders the status comment markdown.
+func StatusBody(s IssueStatus) string {
+	var b strings.Builder
+	b.WriteString(statusMarker + "\n")
+	b.WriteString("**📌 Grainlify status**\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+
+	assignee := "_unassigned_"
+	if s.Assignee != "" {
+		assignee = "@" + s.Assignee
+	}
+	b.WriteString("| Assignee | " + assignee + " |\n")
+
+	applicants := "_none yet_"
+	if len(s.Applicants) > 0 {
+		at := make([]string, 0, len(s.Applicants))
+		for _, a := range s.Applicants {
+			at = append(at, "@"+a)
+		}
+		applicants = fmt.Sprintf("%d (%s)", len(s.Applicants), strings.Join(at, ", "))
+	}
+	b.WriteString("| Applicants | " + applicants + " |\n")
+
+	if s.Points != nil {
+		b.WriteString(fmt.Sprintf("| Points | %d |\n", *s.Points))
+	}
+	if s.Deadline != nil {
+		b.WriteString("| Deadline | " + s.Deadline.UTC().Format("2006-01-02 15:04 UTC") + " |\n")
+	}
+	if s.State != "" && s.State != "open" {
+		b.WriteString("| State | " + s.State + " |\n")
+	}
+	if len(s.Updates) > 0 {
+		b.WriteString("\n**Recent updates**\n")
+		for _, u := range s.Updates {
+			b.WriteString("\n> _" + u.At.UTC().Format("2006-01-02 15:04 UTC") + "_\n")
+			for _, line := range strings.Split(strings.TrimSpace(u.Body), "\n") {
+				b.WriteString("> " + line + "\n")
+			}
+		}
+	}
+	b.WriteString("\n_This comment is updated automatically by Grainlify._")
+	return b.String()
+}
+
+// LoadIssueStatus builds an IssueStatus from the stored issue row.
+func LoadIssueStatus(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) (IssueStatus, *int64, error) {
+	var state string
+	var assigneesJSON, commentsJSON []byte
+	var points *int
+	var deadline *time.Time
+	var statusCommentID *int64
+	err := pool.QueryRow(ctx, `
+SELECT state, COALESCE(assignees, '[]'::jsonb), COALESCE(comments, '[]'::jsonb), points, deadline_at, status_comment_id
+FROM github_issues
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber).Scan(&state, &assigneesJSON, &commentsJSON, &points, &deadline, &statusCommentID)
+	if err != nil {
+		return IssueStatus{}, nil, err
+	}
+
+	s := IssueStatus{State: strings.ToLower(strings.TrimSpace(state)), Points: points, Deadline: deadline}
+
+	var assignees []struct {
+		Login string `json:"login"`
+	}
+	if json.Unmarshal(assigneesJSON, &assignees) == nil && len(assignees) > 0 {
+		s.Assignee = assignees[0].Login
+	}
+
+	var comments []github.IssueComment
+	_ = json.Unmarshal(commentsJSON, &comments)
+	seen := map[string]bool{}
+	for _, com := range comments {
+		login := strings.TrimSpace(com.User.Login)
+		if login == "" || !strings.Contains(com.Body, ApplicationMarker) || seen[strings.ToLower(login)] {
+			continue
+		}
+		seen[strings.ToLower(login)] = true
+		s.Applicants = append(s.Applicants, login)
+	}
+
+	// Quick applications ("/apply" comments) don't carry the marker; they are tracked in issue_applications.
+	rows, err := pool.Query(ctx, `
+SELECT github_login
+FROM issue_applications
+WHERE project_id = $1 AND issue_number = $2 AND source = 'quick_apply' AND status = 'pending'
+ORDER BY created_at ASC
+`, projectID, issueNumber)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var login string
+			if rows.Scan(&login) != nil || seen[strings.ToLower(login)] {
+				continue
+			}
+			seen[strings.ToLower(login)] = true
+			s.Applicants = append(s.Applicants, login)
+		}
+	}
+	s.Updates = recentUpdates(ctx, pool, projectID, issueNumber)
+	return s, statusCommentID, nil
+}
+
+// RefreshStatusComment creates or updates the bot's status comment for an issue and stores its ID
+// on the issue row. Projects without a GitHub App installation and native tasks are skipped.
+func RefreshStatusComment(ctx context.Context, cfg config.Config, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) error {
+	if strings.TrimSpace(cfg.GitHubAppID) == "" || strings.TrimSpace(cfg.GitHubAppPrivateKey) == "" || tasks.IsNative(issueNumber) {
+		return nil
+	}
+	var fullName, installationID string
+	if err := pool.QueryRow(ctx, `
+SELECT github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&fullName, &installationID); err != nil {
+		return fmt.Errorf("project lookup: %w", err)
+	}
+	if installationID == "" {
+		return nil
+	}
+
+	status, statusCommentID, err := LoadIssueStatus(ctx, pool, projectID, issueNumber)
+	if err != nil {
+		return fmt.Errorf("issue lookup: %w", err)
+	}
+	body := StatusBody(status)
+
+	appClient, err := github.NewInstallationAppClient(ctx, pool, cfg, installationID)
+	if err != nil {
+		return err
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		return fmt.Errorf("installation token: %w", err)
+	}
+	gh := github.NewClient()
+
+	if statusCommentID != nil && *statusCommentID > 0 {
+		com, err := gh.UpdateIssueComment(ctx, token, fullName, *statusCommentID, body)
+		if err == nil {
+			commentJSON, _ := json.Marshal(com)
+			_, _ = pool.Exec(ctx, `
+UPDATE github_issues
+SET comments = (
+  SELECT COALESCE(jsonb_agg(CASE WHEN (elem->>'id')::bigint = $3 THEN $4::jsonb ELSE elem END), '[]'::jsonb)
+  FROM jsonb_array_elements(COALESCE(comments, '[]'::jsonb)) AS elem
+),
+last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, com.ID, commentJSON)
+			return nil
+		}
+		// The comment was deleted on GitHub: fall through and post a fresh one.
+		var ghErr *github.GitHubAPIError
+		if !errors.As(err, &ghErr) || ghErr.StatusCode != 404 {
+			return err
+		}
+	}
+
+	com, err := gh.CreateIssueComment(ctx, token, fullName, issueNumber, body)
+	if err != nil {
+		return err
+	}
+	commentJSON, _ := json.Marshal(com)
+	_, err = pool.Exec(ctx, `
+UPDATE github_issues
+SET status_comment_id = $3,
+    comments = COALESCE(comments, '[]'::jsonb) || $4::jsonb,
+    comments_count = COALESCE(comments_count, 0) + 1,
+    last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, com.ID, commentJSON)
+	return err
+}
@@ -0,0 +1,73 @@
+package botcomments
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusBody(t *testing.T) {
+	points := 50
+	deadline := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	body := StatusBody(IssueStatus{
+		Applicants: []string{"alice", "bob"},
+		Assignee:   "alice",
+		Points:     &points,
+		Deadline:   &deadline,
+		State:      "open",
+	})
+
+	for _, want := range []string{
+		statusMarker,
+		"| Assignee | @alice |",
+		"| Applicants | 2 (@alice, @bob) |",
+		"| Points | 50 |",
+		"| Deadline | 2026-01-31 12:00 UTC |",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("StatusBody() missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "| State |") {
+		t.Errorf("StatusBody() should not show state for open issues")
+	}
+}
+
+func TestStatusBodyEmpty(t *testing.T) {
+	body := StatusBody(IssueStatus{State: "closed"})
+	for _, want := range []string{"_unassigned_", "_none yet_", "| State | closed |"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("StatusBody() missing %q", want)
+		}
+	}
+}
+
+func TestStatusBodyUpdates(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	body := StatusBody(IssueStatus{Updates: []StatusUpdate{{Body: "Assigned to @alice.\nGood luck!", At: at}}})
+	for _, want := range []string{"**Recent updates**", "> _2026-03-01 09:30 UTC_", "> Assigned to @alice.\n> Good luck!"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("StatusBody() missing %q in:\n%s", want, body)
+		}
+	}
+}
+
+func TestShouldCoalesce(t *testing.T) {
+	now := time.Now()
+	recent, old := now.Add(-30*time.Second), now.Add(-10*time.Minute)
+	cases := []struct {
+		last   *time.Time
+		posted int
+		want   bool
+	}{
+		{nil, 0, false},
+		{&old, 1, false},
+		{&recent, 1, true},
+		{&old, MaxPerIssuePerHour, true},
+	}
+	for _, tc := range cases {
+		if got := shouldCoalesce(tc.last, tc.posted, now); got != tc.want {
+			t.Errorf("shouldCoalesce(%v, %d) = %v, want %v", tc.last, tc.posted, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package botmessages holds the wording of the bot's own comments (assignment, rejection, unassign
+// notices and so on) in several languages. Projects or ecosystems pick a locale and may override
+// individual messages; placeholders use the bot template {{name}} syntax.
+package botmessages
+
+import "sort"
+
+// DefaultLocale is used when neither the project nor its ecosystem picked one, and as the
+// fallback for messages missing from a locale.
+const DefaultLocale = "en"
+
+// Message keys.
+const (
+	Assigned          = "assigned"           // {{assignee}}, {{manage_url}}
+	AssignedDeadline  = "assigned_deadline"  // {{deadline}}
+	Rejected          = "rejected"           // {{applicant}}
+	Unassigned        = "unassigned"         // {{assignees}}
+	UnassignedOverdue = "unassigned_overdue" // {{assignees}}, {{deadline}}
+	WaitlistNext      = "waitlist_next"      // {{next}}
+	QuickApplyAck     = "quick_apply_ack"    // {{applicant}}
+	HandedOff         = "handed_off"         // {{from}}, {{to}}
+)
+
+// Variables lists the placeholders each message can use.
+var Variables = map[string][]string{
+	Assigned:          {"assignee", "manage_url"},
+	AssignedDeadline:  {"deadline"},
+	Rejected:          {"applicant"},
+	Unassigned:        {"assignees"},
+	UnassignedOverdue: {"assignees", "deadline"},
+	WaitlistNext:      {"next"},
+	QuickApplyAck:     {"applicant"},
+	HandedOff:         {"from", "to"},
+}
+
+var catalog = map[string]map[string]string{
+	"en": {
+		Assigned: "Congratulations, **@{{assignee}}**! 🎉 Your application was accepted by the repo's maintainers.\n\n" +
+			"Please resolve the issue such that the repo's maintainers have enough time to review your contribution.\n\n" +
+			"> ⚠️ **Warning:** When opening a PR, please link it to this issue to ensure it gets tracked accurately.\n\n" +
+			"**Repo maintainers:** You can manage this issue, including adjusting complexity and points, [here]({{manage_url}}).",
+		AssignedDeadline:  "⏰ **Deadline:** {{deadline}}. If you need more time, request an extension from the Grainlify dashboard before then.",
+		Rejected:          "@{{applicant}} your application was not accepted for this issue. The maintainer may assign another contributor.",
+		Unassigned:        "{{assignees}} has been unassigned from this issue. The maintainer may assign another contributor.",
+		UnassignedOverdue: "{{assignees}} has been unassigned from this issue because the deadline ({{deadline}}) passed. The issue is open for other contributors.",
+		WaitlistNext:      "@{{next}} is next on the waitlist.",
+		QuickApplyAck:     "@{{applicant}} thanks! Your application for this issue has been recorded in Grainlify. A maintainer will review it soon.",
+		HandedOff:         "@{{from}} has handed this issue over to @{{to}}, with the maintainer's approval. @{{to}} is now assigned.",
+	},
+	"es": {
+		Assigned: "¡Felicidades, **@{{assignee}}**! 🎉 Los mantenedores del repositorio aceptaron tu solicitud.\n\n" +
+			"Resuelve el issue con tiempo suficiente para que los mantenedores puedan revisar tu contribución.\n\n" +
+			"> ⚠️ **Aviso:** Al abrir un PR, enlázalo a este issue para que se registre correctamente.\n\n" +
+			"**Mantenedores:** Pueden gestionar este issue, incluida la complejidad y los puntos, [aquí]({{manage_url}}).",
+		AssignedDeadline:  "⏰ **Fecha límite:** {{deadline}}. Si necesitas más tiempo, solicita una prórroga desde el panel de Grainlify antes de esa fecha.",
+		Rejected:          "@{{applicant}} tu solicitud para este issue no fue aceptada. El mantenedor puede asignar a otra persona.",
+		Unassigned:        "{{assignees}} ya no está asignado a este issue. El mantenedor puede asignar a otra persona.",
+		UnassignedOverdue: "{{assignees}} ya no está asignado a este issue porque venció la fecha límite ({{deadline}}). El issue queda abierto para otras personas.",
+		WaitlistNext:      "@{{next}} es la siguiente persona en la lista de espera.",
+		QuickApplyAck:     "@{{applicant}} ¡gracias! Tu solicitud para este issue quedó registrada en Grainlify. Un mantenedor la revisará pronto.",
+		HandedOff:         "@{{from}} traspasó este issue a @{{to}}, con la aprobación del mantenedor. Ahora @{{to}} está asignado.",
+	},
+	"fr": {
+		Assigned: "Félicitations, **@{{assignee}}** ! 🎉 Ta candidature a été acceptée par les mainteneurs du dépôt.\n\n" +
+			"Merci de résoudre l'issue en laissant aux mainteneurs le temps de relire ta contribution.\n\n" +
+			"> ⚠️ **Attention :** en ouvrant une PR, lie-la à cette issue pour qu'elle soit bien suivie.\n\n" +
+			"**Mainteneurs :** vous pouvez gérer cette issue, y compris la complexité et les points, [ici]({{manage_url}}).",
+		AssignedDeadline:  "⏰ **Échéance :** {{deadline}}. Si tu as besoin de plus de temps, demande un délai depuis le tableau de bord Grainlify avant cette date.",
+		Rejected:          "@{{applicant}} ta candidature pour cette issue n'a pas été retenue. Le mainteneur peut assigner quelqu'un d'autre.",
+		Unassigned:        "{{assignees}} n'est plus assigné·e à cette issue. Le mainteneur peut assigner quelqu'un d'autre.",
+		UnassignedOverdue: "{{assignees}} n'est plus assigné·e à cette issue car l'échéance ({{deadline}}) est dépassée. L'issue est ouverte à d'autres contributeurs.",
+		WaitlistNext:      "@{{next}} est la prochaine personne sur la liste d'attente.",
+		QuickApplyAck:     "@{{applicant}} merci ! Ta candidature pour cette issue a été enregistrée sur Grainlify. Un mainteneur l'examinera bientôt.",
+		HandedOff:         "@{{from}} a passé cette issue à @{{to}}, avec l'accord du mainteneur. @{{to}} est maintenant assigné·e.",
+	},
+	"pt": {
+		Assigned: "Parabéns, **@{{assignee}}**! 🎉 Sua candidatura foi aceita pelos mantenedores do repositório.\n\n" +
+			"Resolva a issue com antecedência suficiente para que os mantenedores possam revisar sua contribuição.\n\n" +
+			"> ⚠️ **Atenção:** Ao abrir um PR, vincule-o a esta issue para que seja acompanhado corretamente.\n\n" +
+			"**Mantenedores:** vocês podem gerenciar esta issue, incluindo complexidade e pontos, [aqui]({{manage_url}}).",
+		AssignedDeadline:  "⏰ **Prazo:** {{deadline}}. Se precisar de mais tempo, solicite uma prorrogação pelo painel do Grainlify antes dessa data.",
+		Rejected:          "@{{applicant}} sua candidatura para esta issue não foi aceita. O mantenedor pode atribuir outra pessoa.",
+		Unassigned:        "{{assignees}} não está mais atribuído a esta issue. O mantenedor pode atribuir outra pessoa.",
+		UnassignedOverdue: "{{assignees}} não está mais atribuído a esta issue porque o prazo ({{deadline}}) expirou. A issue está aberta para outras pessoas.",
+		WaitlistNext:      "@{{next}} é a próxima pessoa na lista de espera.",
+		QuickApplyAck:     "@{{applicant}} obrigado! Sua candidatura para esta issue foi registrada no Grainlify. Um mantenedor vai analisá-la em breve.",
+		HandedOff:         "@{{from}} repassou esta issue para @{{to}}, com a aprovação do mantenedor. Agora @{{to}} está atribuído.",
+	},
+}
+
+// Locales returns the supported locale codes, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(catalog))
+	for l := range catalog {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Supported reports whether locale has a catalog.
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// Keys returns all message keys, sorted.
+func Keys() []string {
+	out := make([]string, 0, len(Variables))
+	for k := range Variables {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Default returns the built-in wording of key in locale, falling back to DefaultLocale.
+func Default(locale, key string) string {
+	if m, ok := catalog[locale][key]; ok {
+		return m
+	}
+	return catalog[DefaultLocale][key]
+}
@@ -0,0 +1,34 @@
+package botmessages
+
+import (
+	"testing"
+
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+)
+
+func TestCatalogComplete(t *testing.T) {
+	for _, locale := range Locales() {
+		for _, key := range Keys() {
+			body, ok := catalog[locale][key]
+			if !ok {
+				t.Errorf("%s: missing %q", locale, key)
+				continue
+			}
+			allowed := map[string]bool{}
+			for _, v := range Variables[key] {
+				allowed[v] = true
+			}
+			for _, v := range bottemplate.Variables(body) {
+				if !allowed[v] {
+					t.Errorf("%s/%s: unknown variable %q", locale, key, v)
+				}
+			}
+		}
+	}
+}
+
+func TestDefaultFallsBackToEnglish(t *testing.T) {
+	if got := Default("xx", WaitlistNext); got != catalog[DefaultLocale][WaitlistNext] {
+		t.Errorf("fallback = %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package botmessages
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+)
+
+// Render returns message key for a project with vars filled in. A project override wins over an
+// ecosystem override, which wins over the built-in wording for the project's (or ecosystem's) locale.
+func Render(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, key string, vars map[string]string) string {
+	locale := DefaultLocale
+	var override *string
+	_ = pool.QueryRow(ctx, `
+SELECT COALESCE(p.bot_locale, e.bot_locale, $3),
+  (SELECT o.body FROM bot_message_overrides o
+   WHERE o.key = $2 AND (o.project_id = p.id OR (o.project_id IS NULL AND o.ecosystem_id = p.ecosystem_id))
+   ORDER BY o.project_id IS NULL
+   LIMIT 1)
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, projectID, key, DefaultLocale).Scan(&locale, &override)
+
+	body := Default(locale, key)
+	if override != nil && *override != "" {
+		body = *override
+	}
+	return bottemplate.Render(body, vars)
+}
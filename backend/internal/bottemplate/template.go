@@ -0,0 +1,48 @@
+// Package bottemplate renders maintainer-defined bot comment templates.
+// Variables use the {{name}} syntax (whitespace inside the braces is ignored);
+// unknown variables are left in place so a missing value is visible rather than silently dropped.
+package bottemplate
+
+import (
+	"regexp"
+	"strings"
+)
+
+var varPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// Render substitutes {{name}} placeholders with values from vars.
+func Render(body string, vars map[string]string) string {
+	return varPattern.ReplaceAllStringFunc(body, func(m string) string {
+		name := varPattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[strings.ToLower(name)]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// Variables returns the distinct (lower-cased) variable names referenced by body, in order of first use.
+func Variables(body string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, m := range varPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.ToLower(m[1])
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Merge returns a new map with base values overridden by overrides. Keys are lower-cased.
+func Merge(base, overrides map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		out[strings.ToLower(k)] = v
+	}
+	for k, v := range overrides {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package bottemplate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	body := "Hi @{{applicant}}, please finish by {{ deadline }} ({{points}} pts). {{unknown}}"
+	got := Render(body, map[string]string{
+		"applicant": "octocat",
+		"deadline":  "2026-01-31",
+		"points":    "50",
+	})
+	want := "Hi @octocat, please finish by 2026-01-31 (50 pts). {{unknown}}"
+	if got != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderCaseInsensitive(t *testing.T) {
+	if got := Render("{{Applicant}}", map[string]string{"applicant": "x"}); got != "x" {
+		t.Errorf("Render() = %q, want %q", got, "x")
+	}
+}
+
+func TestVariables(t *testing.T) {
+	got := Variables("{{applicant}} {{deadline}} {{ applicant }} {{Points}} {{not valid}}")
+	want := []string{"applicant", "deadline", "points"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Variables() = %v, want %v", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := Merge(map[string]string{"applicant": "a", "points": "1"}, map[string]string{"Points": "2"})
+	if got["applicant"] != "a" || got["points"] != "2" {
+		t.Fatalf("Merge() = %v", got)
+	}
+}
@@ -0,0 +1,128 @@
+// Package checkruns publishes a Grainlify check run on pull requests that close tracked issues,
+// showing whether the PR author is the issue's assignee and how many points are at stake.
+package checkruns
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CheckName is the check run name shown on pull requests.
+const CheckName = "Grainlify"
+
+// closingPattern matches GitHub's closing keywords followed by a same-repo issue reference ("Fixes #12").
+var closingPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+#(\d+)\b`)
+
+// LinkedIssues returns the distinct issue numbers a PR body claims to close, in ascending order.
+func LinkedIssues(body string) []int {
+	seen := map[int]bool{}
+	var out []int
+	for _, m := range closingPattern.FindAllStringSubmatch(body, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// Issue is the tracked state of an issue referenced by a PR.
+type Issue struct {
+	Number    int
+	Title     string
+	State     string
+	Assignees []string
+	Points    *int
+}
+
+// Result is the rendered check run outcome.
+type Result struct {
+	Conclusion string
+	Title      string
+	Summary    string
+}
+
+// Evaluate decides the check outcome for a PR by author against the tracked issues it closes.
+// The PR passes when the author is assigned to every linked issue. Unassigned issues are neutral
+// (a maintainer may still assign the author); issues assigned to someone else fail the check.
+func Evaluate(author string, issues []Issue) Result {
+	if len(issues) == 0 {
+		return Result{
+			Conclusion: "neutral",
+			Title:      "No Grainlify issues linked",
+			Summary:    "This pull request does not close any issue tracked by Grainlify.",
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("| Issue | Assignee | Points | Status |\n|---|---|---|---|\n")
+	totalPoints := 0
+	unassigned, other := 0, 0
+	for _, is := range issues {
+		assignee := "_unassigned_"
+		status := "⚠️ not assigned"
+		switch {
+		case len(is.Assignees) == 0:
+			unassigned++
+		case containsFold(is.Assignees, author):
+			assignee = "@" + strings.Join(is.Assignees, ", @")
+			status = "✅ assigned to author"
+		default:
+			assignee = "@" + strings.Join(is.Assignees, ", @")
+			status = "❌ assigned to someone else"
+			other++
+		}
+		points := "–"
+		if is.Points != nil {
+			points = strconv.Itoa(*is.Points)
+			totalPoints += *is.Points
+		}
+		if is.State != "" && is.State != "open" {
+			status += " (issue " + is.State + ")"
+		}
+		b.WriteString(fmt.Sprintf("| #%d %s | %s | %s | %s |\n", is.Number, escapeCell(is.Title), assignee, points, status))
+	}
+	if totalPoints > 0 {
+		b.WriteString(fmt.Sprintf("\n**Points at stake:** %d\n", totalPoints))
+	}
+
+	r := Result{Summary: b.String()}
+	switch {
+	case other > 0:
+		r.Conclusion = "failure"
+		r.Title = fmt.Sprintf("@%s is not the assignee of %s", author, plural(other, "linked issue"))
+	case unassigned > 0:
+		r.Conclusion = "neutral"
+		r.Title = fmt.Sprintf("%s not assigned yet", plural(unassigned, "linked issue"))
+	default:
+		r.Conclusion = "success"
+		r.Title = fmt.Sprintf("@%s is assigned to %s", author, plural(len(issues), "linked issue"))
+	}
+	return r
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
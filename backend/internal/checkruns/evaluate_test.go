@@ -0,0 +1,81 @@
+package checkruns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinkedIssues(t *testing.T) {
+	cases := map[string][]int{
+		"Fixes #12":                         {12},
+		"closes #3, resolves #1 and fix #3": {1, 3},
+		"Resolved: #7":                      {7},
+		"See #4 for context":                nil,
+		"prefix#5 fixes":                    nil,
+		"fixes other/repo#9":                nil,
+	}
+	for body, want := range cases {
+		if got := LinkedIssues(body); !reflect.DeepEqual(got, want) {
+			t.Errorf("LinkedIssues(%q) = %v, want %v", body, got, want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	pts := 50
+	tests := []struct {
+		name   string
+		issues []Issue
+		want   string
+	}{
+		{"none", nil, "neutral"},
+		{"assigned", []Issue{{Number: 1, State: "open", Assignees: []string{"Alice"}, Points: &pts}}, "success"},
+		{"unassigned", []Issue{{Number: 1, State: "open"}}, "neutral"},
+		{"other", []Issue{{Number: 1, Assignees: []string{"alice"}}, {Number: 2, Assignees: []string{"bob"}}}, "failure"},
+	}
+	for _, tt := range tests {
+		if got := Evaluate("alice", tt.issues); got.Conclusion != tt.want {
+			t.Errorf("%s: conclusion = %q, want %q", tt.name, got.Conclusion, tt.want)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	issues := []Issue{{Number: 4, Assignees: []string{"alice"}}}
+	codes := func(author string, p Provenance, issues []Issue) []string {
+		return FlagCodes(Review(author, p, issues))
+	}
+	own := Provenance{BaseRepo: "acme/app", HeadRepo: "alice/app", HeadOwner: "alice", CommitAuthors: []string{"alice", ""}}
+	if got := codes("alice", own, issues); len(got) != 0 {
+		t.Errorf("own fork flagged: %v", got)
+	}
+	branch := Provenance{BaseRepo: "acme/app", HeadRepo: "ACME/app", HeadOwner: "acme"}
+	if got := codes("alice", branch, issues); len(got) != 0 {
+		t.Errorf("upstream branch flagged: %v", got)
+	}
+	unmatched := Provenance{BaseRepo: "acme/app", HeadRepo: "alice/app", HeadOwner: "alice", CommitAuthors: []string{""}}
+	if got := codes("alice", unmatched, issues); len(got) != 0 {
+		t.Errorf("unmatched commits flagged: %v", got)
+	}
+
+	other := Provenance{BaseRepo: "acme/app", HeadRepo: "bob/app", HeadOwner: "bob", CommitAuthors: []string{"bob", "carol"}}
+	got := Review("alice", other, []Issue{{Number: 4, Assignees: []string{"dave"}}, {Number: 5}})
+	want := []string{FlagForeignFork, FlagCommitsByOthers, FlagAuthorNotAssignee}
+	if !reflect.DeepEqual(FlagCodes(got), want) {
+		t.Fatalf("Review = %v, want %v", FlagCodes(got), want)
+	}
+	if got[1].Message != "None of the commits are by @alice; they are by @bob, @carol." || got[2].Message != "@alice is not the assignee of #4." {
+		t.Errorf("messages = %q", got)
+	}
+
+	pass := Result{Conclusion: "success", Title: "ok", Summary: "table\n"}
+	if r := WithProvenance(pass, got[:1], false); r.Conclusion != "neutral" || r.Title != "Needs maintainer review: 1 provenance flag" {
+		t.Errorf("unreviewed = %+v", r)
+	}
+	if r := WithProvenance(pass, got[:1], true); r.Conclusion != "success" || r.Title != "ok" {
+		t.Errorf("reviewed = %+v", r)
+	}
+	if r := WithProvenance(pass, nil, false); r != pass {
+		t.Errorf("no flags = %+v", r)
+	}
+}
@@ -0,0 +1,108 @@
+package checkruns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Provenance flags: reasons a maintainer should look at who did the work on a pull request.
+const (
+	// FlagForeignFork: the pull request comes from a fork owned by someone other than its author.
+	FlagForeignFork = "fork_not_owned_by_author"
+	// FlagCommitsByOthers: none of the commits GitHub matched to an account are the author's.
+	FlagCommitsByOthers = "commits_by_others"
+	// FlagAuthorNotAssignee: a linked issue is assigned to someone other than the author.
+	FlagAuthorNotAssignee = "author_not_assignee"
+)
+
+// Provenance is where a pull request's changes come from.
+type Provenance struct {
+	BaseRepo  string // the project's repository, owner/name
+	HeadRepo  string // the repository the branch lives in; "" when unknown (e.g. a deleted fork)
+	HeadOwner string
+	// CommitAuthors are the logins GitHub matched to the commits' emails; "" for unmatched commits. nil
+	// when the commits couldn't be loaded.
+	CommitAuthors []string
+}
+
+// Flag is a provenance problem with a human-readable explanation.
+type Flag struct {
+	Code    string
+	Message string
+}
+
+// Review checks that the pull request comes from the author's own fork or a branch of the project, that
+// the author wrote at least one of the commits GitHub could attribute, and that the author is the
+// assignee of the tracked issues it closes.
+func Review(author string, p Provenance, issues []Issue) []Flag {
+	var flags []Flag
+	if p.HeadRepo != "" && !strings.EqualFold(p.HeadRepo, p.BaseRepo) && !strings.EqualFold(p.HeadOwner, author) {
+		flags = append(flags, Flag{FlagForeignFork,
+			fmt.Sprintf("The branch is in %s, a fork owned by @%s rather than @%s.", p.HeadRepo, p.HeadOwner, author)})
+	}
+	others := map[string]bool{}
+	byAuthor := false
+	for _, login := range p.CommitAuthors {
+		switch {
+		case login == "":
+		case strings.EqualFold(login, author):
+			byAuthor = true
+		default:
+			others[login] = true
+		}
+	}
+	if !byAuthor && len(others) > 0 {
+		names := make([]string, 0, len(others))
+		for l := range others {
+			names = append(names, "@"+l)
+		}
+		sort.Strings(names)
+		flags = append(flags, Flag{FlagCommitsByOthers,
+			fmt.Sprintf("None of the commits are by @%s; they are by %s.", author, strings.Join(names, ", "))})
+	}
+	var assigned []string
+	for _, is := range issues {
+		if len(is.Assignees) > 0 && !containsFold(is.Assignees, author) {
+			assigned = append(assigned, fmt.Sprintf("#%d", is.Number))
+		}
+	}
+	if len(assigned) > 0 {
+		flags = append(flags, Flag{FlagAuthorNotAssignee,
+			fmt.Sprintf("@%s is not the assignee of %s.", author, strings.Join(assigned, ", "))})
+	}
+	return flags
+}
+
+// FlagCodes returns the codes of flags, for storing.
+func FlagCodes(flags []Flag) []string {
+	out := make([]string, 0, len(flags))
+	for _, f := range flags {
+		out = append(out, f.Code)
+	}
+	return out
+}
+
+// WithProvenance adds the provenance flags to a check result. Unless a maintainer has reviewed them, a
+// passing check becomes neutral so the flags aren't missed.
+func WithProvenance(r Result, flags []Flag, reviewed bool) Result {
+	if len(flags) == 0 {
+		return r
+	}
+	var b strings.Builder
+	b.WriteString(r.Summary)
+	if reviewed {
+		b.WriteString("\n**Reviewed by a maintainer:**\n")
+	} else {
+		b.WriteString("\n**Needs maintainer review:**\n")
+	}
+	for _, f := range flags {
+		b.WriteString("- " + f.Message + "\n")
+	}
+	r.Summary = b.String()
+	if !reviewed && r.Conclusion == "success" {
+		r.Conclusion = "neutral"
+		r.Title = "Needs maintainer review: " + plural(len(flags), "provenance flag")
+	}
+	return r
+}
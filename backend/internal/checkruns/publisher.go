@@ -0,0 +1,285 @@
+package checkruns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+const maxAttempts = 5
+
+// Publisher creates or updates check runs for pr_check_runs rows flagged needs_publish.
+type Publisher struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+	gh   *github.Client
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Publisher {
+	return &Publisher{cfg: cfg, pool: pool, gh: github.NewClient()}
+}
+
+func (p *Publisher) Run(ctx context.Context) error {
+	if p.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(10 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			for {
+				err := p.processOne(ctx)
+				if errors.Is(err, pgx.ErrNoRows) {
+					break
+				}
+				if err != nil {
+					slog.Error("check run publisher error", "error", err)
+					break
+				}
+			}
+		}
+	}
+}
+
+// MarkIssueChanged flags check runs of PRs linked to an issue for republishing (best-effort).
+func MarkIssueChanged(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) {
+	_, _ = pool.Exec(ctx, `
+UPDATE pr_check_runs SET needs_publish = true, attempts = 0, updated_at = now()
+WHERE project_id = $1 AND $2 = ANY(issue_numbers)
+`, projectID, issueNumber)
+}
+
+func (p *Publisher) processOne(ctx context.Context) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id, projectID uuid.UUID
+	var prNumber, attempts int
+	var headSHA, author string
+	var issueNumbers []int32
+	var checkRunID *int64
+	var pr prState
+	err = tx.QueryRow(ctx, `
+SELECT id, project_id, pr_number, head_sha, author_login, issue_numbers, check_run_id, attempts,
+       COALESCE(head_repo_full_name, ''), COALESCE(head_repo_owner, ''), flags, flags_reviewed_at IS NOT NULL
+FROM pr_check_runs
+WHERE needs_publish
+ORDER BY updated_at ASC
+FOR UPDATE SKIP LOCKED
+LIMIT 1
+`).Scan(&id, &projectID, &prNumber, &headSHA, &author, &issueNumbers, &checkRunID, &attempts,
+		&pr.headRepo, &pr.headOwner, &pr.flags, &pr.reviewed)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE pr_check_runs SET needs_publish = false WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	newID, conclusion, flags, pubErr := p.publish(ctx, projectID, prNumber, headSHA, author, issueNumbers, checkRunID, pr)
+	if pubErr != nil {
+		// Leave the row flagged for another try unless we have exhausted our attempts.
+		_, _ = p.pool.Exec(ctx, `
+UPDATE pr_check_runs
+SET needs_publish = attempts + 1 < $3, attempts = attempts + 1, last_error = $2, updated_at = now()
+WHERE id = $1
+`, id, pubErr.Error(), maxAttempts)
+		slog.Warn("check run publish failed", "project_id", projectID, "pr_number", prNumber, "error", pubErr)
+		return nil
+	}
+	// New or different flags need a fresh review.
+	_, _ = p.pool.Exec(ctx, `
+UPDATE pr_check_runs
+SET check_run_id = $2, conclusion = $3, attempts = 0, last_error = NULL, published_at = now(), updated_at = now(),
+    flags_reviewed_at = CASE WHEN flags = $4 THEN flags_reviewed_at END,
+    flags_reviewed_by_user_id = CASE WHEN flags = $4 THEN flags_reviewed_by_user_id END,
+    flags = $4
+WHERE id = $1
+`, id, newID, conclusion, flags)
+	if len(flags) > 0 && !sameFlags(flags, pr.flags) {
+		p.notifyFlagged(ctx, projectID, prNumber, author, flags)
+	}
+	return nil
+}
+
+// prState is what the publisher knows of a PR besides its check run: where its branch lives and the
+// provenance flags found last time.
+type prState struct {
+	headRepo, headOwner string
+	flags               []string
+	reviewed            bool
+}
+
+func sameFlags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// notifyFlagged tells the project owner a pull request needs a provenance review (best-effort).
+func (p *Publisher) notifyFlagged(ctx context.Context, projectID uuid.UUID, prNumber int, author string, flags []string) {
+	p.notifyOwner(ctx, projectID, "pr_provenance_flagged", map[string]any{
+		"project_id": projectID.String(), "pr_number": prNumber, "author": author, "flags": flags,
+	})
+}
+
+// notifyOwner stores a notification for the project's owner (best-effort).
+func (p *Publisher) notifyOwner(ctx context.Context, projectID uuid.UUID, kind string, payload map[string]any) {
+	var ownerID uuid.UUID
+	if err := p.pool.QueryRow(ctx, `SELECT owner_user_id FROM projects WHERE id = $1`, projectID).Scan(&ownerID); err != nil {
+		slog.Warn("failed to look up project owner for notification", "project_id", projectID, "kind", kind, "error", err)
+		return
+	}
+	_ = notify.Store(ctx, p.pool, ownerID, kind, payload)
+}
+
+func (p *Publisher) publish(ctx context.Context, projectID uuid.UUID, prNumber int, headSHA, author string, issueNumbers []int32, checkRunID *int64, pr prState) (int64, string, []string, error) {
+	if strings.TrimSpace(p.cfg.GitHubAppID) == "" || strings.TrimSpace(p.cfg.GitHubAppPrivateKey) == "" {
+		return 0, "", nil, fmt.Errorf("github app not configured")
+	}
+	var fullName, installationID string
+	if err := p.pool.QueryRow(ctx, `
+SELECT github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&fullName, &installationID); err != nil {
+		return 0, "", nil, fmt.Errorf("project lookup: %w", err)
+	}
+	if installationID == "" {
+		return 0, "", nil, fmt.Errorf("project has no github app installation")
+	}
+
+	issues, err := p.loadIssues(ctx, projectID, issueNumbers)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	res := Evaluate(author, issues)
+
+	appClient, err := github.NewInstallationAppClient(ctx, p.pool, p.cfg, installationID)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("github app client: %w", err)
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("installation token: %w", err)
+	}
+
+	// Provenance and similarity are only checked on PRs that close tracked issues; both are best-effort.
+	var flags []Flag
+	if len(issues) > 0 {
+		prov := Provenance{BaseRepo: fullName, HeadRepo: pr.headRepo, HeadOwner: pr.headOwner}
+		if commits, err := p.gh.ListPRCommits(ctx, token, fullName, prNumber); err == nil {
+			prov.CommitAuthors = make([]string, 0, len(commits))
+			for _, cm := range commits {
+				login := ""
+				if cm.Author != nil {
+					login = cm.Author.Login
+				}
+				prov.CommitAuthors = append(prov.CommitAuthors, login)
+			}
+		} else {
+			slog.Warn("check run commit lookup failed", "project_id", projectID, "pr_number", prNumber, "error", err)
+		}
+		flags = Review(author, prov, issues)
+		// A review covers the flags it was given; different ones need another.
+		res = WithProvenance(res, flags, pr.reviewed && sameFlags(FlagCodes(flags), pr.flags))
+		if err := p.compareDiffs(ctx, token, fullName, projectID, prNumber, headSHA, issueNumbers); err != nil {
+			slog.Warn("pull request diff comparison failed", "project_id", projectID, "pr_number", prNumber, "error", err)
+		}
+	}
+	codes := FlagCodes(flags)
+
+	run := github.CheckRun{
+		Name:       CheckName,
+		HeadSHA:    headSHA,
+		Conclusion: res.Conclusion,
+		Title:      res.Title,
+		Summary:    res.Summary,
+	}
+	if base := strings.TrimRight(strings.TrimSpace(p.cfg.FrontendBaseURL), "/"); base != "" {
+		run.DetailsURL = fmt.Sprintf("%s/dashboard?tab=browse&project=%s", base, projectID.String())
+	}
+
+	if checkRunID != nil {
+		err := p.gh.UpdateCheckRun(ctx, token, fullName, *checkRunID, run)
+		if err == nil {
+			return *checkRunID, res.Conclusion, codes, nil
+		}
+		var ghErr *github.GitHubAPIError
+		if !errors.As(err, &ghErr) || ghErr.StatusCode != 404 {
+			return 0, "", nil, err
+		}
+		// The check run is gone; create a fresh one below.
+	}
+	newID, err := p.gh.CreateCheckRun(ctx, token, fullName, run)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return newID, res.Conclusion, codes, nil
+}
+
+// loadIssues returns the tracked issues among issueNumbers; numbers Grainlify doesn't know are skipped.
+func (p *Publisher) loadIssues(ctx context.Context, projectID uuid.UUID, issueNumbers []int32) ([]Issue, error) {
+	if len(issueNumbers) == 0 {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx, `
+SELECT number, title, state, COALESCE(assignees, '[]'::jsonb), points
+FROM github_issues
+WHERE project_id = $1 AND number = ANY($2)
+ORDER BY number ASC
+`, projectID, issueNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("issue lookup: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Issue
+	for rows.Next() {
+		var is Issue
+		var assigneesJSON []byte
+		if err := rows.Scan(&is.Number, &is.Title, &is.State, &assigneesJSON, &is.Points); err != nil {
+			return nil, err
+		}
+		is.State = strings.ToLower(strings.TrimSpace(is.State))
+		var assignees []struct {
+			Login string `json:"login"`
+		}
+		_ = json.Unmarshal(assigneesJSON, &assignees)
+		for _, a := range assignees {
+			if a.Login != "" {
+				is.Assignees = append(is.Assignees, a.Login)
+			}
+		}
+		out = append(out, is)
+	}
+	return out, rows.Err()
+}
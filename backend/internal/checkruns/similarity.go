@@ -0,0 +1,116 @@
+package checkruns
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/similarity"
+)
+
+// compareDiffs fingerprints the pull request's diff (once per head commit) and compares it with the other
+// pull requests closing any of the same issues. Pairs at or above similarity.FlagThreshold are flagged
+// for the maintainer, who is notified of new ones; pairs that drift apart are unflagged.
+func (p *Publisher) compareDiffs(ctx context.Context, token, fullName string, projectID uuid.UUID, prNumber int, headSHA string, issueNumbers []int32) error {
+	var fingerprint []int64
+	var fingerprintSHA *string
+	var createdAt *time.Time
+	if err := p.pool.QueryRow(ctx, `
+SELECT r.fingerprint, r.fingerprint_sha, pr.created_at_github
+FROM pr_check_runs r
+LEFT JOIN github_pull_requests pr ON pr.project_id = r.project_id AND pr.number = r.pr_number
+WHERE r.project_id = $1 AND r.pr_number = $2
+`, projectID, prNumber).Scan(&fingerprint, &fingerprintSHA, &createdAt); err != nil {
+		return err
+	}
+	if fingerprintSHA == nil || *fingerprintSHA != headSHA {
+		files, err := p.gh.ListPRFiles(ctx, token, fullName, prNumber)
+		if err != nil {
+			return err
+		}
+		patches := make([]string, 0, len(files))
+		for _, f := range files {
+			patches = append(patches, f.Patch)
+		}
+		fingerprint = similarity.Fingerprint(patches)
+		if _, err := p.pool.Exec(ctx, `
+UPDATE pr_check_runs SET fingerprint = $3, fingerprint_sha = $4 WHERE project_id = $1 AND pr_number = $2
+`, projectID, prNumber, fingerprint, headSHA); err != nil {
+			return err
+		}
+	}
+
+	rows, err := p.pool.Query(ctx, `
+SELECT r.pr_number, r.fingerprint, ARRAY(SELECT unnest(r.issue_numbers) INTERSECT SELECT unnest($3::int[])),
+       pr.created_at_github
+FROM pr_check_runs r
+LEFT JOIN github_pull_requests pr ON pr.project_id = r.project_id AND pr.number = r.pr_number
+WHERE r.project_id = $1 AND r.pr_number <> $2 AND r.issue_numbers && $3::int[] AND cardinality(r.fingerprint) > 0
+`, projectID, prNumber, issueNumbers)
+	if err != nil {
+		return err
+	}
+	type other struct {
+		number    int
+		score     float64
+		issues    []int32
+		createdAt *time.Time
+	}
+	var others []other
+	for rows.Next() {
+		var o other
+		var fp []int64
+		if err := rows.Scan(&o.number, &fp, &o.issues, &o.createdAt); err != nil {
+			rows.Close()
+			return err
+		}
+		o.score = similarity.Jaccard(fingerprint, fp)
+		others = append(others, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, o := range others {
+		later, earlier := prNumber, o.number
+		if openedBefore(createdAt, prNumber, o.createdAt, o.number) {
+			later, earlier = o.number, prNumber
+		}
+		if o.score < similarity.FlagThreshold {
+			if _, err := p.pool.Exec(ctx, `
+DELETE FROM pr_similarity_flags WHERE project_id = $1 AND pr_number = $2 AND similar_pr_number = $3
+`, projectID, later, earlier); err != nil {
+				return err
+			}
+			continue
+		}
+		var inserted bool
+		if err := p.pool.QueryRow(ctx, `
+INSERT INTO pr_similarity_flags (project_id, pr_number, similar_pr_number, issue_numbers, similarity)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (project_id, pr_number, similar_pr_number) DO UPDATE SET
+  issue_numbers = EXCLUDED.issue_numbers, similarity = EXCLUDED.similarity, updated_at = now()
+RETURNING xmax = 0
+`, projectID, later, earlier, o.issues, o.score).Scan(&inserted); err != nil {
+			return err
+		}
+		if inserted {
+			p.notifyOwner(ctx, projectID, "pr_similarity_flagged", map[string]any{
+				"project_id": projectID.String(), "pr_number": later, "similar_pr_number": earlier,
+				"issue_numbers": o.issues, "similarity": o.score,
+			})
+		}
+	}
+	return nil
+}
+
+// openedBefore reports whether pull request a was opened before b, by creation time when both are known
+// and by number otherwise.
+func openedBefore(aCreated *time.Time, a int, bCreated *time.Time, b int) bool {
+	if aCreated != nil && bCreated != nil && !aCreated.Equal(*bCreated) {
+		return aCreated.Before(*bCreated)
+	}
+	return a < b
+}
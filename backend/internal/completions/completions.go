@@ -0,0 +1,128 @@
+// Package completions records how long assigned issues took to finish: from the assignment on Grainlify
+// to the merge of the pull request that closed the issue. A periodic job rebuilds issue_completions, and
+// GET /analytics/time-to-complete aggregates it into percentiles per label, complexity or project so
+// maintainers can calibrate points and deadlines.
+package completions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+)
+
+// Assignment is an issue assigned through Grainlify. AssignedAt is the first assignment of Login.
+type Assignment struct {
+	ProjectID   uuid.UUID
+	IssueNumber int
+	Login       string
+	AssignedAt  time.Time
+}
+
+// PullRequest is a merged pull request.
+type PullRequest struct {
+	ProjectID uuid.UUID
+	Number    int
+	Author    string
+	Body      string
+	MergedAt  time.Time
+}
+
+// ClosedTask is a native task (see package tasks) the maintainer closed as completed.
+type ClosedTask struct {
+	ProjectID uuid.UUID
+	Number    int
+	ClosedAt  time.Time
+}
+
+// Completion is an assigned issue closed by a merged pull request, or a native task closed as completed
+// (PRNumber 0, MergedAt the close).
+type Completion struct {
+	ProjectID   uuid.UUID
+	IssueNumber int
+	Login       string
+	PRNumber    int
+	AssignedAt  time.Time
+	MergedAt    time.Time
+}
+
+// Duration is the time from assignment to merge.
+func (c Completion) Duration() time.Duration { return c.MergedAt.Sub(c.AssignedAt) }
+
+type issueKey struct {
+	projectID uuid.UUID
+	number    int
+}
+
+// Match pairs merged pull requests with the assigned issues they close ("Fixes #12"). When an issue was
+// assigned to several people, the assignment of the PR author is used, falling back to the earliest one.
+// Each issue completes at its first merge after assignment; merges before the assignment are ignored.
+func Match(assignments []Assignment, prs []PullRequest) []Completion {
+	byIssue := map[issueKey][]Assignment{}
+	for _, a := range assignments {
+		k := issueKey{a.ProjectID, a.IssueNumber}
+		byIssue[k] = append(byIssue[k], a)
+	}
+
+	best := map[issueKey]Completion{}
+	var order []issueKey
+	for _, pr := range prs {
+		for _, n := range checkruns.LinkedIssues(pr.Body) {
+			k := issueKey{pr.ProjectID, n}
+			a, ok := pick(byIssue[k], pr.Author)
+			if !ok || pr.MergedAt.Before(a.AssignedAt) {
+				continue
+			}
+			c := Completion{ProjectID: k.projectID, IssueNumber: n, Login: a.Login, PRNumber: pr.Number,
+				AssignedAt: a.AssignedAt, MergedAt: pr.MergedAt}
+			prev, seen := best[k]
+			if !seen {
+				order = append(order, k)
+			}
+			if !seen || c.MergedAt.Before(prev.MergedAt) {
+				best[k] = c
+			}
+		}
+	}
+	out := make([]Completion, 0, len(order))
+	for _, k := range order {
+		out = append(out, best[k])
+	}
+	return out
+}
+
+// MatchTasks completes native tasks, which have no pull request: each completes for its assignee (the
+// earliest one if several) when it was closed. Tasks closed before they were assigned are ignored.
+func MatchTasks(assignments []Assignment, closed []ClosedTask) []Completion {
+	byIssue := map[issueKey][]Assignment{}
+	for _, a := range assignments {
+		k := issueKey{a.ProjectID, a.IssueNumber}
+		byIssue[k] = append(byIssue[k], a)
+	}
+	var out []Completion
+	for _, t := range closed {
+		a, ok := pick(byIssue[issueKey{t.ProjectID, t.Number}], "")
+		if !ok || t.ClosedAt.Before(a.AssignedAt) {
+			continue
+		}
+		out = append(out, Completion{ProjectID: t.ProjectID, IssueNumber: t.Number, Login: a.Login,
+			AssignedAt: a.AssignedAt, MergedAt: t.ClosedAt})
+	}
+	return out
+}
+
+func pick(candidates []Assignment, author string) (Assignment, bool) {
+	var earliest Assignment
+	found := false
+	for _, a := range candidates {
+		if strings.EqualFold(a.Login, author) {
+			return a, true
+		}
+		if !found || a.AssignedAt.Before(earliest.AssignedAt) {
+			earliest, found = a, true
+		}
+	}
+	return earliest, found
+}
@@ -0,0 +1,111 @@
+package completions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMatch(t *testing.T) {
+	project := uuid.New()
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	assignments := []Assignment{
+		{ProjectID: project, IssueNumber: 1, Login: "alice", AssignedAt: t0},
+		{ProjectID: project, IssueNumber: 2, Login: "bob", AssignedAt: t0},
+		{ProjectID: project, IssueNumber: 2, Login: "carol", AssignedAt: t0.Add(24 * time.Hour)},
+		{ProjectID: project, IssueNumber: 3, Login: "dave", AssignedAt: t0.Add(72 * time.Hour)},
+	}
+	prs := []PullRequest{
+		{ProjectID: project, Number: 10, Author: "Alice", Body: "Fixes #1", MergedAt: t0.Add(5 * time.Hour)},
+		{ProjectID: project, Number: 11, Author: "alice", Body: "closes #1", MergedAt: t0.Add(2 * time.Hour)},
+		{ProjectID: project, Number: 12, Author: "carol", Body: "Resolves #2 and fixes #9", MergedAt: t0.Add(48 * time.Hour)},
+		// Merged before the issue was assigned: not a completion of that assignment.
+		{ProjectID: project, Number: 13, Author: "dave", Body: "fixes #3", MergedAt: t0},
+		{ProjectID: uuid.New(), Number: 14, Author: "alice", Body: "fixes #1", MergedAt: t0},
+	}
+
+	got := Match(assignments, prs)
+	if len(got) != 2 {
+		t.Fatalf("got %d completions: %+v", len(got), got)
+	}
+	if c := got[0]; c.IssueNumber != 1 || c.PRNumber != 11 || c.Duration() != 2*time.Hour {
+		t.Errorf("issue 1 = %+v", c)
+	}
+	if c := got[1]; c.IssueNumber != 2 || c.Login != "carol" || c.Duration() != 24*time.Hour {
+		t.Errorf("issue 2 = %+v (want the PR author's assignment)", c)
+	}
+}
+
+func TestMatchTasks(t *testing.T) {
+	project := uuid.New()
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	assignments := []Assignment{
+		{ProjectID: project, IssueNumber: 1_000_000_000, Login: "erin", AssignedAt: t0.Add(time.Hour)},
+		{ProjectID: project, IssueNumber: 1_000_000_000, Login: "frank", AssignedAt: t0},
+		{ProjectID: project, IssueNumber: 1_000_000_001, Login: "gina", AssignedAt: t0.Add(time.Hour)},
+	}
+	closed := []ClosedTask{
+		{ProjectID: project, Number: 1_000_000_000, ClosedAt: t0.Add(30 * time.Hour)},
+		// Closed before it was assigned.
+		{ProjectID: project, Number: 1_000_000_001, ClosedAt: t0},
+		// Never assigned.
+		{ProjectID: project, Number: 1_000_000_002, ClosedAt: t0},
+	}
+
+	got := MatchTasks(assignments, closed)
+	if len(got) != 1 {
+		t.Fatalf("got %d completions: %+v", len(got), got)
+	}
+	if c := got[0]; c.Login != "frank" || c.PRNumber != 0 || c.Duration() != 30*time.Hour {
+		t.Errorf("task = %+v (want the earliest assignment)", c)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	shares := []Share{{Login: "alice", Percent: 50}, {Login: "bob", Percent: 30}, {Login: "carol", Percent: 20}}
+	if err := ValidateShares(shares); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		points int
+		want   []int
+	}{
+		{10, []int{5, 3, 2}},
+		{7, []int{4, 2, 1}}, // 3.5, 2.1, 1.4: the half point goes to alice
+		{1, []int{1, 0, 0}},
+		{0, []int{0, 0, 0}},
+	} {
+		got := Split(tc.points, shares)
+		sum := 0
+		for i, c := range got {
+			sum += c.Points
+			if c.Points != tc.want[i] {
+				t.Errorf("Split(%d)[%s] = %d, want %d", tc.points, c.Login, c.Points, tc.want[i])
+			}
+		}
+		if sum != tc.points {
+			t.Errorf("Split(%d) hands out %d points", tc.points, sum)
+		}
+	}
+	// Equal remainders and shares: alphabetical.
+	if got := Split(1, []Share{{Login: "zed", Percent: 50}, {Login: "amy", Percent: 50}}); got[1].Points != 1 {
+		t.Errorf("tie = %+v", got)
+	}
+
+	for name, bad := range map[string][]Share{
+		"total":     {{Login: "a", Percent: 60}, {Login: "b", Percent: 30}},
+		"duplicate": {{Login: "a", Percent: 50}, {Login: "A", Percent: 50}},
+		"zero":      {{Login: "a", Percent: 100}, {Login: "b", Percent: 0}},
+		"no login":  {{Login: " ", Percent: 100}},
+	} {
+		if ValidateShares(bad) == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	even := EvenShares([]string{"a", "b", "c"})
+	if even[0].Percent != 34 || even[1].Percent != 33 || even[2].Percent != 33 || ValidateShares(even) != nil {
+		t.Errorf("EvenShares = %+v", even)
+	}
+}
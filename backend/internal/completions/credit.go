@@ -0,0 +1,92 @@
+package completions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Share is a contributor's percentage of an issue's points, when the maintainer splits the credit for an
+// issue completed across several pull requests or by several contributors.
+type Share struct {
+	Login   string `json:"login"`
+	Percent int    `json:"percent"`
+}
+
+// Credit is the points a contributor earned for an issue.
+type Credit struct {
+	Login   string `json:"login"`
+	Points  int    `json:"points"`
+	Percent int    `json:"percent,omitempty"`
+}
+
+// ValidateShares checks that shares name distinct contributors, each with 1-100 percent, adding up to 100.
+func ValidateShares(shares []Share) error {
+	seen := map[string]bool{}
+	total := 0
+	for _, s := range shares {
+		login := strings.ToLower(strings.TrimSpace(s.Login))
+		if login == "" {
+			return fmt.Errorf("share without a login")
+		}
+		if seen[login] {
+			return fmt.Errorf("%s has more than one share", s.Login)
+		}
+		seen[login] = true
+		if s.Percent < 1 || s.Percent > 100 {
+			return fmt.Errorf("%s's share must be between 1 and 100 percent", s.Login)
+		}
+		total += s.Percent
+	}
+	if len(shares) > 0 && total != 100 {
+		return fmt.Errorf("shares add up to %d%%, not 100%%", total)
+	}
+	return nil
+}
+
+// Split divides points by shares. Whole points are handed out by largest remainder, so the credits always
+// add up to points; ties go to the larger share, then the login first in alphabetical order.
+func Split(points int, shares []Share) []Credit {
+	out := make([]Credit, len(shares))
+	rem := make([]int, len(shares))
+	given := 0
+	for i, s := range shares {
+		out[i] = Credit{Login: s.Login, Points: points * s.Percent / 100, Percent: s.Percent}
+		rem[i] = points * s.Percent % 100
+		given += out[i].Points
+	}
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if rem[i] != rem[j] {
+			return rem[i] > rem[j]
+		}
+		if shares[i].Percent != shares[j].Percent {
+			return shares[i].Percent > shares[j].Percent
+		}
+		return strings.ToLower(shares[i].Login) < strings.ToLower(shares[j].Login)
+	})
+	for k := 0; given < points && len(order) > 0; k = (k + 1) % len(order) {
+		out[order[k]].Points++
+		given++
+	}
+	return out
+}
+
+// EvenShares splits 100 percent evenly between logins, the remainder going to the first ones.
+func EvenShares(logins []string) []Share {
+	if len(logins) == 0 {
+		return nil
+	}
+	out := make([]Share, len(logins))
+	for i, l := range logins {
+		out[i] = Share{Login: l, Percent: 100 / len(logins)}
+		if i < 100%len(logins) {
+			out[i].Percent++
+		}
+	}
+	return out
+}
@@ -0,0 +1,266 @@
+package completions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/estimate"
+	"github.com/jagadeesh/grainlify/backend/internal/ledger"
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+)
+
+const (
+	// Interval is how often issue_completions is rebuilt.
+	Interval = time.Hour
+	// historyDays is how far back merged pull requests (and completed native tasks) are considered.
+	historyDays = 730
+)
+
+// Job rebuilds issue_completions from assignments and merged pull requests, plus native tasks closed as
+// completed.
+type Job struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(15 * time.Minute)
+	defer t.Stop()
+	for {
+		if j.due(ctx) {
+			if err := j.Compute(ctx, time.Now()); err != nil {
+				slog.Error("issue completion analytics failed", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (j *Job) due(ctx context.Context) bool {
+	var last *time.Time
+	if err := j.pool.QueryRow(ctx, `SELECT MAX(computed_at) FROM issue_completions`).Scan(&last); err != nil {
+		return false
+	}
+	return last == nil || time.Since(*last) >= Interval
+}
+
+type issueInfo struct {
+	issue  estimate.Issue
+	points int
+}
+
+// Compute matches assignments with merged pull requests, classifies each completed issue's complexity
+// against its project's history, and replaces the stored rows.
+func (j *Job) Compute(ctx context.Context, now time.Time) error {
+	started := time.Now()
+	assignments, issues, err := j.assignments(ctx)
+	if err != nil {
+		return fmt.Errorf("assignments: %w", err)
+	}
+	prs, err := j.mergedPRs(ctx)
+	if err != nil {
+		return fmt.Errorf("pull requests: %w", err)
+	}
+	closedTasks, err := j.closedTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("native tasks: %w", err)
+	}
+	completed := append(Match(assignments, prs), MatchTasks(assignments, closedTasks)...)
+	splits, err := j.splits(ctx)
+	if err != nil {
+		return fmt.Errorf("credit splits: %w", err)
+	}
+
+	// Each project's completions are the history its own issues are classified against.
+	history := map[uuid.UUID][]estimate.Sample{}
+	for _, c := range completed {
+		info := issues[issueKey{c.ProjectID, c.IssueNumber}]
+		history[c.ProjectID] = append(history[c.ProjectID], estimate.Sample{
+			Labels: info.issue.Labels, Points: info.points, Hours: c.Duration().Hours(),
+		})
+	}
+
+	batch := &pgx.Batch{}
+	credits := map[issueKey][]Credit{}
+	for _, c := range completed {
+		k := issueKey{c.ProjectID, c.IssueNumber}
+		info := issues[k]
+		var points *int
+		if info.points > 0 {
+			points = &info.points
+		}
+		credits[k] = []Credit{}
+		if shares := splits[k]; len(shares) > 0 {
+			credits[k] = Split(info.points, shares)
+		}
+		creditsJSON, _ := json.Marshal(credits[k])
+		labels := info.issue.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		batch.Queue(`
+INSERT INTO issue_completions (project_id, issue_number, assignee_login, pr_number, assigned_at, merged_at,
+                               duration_seconds, points, labels, complexity, credits, computed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (project_id, issue_number) DO UPDATE SET
+  assignee_login = EXCLUDED.assignee_login,
+  pr_number = EXCLUDED.pr_number,
+  assigned_at = EXCLUDED.assigned_at,
+  merged_at = EXCLUDED.merged_at,
+  duration_seconds = EXCLUDED.duration_seconds,
+  points = EXCLUDED.points,
+  labels = EXCLUDED.labels,
+  complexity = EXCLUDED.complexity,
+  credits = EXCLUDED.credits,
+  computed_at = EXCLUDED.computed_at
+`, c.ProjectID, c.IssueNumber, c.Login, c.PRNumber, c.AssignedAt, c.MergedAt, int64(c.Duration().Seconds()),
+			points, labels, estimate.Classify(info.issue, info.points, history[c.ProjectID]), creditsJSON, now)
+	}
+	if err := j.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	// Issues that no longer qualify (assignment withdrawn, PR body edited) drop out.
+	if _, err := j.pool.Exec(ctx, `DELETE FROM issue_completions WHERE computed_at < $1`, now); err != nil {
+		return err
+	}
+
+	// Points are credited in the ledger, to the assignee or split as the maintainer decided; completions
+	// that drop out are taken back.
+	var awards []ledger.Award
+	for _, c := range completed {
+		k := issueKey{c.ProjectID, c.IssueNumber}
+		if len(credits[k]) == 0 {
+			credits[k] = []Credit{{Login: c.Login, Points: issues[k].points}}
+		}
+		for _, cr := range credits[k] {
+			if cr.Points > 0 {
+				awards = append(awards, ledger.Award{ProjectID: c.ProjectID, IssueNumber: c.IssueNumber, Login: cr.Login, Points: int64(cr.Points)})
+			}
+		}
+	}
+	posted, err := ledger.SyncPoints(ctx, j.pool, awards, now.AddDate(0, 0, -historyDays))
+	if err != nil {
+		return fmt.Errorf("points ledger: %w", err)
+	}
+	slog.Info("issue completion analytics computed", "assignments", len(assignments), "merged_prs", len(prs),
+		"completions", len(completed), "points_posted", posted, "duration", time.Since(started))
+	return nil
+}
+
+// assignments loads current Grainlify assignments and the issues they are on.
+func (j *Job) assignments(ctx context.Context) ([]Assignment, map[issueKey]issueInfo, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT a.project_id, a.issue_number, a.github_login, MIN(COALESCE(a.decided_at, a.created_at)),
+       COALESCE(gi.title, ''), COALESCE(gi.body, ''), COALESCE(gi.labels, '[]'::jsonb), COALESCE(gi.points, 0)
+FROM issue_applications a
+JOIN github_issues gi ON gi.project_id = a.project_id AND gi.number = a.issue_number
+JOIN projects p ON p.id = a.project_id AND p.deleted_at IS NULL
+WHERE a.status = 'assigned'
+GROUP BY a.project_id, a.issue_number, a.github_login, gi.title, gi.body, gi.labels, gi.points
+`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var out []Assignment
+	issues := map[issueKey]issueInfo{}
+	for rows.Next() {
+		var a Assignment
+		var info issueInfo
+		var labelsJSON []byte
+		if err := rows.Scan(&a.ProjectID, &a.IssueNumber, &a.Login, &a.AssignedAt,
+			&info.issue.Title, &info.issue.Body, &labelsJSON, &info.points); err != nil {
+			return nil, nil, err
+		}
+		info.issue.Labels = recommend.LabelNames(labelsJSON)
+		out = append(out, a)
+		issues[issueKey{a.ProjectID, a.IssueNumber}] = info
+	}
+	return out, issues, rows.Err()
+}
+
+// splits loads the credit splits maintainers set, largest share first.
+func (j *Job) splits(ctx context.Context) (map[issueKey][]Share, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT project_id, issue_number, login, percent
+FROM issue_credit_splits
+ORDER BY project_id, issue_number, percent DESC, LOWER(login)
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[issueKey][]Share{}
+	for rows.Next() {
+		var k issueKey
+		var s Share
+		if err := rows.Scan(&k.projectID, &k.number, &s.Login, &s.Percent); err != nil {
+			return nil, err
+		}
+		out[k] = append(out[k], s)
+	}
+	return out, rows.Err()
+}
+
+// closedTasks loads native tasks closed as completed within the history window.
+func (j *Job) closedTasks(ctx context.Context) ([]ClosedTask, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT project_id, number, closed_at_github
+FROM github_issues
+WHERE task_type <> 'code' AND state = 'closed' AND state_reason = 'completed'
+  AND closed_at_github >= now() - make_interval(days => $1)
+ORDER BY closed_at_github
+`, historyDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ClosedTask
+	for rows.Next() {
+		var t ClosedTask
+		if err := rows.Scan(&t.ProjectID, &t.Number, &t.ClosedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (j *Job) mergedPRs(ctx context.Context) ([]PullRequest, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT project_id, number, COALESCE(author_login, ''), COALESCE(body, ''), merged_at_github
+FROM github_pull_requests
+WHERE merged AND merged_at_github IS NOT NULL AND merged_at_github >= now() - make_interval(days => $1)
+ORDER BY merged_at_github
+`, historyDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []PullRequest
+	for rows.Next() {
+		var pr PullRequest
+		if err := rows.Scan(&pr.ProjectID, &pr.Number, &pr.Author, &pr.Body, &pr.MergedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, pr)
+	}
+	return out, rows.Err()
+}
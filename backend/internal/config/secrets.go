@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jagadeesh/grainlify/backend/internal/secrets"
+)
+
+// Settings read with getSecretEnv may be secret references (aws-sm://, gcp-sm://, vault://, see package
+// secrets) instead of literal values. They are resolved in Load and, while WatchSecrets runs, refreshed in
+// the background. Refreshed values reach code through the accessors that read them (DefaultGitHubApp,
+// GitHubApp, GitHubWebhookSecrets, TokenEncKey, DatabaseURL); everything else uses the value from startup.
+var (
+	liveMu   sync.RWMutex
+	liveRefs = map[string]string{} // setting -> reference
+	live     = map[string]string{} // setting -> current resolved value
+)
+
+// getSecretEnv is getEnv for settings that may be secret references.
+func getSecretEnv(key, fallback string) string {
+	return resolveSecret(key, getEnv(key, fallback))
+}
+
+// resolveSecret resolves v when it is a reference, remembering it under name for refreshes.
+// A reference that cannot be resolved yields "" (and an error log), never the reference itself.
+func resolveSecret(name, v string) string {
+	if !secrets.IsRef(v) {
+		return v
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	out, err := secrets.Resolve(ctx, v)
+	liveMu.Lock()
+	liveRefs[name] = v
+	if err == nil {
+		live[name] = out
+	}
+	liveMu.Unlock()
+	if err != nil {
+		slog.Error("failed to resolve secret", "setting", name, "error", err)
+		return ""
+	}
+	return out
+}
+
+// liveOr returns the latest refreshed value of a referenced setting, or v when it isn't one.
+func liveOr(name, v string) string {
+	liveMu.RLock()
+	defer liveMu.RUnlock()
+	if cur, ok := live[name]; ok {
+		return cur
+	}
+	return v
+}
+
+// TokenEncKey is TOKEN_ENC_KEY_B64, following secret rotation.
+func (c Config) TokenEncKey() string {
+	return liveOr("TOKEN_ENC_KEY_B64", c.TokenEncKeyB64)
+}
+
+// TaxEncKey is TAX_ENC_KEY_B64, following secret rotation.
+func (c Config) TaxEncKey() string {
+	return liveOr("TAX_ENC_KEY_B64", c.TaxEncKeyB64)
+}
+
+// DatabaseURL is DB_URL, following secret rotation. New pool connections use it (see db.Connect).
+func (c Config) DatabaseURL() string {
+	return liveOr("DB_URL", c.DBURL)
+}
+
+// HasSecretRefs reports whether any setting was loaded from a secrets manager.
+func HasSecretRefs() bool {
+	liveMu.RLock()
+	defer liveMu.RUnlock()
+	return len(liveRefs) > 0
+}
+
+// UnresolvedSecrets maps settings whose reference has never resolved to that reference.
+func UnresolvedSecrets() map[string]string {
+	liveMu.RLock()
+	defer liveMu.RUnlock()
+	out := map[string]string{}
+	for name, ref := range liveRefs {
+		if _, ok := live[name]; !ok {
+			out[name] = ref
+		}
+	}
+	return out
+}
+
+// WatchSecrets re-resolves every referenced setting each interval until ctx is done.
+func WatchSecrets(ctx context.Context, every time.Duration) {
+	if every <= 0 {
+		return
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		liveMu.RLock()
+		refs := make(map[string]string, len(liveRefs))
+		for k, v := range liveRefs {
+			refs[k] = v
+		}
+		liveMu.RUnlock()
+
+		for name, ref := range refs {
+			rctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			v, err := secrets.Resolve(rctx, ref)
+			cancel()
+			if err != nil {
+				// Keep serving the last good value.
+				slog.Warn("secret refresh failed", "setting", name, "error", err)
+				continue
+			}
+			liveMu.Lock()
+			changed := live[name] != v
+			live[name] = v
+			liveMu.Unlock()
+			if changed {
+				slog.Info("secret rotated", "setting", name)
+			}
+		}
+	}
+}
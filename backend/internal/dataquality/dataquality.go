@@ -0,0 +1,330 @@
+// Package dataquality finds records that disagree with each other or with GitHub (assignments GitHub no
+// longer has, verified projects without an App installation, sync jobs whose worker is gone, comment
+// counts that don't match the stored comments) and reconciles them on request.
+package dataquality
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+)
+
+// Kind names a check.
+type Kind string
+
+const (
+	AssignmentDrift             Kind = "assignment_drift"
+	VerifiedWithoutInstallation Kind = "verified_without_installation"
+	OrphanedSyncJobs            Kind = "orphaned_sync_jobs"
+	CommentDrift                Kind = "comment_drift"
+)
+
+// Kinds lists every check, in report order.
+var Kinds = []Kind{AssignmentDrift, VerifiedWithoutInstallation, OrphanedSyncJobs, CommentDrift}
+
+// ParseKind returns the check named s.
+func ParseKind(s string) (Kind, bool) {
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// StaleRunningAfter is how long a sync job may stay running before its worker is presumed gone.
+const StaleRunningAfter = 30 * time.Minute
+
+// commentPage is how many comments the sync stores per issue (one page of GitHub's default size), so
+// issues with more comments only drift when fewer than that are stored.
+const commentPage = 30
+
+// Finding is one inconsistent record. Fields not relevant to the check are left empty.
+type Finding struct {
+	ProjectID   uuid.UUID  `json:"project_id"`
+	Repo        string     `json:"repo"`
+	IssueNumber int        `json:"issue_number,omitempty"`
+	Login       string     `json:"login,omitempty"`
+	JobID       *uuid.UUID `json:"job_id,omitempty"`
+	JobType     string     `json:"job_type,omitempty"`
+	Detail      string     `json:"detail"`
+	Since       *time.Time `json:"since,omitempty"`
+}
+
+// Section is one check's findings: the total, and up to limit examples.
+type Section struct {
+	Kind     Kind      `json:"kind"`
+	Count    int       `json:"count"`
+	Findings []Finding `json:"findings"`
+}
+
+const assignmentDriftSQL = `
+FROM issue_applications ia
+JOIN projects p ON p.id = ia.project_id AND p.deleted_at IS NULL
+JOIN github_issues gi ON gi.project_id = ia.project_id AND gi.number = ia.issue_number
+WHERE ia.status = 'assigned' AND gi.state = 'open'
+  AND NOT EXISTS (
+    SELECT 1 FROM jsonb_array_elements(COALESCE(gi.assignees, '[]'::jsonb)) a
+    WHERE LOWER(a->>'login') = LOWER(ia.github_login)
+  )`
+
+const missingInstallationSQL = `
+FROM projects p
+WHERE p.status = 'verified' AND p.deleted_at IS NULL AND COALESCE(p.github_app_installation_id, '') = ''`
+
+// Orphaned: running with no word from the worker for StaleRunningAfter ($1), or queued for a project that
+// was deleted since.
+const orphanedJobsSQL = `
+FROM sync_jobs j
+JOIN projects p ON p.id = j.project_id
+WHERE (j.status = 'running' AND COALESCE(j.locked_at, j.updated_at) < now() - $1::interval)
+   OR (j.status IN ('pending', 'running') AND p.deleted_at IS NOT NULL)`
+
+const commentDriftSQL = `
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id AND p.deleted_at IS NULL
+WHERE COALESCE(jsonb_array_length(gi.comments), 0) <> LEAST(COALESCE(gi.comments_count, 0), $1)`
+
+// Check runs one check, returning up to limit findings.
+func Check(ctx context.Context, pool *pgxpool.Pool, kind Kind, limit int) (Section, error) {
+	s := Section{Kind: kind, Findings: []Finding{}}
+	var countSQL, listSQL string
+	var args []any
+	switch kind {
+	case AssignmentDrift:
+		countSQL = `SELECT COUNT(*)` + assignmentDriftSQL
+		listSQL = `
+SELECT ia.project_id, p.github_full_name, ia.issue_number, ia.github_login, NULL::uuid, '',
+       'assigned in Grainlify but not on GitHub', COALESCE(ia.decided_at, ia.updated_at)` + assignmentDriftSQL + `
+ORDER BY COALESCE(ia.decided_at, ia.updated_at)`
+	case VerifiedWithoutInstallation:
+		countSQL = `SELECT COUNT(*)` + missingInstallationSQL
+		listSQL = `
+SELECT p.id, p.github_full_name, 0, '', NULL::uuid, '', 'verified without a GitHub App installation', p.updated_at` +
+			missingInstallationSQL + `
+ORDER BY p.updated_at`
+	case OrphanedSyncJobs:
+		args = []any{fmt.Sprintf("%d seconds", int(StaleRunningAfter.Seconds()))}
+		countSQL = `SELECT COUNT(*)` + orphanedJobsSQL
+		listSQL = `
+SELECT j.project_id, p.github_full_name, 0, COALESCE(j.locked_by, ''), j.id, j.job_type,
+       CASE WHEN p.deleted_at IS NOT NULL THEN 'queued for a deleted project' ELSE 'running with no worker' END,
+       COALESCE(j.locked_at, j.updated_at)` + orphanedJobsSQL + `
+ORDER BY COALESCE(j.locked_at, j.updated_at)`
+	case CommentDrift:
+		args = []any{commentPage}
+		countSQL = `SELECT COUNT(*)` + commentDriftSQL
+		listSQL = `
+SELECT gi.project_id, p.github_full_name, gi.number, '', NULL::uuid, '',
+       format('%s comments on GitHub, %s stored', COALESCE(gi.comments_count, 0), COALESCE(jsonb_array_length(gi.comments), 0)),
+       gi.last_seen_at` + commentDriftSQL + `
+ORDER BY gi.last_seen_at NULLS FIRST`
+	default:
+		return s, fmt.Errorf("unknown check %q", kind)
+	}
+
+	if err := pool.QueryRow(ctx, countSQL, args...).Scan(&s.Count); err != nil {
+		return s, err
+	}
+	if s.Count == 0 || limit <= 0 {
+		return s, nil
+	}
+	rows, err := pool.Query(ctx, listSQL+fmt.Sprintf("\nLIMIT %d", limit), args...)
+	if err != nil {
+		return s, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f Finding
+		if err := rows.Scan(&f.ProjectID, &f.Repo, &f.IssueNumber, &f.Login, &f.JobID, &f.JobType, &f.Detail, &f.Since); err != nil {
+			return s, err
+		}
+		s.Findings = append(s.Findings, f)
+	}
+	return s, rows.Err()
+}
+
+// Report runs every check.
+func Report(ctx context.Context, pool *pgxpool.Pool, limit int) ([]Section, error) {
+	out := make([]Section, 0, len(Kinds))
+	for _, k := range Kinds {
+		s, err := Check(ctx, pool, k, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Result says what a reconciliation did.
+type Result struct {
+	Kind Kind `json:"kind"`
+	// Fixed records were corrected in place.
+	Fixed int `json:"fixed"`
+	// Queued sync jobs will refresh records from GitHub.
+	Queued int `json:"queued"`
+	// Notified project owners have to act themselves.
+	Notified int `json:"notified"`
+}
+
+// Reconcile fixes what one check found:
+//   - assignment_drift: assignments GitHub dropped after they were made are released (and their locked
+//     rewards returned); for the rest our copy of the issue may be stale, so the project's issues are resynced.
+//   - verified_without_installation: owners are asked to install the GitHub App; only they can.
+//   - orphaned_sync_jobs: stuck jobs are queued again, jobs of deleted projects are failed.
+//   - comment_drift: the projects' issues are resynced.
+func Reconcile(ctx context.Context, pool *pgxpool.Pool, kind Kind) (Result, error) {
+	switch kind {
+	case AssignmentDrift:
+		return reconcileAssignments(ctx, pool)
+	case VerifiedWithoutInstallation:
+		n, err := notifyMissingInstallations(ctx, pool)
+		return Result{Kind: kind, Notified: n}, err
+	case OrphanedSyncJobs:
+		failed, err := pool.Exec(ctx, `
+UPDATE sync_jobs j SET status = 'failed', last_error = 'project deleted', locked_at = NULL, locked_by = NULL, updated_at = now()
+FROM projects p
+WHERE p.id = j.project_id AND j.status IN ('pending', 'running') AND p.deleted_at IS NOT NULL`)
+		if err != nil {
+			return Result{Kind: kind}, err
+		}
+		requeued, err := pool.Exec(ctx, `
+UPDATE sync_jobs
+SET status = 'pending', run_at = now(), attempts = attempts + 1, last_error = 'worker lost; requeued',
+    locked_at = NULL, locked_by = NULL, updated_at = now()
+WHERE status = 'running' AND COALESCE(locked_at, updated_at) < now() - $1::interval`,
+			fmt.Sprintf("%d seconds", int(StaleRunningAfter.Seconds())))
+		return Result{Kind: kind, Fixed: int(failed.RowsAffected() + requeued.RowsAffected())}, err
+	case CommentDrift:
+		n, err := queueIssueSync(ctx, pool, `SELECT DISTINCT gi.project_id`+commentDriftSQL, commentPage)
+		return Result{Kind: kind, Queued: n}, err
+	}
+	return Result{Kind: kind}, fmt.Errorf("unknown check %q", kind)
+}
+
+func reconcileAssignments(ctx context.Context, pool *pgxpool.Pool) (Result, error) {
+	res := Result{Kind: AssignmentDrift}
+	rows, err := pool.Query(ctx, `
+UPDATE issue_applications SET status = 'unassigned', updated_at = now()
+WHERE id IN (
+  SELECT ia.id`+assignmentDriftSQL+`
+    AND gi.last_seen_at > COALESCE(ia.decided_at, ia.updated_at)
+)
+RETURNING project_id, issue_number`)
+	if err != nil {
+		return res, err
+	}
+	type issueKey struct {
+		projectID uuid.UUID
+		number    int
+	}
+	released := map[issueKey]bool{}
+	for rows.Next() {
+		var k issueKey
+		if err := rows.Scan(&k.projectID, &k.number); err != nil {
+			rows.Close()
+			return res, err
+		}
+		res.Fixed++
+		released[k] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return res, err
+	}
+	for k := range released {
+		var stillAssigned bool
+		_ = pool.QueryRow(ctx, `
+SELECT EXISTS (SELECT 1 FROM issue_applications WHERE project_id = $1 AND issue_number = $2 AND status = 'assigned')
+`, k.projectID, k.number).Scan(&stillAssigned)
+		if stillAssigned {
+			continue
+		}
+		if err := rewards.Return(ctx, pool, k.projectID, k.number, "unassigned on GitHub"); err != nil {
+			slog.Error("data quality: failed to return issue reward", "project_id", k.projectID, "issue_number", k.number, "error", err)
+		}
+	}
+
+	res.Queued, err = queueIssueSync(ctx, pool, `SELECT DISTINCT ia.project_id`+assignmentDriftSQL)
+	return res, err
+}
+
+// notifyMissingInstallations asks the owners of verified projects without an installation to install the
+// GitHub App, at most once a week per project.
+func notifyMissingInstallations(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	rows, err := pool.Query(ctx, `
+SELECT p.owner_user_id, p.id, p.github_full_name`+missingInstallationSQL+`
+  AND NOT EXISTS (
+    SELECT 1 FROM notifications n
+    WHERE n.user_id = p.owner_user_id AND n.kind = 'github_app_install_needed'
+      AND n.payload->>'project_id' = p.id::text AND n.created_at > now() - interval '7 days'
+  )`)
+	if err != nil {
+		return 0, err
+	}
+	type missing struct {
+		ownerID, projectID uuid.UUID
+		repo               string
+	}
+	var list []missing
+	for rows.Next() {
+		var m missing
+		if err := rows.Scan(&m.ownerID, &m.projectID, &m.repo); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	notified := 0
+	for _, m := range list {
+		if err := notify.Store(ctx, pool, m.ownerID, "github_app_install_needed", map[string]any{
+			"project_id": m.projectID, "repo": m.repo,
+		}); err == nil {
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// queueIssueSync queues an issue sync for each project the query returns, unless one is already waiting.
+func queueIssueSync(ctx context.Context, pool *pgxpool.Pool, projectsSQL string, args ...any) (int, error) {
+	rows, err := pool.Query(ctx, projectsSQL, args...)
+	if err != nil {
+		return 0, err
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	ct, err := pool.Exec(ctx, `
+INSERT INTO sync_jobs (project_id, job_type, status, run_at)
+SELECT u.project_id, 'sync_issues', 'pending', now() FROM unnest($1::uuid[]) AS u(project_id)
+WHERE NOT EXISTS (
+  SELECT 1 FROM sync_jobs j WHERE j.project_id = u.project_id AND j.job_type = 'sync_issues' AND j.status = 'pending'
+)`, ids)
+	return int(ct.RowsAffected()), err
+}
@@ -0,0 +1,17 @@
+package dataquality
+
+import "testing"
+
+func TestParseKind(t *testing.T) {
+	for _, k := range Kinds {
+		got, ok := ParseKind(string(k))
+		if !ok || got != k {
+			t.Errorf("ParseKind(%q) = %q, %v", k, got, ok)
+		}
+	}
+	for _, s := range []string{"", "Assignment_Drift", "drift"} {
+		if _, ok := ParseKind(s); ok {
+			t.Errorf("ParseKind(%q) accepted", s)
+		}
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
 )
 
 type DB struct {
@@ -14,6 +18,16 @@ type DB struct {
 }
 
 func Connect(ctx context.Context, dbURL string) (*DB, error) {
+	return connect(ctx, dbURL, nil)
+}
+
+// ConnectRotating is Connect for credentials that rotate (DB_URL from a secrets manager): every new
+// pool connection takes its user and password from the current value of dbURL().
+func ConnectRotating(ctx context.Context, dbURL func() string) (*DB, error) {
+	return connect(ctx, dbURL(), dbURL)
+}
+
+func connect(ctx context.Context, dbURL string, source func() string) (*DB, error) {
 	if dbURL == "" {
 		return nil, fmt.Errorf("DB_URL is required")
 	}
@@ -43,6 +57,19 @@ func Connect(ctx context.Context, dbURL string) (*DB, error) {
 	cfg.MaxConnLifetime = 30 * time.Minute
 	cfg.MaxConnIdleTime = 5 * time.Minute
 	cfg.HealthCheckPeriod = 30 * time.Second
+	if source != nil {
+		cfg.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+			cur, err := pgx.ParseConfig(source())
+			if err != nil {
+				return nil // keep the last good credentials
+			}
+			cc.User = cur.User
+			cc.Password = cur.Password
+			return nil
+		}
+	}
+
+	scopeToTenant(cfg)
 
 	slog.Info("creating database connection pool",
 		"max_conns", cfg.MaxConns,
@@ -72,6 +99,31 @@ func Connect(ctx context.Context, dbURL string) (*DB, error) {
 	return &DB{Pool: pool}, nil
 }
 
+// scopeToTenant sets app.tenant_id on each connection as it is acquired, from the tenant of the
+// acquiring context ("" when it has none), so row level security scopes every query a request makes.
+// Connections remember their last value to skip the round trip when it doesn't change.
+func scopeToTenant(cfg *pgxpool.Config) {
+	var current sync.Map // *pgx.Conn -> tenant id
+	cfg.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		want := tenant.IDFromContext(ctx)
+		have, _ := current.Load(conn)
+		if have == nil {
+			have = ""
+		}
+		if have == want {
+			return true, nil
+		}
+		if _, err := conn.Exec(ctx, `SELECT set_config('app.tenant_id', $1, false)`, want); err != nil {
+			return false, fmt.Errorf("set tenant: %w", err)
+		}
+		current.Store(conn, want)
+		return true, nil
+	}
+	cfg.BeforeClose = func(conn *pgx.Conn) {
+		current.Delete(conn)
+	}
+}
+
 // maskDBURL masks the password in a database URL for logging
 func maskDBURL(dbURL string) string {
 	// Simple masking: replace password with ***
@@ -0,0 +1,205 @@
+// Package digests builds the weekly report for each ecosystem (new projects, top contributors,
+// completed issues, how long applications have been waiting, and maintainer response times from
+// project_response_metrics) and sends it to the ecosystem's report subscribers, by email and as an
+// in-app notification.
+package digests
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Digest is one ecosystem's report for [PeriodStart, PeriodEnd).
+type Digest struct {
+	EcosystemID   uuid.UUID `json:"ecosystem_id"`
+	EcosystemName string    `json:"ecosystem_name"`
+	PeriodStart   time.Time `json:"period_start"`
+	PeriodEnd     time.Time `json:"period_end"`
+
+	NewProjects     []Project     `json:"new_projects"`
+	TopContributors []Contributor `json:"top_contributors"`
+	CompletedIssues int           `json:"completed_issues"`
+	RecentlyClosed  []Issue       `json:"recently_closed"`
+	Pending         PendingAging  `json:"pending_applications"`
+
+	// MedianDecisionHours is the median of the projects' application decision times (nil without data).
+	MedianDecisionHours *float64 `json:"median_decision_hours"`
+}
+
+type Project struct {
+	FullName string `json:"github_full_name"`
+	Language string `json:"language,omitempty"`
+}
+
+type Contributor struct {
+	Login     string `json:"login"`
+	MergedPRs int    `json:"merged_prs"`
+}
+
+type Issue struct {
+	Project string `json:"project"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+}
+
+// PendingAging buckets applications still awaiting a decision by how long they have waited.
+type PendingAging struct {
+	Total      int              `json:"total"`
+	Under3Days int              `json:"under_3_days"`
+	Days3To7   int              `json:"days_3_to_7"`
+	Over7Days  int              `json:"over_7_days"`
+	Oldest     []PendingOldItem `json:"oldest"`
+}
+
+type PendingOldItem struct {
+	Project     string `json:"project"`
+	IssueNumber int    `json:"issue_number"`
+	Login       string `json:"github_login"`
+	WaitingDays int    `json:"waiting_days"`
+}
+
+// Empty reports whether nothing happened and nothing is waiting, so the report can be skipped.
+func (d Digest) Empty() bool {
+	return len(d.NewProjects) == 0 && len(d.TopContributors) == 0 && d.CompletedIssues == 0 && d.Pending.Total == 0
+}
+
+// Week returns the last full week (Monday to Monday, UTC) before now.
+func Week(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	end := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	return end.AddDate(0, 0, -7), end
+}
+
+// Build aggregates the report for ecosystemID over [start, end). Pending application ages are
+// measured at end.
+func Build(ctx context.Context, pool *pgxpool.Pool, ecosystemID uuid.UUID, start, end time.Time) (Digest, error) {
+	d := Digest{EcosystemID: ecosystemID, PeriodStart: start, PeriodEnd: end}
+	if err := pool.QueryRow(ctx, `SELECT name FROM ecosystems WHERE id = $1`, ecosystemID).Scan(&d.EcosystemName); err != nil {
+		return d, fmt.Errorf("ecosystem: %w", err)
+	}
+
+	rows, err := pool.Query(ctx, `
+SELECT github_full_name, COALESCE(language, '')
+FROM projects
+WHERE ecosystem_id = $1 AND deleted_at IS NULL AND created_at >= $2 AND created_at < $3
+ORDER BY created_at
+LIMIT 20
+`, ecosystemID, start, end)
+	if err != nil {
+		return d, fmt.Errorf("new projects: %w", err)
+	}
+	for rows.Next() {
+		var p Project
+		if err := rows.Scan(&p.FullName, &p.Language); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.NewProjects = append(d.NewProjects, p)
+	}
+	rows.Close()
+
+	rows, err = pool.Query(ctx, `
+SELECT pr.author_login, COUNT(*)
+FROM github_pull_requests pr
+JOIN projects p ON p.id = pr.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL
+  AND pr.merged = true AND pr.merged_at_github >= $2 AND pr.merged_at_github < $3
+  AND pr.author_login IS NOT NULL AND pr.author_login <> '' AND pr.author_login NOT LIKE '%[bot]'
+GROUP BY pr.author_login
+ORDER BY COUNT(*) DESC, pr.author_login
+LIMIT 5
+`, ecosystemID, start, end)
+	if err != nil {
+		return d, fmt.Errorf("top contributors: %w", err)
+	}
+	for rows.Next() {
+		var c Contributor
+		if err := rows.Scan(&c.Login, &c.MergedPRs); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.TopContributors = append(d.TopContributors, c)
+	}
+	rows.Close()
+
+	if err := pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL
+  AND gi.state = 'closed' AND gi.closed_at_github >= $2 AND gi.closed_at_github < $3
+`, ecosystemID, start, end).Scan(&d.CompletedIssues); err != nil {
+		return d, fmt.Errorf("completed issues: %w", err)
+	}
+	rows, err = pool.Query(ctx, `
+SELECT p.github_full_name, gi.number, COALESCE(gi.title, ''), COALESCE(gi.url, '')
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL
+  AND gi.state = 'closed' AND gi.closed_at_github >= $2 AND gi.closed_at_github < $3
+ORDER BY gi.closed_at_github DESC
+LIMIT 10
+`, ecosystemID, start, end)
+	if err != nil {
+		return d, fmt.Errorf("closed issues: %w", err)
+	}
+	for rows.Next() {
+		var i Issue
+		if err := rows.Scan(&i.Project, &i.Number, &i.Title, &i.URL); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.RecentlyClosed = append(d.RecentlyClosed, i)
+	}
+	rows.Close()
+
+	if err := pool.QueryRow(ctx, `
+SELECT COUNT(*),
+       COUNT(*) FILTER (WHERE a.created_at > $2 - interval '3 days'),
+       COUNT(*) FILTER (WHERE a.created_at <= $2 - interval '3 days' AND a.created_at > $2 - interval '7 days'),
+       COUNT(*) FILTER (WHERE a.created_at <= $2 - interval '7 days')
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND a.status = 'pending' AND a.created_at < $2
+`, ecosystemID, end).Scan(&d.Pending.Total, &d.Pending.Under3Days, &d.Pending.Days3To7, &d.Pending.Over7Days); err != nil {
+		return d, fmt.Errorf("pending applications: %w", err)
+	}
+	rows, err = pool.Query(ctx, `
+SELECT p.github_full_name, a.issue_number,
+       CASE WHEN p.anonymous_applications THEN 'an anonymous applicant' ELSE a.github_login END,
+       EXTRACT(DAY FROM $2 - a.created_at)::int
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND a.status = 'pending' AND a.created_at < $2
+ORDER BY a.created_at
+LIMIT 5
+`, ecosystemID, end)
+	if err != nil {
+		return d, fmt.Errorf("oldest applications: %w", err)
+	}
+	for rows.Next() {
+		var o PendingOldItem
+		if err := rows.Scan(&o.Project, &o.IssueNumber, &o.Login, &o.WaitingDays); err != nil {
+			rows.Close()
+			return d, err
+		}
+		d.Pending.Oldest = append(d.Pending.Oldest, o)
+	}
+	rows.Close()
+
+	if err := pool.QueryRow(ctx, `
+SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY m.median_application_decision_seconds) / 3600.0
+FROM project_response_metrics m
+JOIN projects p ON p.id = m.project_id
+WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND m.median_application_decision_seconds IS NOT NULL
+`, ecosystemID).Scan(&d.MedianDecisionHours); err != nil {
+		return d, fmt.Errorf("response metrics: %w", err)
+	}
+	return d, nil
+}
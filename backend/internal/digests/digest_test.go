@@ -0,0 +1,63 @@
+package digests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWeek(t *testing.T) {
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{
+		monday,
+		monday.Add(5 * time.Hour),
+		time.Date(2026, 3, 15, 23, 59, 0, 0, time.UTC), // Sunday
+	} {
+		start, end := Week(now)
+		if !end.Equal(monday) || !start.Equal(monday.AddDate(0, 0, -7)) {
+			t.Errorf("Week(%s) = %s, %s", now, start, end)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	h := 30.0
+	d := Digest{
+		EcosystemName:       "Stellar",
+		PeriodStart:         time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:           time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		NewProjects:         []Project{{FullName: "acme/widget", Language: "Go"}},
+		TopContributors:     []Contributor{{Login: "octo", MergedPRs: 3}},
+		CompletedIssues:     1,
+		Pending:             PendingAging{Total: 2, Over7Days: 2},
+		MedianDecisionHours: &h,
+	}
+	subject, text, html, err := Render(d, "https://app.example/dashboard", "https://api.example/reports/unsubscribe?s=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Stellar weekly report: 1 new project, 1 issue completed, 2 applications waiting" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"Mar 2 to Mar 8, 2026", "acme/widget (Go)", "octo: 3", "30 hours", "over 7 days: 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q", want)
+		}
+	}
+	if !strings.Contains(html, `href="https://api.example/reports/unsubscribe?s=x"`) {
+		t.Error("html missing unsubscribe link")
+	}
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	id := uuid.New()
+	tok := UnsubscribeToken("secret", id)
+	if !ValidUnsubscribeToken("secret", id, tok) {
+		t.Fatal("valid token rejected")
+	}
+	if ValidUnsubscribeToken("secret", uuid.New(), tok) || ValidUnsubscribeToken("other", id, tok) || ValidUnsubscribeToken("", id, UnsubscribeToken("", id)) {
+		t.Fatal("invalid token accepted")
+	}
+}
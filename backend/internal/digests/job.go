@@ -0,0 +1,201 @@
+package digests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/mailer"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// Job sends each ecosystem's report for the previous week once, early in the week. The
+// (ecosystem, week) row in ecosystem_digests is claimed before sending, so with several instances
+// running only one sends it.
+type Job struct {
+	cfg    config.Config
+	pool   *pgxpool.Pool
+	mailer mailer.Mailer
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool, m mailer.Mailer) *Job {
+	return &Job{cfg: cfg, pool: pool, mailer: m}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(time.Hour)
+	defer t.Stop()
+	for {
+		if err := j.SendDue(ctx, time.Now()); err != nil {
+			slog.Error("ecosystem reports failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// SendDue sends last week's report for every ecosystem with subscribers that hasn't had it yet.
+func (j *Job) SendDue(ctx context.Context, now time.Time) error {
+	start, end := Week(now)
+	rows, err := j.pool.Query(ctx, `
+SELECT e.id
+FROM ecosystems e
+WHERE e.status = 'active'
+  AND EXISTS (SELECT 1 FROM ecosystem_report_subscribers s WHERE s.ecosystem_id = e.id)
+  AND NOT EXISTS (SELECT 1 FROM ecosystem_digests d WHERE d.ecosystem_id = e.id AND d.period_start = $1)
+`, start)
+	if err != nil {
+		return err
+	}
+	var due []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, ecoID := range due {
+		if err := j.send(ctx, ecoID, start, end); err != nil {
+			slog.Error("ecosystem report failed", "ecosystem_id", ecoID, "error", err)
+		}
+	}
+	return nil
+}
+
+type subscriber struct {
+	id     uuid.UUID
+	userID *uuid.UUID
+	email  *string
+}
+
+func (j *Job) send(ctx context.Context, ecoID uuid.UUID, start, end time.Time) error {
+	d, err := Build(ctx, j.pool, ecoID, start, end)
+	if err != nil {
+		return err
+	}
+	content, _ := json.Marshal(d)
+
+	var digestID uuid.UUID
+	err = j.pool.QueryRow(ctx, `
+INSERT INTO ecosystem_digests (ecosystem_id, period_start, period_end, content, skipped)
+VALUES ($1, $2, $3, $4::jsonb, $5)
+ON CONFLICT (ecosystem_id, period_start) DO NOTHING
+RETURNING id
+`, ecoID, start, end, content, d.Empty()).Scan(&digestID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // another instance has it
+	}
+	if err != nil {
+		return err
+	}
+	if d.Empty() {
+		return nil
+	}
+
+	var frontendURL string
+	_ = j.pool.QueryRow(ctx, `
+SELECT COALESCE(t.frontend_url, '') FROM ecosystems e LEFT JOIN tenants t ON t.id = e.tenant_id WHERE e.id = $1
+`, ecoID).Scan(&frontendURL)
+	if frontendURL == "" {
+		frontendURL = j.cfg.FrontendBaseURL
+	}
+	dashboardURL := ""
+	if frontendURL != "" {
+		dashboardURL = strings.TrimSuffix(frontendURL, "/") + "/dashboard"
+	}
+
+	rows, err := j.pool.Query(ctx, `
+SELECT id, user_id, email FROM ecosystem_report_subscribers WHERE ecosystem_id = $1
+`, ecoID)
+	if err != nil {
+		return err
+	}
+	var subs []subscriber
+	for rows.Next() {
+		var s subscriber
+		if err := rows.Scan(&s.id, &s.userID, &s.email); err != nil {
+			rows.Close()
+			return err
+		}
+		subs = append(subs, s)
+	}
+	rows.Close()
+
+	sent := 0
+	for _, s := range subs {
+		if s.userID != nil {
+			_ = notify.Store(ctx, j.pool, *s.userID, "ecosystem_report", map[string]any{
+				"digest_id":      digestID,
+				"ecosystem_id":   ecoID,
+				"ecosystem_name": d.EcosystemName,
+				"period_start":   start,
+			})
+		}
+		if s.email == nil || *s.email == "" {
+			continue
+		}
+		unsubscribe := UnsubscribeURL(j.cfg.PublicBaseURL, j.cfg.JWTSecret, s.id)
+		subject, text, html, err := Render(d, dashboardURL, unsubscribe)
+		if err != nil {
+			return err
+		}
+		err = j.mailer.Send(ctx, mailer.Message{
+			To:      []string{*s.email},
+			Subject: subject,
+			Text:    text,
+			HTML:    html,
+			Headers: map[string]string{"List-Unsubscribe": "<" + unsubscribe + ">"},
+		})
+		if err != nil {
+			slog.Error("failed to email ecosystem report", "ecosystem_id", ecoID, "subscriber_id", s.id, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	_, err = j.pool.Exec(ctx, `UPDATE ecosystem_digests SET sent_at = now(), emails_sent = $2 WHERE id = $1`, digestID, sent)
+	slog.Info("ecosystem report sent", "ecosystem_id", ecoID, "period_start", start, "subscribers", len(subs), "emails", sent)
+	return err
+}
+
+// UnsubscribeToken signs a subscription id for the unsubscribe link in report emails.
+func UnsubscribeToken(secret string, subscriptionID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("report-unsubscribe:" + subscriptionID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func ValidUnsubscribeToken(secret string, subscriptionID uuid.UUID, token string) bool {
+	return secret != "" && hmac.Equal([]byte(token), []byte(UnsubscribeToken(secret, subscriptionID)))
+}
+
+func UnsubscribeURL(publicBaseURL, secret string, subscriptionID uuid.UUID) string {
+	q := url.Values{"s": {subscriptionID.String()}, "token": {UnsubscribeToken(secret, subscriptionID)}}
+	return strings.TrimSuffix(publicBaseURL, "/") + "/reports/unsubscribe?" + q.Encode()
+}
@@ -0,0 +1,114 @@
+package digests
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+type view struct {
+	Digest
+	Period         string
+	DashboardURL   string
+	UnsubscribeURL string
+}
+
+var funcs = map[string]any{
+	"hours": func(h *float64) string {
+		if h == nil {
+			return "no data yet"
+		}
+		if *h < 48 {
+			return fmt.Sprintf("%.0f hours", *h)
+		}
+		return fmt.Sprintf("%.1f days", *h/24)
+	},
+}
+
+const textTmpl = `{{.EcosystemName}} weekly report, {{.Period}}
+
+NEW PROJECTS ({{len .NewProjects}})
+{{range .NewProjects}}- {{.FullName}}{{if .Language}} ({{.Language}}){{end}}
+{{else}}None this week.
+{{end}}
+TOP CONTRIBUTORS (merged PRs)
+{{range .TopContributors}}- {{.Login}}: {{.MergedPRs}}
+{{else}}No merged PRs this week.
+{{end}}
+COMPLETED ISSUES: {{.CompletedIssues}}
+{{range .RecentlyClosed}}- {{.Project}}#{{.Number}} {{.Title}}
+{{end}}
+PENDING APPLICATIONS: {{.Pending.Total}}
+  under 3 days: {{.Pending.Under3Days}}, 3-7 days: {{.Pending.Days3To7}}, over 7 days: {{.Pending.Over7Days}}
+{{range .Pending.Oldest}}- {{.Project}}#{{.IssueNumber}} by {{.Login}}, waiting {{.WaitingDays}} days
+{{end}}
+Median time to decide on an application: {{hours .MedianDecisionHours}}
+{{if .DashboardURL}}
+Dashboard: {{.DashboardURL}}
+{{end}}
+--
+You receive this because you subscribed to {{.EcosystemName}} reports.
+Unsubscribe: {{.UnsubscribeURL}}
+`
+
+const htmlTmpl = `<!doctype html>
+<html><body style="font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#1f2328;max-width:640px">
+<h2>{{.EcosystemName}} weekly report</h2>
+<p style="color:#59636e">{{.Period}}</p>
+
+<h3>New projects ({{len .NewProjects}})</h3>
+{{if .NewProjects}}<ul>{{range .NewProjects}}<li>{{.FullName}}{{if .Language}} <span style="color:#59636e">({{.Language}})</span>{{end}}</li>{{end}}</ul>{{else}}<p>None this week.</p>{{end}}
+
+<h3>Top contributors</h3>
+{{if .TopContributors}}<ol>{{range .TopContributors}}<li><a href="https://github.com/{{.Login}}">{{.Login}}</a>: {{.MergedPRs}} merged PR{{if ne .MergedPRs 1}}s{{end}}</li>{{end}}</ol>{{else}}<p>No merged PRs this week.</p>{{end}}
+
+<h3>Completed issues: {{.CompletedIssues}}</h3>
+{{if .RecentlyClosed}}<ul>{{range .RecentlyClosed}}<li><a href="{{.URL}}">{{.Project}}#{{.Number}}</a> {{.Title}}</li>{{end}}</ul>{{end}}
+
+<h3>Pending applications: {{.Pending.Total}}</h3>
+<table cellpadding="4"><tr><td>Under 3 days</td><td>{{.Pending.Under3Days}}</td></tr>
+<tr><td>3 to 7 days</td><td>{{.Pending.Days3To7}}</td></tr>
+<tr><td><strong>Over 7 days</strong></td><td><strong>{{.Pending.Over7Days}}</strong></td></tr></table>
+{{if .Pending.Oldest}}<p>Waiting longest:</p><ul>{{range .Pending.Oldest}}<li>{{.Project}}#{{.IssueNumber}} by {{.Login}}, {{.WaitingDays}} days</li>{{end}}</ul>{{end}}
+
+<p>Median time to decide on an application: {{hours .MedianDecisionHours}}</p>
+{{if .DashboardURL}}<p><a href="{{.DashboardURL}}">Open the dashboard</a></p>{{end}}
+<hr><p style="color:#59636e;font-size:12px">You receive this because you subscribed to {{.EcosystemName}} reports.
+<a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>
+</body></html>
+`
+
+var (
+	textT = texttemplate.Must(texttemplate.New("text").Funcs(funcs).Parse(textTmpl))
+	htmlT = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(htmlTmpl))
+)
+
+// Render returns the email subject, plain text and HTML for d.
+func Render(d Digest, dashboardURL, unsubscribeURL string) (string, string, string, error) {
+	v := view{
+		Digest:         d,
+		Period:         d.PeriodStart.Format("Jan 2") + " to " + d.PeriodEnd.AddDate(0, 0, -1).Format("Jan 2, 2006"),
+		DashboardURL:   dashboardURL,
+		UnsubscribeURL: unsubscribeURL,
+	}
+	var text, html bytes.Buffer
+	if err := textT.Execute(&text, v); err != nil {
+		return "", "", "", err
+	}
+	if err := htmlT.Execute(&html, v); err != nil {
+		return "", "", "", err
+	}
+	subject := fmt.Sprintf("%s weekly report: %d new project%s, %d issue%s completed, %d application%s waiting",
+		d.EcosystemName, len(d.NewProjects), plural(len(d.NewProjects)), d.CompletedIssues, plural(d.CompletedIssues),
+		d.Pending.Total, plural(d.Pending.Total))
+	return strings.TrimSpace(subject), text.String(), html.String(), nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
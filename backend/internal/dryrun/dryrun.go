@@ -0,0 +1,112 @@
+// Package dryrun suppresses GitHub mutations. With the environment-wide switch on (GITHUB_DRY_RUN),
+// or for a single request that asked for it, every mutating GitHub API call is logged and recorded in
+// github_dry_run_mutations and answered with a synthetic success instead of being sent. Reads, and
+// minting installation tokens, still go through.
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Scopes a mutation can be recorded under.
+const (
+	ScopeEnvironment = "environment"
+	ScopeRequest     = "request"
+)
+
+var (
+	enabled atomic.Bool
+	sinkMu  sync.RWMutex
+	sink    *pgxpool.Pool
+)
+
+// Configure sets the environment-wide switch and where intercepted mutations are recorded (pool may be nil).
+func Configure(on bool, pool *pgxpool.Pool) {
+	enabled.Store(on)
+	sinkMu.Lock()
+	sink = pool
+	sinkMu.Unlock()
+}
+
+// Enabled reports whether the environment-wide dry-run mode is on.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Mutation is one GitHub call that was intercepted.
+type Mutation struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	At     time.Time       `json:"at"`
+}
+
+// Collector marks a request as dry run and gathers the mutations it would have made.
+type Collector struct {
+	mu        sync.Mutex
+	mutations []Mutation
+}
+
+func (c *Collector) add(m Mutation) {
+	c.mu.Lock()
+	c.mutations = append(c.mutations, m)
+	c.mu.Unlock()
+}
+
+// Mutations returns what was intercepted so far.
+func (c *Collector) Mutations() []Mutation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Mutation{}, c.mutations...)
+}
+
+type ctxKey struct{}
+
+// Key is the context key a Collector is stored under. Fiber handlers set it with c.Locals(dryrun.Key, col):
+// Fiber's request context exposes locals as context values, so GitHub calls made with c.Context() see it.
+var Key = ctxKey{}
+
+// WithCollector returns a context whose GitHub mutations are intercepted into col.
+func WithCollector(ctx context.Context, col *Collector) context.Context {
+	return context.WithValue(ctx, Key, col)
+}
+
+// FromContext returns the request's Collector, if it asked for a dry run.
+func FromContext(ctx context.Context) *Collector {
+	if ctx == nil {
+		return nil
+	}
+	col, _ := ctx.Value(Key).(*Collector)
+	return col
+}
+
+// record logs and persists an intercepted mutation.
+func record(m Mutation, scope string) {
+	slog.Info("dry run: github mutation not performed", "method", m.Method, "url", m.URL, "scope", scope)
+
+	sinkMu.RLock()
+	pool := sink
+	sinkMu.RUnlock()
+	if pool == nil {
+		return
+	}
+	body := m.Body
+	if len(body) == 0 || !json.Valid(body) {
+		body = nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := pool.Exec(ctx, `
+INSERT INTO github_dry_run_mutations (method, url, body, scope)
+VALUES ($1, $2, $3, $4)
+`, m.Method, m.URL, body, scope); err != nil {
+		slog.Warn("dry run: failed to record mutation", "error", err)
+	}
+}
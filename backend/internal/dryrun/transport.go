@@ -0,0 +1,105 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// tokenPath is the one non-GET call that is safe (and needed) in dry run: minting an installation token.
+var tokenPath = regexp.MustCompile(`^/app/installations/[^/]+/access_tokens$`)
+
+// syntheticLogin is the author reported on intercepted comment creations.
+const syntheticLogin = "grainlify-dry-run[bot]"
+
+var syntheticID atomic.Int64
+
+func init() {
+	// Far above real GitHub IDs, so synthetic ones are easy to tell apart.
+	syntheticID.Store(9_000_000_000_000)
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so mutating calls are intercepted in dry run.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Mutating reports whether a request changes state on GitHub.
+func Mutating(method, path string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !(method == http.MethodPost && tokenPath.MatchString(path))
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	col := FromContext(req.Context())
+	if (col == nil && !Enabled()) || !Mutating(req.Method, req.URL.Path) {
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+	}
+	m := Mutation{Method: req.Method, URL: req.URL.String(), Body: body, At: time.Now().UTC()}
+	scope := ScopeEnvironment
+	if col != nil {
+		col.add(m)
+		scope = ScopeRequest
+	}
+	record(m, scope)
+	return syntheticResponse(req, body), nil
+}
+
+// syntheticResponse answers like GitHub would on success: 204 for deletes, otherwise the request
+// object echoed back with an id and timestamps (enough for comment and check run callers).
+func syntheticResponse(req *http.Request, body []byte) *http.Response {
+	resp := &http.Response{
+		Request:    req,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"X-Grainlify-Dry-Run": []string{"true"}},
+	}
+	if req.Method == http.MethodDelete {
+		resp.StatusCode = http.StatusNoContent
+		resp.Status = "204 No Content"
+		resp.Body = io.NopCloser(strings.NewReader(""))
+		return resp
+	}
+
+	out := map[string]any{}
+	_ = json.Unmarshal(body, &out)
+	now := time.Now().UTC().Format(time.RFC3339)
+	out["id"] = syntheticID.Add(1)
+	out["created_at"] = now
+	out["updated_at"] = now
+	out["user"] = map[string]string{"login": syntheticLogin}
+	b, _ := json.Marshal(out)
+
+	resp.StatusCode = http.StatusOK
+	if req.Method == http.MethodPost {
+		resp.StatusCode = http.StatusCreated
+	}
+	resp.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	resp.ContentLength = int64(len(b))
+	return resp
+}
@@ -0,0 +1,66 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type failTransport struct{ t *testing.T }
+
+func (f failTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Fatalf("request reached GitHub: %s %s", req.Method, req.URL)
+	return nil, nil
+}
+
+func TestMutating(t *testing.T) {
+	cases := []struct {
+		method, path string
+		want         bool
+	}{
+		{http.MethodGet, "/repos/o/r/issues/1", false},
+		{http.MethodPost, "/app/installations/42/access_tokens", false},
+		{http.MethodPost, "/repos/o/r/issues/1/assignees", true},
+		{http.MethodPatch, "/repos/o/r/issues/comments/7", true},
+		{http.MethodDelete, "/repos/o/r/issues/1/assignees", true},
+	}
+	for _, c := range cases {
+		if got := Mutating(c.method, c.path); got != c.want {
+			t.Errorf("Mutating(%s %s) = %v, want %v", c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestTransportInterceptsRequestScope(t *testing.T) {
+	col := &Collector{}
+	ctx := WithCollector(context.Background(), col)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/repos/o/r/issues/1/comments", strings.NewReader(`{"body":"hi"}`))
+
+	resp, err := Transport(failTransport{t}).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	var out struct {
+		ID   int64  `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID == 0 || out.Body != "hi" || out.User.Login != syntheticLogin {
+		t.Fatalf("unexpected synthetic comment: %s", b)
+	}
+	if m := col.Mutations(); len(m) != 1 || m[0].Method != http.MethodPost {
+		t.Fatalf("mutations = %+v", m)
+	}
+}
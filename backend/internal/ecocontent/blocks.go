@@ -0,0 +1,259 @@
+// Package ecocontent models an ecosystem page as an ordered list of content blocks. Admins edit a draft
+// version and publish it; every published version is kept so the page can be rolled back. Publishing also
+// writes the blocks back to the ecosystems.about/links/key_areas/technologies columns, which the public
+// endpoints and older clients read.
+package ecocontent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// Block types.
+const (
+	Markdown     = "markdown"     // Body is markdown; the first markdown block is the page's "about"
+	KeyAreas     = "key_areas"    // Items: [{"title","description"}]
+	Links        = "links"        // Items: [{"label","url"}]
+	Technologies = "technologies" // Items: ["..."]
+)
+
+const (
+	MaxBlocks    = 50
+	MaxBodyLen   = 20000
+	MaxItems     = 100
+	maxTitleLen  = 200
+	maxItemField = 1000
+)
+
+type Block struct {
+	Type  string          `json:"type"`
+	Title string          `json:"title,omitempty"`
+	Body  string          `json:"body,omitempty"`
+	Items json.RawMessage `json:"items,omitempty"`
+}
+
+type KeyArea struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type Link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Validate checks blocks and normalises them in place (trimmed text, canonical item JSON). The error is a
+// validate.Errors naming each failed field, e.g. "blocks[1].items[0].url".
+func Validate(blocks []Block) error {
+	var v validate.Validator
+	v.MaxItems("blocks", len(blocks), MaxBlocks)
+	for i := range blocks {
+		b := &blocks[i]
+		b.Type = strings.TrimSpace(b.Type)
+		b.Title = strings.TrimSpace(b.Title)
+		v.MaxLen(validate.Index("blocks", i, "title"), b.Title, maxTitleLen)
+		switch b.Type {
+		case Markdown:
+			b.Body = strings.TrimSpace(b.Body)
+			v.MaxLen(validate.Index("blocks", i, "body"), b.Body, MaxBodyLen)
+			b.Items = nil
+		case KeyAreas, Links, Technologies:
+			b.Items = ValidateItems(&v, validate.Index("blocks", i, "items"), b.Type, b.Items)
+			b.Body = ""
+		default:
+			v.Fail(validate.Index("blocks", i, "type"), validate.Invalid, fmt.Sprintf("unknown block type %q", b.Type))
+		}
+	}
+	return v.Err()
+}
+
+// ValidateItems checks the items of a list block type (also the shape of the matching legacy column) and
+// returns them normalised, recording failures under field. Items must match the schema exactly: key areas
+// are {"title","description"} objects, links {"label","url"} objects with an http(s) url, technologies
+// strings. Missing items are an empty list.
+func ValidateItems(v *validate.Validator, field, typ string, raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("[]")
+	}
+	var elems []json.RawMessage
+	if json.Unmarshal(raw, &elems) != nil {
+		v.Fail(field, validate.Invalid, "must be a list")
+		return nil
+	}
+	if !v.MaxItems(field, len(elems), MaxItems) {
+		return nil
+	}
+	var out json.RawMessage
+	switch typ {
+	case KeyAreas:
+		items := make([]KeyArea, 0, len(elems))
+		for j, e := range elems {
+			var it KeyArea
+			if decodeStrict(e, &it) != nil {
+				v.Fail(validate.Index(field, j, ""), validate.Invalid, `must be an object with "title" and "description"`)
+				continue
+			}
+			it.Title = strings.TrimSpace(it.Title)
+			it.Description = strings.TrimSpace(it.Description)
+			if v.Required(validate.Index(field, j, "title"), it.Title) {
+				v.MaxLen(validate.Index(field, j, "title"), it.Title, maxItemField)
+			}
+			v.MaxLen(validate.Index(field, j, "description"), it.Description, maxItemField)
+			items = append(items, it)
+		}
+		out, _ = encodeItems(items)
+	case Links:
+		items := make([]Link, 0, len(elems))
+		for j, e := range elems {
+			var it Link
+			if decodeStrict(e, &it) != nil {
+				v.Fail(validate.Index(field, j, ""), validate.Invalid, `must be an object with "label" and "url"`)
+				continue
+			}
+			it.Label = strings.TrimSpace(it.Label)
+			it.URL = strings.TrimSpace(it.URL)
+			if v.Required(validate.Index(field, j, "label"), it.Label) {
+				v.MaxLen(validate.Index(field, j, "label"), it.Label, maxItemField)
+			}
+			if v.Required(validate.Index(field, j, "url"), it.URL) {
+				v.URL(validate.Index(field, j, "url"), &it.URL)
+			}
+			items = append(items, it)
+		}
+		out, _ = encodeItems(items)
+	case Technologies:
+		items := make([]string, 0, len(elems))
+		for j, e := range elems {
+			var t string
+			if json.Unmarshal(e, &t) != nil {
+				v.Fail(validate.Index(field, j, ""), validate.Invalid, "must be a string")
+				continue
+			}
+			if t = strings.TrimSpace(t); t != "" {
+				v.MaxLen(validate.Index(field, j, ""), t, maxItemField)
+				items = append(items, t)
+			}
+		}
+		out, _ = encodeItems(items)
+	}
+	return out
+}
+
+// decodeStrict decodes one JSON object into dst, rejecting other JSON types and unknown keys.
+func decodeStrict(raw json.RawMessage, dst any) error {
+	if t := strings.TrimSpace(string(raw)); !strings.HasPrefix(t, "{") {
+		return fmt.Errorf("not an object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
+
+func encodeItems[T any](items []T) (json.RawMessage, error) {
+	if items == nil {
+		items = []T{}
+	}
+	return json.Marshal(items)
+}
+
+// Legacy is the flattened form stored in the ecosystems table.
+type Legacy struct {
+	About        string
+	Links        json.RawMessage
+	KeyAreas     json.RawMessage
+	Technologies json.RawMessage
+}
+
+// Flatten maps validated blocks onto the legacy columns: markdown bodies are joined into about, and list
+// blocks of the same type are concatenated.
+func Flatten(blocks []Block) Legacy {
+	var about []string
+	var links []Link
+	var keyAreas []KeyArea
+	var technologies []string
+	for _, b := range blocks {
+		switch b.Type {
+		case Markdown:
+			if b.Body != "" {
+				about = append(about, b.Body)
+			}
+		case Links:
+			var items []Link
+			_ = json.Unmarshal(b.Items, &items)
+			links = append(links, items...)
+		case KeyAreas:
+			var items []KeyArea
+			_ = json.Unmarshal(b.Items, &items)
+			keyAreas = append(keyAreas, items...)
+		case Technologies:
+			var items []string
+			_ = json.Unmarshal(b.Items, &items)
+			technologies = append(technologies, items...)
+		}
+	}
+	out := Legacy{About: strings.Join(about, "\n\n")}
+	out.Links, _ = encodeItems(links)
+	out.KeyAreas, _ = encodeItems(keyAreas)
+	out.Technologies, _ = encodeItems(technologies)
+	return out
+}
+
+// FromLegacy builds blocks from an ecosystem's legacy columns, for ecosystems that predate versioning.
+// Empty columns produce no block.
+func FromLegacy(l Legacy) []Block {
+	blocks := []Block{}
+	if about := strings.TrimSpace(l.About); about != "" {
+		blocks = append(blocks, Block{Type: Markdown, Title: "About", Body: about})
+	}
+	for _, c := range []struct {
+		typ, title string
+		raw        json.RawMessage
+	}{
+		{KeyAreas, "Key areas", l.KeyAreas},
+		{Technologies, "Technologies", l.Technologies},
+		{Links, "Links", l.Links},
+	} {
+		var items []json.RawMessage
+		if json.Unmarshal(c.raw, &items) == nil && len(items) > 0 {
+			blocks = append(blocks, Block{Type: c.typ, Title: c.title, Items: c.raw})
+		}
+	}
+	return blocks
+}
+
+// Localize returns blocks with a translation's text in place of the default: about replaces the body of the
+// first markdown block and keyAreas (a key areas list, as in the legacy column) the items of the key areas
+// blocks. An empty about or keyAreas keeps the default.
+func Localize(blocks []Block, about string, keyAreas json.RawMessage) []Block {
+	about = strings.TrimSpace(about)
+	var areas []json.RawMessage
+	if json.Unmarshal(keyAreas, &areas) != nil || len(areas) == 0 {
+		keyAreas = nil
+	}
+	out := make([]Block, 0, len(blocks)+1)
+	aboutDone, areasDone := about == "", keyAreas == nil
+	for _, b := range blocks {
+		switch {
+		case b.Type == Markdown && !aboutDone:
+			b.Body, aboutDone = about, true
+		case b.Type == KeyAreas && keyAreas != nil:
+			// The translation covers every key area, so it replaces the first block and drops the rest.
+			if areasDone {
+				continue
+			}
+			b.Items, areasDone = keyAreas, true
+		}
+		out = append(out, b)
+	}
+	if !aboutDone {
+		out = append([]Block{{Type: Markdown, Title: "About", Body: about}}, out...)
+	}
+	if !areasDone {
+		out = append(out, Block{Type: KeyAreas, Title: "Key areas", Items: keyAreas})
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package ecocontent
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+func TestValidate(t *testing.T) {
+	blocks := []Block{
+		{Type: " markdown ", Title: " Intro ", Body: "  Hello  ", Items: json.RawMessage(`[1]`)},
+		{Type: Links, Items: json.RawMessage(`[{"label":" Docs ","url":" https://Docs.Example.com:443 "}]`)},
+		{Type: Technologies, Body: "ignored", Items: json.RawMessage(`["Rust", " ", "Go "]`)},
+		{Type: KeyAreas},
+	}
+	if err := Validate(blocks); err != nil {
+		t.Fatal(err)
+	}
+	if b := blocks[0]; b.Type != Markdown || b.Title != "Intro" || b.Body != "Hello" || b.Items != nil {
+		t.Errorf("markdown = %+v", b)
+	}
+	if got := string(blocks[1].Items); got != `[{"label":"Docs","url":"https://docs.example.com"}]` {
+		t.Errorf("links = %s", got)
+	}
+	if got := string(blocks[2].Items); got != `["Rust","Go"]` || blocks[2].Body != "" {
+		t.Errorf("technologies = %s %q", got, blocks[2].Body)
+	}
+	if got := string(blocks[3].Items); got != `[]` {
+		t.Errorf("empty key areas = %s", got)
+	}
+
+	for name, bad := range map[string]Block{
+		"unknown type":  {Type: "video"},
+		"bad link url":  {Type: Links, Items: json.RawMessage(`[{"label":"x","url":"javascript:alert(1)"}]`)},
+		"no area title": {Type: KeyAreas, Items: json.RawMessage(`[{"description":"x"}]`)},
+		"items object":  {Type: Technologies, Items: json.RawMessage(`{"a":1}`)},
+		"link string":   {Type: Links, Items: json.RawMessage(`["https://example.com"]`)},
+		"unknown key":   {Type: KeyAreas, Items: json.RawMessage(`[{"title":"x","summary":"y"}]`)},
+		"tech number":   {Type: Technologies, Items: json.RawMessage(`["Go", 7]`)},
+	} {
+		if err := Validate([]Block{bad}); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	err := Validate([]Block{
+		{Type: Markdown},
+		{Type: Links, Items: json.RawMessage(`[{"label":"ok","url":"https://example.com"},{"label":"","url":"ftp://x"}]`)},
+	})
+	var errs validate.Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "blocks[1].items[1].label" || errs[1].Field != "blocks[1].items[1].url" {
+		t.Errorf("field errors = %v", err)
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	legacy := Legacy{
+		About:        "About us",
+		Links:        json.RawMessage(`[{"label":"Site","url":"https://example.com"}]`),
+		KeyAreas:     json.RawMessage(`[]`),
+		Technologies: json.RawMessage(`["Go"]`),
+	}
+	blocks := FromLegacy(legacy)
+	if len(blocks) != 3 {
+		t.Fatalf("FromLegacy = %+v", blocks)
+	}
+	if err := Validate(blocks); err != nil {
+		t.Fatal(err)
+	}
+	got := Flatten(blocks)
+	if got.About != "About us" || string(got.Links) != string(legacy.Links) ||
+		string(got.KeyAreas) != "[]" || string(got.Technologies) != `["Go"]` {
+		t.Errorf("Flatten = %+v", got)
+	}
+
+	merged := Flatten([]Block{
+		{Type: Markdown, Body: "One"},
+		{Type: Technologies, Items: json.RawMessage(`["Go"]`)},
+		{Type: Markdown, Body: "Two"},
+		{Type: Technologies, Items: json.RawMessage(`["Rust"]`)},
+	})
+	if merged.About != "One\n\nTwo" || string(merged.Technologies) != `["Go","Rust"]` {
+		t.Errorf("merged = %+v", merged)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	blocks := []Block{
+		{Type: Markdown, Title: "About", Body: "About us"},
+		{Type: KeyAreas, Items: json.RawMessage(`[{"title":"DeFi","description":""}]`)},
+		{Type: Markdown, Body: "More"},
+		{Type: KeyAreas, Items: json.RawMessage(`[{"title":"Tooling","description":""}]`)},
+	}
+	areas := json.RawMessage(`[{"title":"Finanzas","description":""},{"title":"Herramientas","description":""}]`)
+	got := Localize(blocks, "Sobre nosotros", areas)
+	if len(got) != 3 || got[0].Body != "Sobre nosotros" || got[0].Title != "About" ||
+		string(got[1].Items) != string(areas) || got[2].Body != "More" {
+		t.Errorf("Localize = %+v", got)
+	}
+	if blocks[0].Body != "About us" {
+		t.Error("Localize changed its input")
+	}
+
+	if got := Localize(blocks, " ", json.RawMessage(`[]`)); len(got) != len(blocks) || got[0].Body != "About us" {
+		t.Errorf("empty translation = %+v", got)
+	}
+	added := Localize([]Block{{Type: Links, Items: json.RawMessage(`[]`)}}, "Hola", areas)
+	if len(added) != 3 || added[0].Type != Markdown || added[0].Body != "Hola" || added[2].Type != KeyAreas {
+		t.Errorf("Localize without default blocks = %+v", added)
+	}
+}
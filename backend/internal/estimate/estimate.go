@@ -0,0 +1,346 @@
+// Package estimate suggests a complexity and point value for an issue from cheap heuristics: the issue's
+// labels, the size of its description, the code areas it mentions, and how many points similar,
+// already-completed issues of the same project carried and how long they took. The result is only a
+// suggestion; maintainers accept it (or not) from the dashboard.
+package estimate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Complexity levels, smallest first.
+var Levels = []string{"trivial", "small", "medium", "large", "epic"}
+
+// DefaultPoints is the point value of each level for projects without enough history to learn their own
+// scale from.
+var DefaultPoints = []int{1, 2, 3, 5, 8}
+
+// Issue is the issue being estimated.
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Sample is a completed issue of the same project. Hours is the time from assignment (or creation) to
+// close; zero when unknown.
+type Sample struct {
+	Labels []string
+	Points int
+	Hours  float64
+}
+
+// Signal explains one input to a suggestion.
+type Signal struct {
+	Kind  string `json:"kind"` // label|description|code_areas|history
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+type Suggestion struct {
+	Complexity string   `json:"complexity"`
+	Points     int      `json:"points"`
+	Confidence string   `json:"confidence"` // low|medium|high
+	Signals    []Signal `json:"signals"`
+	// SimilarIssues counts completed issues sharing a label with this one.
+	SimilarIssues int `json:"similar_issues"`
+	// ExpectedHours is the median completion time of similar issues, when at least two are known.
+	ExpectedHours *float64 `json:"expected_hours"`
+}
+
+// minScaleSamples is how many completed, pointed issues a project needs before its own point scale is used.
+const minScaleSamples = 5
+
+// Signal weights: history is the best predictor once there is enough of it.
+const (
+	wLabel       = 2.0
+	wDescription = 1.0
+	wCodeAreas   = 1.0
+	wHistory     = 3.0
+)
+
+var (
+	explicitSize   = regexp.MustCompile(`^(?:size|complexity|difficulty|effort)\s*[:/_-]?\s*(\w+)$`)
+	explicitPoints = regexp.MustCompile(`^(?:points?|sp|story\s*points?)\s*[:/_-]?\s*(\d+)$|^(\d+)\s*(?:points?|pts|sp)$`)
+	checklistItem  = regexp.MustCompile(`(?m)^\s*[-*]\s*\[[ xX]\]`)
+	codePath       = regexp.MustCompile(`(?:^|[\s(` + "`" + `'"])((?:[\w.-]+/)+[\w.-]+\.[A-Za-z]{1,10})\b`)
+)
+
+// sizeWords maps the values used by size/complexity/difficulty labels to levels.
+var sizeWords = map[string]int{
+	"xs": 0, "trivial": 0, "tiny": 0,
+	"s": 1, "small": 1, "easy": 1, "low": 1, "beginner": 1,
+	"m": 2, "medium": 2, "moderate": 2, "intermediate": 2, "normal": 2,
+	"l": 3, "large": 3, "hard": 3, "high": 3, "advanced": 3, "difficult": 3,
+	"xl": 4, "xxl": 4, "epic": 4, "huge": 4, "expert": 4,
+}
+
+// keywordLevels are common labels that hint at size without stating it.
+var keywordLevels = map[string]int{
+	"typo":             0,
+	"good first issue": 1, "good-first-issue": 1, "first-timers-only": 1, "beginner": 1, "easy": 1,
+	"documentation": 1, "docs": 1, "tests": 1, "testing": 1,
+	"bug": 2, "enhancement": 2, "improvement": 2, "ui": 2, "frontend": 2,
+	"feature": 3, "refactor": 3, "performance": 3, "security": 3, "breaking change": 3, "hard": 3,
+	"epic": 4, "research": 4, "architecture": 4, "complex": 4,
+}
+
+// Estimate suggests a complexity for issue given the project's completed issues.
+func Estimate(issue Issue, history []Sample) Suggestion {
+	scale := pointScale(history)
+	s := Suggestion{Signals: []Signal{}}
+
+	// An explicit size or points label is the maintainers' own estimate; report it as is.
+	if level, text, ok := explicitLevel(issue.Labels, scale); ok {
+		s.Signals = append(s.Signals, Signal{Kind: "label", Level: Levels[level], Text: text})
+		s.Complexity, s.Points, s.Confidence = Levels[level], scale[level], "high"
+		s.SimilarIssues, s.ExpectedHours = similar(issue.Labels, history)
+		return s
+	}
+
+	var sum, weight float64
+	add := func(kind string, level int, w float64, text string) {
+		s.Signals = append(s.Signals, Signal{Kind: kind, Level: Levels[level], Text: text})
+		sum += float64(level) * w
+		weight += w
+	}
+	if level, label, ok := keywordLevel(issue.Labels); ok {
+		add("label", level, wLabel, fmt.Sprintf("Labelled %q", label))
+	}
+	add("description", descriptionLevel(issue.Body), wDescription, describeBody(issue.Body))
+	if paths := codeAreas(issue.Title + "\n" + issue.Body); len(paths) > 0 {
+		add("code_areas", codeAreaLevel(paths), wCodeAreas, fmt.Sprintf("Mentions %d file%s in %d area%s",
+			len(paths), plural(len(paths)), len(topDirs(paths)), plural(len(topDirs(paths)))))
+	}
+
+	similarPoints := similarPoints(issue.Labels, history)
+	s.SimilarIssues, s.ExpectedHours = similar(issue.Labels, history)
+	if len(similarPoints) > 0 {
+		median := medianInt(similarPoints)
+		level := levelForPoints(median, scale)
+		w := wHistory
+		if len(similarPoints) < 3 {
+			w = wHistory / 2
+		}
+		add("history", level, w, fmt.Sprintf("%d similar completed issue%s carried a median of %d point%s",
+			len(similarPoints), plural(len(similarPoints)), median, plural(median)))
+	}
+
+	level := int(math.Round(sum / weight))
+	s.Complexity, s.Points = Levels[level], scale[level]
+	switch {
+	case len(similarPoints) >= 3 && len(s.Signals) >= 3:
+		s.Confidence = "high"
+	case len(s.Signals) >= 3 || len(similarPoints) > 0:
+		s.Confidence = "medium"
+	default:
+		s.Confidence = "low"
+	}
+	return s
+}
+
+// Classify places a completed issue on the complexity scale: by its points relative to the project's
+// scale when it has any, otherwise as Estimate would.
+func Classify(issue Issue, points int, history []Sample) string {
+	if points > 0 {
+		return Levels[levelForPoints(points, pointScale(history))]
+	}
+	return Estimate(issue, history).Complexity
+}
+
+// pointScale returns the points for each level. With enough history it follows the project's own
+// distribution (so projects awarding 10/50/100 get suggestions on that scale), otherwise DefaultPoints.
+func pointScale(history []Sample) []int {
+	var points []int
+	for _, h := range history {
+		if h.Points > 0 {
+			points = append(points, h.Points)
+		}
+	}
+	if len(points) < minScaleSamples {
+		return DefaultPoints
+	}
+	sort.Ints(points)
+	scale := make([]int, len(Levels))
+	for i := range scale {
+		// Levels sit at the 10th, 30th, 50th, 70th and 90th percentiles.
+		scale[i] = points[(len(points)-1)*(2*i+1)/(2*len(Levels))]
+		if i > 0 && scale[i] < scale[i-1] {
+			scale[i] = scale[i-1]
+		}
+	}
+	return scale
+}
+
+func levelForPoints(points int, scale []int) int {
+	best, bestDiff := 0, math.MaxInt
+	for i, p := range scale {
+		d := points - p
+		if d < 0 {
+			d = -d
+		}
+		if d < bestDiff {
+			best, bestDiff = i, d
+		}
+	}
+	return best
+}
+
+func explicitLevel(labels []string, scale []int) (int, string, bool) {
+	for _, l := range labels {
+		name := strings.ToLower(strings.TrimSpace(l))
+		if m := explicitPoints.FindStringSubmatch(name); m != nil {
+			digits := m[1] + m[2]
+			if n, err := strconv.Atoi(digits); err == nil && n > 0 {
+				return levelForPoints(n, scale), fmt.Sprintf("Labelled %q", l), true
+			}
+		}
+		if m := explicitSize.FindStringSubmatch(name); m != nil {
+			if level, ok := sizeWords[m[1]]; ok {
+				return level, fmt.Sprintf("Labelled %q", l), true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// keywordLevel returns the largest level hinted at by the labels.
+func keywordLevel(labels []string) (int, string, bool) {
+	level, label, found := 0, "", false
+	for _, l := range labels {
+		if lv, ok := keywordLevels[strings.ToLower(strings.TrimSpace(l))]; ok && (!found || lv > level) {
+			level, label, found = lv, l, true
+		}
+	}
+	return level, label, found
+}
+
+// descriptionLevel sizes the body by word count, counting each checklist item as a unit of work.
+func descriptionLevel(body string) int {
+	words := len(strings.Fields(body))
+	tasks := len(checklistItem.FindAllString(body, -1))
+	switch {
+	case words < 30 && tasks <= 1:
+		return 0
+	case words < 120 && tasks <= 3:
+		return 1
+	case words < 400 && tasks <= 6:
+		return 2
+	case words < 1000 && tasks <= 12:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func describeBody(body string) string {
+	words := len(strings.Fields(body))
+	if tasks := len(checklistItem.FindAllString(body, -1)); tasks > 0 {
+		return fmt.Sprintf("Description of %d word%s with %d checklist item%s", words, plural(words), tasks, plural(tasks))
+	}
+	if words == 0 {
+		return "No description"
+	}
+	return fmt.Sprintf("Description of %d word%s", words, plural(words))
+}
+
+// codeAreas returns the distinct file paths mentioned in text.
+func codeAreas(text string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, m := range codePath.FindAllStringSubmatch(text, -1) {
+		p := strings.TrimPrefix(m[1], "./")
+		if strings.Contains(p, "://") || strings.HasPrefix(p, "www.") || seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+func topDirs(paths []string) map[string]bool {
+	dirs := map[string]bool{}
+	for _, p := range paths {
+		dirs[strings.SplitN(p, "/", 2)[0]] = true
+	}
+	return dirs
+}
+
+func codeAreaLevel(paths []string) int {
+	files, dirs := len(paths), len(topDirs(paths))
+	switch {
+	case files == 1:
+		return 1
+	case files <= 3 && dirs == 1:
+		return 2
+	case files <= 6 && dirs <= 2:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// similarPoints returns the points of completed issues sharing at least one label with labels.
+func similarPoints(labels []string, history []Sample) []int {
+	var out []int
+	for _, h := range history {
+		if h.Points > 0 && sharesLabel(labels, h.Labels) {
+			out = append(out, h.Points)
+		}
+	}
+	return out
+}
+
+func similar(labels []string, history []Sample) (int, *float64) {
+	n := 0
+	var hours []float64
+	for _, h := range history {
+		if !sharesLabel(labels, h.Labels) {
+			continue
+		}
+		n++
+		if h.Hours > 0 {
+			hours = append(hours, h.Hours)
+		}
+	}
+	if len(hours) < 2 {
+		return n, nil
+	}
+	sort.Float64s(hours)
+	m := hours[len(hours)/2]
+	if len(hours)%2 == 0 {
+		m = (hours[len(hours)/2-1] + hours[len(hours)/2]) / 2
+	}
+	m = math.Round(m*10) / 10
+	return n, &m
+}
+
+func sharesLabel(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func medianInt(v []int) int {
+	s := append([]int(nil), v...)
+	sort.Ints(s)
+	return s[len(s)/2]
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateExplicitLabel(t *testing.T) {
+	for label, want := range map[string]string{
+		"size/XL":            "epic",
+		"Difficulty: Medium": "medium",
+		"complexity-low":     "small",
+		"3 points":           "medium",
+	} {
+		s := Estimate(Issue{Labels: []string{"bug", label}}, nil)
+		if s.Complexity != want || s.Confidence != "high" {
+			t.Errorf("%q: got %s (%s), want %s", label, s.Complexity, s.Confidence, want)
+		}
+	}
+}
+
+func TestEstimateHeuristics(t *testing.T) {
+	small := Estimate(Issue{Title: "Fix typo in README", Labels: []string{"good first issue"}}, nil)
+	if small.Complexity != "trivial" && small.Complexity != "small" {
+		t.Errorf("typo fix = %s", small.Complexity)
+	}
+	if small.Confidence != "low" {
+		t.Errorf("confidence without history = %s", small.Confidence)
+	}
+
+	body := strings.Repeat("word ", 500) + "\n- [ ] api/handlers/users.go\n- [ ] web/src/pages/Profile.tsx\n" +
+		"- [ ] db/migrations/0001.sql\n- [ ] docs/api.md\n"
+	big := Estimate(Issue{Body: body, Labels: []string{"feature"}}, nil)
+	if big.Complexity != "large" && big.Complexity != "epic" {
+		t.Errorf("large feature = %s (%+v)", big.Complexity, big.Signals)
+	}
+	var kinds []string
+	for _, s := range big.Signals {
+		kinds = append(kinds, s.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "label,description,code_areas" {
+		t.Errorf("signals = %s", got)
+	}
+}
+
+func TestEstimateHistory(t *testing.T) {
+	history := []Sample{
+		{Labels: []string{"bug"}, Points: 100, Hours: 10},
+		{Labels: []string{"bug"}, Points: 100, Hours: 20},
+		{Labels: []string{"bug"}, Points: 100, Hours: 30},
+		{Labels: []string{"feature"}, Points: 500},
+		{Labels: []string{"docs"}, Points: 10},
+		{Labels: []string{"docs"}, Points: 10},
+	}
+	s := Estimate(Issue{Body: "The parser crashes on empty input; see the stack trace below for details.", Labels: []string{"Bug"}}, history)
+	if s.SimilarIssues != 3 || s.ExpectedHours == nil || *s.ExpectedHours != 20 {
+		t.Fatalf("similar = %d, hours = %v", s.SimilarIssues, s.ExpectedHours)
+	}
+	if s.Confidence != "high" {
+		t.Errorf("confidence = %s", s.Confidence)
+	}
+	// The project's own scale is used once it has enough pointed issues.
+	if s.Points < 10 || s.Points > 100 {
+		t.Errorf("points = %d, want on the project's scale", s.Points)
+	}
+}
+
+func TestPointScale(t *testing.T) {
+	if got := pointScale([]Sample{{Points: 3}}); got[2] != 3 || got[4] != 8 {
+		t.Errorf("default scale = %v", got)
+	}
+	got := pointScale([]Sample{{Points: 10}, {Points: 20}, {Points: 30}, {Points: 40}, {Points: 50}})
+	for i := 1; i < len(got); i++ {
+		if got[i] < got[i-1] {
+			t.Fatalf("scale not monotonic: %v", got)
+		}
+	}
+	if got[0] != 10 || got[4] != 40 {
+		t.Errorf("scale = %v", got)
+	}
+}
+
+func TestCodeAreas(t *testing.T) {
+	paths := codeAreas("See `internal/api/api.go` and ./web/app.tsx, not https://example.com/a/b.html or foo.go")
+	if strings.Join(paths, ",") != "internal/api/api.go,web/app.tsx" {
+		t.Errorf("paths = %v", paths)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	history := []Sample{{Points: 10}, {Points: 20}, {Points: 30}, {Points: 40}, {Points: 50}}
+	if got := Classify(Issue{}, 40, history); got != "epic" {
+		t.Errorf("top of scale = %s", got)
+	}
+	if got := Classify(Issue{Labels: []string{"size/M"}}, 0, history); got != "medium" {
+		t.Errorf("unpointed = %s", got)
+	}
+}
@@ -0,0 +1,269 @@
+// Package exports produces CSV and JSON exports of applications, contributions and payouts for a
+// project, an ecosystem or the whole platform over a date range. Small exports are written straight
+// to the response; larger ones are queued in export_jobs and generated in the background by Job.
+package exports
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Datasets.
+const (
+	Applications  = "applications"
+	Contributions = "contributions"
+	Payouts       = "payouts"
+)
+
+// Formats.
+const (
+	CSV  = "csv"
+	JSON = "json"
+)
+
+const (
+	// SyncRowLimit is the largest export generated inline; anything bigger is queued.
+	SyncRowLimit = 5000
+	// MaxRows caps a single export; narrower ranges are needed beyond it.
+	MaxRows = 500000
+)
+
+// Request selects what to export. ProjectID and EcosystemID are optional filters (payouts are not
+// linked to projects, so they only support the date range).
+type Request struct {
+	Dataset     string
+	Format      string
+	ProjectID   *uuid.UUID
+	EcosystemID *uuid.UUID
+	From        time.Time
+	To          time.Time
+}
+
+type dataset struct {
+	columns []string
+	// query takes $1 project id, $2 ecosystem id, $3 from, $4 to; every column is already text or a
+	// timestamp so rows can be written without per-type handling.
+	query string
+}
+
+var datasets = map[string]dataset{
+	Applications: {
+		columns: []string{"id", "project", "ecosystem", "issue_number", "github_login", "source", "status", "created_at", "updated_at"},
+		query: `
+SELECT ia.id::text, p.github_full_name, COALESCE(e.slug, ''), ia.issue_number::text,
+       CASE WHEN p.anonymous_applications AND ia.status NOT IN ('assigned', 'unassigned') THEN '' ELSE ia.github_login END,
+       ia.source, ia.status,
+       ia.created_at, ia.updated_at
+FROM issue_applications ia
+JOIN projects p ON p.id = ia.project_id
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE ($1::uuid IS NULL OR p.id = $1)
+  AND ($2::uuid IS NULL OR p.ecosystem_id = $2)
+  AND ia.created_at >= $3 AND ia.created_at < $4
+ORDER BY ia.created_at
+`,
+	},
+	Contributions: {
+		columns: []string{"kind", "project", "ecosystem", "number", "title", "author_login", "state", "merged", "url", "created_at", "closed_at"},
+		query: `
+SELECT 'pull_request', p.github_full_name, COALESCE(e.slug, ''), pr.number::text, COALESCE(pr.title, ''), COALESCE(pr.author_login, ''),
+       COALESCE(pr.state, ''), COALESCE(pr.merged, false)::text, COALESCE(pr.url, ''), pr.created_at_github,
+       COALESCE(pr.merged_at_github, pr.closed_at_github)
+FROM github_pull_requests pr
+JOIN projects p ON p.id = pr.project_id AND p.deleted_at IS NULL
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE ($1::uuid IS NULL OR p.id = $1)
+  AND ($2::uuid IS NULL OR p.ecosystem_id = $2)
+  AND pr.created_at_github >= $3 AND pr.created_at_github < $4
+UNION ALL
+SELECT 'issue', p.github_full_name, COALESCE(e.slug, ''), gi.number::text, COALESCE(gi.title, ''), COALESCE(gi.author_login, ''),
+       COALESCE(gi.state, ''), '', COALESCE(gi.url, ''), gi.created_at_github, gi.closed_at_github
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id AND p.deleted_at IS NULL
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE ($1::uuid IS NULL OR p.id = $1)
+  AND ($2::uuid IS NULL OR p.ecosystem_id = $2)
+  AND gi.created_at_github >= $3 AND gi.created_at_github < $4
+ORDER BY 10
+`,
+	},
+	Payouts: {
+		columns: []string{"tx_hash", "contract_id", "topic", "bounty_id", "program_id", "amount", "recipient", "github_login", "paid_at"},
+		query: `
+SELECT oe.tx_hash, oe.contract_id, oe.topic, COALESCE(oe.bounty_id::text, ''), COALESCE(oe.program_id, ''),
+       COALESCE(oe.amount::text, ''), COALESCE(oe.recipient, ''), COALESCE(ga.login, ''), to_timestamp(oe.event_timestamp)
+FROM onchain_events oe
+LEFT JOIN wallets w ON w.address = oe.recipient
+LEFT JOIN github_accounts ga ON ga.user_id = w.user_id
+WHERE oe.topic IN ('f_rel', 'Payout', 'BatchPay')
+  AND $1::uuid IS NULL AND $2::uuid IS NULL -- on-chain payouts can't be attributed to a project
+  AND to_timestamp(oe.event_timestamp) >= $3 AND to_timestamp(oe.event_timestamp) < $4
+ORDER BY oe.event_timestamp
+`,
+	},
+}
+
+// Valid reports whether dataset and format are supported.
+func Valid(datasetName, format string) bool {
+	_, ok := datasets[datasetName]
+	return ok && (format == CSV || format == JSON)
+}
+
+// Count returns how many rows r would export.
+func Count(ctx context.Context, pool *pgxpool.Pool, r Request) (int, error) {
+	ds, ok := datasets[r.Dataset]
+	if !ok {
+		return 0, fmt.Errorf("unknown dataset %q", r.Dataset)
+	}
+	var n int
+	err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM (`+ds.query+`) q`, r.ProjectID, r.EcosystemID, r.From, r.To).Scan(&n)
+	return n, err
+}
+
+// Write streams the export to w and returns the number of rows written.
+func Write(ctx context.Context, pool *pgxpool.Pool, r Request, w io.Writer) (int, error) {
+	ds, ok := datasets[r.Dataset]
+	if !ok {
+		return 0, fmt.Errorf("unknown dataset %q", r.Dataset)
+	}
+	rows, err := pool.Query(ctx, ds.query, r.ProjectID, r.EcosystemID, r.From, r.To)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	out := newWriter(r.Format, w, ds.columns)
+	n := 0
+	for rows.Next() {
+		if n >= MaxRows {
+			return n, fmt.Errorf("export exceeds %d rows; narrow the date range", MaxRows)
+		}
+		vals, err := rows.Values()
+		if err != nil {
+			return n, err
+		}
+		if err := out.row(vals); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	return n, out.close()
+}
+
+// ContentType and Filename describe the file for r.
+func ContentType(format string) string {
+	if format == JSON {
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func Filename(r Request) string {
+	return fmt.Sprintf("grainlify-%s-%s-%s.%s", r.Dataset, r.From.Format("20060102"), r.To.Format("20060102"), r.Format)
+}
+
+type writer struct {
+	format  string
+	w       io.Writer
+	csv     *csv.Writer
+	columns []string
+	n       int
+}
+
+func newWriter(format string, w io.Writer, columns []string) *writer {
+	out := &writer{format: format, w: w, columns: columns}
+	if format == CSV {
+		out.csv = csv.NewWriter(w)
+		_ = out.csv.Write(columns)
+	}
+	return out
+}
+
+func (o *writer) row(vals []any) error {
+	if o.format == CSV {
+		rec := make([]string, len(vals))
+		for i, v := range vals {
+			rec[i] = cell(v)
+		}
+		return o.csv.Write(rec)
+	}
+
+	obj := make(map[string]any, len(vals))
+	for i, v := range vals {
+		if i < len(o.columns) {
+			obj[o.columns[i]] = v
+		}
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if o.n == 0 {
+		sep = "[\n"
+	}
+	o.n++
+	_, err = io.WriteString(o.w, sep+string(b))
+	return err
+}
+
+func (o *writer) close() error {
+	if o.format == CSV {
+		o.csv.Flush()
+		return o.csv.Error()
+	}
+	end := "\n]\n"
+	if o.n == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(o.w, end)
+	return err
+}
+
+func cell(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		// Keep spreadsheet apps from evaluating user-controlled text (issue titles) as formulas.
+		if t != "" && strings.ContainsRune("=+-@\t\r", rune(t[0])) {
+			return "'" + t
+		}
+		return t
+	case time.Time:
+		return t.UTC().Format(time.RFC3339)
+	case int32:
+		return strconv.FormatInt(int64(t), 10)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// DownloadToken signs a queued export's id, so its download link works without a session
+// (e.g. opened from an email) but can't be guessed.
+func DownloadToken(secret string, id uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("export:" + id.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func ValidDownloadToken(secret string, id uuid.UUID, token string) bool {
+	return secret != "" && hmac.Equal([]byte(token), []byte(DownloadToken(secret, id)))
+}
@@ -0,0 +1,65 @@
+package exports
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newWriter(CSV, &buf, []string{"title", "created_at"})
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := w.row([]any{"=HYPERLINK(\"x\")", at}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.row([]any{"plain, with comma", nil}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "title,created_at\n\"'=HYPERLINK(\"\"x\"\")\",2026-03-01T12:00:00Z\n\"plain, with comma\",\n"
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	for _, rows := range [][][]any{nil, {{"a", "1"}, {"b", "2"}}} {
+		var buf bytes.Buffer
+		w := newWriter(JSON, &buf, []string{"login", "n"})
+		for _, r := range rows {
+			if err := w.row(r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.close(); err != nil {
+			t.Fatal(err)
+		}
+		var out []map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if len(out) != len(rows) {
+			t.Fatalf("got %d objects, want %d", len(out), len(rows))
+		}
+		if len(rows) > 0 && (out[1]["login"] != "b" || out[1]["n"] != "2") {
+			t.Fatalf("unexpected objects %v", out)
+		}
+	}
+}
+
+func TestDownloadToken(t *testing.T) {
+	id := uuid.New()
+	tok := DownloadToken("secret", id)
+	if !ValidDownloadToken("secret", id, tok) {
+		t.Fatal("token should validate")
+	}
+	if ValidDownloadToken("other", id, tok) || ValidDownloadToken("secret", uuid.New(), tok) || ValidDownloadToken("", id, DownloadToken("", id)) {
+		t.Fatal("token must be bound to the secret and the export")
+	}
+}
@@ -0,0 +1,105 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+const (
+	// Retention is how long a generated export stays downloadable.
+	Retention = 7 * 24 * time.Hour
+	// stuckAfter requeues jobs whose instance died mid-export.
+	stuckAfter = 30 * time.Minute
+)
+
+// Job generates queued exports. Several API instances may run it: jobs are claimed with SKIP LOCKED.
+type Job struct {
+	pool *pgxpool.Pool
+}
+
+func NewJob(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	t := time.NewTicker(10 * time.Second)
+	defer t.Stop()
+	for {
+		for {
+			ran, err := j.RunOnce(ctx)
+			if err != nil {
+				slog.Error("export job failed", "error", err)
+			}
+			if !ran {
+				break
+			}
+		}
+		if _, err := j.pool.Exec(ctx, `DELETE FROM export_jobs WHERE expires_at < now()`); err != nil {
+			slog.Error("failed to prune expired exports", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce generates the oldest queued export, reporting whether there was one.
+func (j *Job) RunOnce(ctx context.Context) (bool, error) {
+	var id uuid.UUID
+	var tenantID *uuid.UUID
+	var r Request
+	err := j.pool.QueryRow(ctx, `
+UPDATE export_jobs
+SET status = 'running', started_at = now()
+WHERE id = (
+  SELECT id FROM export_jobs
+  WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => $1))
+  ORDER BY created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tenant_id, dataset, format, project_id, ecosystem_id, range_from, range_to
+`, stuckAfter.Seconds()).Scan(&id, &tenantID, &r.Dataset, &r.Format, &r.ProjectID, &r.EcosystemID, &r.From, &r.To)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Generate with the requester's tenant so row level security applies as it did for the request.
+	gctx := ctx
+	if tenantID != nil {
+		gctx = tenant.WithID(ctx, *tenantID)
+	}
+	var buf bytes.Buffer
+	n, genErr := Write(gctx, j.pool, r, &buf)
+	if genErr != nil {
+		slog.Error("export failed", "export_id", id, "dataset", r.Dataset, "error", genErr)
+		_, err = j.pool.Exec(ctx, `
+UPDATE export_jobs SET status = 'failed', error = $2, finished_at = now(), expires_at = now() + make_interval(secs => $3)
+WHERE id = $1
+`, id, genErr.Error(), Retention.Seconds())
+		return true, err
+	}
+	_, err = j.pool.Exec(ctx, `
+UPDATE export_jobs
+SET status = 'ready', content = $2, row_count = $3, finished_at = now(), expires_at = now() + make_interval(secs => $4)
+WHERE id = $1
+`, id, buf.Bytes(), n, Retention.Seconds())
+	if err == nil {
+		slog.Info("export ready", "export_id", id, "dataset", r.Dataset, "rows", n)
+	}
+	return true, err
+}
@@ -0,0 +1,130 @@
+// Package fieldset trims list responses to the fields a client asks for with ?fields=, e.g.
+// "fields=number,title,state" to keep only those, or "fields=-comments.body" to drop comment bodies
+// (and keep who commented and when). One level of nesting is supported, into objects and arrays of objects.
+//
+// Endpoints may also name expandable fields, the costly relations (comments, labels, ...). With
+// ?include= a client lists the ones it renders and the others are neither loaded nor sent; without it
+// every expandable field is included, as before the parameter existed.
+package fieldset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Set is a parsed ?fields= value. The zero Set keeps everything.
+type Set struct {
+	include map[string]bool
+	exclude map[string]bool
+	// nested exclusions by parent field: "comments.body" drops body from each comment.
+	excludeNested map[string]map[string]bool
+	// expandable fields left out by ?include=.
+	notIncluded map[string]bool
+}
+
+// Parse reads a comma-separated list of field names. Names prefixed with "-" are dropped; unprefixed names
+// are kept and everything else dropped. Only exclusions may be nested (parent.child).
+func Parse(raw string) (Set, error) {
+	var s Set
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		neg := strings.HasPrefix(f, "-")
+		name := strings.TrimPrefix(f, "-")
+		parent, child, nested := strings.Cut(name, ".")
+		if parent == "" || (nested && (child == "" || strings.Contains(child, "."))) {
+			return Set{}, fmt.Errorf("invalid field %q", f)
+		}
+		switch {
+		case nested && !neg:
+			return Set{}, fmt.Errorf("nested field %q can only be excluded", f)
+		case nested:
+			if s.excludeNested == nil {
+				s.excludeNested = map[string]map[string]bool{}
+			}
+			if s.excludeNested[parent] == nil {
+				s.excludeNested[parent] = map[string]bool{}
+			}
+			s.excludeNested[parent][child] = true
+		case neg:
+			if s.exclude == nil {
+				s.exclude = map[string]bool{}
+			}
+			s.exclude[name] = true
+		default:
+			if s.include == nil {
+				s.include = map[string]bool{}
+			}
+			s.include[name] = true
+		}
+	}
+	return s, nil
+}
+
+// WithIncludes applies an ?include= list (comma-separated) over the endpoint's expandable fields. An
+// empty list includes them all; naming a field that isn't expandable is an error.
+func (s Set) WithIncludes(raw string, expandable ...string) (Set, error) {
+	included := map[string]bool{}
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			included[f] = true
+		}
+	}
+	if len(included) == 0 {
+		return s, nil
+	}
+	known := map[string]bool{}
+	for _, f := range expandable {
+		known[f] = true
+	}
+	for f := range included {
+		if !known[f] {
+			return Set{}, fmt.Errorf("unknown include %q (expandable: %s)", f, strings.Join(expandable, ", "))
+		}
+	}
+	s.notIncluded = map[string]bool{}
+	for _, f := range expandable {
+		if !included[f] {
+			s.notIncluded[f] = true
+		}
+	}
+	return s, nil
+}
+
+// Wants reports whether the field is part of the response, so callers can skip loading what is dropped.
+func (s Set) Wants(field string) bool {
+	if s.exclude[field] || s.notIncluded[field] {
+		return false
+	}
+	return s.include == nil || s.include[field]
+}
+
+// Apply trims one item in place and returns it.
+func (s Set) Apply(item map[string]any) map[string]any {
+	for k, v := range item {
+		if !s.Wants(k) {
+			delete(item, k)
+			continue
+		}
+		if drop := s.excludeNested[k]; drop != nil {
+			item[k] = trim(v, drop)
+		}
+	}
+	return item
+}
+
+func trim(v any, drop map[string]bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k := range drop {
+			delete(t, k)
+		}
+	case []any:
+		for i := range t {
+			t[i] = trim(t[i], drop)
+		}
+	}
+	return v
+}
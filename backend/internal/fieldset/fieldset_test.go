@@ -0,0 +1,96 @@
+package fieldset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func issue() map[string]any {
+	return map[string]any{
+		"number": 7,
+		"title":  "Fix login",
+		"comments": []any{
+			map[string]any{"user": "alice", "body": "long text", "created_at": "2026-01-01"},
+			map[string]any{"user": "bob", "body": "more text"},
+		},
+		"labels": []any{"bug"},
+	}
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want map[string]any
+	}{
+		{"", issue()},
+		{"number,title", map[string]any{"number": 7, "title": "Fix login"}},
+		{"-comments", map[string]any{"number": 7, "title": "Fix login", "labels": []any{"bug"}}},
+		{"-comments.body,-labels", map[string]any{
+			"number": 7,
+			"title":  "Fix login",
+			"comments": []any{
+				map[string]any{"user": "alice", "created_at": "2026-01-01"},
+				map[string]any{"user": "bob"},
+			},
+		}},
+		{"number, comments, -comments.body", map[string]any{
+			"number":   7,
+			"comments": []any{map[string]any{"user": "alice", "created_at": "2026-01-01"}, map[string]any{"user": "bob"}},
+		}},
+	}
+	for _, tc := range cases {
+		s, err := Parse(tc.raw)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.raw, err)
+		}
+		if got := s.Apply(issue()); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("fields=%q: got %v, want %v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, raw := range []string{"-", "comments.body", "-comments.", "-a.b.c", ".body"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) accepted", raw)
+		}
+	}
+}
+
+func TestWants(t *testing.T) {
+	s, _ := Parse("-comments.body")
+	if !s.Wants("comments") {
+		t.Error("excluding comment bodies must still load comments")
+	}
+	s, _ = Parse("number,title")
+	if s.Wants("comments") || !s.Wants("title") {
+		t.Error("an include list keeps only the listed fields")
+	}
+}
+
+func TestWithIncludes(t *testing.T) {
+	s, err := Set{}.WithIncludes("", "comments", "labels")
+	if err != nil || !s.Wants("comments") || !s.Wants("labels") {
+		t.Fatalf("no include list must keep every expandable field (err %v)", err)
+	}
+
+	s, err = Set{}.WithIncludes("labels", "comments", "labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"number": 7, "title": "Fix login", "labels": []any{"bug"}}
+	if got := s.Apply(issue()); !reflect.DeepEqual(got, want) {
+		t.Errorf("include=labels: got %v, want %v", got, want)
+	}
+
+	// fields= still applies on top.
+	f, _ := Parse("-labels")
+	s, _ = f.WithIncludes("labels,comments", "comments", "labels")
+	if s.Wants("labels") || !s.Wants("comments") {
+		t.Error("fields=-labels must win over include=labels")
+	}
+
+	if _, err := (Set{}).WithIncludes("reviews", "comments", "labels"); err == nil {
+		t.Error("unknown include accepted")
+	}
+}
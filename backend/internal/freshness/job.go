@@ -0,0 +1,185 @@
+// Package freshness re-checks issues we surface as available (open and unassigned) against GitHub,
+// catching closes and assignments our webhooks missed, and demotes the ones that are no longer available.
+package freshness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/time/rate"
+
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// batchSize bounds how many issues are re-checked per pass.
+const batchSize = 200
+
+// Job periodically validates available issues.
+type Job struct {
+	cfg     config.Config
+	pool    *pgxpool.Pool
+	gh      *github.Client
+	limiter *rate.Limiter
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Job {
+	return &Job{
+		cfg:     cfg,
+		pool:    pool,
+		gh:      github.NewClient(),
+		limiter: rate.NewLimiter(rate.Every(500*time.Millisecond), 2), // ~2 req/s, leaves headroom for the sync worker
+	}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	if j.cfg.IssueFreshnessHours <= 0 {
+		return nil
+	}
+	t := time.NewTicker(10 * time.Minute)
+	defer t.Stop()
+
+	for {
+		if err := j.Check(ctx); err != nil {
+			slog.Error("issue freshness check failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+type candidate struct {
+	projectID      uuid.UUID
+	fullName       string
+	ownerID        uuid.UUID
+	installationID string
+	number         int
+}
+
+// Check re-validates the available issues that were checked longest ago (or never).
+func (j *Job) Check(ctx context.Context) error {
+	rows, err := j.pool.Query(ctx, `
+SELECT gi.project_id, p.github_full_name, p.owner_user_id, COALESCE(p.github_app_installation_id, ''), gi.number
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE gi.state = 'open'
+  AND COALESCE(jsonb_array_length(gi.assignees), 0) = 0
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND (gi.freshness_checked_at IS NULL OR gi.freshness_checked_at < now() - make_interval(hours => $1))
+ORDER BY gi.freshness_checked_at NULLS FIRST
+LIMIT $2
+`, j.cfg.IssueFreshnessHours, batchSize)
+	if err != nil {
+		return err
+	}
+	var list []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.projectID, &c.fullName, &c.ownerID, &c.installationID, &c.number); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tokens := map[uuid.UUID]string{}
+	demoted := 0
+	for _, c := range list {
+		token, ok := tokens[c.projectID]
+		if !ok {
+			token = j.token(ctx, c)
+			tokens[c.projectID] = token
+		}
+		if token == "" {
+			continue
+		}
+		if err := j.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		changed, err := j.checkOne(ctx, c, token)
+		if err != nil {
+			slog.Warn("issue freshness check failed", "project_id", c.projectID, "repo", c.fullName, "issue", c.number, "error", err)
+			continue
+		}
+		if changed {
+			demoted++
+			checkruns.MarkIssueChanged(ctx, j.pool, c.projectID, c.number)
+		}
+	}
+
+	if demoted > 0 {
+		slog.Info("stale available issues demoted", "checked", len(list), "demoted", demoted)
+	}
+	return nil
+}
+
+// token prefers the project's App installation token and falls back to the owner's OAuth token.
+func (j *Job) token(ctx context.Context, c candidate) string {
+	if c.installationID != "" && j.cfg.GitHubAppID != "" && j.cfg.GitHubAppPrivateKey != "" {
+		if appClient, err := github.NewInstallationAppClient(ctx, j.pool, j.cfg, c.installationID); err == nil {
+			if t, err := appClient.GetInstallationToken(ctx, c.installationID); err == nil {
+				return t
+			}
+		}
+	}
+	linked, err := github.GetLinkedAccount(ctx, j.pool, c.ownerID, j.cfg.TokenEncKey())
+	if err != nil {
+		return ""
+	}
+	return linked.AccessToken
+}
+
+// checkOne refreshes one issue and reports whether it stopped being available.
+func (j *Job) checkOne(ctx context.Context, c candidate, token string) (bool, error) {
+	it, err := j.gh.GetIssue(ctx, token, c.fullName, c.number)
+	if errors.Is(err, github.ErrIssueGone) {
+		_, err = j.pool.Exec(ctx, `
+UPDATE github_issues
+SET state = 'closed', closed_at_github = COALESCE(closed_at_github, now()), freshness_checked_at = now()
+WHERE project_id = $1 AND number = $2
+`, c.projectID, c.number)
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if it.State == "open" && len(it.Assignees) == 0 {
+		_, err = j.pool.Exec(ctx, `
+UPDATE github_issues SET freshness_checked_at = now() WHERE project_id = $1 AND number = $2
+`, c.projectID, c.number)
+		return false, err
+	}
+
+	var closedAt *time.Time
+	if it.ClosedAt != nil {
+		if t, err := time.Parse(time.RFC3339, *it.ClosedAt); err == nil {
+			closedAt = &t
+		}
+	}
+	assigneesJSON, _ := json.Marshal(it.Assignees)
+	_, err = j.pool.Exec(ctx, `
+UPDATE github_issues
+SET state = $3, state_reason = $6, assignees = $4, closed_at_github = COALESCE($5, closed_at_github),
+    freshness_checked_at = now(), last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, c.projectID, c.number, it.State, assigneesJSON, closedAt, it.StateReason)
+	return err == nil, err
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 )
 
 type Client struct {
@@ -15,7 +14,7 @@ type Client struct {
 
 func NewClient() *Client {
 	return &Client{
-		HTTP:      &http.Client{Timeout: 10 * time.Second},
+		HTTP:      newHTTPClient(),
 		UserAgent: "patchwork-backend",
 	}
 }
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
 )
 
 // GitHubAppClient handles GitHub App API calls
@@ -40,7 +42,7 @@ func NewGitHubAppClient(appID string, privateKeyPEM string) (*GitHubAppClient, e
 	return &GitHubAppClient{
 		AppID:      appID,
 		PrivateKey: privateKey,
-		HTTP:       &http.Client{Timeout: 10 * time.Second},
+		HTTP:       newHTTPClient(),
 		UserAgent:  "grainlify-backend",
 	}, nil
 }
@@ -59,6 +61,7 @@ func (c *GitHubAppClient) GenerateJWT() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT: %w", err)
 	}
+	apiusage.Label(tokenString, apiusage.KindApp, c.AppID)
 
 	return tokenString, nil
 }
@@ -104,6 +107,7 @@ func (c *GitHubAppClient) GetInstallationToken(ctx context.Context, installation
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
 		return "", err
 	}
+	apiusage.Label(tokenResp.Token, apiusage.KindInstallation, installationID)
 
 	return tokenResp.Token, nil
 }
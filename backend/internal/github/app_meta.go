@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// AppInfo is the GitHub App authenticated by the client's JWT (GET /app).
+type AppInfo struct {
+	ID   int64  `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// GetApp returns the App the private key belongs to. A wrong App ID / key pair fails with 401.
+func (c *GitHubAppClient) GetApp(ctx context.Context) (AppInfo, error) {
+	var out AppInfo
+	err := c.getAsApp(ctx, "https://api.github.com/app", &out)
+	return out, err
+}
+
+// AppHookConfig is the App's webhook delivery configuration. GitHub never returns the secret itself,
+// only "********" when one is set.
+type AppHookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret"`
+}
+
+// GetHookConfig returns where and how GitHub delivers the App's webhooks.
+func (c *GitHubAppClient) GetHookConfig(ctx context.Context) (AppHookConfig, error) {
+	var out AppHookConfig
+	err := c.getAsApp(ctx, "https://api.github.com/app/hook/config", &out)
+	return out, err
+}
+
+func (c *GitHubAppClient) getAsApp(ctx context.Context, u string, out any) error {
+	jwtToken, err := c.GenerateJWT()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseGitHubAPIError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
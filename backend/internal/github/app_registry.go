@@ -0,0 +1,61 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+)
+
+// InstallationAppID returns the ID of the GitHub App that owns installationID, or "" for the default app.
+func InstallationAppID(ctx context.Context, pool *pgxpool.Pool, installationID string) (string, error) {
+	if pool == nil || strings.TrimSpace(installationID) == "" {
+		return "", nil
+	}
+	var appID string
+	err := pool.QueryRow(ctx, `
+SELECT app_id
+FROM github_app_installations
+WHERE installation_id = $1
+`, installationID).Scan(&appID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return appID, nil
+}
+
+// RecordInstallationApp remembers which GitHub App an installation belongs to.
+func RecordInstallationApp(ctx context.Context, pool *pgxpool.Pool, installationID string, appID string) error {
+	if pool == nil || strings.TrimSpace(installationID) == "" || strings.TrimSpace(appID) == "" {
+		return nil
+	}
+	_, err := pool.Exec(ctx, `
+INSERT INTO github_app_installations (installation_id, app_id)
+VALUES ($1, $2)
+ON CONFLICT (installation_id) DO UPDATE SET app_id = EXCLUDED.app_id, updated_at = now()
+`, installationID, appID)
+	return err
+}
+
+// NewInstallationAppClient returns a client for the GitHub App that owns installationID, so bot
+// actions on that installation come from the right (possibly ecosystem-branded) identity.
+// Installations with no recorded app use the default app.
+func NewInstallationAppClient(ctx context.Context, pool *pgxpool.Pool, cfg config.Config, installationID string) (*GitHubAppClient, error) {
+	appID, err := InstallationAppID(ctx, pool, installationID)
+	if err != nil {
+		return nil, fmt.Errorf("installation app lookup: %w", err)
+	}
+	app, ok := cfg.GitHubApp(appID)
+	if !ok {
+		return nil, fmt.Errorf("github app %q is not configured", appID)
+	}
+	return NewGitHubAppClient(app.ID, app.PrivateKey)
+}
@@ -82,3 +82,38 @@ func (c *Client) RemoveIssueAssignees(ctx context.Context, accessToken string, f
 	}
 	return nil
 }
+
+// CheckAssignee reports whether login can be assigned to issues in the repository. GitHub silently
+// drops assignees who lack access, so callers should check before assigning.
+func (c *Client) CheckAssignee(ctx context.Context, accessToken string, fullName string, login string) (bool, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return false, err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/assignees/" + url.PathEscape(login)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, parseGitHubAPIError(resp)
+	}
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CheckRun is the subset of a GitHub check run we create and update.
+// Requires the GitHub App "checks: write" permission.
+type CheckRun struct {
+	Name       string
+	HeadSHA    string
+	Conclusion string // success, neutral, failure, ...
+	Title      string
+	Summary    string
+	DetailsURL string
+}
+
+func (r CheckRun) payload(includeHead bool) map[string]any {
+	p := map[string]any{
+		"name":       r.Name,
+		"status":     "completed",
+		"conclusion": r.Conclusion,
+		"output": map[string]string{
+			"title":   r.Title,
+			"summary": r.Summary,
+		},
+	}
+	if includeHead {
+		p["head_sha"] = r.HeadSHA
+	}
+	if strings.HasPrefix(r.DetailsURL, "http") {
+		p["details_url"] = r.DetailsURL
+	}
+	return p
+}
+
+// CreateCheckRun creates a completed check run on a commit and returns its ID.
+func (c *Client) CreateCheckRun(ctx context.Context, accessToken string, fullName string, run CheckRun) (int64, error) {
+	if strings.TrimSpace(run.HeadSHA) == "" {
+		return 0, fmt.Errorf("head sha is required")
+	}
+	return c.sendCheckRun(ctx, accessToken, fullName, http.MethodPost, "", run.payload(true))
+}
+
+// UpdateCheckRun replaces the conclusion and output of an existing check run.
+func (c *Client) UpdateCheckRun(ctx context.Context, accessToken string, fullName string, checkRunID int64, run CheckRun) error {
+	if checkRunID <= 0 {
+		return fmt.Errorf("invalid check run id")
+	}
+	_, err := c.sendCheckRun(ctx, accessToken, fullName, http.MethodPatch, fmt.Sprintf("/%d", checkRunID), run.payload(false))
+	return err
+}
+
+func (c *Client) sendCheckRun(ctx context.Context, accessToken string, fullName string, method string, suffix string, payload map[string]any) (int64, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(accessToken) == "" {
+		return 0, fmt.Errorf("missing github access token")
+	}
+
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/check-runs" + suffix
+	b, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(b))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, parseGitHubAPIError(resp)
+	}
+
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	if out.ID == 0 {
+		return 0, fmt.Errorf("invalid github check run response")
+	}
+	return out.ID, nil
+}
@@ -0,0 +1,29 @@
+package github
+
+import "context"
+
+// PRCommit is a commit on a pull request.
+type PRCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	} `json:"commit"`
+	// Author is the GitHub account matched to the commit email; nil when there is no match.
+	Author *struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// ListPRCommits fetches the commits on a pull request (first 100).
+func (c *Client) ListPRCommits(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRCommit, error) {
+	var out []PRCommit
+	if err := c.getPRList(ctx, accessToken, fullName, prNumber, "commits", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
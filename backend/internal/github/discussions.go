@@ -0,0 +1,167 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Discussion is a repository discussion. Discussions are only in the GraphQL API.
+type Discussion struct {
+	NodeID   string
+	Number   int
+	Title    string
+	URL      string
+	Author   string
+	Category string
+	// Answerable categories (Q&A) let the author mark a comment as the answer.
+	Answerable   bool
+	Closed       bool
+	AnswerAuthor string
+	AnswerURL    string
+	AnsweredAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// DiscussionsPageSize is how many discussions ListDiscussionsPage returns at most.
+const DiscussionsPageSize = 50
+
+const discussionsQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    discussions(first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        id number title url closed createdAt updatedAt
+        author { login }
+        category { name isAnswerable }
+        answer { url author { login } }
+        answerChosenAt
+      }
+    }
+  }
+}`
+
+type discussionNode struct {
+	ID        string    `json:"id"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Closed    bool      `json:"closed"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Author    *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Category struct {
+		Name         string `json:"name"`
+		IsAnswerable bool   `json:"isAnswerable"`
+	} `json:"category"`
+	Answer *struct {
+		URL    string `json:"url"`
+		Author *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	} `json:"answer"`
+	AnswerChosenAt *time.Time `json:"answerChosenAt"`
+}
+
+// ListDiscussionsPage returns a page of the repository's discussions, most recently updated first, and the
+// cursor of the next page ("" on the last page). Pass "" for the first page.
+func (c *Client) ListDiscussionsPage(ctx context.Context, accessToken string, fullName string, after string) ([]Discussion, string, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(accessToken) == "" {
+		return nil, "", fmt.Errorf("missing github access token")
+	}
+	vars := map[string]any{"owner": owner, "name": repo, "first": DiscussionsPageSize, "after": nil}
+	if after != "" {
+		vars["after"] = after
+	}
+	b, _ := json.Marshal(map[string]any{"query": discussionsQuery, "variables": vars})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql", bytes.NewReader(b))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", parseGitHubAPIError(resp)
+	}
+
+	var out struct {
+		Data struct {
+			Repository *struct {
+				Discussions struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []discussionNode `json:"nodes"`
+				} `json:"discussions"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, "", err
+	}
+	// GraphQL reports failures (e.g. an unknown repository) with 200 and an errors list.
+	if len(out.Errors) > 0 {
+		return nil, "", &GitHubAPIError{StatusCode: resp.StatusCode, Message: out.Errors[0].Message}
+	}
+	if out.Data.Repository == nil {
+		return nil, "", &GitHubAPIError{StatusCode: http.StatusNotFound, Message: "repository not found"}
+	}
+
+	page := out.Data.Repository.Discussions
+	list := make([]Discussion, 0, len(page.Nodes))
+	for _, n := range page.Nodes {
+		d := Discussion{
+			NodeID:     n.ID,
+			Number:     n.Number,
+			Title:      n.Title,
+			URL:        n.URL,
+			Category:   n.Category.Name,
+			Answerable: n.Category.IsAnswerable,
+			Closed:     n.Closed,
+			AnsweredAt: n.AnswerChosenAt,
+			CreatedAt:  n.CreatedAt,
+			UpdatedAt:  n.UpdatedAt,
+		}
+		if n.Author != nil {
+			d.Author = n.Author.Login
+		}
+		if n.Answer != nil {
+			d.AnswerURL = n.Answer.URL
+			if n.Answer.Author != nil {
+				d.AnswerAuthor = n.Answer.Author.Login
+			}
+		}
+		list = append(list, d)
+	}
+	next := ""
+	if page.PageInfo.HasNextPage {
+		next = page.PageInfo.EndCursor
+	}
+	return list, next, nil
+}
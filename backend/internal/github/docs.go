@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrDocNotFound is returned when a repository has no document at the requested location.
+var ErrDocNotFound = fmt.Errorf("document not found")
+
+// contributingPaths are the locations GitHub itself recognises for contributing guidelines.
+var contributingPaths = []string{"CONTRIBUTING.md", ".github/CONTRIBUTING.md", "docs/CONTRIBUTING.md", "CONTRIBUTING"}
+
+// GetReadmeHTML returns the repository README rendered to HTML by GitHub.
+func (c *Client) GetReadmeHTML(ctx context.Context, accessToken string, fullName string) (string, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return "", err
+	}
+	return c.getRenderedHTML(ctx, accessToken, "https://api.github.com/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/readme")
+}
+
+// GetContributingHTML returns the repository's contributing guidelines rendered to HTML, and the path they were found at.
+func (c *Client) GetContributingHTML(ctx context.Context, accessToken string, fullName string) (string, string, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return "", "", err
+	}
+	for _, p := range contributingPaths {
+		segments := strings.Split(p, "/")
+		for i := range segments {
+			segments[i] = url.PathEscape(segments[i])
+		}
+		html, err := c.getRenderedHTML(ctx, accessToken,
+			"https://api.github.com/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/contents/"+strings.Join(segments, "/"))
+		if err == ErrDocNotFound {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return html, p, nil
+	}
+	return "", "", ErrDocNotFound
+}
+
+func (c *Client) getRenderedHTML(ctx context.Context, accessToken string, u string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(accessToken) != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	// Ask GitHub to render markdown to (sanitized) HTML.
+	req.Header.Set("Accept", "application/vnd.github.html+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrDocNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", parseGitHubAPIError(resp)
+	}
+	// Cap at 1 MiB; very large docs are truncated rather than stored whole.
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,945 @@
+// Package githubtest runs a fake GitHub API for tests. New starts it and routes every request made through
+// the github package's clients (api.github.com and github.com) to it, so handlers and jobs can be exercised
+// end to end without network access:
+//
+//	gh := githubtest.New(t)
+//	repo := gh.AddRepo("acme/widgets")
+//	repo.AddIssue(7, "Fix the thing")
+//	repo.AddAssignable("alice")
+//	gh.AddInstallation("42", "acme/widgets")
+//	cfg.GitHubAppID, cfg.GitHubAppPrivateKey = "1", gh.PrivateKeyPEM()
+//	// ... call the handler, then inspect repo.Issue(7).Assignees / .Comments
+//
+// It covers issues, issue comments, assignees, discussions (GraphQL), App metadata, installations and tokens,
+// the authenticated user, rate limit headers, and error injection via Fail.
+package githubtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// Login of the App's bot user, the author of comments made with installation tokens.
+const BotLogin = "grainlify-test[bot]"
+
+// Server is the fake API. All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	nextID        int64
+	repos         map[string]*Repo    // lower(full name)
+	installations map[string][]string // installation id -> repo full names
+	tokens        map[string]string   // token -> login ("" for installation tokens)
+	oauthCodes    map[string]string   // code -> access token
+	faults        []*fault
+	requests      []Request
+	rate          rateLimit
+	keyPEM        string
+	app           appInfo
+}
+
+type appInfo struct {
+	id            int64
+	slug, hookURL string
+	hookSecret    bool
+}
+
+// Repo is a repository's state on the fake server.
+type Repo struct {
+	FullName string
+
+	srv        *Server
+	issues     map[int]*Issue
+	assignable map[string]bool
+	labels     []github.Label
+
+	discussions map[int]*Discussion
+}
+
+// Issue is an issue's state on the fake server.
+type Issue struct {
+	ID          int64
+	Number      int
+	Title       string
+	Body        string
+	State       string
+	StateReason string
+	Author      string
+	Assignees   []string
+	Labels      []string
+	Comments    []Comment
+}
+
+// Comment is an issue comment on the fake server.
+type Comment struct {
+	ID        int64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Discussion is a discussion's state on the fake server.
+type Discussion struct {
+	Number       int
+	Title        string
+	Author       string
+	Category     string
+	Answerable   bool
+	Closed       bool
+	AnswerAuthor string
+	AnsweredAt   time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Request is one call the server received.
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+	Token  string
+}
+
+type fault struct {
+	method, pathPrefix string
+	status             int
+	message            string
+	remaining          int // -1: until cleared
+}
+
+type rateLimit struct {
+	limit, remaining int
+	reset            time.Time
+}
+
+// New starts a fake GitHub and routes the github package's HTTP traffic to it until the test ends.
+func New(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		nextID:        1000,
+		repos:         map[string]*Repo{},
+		installations: map[string][]string{},
+		tokens:        map[string]string{},
+		oauthCodes:    map[string]string{},
+		rate:          rateLimit{limit: 5000, remaining: 5000, reset: time.Now().Add(time.Hour)},
+	}
+	s.Server = httptest.NewServer(s.routes())
+	target, _ := url.Parse(s.URL)
+	restore := github.UseTransport(&rewriteTransport{target: target})
+	t.Cleanup(func() {
+		restore()
+		s.Close()
+	})
+	return s
+}
+
+// rewriteTransport sends requests meant for GitHub to the fake server, keeping path and query.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL.Scheme = r.target.Scheme
+	out.URL.Host = r.target.Host
+	out.Host = r.target.Host
+	return http.DefaultTransport.RoundTrip(out)
+}
+
+// PrivateKeyPEM returns an RSA key for cfg.GitHubAppPrivateKey. The server does not verify App JWTs.
+func (s *Server) PrivateKeyPEM() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keyPEM == "" {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		s.keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	}
+	return s.keyPEM
+}
+
+// SetApp sets what GET /app and GET /app/hook/config report for the App (any JWT is accepted).
+func (s *Server) SetApp(id int64, slug, hookURL string, hookSecret bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.app = appInfo{id: id, slug: slug, hookURL: hookURL, hookSecret: hookSecret}
+}
+
+// AddRepo creates (or returns) a repository.
+func (s *Server) AddRepo(fullName string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToLower(fullName)
+	if r := s.repos[key]; r != nil {
+		return r
+	}
+	r := &Repo{FullName: fullName, srv: s, issues: map[int]*Issue{}, assignable: map[string]bool{}, discussions: map[int]*Discussion{}}
+	s.repos[key] = r
+	return r
+}
+
+// AddInstallation registers an App installation with access to repos. Its token is InstallationToken(id).
+func (s *Server) AddInstallation(id string, repos ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.installations[id] = append(s.installations[id], repos...)
+	s.tokens[InstallationToken(id)] = ""
+}
+
+// InstallationToken is the token the server mints for an installation.
+func InstallationToken(id string) string {
+	return "ghs_test_" + id
+}
+
+// AddUser registers a user OAuth token, answering /user with login. If code is non-empty, exchanging it at
+// /login/oauth/access_token yields token.
+func (s *Server) AddUser(token, login, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = login
+	if code != "" {
+		s.oauthCodes[code] = token
+	}
+}
+
+// SetRateLimit sets the X-RateLimit-* headers on every response. remaining is decremented per call and a
+// call made at zero gets 403 "API rate limit exceeded".
+func (s *Server) SetRateLimit(limit, remaining int, reset time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rate = rateLimit{limit: limit, remaining: remaining, reset: reset}
+}
+
+// Fail makes the next `times` requests matching method ("" for any) and path prefix answer with status and
+// a GitHub-style error message. times < 0 fails until ClearFaults.
+func (s *Server) Fail(method, pathPrefix string, status int, message string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{method: method, pathPrefix: pathPrefix, status: status, message: message, remaining: times})
+}
+
+// ClearFaults removes all injected errors.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns every call received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// AddIssue creates an open issue.
+func (r *Repo) AddIssue(number int, title string) *Issue {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	r.srv.nextID++
+	is := &Issue{ID: r.srv.nextID, Number: number, Title: title, State: "open", Author: "octocat"}
+	r.issues[number] = is
+	return is
+}
+
+// AddDiscussion opens a discussion by author. Answerable categories (like "Q&A") can have an answer.
+func (r *Repo) AddDiscussion(number int, title, author, category string, answerable bool) *Discussion {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	now := time.Now().UTC().Truncate(time.Second)
+	d := &Discussion{Number: number, Title: title, Author: author, Category: category, Answerable: answerable, CreatedAt: now, UpdatedAt: now}
+	r.discussions[number] = d
+	return d
+}
+
+// AnswerDiscussion marks a comment by login as the discussion's answer.
+func (r *Repo) AnswerDiscussion(number int, login string) {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	d := r.discussions[number]
+	if d == nil || !d.Answerable {
+		panic(fmt.Sprintf("githubtest: %s discussion %d does not exist or is not answerable", r.FullName, number))
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	d.AnswerAuthor, d.AnsweredAt, d.UpdatedAt = login, now, now
+}
+
+// AddAssignable lets login be assigned to the repo's issues (GitHub drops assignees without access).
+func (r *Repo) AddAssignable(logins ...string) {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	for _, l := range logins {
+		r.assignable[strings.ToLower(l)] = true
+	}
+}
+
+// Issue returns a snapshot of an issue, or nil.
+func (r *Repo) Issue(number int) *Issue {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	is := r.issues[number]
+	if is == nil {
+		return nil
+	}
+	cp := *is
+	cp.Assignees = append([]string{}, is.Assignees...)
+	cp.Labels = append([]string{}, is.Labels...)
+	cp.Comments = append([]Comment{}, is.Comments...)
+	return &cp
+}
+
+// Labels returns the repository's labels in creation order.
+func (r *Repo) Labels() []github.Label {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	return append([]github.Label{}, r.labels...)
+}
+
+// labelLocked finds a repository label by name, case-insensitively.
+func (r *Repo) labelLocked(name string) *github.Label {
+	for i := range r.labels {
+		if strings.EqualFold(r.labels[i].Name, name) {
+			return &r.labels[i]
+		}
+	}
+	return nil
+}
+
+// AddComment posts a comment as login, as if made on github.com.
+func (r *Repo) AddComment(number int, login, body string) Comment {
+	r.srv.mu.Lock()
+	defer r.srv.mu.Unlock()
+	is := r.issues[number]
+	if is == nil {
+		panic(fmt.Sprintf("githubtest: %s#%d does not exist", r.FullName, number))
+	}
+	return r.srv.addCommentLocked(is, login, body)
+}
+
+func (s *Server) addCommentLocked(is *Issue, login, body string) Comment {
+	s.nextID++
+	now := time.Now().UTC().Truncate(time.Second)
+	c := Comment{ID: s.nextID, Author: login, Body: body, CreatedAt: now, UpdatedAt: now}
+	is.Comments = append(is.Comments, c)
+	return c
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/oauth/access_token", s.oauthToken)
+	mux.HandleFunc("GET /user", s.user)
+	mux.HandleFunc("GET /app", s.getApp)
+	mux.HandleFunc("GET /app/hook/config", s.getHookConfig)
+	mux.HandleFunc("POST /app/installations/{id}/access_tokens", s.installationToken)
+	mux.HandleFunc("GET /installation/repositories", s.installationRepos)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.withIssue(s.getIssue))
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.withIssue(s.updateIssue))
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.withIssue(s.listComments))
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.withIssue(s.createComment))
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/comments/{cid}", s.withComment(s.updateComment))
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/assignees", s.withIssue(s.addAssignees))
+	// DELETE .../issues/comments/{id} and .../issues/{number}/assignees overlap as mux patterns.
+	deleteComment, removeAssignees := s.withComment(s.deleteComment), s.withIssue(s.removeAssignees)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{a}/{b}", func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.PathValue("a") == "comments":
+			req.SetPathValue("cid", req.PathValue("b"))
+			deleteComment(w, req)
+		case req.PathValue("b") == "assignees":
+			req.SetPathValue("number", req.PathValue("a"))
+			removeAssignees(w, req)
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/assignees/{login}", s.checkAssignee)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/labels", s.withIssue(s.addLabels))
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/issues/{number}/labels/{name}", s.withIssue(s.removeLabel))
+	mux.HandleFunc("POST /repos/{owner}/{repo}/labels", s.createLabel)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+	mux.HandleFunc("POST /graphql", s.graphql)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Body:   string(body),
+			Token:  strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "),
+		})
+		limited := s.rate.remaining <= 0
+		if !limited {
+			s.rate.remaining--
+		}
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.rate.limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(s.rate.remaining, 0)))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.rate.reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		f := s.matchFaultLocked(req)
+		s.mu.Unlock()
+
+		switch {
+		case limited:
+			writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		case f != nil:
+			writeError(w, f.status, f.message)
+		default:
+			mux.ServeHTTP(w, req)
+		}
+	})
+}
+
+func (s *Server) matchFaultLocked(req *http.Request) *fault {
+	for i, f := range s.faults {
+		if (f.method != "" && f.method != req.Method) || !strings.HasPrefix(req.URL.Path, f.pathPrefix) {
+			continue
+		}
+		if f.remaining > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) oauthToken(w http.ResponseWriter, req *http.Request) {
+	var in struct {
+		Code string `json:"code"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&in)
+	s.mu.Lock()
+	token, ok := s.oauthCodes[in.Code]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]string{"error": "bad_verification_code"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": token, "token_type": "bearer", "scope": "read:user,user:email"})
+}
+
+func (s *Server) user(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	login, ok := s.tokens[bearer(req)]
+	s.mu.Unlock()
+	if !ok || login == "" {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": userID(login), "login": login, "avatar_url": "https://avatars.example/" + login})
+}
+
+func (s *Server) getApp(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	app := s.app
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"id": app.id, "slug": app.slug, "name": app.slug})
+}
+
+func (s *Server) getHookConfig(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	app := s.app
+	s.mu.Unlock()
+	out := map[string]any{"url": app.hookURL, "content_type": "json", "insecure_ssl": "0"}
+	if app.hookSecret {
+		out["secret"] = "********"
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) installationToken(w http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+	s.mu.Lock()
+	_, ok := s.installations[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"token": InstallationToken(id), "expires_at": time.Now().Add(time.Hour).UTC()})
+}
+
+func (s *Server) installationRepos(w http.ResponseWriter, req *http.Request) {
+	token := bearer(req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for id, repos := range s.installations {
+		if InstallationToken(id) == token {
+			names = repos
+		}
+	}
+	list := []map[string]any{}
+	for _, n := range names {
+		owner, name, _ := strings.Cut(n, "/")
+		list = append(list, map[string]any{
+			"id":        userID(n),
+			"full_name": n,
+			"name":      name,
+			"owner":     map[string]any{"id": userID(owner), "login": owner, "type": "Organization"},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"total_count": len(list), "repositories": list})
+}
+
+// withIssue resolves {owner}/{repo}/issues/{number}, answering 404 like GitHub when it doesn't exist.
+func (s *Server) withIssue(h func(http.ResponseWriter, *http.Request, *Repo, *Issue)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !s.authorized(req) {
+			writeError(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+		n, _ := strconv.Atoi(req.PathValue("number"))
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		r := s.repos[strings.ToLower(req.PathValue("owner")+"/"+req.PathValue("repo"))]
+		if r == nil || r.issues[n] == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		h(w, req, r, r.issues[n])
+	}
+}
+
+// withComment resolves {owner}/{repo}/issues/comments/{cid}.
+func (s *Server) withComment(h func(http.ResponseWriter, *http.Request, *Issue, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !s.authorized(req) {
+			writeError(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+		cid, _ := strconv.ParseInt(req.PathValue("cid"), 10, 64)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r := s.repos[strings.ToLower(req.PathValue("owner")+"/"+req.PathValue("repo"))]; r != nil {
+			for _, is := range r.issues {
+				for i, c := range is.Comments {
+					if c.ID == cid {
+						h(w, req, is, i)
+						return
+					}
+				}
+			}
+		}
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	writeJSON(w, http.StatusOK, issueJSON(r, is))
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, req *http.Request) {
+	if !s.authorized(req) {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	var in struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil || strings.TrimSpace(in.Title) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repos[strings.ToLower(req.PathValue("owner")+"/"+req.PathValue("repo"))]
+	if r == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	number := 1
+	for n := range r.issues {
+		if n >= number {
+			number = n + 1
+		}
+	}
+	s.nextID++
+	is := &Issue{ID: s.nextID, Number: number, Title: in.Title, Body: in.Body, State: "open", Author: s.loginLocked(req)}
+	r.issues[number] = is
+	writeJSON(w, http.StatusCreated, issueJSON(r, is))
+}
+
+// graphql answers the discussions query of github.Client.ListDiscussionsPage; it doesn't parse the query,
+// only its variables. Cursors are offsets into the list.
+func (s *Server) graphql(w http.ResponseWriter, req *http.Request) {
+	if !s.authorized(req) {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	var in struct {
+		Variables struct {
+			Owner string  `json:"owner"`
+			Name  string  `json:"name"`
+			First int     `json:"first"`
+			After *string `json:"after"`
+		} `json:"variables"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil || in.Variables.First <= 0 {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fullName := in.Variables.Owner + "/" + in.Variables.Name
+	r := s.repos[strings.ToLower(fullName)]
+	if r == nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data":   map[string]any{"repository": nil},
+			"errors": []map[string]any{{"type": "NOT_FOUND", "message": fmt.Sprintf("Could not resolve to a Repository with the name '%s'.", fullName)}},
+		})
+		return
+	}
+
+	list := make([]*Discussion, 0, len(r.discussions))
+	for _, d := range r.discussions {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].UpdatedAt.Equal(list[j].UpdatedAt) {
+			return list[i].UpdatedAt.After(list[j].UpdatedAt)
+		}
+		return list[i].Number > list[j].Number
+	})
+	start := 0
+	if in.Variables.After != nil {
+		start, _ = strconv.Atoi(*in.Variables.After)
+	}
+	start = min(max(start, 0), len(list))
+	end := min(start+in.Variables.First, len(list))
+
+	nodes := []map[string]any{}
+	for _, d := range list[start:end] {
+		link := fmt.Sprintf("https://github.com/%s/discussions/%d", r.FullName, d.Number)
+		node := map[string]any{
+			"id":             fmt.Sprintf("D_%d", userID(r.FullName)+int64(d.Number)),
+			"number":         d.Number,
+			"title":          d.Title,
+			"url":            link,
+			"closed":         d.Closed,
+			"createdAt":      d.CreatedAt,
+			"updatedAt":      d.UpdatedAt,
+			"author":         map[string]string{"login": d.Author},
+			"category":       map[string]any{"name": d.Category, "isAnswerable": d.Answerable},
+			"answer":         nil,
+			"answerChosenAt": nil,
+		}
+		if d.AnswerAuthor != "" {
+			node["answer"] = map[string]any{"url": link + "#discussioncomment-1", "author": map[string]string{"login": d.AnswerAuthor}}
+			node["answerChosenAt"] = d.AnsweredAt
+		}
+		nodes = append(nodes, node)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"repository": map[string]any{"discussions": map[string]any{
+		"pageInfo": map[string]any{"hasNextPage": end < len(list), "endCursor": strconv.Itoa(end)},
+		"nodes":    nodes,
+	}}}})
+}
+
+func (s *Server) updateIssue(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	var in struct {
+		State       *string `json:"state"`
+		StateReason *string `json:"state_reason"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	if in.State != nil {
+		if *in.State != "open" && *in.State != "closed" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		is.State = *in.State
+		is.StateReason = ""
+		switch {
+		case in.StateReason != nil:
+			is.StateReason = *in.StateReason
+		case is.State == "closed":
+			is.StateReason = "completed"
+		}
+	}
+	writeJSON(w, http.StatusOK, issueJSON(r, is))
+}
+
+func (s *Server) listComments(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	list := []map[string]any{}
+	for _, c := range is.Comments {
+		list = append(list, commentJSON(r, is, c))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createComment(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	var in struct {
+		Body string `json:"body"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil || strings.TrimSpace(in.Body) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	c := s.addCommentLocked(is, s.loginLocked(req), in.Body)
+	writeJSON(w, http.StatusCreated, commentJSON(r, is, c))
+}
+
+func (s *Server) updateComment(w http.ResponseWriter, req *http.Request, is *Issue, i int) {
+	var in struct {
+		Body string `json:"body"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil || strings.TrimSpace(in.Body) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	if is.Comments[i].Author != s.loginLocked(req) {
+		writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+		return
+	}
+	is.Comments[i].Body = in.Body
+	is.Comments[i].UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, commentJSON(nil, is, is.Comments[i]))
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, req *http.Request, is *Issue, i int) {
+	if is.Comments[i].Author != s.loginLocked(req) {
+		writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+		return
+	}
+	is.Comments = append(is.Comments[:i], is.Comments[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addAssignees(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	var in struct {
+		Assignees []string `json:"assignees"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&in)
+	for _, l := range in.Assignees {
+		// Like GitHub: users without access are silently ignored.
+		if r.assignable[strings.ToLower(l)] && !containsFold(is.Assignees, l) {
+			is.Assignees = append(is.Assignees, l)
+		}
+	}
+	writeJSON(w, http.StatusCreated, issueJSON(r, is))
+}
+
+func (s *Server) removeAssignees(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	var in struct {
+		Assignees []string `json:"assignees"`
+	}
+	_ = json.NewDecoder(req.Body).Decode(&in)
+	kept := is.Assignees[:0]
+	for _, l := range is.Assignees {
+		if !containsFold(in.Assignees, l) {
+			kept = append(kept, l)
+		}
+	}
+	is.Assignees = kept
+	writeJSON(w, http.StatusOK, issueJSON(r, is))
+}
+
+func (s *Server) addLabels(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	var in struct {
+		Labels []string `json:"labels"`
+	}
+	if json.NewDecoder(req.Body).Decode(&in) != nil || len(in.Labels) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	for _, name := range in.Labels {
+		l := r.labelLocked(name)
+		if l == nil {
+			r.labels = append(r.labels, github.Label{Name: name, Color: "ededed"})
+			l = &r.labels[len(r.labels)-1]
+		}
+		if !containsFold(is.Labels, l.Name) {
+			is.Labels = append(is.Labels, l.Name)
+		}
+	}
+	writeJSON(w, http.StatusOK, labelsJSON(r, is))
+}
+
+func (s *Server) removeLabel(w http.ResponseWriter, req *http.Request, r *Repo, is *Issue) {
+	name := req.PathValue("name")
+	if !containsFold(is.Labels, name) {
+		writeError(w, http.StatusNotFound, "Label does not exist")
+		return
+	}
+	kept := is.Labels[:0]
+	for _, l := range is.Labels {
+		if !strings.EqualFold(l, name) {
+			kept = append(kept, l)
+		}
+	}
+	is.Labels = kept
+	writeJSON(w, http.StatusOK, labelsJSON(r, is))
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, req *http.Request) {
+	if !s.authorized(req) {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	var in github.Label
+	if json.NewDecoder(req.Body).Decode(&in) != nil || strings.TrimSpace(in.Name) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repos[strings.ToLower(req.PathValue("owner")+"/"+req.PathValue("repo"))]
+	if r == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if r.labelLocked(in.Name) != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+			"message": "Validation Failed",
+			"errors":  []map[string]string{{"resource": "Label", "code": "already_exists", "field": "name"}},
+		})
+		return
+	}
+	if in.Color == "" {
+		in.Color = "ededed"
+	}
+	r.labels = append(r.labels, in)
+	writeJSON(w, http.StatusCreated, in)
+}
+
+func (s *Server) checkAssignee(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	r := s.repos[strings.ToLower(req.PathValue("owner")+"/"+req.PathValue("repo"))]
+	ok := r != nil && r.assignable[strings.ToLower(req.PathValue("login"))]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.tokens[bearer(req)]
+	return ok
+}
+
+// loginLocked is who the request acts as: the user for OAuth tokens, the App bot for installation tokens.
+func (s *Server) loginLocked(req *http.Request) string {
+	if login := s.tokens[bearer(req)]; login != "" {
+		return login
+	}
+	return BotLogin
+}
+
+func issueJSON(r *Repo, is *Issue) map[string]any {
+	assignees := []map[string]string{}
+	for _, l := range is.Assignees {
+		assignees = append(assignees, map[string]string{"login": l})
+	}
+	var stateReason any
+	if is.StateReason != "" {
+		stateReason = is.StateReason
+	}
+	return map[string]any{
+		"id":           is.ID,
+		"number":       is.Number,
+		"title":        is.Title,
+		"body":         is.Body,
+		"state":        is.State,
+		"state_reason": stateReason,
+		"html_url":     fmt.Sprintf("https://github.com/%s/issues/%d", r.FullName, is.Number),
+		"user":         map[string]string{"login": is.Author},
+		"assignees":    assignees,
+		"labels":       labelsJSON(r, is),
+		"comments":     len(is.Comments),
+	}
+}
+
+func labelsJSON(r *Repo, is *Issue) []github.Label {
+	out := []github.Label{}
+	for _, name := range is.Labels {
+		if l := r.labelLocked(name); l != nil {
+			out = append(out, *l)
+		}
+	}
+	return out
+}
+
+func commentJSON(r *Repo, is *Issue, c Comment) map[string]any {
+	out := map[string]any{
+		"id":         c.ID,
+		"body":       c.Body,
+		"user":       map[string]string{"login": c.Author},
+		"created_at": c.CreatedAt.Format(time.RFC3339),
+		"updated_at": c.UpdatedAt.Format(time.RFC3339),
+	}
+	if r != nil {
+		out["html_url"] = fmt.Sprintf("https://github.com/%s/issues/%d#issuecomment-%d", r.FullName, is.Number, c.ID)
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message, "documentation_url": "https://docs.github.com/rest"})
+}
+
+func bearer(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
+
+// userID derives a stable fake numeric id from a name.
+func userID(name string) int64 {
+	var h int64 = 7
+	for _, c := range strings.ToLower(name) {
+		h = h*31 + int64(c)
+	}
+	if h < 0 {
+		h = -h
+	}
+	return h%1_000_000_000 + 1
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package githubtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/github/githubtest"
+)
+
+func TestAssignAndCommentAsApp(t *testing.T) {
+	gh := githubtest.New(t)
+	repo := gh.AddRepo("acme/widgets")
+	repo.AddIssue(7, "Fix the thing")
+	repo.AddAssignable("alice")
+	gh.AddInstallation("42", "acme/widgets")
+	ctx := context.Background()
+
+	app, err := github.NewGitHubAppClient("1", gh.PrivateKeyPEM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := app.GetInstallationToken(ctx, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := github.NewClient()
+	if ok, err := client.CheckAssignee(ctx, token, "acme/widgets", "mallory"); err != nil || ok {
+		t.Fatalf("CheckAssignee(mallory) = %v, %v", ok, err)
+	}
+	if err := client.AddIssueAssignees(ctx, token, "acme/widgets", 7, []string{"alice"}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.CreateIssueComment(ctx, token, "acme/widgets", 7, "Assigned to @alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	is := repo.Issue(7)
+	if len(is.Assignees) != 1 || is.Assignees[0] != "alice" {
+		t.Fatalf("assignees = %v", is.Assignees)
+	}
+	if len(is.Comments) != 1 || is.Comments[0].ID != c.ID || is.Comments[0].Author != githubtest.BotLogin {
+		t.Fatalf("comments = %+v", is.Comments)
+	}
+}
+
+func TestFaultsAndRateLimit(t *testing.T) {
+	gh := githubtest.New(t)
+	gh.AddRepo("acme/widgets").AddIssue(1, "x")
+	gh.AddUser("gho_alice", "alice", "")
+	ctx := context.Background()
+	client := github.NewClient()
+
+	gh.Fail(http.MethodPost, "/repos/acme/widgets/issues/1/comments", http.StatusForbidden, "Resource not accessible by integration", 1)
+	_, err := client.CreateIssueComment(ctx, "gho_alice", "acme/widgets", 1, "hi")
+	var apiErr *github.GitHubAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("want injected 403, got %v", err)
+	}
+	if _, err := client.CreateIssueComment(ctx, "gho_alice", "acme/widgets", 1, "hi"); err != nil {
+		t.Fatalf("fault should apply once: %v", err)
+	}
+
+	gh.SetRateLimit(5000, 0, time.Now().Add(time.Minute))
+	_, err = client.CreateIssueComment(ctx, "gho_alice", "acme/widgets", 1, "again")
+	if !errors.As(err, &apiErr) || apiErr.RateLimitRemaining == nil || *apiErr.RateLimitRemaining != 0 {
+		t.Fatalf("want rate limit error, got %v", err)
+	}
+}
+
+func TestCloseAndReopenIssue(t *testing.T) {
+	gh := githubtest.New(t)
+	repo := gh.AddRepo("acme/widgets")
+	repo.AddIssue(3, "Won't fix")
+	gh.AddUser("gho_alice", "alice", "")
+	ctx := context.Background()
+	client := github.NewClient()
+
+	if err := client.UpdateIssueState(ctx, "gho_alice", "acme/widgets", 3, "closed", "not_planned"); err != nil {
+		t.Fatal(err)
+	}
+	it, err := client.GetIssue(ctx, "gho_alice", "acme/widgets", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.State != "closed" || it.StateReason == nil || *it.StateReason != "not_planned" {
+		t.Fatalf("after close: %s %v", it.State, it.StateReason)
+	}
+	if err := client.UpdateIssueState(ctx, "gho_alice", "acme/widgets", 3, "open", "reopened"); err != nil {
+		t.Fatal(err)
+	}
+	if is := repo.Issue(3); is.State != "open" || is.StateReason != "reopened" {
+		t.Fatalf("after reopen: %+v", is)
+	}
+	if err := client.UpdateIssueState(ctx, "gho_alice", "acme/widgets", 4, "closed", ""); !errors.Is(err, github.ErrIssueGone) {
+		t.Fatalf("missing issue: %v", err)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	gh := githubtest.New(t)
+	repo := gh.AddRepo("acme/widgets")
+	repo.AddIssue(5, "Add dark mode")
+	gh.AddUser("gho_alice", "alice", "")
+	ctx := context.Background()
+	client := github.NewClient()
+
+	if created, err := client.CreateLabel(ctx, "gho_alice", "acme/widgets", github.Label{Name: "grainlify", Color: "6d28d9"}); err != nil || !created {
+		t.Fatalf("CreateLabel = %v, %v", created, err)
+	}
+	if created, err := client.CreateLabel(ctx, "gho_alice", "acme/widgets", github.Label{Name: "Grainlify"}); err != nil || created {
+		t.Fatalf("CreateLabel(existing) = %v, %v", created, err)
+	}
+	if err := client.AddIssueLabels(ctx, "gho_alice", "acme/widgets", 5, []string{"grainlify", "ui"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveIssueLabel(ctx, "gho_alice", "acme/widgets", 5, "ui"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveIssueLabel(ctx, "gho_alice", "acme/widgets", 5, "ui"); err != nil {
+		t.Fatalf("removing an absent label: %v", err)
+	}
+	it, err := client.GetIssue(ctx, "gho_alice", "acme/widgets", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(it.Labels) != 1 || it.Labels[0].Name != "grainlify" || it.Labels[0].Color != "6d28d9" {
+		t.Fatalf("labels = %+v", it.Labels)
+	}
+	if got := repo.Labels(); len(got) != 2 {
+		t.Fatalf("repo labels = %+v", got)
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	gh := githubtest.New(t)
+	repo := gh.AddRepo("acme/widgets")
+	repo.AddIssue(9, "Existing")
+	gh.AddInstallation("42", "acme/widgets")
+	ctx := context.Background()
+
+	app, err := github.NewGitHubAppClient("1", gh.PrivateKeyPEM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := app.GetInstallationToken(ctx, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	it, err := github.NewClient().CreateIssue(ctx, token, "acme/widgets", "Add CSV export", "Details")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Number != 10 || it.ID == 0 || it.User.Login != githubtest.BotLogin {
+		t.Fatalf("created = %+v", it)
+	}
+	if is := repo.Issue(10); is == nil || is.Title != "Add CSV export" || is.State != "open" {
+		t.Fatalf("issue = %+v", is)
+	}
+}
+
+func TestListDiscussions(t *testing.T) {
+	gh := githubtest.New(t)
+	repo := gh.AddRepo("acme/widgets")
+	gh.AddUser("gho_alice", "alice", "")
+	for n := 1; n <= github.DiscussionsPageSize+5; n++ {
+		repo.AddDiscussion(n, "How do I build it?", "bob", "Q&A", true)
+	}
+	repo.AddDiscussion(100, "Show and tell", "bob", "General", false)
+	repo.AnswerDiscussion(3, "alice")
+	ctx := context.Background()
+	client := github.NewClient()
+
+	var all []github.Discussion
+	cursor, pages := "", 0
+	for {
+		list, next, err := client.ListDiscussionsPage(ctx, "gho_alice", "acme/widgets", cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all, pages = append(all, list...), pages+1
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if pages != 2 || len(all) != github.DiscussionsPageSize+6 {
+		t.Fatalf("got %d discussions in %d pages", len(all), pages)
+	}
+	var d github.Discussion
+	for _, x := range all {
+		if x.Number == 3 {
+			d = x
+		}
+	}
+	if d.AnswerAuthor != "alice" || d.AnsweredAt == nil || d.AnswerURL == "" || !d.Answerable || d.Category != "Q&A" || d.Author != "bob" {
+		t.Fatalf("answered discussion = %+v", d)
+	}
+
+	var apiErr *github.GitHubAPIError
+	if _, _, err := client.ListDiscussionsPage(ctx, "gho_alice", "acme/missing", ""); !errors.As(err, &apiErr) {
+		t.Fatalf("missing repo: err = %v", err)
+	}
+}
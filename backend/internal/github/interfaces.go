@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+)
+
+// API is the GitHub REST (and GraphQL, for discussions) surface used by handlers. *Client is the real implementation; tests can inject
+// a fake (or a *Client pointed at githubtest).
+type API interface {
+	GetUser(ctx context.Context, accessToken string) (User, error)
+	GetUserEmails(ctx context.Context, accessToken string) ([]Email, error)
+	GetPrimaryEmail(ctx context.Context, accessToken string) (string, error)
+
+	GetRepo(ctx context.Context, accessToken string, fullName string) (Repo, error)
+	GetRepoLanguages(ctx context.Context, accessToken string, fullName string) (map[string]int64, error)
+	GetReadme(ctx context.Context, accessToken string, fullName string) (string, error)
+	GetReadmeHTML(ctx context.Context, accessToken string, fullName string) (string, error)
+	GetContributingHTML(ctx context.Context, accessToken string, fullName string) (string, string, error)
+	CreateWebhook(ctx context.Context, accessToken string, fullName string, req CreateWebhookRequest) (Webhook, error)
+
+	GetIssue(ctx context.Context, accessToken string, fullName string, issueNumber int) (IssueListItem, error)
+	CreateIssue(ctx context.Context, accessToken string, fullName string, title string, body string) (IssueListItem, error)
+	UpdateIssueState(ctx context.Context, accessToken string, fullName string, issueNumber int, state string, stateReason string) error
+	ListIssuesPage(ctx context.Context, accessToken string, fullName string, page int) ([]IssueListItem, error)
+	ListPRsPage(ctx context.Context, accessToken string, fullName string, page int) ([]PRListItem, error)
+	ListIssueComments(ctx context.Context, accessToken string, fullName string, issueNumber int) ([]IssueComment, error)
+	CreateIssueComment(ctx context.Context, accessToken string, fullName string, issueNumber int, body string) (IssueComment, error)
+	UpdateIssueComment(ctx context.Context, accessToken string, fullName string, commentID int64, body string) (IssueComment, error)
+	DeleteIssueComment(ctx context.Context, accessToken string, fullName string, commentID int64) error
+
+	AddIssueAssignees(ctx context.Context, accessToken string, fullName string, issueNumber int, logins []string) error
+	RemoveIssueAssignees(ctx context.Context, accessToken string, fullName string, issueNumber int, logins []string) error
+	CheckAssignee(ctx context.Context, accessToken string, fullName string, login string) (bool, error)
+
+	AddIssueLabels(ctx context.Context, accessToken string, fullName string, issueNumber int, names []string) error
+	RemoveIssueLabel(ctx context.Context, accessToken string, fullName string, issueNumber int, name string) error
+	CreateLabel(ctx context.Context, accessToken string, fullName string, label Label) (bool, error)
+
+	CreateCheckRun(ctx context.Context, accessToken string, fullName string, run CheckRun) (int64, error)
+	UpdateCheckRun(ctx context.Context, accessToken string, fullName string, checkRunID int64, run CheckRun) error
+
+	GetPRStats(ctx context.Context, accessToken string, fullName string, prNumber int) (PRStats, error)
+	ListPRCommits(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRCommit, error)
+	ListPRFiles(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRFile, error)
+	ListPRReviews(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRReview, error)
+	ListPRReviewComments(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRReviewComment, error)
+
+	ListDiscussionsPage(ctx context.Context, accessToken string, fullName string, after string) ([]Discussion, string, error)
+}
+
+// AppAPI is what handlers do as a GitHub App. *GitHubAppClient implements it.
+type AppAPI interface {
+	GetInstallationToken(ctx context.Context, installationID string) (string, error)
+	ListInstallationRepositories(ctx context.Context, installationToken string) ([]InstallationRepository, error)
+}
+
+// Apps resolves the GitHub App client for an installation (see NewInstallationAppClient).
+type Apps interface {
+	ForInstallation(ctx context.Context, installationID string) (AppAPI, error)
+}
+
+var (
+	_ API    = (*Client)(nil)
+	_ AppAPI = (*GitHubAppClient)(nil)
+)
+
+// NewApps returns the default Apps: installations are mapped to their app via github_app_installations
+// (pool may be nil, meaning always the default app), and clients are reused per app.
+func NewApps(cfg config.Config, pool *pgxpool.Pool) Apps {
+	return &apps{cfg: cfg, pool: pool, clients: map[string]*GitHubAppClient{}, keys: map[string]string{}}
+}
+
+type apps struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+
+	mu      sync.Mutex
+	clients map[string]*GitHubAppClient
+	keys    map[string]string // private key each client was built from, to notice rotations
+}
+
+func (a *apps) ForInstallation(ctx context.Context, installationID string) (AppAPI, error) {
+	appID, err := InstallationAppID(ctx, a.pool, installationID)
+	if err != nil {
+		return nil, fmt.Errorf("installation app lookup: %w", err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	app, ok := a.cfg.GitHubApp(appID)
+	if !ok {
+		return nil, fmt.Errorf("github app %q is not configured", appID)
+	}
+	if client, ok := a.clients[appID]; ok && a.keys[appID] == app.PrivateKey {
+		return client, nil
+	}
+	client, err := NewGitHubAppClient(app.ID, app.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	a.clients[appID] = client
+	a.keys[appID] = app.PrivateKey
+	return client, nil
+}
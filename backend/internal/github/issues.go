@@ -0,0 +1,134 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrIssueGone is returned when an issue no longer exists upstream (deleted or transferred away).
+var ErrIssueGone = fmt.Errorf("issue gone")
+
+// GetIssue fetches a single issue.
+func (c *Client) GetIssue(ctx context.Context, accessToken string, fullName string, issueNumber int) (IssueListItem, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues/" + fmt.Sprintf("%d", issueNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return IssueListItem{}, ErrIssueGone
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return IssueListItem{}, parseGitHubAPIError(resp)
+	}
+
+	var it IssueListItem
+	if err := json.NewDecoder(resp.Body).Decode(&it); err != nil {
+		return IssueListItem{}, err
+	}
+	return it, nil
+}
+
+// UpdateIssueState closes or reopens an issue. state is "open" or "closed"; stateReason is "completed" or
+// "not_planned" when closing and "reopened" when reopening ("" leaves it to GitHub). Requires issues write
+// permission.
+func (c *Client) UpdateIssueState(ctx context.Context, accessToken string, fullName string, issueNumber int, state string, stateReason string) error {
+	if issueNumber <= 0 || (state != "open" && state != "closed") {
+		return fmt.Errorf("invalid issue number or state")
+	}
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues/" + fmt.Sprintf("%d", issueNumber)
+	payload := map[string]string{"state": state}
+	if stateReason != "" {
+		payload["state_reason"] = stateReason
+	}
+	b, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return ErrIssueGone
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseGitHubAPIError(resp)
+	}
+	return nil
+}
+
+// CreateIssue opens an issue. Requires issues write permission.
+func (c *Client) CreateIssue(ctx context.Context, accessToken string, fullName string, title string, body string) (IssueListItem, error) {
+	if title == "" {
+		return IssueListItem{}, fmt.Errorf("issue title is required")
+	}
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues"
+	b, _ := json.Marshal(map[string]string{"title": title, "body": body})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return IssueListItem{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return IssueListItem{}, parseGitHubAPIError(resp)
+	}
+	var it IssueListItem
+	if err := json.NewDecoder(resp.Body).Decode(&it); err != nil {
+		return IssueListItem{}, err
+	}
+	return it, nil
+}
@@ -115,3 +115,59 @@ func (c *Client) DeleteIssueComment(ctx context.Context, accessToken string, ful
 }
 
 
+
+// UpdateIssueComment replaces the body of an existing issue comment. The accessToken must belong to the
+// comment author (for bot comments, an installation token of the same GitHub App).
+func (c *Client) UpdateIssueComment(ctx context.Context, accessToken string, fullName string, commentID int64, body string) (IssueComment, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return IssueComment{}, err
+	}
+	if strings.TrimSpace(accessToken) == "" {
+		return IssueComment{}, fmt.Errorf("missing github access token")
+	}
+	if commentID <= 0 {
+		return IssueComment{}, fmt.Errorf("invalid comment id")
+	}
+	if strings.TrimSpace(body) == "" {
+		return IssueComment{}, fmt.Errorf("comment body is required")
+	}
+
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues/comments/" + fmt.Sprintf("%d", commentID)
+	b, _ := json.Marshal(map[string]string{"body": body})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, u, bytes.NewReader(b))
+	if err != nil {
+		return IssueComment{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return IssueComment{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return IssueComment{}, parseGitHubAPIError(resp)
+	}
+
+	var out issueCommentCreateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return IssueComment{}, err
+	}
+	return IssueComment{
+		ID:   out.ID,
+		Body: out.Body,
+		User: struct {
+			Login string `json:"login"`
+		}{Login: out.User.Login},
+		CreatedAt: out.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: out.UpdatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Label is a repository label. Color is hex without the leading '#'.
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+// AddIssueLabels adds labels to an issue; GitHub creates missing ones with its default color. Requires
+// issues write permission.
+func (c *Client) AddIssueLabels(ctx context.Context, accessToken string, fullName string, issueNumber int, names []string) error {
+	if issueNumber <= 0 || len(names) == 0 {
+		return fmt.Errorf("invalid issue number or labels")
+	}
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues/" + fmt.Sprintf("%d", issueNumber) + "/labels"
+	b, _ := json.Marshal(map[string][]string{"labels": names})
+	return c.sendLabels(ctx, accessToken, http.MethodPost, u, b)
+}
+
+// RemoveIssueLabel removes one label from an issue. Removing a label the issue does not have is not an
+// error.
+func (c *Client) RemoveIssueLabel(ctx context.Context, accessToken string, fullName string, issueNumber int, name string) error {
+	if issueNumber <= 0 || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid issue number or label")
+	}
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/issues/" + fmt.Sprintf("%d", issueNumber) + "/labels/" + url.PathEscape(name)
+	err = c.sendLabels(ctx, accessToken, http.MethodDelete, u, nil)
+	var apiErr *GitHubAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Message == "Label does not exist" {
+		return nil
+	}
+	return err
+}
+
+// CreateLabel creates a repository label. It returns false without error when a label with that name
+// already exists (GitHub compares names case-insensitively).
+func (c *Client) CreateLabel(ctx context.Context, accessToken string, fullName string, label Label) (bool, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return false, err
+	}
+	u := "https://api.github.com/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/labels"
+	b, _ := json.Marshal(label)
+	err = c.sendLabels(ctx, accessToken, http.MethodPost, u, b)
+	var apiErr *GitHubAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity && strings.Contains(apiErr.Body, "already_exists") {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Client) sendLabels(ctx context.Context, accessToken string, method string, u string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseGitHubAPIError(resp)
+	}
+	return nil
+}
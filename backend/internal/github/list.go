@@ -11,13 +11,15 @@ import (
 )
 
 type IssueListItem struct {
-	ID      int64  `json:"id"`
-	Number  int    `json:"number"`
-	State   string `json:"state"`
-	Title   string `json:"title"`
-	Body    string `json:"body"`
-	HTMLURL string `json:"html_url"`
-	User    struct {
+	ID     int64  `json:"id"`
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	// StateReason is completed, not_planned or reopened (null for issues never closed).
+	StateReason *string `json:"state_reason"`
+	Title       string  `json:"title"`
+	Body        string  `json:"body"`
+	HTMLURL     string  `json:"html_url"`
+	User        struct {
 		Login string `json:"login"`
 	} `json:"user"`
 	Assignees []struct {
@@ -27,7 +29,7 @@ type IssueListItem struct {
 		Name  string `json:"name"`
 		Color string `json:"color"`
 	} `json:"labels"`
-	Comments int `json:"comments"` // Comments count
+	Comments  int     `json:"comments"` // Comments count
 	CreatedAt *string `json:"created_at"`
 	UpdatedAt *string `json:"updated_at"`
 	ClosedAt  *string `json:"closed_at"`
@@ -45,8 +47,9 @@ type PRListItem struct {
 	User    struct {
 		Login string `json:"login"`
 	} `json:"user"`
-	Merged   bool    `json:"merged"`
-	MergedAt *string `json:"merged_at"`
+	Draft     bool    `json:"draft"`
+	Merged    bool    `json:"merged"`
+	MergedAt  *string `json:"merged_at"`
 	CreatedAt *string `json:"created_at"`
 	UpdatedAt *string `json:"updated_at"`
 	ClosedAt  *string `json:"closed_at"`
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PRStats is the size of a pull request's diff. The list endpoint leaves it out; only a single pull
+// request (or a pull_request webhook) carries it.
+type PRStats struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// GetPRStats fetches a single pull request for its diff stats.
+func (c *Client) GetPRStats(ctx context.Context, accessToken string, fullName string, prNumber int) (PRStats, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return PRStats{}, err
+	}
+	u := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", url.PathEscape(owner), url.PathEscape(repo), prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return PRStats{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return PRStats{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return PRStats{}, parseGitHubAPIError(resp)
+	}
+	var st PRStats
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return PRStats{}, err
+	}
+	return st, nil
+}
+
+// PRFile is a file changed by a pull request. Patch is the unified diff hunk text; GitHub leaves it out
+// for binary and very large files.
+type PRFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
+}
+
+// ListPRFiles fetches the files a pull request changes (first 100).
+func (c *Client) ListPRFiles(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRFile, error) {
+	var out []PRFile
+	if err := c.getPRList(ctx, accessToken, fullName, prNumber, "files", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -19,14 +19,19 @@ type Repo struct {
 		Login     string `json:"login"`
 		AvatarURL string `json:"avatar_url"`
 	} `json:"owner"`
-	FullName        string `json:"full_name"`
-	HTMLURL         string `json:"html_url"`
-	Homepage        string `json:"homepage"`
-	Private         bool   `json:"private"`
-	StargazersCount int    `json:"stargazers_count"`
-	ForksCount      int    `json:"forks_count"`
-	OpenIssuesCount int    `json:"open_issues_count"`
-	Description     string `json:"description"`
+	FullName        string   `json:"full_name"`
+	HTMLURL         string   `json:"html_url"`
+	Homepage        string   `json:"homepage"`
+	Private         bool     `json:"private"`
+	StargazersCount int      `json:"stargazers_count"`
+	ForksCount      int      `json:"forks_count"`
+	OpenIssuesCount int      `json:"open_issues_count"`
+	Description     string   `json:"description"`
+	Topics          []string `json:"topics"`
+	License         *struct {
+		SPDXID string `json:"spdx_id"`
+		Name   string `json:"name"`
+	} `json:"license"`
 	Permissions struct {
 		Admin bool `json:"admin"`
 		Push  bool `json:"push"`
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PRReview is a submitted review on a pull request.
+type PRReview struct {
+	ID   int64 `json:"id"`
+	User struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"user"`
+	Body        string  `json:"body"`
+	State       string  `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED, PENDING
+	SubmittedAt *string `json:"submitted_at"`
+}
+
+// PRReviewComment is an inline (diff) comment on a pull request.
+type PRReviewComment struct {
+	ID                  int64  `json:"id"`
+	PullRequestReviewID *int64 `json:"pull_request_review_id"`
+	User                struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"user"`
+	Body      string `json:"body"`
+	Path      string `json:"path"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ListPRReviews fetches the reviews on a pull request (first 100).
+func (c *Client) ListPRReviews(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRReview, error) {
+	var out []PRReview
+	if err := c.getPRList(ctx, accessToken, fullName, prNumber, "reviews", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListPRReviewComments fetches the inline review comments on a pull request (first 100).
+func (c *Client) ListPRReviewComments(ctx context.Context, accessToken string, fullName string, prNumber int) ([]PRReviewComment, error) {
+	var out []PRReviewComment
+	if err := c.getPRList(ctx, accessToken, fullName, prNumber, "comments", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *Client) getPRList(ctx context.Context, accessToken string, fullName string, prNumber int, kind string, out any) error {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return err
+	}
+	u, _ := url.Parse(fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/%s",
+		url.PathEscape(owner), url.PathEscape(repo), prNumber, kind))
+	q := u.Query()
+	q.Set("per_page", "100")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("github list pr %s failed: status %d", kind, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
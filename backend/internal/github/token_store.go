@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
 	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
 )
 
@@ -46,6 +47,7 @@ WHERE user_id = $1
 	if err != nil {
 		return LinkedAccount{}, fmt.Errorf("decrypt github token failed")
 	}
+	apiusage.Label(string(tokenBytes), apiusage.KindUser, login)
 
 	return LinkedAccount{
 		GitHubUserID: githubUserID,
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
+	"github.com/jagadeesh/grainlify/backend/internal/dryrun"
+)
+
+var (
+	baseMu        sync.RWMutex
+	baseTransport http.RoundTripper
+)
+
+// UseTransport sends every GitHub request (from clients created before or after the call) through rt
+// instead of the network, and returns a func that restores the previous transport. It exists for tests:
+// see githubtest.New, which points it at a fake GitHub server.
+func UseTransport(rt http.RoundTripper) (restore func()) {
+	baseMu.Lock()
+	prev := baseTransport
+	baseTransport = rt
+	baseMu.Unlock()
+	return func() {
+		baseMu.Lock()
+		baseTransport = prev
+		baseMu.Unlock()
+	}
+}
+
+// hookTransport resolves the base transport per request, so swapping it affects long-lived clients too.
+type hookTransport struct{}
+
+func (hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	baseMu.RLock()
+	rt := baseTransport
+	baseMu.RUnlock()
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return rt.RoundTrip(req)
+}
+
+// newHTTPClient is the HTTP client behind every API client: dry-run interception, then usage accounting,
+// then the (swappable) network transport.
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second, Transport: dryrun.Transport(apiusage.Transport(hookTransport{}))}
+}
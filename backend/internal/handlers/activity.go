@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/activity"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// ActivityHandler serves the curated activity feed of a project or an ecosystem.
+type ActivityHandler struct {
+	db *db.DB
+}
+
+func NewActivityHandler(d *db.DB) *ActivityHandler {
+	return &ActivityHandler{db: d}
+}
+
+// Project returns the activity feed of a verified project.
+func (h *ActivityHandler) Project() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		var exists bool
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL)
+`, projectID).Scan(&exists)
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		return respondActivity(c, h.db, activity.Filter{ProjectID: &projectID})
+	}
+}
+
+// Ecosystem returns the combined activity feed of an active ecosystem's verified projects.
+func (h *ActivityHandler) Ecosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+		}
+		var exists bool
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM ecosystems WHERE id = $1 AND status = 'active')
+`, ecoID).Scan(&exists)
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		return respondActivity(c, h.db, activity.Filter{EcosystemID: &ecoID})
+	}
+}
+
+// respondActivity applies the shared query parameters (types, limit, offset) and writes one page of the feed.
+func respondActivity(c *fiber.Ctx, d *db.DB, f activity.Filter) error {
+	if raw := strings.TrimSpace(c.Query("types")); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if !activity.ValidType(t) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_activity_type", "type": t, "valid_types": activity.Types})
+			}
+			f.Types = append(f.Types, t)
+		}
+	}
+	f.Limit = 30
+	if l := c.QueryInt("limit", 30); l > 0 && l <= 100 {
+		f.Limit = l
+	}
+	f.Offset = c.QueryInt("offset", 0)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+
+	items, hasMore, err := activity.List(c.Context(), d.Pool, f)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "activity_list_failed"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"items":    items,
+		"limit":    f.Limit,
+		"offset":   f.Offset,
+		"has_more": hasMore,
+	})
+}
@@ -23,6 +23,34 @@ func NewAdminHandler(cfg config.Config, d *db.DB) *AdminHandler {
 	return &AdminHandler{cfg: cfg, db: d}
 }
 
+// RequireScope lets admins and users granted scope through. It reads the scopes from the database rather
+// than the token, so revoking one takes effect on the next request.
+func (h *AdminHandler) RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals(auth.LocalRole).(string)
+		if role == "admin" {
+			return c.Next()
+		}
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var granted []string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT admin_scopes FROM users WHERE id = $1`, userID).Scan(&granted)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "scope_lookup_failed"})
+		}
+		if !auth.HasScope(role, granted, scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "insufficient_scope", "required_scope": scope})
+		}
+		return c.Next()
+	}
+}
+
 func (h *AdminHandler) ListUsers() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -30,7 +58,7 @@ func (h *AdminHandler) ListUsers() fiber.Handler {
 		}
 
 		rows, err := h.db.Pool.Query(c.Context(), `
-SELECT id, role, github_user_id, created_at, updated_at
+SELECT id, role, admin_scopes, github_user_id, created_at, updated_at
 FROM users
 ORDER BY created_at DESC
 LIMIT 50
@@ -44,14 +72,16 @@ LIMIT 50
 		for rows.Next() {
 			var id uuid.UUID
 			var role string
+			var scopes []string
 			var ghID *int64
 			var createdAt, updatedAt time.Time
-			if err := rows.Scan(&id, &role, &ghID, &createdAt, &updatedAt); err != nil {
+			if err := rows.Scan(&id, &role, &scopes, &ghID, &createdAt, &updatedAt); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "users_list_failed"})
 			}
 			out = append(out, fiber.Map{
 				"id":             id.String(),
 				"role":           role,
+				"admin_scopes":   scopes,
 				"github_user_id": ghID,
 				"created_at":     createdAt,
 				"updated_at":     updatedAt,
@@ -66,6 +96,8 @@ type setRoleRequest struct {
 	Role string `json:"role"`
 }
 
+// SetUserRole changes a user's role. Holders of users:write who aren't admins can't grant the admin role
+// or change an admin's role.
 func (h *AdminHandler) SetUserRole() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -83,20 +115,77 @@ func (h *AdminHandler) SetUserRole() fiber.Handler {
 		if role != "contributor" && role != "maintainer" && role != "admin" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_role"})
 		}
+		if callerRole, _ := c.Locals(auth.LocalRole).(string); callerRole != "admin" {
+			var current string
+			err := h.db.Pool.QueryRow(c.Context(), `SELECT role FROM users WHERE id = $1`, userID).Scan(&current)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "role_update_failed"})
+			}
+			if role == "admin" || current == "admin" {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "admin_role_requires_admin"})
+			}
+		}
 		ct, err := h.db.Pool.Exec(c.Context(), `
 UPDATE users SET role = $2, updated_at = now()
 WHERE id = $1
 `, userID, role)
-		if errors.Is(err, pgx.ErrNoRows) || ct.RowsAffected() == 0 {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
-		}
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "role_update_failed"})
 		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
+		}
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
 	}
 }
 
+type setScopesRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+// SetUserScopes replaces a user's admin scopes. Admin only: scopes can't be used to hand out scopes.
+func (h *AdminHandler) SetUserScopes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_user_id"})
+		}
+		var req setScopesRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		scopes := []string{}
+		seen := map[string]bool{}
+		for _, s := range req.Scopes {
+			s = strings.TrimSpace(s)
+			if !auth.ValidScope(s) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_scope", "scope": s, "valid_scopes": auth.Scopes})
+			}
+			if !seen[s] {
+				seen[s] = true
+				scopes = append(scopes, s)
+			}
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE users SET admin_scopes = $2, updated_at = now()
+WHERE id = $1
+`, userID, scopes)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "scopes_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "admin_scopes": scopes})
+	}
+}
+
 // BootstrapAdmin promotes the currently authenticated user to admin if they know the bootstrap token.
 // This allows any authenticated user with the correct bootstrap token to become an admin.
 //
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,15 +12,20 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/ecocontent"
+	"github.com/jagadeesh/grainlify/backend/internal/moderation"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
 )
 
 type EcosystemsAdminHandler struct {
-	db *db.DB
+	cfg config.Config
+	db  *db.DB
 }
 
-func NewEcosystemsAdminHandler(d *db.DB) *EcosystemsAdminHandler {
-	return &EcosystemsAdminHandler{db: d}
+func NewEcosystemsAdminHandler(cfg config.Config, d *db.DB) *EcosystemsAdminHandler {
+	return &EcosystemsAdminHandler{cfg: cfg, db: d}
 }
 
 func (h *EcosystemsAdminHandler) List() fiber.Handler {
@@ -110,53 +117,97 @@ func (h *EcosystemsAdminHandler) GetByID() fiber.Handler {
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
 		}
-		var id uuid.UUID
-		var slug, name, status string
-		var desc, website, logoURL, about *string
-		var linksJSON, keyAreasJSON, technologiesJSON []byte
-		var createdAt, updatedAt time.Time
-		err = h.db.Pool.QueryRow(c.Context(), `
-SELECT e.id, e.slug, e.name, e.description, e.website_url, e.logo_url, e.status, e.created_at, e.updated_at,
-       e.about, e.links, e.key_areas, e.technologies
-FROM ecosystems e
-WHERE e.id = $1
-`, ecoID).Scan(&id, &slug, &name, &desc, &website, &logoURL, &status, &createdAt, &updatedAt, &about, &linksJSON, &keyAreasJSON, &technologiesJSON)
+		eco, updatedAt, err := h.ecosystem(c.Context(), ecoID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
 		if err != nil {
-			if err.Error() == "no rows in result set" {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
-			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
 		}
-		var links, keyAreas, technologies interface{}
-		if len(linksJSON) > 0 {
-			_ = json.Unmarshal(linksJSON, &links)
-		}
-		if len(keyAreasJSON) > 0 {
-			_ = json.Unmarshal(keyAreasJSON, &keyAreas)
-		}
-		if len(technologiesJSON) > 0 {
-			_ = json.Unmarshal(technologiesJSON, &technologies)
-		}
-		var projectCnt, userCnt int64
-		_ = h.db.Pool.QueryRow(c.Context(), `SELECT COUNT(p.id), COUNT(DISTINCT p.owner_user_id) FROM projects p WHERE p.ecosystem_id = $1`, ecoID).Scan(&projectCnt, &userCnt)
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"id":             id.String(),
-			"slug":           slug,
-			"name":           name,
-			"description":    desc,
-			"website_url":    website,
-			"logo_url":       logoURL,
-			"status":         status,
-			"created_at":     createdAt,
-			"updated_at":     updatedAt,
-			"about":          about,
-			"links":          links,
-			"key_areas":      keyAreas,
-			"technologies":   technologies,
-			"project_count":  projectCnt,
-			"user_count":     userCnt,
-		})
+		c.Set(fiber.HeaderETag, ecosystemETag(updatedAt))
+		return c.Status(fiber.StatusOK).JSON(eco)
+	}
+}
+
+// ecosystem loads the admin view of an ecosystem and its updated_at.
+func (h *EcosystemsAdminHandler) ecosystem(ctx context.Context, ecoID uuid.UUID) (fiber.Map, time.Time, error) {
+	var id uuid.UUID
+	var slug, name, status string
+	var desc, website, logoURL, about *string
+	var maxAssignments *int
+	var githubAppID, programID, rewardCurrency *string
+	var requiresTaxInfo bool
+	var linksJSON, keyAreasJSON, technologiesJSON []byte
+	var createdAt, updatedAt time.Time
+	var projectCnt, userCnt int64
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT e.id, e.slug, e.name, e.description, e.website_url, e.logo_url, e.status, e.created_at, e.updated_at,
+       e.about, e.links, e.key_areas, e.technologies, e.max_concurrent_assignments,
+       e.github_app_id, e.program_id, e.reward_currency, e.requires_tax_info,
+       pc.project_count, pc.user_count
+FROM ecosystems e
+CROSS JOIN LATERAL (
+  SELECT COUNT(p.id) AS project_count, COUNT(DISTINCT p.owner_user_id) AS user_count
+  FROM projects p WHERE p.ecosystem_id = e.id
+) pc
+WHERE e.id = $1
+`, ecoID).Scan(&id, &slug, &name, &desc, &website, &logoURL, &status, &createdAt, &updatedAt, &about, &linksJSON, &keyAreasJSON, &technologiesJSON, &maxAssignments, &githubAppID, &programID, &rewardCurrency, &requiresTaxInfo, &projectCnt, &userCnt)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var links, keyAreas, technologies interface{}
+	if len(linksJSON) > 0 {
+		_ = json.Unmarshal(linksJSON, &links)
+	}
+	if len(keyAreasJSON) > 0 {
+		_ = json.Unmarshal(keyAreasJSON, &keyAreas)
+	}
+	if len(technologiesJSON) > 0 {
+		_ = json.Unmarshal(technologiesJSON, &technologies)
+	}
+	return fiber.Map{
+		"id":                         id.String(),
+		"slug":                       slug,
+		"name":                       name,
+		"description":                desc,
+		"website_url":                website,
+		"logo_url":                   logoURL,
+		"status":                     status,
+		"created_at":                 createdAt,
+		"updated_at":                 updatedAt,
+		"about":                      about,
+		"links":                      links,
+		"key_areas":                  keyAreas,
+		"technologies":               technologies,
+		"max_concurrent_assignments": maxAssignments,
+		"github_app_id":              githubAppID,
+		"program_id":                 programID,
+		"reward_currency":            rewardCurrency,
+		"requires_tax_info":          requiresTaxInfo,
+		"project_count":              projectCnt,
+		"user_count":                 userCnt,
+	}, updatedAt, nil
+}
+
+// ecosystemETag is the ETag of an ecosystem version; it changes whenever updated_at does.
+func ecosystemETag(updatedAt time.Time) string {
+	return `W/"` + strconv.FormatInt(updatedAt.UnixMicro(), 10) + `"`
+}
+
+// ecosystemPrecondition is the updated_at an update expects, from If-Match (an ETag from GetByID) or the
+// body's updated_at; nil means unconditional. ok is false for an If-Match that isn't one of ours.
+func ecosystemPrecondition(ifMatch string, bodyUpdatedAt *time.Time) (expected *time.Time, ok bool) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return bodyUpdatedAt, true
+	}
+	v := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	micros, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, false
 	}
+	t := time.UnixMicro(micros).UTC()
+	return &t, true
 }
 
 type ecosystemUpsertRequest struct {
@@ -170,6 +221,76 @@ type ecosystemUpsertRequest struct {
 	Links        json.RawMessage `json:"links"`        // [{"label":"...","url":"..."}]
 	KeyAreas     json.RawMessage `json:"key_areas"`     // [{"title":"...","description":"..."}]
 	Technologies json.RawMessage `json:"technologies"` // ["..."]
+	// Cap on open issues one contributor can be assigned within this ecosystem; 0 clears it (platform default).
+	MaxConcurrentAssignments *int `json:"max_concurrent_assignments"`
+	// Branded GitHub App (one of GITHUB_APPS) the ecosystem's bot acts as; "" switches back to the default app.
+	GitHubAppID *string `json:"github_app_id"`
+	// Program escrow id (as in on-chain events) whose payouts count as this ecosystem's rewards; "" clears it.
+	ProgramID *string `json:"program_id"`
+}
+
+const (
+	maxEcosystemNameLen        = 200
+	maxEcosystemDescriptionLen = 1000
+)
+
+// validateEcosystemText checks the free-text and URL fields shared by Create, Update and Import, normalising
+// the URLs in place. Blank or nil (left unchanged) passes.
+func validateEcosystemText(v *validate.Validator, description, websiteURL, logoURL, about *string) {
+	if description != nil {
+		v.MaxLen("description", *description, maxEcosystemDescriptionLen)
+	}
+	if websiteURL != nil {
+		v.URL("website_url", websiteURL)
+	}
+	if logoURL != nil {
+		v.URL("logo_url", logoURL)
+	}
+	if about != nil {
+		v.MaxLen("about", *about, ecocontent.MaxBodyLen)
+	}
+}
+
+// moderateEcosystemText moderates the description and about text shown on the public ecosystem pages; nil
+// means unchanged.
+func (h *EcosystemsAdminHandler) moderateEcosystemText(ctx context.Context, description, about *string) fiber.Map {
+	for _, f := range []struct {
+		name string
+		text *string
+	}{{"description", description}, {"about", about}} {
+		if f.text == nil {
+			continue
+		}
+		if out := moderate(ctx, h.cfg, moderation.KindEcosystemDescription, f.name, *f.text); out != nil {
+			return out
+		}
+	}
+	return nil
+}
+
+// ecosystemPatchRequest is the body of Update, a JSON Merge Patch: omitted fields keep their value, null
+// (or "" for text) clears a field. Name and status can be changed but not cleared.
+type ecosystemPatchRequest struct {
+	Name         patchField[string]          `json:"name"`
+	Description  patchField[string]          `json:"description"`
+	WebsiteURL   patchField[string]          `json:"website_url"`
+	LogoURL      patchField[string]          `json:"logo_url"`
+	Status       patchField[string]          `json:"status"`
+	About        patchField[string]          `json:"about"`
+	Links        patchField[json.RawMessage] `json:"links"`
+	KeyAreas     patchField[json.RawMessage] `json:"key_areas"`
+	Technologies patchField[json.RawMessage] `json:"technologies"`
+	// 0 or null clears the cap (platform default).
+	MaxConcurrentAssignments patchField[int]    `json:"max_concurrent_assignments"`
+	GitHubAppID              patchField[string] `json:"github_app_id"`
+	ProgramID                patchField[string] `json:"program_id"`
+	// Currency code (see /admin/currencies) the ecosystem's rewards are paid in; "" or null clears it.
+	RewardCurrency patchField[string] `json:"reward_currency"`
+	// Fiat payouts in the ecosystem need the recipient's verified tax information; null turns it off.
+	RequiresTaxInfo patchField[bool] `json:"requires_tax_info"`
+	// UpdatedAt, as last read, makes Update fail with 409 if someone changed the ecosystem since (like
+	// If-Match). Not a patched field.
+	UpdatedAt *time.Time `json:"updated_at"`
 }
 
 func (h *EcosystemsAdminHandler) Create() fiber.Handler {
@@ -182,33 +303,31 @@ func (h *EcosystemsAdminHandler) Create() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
 		}
 		name := strings.TrimSpace(req.Name)
-		if name == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_required"})
-		}
-		// Auto-generate slug from name (users never see/type slug)
-		slug := normalizeSlug(name)
-		if slug == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_must_contain_valid_characters"})
-		}
 		status := strings.TrimSpace(req.Status)
 		if status == "" {
 			status = "active"
 		}
-		if status != "active" && status != "inactive" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_status"})
+		var v validate.Validator
+		if v.Required("name", name) {
+			v.MaxLen("name", name, maxEcosystemNameLen)
 		}
-
-		linksJSON := req.Links
-		if len(linksJSON) == 0 {
-			linksJSON = []byte("[]")
+		v.OneOf("status", status, "active", "inactive")
+		description, websiteURL := strings.TrimSpace(req.Description), strings.TrimSpace(req.WebsiteURL)
+		logoURL, about := strings.TrimSpace(req.LogoURL), strings.TrimSpace(req.About)
+		validateEcosystemText(&v, &description, &websiteURL, &logoURL, &about)
+		linksJSON := ecocontent.ValidateItems(&v, "links", ecocontent.Links, req.Links)
+		keyAreasJSON := ecocontent.ValidateItems(&v, "key_areas", ecocontent.KeyAreas, req.KeyAreas)
+		technologiesJSON := ecocontent.ValidateItems(&v, "technologies", ecocontent.Technologies, req.Technologies)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
 		}
-		keyAreasJSON := req.KeyAreas
-		if len(keyAreasJSON) == 0 {
-			keyAreasJSON = []byte("[]")
+		if out := h.moderateEcosystemText(c.Context(), &description, &about); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
 		}
-		technologiesJSON := req.Technologies
-		if len(technologiesJSON) == 0 {
-			technologiesJSON = []byte("[]")
+		// Auto-generate slug from name (users never see/type slug)
+		slug := normalizeSlug(name)
+		if slug == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_must_contain_valid_characters"})
 		}
 
 		var id uuid.UUID
@@ -216,7 +335,7 @@ func (h *EcosystemsAdminHandler) Create() fiber.Handler {
 INSERT INTO ecosystems (slug, name, description, website_url, logo_url, status, about, links, key_areas, technologies)
 VALUES ($1, $2, NULLIF($3,''), NULLIF($4,''), NULLIF($5,''), $6, NULLIF($7,''), $8::jsonb, $9::jsonb, $10::jsonb)
 RETURNING id
-`, slug, name, strings.TrimSpace(req.Description), strings.TrimSpace(req.WebsiteURL), strings.TrimSpace(req.LogoURL), status, strings.TrimSpace(req.About), linksJSON, keyAreasJSON, technologiesJSON).Scan(&id)
+`, slug, name, description, websiteURL, logoURL, status, about, linksJSON, keyAreasJSON, technologiesJSON).Scan(&id)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_create_failed"})
 		}
@@ -224,6 +343,8 @@ RETURNING id
 	}
 }
 
+// Update serves PATCH (and, for older clients, PUT) /admin/ecosystems/:id with a JSON Merge Patch body; see
+// ecosystemPatchRequest.
 func (h *EcosystemsAdminHandler) Update() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -233,64 +354,123 @@ func (h *EcosystemsAdminHandler) Update() fiber.Handler {
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
 		}
-		var req ecosystemUpsertRequest
-		if err := c.BodyParser(&req); err != nil {
+		var req ecosystemPatchRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
 		}
 
-		name := strings.TrimSpace(req.Name)
-		status := strings.TrimSpace(req.Status)
-
-		if status != "" && status != "active" && status != "inactive" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_status"})
+		var v validate.Validator
+		var name, status *string
+		if req.Name.Set {
+			n := strings.TrimSpace(req.Name.Value)
+			if v.Required("name", n) {
+				v.MaxLen("name", n, maxEcosystemNameLen)
+			}
+			name = &n
+		}
+		if req.Status.Set {
+			st := strings.TrimSpace(req.Status.Value)
+			v.OneOf("status", st, "active", "inactive")
+			status = &st
+		}
+		description, websiteURL, logoURL, about := patchText(req.Description), patchText(req.WebsiteURL), patchText(req.LogoURL), patchText(req.About)
+		validateEcosystemText(&v, description, websiteURL, logoURL, about)
+		links, keyAreas, technologies := patchJSON(req.Links, "[]"), patchJSON(req.KeyAreas, "[]"), patchJSON(req.Technologies, "[]")
+		if links != nil {
+			links = ecocontent.ValidateItems(&v, "links", ecocontent.Links, links)
+		}
+		if keyAreas != nil {
+			keyAreas = ecocontent.ValidateItems(&v, "key_areas", ecocontent.KeyAreas, keyAreas)
+		}
+		if technologies != nil {
+			technologies = ecocontent.ValidateItems(&v, "technologies", ecocontent.Technologies, technologies)
+		}
+		var maxAssignments *int
+		if req.MaxConcurrentAssignments.Set {
+			n := req.MaxConcurrentAssignments.Value
+			v.Range("max_concurrent_assignments", n, 0, 1000)
+			maxAssignments = &n
+		}
+		githubAppID := patchText(req.GitHubAppID)
+		if githubAppID != nil && *githubAppID != "" {
+			if _, ok := h.cfg.GitHubApp(*githubAppID); !ok {
+				v.Fail("github_app_id", validate.Invalid, "is not a configured GitHub App")
+			}
+		}
+		rewardCurrency := patchText(req.RewardCurrency)
+		if rewardCurrency != nil && *rewardCurrency != "" {
+			*rewardCurrency = strings.ToUpper(*rewardCurrency)
+			var known bool
+			_ = h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM currencies WHERE code = $1)`, *rewardCurrency).Scan(&known)
+			if !known {
+				v.Fail("reward_currency", validate.Invalid, "is not a registered currency")
+			}
+		}
+		var requiresTaxInfo *bool
+		if req.RequiresTaxInfo.Set {
+			b := !req.RequiresTaxInfo.Null && req.RequiresTaxInfo.Value
+			requiresTaxInfo = &b
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		if out := h.moderateEcosystemText(c.Context(), description, about); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
 		}
 
-		// Auto-generate slug from name if name is provided
+		// Renaming regenerates the slug (users never see/type slug).
 		var slugVal *string
-		if name != "" {
-			slug := normalizeSlug(name)
+		if name != nil {
+			slug := normalizeSlug(*name)
 			if slug == "" {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_must_contain_valid_characters"})
 			}
 			slugVal = &slug
 		}
 
-		linksJSON := req.Links
-		if len(linksJSON) == 0 {
-			linksJSON = []byte("[]")
-		}
-		keyAreasJSON := req.KeyAreas
-		if len(keyAreasJSON) == 0 {
-			keyAreasJSON = []byte("[]")
-		}
-		technologiesJSON := req.Technologies
-		if len(technologiesJSON) == 0 {
-			technologiesJSON = []byte("[]")
+		expected, ok := ecosystemPrecondition(c.Get(fiber.HeaderIfMatch), req.UpdatedAt)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_if_match"})
 		}
 
-		aboutVal := strings.TrimSpace(req.About)
-		ct, err := h.db.Pool.Exec(c.Context(), `
+		// For the clearable columns a NULL parameter leaves the column alone and '' clears it.
+		var updatedAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
 UPDATE ecosystems
 SET slug = COALESCE($2, slug),
-    name = COALESCE(NULLIF($3,''), name),
-    description = COALESCE(NULLIF($4,''), description),
-    website_url = COALESCE(NULLIF($5,''), website_url),
-    logo_url = COALESCE(NULLIF($6,''), logo_url),
-    status = COALESCE(NULLIF($7,''), status),
-    about = NULLIF($8, ''),
+    name = COALESCE($3, name),
+    description = CASE WHEN $4::text IS NULL THEN description ELSE NULLIF($4::text, '') END,
+    website_url = CASE WHEN $5::text IS NULL THEN website_url ELSE NULLIF($5::text, '') END,
+    logo_url = CASE WHEN $6::text IS NULL THEN logo_url ELSE NULLIF($6::text, '') END,
+    status = COALESCE($7, status),
+    about = CASE WHEN $8::text IS NULL THEN about ELSE NULLIF($8::text, '') END,
     links = COALESCE($9::jsonb, links),
     key_areas = COALESCE($10::jsonb, key_areas),
     technologies = COALESCE($11::jsonb, technologies),
+    max_concurrent_assignments = CASE WHEN $12::int IS NULL THEN max_concurrent_assignments ELSE NULLIF($12::int, 0) END,
+    github_app_id = CASE WHEN $13::text IS NULL THEN github_app_id ELSE NULLIF($13::text, '') END,
+    program_id = CASE WHEN $14::text IS NULL THEN program_id ELSE NULLIF($14::text, '') END,
+    reward_currency = CASE WHEN $16::text IS NULL THEN reward_currency ELSE NULLIF($16::text, '') END,
+    requires_tax_info = COALESCE($17::bool, requires_tax_info),
     updated_at = now()
-WHERE id = $1
-`, ecoID, slugVal, name, strings.TrimSpace(req.Description), strings.TrimSpace(req.WebsiteURL), strings.TrimSpace(req.LogoURL), status, aboutVal, linksJSON, keyAreasJSON, technologiesJSON)
-		if errors.Is(err, pgx.ErrNoRows) || ct.RowsAffected() == 0 {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+WHERE id = $1 AND ($15::timestamptz IS NULL OR updated_at = $15)
+RETURNING updated_at
+`, ecoID, slugVal, name, description, websiteURL, logoURL, status, about,
+			links, keyAreas, technologies, maxAssignments, githubAppID, patchText(req.ProgramID), expected, rewardCurrency, requiresTaxInfo).Scan(&updatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Either gone, or changed since the caller read it: send the current version to merge against.
+			current, currentUpdatedAt, lookupErr := h.ecosystem(c.Context(), ecoID)
+			if lookupErr != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+			}
+			c.Set(fiber.HeaderETag, ecosystemETag(currentUpdatedAt))
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "ecosystem_modified", "current": current})
 		}
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_update_failed"})
 		}
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+		c.Set(fiber.HeaderETag, ecosystemETag(updatedAt))
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "updated_at": updatedAt})
 	}
 }
 
@@ -338,3 +518,17 @@ func normalizeSlug(s string) string {
 }
 
 
+
+// GitHubApps lists the GitHub Apps an ecosystem can be branded with (credentials are never returned).
+func (h *EcosystemsAdminHandler) GitHubApps() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apps := []fiber.Map{}
+		if def := h.cfg.DefaultGitHubApp(); strings.TrimSpace(def.ID) != "" {
+			apps = append(apps, fiber.Map{"id": def.ID, "slug": def.Slug, "default": true})
+		}
+		for _, app := range h.cfg.GitHubApps {
+			apps = append(apps, fiber.Map{"id": app.ID, "slug": app.Slug, "default": false})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"apps": apps})
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/ecocontent"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// ecosystemsExportVersion is bumped when the export document changes incompatibly.
+const ecosystemsExportVersion = 1
+
+type ecosystemsDocument struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Ecosystems []ecosystemRecord `json:"ecosystems"`
+}
+
+// ecosystemRecord is one ecosystem as exported; ids are left out so documents move between environments.
+type ecosystemRecord struct {
+	Slug                     string          `json:"slug"`
+	Name                     string          `json:"name"`
+	Description              *string         `json:"description"`
+	WebsiteURL               *string         `json:"website_url"`
+	LogoURL                  *string         `json:"logo_url"`
+	Status                   string          `json:"status"`
+	About                    *string         `json:"about"`
+	Links                    json.RawMessage `json:"links"`
+	KeyAreas                 json.RawMessage `json:"key_areas"`
+	Technologies             json.RawMessage `json:"technologies"`
+	MaxConcurrentAssignments *int            `json:"max_concurrent_assignments"`
+	GitHubAppID              *string         `json:"github_app_id"`
+	ProgramID                *string         `json:"program_id"`
+}
+
+// Export serves GET /admin/ecosystems/export: every ecosystem of the tenant as one JSON document that
+// Import accepts.
+func (h *EcosystemsAdminHandler) Export() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT slug, name, description, website_url, logo_url, status, about,
+       COALESCE(links, '[]'::jsonb), COALESCE(key_areas, '[]'::jsonb), COALESCE(technologies, '[]'::jsonb),
+       max_concurrent_assignments, github_app_id, program_id
+FROM ecosystems
+ORDER BY slug
+`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_export_failed"})
+		}
+		defer rows.Close()
+
+		doc := ecosystemsDocument{Version: ecosystemsExportVersion, ExportedAt: time.Now().UTC(), Ecosystems: []ecosystemRecord{}}
+		for rows.Next() {
+			var r ecosystemRecord
+			var links, keyAreas, technologies []byte
+			if err := rows.Scan(&r.Slug, &r.Name, &r.Description, &r.WebsiteURL, &r.LogoURL, &r.Status, &r.About,
+				&links, &keyAreas, &technologies, &r.MaxConcurrentAssignments, &r.GitHubAppID, &r.ProgramID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_export_failed"})
+			}
+			r.Links, r.KeyAreas, r.Technologies = links, keyAreas, technologies
+			doc.Ecosystems = append(doc.Ecosystems, r)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_export_failed"})
+		}
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="ecosystems-`+doc.ExportedAt.Format("20060102")+`.json"`)
+		return c.Status(fiber.StatusOK).JSON(doc)
+	}
+}
+
+// Import serves POST /admin/ecosystems/import: creates or updates ecosystems by slug from an Export
+// document, in one transaction. Re-importing the same document changes nothing; ecosystems missing from
+// the document are left alone. ?dry_run=true reports what would change without writing.
+func (h *EcosystemsAdminHandler) Import() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var doc ecosystemsDocument
+		if err := json.Unmarshal(c.Body(), &doc); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if doc.Version != ecosystemsExportVersion {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unsupported_version", "supported": ecosystemsExportVersion})
+		}
+
+		var warnings []string
+		seen := map[string]bool{}
+		for i := range doc.Ecosystems {
+			r := &doc.Ecosystems[i]
+			if code, fields := h.normalizeEcosystemRecord(r, &warnings); code != "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code, "index": i, "slug": r.Slug, "fields": fields})
+			}
+			if seen[r.Slug] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "duplicate_slug", "index": i, "slug": r.Slug})
+			}
+			seen[r.Slug] = true
+		}
+
+		dryRun := c.QueryBool("dry_run", false)
+		results := make([]fiber.Map, 0, len(doc.Ecosystems))
+		counts := map[string]int{"created": 0, "updated": 0, "unchanged": 0}
+		errDryRun := errors.New("dry run")
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			for _, r := range doc.Ecosystems {
+				action, err := importEcosystem(c.Context(), tx, r)
+				if err != nil {
+					return fmt.Errorf("%s: %w", r.Slug, err)
+				}
+				counts[action]++
+				results = append(results, fiber.Map{"slug": r.Slug, "action": action})
+			}
+			if dryRun {
+				return errDryRun
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errDryRun) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_import_failed"})
+		}
+		if warnings == nil {
+			warnings = []string{}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"dry_run":   dryRun,
+			"created":   counts["created"],
+			"updated":   counts["updated"],
+			"unchanged": counts["unchanged"],
+			"results":   results,
+			"warnings":  warnings,
+		})
+	}
+}
+
+// normalizeEcosystemRecord validates r in place like Create/Update do, returning an error code and, for
+// content errors, the failed fields. GitHub Apps configured in the source environment but not this one are
+// dropped with a warning rather than failing the import.
+func (h *EcosystemsAdminHandler) normalizeEcosystemRecord(r *ecosystemRecord, warnings *[]string) (string, validate.Errors) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return "name_required", nil
+	}
+	if strings.TrimSpace(r.Slug) == "" {
+		r.Slug = r.Name
+	}
+	r.Slug = normalizeSlug(r.Slug)
+	if r.Slug == "" {
+		return "invalid_slug", nil
+	}
+	r.Status = strings.TrimSpace(r.Status)
+	if r.Status == "" {
+		r.Status = "active"
+	}
+	if r.Status != "active" && r.Status != "inactive" {
+		return "invalid_status", nil
+	}
+	for _, p := range []**string{&r.Description, &r.WebsiteURL, &r.LogoURL, &r.About, &r.GitHubAppID, &r.ProgramID} {
+		if *p != nil {
+			if v := strings.TrimSpace(**p); v != "" {
+				*p = &v
+			} else {
+				*p = nil
+			}
+		}
+	}
+	var v validate.Validator
+	validateEcosystemText(&v, r.Description, r.WebsiteURL, r.LogoURL, r.About)
+	r.Links = ecocontent.ValidateItems(&v, "links", ecocontent.Links, r.Links)
+	r.KeyAreas = ecocontent.ValidateItems(&v, "key_areas", ecocontent.KeyAreas, r.KeyAreas)
+	r.Technologies = ecocontent.ValidateItems(&v, "technologies", ecocontent.Technologies, r.Technologies)
+	var errs validate.Errors
+	if errors.As(v.Err(), &errs) {
+		return errs.Code(), errs
+	}
+	if r.MaxConcurrentAssignments != nil && *r.MaxConcurrentAssignments <= 0 {
+		if *r.MaxConcurrentAssignments < 0 {
+			return "invalid_max_concurrent_assignments", nil
+		}
+		r.MaxConcurrentAssignments = nil
+	}
+	if r.GitHubAppID != nil {
+		if _, ok := h.cfg.GitHubApp(*r.GitHubAppID); !ok {
+			*warnings = append(*warnings, fmt.Sprintf("%s: github app %s is not configured here; using the default app", r.Slug, *r.GitHubAppID))
+			r.GitHubAppID = nil
+		}
+	}
+	return "", nil
+}
+
+func importEcosystem(ctx context.Context, tx pgx.Tx, r ecosystemRecord) (string, error) {
+	args := []any{r.Slug, r.Name, r.Description, r.WebsiteURL, r.LogoURL, r.Status, r.About,
+		[]byte(r.Links), []byte(r.KeyAreas), []byte(r.Technologies), r.MaxConcurrentAssignments, r.GitHubAppID, r.ProgramID}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM ecosystems WHERE slug = $1)`, r.Slug).Scan(&exists); err != nil {
+		return "", err
+	}
+	if !exists {
+		_, err := tx.Exec(ctx, `
+INSERT INTO ecosystems (slug, name, description, website_url, logo_url, status, about, links, key_areas, technologies,
+                        max_concurrent_assignments, github_app_id, program_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10::jsonb, $11, $12, $13)
+`, args...)
+		return "created", err
+	}
+
+	ct, err := tx.Exec(ctx, `
+UPDATE ecosystems
+SET name = $2, description = $3, website_url = $4, logo_url = $5, status = $6, about = $7,
+    links = $8::jsonb, key_areas = $9::jsonb, technologies = $10::jsonb,
+    max_concurrent_assignments = $11, github_app_id = $12, program_id = $13, updated_at = now()
+WHERE slug = $1
+  AND (name, description, website_url, logo_url, status, about, links, key_areas, technologies,
+       max_concurrent_assignments, github_app_id, program_id)
+      IS DISTINCT FROM
+      ($2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10::jsonb, $11::int, $12::text, $13::text)
+`, args...)
+	if err != nil {
+		return "", err
+	}
+	if ct.RowsAffected() == 0 {
+		return "unchanged", nil
+	}
+	return "updated", nil
+}
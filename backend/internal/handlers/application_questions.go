@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// ApplicationQuestionsHandler manages the per-project Q&A checklist applicants fill in
+// before a maintainer can assign them.
+type ApplicationQuestionsHandler struct {
+	db *db.DB
+}
+
+func NewApplicationQuestionsHandler(d *db.DB) *ApplicationQuestionsHandler {
+	return &ApplicationQuestionsHandler{db: d}
+}
+
+type applicationAnswer struct {
+	QuestionID string `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+// List returns the active questions for a verified project. Public so applicants can see them before applying.
+func (h *ApplicationQuestionsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT q.id, q.prompt, q.required, q.position
+FROM project_application_questions q
+JOIN projects p ON p.id = q.project_id
+WHERE q.project_id = $1 AND q.archived_at IS NULL
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+ORDER BY q.position ASC, q.created_at ASC
+`, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "questions_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var prompt string
+			var required bool
+			var position int
+			if err := rows.Scan(&id, &prompt, &required, &position); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "questions_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":       id.String(),
+				"prompt":   prompt,
+				"required": required,
+				"position": position,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"questions": out})
+	}
+}
+
+const maxQuestionPromptLen = 1000
+
+type applicationQuestionRequest struct {
+	Prompt   string `json:"prompt"`
+	Required *bool  `json:"required"`
+	Position *int   `json:"position"`
+}
+
+// Create adds a question to the project's checklist. Maintainer only.
+func (h *ApplicationQuestionsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		var req applicationQuestionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Prompt = strings.TrimSpace(req.Prompt)
+		var v validate.Validator
+		if v.Required("prompt", req.Prompt) {
+			v.MaxLen("prompt", req.Prompt, maxQuestionPromptLen)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		required := true
+		if req.Required != nil {
+			required = *req.Required
+		}
+		position := 0
+		if req.Position != nil {
+			position = *req.Position
+		} else {
+			_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT COALESCE(MAX(position) + 1, 0) FROM project_application_questions WHERE project_id = $1 AND archived_at IS NULL
+`, projectID).Scan(&position)
+		}
+
+		var id uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO project_application_questions (project_id, prompt, required, position)
+VALUES ($1, $2, $3, $4)
+RETURNING id
+`, projectID, req.Prompt, required, position).Scan(&id); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "question_create_failed"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"id":       id.String(),
+			"prompt":   req.Prompt,
+			"required": required,
+			"position": position,
+		})
+	}
+}
+
+// Update edits a question's prompt, required flag or position. Maintainer only.
+func (h *ApplicationQuestionsHandler) Update() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		questionID, err := uuid.Parse(c.Params("questionId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_question_id"})
+		}
+
+		var req applicationQuestionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		var prompt *string
+		if p := strings.TrimSpace(req.Prompt); p != "" {
+			var v validate.Validator
+			if !v.MaxLen("prompt", p, maxQuestionPromptLen) {
+				return validationFailed(c, v.Err())
+			}
+			prompt = &p
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE project_application_questions
+SET prompt = COALESCE($3, prompt),
+    required = COALESCE($4, required),
+    position = COALESCE($5, position),
+    updated_at = now()
+WHERE id = $1 AND project_id = $2 AND archived_at IS NULL
+`, questionID, projectID, prompt, req.Required, req.Position)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "question_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "question_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Delete archives a question so existing answers stay readable. Maintainer only.
+func (h *ApplicationQuestionsHandler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		questionID, err := uuid.Parse(c.Params("questionId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_question_id"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE project_application_questions SET archived_at = now(), updated_at = now()
+WHERE id = $1 AND project_id = $2 AND archived_at IS NULL
+`, questionID, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "question_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "question_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+type submitAnswersRequest struct {
+	Answers []applicationAnswer `json:"answers"`
+}
+
+// SubmitAnswers stores (or replaces) the caller's answers for an issue they are applying to.
+func (h *ApplicationQuestionsHandler) SubmitAnswers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req submitAnswersRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+
+		var exists bool
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(
+  SELECT 1 FROM github_issues gi
+  JOIN projects p ON p.id = gi.project_id
+  WHERE gi.project_id = $1 AND gi.number = $2 AND p.status = 'verified' AND p.deleted_at IS NULL
+)
+`, projectID, issueNumber).Scan(&exists); err != nil || !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+
+		if code, err := saveApplicationAnswers(c.Context(), h.db.Pool, projectID, issueNumber, userID, req.Answers); code != "" {
+			if err != nil {
+				slog.Error("failed to save application answers", "error", err, "project_id", projectID, "issue_number", issueNumber)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": code})
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+
+		missing, err := missingRequiredAnswers(c.Context(), h.db.Pool, projectID, issueNumber, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "answers_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "missing_required": uuidStrings(missing)})
+	}
+}
+
+// Answers returns answers for an issue. Maintainers see every applicant; others see only their own.
+func (h *ApplicationQuestionsHandler) Answers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		isMaintainer := owner == userID || role == "admin"
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT a.user_id, COALESCE(ga.login, ''), q.id, q.prompt, q.required, a.answer, a.updated_at
+FROM issue_application_answers a
+JOIN project_application_questions q ON q.id = a.question_id
+LEFT JOIN github_accounts ga ON ga.user_id = a.user_id
+WHERE a.project_id = $1 AND a.issue_number = $2
+  AND ($3 = true OR a.user_id = $4)
+ORDER BY a.user_id, q.position ASC, q.created_at ASC
+`, projectID, issueNumber, isMaintainer, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "answers_list_failed"})
+		}
+		defer rows.Close()
+
+		byUser := map[uuid.UUID]fiber.Map{}
+		var order []uuid.UUID
+		for rows.Next() {
+			var applicantID, questionID uuid.UUID
+			var login, prompt, answer string
+			var required bool
+			var updatedAt time.Time
+			if err := rows.Scan(&applicantID, &login, &questionID, &prompt, &required, &answer, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "answers_list_failed"})
+			}
+			entry, ok := byUser[applicantID]
+			if !ok {
+				entry = fiber.Map{"user_id": applicantID.String(), "login": login, "answers": []fiber.Map{}}
+				byUser[applicantID] = entry
+				order = append(order, applicantID)
+			}
+			entry["answers"] = append(entry["answers"].([]fiber.Map), fiber.Map{
+				"question_id": questionID.String(),
+				"prompt":      prompt,
+				"required":    required,
+				"answer":      answer,
+				"updated_at":  updatedAt,
+			})
+		}
+
+		out := make([]fiber.Map, 0, len(order))
+		for _, id := range order {
+			missing, err := missingRequiredAnswers(c.Context(), h.db.Pool, projectID, issueNumber, id)
+			if err == nil {
+				byUser[id]["missing_required"] = uuidStrings(missing)
+			}
+			out = append(out, byUser[id])
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"applicants": out})
+	}
+}
+
+func (h *ApplicationQuestionsHandler) requireMaintainer(c *fiber.Ctx) (uuid.UUID, int, string) {
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if owner != userID && role != "admin" {
+		return uuid.Nil, fiber.StatusForbidden, "forbidden"
+	}
+	return projectID, 0, ""
+}
+
+// saveApplicationAnswers upserts answers for the given applicant. It returns a non-empty error
+// code on failure; err is only set for unexpected database errors.
+func saveApplicationAnswers(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, userID uuid.UUID, answers []applicationAnswer) (string, error) {
+	for _, a := range answers {
+		qid, err := uuid.Parse(strings.TrimSpace(a.QuestionID))
+		if err != nil {
+			return "invalid_question_id", nil
+		}
+		answer := strings.TrimSpace(a.Answer)
+		if len(answer) > 5000 {
+			return "answer_too_long", nil
+		}
+		if answer == "" {
+			if _, err := pool.Exec(ctx, `
+DELETE FROM issue_application_answers
+WHERE project_id = $1 AND issue_number = $2 AND user_id = $3 AND question_id = $4
+`, projectID, issueNumber, userID, qid); err != nil {
+				return "answers_save_failed", err
+			}
+			continue
+		}
+		ct, err := pool.Exec(ctx, `
+INSERT INTO issue_application_answers (project_id, issue_number, user_id, question_id, answer)
+SELECT $1, $2, $3, q.id, $5
+FROM project_application_questions q
+WHERE q.id = $4 AND q.project_id = $1 AND q.archived_at IS NULL
+ON CONFLICT (project_id, issue_number, user_id, question_id)
+DO UPDATE SET answer = EXCLUDED.answer, updated_at = now()
+`, projectID, issueNumber, userID, qid, answer)
+		if err != nil {
+			return "answers_save_failed", fmt.Errorf("upsert answer: %w", err)
+		}
+		if ct.RowsAffected() == 0 {
+			return "question_not_found", nil
+		}
+	}
+	return "", nil
+}
+
+// missingRequiredAnswers returns the IDs of active required questions the applicant has not answered.
+func missingRequiredAnswers(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := pool.Query(ctx, `
+SELECT q.id
+FROM project_application_questions q
+WHERE q.project_id = $1 AND q.required = true AND q.archived_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM issue_application_answers a
+    WHERE a.project_id = $1 AND a.issue_number = $2 AND a.user_id = $3 AND a.question_id = q.id
+  )
+ORDER BY q.position ASC
+`, projectID, issueNumber, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/appstage"
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+const maxStageDetailsLen = 2000
+
+type applicationStageRequest struct {
+	Stage string `json:"stage"`
+	// Details is the trial task or call arrangements (maintainer), or the submitted work (applicant).
+	Details string     `json:"details"`
+	DueAt   *time.Time `json:"due_at"`
+}
+
+// stagedApplication is what moving an application between stages needs to know about it.
+type stagedApplication struct {
+	owner       uuid.UUID
+	applicantID *uuid.UUID // the applicant's account, also for quick applies made before they signed up
+	login       string
+	status      string
+	stage       string
+	anonymous   bool
+}
+
+// loadStagedApplication looks up an application on a verified project's issue.
+func (h *IssueApplicationsHandler) loadStagedApplication(ctx context.Context, projectID uuid.UUID, issueNumber int, applicationID uuid.UUID) (stagedApplication, error) {
+	var a stagedApplication
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT p.owner_user_id,
+       COALESCE(a.user_id, (SELECT ga.user_id FROM github_accounts ga WHERE LOWER(ga.login) = LOWER(a.github_login))),
+       a.github_login, a.status, a.stage, p.anonymous_applications
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id
+WHERE a.id = $3 AND a.project_id = $1 AND a.issue_number = $2 AND p.status = 'verified' AND p.deleted_at IS NULL
+`, projectID, issueNumber, applicationID).Scan(&a.owner, &a.applicantID, &a.login, &a.status, &a.stage, &a.anonymous)
+	return a, err
+}
+
+// SetApplicationStage moves a pending application to another stage (see package appstage): the
+// maintainer asks for a trial task or a call, or records that the call happened; the applicant hands in
+// the trial task. Every move is recorded, and the other party (both, when an admin acts) is notified.
+func (h *IssueApplicationsHandler) SetApplicationStage() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		applicationID, err := uuid.Parse(c.Params("applicationId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_application_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req applicationStageRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Stage = strings.TrimSpace(req.Stage)
+		req.Details = strings.TrimSpace(req.Details)
+		var v validate.Validator
+		if v.Required("stage", req.Stage) && !appstage.Valid(req.Stage) {
+			v.Fail("stage", validate.Invalid, "is not a stage")
+		}
+		v.MaxLen("details", req.Details, maxStageDetailsLen)
+		if req.DueAt != nil {
+			if !appstage.Requested(req.Stage) {
+				v.Fail("due_at", validate.Invalid, "can only be set when requesting a trial task or a call")
+			} else if !req.DueAt.After(time.Now()) {
+				v.Fail("due_at", validate.Invalid, "must be in the future")
+			}
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		app, err := h.loadStagedApplication(c.Context(), projectID, issueNumber, applicationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "application_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_lookup_failed"})
+		}
+		maintainer := app.owner == userID || role == "admin"
+		applicant := app.applicantID != nil && *app.applicantID == userID
+		if !maintainer && !applicant {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+		if app.status != "pending" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "application_not_pending"})
+		}
+		switch appstage.Actor(app.stage, req.Stage) {
+		case appstage.Maintainer:
+			if !maintainer {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+		case appstage.Applicant:
+			if !applicant {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+		default:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invalid_stage_transition", "stage": app.stage})
+		}
+
+		err = pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			ct, err := tx.Exec(c.Context(), `
+UPDATE issue_applications
+SET stage = $3, stage_details = NULLIF($4, ''), stage_due_at = $5, stage_changed_at = now(), updated_at = now()
+WHERE id = $1 AND status = 'pending' AND stage = $2
+`, applicationID, app.stage, req.Stage, req.Details, req.DueAt)
+			if err != nil {
+				return err
+			}
+			if ct.RowsAffected() == 0 {
+				return pgx.ErrNoRows
+			}
+			_, err = tx.Exec(c.Context(), `
+INSERT INTO application_stage_events (application_id, from_stage, to_stage, details, due_at, actor_user_id)
+VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+`, applicationID, app.stage, req.Stage, req.Details, req.DueAt, userID)
+			return err
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Decided, withdrawn or moved by someone else since we looked.
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "application_modified"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_stage_update_failed"})
+		}
+
+		payload := fiber.Map{
+			"application_id": applicationID.String(),
+			"project_id":     projectID.String(),
+			"issue_number":   issueNumber,
+			"from_stage":     app.stage,
+			"stage":          req.Stage,
+			"details":        req.Details,
+			"due_at":         req.DueAt,
+		}
+		if app.applicantID != nil && *app.applicantID != userID {
+			notifyUser(c.Context(), h.db.Pool, *app.applicantID, "application_stage_changed", payload)
+		}
+		if app.owner != userID {
+			login := app.login
+			if app.anonymous {
+				login = applicantAlias(applicationID)
+			}
+			payload["github_login"] = login
+			notifyUser(c.Context(), h.db.Pool, app.owner, "application_stage_changed", payload)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "stage": req.Stage, "details": req.Details, "due_at": req.DueAt})
+	}
+}
+
+// ApplicationStages lists an application's stage transitions, oldest first. Maintainer, admin, or the
+// applicant. Each move says which side made it rather than who, so anonymous applicants stay anonymous.
+func (h *IssueApplicationsHandler) ApplicationStages() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		applicationID, err := uuid.Parse(c.Params("applicationId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_application_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		app, err := h.loadStagedApplication(c.Context(), projectID, issueNumber, applicationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "application_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_lookup_failed"})
+		}
+		if app.owner != userID && role != "admin" && (app.applicantID == nil || *app.applicantID != userID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT from_stage, to_stage, COALESCE(details, ''), due_at, created_at
+FROM application_stage_events
+WHERE application_id = $1
+ORDER BY created_at
+`, applicationID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_stages_lookup_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var from, to, details string
+			var dueAt *time.Time
+			var createdAt time.Time
+			if err := rows.Scan(&from, &to, &details, &dueAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_stages_lookup_failed"})
+			}
+			out = append(out, fiber.Map{
+				"from_stage": from,
+				"stage":      to,
+				"by":         appstage.Actor(from, to),
+				"details":    details,
+				"due_at":     dueAt,
+				"created_at": createdAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_stages_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"stage": app.stage, "events": out})
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/assets"
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// Logos are scaled to fit this many pixels per side; other uploads keep up to assetMaxDim.
+const (
+	logoMaxDim  = 512
+	assetMaxDim = 2048
+)
+
+type AssetsHandler struct {
+	cfg   config.Config
+	db    *db.DB
+	store assets.Store
+	ttl   time.Duration
+}
+
+func NewAssetsHandler(cfg config.Config, d *db.DB) *AssetsHandler {
+	signingKey := sha256.Sum256([]byte("assets:" + cfg.JWTSecret))
+	store, err := assets.NewStore(cfg.AssetStorageURL, assets.Options{
+		PublicBaseURL: cfg.PublicBaseURL,
+		SigningKey:    signingKey[:],
+		KeyID:         cfg.AssetStorageKeyID,
+		Secret:        cfg.AssetStorageSecret,
+	})
+	if err != nil {
+		slog.Warn("asset storage disabled", "error", err)
+	}
+	ttl := time.Duration(cfg.AssetURLTTLMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return &AssetsHandler{cfg: cfg, db: d, store: store, ttl: ttl}
+}
+
+// Upload serves POST /admin/assets: stores an image (multipart field "file") and returns its permanent
+// URL. ?kind= groups uploads (letters, digits and dashes; default "images").
+func (h *AssetsHandler) Upload() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		kind := strings.ToLower(strings.TrimSpace(c.Query("kind", "images")))
+		if !assets.ValidKey(kind + "/x.png") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_kind"})
+		}
+		asset, status, code := h.save(c, kind, assetMaxDim)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		return c.Status(fiber.StatusCreated).JSON(asset)
+	}
+}
+
+// EcosystemLogo serves POST /admin/ecosystems/:id/logo: uploads a logo and points logo_url at it.
+func (h *AssetsHandler) EcosystemLogo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+		}
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var exists bool
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS (SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+		}
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		asset, status, code := h.save(c, "ecosystem-logos", logoMaxDim)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE ecosystems SET logo_url = $2, updated_at = now() WHERE id = $1
+`, ecoID, asset["url"]); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(asset)
+	}
+}
+
+// ProjectLogo serves POST /projects/:id/logo for the project's maintainers (owner or admin).
+func (h *AssetsHandler) ProjectLogo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if owner != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		asset, status, code := h.save(c, "project-logos", logoMaxDim)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE projects SET logo_url = $2, updated_at = now() WHERE id = $1
+`, projectID, asset["url"]); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(asset)
+	}
+}
+
+// Serve serves GET /assets/*: redirects the permanent URL to a short-lived signed one. For local storage
+// the signed URL is this same route with expires/sig, which streams the file.
+func (h *AssetsHandler) Serve() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.store == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "asset_storage_not_configured"})
+		}
+		key := c.Params("*")
+		if !assets.ValidKey(key) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "asset_not_found"})
+		}
+
+		if local, ok := h.store.(*assets.LocalStore); ok && c.Query("sig") != "" {
+			if !local.Verify(key, c.Query("expires"), c.Query("sig"), time.Now()) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "invalid_signature"})
+			}
+			f, err := local.Open(key)
+			if errors.Is(err, assets.ErrNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "asset_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "asset_read_failed"})
+			}
+			defer f.Close()
+			data, err := io.ReadAll(f)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "asset_read_failed"})
+			}
+			// Keys are content hashes, so the object behind a URL never changes.
+			c.Set("Cache-Control", "public, max-age=31536000, immutable")
+			c.Set("X-Content-Type-Options", "nosniff")
+			c.Set(fiber.HeaderContentType, assets.ContentType(key))
+			return c.Send(data)
+		}
+
+		signed, err := h.store.SignedURL(c.Context(), key, h.ttl)
+		if err != nil {
+			slog.Error("failed to sign asset url", "key", key, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "asset_sign_failed"})
+		}
+		// Let clients reuse the redirect for half the signature's lifetime.
+		c.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.ttl.Seconds())/2))
+		return c.Redirect(signed, fiber.StatusFound)
+	}
+}
+
+// save processes the uploaded "file" and stores it under kind, returning the asset description.
+func (h *AssetsHandler) save(c *fiber.Ctx, kind string, maxDim int) (fiber.Map, int, string) {
+	if h.store == nil {
+		return nil, fiber.StatusServiceUnavailable, "asset_storage_not_configured"
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, fiber.StatusBadRequest, "file_required"
+	}
+	if fh.Size > assets.MaxUploadBytes {
+		return nil, fiber.StatusRequestEntityTooLarge, "file_too_large"
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, fiber.StatusBadRequest, "file_unreadable"
+	}
+	data, err := io.ReadAll(io.LimitReader(f, assets.MaxUploadBytes+1))
+	f.Close()
+	if err != nil {
+		return nil, fiber.StatusBadRequest, "file_unreadable"
+	}
+
+	img, err := assets.Process(data, maxDim)
+	switch {
+	case errors.Is(err, assets.ErrTooLarge):
+		return nil, fiber.StatusRequestEntityTooLarge, "image_too_large"
+	case errors.Is(err, assets.ErrUnsupported):
+		return nil, fiber.StatusUnsupportedMediaType, "unsupported_image_format"
+	case err != nil:
+		return nil, fiber.StatusInternalServerError, "image_processing_failed"
+	}
+
+	key := assets.NewKey(kind, img.Data, img.ContentType)
+	ctx, cancel := context.WithTimeout(c.Context(), 60*time.Second)
+	defer cancel()
+	if err := h.store.Put(ctx, key, img.ContentType, img.Data); err != nil {
+		slog.Error("failed to store asset", "key", key, "error", err)
+		return nil, fiber.StatusBadGateway, "asset_store_failed"
+	}
+
+	var uploadedBy *uuid.UUID
+	if sub, _ := c.Locals(auth.LocalUserID).(string); sub != "" {
+		if id, err := uuid.Parse(sub); err == nil {
+			uploadedBy = &id
+		}
+	}
+	if h.db != nil && h.db.Pool != nil {
+		_, _ = h.db.Pool.Exec(c.Context(), `
+INSERT INTO assets (key, kind, content_type, size_bytes, width, height, uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (key) DO NOTHING
+`, key, kind, img.ContentType, len(img.Data), img.Width, img.Height, uploadedBy)
+	}
+
+	signed, _ := h.store.SignedURL(c.Context(), key, h.ttl)
+	return fiber.Map{
+		"key":          key,
+		"url":          strings.TrimRight(h.cfg.PublicBaseURL, "/") + "/assets/" + key,
+		"signed_url":   signed,
+		"content_type": img.ContentType,
+		"width":        img.Width,
+		"height":       img.Height,
+		"size_bytes":   len(img.Data),
+	}, 0, ""
+}
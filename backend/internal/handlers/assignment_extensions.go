@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+type extensionRequest struct {
+	RequestedDeadline *time.Time `json:"requested_deadline"`
+	Reason            string     `json:"reason"`
+}
+
+// RequestExtension lets the issue's assignee ask the maintainer to push back the deadline.
+// While a request is pending the overdue job leaves the assignment alone.
+func (h *IssueApplicationsHandler) RequestExtension() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req extensionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		var v validate.Validator
+		if !v.MaxLen("reason", req.Reason, 2000) {
+			return validationFailed(c, v.Err())
+		}
+		if req.RequestedDeadline == nil || !req.RequestedDeadline.After(time.Now()) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_requested_deadline"})
+		}
+
+		var login string
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1`, userID).Scan(&login); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		}
+
+		var owner uuid.UUID
+		var assigneesJSON []byte
+		var deadline *time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.owner_user_id, COALESCE(gi.assignees, '[]'::jsonb), gi.deadline_at
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number = $2 AND gi.state = 'open'
+`, projectID, issueNumber).Scan(&owner, &assigneesJSON, &deadline)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+
+		var assignees []struct {
+			Login string `json:"login"`
+		}
+		_ = json.Unmarshal(assigneesJSON, &assignees)
+		isAssignee := false
+		for _, a := range assignees {
+			if strings.EqualFold(a.Login, login) {
+				isAssignee = true
+			}
+		}
+		if !isAssignee {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "not_assignee"})
+		}
+		if deadline == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issue_has_no_deadline"})
+		}
+		if !req.RequestedDeadline.After(*deadline) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "requested_deadline_not_later"})
+		}
+
+		var id uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO assignment_extension_requests (project_id, issue_number, user_id, github_login, current_deadline, requested_deadline, reason)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+ON CONFLICT DO NOTHING
+RETURNING id
+`, projectID, issueNumber, userID, login, deadline, req.RequestedDeadline, req.Reason).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "extension_already_requested"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "extension_request_failed"})
+		}
+
+		notifyUser(c.Context(), h.db.Pool, owner, "extension_requested", fiber.Map{
+			"request_id":         id.String(),
+			"project_id":         projectID.String(),
+			"issue_number":       issueNumber,
+			"github_login":       login,
+			"current_deadline":   deadline,
+			"requested_deadline": req.RequestedDeadline,
+			"reason":             req.Reason,
+		})
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String(), "status": "pending"})
+	}
+}
+
+// ListExtensions returns an issue's extension requests, newest first. Maintainer, admin, or the requester.
+func (h *IssueApplicationsHandler) ListExtensions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		maintainer := h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber)
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, github_login, current_deadline, requested_deadline, COALESCE(reason, ''), status, decided_at, created_at
+FROM assignment_extension_requests
+WHERE project_id = $1 AND issue_number = $2 AND ($3 OR user_id = $4)
+ORDER BY created_at DESC
+`, projectID, issueNumber, maintainer, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "extensions_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var login, reason, status string
+			var current, decidedAt *time.Time
+			var requested, createdAt time.Time
+			if err := rows.Scan(&id, &login, &current, &requested, &reason, &status, &decidedAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "extensions_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":                 id.String(),
+				"github_login":       login,
+				"current_deadline":   current,
+				"requested_deadline": requested,
+				"reason":             reason,
+				"status":             status,
+				"decided_at":         decidedAt,
+				"created_at":         createdAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"extension_requests": out})
+	}
+}
+
+type extensionDecisionRequest struct {
+	Approve bool `json:"approve"`
+	// Optional: approve with a different deadline than the one requested.
+	DeadlineAt *time.Time `json:"deadline_at"`
+}
+
+// DecideExtension approves or denies a pending extension request. Maintainer only.
+func (h *IssueApplicationsHandler) DecideExtension() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		requestID, err := uuid.Parse(c.Params("requestId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_request_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req extensionDecisionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if req.DeadlineAt != nil && !req.DeadlineAt.After(time.Now()) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_deadline"})
+		}
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		status := "denied"
+		if req.Approve {
+			status = "approved"
+		}
+		var requesterID uuid.UUID
+		var requested time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+UPDATE assignment_extension_requests
+SET status = $4, decided_by_user_id = $5, decided_at = now()
+WHERE id = $3 AND project_id = $1 AND issue_number = $2 AND status = 'pending'
+RETURNING user_id, requested_deadline
+`, projectID, issueNumber, requestID, status, userID).Scan(&requesterID, &requested)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "extension_request_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "extension_decision_failed"})
+		}
+
+		var newDeadline *time.Time
+		if req.Approve {
+			newDeadline = &requested
+			if req.DeadlineAt != nil {
+				newDeadline = req.DeadlineAt
+			}
+			_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues SET deadline_at = $3 WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, newDeadline)
+			checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+			h.refreshStatusComment(c.Context(), projectID, issueNumber)
+		}
+
+		notifyUser(c.Context(), h.db.Pool, requesterID, "extension_"+status, fiber.Map{
+			"request_id":   requestID.String(),
+			"project_id":   projectID.String(),
+			"issue_number": issueNumber,
+			"deadline_at":  newDeadline,
+		})
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": status, "deadline_at": newDeadline})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+)
+
+// Progress of an assignment, from the assignee's open pull requests that close the issue.
+const (
+	progressNotStarted = "not_started" // no open pull request yet
+	progressInProgress = "in_progress" // a draft pull request: work in progress
+	progressInReview   = "in_review"   // a pull request ready for review
+)
+
+// assignmentProgress is what maintainers see of an assignee's work without having to ask.
+type assignmentProgress struct {
+	Status       string     `json:"status"`
+	PRNumber     *int       `json:"pr_number"`
+	PRURL        string     `json:"pr_url,omitempty"`
+	Draft        bool       `json:"draft"`
+	LastPushedAt *time.Time `json:"last_pushed_at"`
+}
+
+// assignmentProgress looks for the assignee's open pull requests in the project that close the issue and
+// reports the most recently pushed one. A ready pull request wins over a draft.
+func (h *IssueApplicationsHandler) assignmentProgress(ctx context.Context, projectID uuid.UUID, issueNumber int, login string) (assignmentProgress, error) {
+	p := assignmentProgress{Status: progressNotStarted}
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT number, COALESCE(url, ''), COALESCE(body, ''), draft, COALESCE(last_pushed_at, created_at_github)
+FROM github_pull_requests
+WHERE project_id = $1 AND state = 'open' AND LOWER(author_login) = LOWER($2)
+  AND body LIKE '%#' || $3::int || '%'
+ORDER BY draft, COALESCE(last_pushed_at, created_at_github) DESC NULLS LAST
+`, projectID, login, issueNumber)
+	if err != nil {
+		return p, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var number int
+		var url, body string
+		var draft bool
+		var pushedAt *time.Time
+		if err := rows.Scan(&number, &url, &body, &draft, &pushedAt); err != nil {
+			return p, err
+		}
+		if p.PRNumber != nil || !closesIssue(body, issueNumber) {
+			continue
+		}
+		p.PRNumber, p.PRURL, p.Draft, p.LastPushedAt = &number, url, draft, pushedAt
+		p.Status = progressInReview
+		if draft {
+			p.Status = progressInProgress
+		}
+	}
+	return p, rows.Err()
+}
+
+func closesIssue(body string, issueNumber int) bool {
+	for _, n := range checkruns.LinkedIssues(body) {
+		if n == issueNumber {
+			return true
+		}
+	}
+	return false
+}
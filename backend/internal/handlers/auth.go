@@ -16,10 +16,11 @@ import (
 type AuthHandler struct {
 	cfg config.Config
 	db  *db.DB
+	gh  github.API
 }
 
-func NewAuthHandler(cfg config.Config, d *db.DB) *AuthHandler {
-	return &AuthHandler{cfg: cfg, db: d}
+func NewAuthHandler(cfg config.Config, d *db.DB, gh github.API) *AuthHandler {
+	return &AuthHandler{cfg: cfg, db: d, gh: gh}
 }
 
 type nonceRequest struct {
@@ -150,25 +151,27 @@ func (h *AuthHandler) Me() fiber.Handler {
 
 		// Get user profile fields from database
 		var firstName, lastName, location, website, bio, avatarURL, telegram, linkedin, whatsapp, twitter, discord *string
+		adminScopes := []string{}
 		err = h.db.Pool.QueryRow(c.Context(), `
-SELECT first_name, last_name, location, website, bio, avatar_url, telegram, linkedin, whatsapp, twitter, discord
+SELECT first_name, last_name, location, website, bio, avatar_url, telegram, linkedin, whatsapp, twitter, discord, admin_scopes
 FROM users
 WHERE id = $1
-`, userID).Scan(&firstName, &lastName, &location, &website, &bio, &avatarURL, &telegram, &linkedin, &whatsapp, &twitter, &discord)
+`, userID).Scan(&firstName, &lastName, &location, &website, &bio, &avatarURL, &telegram, &linkedin, &whatsapp, &twitter, &discord, &adminScopes)
 		if err != nil {
 			slog.Warn("failed to fetch user profile fields", "error", err, "user_id", userID)
 		}
 
 		response := fiber.Map{
-			"id":   userIDStr,
-			"role": role,
+			"id":           userIDStr,
+			"role":         role,
+			"admin_scopes": adminScopes,
 		}
 
 		// Try to get GitHub access token and fetch full profile
-		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 		if err == nil {
 			// Fetch full GitHub user profile
-			gh := github.NewClient()
+			gh := h.gh
 			ghUser, err := gh.GetUser(c.Context(), linkedAccount.AccessToken)
 			if err == nil {
 				githubMap := fiber.Map{
@@ -317,13 +320,13 @@ func (h *AuthHandler) ResyncGitHubProfile() fiber.Handler {
 		}
 
 		// Get GitHub access token
-		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 		if err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "github_not_linked"})
 		}
 
 		// Fetch fresh GitHub user profile
-		gh := github.NewClient()
+		gh := h.gh
 		ghUser, err := gh.GetUser(c.Context(), linkedAccount.AccessToken)
 		if err != nil {
 			slog.Error("failed to fetch GitHub user", "error", err, "user_id", userID)
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/botmessages"
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// BotMessagesHandler manages the locale and custom wording of the bot's own comments.
+type BotMessagesHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewBotMessagesHandler(cfg config.Config, d *db.DB) *BotMessagesHandler {
+	return &BotMessagesHandler{cfg: cfg, db: d}
+}
+
+type botMessagesRequest struct {
+	// Locale sets the scope's locale; "" clears it (inherit). Omit to leave unchanged.
+	Locale *string `json:"locale"`
+	// Messages maps message keys to custom wording; null or "" removes the override.
+	Messages map[string]*string `json:"messages"`
+}
+
+// Project returns the bot messages of a project: defaults, ecosystem and project overrides. Maintainer only.
+func (h *BotMessagesHandler) Project() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, status, code := h.authorizeProject(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		var projectLocale, ecosystemLocale *string
+		var ecosystemID *uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT p.bot_locale, e.bot_locale, p.ecosystem_id
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, projectID).Scan(&projectLocale, &ecosystemLocale, &ecosystemID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_messages_lookup_failed"})
+		}
+		locale := botmessages.DefaultLocale
+		if ecosystemLocale != nil {
+			locale = *ecosystemLocale
+		}
+		if projectLocale != nil {
+			locale = *projectLocale
+		}
+
+		projectOverrides, err := h.loadOverrides(c.Context(), "project_id", projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_messages_lookup_failed"})
+		}
+		ecosystemOverrides := map[string]string{}
+		if ecosystemID != nil {
+			if ecosystemOverrides, err = h.loadOverrides(c.Context(), "ecosystem_id", *ecosystemID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_messages_lookup_failed"})
+			}
+		}
+
+		messages := make([]fiber.Map, 0, len(botmessages.Keys()))
+		for _, key := range botmessages.Keys() {
+			effective := botmessages.Default(locale, key)
+			if v, ok := ecosystemOverrides[key]; ok {
+				effective = v
+			}
+			if v, ok := projectOverrides[key]; ok {
+				effective = v
+			}
+			messages = append(messages, fiber.Map{
+				"key":                key,
+				"variables":          botmessages.Variables[key],
+				"default":            botmessages.Default(locale, key),
+				"ecosystem_override": nullIfMissing(ecosystemOverrides, key),
+				"override":           nullIfMissing(projectOverrides, key),
+				"effective":          effective,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"locale":           locale,
+			"project_locale":   projectLocale,
+			"ecosystem_locale": ecosystemLocale,
+			"locales":          botmessages.Locales(),
+			"messages":         messages,
+		})
+	}
+}
+
+// UpdateProject sets a project's bot locale and/or message overrides. Maintainer only.
+func (h *BotMessagesHandler) UpdateProject() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, status, code := h.authorizeProject(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req botMessagesRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if status, body := h.save(c.Context(), "projects", "project_id", projectID, req); body != nil {
+			return c.Status(status).JSON(body)
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Ecosystem returns an ecosystem's bot locale and message overrides. Admin only (route-guarded).
+func (h *BotMessagesHandler) Ecosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+		}
+		var locale *string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT bot_locale FROM ecosystems WHERE id = $1`, ecoID).Scan(&locale)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_messages_lookup_failed"})
+		}
+		overrides, err := h.loadOverrides(c.Context(), "ecosystem_id", ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_messages_lookup_failed"})
+		}
+		effectiveLocale := botmessages.DefaultLocale
+		if locale != nil {
+			effectiveLocale = *locale
+		}
+		messages := make([]fiber.Map, 0, len(botmessages.Keys()))
+		for _, key := range botmessages.Keys() {
+			messages = append(messages, fiber.Map{
+				"key":       key,
+				"variables": botmessages.Variables[key],
+				"default":   botmessages.Default(effectiveLocale, key),
+				"override":  nullIfMissing(overrides, key),
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"locale":   locale,
+			"locales":  botmessages.Locales(),
+			"messages": messages,
+		})
+	}
+}
+
+// UpdateEcosystem sets an ecosystem's bot locale and/or message overrides. Admin only (route-guarded).
+func (h *BotMessagesHandler) UpdateEcosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+		}
+		var exists bool
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil || !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		var req botMessagesRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if status, body := h.save(c.Context(), "ecosystems", "ecosystem_id", ecoID, req); body != nil {
+			return c.Status(status).JSON(body)
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// authorizeProject resolves :id and checks the caller owns the project (or is admin).
+func (h *BotMessagesHandler) authorizeProject(c *fiber.Ctx) (uuid.UUID, int, string) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, fiber.StatusServiceUnavailable, "db_not_configured"
+	}
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if owner != userID && role != "admin" {
+		return uuid.Nil, fiber.StatusForbidden, "forbidden"
+	}
+	return projectID, 0, ""
+}
+
+func (h *BotMessagesHandler) loadOverrides(ctx context.Context, scopeColumn string, scopeID uuid.UUID) (map[string]string, error) {
+	rows, err := h.db.Pool.Query(ctx, `SELECT key, body FROM bot_message_overrides WHERE `+scopeColumn+` = $1`, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]string{}
+	for rows.Next() {
+		var key, body string
+		if err := rows.Scan(&key, &body); err != nil {
+			return nil, err
+		}
+		out[key] = body
+	}
+	return out, rows.Err()
+}
+
+// save validates and stores a locale change and message overrides for one scope.
+// table/scopeColumn are fixed identifiers chosen by the caller, never user input.
+func (h *BotMessagesHandler) save(ctx context.Context, table, scopeColumn string, scopeID uuid.UUID, req botMessagesRequest) (int, fiber.Map) {
+	if req.Locale != nil {
+		*req.Locale = strings.TrimSpace(strings.ToLower(*req.Locale))
+		if *req.Locale != "" && !botmessages.Supported(*req.Locale) {
+			return fiber.StatusBadRequest, fiber.Map{"error": "unsupported_locale", "locales": botmessages.Locales()}
+		}
+	}
+	for key, body := range req.Messages {
+		allowed, ok := botmessages.Variables[key]
+		if !ok {
+			return fiber.StatusBadRequest, fiber.Map{"error": "unknown_message_key", "key": key}
+		}
+		if body == nil {
+			continue
+		}
+		if len(*body) > 8000 {
+			return fiber.StatusBadRequest, fiber.Map{"error": "message_too_long", "key": key}
+		}
+		for _, v := range bottemplate.Variables(*body) {
+			if !containsString(allowed, v) {
+				return fiber.StatusBadRequest, fiber.Map{"error": "unknown_variable", "key": key, "variable": v, "allowed": allowed}
+			}
+		}
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "bot_messages_update_failed"}
+	}
+	defer tx.Rollback(ctx)
+
+	if req.Locale != nil {
+		if _, err := tx.Exec(ctx, `UPDATE `+table+` SET bot_locale = NULLIF($2, ''), updated_at = now() WHERE id = $1`, scopeID, *req.Locale); err != nil {
+			return fiber.StatusInternalServerError, fiber.Map{"error": "bot_messages_update_failed"}
+		}
+	}
+	for key, body := range req.Messages {
+		if body == nil || strings.TrimSpace(*body) == "" {
+			_, err = tx.Exec(ctx, `DELETE FROM bot_message_overrides WHERE `+scopeColumn+` = $1 AND key = $2`, scopeID, key)
+		} else {
+			_, err = tx.Exec(ctx, `
+INSERT INTO bot_message_overrides (`+scopeColumn+`, key, body) VALUES ($1, $2, $3)
+ON CONFLICT (`+scopeColumn+`, key) WHERE `+scopeColumn+` IS NOT NULL DO UPDATE SET body = EXCLUDED.body, updated_at = now()
+`, scopeID, key, strings.TrimSpace(*body))
+		}
+		if err != nil {
+			return fiber.StatusInternalServerError, fiber.Map{"error": "bot_messages_update_failed"}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "bot_messages_update_failed"}
+	}
+	return fiber.StatusOK, nil
+}
+
+func nullIfMissing(m map[string]string, key string) *string {
+	if v, ok := m[key]; ok {
+		return &v
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// BotTemplatesHandler manages reusable bot comment templates and the scheduled comment queue.
+type BotTemplatesHandler struct {
+	db *db.DB
+}
+
+func NewBotTemplatesHandler(d *db.DB) *BotTemplatesHandler {
+	return &BotTemplatesHandler{db: d}
+}
+
+type botTemplateRequest struct {
+	Name         *string `json:"name"`
+	Body         *string `json:"body"`
+	TriggerEvent *string `json:"trigger_event"`
+	DelayMinutes *int    `json:"delay_minutes"`
+}
+
+func (h *BotTemplatesHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, name, body, trigger_event, delay_minutes, created_at, updated_at
+FROM bot_comment_templates
+WHERE project_id = $1
+ORDER BY name ASC
+`, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_templates_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var name, body string
+			var trigger *string
+			var delay int
+			var createdAt, updatedAt time.Time
+			if err := rows.Scan(&id, &name, &body, &trigger, &delay, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_templates_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":            id.String(),
+				"name":          name,
+				"body":          body,
+				"variables":     bottemplate.Variables(body),
+				"trigger_event": trigger,
+				"delay_minutes": delay,
+				"created_at":    createdAt,
+				"updated_at":    updatedAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"templates": out})
+	}
+}
+
+func (h *BotTemplatesHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, _ := uuid.Parse(userIDStr)
+
+		var req botTemplateRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_required"})
+		}
+		if req.Body == nil || strings.TrimSpace(*req.Body) == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_required"})
+		}
+		if code := validateBotTemplate(req); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		delay := 0
+		if req.DelayMinutes != nil {
+			delay = *req.DelayMinutes
+		}
+
+		var id uuid.UUID
+		err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO bot_comment_templates (project_id, name, body, trigger_event, delay_minutes, created_by_user_id)
+VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+RETURNING id
+`, projectID, strings.TrimSpace(*req.Name), strings.TrimSpace(*req.Body), derefString(req.TriggerEvent), delay, userID).Scan(&id)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "template_name_taken"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_template_create_failed"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String()})
+	}
+}
+
+func (h *BotTemplatesHandler) Update() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		templateID, err := uuid.Parse(c.Params("templateId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_template_id"})
+		}
+
+		var req botTemplateRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if code := validateBotTemplate(req); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+
+		// trigger_event: omitted keeps the current value, "" clears it.
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE bot_comment_templates
+SET name = COALESCE(NULLIF($3, ''), name),
+    body = COALESCE(NULLIF($4, ''), body),
+    trigger_event = CASE WHEN $5::boolean THEN NULLIF($6, '') ELSE trigger_event END,
+    delay_minutes = COALESCE($7, delay_minutes),
+    updated_at = now()
+WHERE id = $1 AND project_id = $2
+`, templateID, projectID, strings.TrimSpace(derefString(req.Name)), strings.TrimSpace(derefString(req.Body)),
+			req.TriggerEvent != nil, derefString(req.TriggerEvent), req.DelayMinutes)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "template_name_taken"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_template_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "template_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+func (h *BotTemplatesHandler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		templateID, err := uuid.Parse(c.Params("templateId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_template_id"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM bot_comment_templates WHERE id = $1 AND project_id = $2
+`, templateID, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_template_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "template_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Scheduled lists queued and recently processed scheduled bot comments for a project.
+func (h *BotTemplatesHandler) Scheduled() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, issue_number, template_id, body, variables, send_at, status, attempts, last_error, github_comment_id, sent_at, created_at
+FROM scheduled_bot_comments
+WHERE project_id = $1
+ORDER BY send_at DESC
+LIMIT 200
+`, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "scheduled_comments_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var templateID *uuid.UUID
+			var issueNumber, attempts int
+			var body, st string
+			var varsJSON []byte
+			var sendAt, createdAt time.Time
+			var lastErr *string
+			var commentID *int64
+			var sentAt *time.Time
+			if err := rows.Scan(&id, &issueNumber, &templateID, &body, &varsJSON, &sendAt, &st, &attempts, &lastErr, &commentID, &sentAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "scheduled_comments_list_failed"})
+			}
+			var vars map[string]string
+			_ = json.Unmarshal(varsJSON, &vars)
+			out = append(out, fiber.Map{
+				"id":                id.String(),
+				"issue_number":      issueNumber,
+				"template_id":       templateID,
+				"body":              body,
+				"variables":         vars,
+				"send_at":           sendAt,
+				"status":            st,
+				"attempts":          attempts,
+				"last_error":        lastErr,
+				"github_comment_id": commentID,
+				"sent_at":           sentAt,
+				"created_at":        createdAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"scheduled": out})
+	}
+}
+
+// CancelScheduled cancels a pending scheduled bot comment.
+func (h *BotTemplatesHandler) CancelScheduled() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		scheduledID, err := uuid.Parse(c.Params("scheduledId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_scheduled_id"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE scheduled_bot_comments SET status = 'cancelled', updated_at = now()
+WHERE id = $1 AND project_id = $2 AND status = 'pending'
+`, scheduledID, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "scheduled_comment_cancel_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "scheduled_comment_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+func (h *BotTemplatesHandler) requireMaintainer(c *fiber.Ctx) (uuid.UUID, int, string) {
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if owner != userID && role != "admin" {
+		return uuid.Nil, fiber.StatusForbidden, "forbidden"
+	}
+	return projectID, 0, ""
+}
+
+func validateBotTemplate(req botTemplateRequest) string {
+	if req.Name != nil && len(strings.TrimSpace(*req.Name)) > 100 {
+		return "name_too_long"
+	}
+	if req.Body != nil && len(*req.Body) > 32000 {
+		return "body_too_long"
+	}
+	if req.TriggerEvent != nil && *req.TriggerEvent != "" && *req.TriggerEvent != "assigned" {
+		return "invalid_trigger_event"
+	}
+	if req.DelayMinutes != nil && *req.DelayMinutes < 0 {
+		return "invalid_delay_minutes"
+	}
+	return ""
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/appstage"
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/ical"
+)
+
+// CalendarHandler serves each contributor's deadlines as an iCalendar feed.
+type CalendarHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewCalendarHandler(cfg config.Config, d *db.DB) *CalendarHandler {
+	return &CalendarHandler{cfg: cfg, db: d}
+}
+
+// CreateFeed serves POST /me/calendar-feed: issues a new feed token and returns the URL to subscribe to.
+// The URL is only shown once; calling again rotates the token and breaks existing subscriptions.
+func (h *CalendarHandler) CreateFeed() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		token, err := auth.RotateCalendarToken(c.Context(), h.db.Pool, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "calendar_feed_create_failed"})
+		}
+		feedURL := strings.TrimSuffix(h.cfg.PublicBaseURL, "/") + "/me/deadlines.ics?token=" + token
+		webcalURL := feedURL
+		if i := strings.Index(feedURL, "://"); i >= 0 {
+			webcalURL = "webcal" + feedURL[i:]
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"url": feedURL, "webcal_url": webcalURL})
+	}
+}
+
+// DeleteFeed serves DELETE /me/calendar-feed: turns the feed off.
+func (h *CalendarHandler) DeleteFeed() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		if err := auth.RevokeCalendarToken(c.Context(), h.db.Pool, userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "calendar_feed_delete_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Deadlines serves GET /me/deadlines.ics: the deadlines of the caller's open assignments, the due dates of
+// trial tasks and calls maintainers asked them for, and Open Source Week campaigns. Calendar apps
+// authenticate with ?token= from CreateFeed; the web app can use its session token instead.
+func (h *CalendarHandler) Deadlines() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var userID uuid.UUID
+		if token := strings.TrimSpace(c.Query("token")); token != "" {
+			id, err := auth.CalendarTokenUser(c.Context(), h.db.Pool, token)
+			if errors.Is(err, auth.ErrInvalidCalendarToken) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_calendar_token"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "calendar_token_lookup_failed"})
+			}
+			userID = id
+		} else {
+			bearer := strings.TrimSpace(c.Get("Authorization"))
+			if len(bearer) < len("bearer ") || !strings.EqualFold(bearer[:len("bearer ")], "bearer ") {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing_bearer_token"})
+			}
+			claims, err := auth.ParseJWT(h.cfg.JWTSecret, strings.TrimSpace(bearer[len("bearer "):]))
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_token"})
+			}
+			if userID, err = uuid.Parse(claims.Subject); err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+			}
+		}
+
+		events, err := h.deadlineEvents(c.Context(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "deadlines_lookup_failed"})
+		}
+		c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, `inline; filename="grainlify-deadlines.ics"`)
+		c.Set(fiber.HeaderCacheControl, "private, max-age=900")
+		return c.Status(fiber.StatusOK).SendString(ical.Render(ical.Calendar{Name: "Grainlify deadlines", Events: events}, time.Now()))
+	}
+}
+
+// campaignLookback keeps recently finished campaigns in the feed.
+const campaignLookback = 30 * 24 * time.Hour
+
+func (h *CalendarHandler) deadlineEvents(ctx context.Context, userID uuid.UUID) ([]ical.Event, error) {
+	events := []ical.Event{}
+
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT gi.project_id, p.github_full_name, gi.number, COALESCE(gi.title, ''), COALESCE(gi.url, ''), gi.deadline_at
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE gi.state = 'open' AND gi.deadline_at IS NOT NULL
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM jsonb_array_elements(COALESCE(gi.assignees, '[]'::jsonb)) x
+    WHERE LOWER(x->>'login') IN (SELECT LOWER(login) FROM github_accounts WHERE user_id = $1)
+  )
+ORDER BY gi.deadline_at
+`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var projectID uuid.UUID
+		var fullName, title, issueURL string
+		var number int
+		var deadline time.Time
+		if err := rows.Scan(&projectID, &fullName, &number, &title, &issueURL, &deadline); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("deadline-%s-%d@grainlify", projectID, number),
+			Summary:     fmt.Sprintf("Due: %s#%d %s", fullName, number, title),
+			Description: "Deadline for your assignment on Grainlify.",
+			URL:         issueURL,
+			Start:       deadline,
+			End:         deadline,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = h.db.Pool.Query(ctx, `
+SELECT a.id, p.github_full_name, a.issue_number, a.stage, a.stage_due_at, COALESCE(gi.url, '')
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id
+LEFT JOIN github_issues gi ON gi.project_id = a.project_id AND gi.number = a.issue_number
+WHERE a.status = 'pending' AND a.stage_due_at IS NOT NULL AND a.stage = ANY($2)
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND (a.user_id = $1 OR LOWER(a.github_login) IN (SELECT LOWER(login) FROM github_accounts WHERE user_id = $1))
+ORDER BY a.stage_due_at
+`, userID, []string{appstage.TrialRequested, appstage.InterviewRequested})
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		var fullName, stage, issueURL string
+		var number int
+		var due time.Time
+		if err := rows.Scan(&id, &fullName, &number, &stage, &due, &issueURL); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		what := "Trial task"
+		if stage == appstage.InterviewRequested {
+			what = "Call"
+		}
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("application-stage-%s@grainlify", id),
+			Summary:     fmt.Sprintf("%s due: %s#%d", what, fullName, number),
+			Description: "Requested by the maintainer before deciding on your application.",
+			URL:         issueURL,
+			Start:       due,
+			End:         due,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = h.db.Pool.Query(ctx, `
+SELECT id, title, COALESCE(description, ''), start_at, end_at
+FROM open_source_week_events
+WHERE status <> 'draft' AND end_at > $1
+ORDER BY start_at
+`, time.Now().Add(-campaignLookback))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var title, description string
+		var start, end time.Time
+		if err := rows.Scan(&id, &title, &description, &start, &end); err != nil {
+			return nil, err
+		}
+		events = append(events, ical.Event{
+			UID:         fmt.Sprintf("campaign-%s@grainlify", id),
+			Summary:     "Open Source Week: " + title,
+			Description: description,
+			Start:       start,
+			End:         end,
+		})
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+type CompletionAnalyticsHandler struct {
+	db *db.DB
+}
+
+func NewCompletionAnalyticsHandler(d *db.DB) *CompletionAnalyticsHandler {
+	return &CompletionAnalyticsHandler{db: d}
+}
+
+// completionGroupings maps group_by values to the grouping key and any join it needs.
+var completionGroupings = map[string]struct{ key, join string }{
+	"complexity": {key: `c.complexity`},
+	"label":      {key: `LOWER(l.label)`, join: `CROSS JOIN LATERAL unnest(c.labels) AS l(label)`},
+	"project":    {key: `p.github_full_name`},
+}
+
+// TimeToComplete serves GET /analytics/time-to-complete: percentiles of the time from assignment to
+// merge (see package completions), grouped by ?group_by=complexity|label|project, over the last ?days
+// (default 365). Maintainers see their own projects; admins see every project. ?project_id= and
+// ?ecosystem_id= narrow the scope.
+func (h *CompletionAnalyticsHandler) TimeToComplete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		groupBy := c.Query("group_by", "complexity")
+		grouping, ok := completionGroupings[groupBy]
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_group_by"})
+		}
+		days := c.QueryInt("days", 365)
+		if days <= 0 || days > 730 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_days"})
+		}
+		var projectID, ecosystemID, ownerID *uuid.UUID
+		if v := c.Query("project_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+			}
+			projectID = &id
+		}
+		if v := c.Query("ecosystem_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+			}
+			ecosystemID = &id
+		}
+		if role != "admin" {
+			ownerID = &userID
+			if projectID != nil {
+				var owner uuid.UUID
+				err := h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, *projectID).Scan(&owner)
+				if errors.Is(err, pgx.ErrNoRows) {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+				}
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+				}
+				if owner != userID {
+					return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+				}
+			}
+		}
+
+		scope := `
+FROM issue_completions c
+JOIN projects p ON p.id = c.project_id AND p.deleted_at IS NULL
+%s
+WHERE c.merged_at >= now() - make_interval(days => $1)
+  AND ($2::uuid IS NULL OR c.project_id = $2)
+  AND ($3::uuid IS NULL OR p.ecosystem_id = $3)
+  AND ($4::uuid IS NULL OR p.owner_user_id = $4)
+`
+		stats := `COUNT(*),
+       percentile_cont(0.5) WITHIN GROUP (ORDER BY c.duration_seconds),
+       percentile_cont(0.75) WITHIN GROUP (ORDER BY c.duration_seconds),
+       percentile_cont(0.9) WITHIN GROUP (ORDER BY c.duration_seconds),
+       percentile_cont(0.5) WITHIN GROUP (ORDER BY c.points)`
+		args := []any{days, projectID, ecosystemID, ownerID}
+
+		overall := completionStats{}
+		var p50, p75, p90, points *float64
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT `+stats+fmt.Sprintf(scope, ""), args...).
+			Scan(&overall.Count, &p50, &p75, &p90, &points); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "analytics_failed"})
+		}
+		overall.set(p50, p75, p90, points)
+
+		rows, err := h.db.Pool.Query(c.Context(), `SELECT `+grouping.key+`, `+stats+fmt.Sprintf(scope, grouping.join)+`
+GROUP BY 1
+ORDER BY COUNT(*) DESC, 1
+LIMIT 200
+`, args...)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "analytics_failed"})
+		}
+		defer rows.Close()
+		groups := []completionStats{}
+		for rows.Next() {
+			var g completionStats
+			if err := rows.Scan(&g.Key, &g.Count, &p50, &p75, &p90, &points); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "analytics_failed"})
+			}
+			g.set(p50, p75, p90, points)
+			groups = append(groups, g)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "analytics_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"group_by": groupBy,
+			"days":     days,
+			"overall":  overall,
+			"groups":   groups,
+		})
+	}
+}
+
+// completionStats are durations in hours, rounded to a tenth.
+type completionStats struct {
+	Key          string   `json:"key,omitempty"`
+	Count        int64    `json:"count"`
+	P50Hours     *float64 `json:"p50_hours"`
+	P75Hours     *float64 `json:"p75_hours"`
+	P90Hours     *float64 `json:"p90_hours"`
+	MedianPoints *float64 `json:"median_points"`
+}
+
+func (s *completionStats) set(p50, p75, p90, points *float64) {
+	hours := func(secs *float64) *float64 {
+		if secs == nil {
+			return nil
+		}
+		v := math.Round(*secs/360) / 10
+		return &v
+	}
+	s.P50Hours, s.P75Hours, s.P90Hours = hours(p50), hours(p75), hours(p90)
+	if points != nil {
+		v := *points
+		s.MedianPoints = &v
+	}
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/statements"
+)
+
+// maxStatementsPerDay limits how many statements a contributor issues in 24 hours.
+const maxStatementsPerDay = 10
+
+// ContributionStatementsHandler issues signed contribution statements and serves their public
+// verification page.
+type ContributionStatementsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewContributionStatementsHandler(cfg config.Config, d *db.DB) *ContributionStatementsHandler {
+	return &ContributionStatementsHandler{cfg: cfg, db: d}
+}
+
+// Create serves POST /me/contribution-statements: snapshots the caller's completed contributions into a
+// new signed statement.
+func (h *ContributionStatementsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.cfg.JWTSecret == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "signing_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var login string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1 ORDER BY login LIMIT 1`, userID).Scan(&login)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_account_lookup_failed"})
+		}
+
+		var recent int
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT COUNT(*) FROM contribution_statements WHERE user_id = $1 AND created_at > now() - interval '24 hours'
+`, userID).Scan(&recent); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statements_lookup_failed"})
+		}
+		if recent >= maxStatementsPerDay {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "too_many_statements"})
+		}
+
+		contributions, err := statements.Load(c.Context(), h.db.Pool, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "contributions_lookup_failed"})
+		}
+		s := statements.New(uuid.New(), login, time.Now(), contributions)
+		payload, err := json.Marshal(s)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statement_encode_failed"})
+		}
+		signature := statements.Sign(h.cfg.JWTSecret, payload)
+		if _, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO contribution_statements (id, user_id, github_login, payload, signature, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, s.ID, userID, login, string(payload), signature, s.IssuedAt); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statement_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"statement":  json.RawMessage(payload),
+			"signature":  signature,
+			"verify_url": statements.VerifyURL(h.cfg.PublicBaseURL, s.ID),
+		})
+	}
+}
+
+// Mine serves GET /me/contribution-statements: the caller's statements, newest first.
+func (h *ContributionStatementsHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, github_login, (payload::jsonb)->'totals', created_at, revoked_at
+FROM contribution_statements
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT 100
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statements_lookup_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var login string
+			var totals []byte
+			var createdAt time.Time
+			var revokedAt *time.Time
+			if err := rows.Scan(&id, &login, &totals, &createdAt, &revokedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statements_lookup_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":           id.String(),
+				"github_login": login,
+				"totals":       json.RawMessage(totals),
+				"created_at":   createdAt,
+				"revoked_at":   revokedAt,
+				"verify_url":   statements.VerifyURL(h.cfg.PublicBaseURL, id),
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statements_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"statements": out})
+	}
+}
+
+// Revoke serves DELETE /me/contribution-statements/:id. The verification page keeps answering, reporting
+// the statement as revoked.
+func (h *ContributionStatementsHandler) Revoke() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_statement_id"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE contribution_statements SET revoked_at = COALESCE(revoked_at, now())
+WHERE id = $1 AND user_id = $2
+`, id, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statement_revoke_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "statement_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Verify serves GET /verify/contributions/:id (public). format=json (default) reports whether the
+// statement is authentic and not revoked; format=md or format=pdf downloads the rendered statement. A
+// holder can pass signature= to check that their copy is the one that was issued.
+func (h *ContributionStatementsHandler) Verify() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "statement_not_found"})
+		}
+		var payload, signature string
+		var revokedAt *time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT payload, signature, revoked_at FROM contribution_statements WHERE id = $1
+`, id).Scan(&payload, &signature, &revokedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "statement_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statement_lookup_failed"})
+		}
+		authentic := statements.ValidSignature(h.cfg.JWTSecret, []byte(payload), signature)
+		if given := strings.TrimSpace(c.Query("signature")); given != "" && given != signature {
+			authentic = false
+		}
+
+		format := strings.ToLower(c.Query("format", "json"))
+		if format == "json" {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"valid":      authentic && revokedAt == nil,
+				"authentic":  authentic,
+				"revoked_at": revokedAt,
+				"statement":  json.RawMessage(payload),
+				"signature":  signature,
+			})
+		}
+		if format != "md" && format != "pdf" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_format"})
+		}
+		if !authentic || revokedAt != nil {
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": "statement_not_valid", "revoked_at": revokedAt})
+		}
+		var s statements.Statement
+		if err := json.Unmarshal([]byte(payload), &s); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "statement_decode_failed"})
+		}
+		verifyURL := statements.VerifyURL(h.cfg.PublicBaseURL, s.ID)
+		filename := "grainlify-contributions-" + s.GitHubLogin + "-" + s.IssuedAt.Format("2006-01-02")
+		if format == "md" {
+			c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+			c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`.md"`)
+			return c.Status(fiber.StatusOK).SendString(statements.Markdown(s, verifyURL, signature))
+		}
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`.pdf"`)
+		return c.Status(fiber.StatusOK).Send(statements.PDF(statements.Lines(s, verifyURL, signature)))
+	}
+}
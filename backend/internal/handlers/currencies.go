@@ -0,0 +1,483 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// CurrenciesHandler manages the registry of currencies rewards and payouts are paid in, and the exchange
+// rates that let reports add them up in one unit.
+type CurrenciesHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewCurrenciesHandler(cfg config.Config, d *db.DB) *CurrenciesHandler {
+	return &CurrenciesHandler{cfg: cfg, db: d}
+}
+
+var currencyCodeRe = regexp.MustCompile(`^[A-Z0-9]{2,12}$`)
+
+type currency struct {
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Kind         string    `json:"kind"`
+	Decimals     int       `json:"decimals"`
+	Chain        *string   `json:"chain"`
+	ContractID   *string   `json:"contract_id"`
+	IconURL      *string   `json:"icon_url"`
+	Active       bool      `json:"active"`
+	KYCThreshold *string   `json:"kyc_threshold"` // base units above which payouts need a verified recipient
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+const currencyColumns = `code, name, kind, decimals, chain, contract_id, icon_url, active, kyc_threshold::text, created_at, updated_at`
+
+func scanCurrency(row pgx.Row) (currency, error) {
+	var cur currency
+	err := row.Scan(&cur.Code, &cur.Name, &cur.Kind, &cur.Decimals, &cur.Chain, &cur.ContractID, &cur.IconURL, &cur.Active, &cur.KYCThreshold, &cur.CreatedAt, &cur.UpdatedAt)
+	return cur, err
+}
+
+// List serves GET /currencies: the active currencies, for showing amounts with the right decimals and icon.
+func (h *CurrenciesHandler) List() fiber.Handler {
+	return h.list(false)
+}
+
+// AdminList serves GET /admin/currencies: every currency, inactive ones included.
+func (h *CurrenciesHandler) AdminList() fiber.Handler {
+	return h.list(true)
+}
+
+func (h *CurrenciesHandler) list(all bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+currencyColumns+`
+FROM currencies
+WHERE active OR $1
+ORDER BY kind, code
+`, all)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currencies_fetch_failed"})
+		}
+		defer rows.Close()
+		out := []currency{}
+		for rows.Next() {
+			cur, err := scanCurrency(rows)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currencies_fetch_failed"})
+			}
+			out = append(out, cur)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currencies_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"currencies": out})
+	}
+}
+
+type currencyCreateRequest struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Decimals   *int   `json:"decimals"`
+	Chain      string `json:"chain"`
+	ContractID string `json:"contract_id"`
+	IconURL    string `json:"icon_url"`
+}
+
+// Create serves POST /admin/currencies: registers a fiat currency or a token. Tokens need the chain they
+// live on; fiat currencies have neither chain nor contract.
+func (h *CurrenciesHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req currencyCreateRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+		req.Name, req.Kind = strings.TrimSpace(req.Name), strings.ToLower(strings.TrimSpace(req.Kind))
+		req.Chain, req.ContractID = strings.ToLower(strings.TrimSpace(req.Chain)), strings.TrimSpace(req.ContractID)
+		req.IconURL = strings.TrimSpace(req.IconURL)
+
+		var v validate.Validator
+		if v.Required("code", req.Code) && !currencyCodeRe.MatchString(req.Code) {
+			v.Fail("code", validate.Invalid, "must be 2-12 letters or digits")
+		}
+		if v.Required("name", req.Name) {
+			v.MaxLen("name", req.Name, 100)
+		}
+		if v.Required("kind", req.Kind) && v.OneOf("kind", req.Kind, "fiat", "token") {
+			validateCurrencyChain(&v, req.Kind, req.Chain, req.ContractID)
+		}
+		if req.Decimals == nil {
+			v.Required("decimals", "")
+		} else {
+			v.Range("decimals", *req.Decimals, 0, 38)
+		}
+		v.URL("icon_url", &req.IconURL)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		cur, err := scanCurrency(h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO currencies (code, name, kind, decimals, chain, contract_id, icon_url)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''))
+RETURNING `+currencyColumns+`
+`, req.Code, req.Name, req.Kind, *req.Decimals, req.Chain, req.ContractID, req.IconURL))
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "currency_exists"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(cur)
+	}
+}
+
+// currencyPatchRequest is the body of Update, a JSON Merge Patch. The code and kind can't change: rates,
+// payouts and ecosystems refer to the code, and the kind decides whether a chain is required.
+type currencyPatchRequest struct {
+	Name       patchField[string] `json:"name"`
+	Decimals   patchField[int]    `json:"decimals"`
+	Chain      patchField[string] `json:"chain"`
+	ContractID patchField[string] `json:"contract_id"`
+	IconURL    patchField[string] `json:"icon_url"`
+	Active     patchField[bool]   `json:"active"`
+	// KYCThreshold is null to stop requiring KYC.
+	KYCThreshold patchField[string] `json:"kyc_threshold"`
+}
+
+// Update serves PATCH /admin/currencies/:code. Deactivating a currency hides it from GET /currencies but
+// keeps its rates and the payouts made in it.
+func (h *CurrenciesHandler) Update() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		code := strings.ToUpper(c.Params("code"))
+		var req currencyPatchRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		existing, err := scanCurrency(h.db.Pool.QueryRow(c.Context(), `SELECT `+currencyColumns+` FROM currencies WHERE code = $1`, code))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "currency_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_update_failed"})
+		}
+
+		var v validate.Validator
+		name := patchText(req.Name)
+		if name != nil && v.Required("name", *name) {
+			v.MaxLen("name", *name, 100)
+		}
+		var decimals *int
+		if req.Decimals.Set && !req.Decimals.Null {
+			decimals = &req.Decimals.Value
+			v.Range("decimals", *decimals, 0, 38)
+		}
+		chain, contractID := patchText(req.Chain), patchText(req.ContractID)
+		if chain != nil {
+			*chain = strings.ToLower(*chain)
+		}
+		// Check the chain and contract as they will be after the patch.
+		finalChain, finalContract := "", ""
+		if existing.Chain != nil {
+			finalChain = *existing.Chain
+		}
+		if existing.ContractID != nil {
+			finalContract = *existing.ContractID
+		}
+		if chain != nil {
+			finalChain = *chain
+		}
+		if contractID != nil {
+			finalContract = *contractID
+		}
+		validateCurrencyChain(&v, existing.Kind, finalChain, finalContract)
+		iconURL := patchText(req.IconURL)
+		if iconURL != nil {
+			v.URL("icon_url", iconURL)
+		}
+		var active *bool
+		if req.Active.Set && !req.Active.Null {
+			active = &req.Active.Value
+		}
+		kycThreshold := patchText(req.KYCThreshold)
+		if kycThreshold != nil && *kycThreshold != "" {
+			if n, ok := new(big.Int).SetString(*kycThreshold, 10); !ok || n.Sign() < 0 || len(*kycThreshold) > 78 {
+				v.Fail("kyc_threshold", validate.Invalid, "must be a whole number of base units")
+			}
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		cur, err := scanCurrency(h.db.Pool.QueryRow(c.Context(), `
+UPDATE currencies
+SET name = COALESCE($2, name),
+    decimals = COALESCE($3, decimals),
+    chain = CASE WHEN $4::text IS NULL THEN chain ELSE NULLIF($4::text, '') END,
+    contract_id = CASE WHEN $5::text IS NULL THEN contract_id ELSE NULLIF($5::text, '') END,
+    icon_url = CASE WHEN $6::text IS NULL THEN icon_url ELSE NULLIF($6::text, '') END,
+    active = COALESCE($7, active),
+    kyc_threshold = CASE WHEN $8::text IS NULL THEN kyc_threshold ELSE NULLIF($8::text, '')::numeric END,
+    updated_at = now()
+WHERE code = $1
+RETURNING `+currencyColumns+`
+`, code, name, decimals, chain, contractID, iconURL, active, kycThreshold))
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "currency_contract_exists"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(cur)
+	}
+}
+
+// validateCurrencyChain checks a currency's chain and contract against its kind.
+func validateCurrencyChain(v *validate.Validator, kind, chain, contractID string) {
+	if kind == "fiat" {
+		if chain != "" {
+			v.Fail("chain", validate.Invalid, "fiat currencies have no chain")
+		}
+		if contractID != "" {
+			v.Fail("contract_id", validate.Invalid, "fiat currencies have no contract")
+		}
+		return
+	}
+	if v.Required("chain", chain) {
+		v.MaxLen("chain", chain, 50)
+	}
+	v.MaxLen("contract_id", contractID, 200)
+}
+
+type currencyRate struct {
+	ID         uuid.UUID  `json:"id"`
+	Currency   string     `json:"currency"`
+	Quote      string     `json:"quote"`
+	Rate       string     `json:"rate"`
+	Source     *string    `json:"source"`
+	CapturedAt time.Time  `json:"captured_at"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+}
+
+type currencyRateRequest struct {
+	Quote      string      `json:"quote"`
+	Rate       json.Number `json:"rate"`
+	Source     string      `json:"source"`
+	CapturedAt *time.Time  `json:"captured_at"`
+}
+
+// AddRate serves POST /admin/currencies/:code/rates: records what one unit of the currency was worth in
+// quote (USD by default) at captured_at (now by default). Snapshots are kept, not replaced, so reports
+// convert old payouts at the rate of their day.
+func (h *CurrenciesHandler) AddRate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		code := strings.ToUpper(c.Params("code"))
+		var req currencyRateRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Quote = strings.ToUpper(strings.TrimSpace(req.Quote))
+		if req.Quote == "" {
+			req.Quote = "USD"
+		}
+		req.Source = strings.TrimSpace(req.Source)
+
+		var v validate.Validator
+		if req.Quote == code {
+			v.Fail("quote", validate.Invalid, "must differ from the currency")
+		}
+		if r, ok := new(big.Rat).SetString(req.Rate.String()); !ok || r.Sign() <= 0 {
+			v.Fail("rate", validate.Invalid, "must be a positive number")
+		}
+		v.MaxLen("source", req.Source, 200)
+		if req.CapturedAt != nil && req.CapturedAt.After(time.Now().Add(time.Minute)) {
+			v.Fail("captured_at", validate.Invalid, "must not be in the future")
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		var known int
+		_ = h.db.Pool.QueryRow(c.Context(), `SELECT COUNT(*) FROM currencies WHERE code IN ($1, $2)`, code, req.Quote).Scan(&known)
+		if known != 2 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "currency_not_found"})
+		}
+
+		r := currencyRate{Currency: code, Quote: req.Quote}
+		err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO currency_rates (currency_code, quote_code, rate, source, captured_at, created_by)
+VALUES ($1, $2, $3::numeric, NULLIF($4, ''), COALESCE($5, now()), $6)
+RETURNING id, rate::text, source, captured_at, created_by
+`, code, req.Quote, req.Rate.String(), req.Source, req.CapturedAt, contentActor(c)).Scan(&r.ID, &r.Rate, &r.Source, &r.CapturedAt, &r.CreatedBy)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22003" {
+			return validationFailed(c, validate.Errors{{Field: "rate", Code: validate.Invalid, Message: "is out of range"}})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_rate_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(r)
+	}
+}
+
+// Rates serves GET /admin/currencies/:code/rates: the currency's rate snapshots, newest first. ?quote=
+// keeps one quote currency.
+func (h *CurrenciesHandler) Rates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		code := strings.ToUpper(c.Params("code"))
+		quote := strings.ToUpper(strings.TrimSpace(c.Query("quote")))
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, currency_code, quote_code, rate::text, source, captured_at, created_by
+FROM currency_rates
+WHERE currency_code = $1 AND (NULLIF($2, '') IS NULL OR quote_code = $2)
+ORDER BY captured_at DESC
+LIMIT 500
+`, code, quote)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_rates_fetch_failed"})
+		}
+		defer rows.Close()
+		out := []currencyRate{}
+		for rows.Next() {
+			var r currencyRate
+			if err := rows.Scan(&r.ID, &r.Currency, &r.Quote, &r.Rate, &r.Source, &r.CapturedAt, &r.CreatedBy); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_rates_fetch_failed"})
+			}
+			out = append(out, r)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "currency_rates_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"rates": out})
+	}
+}
+
+// PayoutsReport serves GET /admin/currencies/payouts-report: on-chain payouts per currency between from
+// and to (as for exports), and their value in ?unit= (USD by default). Each payout is converted at the
+// latest rate captured before it was paid, or the earliest after when there is none; payouts in currencies
+// with no rate to the unit are counted in unconverted and left out of the converted amounts.
+func (h *CurrenciesHandler) PayoutsReport() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		unit := strings.ToUpper(strings.TrimSpace(c.Query("unit", "USD")))
+		from, to, err := parseExportRange(c.Query("from"), c.Query("to"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_range", "message": err.Error()})
+		}
+		var unitDecimals int
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT decimals FROM currencies WHERE code = $1`, unit).Scan(&unitDecimals)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown_unit"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_report_failed"})
+		}
+
+		// A payout's currency is the event's, else its ecosystem's reward currency, else the currency
+		// registered for the configured token contract. Amounts are in base units.
+		rows, err := h.db.Pool.Query(c.Context(), `
+WITH payouts AS (
+  SELECT oe.amount, to_timestamp(oe.event_timestamp) AS paid_at,
+         COALESCE(oe.currency_code,
+                  (SELECT e.reward_currency FROM ecosystems e WHERE e.program_id = oe.program_id AND e.reward_currency IS NOT NULL LIMIT 1),
+                  (SELECT cur.code FROM currencies cur WHERE cur.contract_id = NULLIF($3, '') LIMIT 1)) AS currency
+  FROM onchain_events oe
+  WHERE oe.topic IN ('f_rel', 'Payout', 'BatchPay') AND oe.amount IS NOT NULL
+    AND to_timestamp(oe.event_timestamp) >= $1 AND to_timestamp(oe.event_timestamp) < $2
+), converted AS (
+  SELECT p.currency, p.amount / power(10::numeric, cur.decimals) AS value,
+         CASE WHEN p.currency = $4 THEN 1 ELSE r.rate END AS rate
+  FROM payouts p
+  LEFT JOIN currencies cur ON cur.code = p.currency
+  LEFT JOIN LATERAL (
+    SELECT cr.rate FROM currency_rates cr
+    WHERE cr.currency_code = p.currency AND cr.quote_code = $4
+    ORDER BY (cr.captured_at <= p.paid_at) DESC,
+             CASE WHEN cr.captured_at <= p.paid_at THEN cr.captured_at END DESC,
+             cr.captured_at
+    LIMIT 1
+  ) r ON true
+)
+SELECT GROUPING(currency) = 1, currency, COUNT(*),
+       COALESCE(SUM(value), 0)::text,
+       ROUND(COALESCE(SUM(value * rate), 0), $5)::text,
+       COUNT(*) FILTER (WHERE value IS NULL OR rate IS NULL)
+FROM converted
+GROUP BY ROLLUP (currency)
+ORDER BY GROUPING(currency), currency
+`, from, to, h.cfg.TokenContractID, unit, unitDecimals)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_report_failed"})
+		}
+		defer rows.Close()
+
+		type line struct {
+			Currency    *string `json:"currency"`
+			Payouts     int64   `json:"payouts"`
+			Amount      string  `json:"amount,omitempty"`
+			Converted   string  `json:"converted"`
+			Unconverted int64   `json:"unconverted"`
+		}
+		currencies := []line{}
+		total := line{Converted: "0"}
+		for rows.Next() {
+			var l line
+			var isTotal bool
+			if err := rows.Scan(&isTotal, &l.Currency, &l.Payouts, &l.Amount, &l.Converted, &l.Unconverted); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_report_failed"})
+			}
+			if isTotal {
+				// Amounts in different currencies don't add up; only the converted total does.
+				l.Amount = ""
+				total = l
+				continue
+			}
+			currencies = append(currencies, l)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_report_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"unit":       unit,
+			"from":       from,
+			"to":         to,
+			"currencies": currencies,
+			"total":      total,
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/dataquality"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// DataQualityHandler reports records that disagree with each other or with GitHub, and reconciles them.
+type DataQualityHandler struct {
+	db *db.DB
+}
+
+func NewDataQualityHandler(d *db.DB) *DataQualityHandler {
+	return &DataQualityHandler{db: d}
+}
+
+// Report serves GET /admin/data-quality: every check with its count and up to `limit` (default 20,
+// max 200) findings. ?kind= runs a single check.
+func (h *DataQualityHandler) Report() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		limit := c.QueryInt("limit", 20)
+		if limit < 0 || limit > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_limit"})
+		}
+		if v := c.Query("kind"); v != "" {
+			kind, ok := dataquality.ParseKind(v)
+			if !ok {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_kind"})
+			}
+			s, err := dataquality.Check(c.Context(), h.db.Pool, kind, limit)
+			if err != nil {
+				slog.Error("data quality check failed", "kind", kind, "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "data_quality_check_failed"})
+			}
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"checks": []dataquality.Section{s}})
+		}
+		sections, err := dataquality.Report(c.Context(), h.db.Pool, limit)
+		if err != nil {
+			slog.Error("data quality report failed", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "data_quality_check_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"checks": sections})
+	}
+}
+
+// Reconcile serves POST /admin/data-quality/:kind/reconcile: fixes what the check finds, directly or by
+// queueing sync jobs (see dataquality.Reconcile).
+func (h *DataQualityHandler) Reconcile() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		kind, ok := dataquality.ParseKind(c.Params("kind"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_kind"})
+		}
+		res, err := dataquality.Reconcile(c.Context(), h.db.Pool, kind)
+		if err != nil {
+			slog.Error("data quality reconciliation failed", "kind", kind, "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reconcile_failed"})
+		}
+		actor, _ := c.Locals(auth.LocalUserID).(string)
+		slog.Info("data quality reconciled", "kind", kind, "by", actor,
+			"fixed", res.Fixed, "queued", res.Queued, "notified", res.Notified)
+		return c.Status(fiber.StatusAccepted).JSON(res)
+	}
+}
@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// UndoWindow is how long an undoable assign or reject waits before it runs, so a misclick can be taken
+// back before anything is visible on GitHub.
+const UndoWindow = 60 * time.Second
+
+// MaxActionAttempts is how many times a queued action is tried when GitHub keeps failing.
+const MaxActionAttempts = 6
+
+// deferredActionsInterval is how often due actions are run.
+const deferredActionsInterval = 5 * time.Second
+
+// botCommentRetry is the payload of a queued bot comment: the body as rendered when it was requested.
+type botCommentRetry struct {
+	Body string `json:"body"`
+}
+
+// retryDelay is the wait before the next try after attempts failed ones.
+func retryDelay(attempts int) time.Duration {
+	return time.Duration(attempts*attempts) * time.Minute
+}
+
+func (h *IssueApplicationsHandler) insertDeferredAction(c *fiber.Ctx, projectID, userID uuid.UUID, issueNumber int, action, reason string, payload any, executeAt time.Time, attempts int, lastError *string) (uuid.UUID, error) {
+	role, _ := c.Locals(auth.LocalRole).(string)
+	payloadJSON, _ := json.Marshal(payload)
+	var id uuid.UUID
+	err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO deferred_issue_actions (project_id, issue_number, action, payload, requested_by_user_id, requested_by_role, execute_at, reason, attempts, last_error)
+VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7, $8, $9, $10)
+RETURNING id
+`, projectID, issueNumber, action, payloadJSON, userID, role, executeAt, reason, attempts, lastError).Scan(&id)
+	return id, err
+}
+
+// deferIssueAction queues an assign or reject to run after UndoWindow and answers 202 with the pending
+// action, which DELETE .../pending-actions/:actionId cancels.
+func (h *IssueApplicationsHandler) deferIssueAction(c *fiber.Ctx, issueNumber int, action string, payload any) error {
+	projectID, userID, status, body := h.draftProject(c)
+	if status != fiber.StatusOK {
+		return c.Status(status).JSON(body)
+	}
+	executeAt := time.Now().UTC().Add(UndoWindow)
+	id, err := h.insertDeferredAction(c, projectID, userID, issueNumber, action, "undo", payload, executeAt, 0, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "action_schedule_failed"})
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"ok":                true,
+		"pending_action_id": id.String(),
+		"action":            action,
+		"execute_at":        executeAt,
+		"undo_url":          fmt.Sprintf("/projects/%s/issues/%d/pending-actions/%s", projectID, issueNumber, id),
+	})
+}
+
+// queueForRetry keeps an action that failed on GitHub's side (502) for automatic retry and answers 202
+// with the queued action. If it can't be queued, the original failure is returned.
+func (h *IssueApplicationsHandler) queueForRetry(c *fiber.Ctx, projectID uuid.UUID, issueNumber int, action string, payload any, failure fiber.Map) error {
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(failure)
+	}
+	lastError, _ := failure["error"].(string)
+	executeAt := time.Now().UTC().Add(retryDelay(1))
+	id, err := h.insertDeferredAction(c, projectID, userID, issueNumber, action, "retry", payload, executeAt, 1, &lastError)
+	if err != nil {
+		slog.Error("failed to queue action for retry", "project_id", projectID.String(), "action", action, "error", err)
+		return c.Status(fiber.StatusBadGateway).JSON(failure)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"ok":                true,
+		"queued":            true,
+		"pending_action_id": id.String(),
+		"action":            action,
+		"last_error":        lastError,
+		"retry_at":          executeAt,
+		"cancel_url":        fmt.Sprintf("/projects/%s/issues/%d/pending-actions/%s", projectID, issueNumber, id),
+	})
+}
+
+// PendingActions serves GET /projects/:id/issues/:number/pending-actions: the issue's queued actions
+// (undoable or awaiting retry), pending or recently run. Maintainer only.
+func (h *IssueApplicationsHandler) PendingActions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, issueNumber, status, body := h.promotionIssue(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+deferredActionColumns+`
+FROM deferred_issue_actions
+WHERE project_id = $1 AND issue_number = $2 AND created_at > now() - interval '1 day'
+ORDER BY created_at DESC
+LIMIT 20
+`, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pending_actions_lookup_failed"})
+		}
+		out, err := scanDeferredActions(rows)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pending_actions_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"pending_actions": out})
+	}
+}
+
+// MyPendingActions serves GET /me/pending-actions: the caller's queued actions across issues that are still
+// pending or running, or failed in the past day.
+func (h *IssueApplicationsHandler) MyPendingActions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+deferredActionColumns+`
+FROM deferred_issue_actions
+WHERE requested_by_user_id = $1
+  AND (status IN ('pending', 'running') OR (status = 'failed' AND updated_at > now() - interval '1 day'))
+ORDER BY created_at DESC
+LIMIT 100
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pending_actions_lookup_failed"})
+		}
+		out, err := scanDeferredActions(rows)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pending_actions_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"pending_actions": out})
+	}
+}
+
+const deferredActionColumns = `id, project_id, issue_number, action, reason, payload, status, attempts, last_error, execute_at,
+  result_status, result, created_at`
+
+func scanDeferredActions(rows pgx.Rows) ([]fiber.Map, error) {
+	defer rows.Close()
+	out := []fiber.Map{}
+	for rows.Next() {
+		var id, projectID uuid.UUID
+		var issueNumber, attempts int
+		var action, reason, st string
+		var payload []byte
+		var lastError *string
+		var result *json.RawMessage
+		var executeAt, createdAt time.Time
+		var resultStatus *int
+		if err := rows.Scan(&id, &projectID, &issueNumber, &action, &reason, &payload, &st, &attempts, &lastError, &executeAt,
+			&resultStatus, &result, &createdAt); err != nil {
+			return nil, err
+		}
+		out = append(out, fiber.Map{
+			"id":            id.String(),
+			"project_id":    projectID.String(),
+			"issue_number":  issueNumber,
+			"action":        action,
+			"reason":        reason,
+			"payload":       json.RawMessage(payload),
+			"status":        st,
+			"attempts":      attempts,
+			"last_error":    lastError,
+			"execute_at":    executeAt,
+			"result_status": resultStatus,
+			"result":        result,
+			"created_at":    createdAt,
+		})
+	}
+	return out, rows.Err()
+}
+
+// UndoPendingAction serves DELETE /projects/:id/issues/:number/pending-actions/:actionId, cancelling an
+// action that hasn't run yet. Maintainer only.
+func (h *IssueApplicationsHandler) UndoPendingAction() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, issueNumber, status, body := h.promotionIssue(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		actionID, err := uuid.Parse(c.Params("actionId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_action_id"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE deferred_issue_actions SET status = 'cancelled', updated_at = now()
+WHERE id = $1 AND project_id = $2 AND issue_number = $3 AND status = 'pending'
+`, actionID, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "undo_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			var current string
+			err := h.db.Pool.QueryRow(c.Context(), `
+SELECT status FROM deferred_issue_actions WHERE id = $1 AND project_id = $2 AND issue_number = $3
+`, actionID, projectID, issueNumber).Scan(&current)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "pending_action_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "undo_failed"})
+			}
+			// Too late: it is running or has run (or was already cancelled).
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "action_not_pending", "status": current})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// DeferredIssueActions runs queued maintainer actions: undoable assigns and rejects once their undo window
+// has passed, and actions retried after GitHub failed.
+type DeferredIssueActions struct {
+	h *IssueApplicationsHandler
+}
+
+func NewDeferredIssueActions(cfg config.Config, d *db.DB, gh github.API, apps github.Apps) *DeferredIssueActions {
+	return &DeferredIssueActions{h: NewIssueApplicationsHandler(cfg, d, gh, apps)}
+}
+
+func (j *DeferredIssueActions) Run(ctx context.Context) error {
+	if j.h.db == nil || j.h.db.Pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(deferredActionsInterval)
+	defer t.Stop()
+
+	for {
+		if err := j.RunDue(ctx); err != nil {
+			slog.Error("deferred issue actions failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// RunDue runs the actions that are due. One that fails on GitHub's side again is rescheduled until
+// MaxActionAttempts.
+func (j *DeferredIssueActions) RunDue(ctx context.Context) error {
+	pool := j.h.db.Pool
+	// An action left running by a crashed process may or may not have reached GitHub; don't retry it.
+	_, _ = pool.Exec(ctx, `
+UPDATE deferred_issue_actions
+SET status = 'failed', result = '{"error":"interrupted"}'::jsonb, updated_at = now()
+WHERE status = 'running' AND updated_at < now() - interval '10 minutes'
+`)
+
+	rows, err := pool.Query(ctx, `
+UPDATE deferred_issue_actions SET status = 'running', updated_at = now()
+WHERE id IN (
+  SELECT id FROM deferred_issue_actions
+  WHERE status = 'pending' AND execute_at <= now()
+  ORDER BY execute_at
+  LIMIT 20
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, project_id, issue_number, action, payload, requested_by_user_id, requested_by_role, attempts
+`)
+	if err != nil {
+		return err
+	}
+	type due struct {
+		id, projectID, userID uuid.UUID
+		issueNumber, attempts int
+		action, role          string
+		payload               []byte
+	}
+	var list []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.id, &d.projectID, &d.issueNumber, &d.action, &d.payload, &d.userID, &d.role, &d.attempts); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, d := range list {
+		status, out := fiber.StatusBadRequest, fiber.Map{"error": "unknown_action"}
+		switch d.action {
+		case "assign":
+			var req assignRequest
+			if json.Unmarshal(d.payload, &req) == nil {
+				status, out = j.h.assign(ctx, d.userID, d.role, d.projectID, d.issueNumber, req)
+			}
+		case "reject":
+			var req rejectRequest
+			if json.Unmarshal(d.payload, &req) == nil {
+				status, out = j.h.reject(ctx, d.userID, d.role, d.projectID, d.issueNumber, req.Assignee)
+			}
+		case "comment":
+			var req botCommentRetry
+			if json.Unmarshal(d.payload, &req) == nil {
+				status, out = j.h.queuedBotComment(ctx, d.userID, d.role, d.projectID, d.issueNumber, req.Body)
+			}
+		}
+
+		if status == fiber.StatusBadGateway && d.attempts+1 < MaxActionAttempts {
+			lastError, _ := out["error"].(string)
+			if _, err := pool.Exec(ctx, `
+UPDATE deferred_issue_actions
+SET status = 'pending', attempts = attempts + 1, last_error = $2, execute_at = $3, updated_at = now()
+WHERE id = $1
+`, d.id, lastError, time.Now().UTC().Add(retryDelay(d.attempts+1))); err != nil {
+				slog.Error("failed to reschedule queued action", "action_id", d.id.String(), "error", err)
+			}
+			continue
+		}
+		final := "done"
+		if status >= 300 {
+			final = "failed"
+			slog.Warn("deferred issue action failed", "action_id", d.id.String(), "action", d.action, "status", status, "result", out)
+		}
+		resultJSON, _ := json.Marshal(out)
+		if _, err := pool.Exec(ctx, `
+UPDATE deferred_issue_actions
+SET status = $2, result_status = $3, result = $4::jsonb, attempts = attempts + 1, updated_at = now()
+WHERE id = $1
+`, d.id, final, status, resultJSON); err != nil {
+			slog.Error("failed to record deferred issue action result", "action_id", d.id.String(), "error", err)
+		}
+	}
+	return nil
+}
+
+// queuedBotComment posts a queued bot comment, checking again that the requester maintains the project.
+func (h *IssueApplicationsHandler) queuedBotComment(ctx context.Context, userID uuid.UUID, role string, projectID uuid.UUID, issueNumber int, body string) (int, fiber.Map) {
+	var owner uuid.UUID
+	var fullName, installationID string
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner, &fullName, &installationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusNotFound, fiber.Map{"error": "project_not_found"}
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "project_lookup_failed"}
+	}
+	if owner != userID && role != "admin" {
+		return fiber.StatusForbidden, fiber.Map{"error": "forbidden"}
+	}
+	if installationID == "" {
+		return fiber.StatusBadRequest, fiber.Map{"error": "project_has_no_github_app_installation"}
+	}
+	return h.postBotComment(ctx, projectID, issueNumber, fullName, installationID, body)
+}
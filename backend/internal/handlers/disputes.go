@@ -0,0 +1,604 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// DisputesHandler is contributors' recourse against a rejected application, a failed or cancelled payout,
+// or a completed issue that was never paid. A new dispute is assigned to a staff member (an admin or a
+// holder of disputes:write), preferring those who receive the ecosystem's reports and then whoever has the
+// fewest open disputes. Contributor and staff exchange evidence in the dispute's thread until staff
+// uphold or deny it; each side is notified of the other's moves.
+type DisputesHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewDisputesHandler(cfg config.Config, d *db.DB) *DisputesHandler {
+	return &DisputesHandler{cfg: cfg, db: d}
+}
+
+// Dispute kinds and statuses.
+const (
+	disputeApplication = "application"
+	disputePayout      = "payout"
+	disputeNonPayment  = "non_payment"
+
+	disputeOpen      = "open"
+	disputeUpheld    = "upheld"
+	disputeDenied    = "denied"
+	disputeWithdrawn = "withdrawn"
+)
+
+type dispute struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Login         *string    `json:"login"`
+	Kind          string     `json:"kind"`
+	ApplicationID *uuid.UUID `json:"application_id"`
+	PayoutID      *uuid.UUID `json:"payout_id"`
+	ProjectID     *uuid.UUID `json:"project_id"`
+	IssueNumber   *int       `json:"issue_number"`
+	EcosystemID   *uuid.UUID `json:"ecosystem_id"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	AssigneeID    *uuid.UUID `json:"assignee_id"`
+	AssigneeLogin *string    `json:"assignee_login"`
+	Resolution    *string    `json:"resolution"`
+	ResolvedBy    *uuid.UUID `json:"resolved_by"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+const disputeColumns = `d.id, d.user_id, (SELECT login FROM github_accounts WHERE user_id = d.user_id), d.kind, d.application_id,
+       d.payout_id, d.project_id, d.issue_number, d.ecosystem_id, d.reason, d.status, d.assignee_id,
+       (SELECT login FROM github_accounts WHERE user_id = d.assignee_id), d.resolution, d.resolved_by, d.resolved_at,
+       d.created_at, d.updated_at`
+
+func scanDispute(row pgx.Row) (dispute, error) {
+	var d dispute
+	err := row.Scan(&d.ID, &d.UserID, &d.Login, &d.Kind, &d.ApplicationID, &d.PayoutID, &d.ProjectID, &d.IssueNumber,
+		&d.EcosystemID, &d.Reason, &d.Status, &d.AssigneeID, &d.AssigneeLogin, &d.Resolution, &d.ResolvedBy,
+		&d.ResolvedAt, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+// disputeStaff matches the users u disputes can be assigned to.
+const disputeStaff = `(u.role = 'admin' OR 'disputes:write' = ANY(u.admin_scopes))`
+
+type disputeOpenRequest struct {
+	Kind          string     `json:"kind"` // application|payout|non_payment
+	ApplicationID *uuid.UUID `json:"application_id"`
+	PayoutID      *uuid.UUID `json:"payout_id"`
+	ProjectID     *uuid.UUID `json:"project_id"` // with issue_number, for non_payment
+	IssueNumber   *int       `json:"issue_number"`
+	Reason        string     `json:"reason"`
+	// Evidence and Links open the dispute's thread.
+	Evidence string   `json:"evidence"`
+	Links    []string `json:"links"`
+}
+
+// Open serves POST /me/disputes. Applications can be disputed once rejected or unassigned, payouts once
+// failed or cancelled, and issues the user completed for as long as no payout for them has been paid.
+// A subject has one open dispute at a time.
+func (h *DisputesHandler) Open() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var req disputeOpenRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Kind, req.Reason, req.Evidence = strings.TrimSpace(req.Kind), strings.TrimSpace(req.Reason), strings.TrimSpace(req.Evidence)
+		var v validate.Validator
+		if v.Required("kind", req.Kind) && v.OneOf("kind", req.Kind, disputeApplication, disputePayout, disputeNonPayment) {
+			switch {
+			case req.Kind == disputeApplication && req.ApplicationID == nil:
+				v.Required("application_id", "")
+			case req.Kind == disputePayout && req.PayoutID == nil:
+				v.Required("payout_id", "")
+			case req.Kind == disputeNonPayment && (req.ProjectID == nil || req.IssueNumber == nil):
+				v.Fail("issue_number", validate.Invalid, "project_id and issue_number are required")
+			}
+		}
+		if v.Required("reason", req.Reason) {
+			v.MaxLen("reason", req.Reason, 500)
+		}
+		v.MaxLen("evidence", req.Evidence, 10000)
+		validateDisputeLinks(&v, req.Links)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		d := dispute{UserID: userID, Kind: req.Kind, Reason: req.Reason}
+		if errCode, status := h.checkSubject(c.Context(), &d, req); errCode != "" {
+			return c.Status(status).JSON(fiber.Map{"error": errCode})
+		}
+
+		err = pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			var err error
+			d, err = scanDispute(tx.QueryRow(c.Context(), `
+WITH ins AS (
+  INSERT INTO disputes (user_id, kind, application_id, payout_id, project_id, issue_number, ecosystem_id, reason, assignee_id)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (
+    SELECT u.id FROM users u
+    WHERE `+disputeStaff+` AND u.id <> $1
+    ORDER BY EXISTS (SELECT 1 FROM ecosystem_report_subscribers s WHERE s.ecosystem_id = $7 AND s.user_id = u.id) DESC,
+             (SELECT count(*) FROM disputes o WHERE o.assignee_id = u.id AND o.status = 'open'), u.created_at
+    LIMIT 1))
+  RETURNING *
+)
+SELECT `+disputeColumns+` FROM ins d
+`, userID, d.Kind, d.ApplicationID, d.PayoutID, d.ProjectID, d.IssueNumber, d.EcosystemID, d.Reason))
+			if err != nil {
+				return err
+			}
+			if req.Evidence == "" && len(req.Links) == 0 {
+				return nil
+			}
+			if req.Evidence == "" {
+				req.Evidence = d.Reason
+			}
+			_, err = tx.Exec(c.Context(), `
+INSERT INTO dispute_messages (dispute_id, author_id, body, links) VALUES ($1, $2, $3, $4)
+`, d.ID, userID, req.Evidence, nonNilStrings(req.Links))
+			return err
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "dispute_exists"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_create_failed"})
+		}
+		if d.AssigneeID != nil {
+			h.notify(c.Context(), *d.AssigneeID, "dispute_assigned", d)
+		} else {
+			slog.Warn("dispute opened with no staff to assign it to", "dispute_id", d.ID)
+		}
+		return c.Status(fiber.StatusCreated).JSON(d)
+	}
+}
+
+// checkSubject checks that the user may dispute req's subject and fills in d's subject from it. It
+// returns an error code and status when they may not.
+func (h *DisputesHandler) checkSubject(ctx context.Context, d *dispute, req disputeOpenRequest) (string, int) {
+	var status string
+	var err error
+	switch d.Kind {
+	case disputeApplication:
+		d.ApplicationID = req.ApplicationID
+		err = h.db.Pool.QueryRow(ctx, `
+SELECT project_id, issue_number, status FROM issue_applications WHERE id = $1 AND user_id = $2
+`, req.ApplicationID, d.UserID).Scan(&d.ProjectID, &d.IssueNumber, &status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "application_not_found", fiber.StatusNotFound
+		}
+		if err == nil && status != "rejected" && status != "unassigned" {
+			return "not_disputable", fiber.StatusConflict
+		}
+	case disputePayout:
+		d.PayoutID = req.PayoutID
+		err = h.db.Pool.QueryRow(ctx, `
+SELECT project_id, issue_number, status FROM payouts WHERE id = $1 AND recipient_user_id = $2
+`, req.PayoutID, d.UserID).Scan(&d.ProjectID, &d.IssueNumber, &status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "payout_not_found", fiber.StatusNotFound
+		}
+		if err == nil && status != "failed" && status != "cancelled" {
+			return "not_disputable", fiber.StatusConflict
+		}
+	case disputeNonPayment:
+		d.ProjectID, d.IssueNumber = req.ProjectID, req.IssueNumber
+		var paid bool
+		err = h.db.Pool.QueryRow(ctx, `
+SELECT EXISTS (SELECT 1 FROM payouts p
+               WHERE p.project_id = c.project_id AND p.issue_number = c.issue_number
+                 AND p.recipient_user_id = $3 AND p.status = 'paid')
+FROM issue_completions c
+JOIN github_accounts ga ON LOWER(ga.login) = LOWER(c.assignee_login)
+WHERE c.project_id = $1 AND c.issue_number = $2 AND ga.user_id = $3
+`, req.ProjectID, req.IssueNumber, d.UserID).Scan(&paid)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "completion_not_found", fiber.StatusNotFound
+		}
+		if err == nil && paid {
+			return "not_disputable", fiber.StatusConflict
+		}
+	}
+	if err == nil && d.ProjectID != nil {
+		err = h.db.Pool.QueryRow(ctx, `SELECT ecosystem_id FROM projects WHERE id = $1`, d.ProjectID).Scan(&d.EcosystemID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return "dispute_create_failed", fiber.StatusInternalServerError
+	}
+	return "", 0
+}
+
+// Mine serves GET /me/disputes: the user's disputes, newest first.
+func (h *DisputesHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		list, err := h.list(c.Context(), `d.user_id = $1`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "disputes_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"disputes": list})
+	}
+}
+
+// List serves GET /admin/disputes: the dispute queue, oldest first. ?status= filters (default open);
+// ?assignee= is a user ID, "me" or "none".
+func (h *DisputesHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		status := strings.TrimSpace(c.Query("status", disputeOpen))
+		where, args := `($1 = 'all' OR d.status = $1)`, []any{status}
+		switch assignee := strings.TrimSpace(c.Query("assignee")); assignee {
+		case "":
+		case "none":
+			where += ` AND d.assignee_id IS NULL`
+		default:
+			if assignee == "me" {
+				assignee, _ = c.Locals(auth.LocalUserID).(string)
+			}
+			id, err := uuid.Parse(assignee)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_assignee"})
+			}
+			where, args = where+` AND d.assignee_id = $2`, append(args, id)
+		}
+		list, err := h.list(c.Context(), where+` ORDER BY d.created_at`, args...)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "disputes_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"disputes": list})
+	}
+}
+
+// list returns the disputes matching where, newest first unless where orders them.
+func (h *DisputesHandler) list(ctx context.Context, where string, args ...any) ([]dispute, error) {
+	if !strings.Contains(where, "ORDER BY") {
+		where += ` ORDER BY d.created_at DESC`
+	}
+	rows, err := h.db.Pool.Query(ctx, `SELECT `+disputeColumns+` FROM disputes d WHERE `+where+` LIMIT 500`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []dispute{}
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// Get serves GET /me/disputes/:id, for the user who opened the dispute.
+func (h *DisputesHandler) Get() fiber.Handler { return h.detail(true) }
+
+// AdminGet serves GET /admin/disputes/:id.
+func (h *DisputesHandler) AdminGet() fiber.Handler { return h.detail(false) }
+
+// detail answers a dispute with its thread. With own, only the user who opened it can see it.
+func (h *DisputesHandler) detail(own bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		d, ok := h.load(c, own)
+		if !ok {
+			return nil
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT m.id, m.author_id, (SELECT login FROM github_accounts WHERE user_id = m.author_id), m.body, m.links, m.created_at
+FROM dispute_messages m
+WHERE m.dispute_id = $1
+ORDER BY m.created_at
+`, d.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_fetch_failed"})
+		}
+		defer rows.Close()
+		messages := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var authorID *uuid.UUID
+			var login *string
+			var body string
+			var links []string
+			var createdAt time.Time
+			if err := rows.Scan(&id, &authorID, &login, &body, &links, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_fetch_failed"})
+			}
+			messages = append(messages, fiber.Map{
+				"id":           id,
+				"author_id":    authorID,
+				"author_login": login,
+				"from_staff":   authorID == nil || *authorID != d.UserID,
+				"body":         body,
+				"links":        links,
+				"created_at":   createdAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"dispute": d, "messages": messages})
+	}
+}
+
+// load reads the dispute in :id, answering 404 (and false) when it doesn't exist or, with own, isn't the
+// user's.
+func (h *DisputesHandler) load(c *fiber.Ctx, own bool) (dispute, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_dispute_id"})
+		return dispute{}, false
+	}
+	d, err := scanDispute(h.db.Pool.QueryRow(c.Context(), `SELECT `+disputeColumns+` FROM disputes d WHERE d.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && own && !isActor(c, d.UserID)) {
+		_ = c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "dispute_not_found"})
+		return dispute{}, false
+	}
+	if err != nil {
+		_ = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_fetch_failed"})
+		return dispute{}, false
+	}
+	return d, true
+}
+
+type disputeMessageRequest struct {
+	Body  string   `json:"body"`
+	Links []string `json:"links"`
+}
+
+// AddEvidence serves POST /me/disputes/:id/messages: the contributor adds to an open dispute's thread.
+// The assignee is notified.
+func (h *DisputesHandler) AddEvidence() fiber.Handler { return h.addMessage(true) }
+
+// Reply serves POST /admin/disputes/:id/messages: staff reply in an open dispute's thread. The
+// contributor is notified.
+func (h *DisputesHandler) Reply() fiber.Handler { return h.addMessage(false) }
+
+func (h *DisputesHandler) addMessage(own bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req disputeMessageRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Body = strings.TrimSpace(req.Body)
+		var v validate.Validator
+		if v.Required("body", req.Body) {
+			v.MaxLen("body", req.Body, 10000)
+		}
+		validateDisputeLinks(&v, req.Links)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		d, ok := h.load(c, own)
+		if !ok {
+			return nil
+		}
+		if d.Status != disputeOpen {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "dispute_closed", "status": d.Status})
+		}
+		var id uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO dispute_messages (dispute_id, author_id, body, links) VALUES ($1, $2, $3, $4) RETURNING id
+`, d.ID, contentActor(c), req.Body, nonNilStrings(req.Links)).Scan(&id); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_message_failed"})
+		}
+		_, _ = h.db.Pool.Exec(c.Context(), `UPDATE disputes SET updated_at = now() WHERE id = $1`, d.ID)
+		if !own {
+			h.notify(c.Context(), d.UserID, "dispute_message", d)
+		} else if d.AssigneeID != nil {
+			h.notify(c.Context(), *d.AssigneeID, "dispute_message", d)
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id})
+	}
+}
+
+// Withdraw serves POST /me/disputes/:id/withdraw: the contributor drops an open dispute.
+func (h *DisputesHandler) Withdraw() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		d, ok := h.load(c, true)
+		if !ok {
+			return nil
+		}
+		d, ok = h.close(c, d, disputeWithdrawn, "", nil)
+		if !ok {
+			return nil
+		}
+		if d.AssigneeID != nil {
+			h.notify(c.Context(), *d.AssigneeID, "dispute_withdrawn", d)
+		}
+		return c.Status(fiber.StatusOK).JSON(d)
+	}
+}
+
+type disputeResolveRequest struct {
+	Status     string `json:"status"` // upheld|denied
+	Resolution string `json:"resolution"`
+}
+
+// Resolve serves POST /admin/disputes/:id/resolve: upholds or denies an open dispute, with the resolution
+// the contributor is notified of. Putting an upheld dispute right (reopening the application, creating or
+// retrying the payout) is done through the usual endpoints.
+func (h *DisputesHandler) Resolve() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req disputeResolveRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Status, req.Resolution = strings.TrimSpace(req.Status), strings.TrimSpace(req.Resolution)
+		var v validate.Validator
+		v.OneOf("status", req.Status, disputeUpheld, disputeDenied)
+		if v.Required("resolution", req.Resolution) {
+			v.MaxLen("resolution", req.Resolution, 4000)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		d, ok := h.load(c, false)
+		if !ok {
+			return nil
+		}
+		d, ok = h.close(c, d, req.Status, req.Resolution, contentActor(c))
+		if !ok {
+			return nil
+		}
+		h.notify(c.Context(), d.UserID, "dispute_resolved", d)
+		return c.Status(fiber.StatusOK).JSON(d)
+	}
+}
+
+// close moves an open dispute to status, answering 409 dispute_closed (and false) if it isn't open.
+func (h *DisputesHandler) close(c *fiber.Ctx, d dispute, status, resolution string, by *uuid.UUID) (dispute, bool) {
+	out, err := scanDispute(h.db.Pool.QueryRow(c.Context(), `
+WITH upd AS (
+  UPDATE disputes
+  SET status = $2, resolution = NULLIF($3, ''), resolved_by = $4, resolved_at = now(), updated_at = now()
+  WHERE id = $1 AND status = 'open'
+  RETURNING *
+)
+SELECT `+disputeColumns+` FROM upd d
+`, d.ID, status, resolution, by))
+	if errors.Is(err, pgx.ErrNoRows) {
+		_ = c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "dispute_closed", "status": d.Status})
+		return dispute{}, false
+	}
+	if err != nil {
+		_ = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_update_failed"})
+		return dispute{}, false
+	}
+	return out, true
+}
+
+type disputeAssignRequest struct {
+	AssigneeID uuid.UUID `json:"assignee_id"`
+}
+
+// Assign serves POST /admin/disputes/:id/assign: hands an open dispute to another staff member, who is
+// notified.
+func (h *DisputesHandler) Assign() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req disputeAssignRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		d, ok := h.load(c, false)
+		if !ok {
+			return nil
+		}
+		if d.Status != disputeOpen {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "dispute_closed", "status": d.Status})
+		}
+		if req.AssigneeID == d.UserID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_assignee"})
+		}
+		d, err := scanDispute(h.db.Pool.QueryRow(c.Context(), `
+WITH upd AS (
+  UPDATE disputes SET assignee_id = $2, updated_at = now()
+  WHERE id = $1 AND status = 'open' AND EXISTS (SELECT 1 FROM users u WHERE u.id = $2 AND `+disputeStaff+`)
+  RETURNING *
+)
+SELECT `+disputeColumns+` FROM upd d
+`, d.ID, req.AssigneeID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_assignee"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dispute_update_failed"})
+		}
+		h.notify(c.Context(), req.AssigneeID, "dispute_assigned", d)
+		return c.Status(fiber.StatusOK).JSON(d)
+	}
+}
+
+// notify tells userID about d.
+func (h *DisputesHandler) notify(ctx context.Context, userID uuid.UUID, kind string, d dispute) {
+	notifyUser(ctx, h.db.Pool, userID, kind, fiber.Map{
+		"dispute_id":   d.ID.String(),
+		"kind":         d.Kind,
+		"status":       d.Status,
+		"project_id":   d.ProjectID,
+		"issue_number": d.IssueNumber,
+		"resolution":   d.Resolution,
+	})
+}
+
+// validateDisputeLinks checks evidence links, normalising them in place.
+func validateDisputeLinks(v *validate.Validator, links []string) {
+	if !v.MaxItems("links", len(links), 10) {
+		return
+	}
+	for i := range links {
+		links[i] = strings.TrimSpace(links[i])
+		if v.Required("links", links[i]) {
+			v.URL("links", &links[i])
+		}
+	}
+}
+
+// isActor reports whether the request is made by userID.
+func isActor(c *fiber.Ctx, userID uuid.UUID) bool {
+	actor := contentActor(c)
+	return actor != nil && *actor == userID
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
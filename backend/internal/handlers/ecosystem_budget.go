@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/ledger"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+type budgetBalance struct {
+	Currency  string    `json:"currency"`
+	Decimals  int       `json:"decimals"`
+	Available string    `json:"available"`
+	Locked    string    `json:"locked"`
+	Released  string    `json:"released"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Budget serves GET /admin/ecosystems/:id/budget: the ecosystem's reward budget per currency (available,
+// locked in escrow for assigned issues, released to contributors) and its latest ledger transactions.
+func (h *EcosystemsAdminHandler) Budget() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		// Released is what the ecosystem's release transactions credited to contributors.
+		rows, err := h.db.Pool.Query(c.Context(), `
+WITH balances AS (
+  SELECT asset,
+         COALESCE(SUM(balance) FILTER (WHERE kind = $2), 0) AS available,
+         COALESCE(SUM(balance) FILTER (WHERE kind = $3), 0) AS locked,
+         MAX(updated_at) AS updated_at
+  FROM ledger_accounts
+  WHERE kind IN ($2, $3) AND owner = $1::text
+  GROUP BY asset
+), released AS (
+  SELECT a.asset, SUM(e.amount) AS released
+  FROM ledger_transactions t
+  JOIN ledger_entries e ON e.transaction_id = t.id
+  JOIN ledger_accounts a ON a.id = e.account_id
+  WHERE t.ecosystem_id = $1 AND t.kind = $4 AND a.kind = $5
+  GROUP BY a.asset
+)
+SELECT b.asset, c.decimals, b.available::text, b.locked::text, COALESCE(r.released, 0)::text, b.updated_at
+FROM balances b
+JOIN currencies c ON c.code = b.asset
+LEFT JOIN released r ON r.asset = b.asset
+ORDER BY b.asset
+`, ecoID, ledger.AccountBudget, ledger.AccountEscrow, ledger.KindRelease, ledger.AccountContributor)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "budget_lookup_failed"})
+		}
+		balances := []budgetBalance{}
+		for rows.Next() {
+			var b budgetBalance
+			if err := rows.Scan(&b.Currency, &b.Decimals, &b.Available, &b.Locked, &b.Released, &b.UpdatedAt); err != nil {
+				rows.Close()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "budget_lookup_failed"})
+			}
+			balances = append(balances, b)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "budget_lookup_failed"})
+		}
+
+		txs, err := listLedgerTransactions(c.Context(), h.db.Pool, ledgerFilter{ecosystemID: &ecoID, limit: c.QueryInt("limit", 100)})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "budget_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"balances": balances, "transactions": txs})
+	}
+}
+
+type budgetMovementRequest struct {
+	Kind     string `json:"kind"` // deposit|withdraw
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"` // base units
+	Note     string `json:"note"`
+}
+
+// FundBudget serves POST /admin/ecosystems/:id/budget: deposits into or withdraws from the ecosystem's
+// budget in a currency. Only the available balance can be withdrawn; locked rewards stay in escrow.
+func (h *EcosystemsAdminHandler) FundBudget() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req budgetMovementRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Kind = strings.ToLower(strings.TrimSpace(req.Kind))
+		req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+		req.Amount, req.Note = strings.TrimSpace(req.Amount), strings.TrimSpace(req.Note)
+
+		var v validate.Validator
+		v.OneOf("kind", req.Kind, rewards.KindDeposit, rewards.KindWithdraw)
+		v.Required("currency", req.Currency)
+		if n, ok := new(big.Int).SetString(req.Amount, 10); !ok || n.Sign() <= 0 || len(req.Amount) > 78 {
+			v.Fail("amount", validate.Invalid, "must be a positive whole number of base units")
+		}
+		v.MaxLen("note", req.Note, 1000)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		var known bool
+		_ = h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM currencies WHERE code = $1)`, req.Currency).Scan(&known)
+		if !known {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown_currency"})
+		}
+
+		err := rewards.Fund(c.Context(), h.db.Pool, ecoID, req.Currency, req.Kind, req.Amount, contentActor(c), req.Note)
+		if errors.Is(err, rewards.ErrInsufficientBudget) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "budget_insufficient"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "budget_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
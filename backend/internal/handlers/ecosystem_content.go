@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/ecocontent"
+)
+
+type contentVersion struct {
+	ID          uuid.UUID          `json:"id"`
+	Version     int                `json:"version"`
+	Status      string             `json:"status"`
+	Blocks      []ecocontent.Block `json:"blocks,omitempty"`
+	Note        *string            `json:"note"`
+	CreatedBy   *uuid.UUID         `json:"created_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	PublishedBy *uuid.UUID         `json:"published_by"`
+	PublishedAt *time.Time         `json:"published_at"`
+}
+
+type contentDraftRequest struct {
+	Blocks []ecocontent.Block `json:"blocks"`
+	Note   *string            `json:"note"`
+}
+
+// Content serves GET /admin/ecosystems/:id/content: the published and draft versions. Ecosystems that
+// predate versioning show their legacy columns as an unsaved published version 0.
+func (h *EcosystemsAdminHandler) Content() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		published, err := h.contentVersionWhere(c.Context(), ecoID, `status = 'published'`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		if published == nil {
+			legacy, err := h.legacyContent(c.Context(), ecoID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+			}
+			published = &contentVersion{Status: "published", Blocks: ecocontent.FromLegacy(legacy)}
+		}
+		draft, err := h.contentVersionWhere(c.Context(), ecoID, `status = 'draft'`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"published": published, "draft": draft})
+	}
+}
+
+// SaveDraft serves PUT /admin/ecosystems/:id/content/draft: creates or replaces the ecosystem's draft.
+func (h *EcosystemsAdminHandler) SaveDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		userID := contentActor(c)
+		var req contentDraftRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if req.Blocks == nil {
+			req.Blocks = []ecocontent.Block{}
+		}
+		if err := ecocontent.Validate(req.Blocks); err != nil {
+			return validationFailed(c, err)
+		}
+		blocksJSON, _ := json.Marshal(req.Blocks)
+
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			if err := h.snapshotLegacy(c.Context(), tx, ecoID); err != nil {
+				return err
+			}
+			ct, err := tx.Exec(c.Context(), `
+UPDATE ecosystem_content_versions
+SET blocks = $2, note = $3, created_by = COALESCE($4, created_by), updated_at = now()
+WHERE ecosystem_id = $1 AND status = 'draft'
+`, ecoID, blocksJSON, req.Note, userID)
+			if err != nil || ct.RowsAffected() > 0 {
+				return err
+			}
+			_, err = tx.Exec(c.Context(), `
+INSERT INTO ecosystem_content_versions (ecosystem_id, version, status, blocks, note, created_by)
+SELECT $1, COALESCE(MAX(version), 0) + 1, 'draft', $2, $3, $4
+FROM ecosystem_content_versions WHERE ecosystem_id = $1
+`, ecoID, blocksJSON, req.Note, userID)
+			return err
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "draft_save_failed"})
+		}
+		draft, err := h.contentVersionWhere(c.Context(), ecoID, `status = 'draft'`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(draft)
+	}
+}
+
+// DiscardDraft serves DELETE /admin/ecosystems/:id/content/draft.
+func (h *EcosystemsAdminHandler) DiscardDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM ecosystem_content_versions WHERE ecosystem_id = $1 AND status = 'draft'
+`, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "draft_discard_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "draft_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// PublishDraft serves POST /admin/ecosystems/:id/content/publish: the draft replaces the published
+// version (which is archived) and is written back to the ecosystem's legacy columns.
+func (h *EcosystemsAdminHandler) PublishDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		userID := contentActor(c)
+		var found bool
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			var versionID uuid.UUID
+			var blocksJSON []byte
+			err := tx.QueryRow(c.Context(), `
+SELECT id, blocks FROM ecosystem_content_versions WHERE ecosystem_id = $1 AND status = 'draft' FOR UPDATE
+`, ecoID).Scan(&versionID, &blocksJSON)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			found = true
+			return publishContent(c.Context(), tx, ecoID, versionID, blocksJSON, userID)
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "publish_failed"})
+		}
+		if !found {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "draft_not_found"})
+		}
+		published, err := h.contentVersionWhere(c.Context(), ecoID, `status = 'published'`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(published)
+	}
+}
+
+// ContentHistory serves GET /admin/ecosystems/:id/content/versions: every version, newest first
+// (without blocks; fetch one version for its content).
+func (h *EcosystemsAdminHandler) ContentHistory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, version, status, note, created_by, created_at, updated_at, published_by, published_at
+FROM ecosystem_content_versions
+WHERE ecosystem_id = $1
+ORDER BY version DESC
+`, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_history_failed"})
+		}
+		defer rows.Close()
+		out := []contentVersion{}
+		for rows.Next() {
+			var v contentVersion
+			if err := rows.Scan(&v.ID, &v.Version, &v.Status, &v.Note, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt,
+				&v.PublishedBy, &v.PublishedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_history_failed"})
+			}
+			out = append(out, v)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_history_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"versions": out})
+	}
+}
+
+// ContentVersion serves GET /admin/ecosystems/:id/content/versions/:version.
+func (h *EcosystemsAdminHandler) ContentVersion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		version, err := c.ParamsInt("version")
+		if err != nil || version <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_version"})
+		}
+		v, err := h.contentVersionWhere(c.Context(), ecoID, `version = $2`, version)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		if v == nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "version_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(v)
+	}
+}
+
+// RollbackContent serves POST /admin/ecosystems/:id/content/versions/:version/rollback: publishes a copy
+// of an earlier version as a new version, so history is never rewritten. The draft is left alone.
+func (h *EcosystemsAdminHandler) RollbackContent() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		version, err := c.ParamsInt("version")
+		if err != nil || version <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_version"})
+		}
+		userID := contentActor(c)
+		var found bool
+		err = pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			var blocksJSON []byte
+			err := tx.QueryRow(c.Context(), `
+SELECT blocks FROM ecosystem_content_versions WHERE ecosystem_id = $1 AND version = $2 AND status <> 'draft'
+`, ecoID, version).Scan(&blocksJSON)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			found = true
+			// Lock the ecosystem so concurrent rollbacks don't pick the same version number.
+			if _, err := tx.Exec(c.Context(), `SELECT 1 FROM ecosystems WHERE id = $1 FOR UPDATE`, ecoID); err != nil {
+				return err
+			}
+			var versionID uuid.UUID
+			if err := tx.QueryRow(c.Context(), `
+INSERT INTO ecosystem_content_versions (ecosystem_id, version, status, blocks, note, created_by)
+SELECT $1, COALESCE(MAX(version), 0) + 1, 'archived', $2, 'Rollback to version ' || $3::int, $4
+FROM ecosystem_content_versions WHERE ecosystem_id = $1
+RETURNING id
+`, ecoID, blocksJSON, version, userID).Scan(&versionID); err != nil {
+				return err
+			}
+			return publishContent(c.Context(), tx, ecoID, versionID, blocksJSON, userID)
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "rollback_failed"})
+		}
+		if !found {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "version_not_found"})
+		}
+		published, err := h.contentVersionWhere(c.Context(), ecoID, `status = 'published'`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "content_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(published)
+	}
+}
+
+// publishContent makes versionID the published version and mirrors its blocks into the legacy columns.
+func publishContent(ctx context.Context, tx pgx.Tx, ecoID, versionID uuid.UUID, blocksJSON []byte, userID *uuid.UUID) error {
+	var blocks []ecocontent.Block
+	if err := json.Unmarshal(blocksJSON, &blocks); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+UPDATE ecosystem_content_versions SET status = 'archived', updated_at = now()
+WHERE ecosystem_id = $1 AND status = 'published'
+`, ecoID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+UPDATE ecosystem_content_versions
+SET status = 'published', published_by = $2, published_at = now(), updated_at = now()
+WHERE id = $1
+`, versionID, userID); err != nil {
+		return err
+	}
+	legacy := ecocontent.Flatten(blocks)
+	_, err := tx.Exec(ctx, `
+UPDATE ecosystems
+SET about = NULLIF($2, ''), links = $3::jsonb, key_areas = $4::jsonb, technologies = $5::jsonb, updated_at = now()
+WHERE id = $1
+`, ecoID, legacy.About, []byte(legacy.Links), []byte(legacy.KeyAreas), []byte(legacy.Technologies))
+	return err
+}
+
+// snapshotLegacy records an ecosystem's pre-versioning content as published version 1 the first time a
+// draft is saved, so the original page can be rolled back to.
+func (h *EcosystemsAdminHandler) snapshotLegacy(ctx context.Context, tx pgx.Tx, ecoID uuid.UUID) error {
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM ecosystem_content_versions WHERE ecosystem_id = $1)`, ecoID).Scan(&exists); err != nil || exists {
+		return err
+	}
+	legacy, err := legacyContentQ(ctx, tx, ecoID)
+	if err != nil {
+		return err
+	}
+	blocks := ecocontent.FromLegacy(legacy)
+	if len(blocks) == 0 {
+		return nil
+	}
+	blocksJSON, _ := json.Marshal(blocks)
+	_, err = tx.Exec(ctx, `
+INSERT INTO ecosystem_content_versions (ecosystem_id, version, status, blocks, note, published_at)
+VALUES ($1, 1, 'published', $2, 'Content before versioning', now())
+`, ecoID, blocksJSON)
+	return err
+}
+
+func (h *EcosystemsAdminHandler) legacyContent(ctx context.Context, ecoID uuid.UUID) (ecocontent.Legacy, error) {
+	return legacyContentQ(ctx, h.db.Pool, ecoID)
+}
+
+func legacyContentQ(ctx context.Context, q interface {
+	QueryRow(context.Context, string, ...any) pgx.Row
+}, ecoID uuid.UUID) (ecocontent.Legacy, error) {
+	var l ecocontent.Legacy
+	var about *string
+	var links, keyAreas, technologies []byte
+	err := q.QueryRow(ctx, `
+SELECT about, COALESCE(links, '[]'::jsonb), COALESCE(key_areas, '[]'::jsonb), COALESCE(technologies, '[]'::jsonb)
+FROM ecosystems WHERE id = $1
+`, ecoID).Scan(&about, &links, &keyAreas, &technologies)
+	if about != nil {
+		l.About = *about
+	}
+	l.Links, l.KeyAreas, l.Technologies = links, keyAreas, technologies
+	return l, err
+}
+
+// contentVersionWhere loads the ecosystem's one version matching cond ($1 is the ecosystem id), or nil.
+func (h *EcosystemsAdminHandler) contentVersionWhere(ctx context.Context, ecoID uuid.UUID, cond string, args ...any) (*contentVersion, error) {
+	var v contentVersion
+	var blocksJSON []byte
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT id, version, status, blocks, note, created_by, created_at, updated_at, published_by, published_at
+FROM ecosystem_content_versions
+WHERE ecosystem_id = $1 AND `+cond, append([]any{ecoID}, args...)...).Scan(&v.ID, &v.Version, &v.Status, &blocksJSON,
+		&v.Note, &v.CreatedBy, &v.CreatedAt, &v.UpdatedAt, &v.PublishedBy, &v.PublishedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.Blocks = []ecocontent.Block{}
+	_ = json.Unmarshal(blocksJSON, &v.Blocks)
+	return &v, nil
+}
+
+// contentEcosystem resolves :id to an ecosystem visible to the caller.
+func (h *EcosystemsAdminHandler) contentEcosystem(c *fiber.Ctx) (uuid.UUID, int, string) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, fiber.StatusServiceUnavailable, "db_not_configured"
+	}
+	ecoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_ecosystem_id"
+	}
+	var exists bool
+	if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS (SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "ecosystem_lookup_failed"
+	}
+	if !exists {
+		return uuid.Nil, fiber.StatusNotFound, "ecosystem_not_found"
+	}
+	return ecoID, 0, ""
+}
+
+func contentActor(c *fiber.Ctx) *uuid.UUID {
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	if id, err := uuid.Parse(sub); err == nil {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/digests"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+// EcosystemReportsHandler manages subscriptions to the weekly ecosystem report (see package digests).
+type EcosystemReportsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewEcosystemReportsHandler(cfg config.Config, d *db.DB) *EcosystemReportsHandler {
+	return &EcosystemReportsHandler{cfg: cfg, db: d}
+}
+
+// ecosystem parses :id and checks the ecosystem exists (in this tenant).
+func (h *EcosystemReportsHandler) ecosystem(c *fiber.Ctx) (uuid.UUID, int, string) {
+	ecoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_ecosystem_id"
+	}
+	var exists bool
+	if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS (SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "ecosystem_lookup_failed"
+	}
+	if !exists {
+		return uuid.Nil, fiber.StatusNotFound, "ecosystem_not_found"
+	}
+	return ecoID, 0, ""
+}
+
+func (h *EcosystemReportsHandler) Subscribers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT s.id, s.user_id, ga.login, s.email, s.created_at
+FROM ecosystem_report_subscribers s
+LEFT JOIN github_accounts ga ON ga.user_id = s.user_id
+WHERE s.ecosystem_id = $1
+ORDER BY s.created_at
+`, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subscribers_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var userID *uuid.UUID
+			var login, email *string
+			var createdAt time.Time
+			if err := rows.Scan(&id, &userID, &login, &email, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subscribers_list_failed"})
+			}
+			out = append(out, fiber.Map{"id": id.String(), "user_id": userID, "github_login": login, "email": email, "created_at": createdAt})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"subscribers": out})
+	}
+}
+
+// Subscribe subscribes the calling admin. With an email the report is also emailed there.
+func (h *EcosystemReportsHandler) Subscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req struct {
+			Email string `json:"email"`
+		}
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+			}
+		}
+		var email *string
+		if v := strings.TrimSpace(req.Email); v != "" {
+			addr, err := mail.ParseAddress(v)
+			if err != nil || addr.Name != "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_email"})
+			}
+			email = &addr.Address
+		}
+
+		var id uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO ecosystem_report_subscribers (ecosystem_id, user_id, email)
+VALUES ($1, $2, $3)
+ON CONFLICT (ecosystem_id, user_id) DO UPDATE SET email = EXCLUDED.email
+RETURNING id
+`, ecoID, userID, email).Scan(&id)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subscribe_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "id": id.String(), "email": email})
+	}
+}
+
+func (h *EcosystemReportsHandler) Unsubscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		_, err = h.db.Pool.Exec(c.Context(), `
+DELETE FROM ecosystem_report_subscribers WHERE ecosystem_id = $1 AND user_id = $2
+`, ecoID, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "unsubscribe_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Preview builds the report on demand. Defaults to the last seven days; from/to take YYYY-MM-DD.
+func (h *EcosystemReportsHandler) Preview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		end := time.Now().UTC()
+		start := end.AddDate(0, 0, -7)
+		if c.Query("from") != "" || c.Query("to") != "" {
+			from, to, err := parseExportRange(c.Query("from"), c.Query("to"))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_range", "message": err.Error()})
+			}
+			if c.Query("from") != "" {
+				start = from
+			}
+			if c.Query("to") != "" {
+				end = to
+			}
+		}
+		d, err := digests.Build(c.Context(), h.db.Pool, ecoID, start, end)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "report_build_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(d)
+	}
+}
+
+// History lists the reports sent for an ecosystem, newest first.
+func (h *EcosystemReportsHandler) History() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		limit := c.QueryInt("limit", 12)
+		if limit <= 0 || limit > 52 {
+			limit = 12
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, period_start, period_end, content, skipped, emails_sent, sent_at
+FROM ecosystem_digests
+WHERE ecosystem_id = $1
+ORDER BY period_start DESC
+LIMIT $2
+`, ecoID, limit)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reports_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var start, end time.Time
+			var content []byte
+			var skipped bool
+			var emails int
+			var sentAt *time.Time
+			if err := rows.Scan(&id, &start, &end, &content, &skipped, &emails, &sentAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reports_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id": id.String(), "period_start": start, "period_end": end, "report": json.RawMessage(content),
+				"skipped": skipped, "emails_sent": emails, "sent_at": sentAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"reports": out})
+	}
+}
+
+// UnsubscribeLink handles the signed link at the bottom of report emails; no session needed.
+func (h *EcosystemReportsHandler) UnsubscribeLink() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Query("s"))
+		if err != nil || !digests.ValidUnsubscribeToken(h.cfg.JWTSecret, id, c.Query("token")) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_unsubscribe_link"})
+		}
+		// The link is served from PublicBaseURL whichever tenant the ecosystem belongs to.
+		var ecosystem string
+		err = h.db.Pool.QueryRow(tenant.Unscoped(c.Context()), `
+DELETE FROM ecosystem_report_subscribers s
+USING ecosystems e
+WHERE s.id = $1 AND e.id = s.ecosystem_id
+RETURNING e.name
+`, id).Scan(&ecosystem)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "unsubscribe_failed"})
+		}
+		msg := "You are unsubscribed."
+		if ecosystem != "" {
+			msg = "You will no longer receive " + ecosystem + " weekly reports."
+		}
+		return c.Status(fiber.StatusOK).SendString(msg)
+	}
+}
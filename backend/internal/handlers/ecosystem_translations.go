@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/ecocontent"
+	"github.com/jagadeesh/grainlify/backend/internal/locale"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+type ecosystemTranslation struct {
+	Locale      string          `json:"locale"`
+	Description *string         `json:"description"`
+	About       *string         `json:"about"`
+	KeyAreas    json.RawMessage `json:"key_areas"`
+	UpdatedBy   *uuid.UUID      `json:"updated_by"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type ecosystemTranslationRequest struct {
+	Description string          `json:"description"`
+	About       string          `json:"about"`
+	KeyAreas    json.RawMessage `json:"key_areas"`
+}
+
+// Translations serves GET /admin/ecosystems/:id/translations: the ecosystem's translated text, by locale.
+func (h *EcosystemsAdminHandler) Translations() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		translations, err := ecosystemTranslations(c.Context(), h.db.Pool, []uuid.UUID{ecoID})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "translations_lookup_failed"})
+		}
+		out := translations[ecoID]
+		if out == nil {
+			out = []ecosystemTranslation{}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"translations": out})
+	}
+}
+
+// PutTranslation serves PUT /admin/ecosystems/:id/translations/:locale: sets the ecosystem's description,
+// about and key areas in a locale, replacing any earlier translation. Blank fields fall back to the default
+// content on the public pages.
+func (h *EcosystemsAdminHandler) PutTranslation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		loc, ok := locale.Normalize(c.Params("locale"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_locale"})
+		}
+		var req ecosystemTranslationRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+
+		var v validate.Validator
+		description, about := strings.TrimSpace(req.Description), strings.TrimSpace(req.About)
+		validateEcosystemText(&v, &description, nil, nil, &about)
+		keyAreas := ecocontent.ValidateItems(&v, "key_areas", ecocontent.KeyAreas, req.KeyAreas)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		if description == "" && about == "" && string(keyAreas) == "[]" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "translation_empty"})
+		}
+		if out := h.moderateEcosystemText(c.Context(), &description, &about); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
+		}
+
+		t := ecosystemTranslation{Locale: loc}
+		var keyAreasOut []byte
+		err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO ecosystem_translations (ecosystem_id, locale, description, about, key_areas, updated_by)
+VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5::jsonb, '[]'::jsonb), $6)
+ON CONFLICT (ecosystem_id, locale) DO UPDATE
+SET description = EXCLUDED.description, about = EXCLUDED.about, key_areas = EXCLUDED.key_areas,
+    updated_by = EXCLUDED.updated_by, updated_at = now()
+RETURNING description, about, key_areas, updated_by, updated_at
+`, ecoID, loc, description, about, string(keyAreas), contentActor(c)).Scan(&t.Description, &t.About, &keyAreasOut, &t.UpdatedBy, &t.UpdatedAt)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "translation_save_failed"})
+		}
+		t.KeyAreas = keyAreasOut
+		return c.Status(fiber.StatusOK).JSON(t)
+	}
+}
+
+// DeleteTranslation serves DELETE /admin/ecosystems/:id/translations/:locale.
+func (h *EcosystemsAdminHandler) DeleteTranslation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.contentEcosystem(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		loc, ok := locale.Normalize(c.Params("locale"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_locale"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `DELETE FROM ecosystem_translations WHERE ecosystem_id = $1 AND locale = $2`, ecoID, loc)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "translation_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "translation_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// localePreference returns the locales a public request asks for, as an Accept-Language value for
+// locale.Negotiate: ?locale= when given, else the Accept-Language header. It marks the response as varying
+// by the header so caches keep the translations apart.
+func localePreference(c *fiber.Ctx) string {
+	c.Vary(fiber.HeaderAcceptLanguage)
+	if q := strings.TrimSpace(c.Query("locale")); q != "" {
+		return q
+	}
+	return c.Get(fiber.HeaderAcceptLanguage)
+}
+
+// ecosystemTranslations loads the translations of the given ecosystems, by ecosystem.
+func ecosystemTranslations(ctx context.Context, pool *pgxpool.Pool, ids []uuid.UUID) (map[uuid.UUID][]ecosystemTranslation, error) {
+	out := map[uuid.UUID][]ecosystemTranslation{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := pool.Query(ctx, `
+SELECT ecosystem_id, locale, description, about, key_areas, updated_by, updated_at
+FROM ecosystem_translations
+WHERE ecosystem_id = ANY($1)
+ORDER BY locale
+`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var t ecosystemTranslation
+		var keyAreas []byte
+		if err := rows.Scan(&id, &t.Locale, &t.Description, &t.About, &keyAreas, &t.UpdatedBy, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		t.KeyAreas = keyAreas
+		out[id] = append(out[id], t)
+	}
+	return out, rows.Err()
+}
+
+// pickTranslation returns the translation that best matches pref, an Accept-Language value.
+func pickTranslation(list []ecosystemTranslation, pref string) (ecosystemTranslation, bool) {
+	available := make([]string, len(list))
+	for i, t := range list {
+		available[i] = t.Locale
+	}
+	chosen := locale.Negotiate(pref, available)
+	for _, t := range list {
+		if chosen != "" && t.Locale == chosen {
+			return t, true
+		}
+	}
+	return ecosystemTranslation{}, false
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/ecocontent"
 )
 
 type EcosystemsPublicHandler struct {
@@ -19,6 +20,8 @@ func NewEcosystemsPublicHandler(d *db.DB) *EcosystemsPublicHandler {
 }
 
 // GetByID returns one ecosystem by ID with full detail (about, links, key_areas, technologies) and computed stats.
+// Description, about and key areas are translated into the best match for ?locale= or Accept-Language among the
+// ecosystem's available_locales; locale is the one served (null for the default content).
 func (h *EcosystemsPublicHandler) GetByID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -29,17 +32,38 @@ func (h *EcosystemsPublicHandler) GetByID() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
 		}
 
+		// One round trip for the ecosystem, its published page blocks and its stats. Stats count only verified
+		// projects (same as public projects list) so Overview matches Projects tab.
 		var id uuid.UUID
 		var slug, name, status string
 		var desc, website, logoURL, about *string
-		var linksJSON, keyAreasJSON, technologiesJSON []byte
+		var linksJSON, keyAreasJSON, technologiesJSON, blocksJSON []byte
 		var createdAt, updatedAt time.Time
+		var projectCount, contributorsCount, openIssuesCount, openPRsCount int64
 		err = h.db.Pool.QueryRow(c.Context(), `
+WITH listed AS (
+  SELECT p.id FROM projects p
+  WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND p.status = 'verified' AND p.needs_metadata = false
+)
 SELECT e.id, e.slug, e.name, e.description, e.website_url, e.logo_url, e.status, e.created_at, e.updated_at,
-       e.about, e.links, e.key_areas, e.technologies
+       e.about, e.links, e.key_areas, e.technologies, v.blocks,
+       s.project_count, s.contributors_count, s.open_issues_count, s.open_prs_count
 FROM ecosystems e
+LEFT JOIN ecosystem_content_versions v ON v.ecosystem_id = e.id AND v.status = 'published'
+CROSS JOIN (
+  SELECT
+    (SELECT COUNT(*) FROM listed) AS project_count,
+    (SELECT COUNT(DISTINCT a.author_login) FROM (
+       SELECT author_login FROM github_issues WHERE project_id IN (SELECT id FROM listed)
+       UNION ALL
+       SELECT author_login FROM github_pull_requests WHERE project_id IN (SELECT id FROM listed)
+     ) a WHERE a.author_login <> '') AS contributors_count,
+    (SELECT COUNT(*) FROM github_issues WHERE project_id IN (SELECT id FROM listed) AND state = 'open') AS open_issues_count,
+    (SELECT COUNT(*) FROM github_pull_requests WHERE project_id IN (SELECT id FROM listed) AND state = 'open') AS open_prs_count
+) s
 WHERE e.id = $1 AND e.status = 'active'
-`, ecoID).Scan(&id, &slug, &name, &desc, &website, &logoURL, &status, &createdAt, &updatedAt, &about, &linksJSON, &keyAreasJSON, &technologiesJSON)
+`, ecoID).Scan(&id, &slug, &name, &desc, &website, &logoURL, &status, &createdAt, &updatedAt, &about, &linksJSON, &keyAreasJSON, &technologiesJSON, &blocksJSON,
+			&projectCount, &contributorsCount, &openIssuesCount, &openPRsCount)
 		if err != nil {
 			if err.Error() == "no rows in result set" {
 				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
@@ -58,25 +82,49 @@ WHERE e.id = $1 AND e.status = 'active'
 			_ = json.Unmarshal(technologiesJSON, &technologies)
 		}
 
-		// Count only verified projects (same as public projects list) so Overview matches Projects tab
-		var projectCount int64
-		var contributorsCount int64
-		var openIssuesCount int64
-		var openPRsCount int64
-		_ = h.db.Pool.QueryRow(c.Context(), `
-SELECT
-  (SELECT COUNT(*) FROM projects p WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND p.status = 'verified' AND p.needs_metadata = false),
-  COALESCE((
-    SELECT COUNT(DISTINCT a.author_login)
-    FROM (
-      SELECT author_login FROM github_issues WHERE project_id IN (SELECT id FROM projects WHERE ecosystem_id = $1 AND deleted_at IS NULL AND status = 'verified' AND needs_metadata = false) AND author_login IS NOT NULL AND author_login != ''
-      UNION
-      SELECT author_login FROM github_pull_requests WHERE project_id IN (SELECT id FROM projects WHERE ecosystem_id = $1 AND deleted_at IS NULL AND status = 'verified' AND needs_metadata = false) AND author_login IS NOT NULL AND author_login != ''
-    ) a
-  ), 0),
-  COALESCE((SELECT COUNT(*) FROM github_issues gi INNER JOIN projects p ON p.id = gi.project_id WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND p.status = 'verified' AND p.needs_metadata = false AND gi.state = 'open'), 0),
-  COALESCE((SELECT COUNT(*) FROM github_pull_requests gpr INNER JOIN projects p ON p.id = gpr.project_id WHERE p.ecosystem_id = $1 AND p.deleted_at IS NULL AND p.status = 'verified' AND p.needs_metadata = false AND gpr.state = 'open'), 0)
-`, ecoID, ecoID, ecoID, ecoID).Scan(&projectCount, &contributorsCount, &openIssuesCount, &openPRsCount)
+		// Page blocks come from the published content version; ecosystems never edited as blocks get blocks
+		// derived from the legacy columns.
+		var blocks []ecocontent.Block
+		if len(blocksJSON) > 0 {
+			_ = json.Unmarshal(blocksJSON, &blocks)
+		}
+		if blocks == nil {
+			var a string
+			if about != nil {
+				a = *about
+			}
+			blocks = ecocontent.FromLegacy(ecocontent.Legacy{About: a, Links: linksJSON, KeyAreas: keyAreasJSON, Technologies: technologiesJSON})
+		}
+
+		// Serve the translation that best matches the request; untranslated fields keep the default text.
+		translations, err := ecosystemTranslations(c.Context(), h.db.Pool, []uuid.UUID{ecoID})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+		}
+		available := []string{}
+		for _, t := range translations[ecoID] {
+			available = append(available, t.Locale)
+		}
+		var contentLocale *string
+		if t, ok := pickTranslation(translations[ecoID], localePreference(c)); ok {
+			contentLocale = &t.Locale
+			c.Set(fiber.HeaderContentLanguage, t.Locale)
+			if t.Description != nil {
+				desc = t.Description
+			}
+			if t.About != nil {
+				about = t.About
+			}
+			if len(t.KeyAreas) > 0 {
+				keyAreas = nil
+				_ = json.Unmarshal(t.KeyAreas, &keyAreas)
+			}
+			var a string
+			if t.About != nil {
+				a = *t.About
+			}
+			blocks = ecocontent.Localize(blocks, a, t.KeyAreas)
+		}
 
 		out := fiber.Map{
 			"id":                   id.String(),
@@ -92,10 +140,13 @@ SELECT
 			"links":                links,
 			"key_areas":            keyAreas,
 			"technologies":         technologies,
+			"content_blocks":       blocks,
 			"project_count":        projectCount,
 			"contributors_count":   contributorsCount,
 			"open_issues_count":    openIssuesCount,
 			"open_prs_count":       openPRsCount,
+			"locale":               contentLocale,
+			"available_locales":    available,
 		}
 		return c.Status(fiber.StatusOK).JSON(out)
 	}
@@ -104,6 +155,7 @@ SELECT
 // ListActive returns active ecosystems with computed counts:
 // - project_count: number of projects assigned to the ecosystem
 // - user_count: number of distinct project owners in the ecosystem
+// Descriptions are translated like GetByID's, per ecosystem.
 func (h *EcosystemsPublicHandler) ListActive() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -136,6 +188,7 @@ LIMIT 200
 		defer rows.Close()
 
 		var out []fiber.Map
+		var ids []uuid.UUID
 		for rows.Next() {
 			var (
 				id         uuid.UUID
@@ -165,7 +218,26 @@ LIMIT 200
 				"updated_at":    updatedAt,
 				"project_count": projectCnt,
 				"user_count":    userCnt,
+				"locale":        nil,
 			})
+			ids = append(ids, id)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_list_failed"})
+		}
+
+		translations, err := ecosystemTranslations(c.Context(), h.db.Pool, ids)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystems_list_failed"})
+		}
+		pref := localePreference(c)
+		for i, id := range ids {
+			if t, ok := pickTranslation(translations[id], pref); ok {
+				out[i]["locale"] = t.Locale
+				if t.Description != nil {
+					out[i]["description"] = t.Description
+				}
+			}
 		}
 
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ecosystems": out})
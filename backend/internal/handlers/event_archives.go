@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/retention"
+)
+
+// EventArchivesHandler lets admins find and read webhook events that were pruned into the archive.
+type EventArchivesHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewEventArchivesHandler(cfg config.Config, d *db.DB) *EventArchivesHandler {
+	return &EventArchivesHandler{cfg: cfg, db: d}
+}
+
+// parseTimeParam accepts RFC 3339 timestamps or plain dates (YYYY-MM-DD, UTC).
+func parseTimeParam(v string) (*time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, true
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return &t, true
+	}
+	return nil, false
+}
+
+// List returns the archive objects whose range overlaps [from, to], plus the retention settings.
+func (h *EventArchivesHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		from, ok := parseTimeParam(c.Query("from"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_from"})
+		}
+		to, ok := parseTimeParam(c.Query("to"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_to"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, object_key, from_received_at, to_received_at, event_count, size_bytes, created_at
+FROM github_event_archives
+WHERE ($1::timestamptz IS NULL OR to_received_at >= $1)
+  AND ($2::timestamptz IS NULL OR from_received_at <= $2)
+ORDER BY from_received_at DESC
+LIMIT 500
+`, from, to)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "archives_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var key string
+			var rangeFrom, rangeTo, createdAt time.Time
+			var count int
+			var size int64
+			if err := rows.Scan(&id, &key, &rangeFrom, &rangeTo, &count, &size, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "archives_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":               id.String(),
+				"object_key":       key,
+				"from_received_at": rangeFrom,
+				"to_received_at":   rangeTo,
+				"event_count":      count,
+				"size_bytes":       size,
+				"created_at":       createdAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"archives":       out,
+			"retention_days": h.cfg.EventRetentionDays,
+			"archiving":      strings.TrimSpace(h.cfg.EventArchiveURL) != "",
+		})
+	}
+}
+
+// Events reads one archive object back, optionally filtered by project, event name and time range.
+func (h *EventArchivesHandler) Events() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		archiveID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_archive_id"})
+		}
+		from, ok := parseTimeParam(c.Query("from"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_from"})
+		}
+		to, ok := parseTimeParam(c.Query("to"))
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_to"})
+		}
+		projectFilter := strings.TrimSpace(c.Query("project_id"))
+		eventFilter := strings.TrimSpace(c.Query("event"))
+		limit := 100
+		if l := c.QueryInt("limit", 100); l > 0 && l <= 1000 {
+			limit = l
+		}
+
+		var key string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT object_key FROM github_event_archives WHERE id = $1`, archiveID).Scan(&key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "archive_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "archive_lookup_failed"})
+		}
+
+		store, err := retention.NewStore(h.cfg.EventArchiveURL, h.cfg.EventArchiveToken)
+		if err != nil || store == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "event_archive_not_configured"})
+		}
+		data, err := store.Get(c.Context(), key)
+		if err != nil {
+			slog.Warn("failed to read event archive", "object_key", key, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "archive_read_failed"})
+		}
+		events, err := retention.Decode(data)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "archive_decode_failed"})
+		}
+
+		out := []retention.ArchivedEvent{}
+		matched := 0
+		for _, ev := range events {
+			if projectFilter != "" && (ev.ProjectID == nil || *ev.ProjectID != projectFilter) {
+				continue
+			}
+			if eventFilter != "" && ev.Event != eventFilter {
+				continue
+			}
+			if (from != nil && ev.ReceivedAt.Before(*from)) || (to != nil && ev.ReceivedAt.After(*to)) {
+				continue
+			}
+			matched++
+			if len(out) < limit {
+				out = append(out, ev)
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"object_key": key,
+			"events":     out,
+			"matched":    matched,
+			"truncated":  matched > len(out),
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/exports"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+type ExportsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewExportsHandler(cfg config.Config, d *db.DB) *ExportsHandler {
+	return &ExportsHandler{cfg: cfg, db: d}
+}
+
+// Export exports a dataset (applications, contributions, payouts) as CSV or JSON.
+//
+// Query: format (csv|json), project_id or ecosystem_id, from, to (YYYY-MM-DD or RFC 3339; to is
+// exclusive, a bare date includes that day), async=true to always queue.
+// Project owners may export their project; everything else needs the admin role. Exports of up to
+// exports.SyncRowLimit rows are returned directly; larger ones answer 202 with a job to poll.
+func (h *ExportsHandler) Export() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		req := exports.Request{
+			Dataset: c.Params("dataset"),
+			Format:  strings.ToLower(c.Query("format", exports.CSV)),
+		}
+		if !exports.Valid(req.Dataset, req.Format) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_export", "datasets": []string{exports.Applications, exports.Contributions, exports.Payouts}, "formats": []string{exports.CSV, exports.JSON}})
+		}
+		if req.From, req.To, err = parseExportRange(c.Query("from"), c.Query("to")); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_range", "message": err.Error()})
+		}
+		if raw := c.Query("project_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+			}
+			req.ProjectID = &id
+		}
+		if raw := c.Query("ecosystem_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+			}
+			req.EcosystemID = &id
+		}
+		if req.ProjectID != nil && req.EcosystemID != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_or_ecosystem"})
+		}
+
+		switch {
+		case req.Dataset == exports.Payouts:
+			if req.ProjectID != nil || req.EcosystemID != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payouts_not_scoped", "message": "on-chain payouts are not linked to projects; export them by date range only"})
+			}
+			// Payouts aren't tenant-scoped either, so only the default tenant's admins see them.
+			if t := tenant.FromCtx(c); role != "admin" || (t != nil && !t.IsDefault) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+		case req.ProjectID != nil:
+			var owner uuid.UUID
+			err := h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, *req.ProjectID).Scan(&owner)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+			}
+			if owner != userID && role != "admin" {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+		default:
+			if role != "admin" {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+		}
+
+		if !c.QueryBool("async") {
+			n, err := exports.Count(c.Context(), h.db.Pool, req)
+			if err != nil {
+				slog.Error("failed to count export rows", "dataset", req.Dataset, "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "export_failed"})
+			}
+			if n <= exports.SyncRowLimit {
+				var buf bytes.Buffer
+				if _, err := exports.Write(c.Context(), h.db.Pool, req, &buf); err != nil {
+					slog.Error("export failed", "dataset", req.Dataset, "error", err)
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "export_failed"})
+				}
+				c.Set(fiber.HeaderContentType, exports.ContentType(req.Format))
+				c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+exports.Filename(req)+`"`)
+				return c.Status(fiber.StatusOK).Send(buf.Bytes())
+			}
+		}
+
+		var id uuid.UUID
+		var createdAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO export_jobs (requested_by, dataset, format, project_id, ecosystem_id, range_from, range_to)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at
+`, userID, req.Dataset, req.Format, req.ProjectID, req.EcosystemID, req.From, req.To).Scan(&id, &createdAt)
+		if err != nil {
+			slog.Error("failed to queue export", "dataset", req.Dataset, "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "export_queue_failed"})
+		}
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"id":         id.String(),
+			"status":     "pending",
+			"dataset":    req.Dataset,
+			"format":     req.Format,
+			"created_at": createdAt,
+			"status_url": "/export-jobs/" + id.String(),
+		})
+	}
+}
+
+// parseExportRange defaults to everything up to now.
+func parseExportRange(fromRaw, toRaw string) (time.Time, time.Time, error) {
+	from, to := time.Unix(0, 0).UTC(), time.Now().UTC()
+	if fromRaw != "" {
+		t, _, err := parseExportTime(fromRaw)
+		if err != nil {
+			return from, to, errors.New("from must be YYYY-MM-DD or RFC 3339")
+		}
+		from = t
+	}
+	if toRaw != "" {
+		t, dateOnly, err := parseExportTime(toRaw)
+		if err != nil {
+			return from, to, errors.New("to must be YYYY-MM-DD or RFC 3339")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+	if !from.Before(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func parseExportTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, false, err
+}
+
+func (h *ExportsHandler) jobJSON(id uuid.UUID, dataset, format, status string, rows *int, errMsg *string, createdAt time.Time, finishedAt, expiresAt *time.Time) fiber.Map {
+	out := fiber.Map{
+		"id":          id.String(),
+		"dataset":     dataset,
+		"format":      format,
+		"status":      status,
+		"row_count":   rows,
+		"error":       errMsg,
+		"created_at":  createdAt,
+		"finished_at": finishedAt,
+		"expires_at":  expiresAt,
+	}
+	if status == "ready" {
+		out["download_url"] = strings.TrimSuffix(h.cfg.PublicBaseURL, "/") + "/export-jobs/" + id.String() +
+			"/download?token=" + exports.DownloadToken(h.cfg.JWTSecret, id)
+	}
+	return out
+}
+
+// Jobs lists the caller's queued exports, newest first.
+func (h *ExportsHandler) Jobs() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, dataset, format, status, row_count, error, created_at, finished_at, expires_at
+FROM export_jobs
+WHERE requested_by = $1
+ORDER BY created_at DESC
+LIMIT 50
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "exports_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var dataset, format, status string
+			var rowCount *int
+			var errMsg *string
+			var createdAt time.Time
+			var finishedAt, expiresAt *time.Time
+			if err := rows.Scan(&id, &dataset, &format, &status, &rowCount, &errMsg, &createdAt, &finishedAt, &expiresAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "exports_list_failed"})
+			}
+			out = append(out, h.jobJSON(id, dataset, format, status, rowCount, errMsg, createdAt, finishedAt, expiresAt))
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"exports": out})
+	}
+}
+
+// Job returns one queued export; download_url appears once it is ready.
+func (h *ExportsHandler) Job() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_export_id"})
+		}
+
+		var dataset, format, status string
+		var rowCount *int
+		var errMsg *string
+		var createdAt time.Time
+		var finishedAt, expiresAt *time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT dataset, format, status, row_count, error, created_at, finished_at, expires_at
+FROM export_jobs
+WHERE id = $1 AND requested_by = $2
+`, id, userID).Scan(&dataset, &format, &status, &rowCount, &errMsg, &createdAt, &finishedAt, &expiresAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "export_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "export_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(h.jobJSON(id, dataset, format, status, rowCount, errMsg, createdAt, finishedAt, expiresAt))
+	}
+}
+
+// Download serves a ready export. It needs no session: the signed token in the link authorizes it.
+func (h *ExportsHandler) Download() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil || !exports.ValidDownloadToken(h.cfg.JWTSecret, id, c.Query("token")) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "export_not_found"})
+		}
+
+		var req exports.Request
+		var content []byte
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT dataset, format, range_from, range_to, content
+FROM export_jobs
+WHERE id = $1 AND status = 'ready' AND expires_at > now()
+`, id).Scan(&req.Dataset, &req.Format, &req.From, &req.To, &content)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "export_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "export_lookup_failed"})
+		}
+		c.Set(fiber.HeaderContentType, exports.ContentType(req.Format))
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+exports.Filename(req)+`"`)
+		return c.Status(fiber.StatusOK).Send(content)
+	}
+}
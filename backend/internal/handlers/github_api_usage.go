@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// GitHubAPIUsageHandler reports GitHub API call volume per installation and token, to diagnose throttling.
+type GitHubAPIUsageHandler struct {
+	db *db.DB
+}
+
+func NewGitHubAPIUsageHandler(d *db.DB) *GitHubAPIUsageHandler {
+	return &GitHubAPIUsageHandler{db: d}
+}
+
+type usageHour struct {
+	Bucket       time.Time `json:"bucket_start"`
+	Calls        int       `json:"calls"`
+	Errors       int       `json:"errors"`
+	RateLimited  int       `json:"rate_limited"`
+	MinRemaining *int      `json:"min_remaining"`
+}
+
+type usageIdentity struct {
+	Kind         string      `json:"kind"`
+	ID           string      `json:"id"`
+	Repos        []string    `json:"repos,omitempty"` // projects using the installation
+	Calls        int         `json:"calls"`
+	Errors       int         `json:"errors"`
+	ErrorRate    float64     `json:"error_rate"`
+	RateLimited  int         `json:"rate_limited"`
+	MinRemaining *int        `json:"min_remaining"`
+	RateLimit    *int        `json:"rate_limit"`
+	ResetAt      *time.Time  `json:"reset_at"`
+	LastSeen     time.Time   `json:"last_seen"`
+	Hourly       []usageHour `json:"hourly"`
+}
+
+// Report summarizes the last `hours` (default 24, max 720) of usage per identity, with an hourly series.
+// Optional filters: kind (installation|user|app|token|anonymous) and id.
+func (h *GitHubAPIUsageHandler) Report() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		hours := 24
+		if v := c.QueryInt("hours", 24); v > 0 && v <= apiusage.RetentionDays*24 {
+			hours = v
+		}
+		kind := strings.TrimSpace(c.Query("kind"))
+		id := strings.TrimSpace(c.Query("id"))
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT bucket_start, identity_kind, identity_id, calls, errors, rate_limited, min_remaining, rate_limit, reset_at
+FROM github_api_usage
+WHERE bucket_start >= date_trunc('hour', now()) - make_interval(hours => $1)
+  AND ($2 = '' OR identity_kind = $2)
+  AND ($3 = '' OR identity_id = $3)
+ORDER BY bucket_start ASC
+`, hours-1, kind, id)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "usage_report_failed"})
+		}
+		defer rows.Close()
+
+		byIdentity := map[string]*usageIdentity{}
+		totals := fiber.Map{}
+		var totalCalls, totalErrors, totalLimited int
+		for rows.Next() {
+			var hr usageHour
+			var k, ident string
+			var limit *int
+			var resetAt *time.Time
+			if err := rows.Scan(&hr.Bucket, &k, &ident, &hr.Calls, &hr.Errors, &hr.RateLimited, &hr.MinRemaining, &limit, &resetAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "usage_report_failed"})
+			}
+			u := byIdentity[k+":"+ident]
+			if u == nil {
+				u = &usageIdentity{Kind: k, ID: ident, Hourly: []usageHour{}}
+				byIdentity[k+":"+ident] = u
+			}
+			u.Calls += hr.Calls
+			u.Errors += hr.Errors
+			u.RateLimited += hr.RateLimited
+			if hr.MinRemaining != nil && (u.MinRemaining == nil || *hr.MinRemaining < *u.MinRemaining) {
+				u.MinRemaining = hr.MinRemaining
+			}
+			// Rows are oldest first, so the latest limit/reset wins.
+			if limit != nil {
+				u.RateLimit = limit
+			}
+			if resetAt != nil {
+				u.ResetAt = resetAt
+			}
+			u.LastSeen = hr.Bucket
+			u.Hourly = append(u.Hourly, hr)
+			totalCalls += hr.Calls
+			totalErrors += hr.Errors
+			totalLimited += hr.RateLimited
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "usage_report_failed"})
+		}
+
+		out := make([]*usageIdentity, 0, len(byIdentity))
+		var installationIDs []string
+		for _, u := range byIdentity {
+			if u.Calls > 0 {
+				u.ErrorRate = float64(u.Errors) / float64(u.Calls)
+			}
+			if u.Kind == apiusage.KindInstallation {
+				installationIDs = append(installationIDs, u.ID)
+			}
+			out = append(out, u)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Calls != out[j].Calls {
+				return out[i].Calls > out[j].Calls
+			}
+			return out[i].Kind+out[i].ID < out[j].Kind+out[j].ID
+		})
+
+		// Name installations by the repositories registered under them.
+		if len(installationIDs) > 0 {
+			repoRows, err := h.db.Pool.Query(c.Context(), `
+SELECT github_app_installation_id, github_full_name
+FROM projects
+WHERE github_app_installation_id = ANY($1) AND deleted_at IS NULL
+ORDER BY github_full_name
+`, installationIDs)
+			if err == nil {
+				for repoRows.Next() {
+					var inst, repo string
+					if repoRows.Scan(&inst, &repo) == nil {
+						if u := byIdentity[apiusage.KindInstallation+":"+inst]; u != nil {
+							u.Repos = append(u.Repos, repo)
+						}
+					}
+				}
+				repoRows.Close()
+			}
+		}
+
+		totals["calls"] = totalCalls
+		totals["errors"] = totalErrors
+		totals["rate_limited"] = totalLimited
+		if totalCalls > 0 {
+			totals["error_rate"] = float64(totalErrors) / float64(totalCalls)
+		} else {
+			totals["error_rate"] = 0.0
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"window_hours": hours,
+			"totals":       totals,
+			"identities":   out,
+		})
+	}
+}
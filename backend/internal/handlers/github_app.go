@@ -17,15 +17,17 @@ import (
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
 )
 
 type GitHubAppHandler struct {
-	cfg config.Config
-	db  *db.DB
+	cfg  config.Config
+	db   *db.DB
+	apps github.Apps
 }
 
-func NewGitHubAppHandler(cfg config.Config, d *db.DB) *GitHubAppHandler {
-	return &GitHubAppHandler{cfg: cfg, db: d}
+func NewGitHubAppHandler(cfg config.Config, d *db.DB, apps github.Apps) *GitHubAppHandler {
+	return &GitHubAppHandler{cfg: cfg, db: d, apps: apps}
 }
 
 // StartInstallation generates a GitHub App installation URL
@@ -48,14 +50,51 @@ func (h *GitHubAppHandler) StartInstallation() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
 		}
 
+		// Installing for a specific ecosystem uses that ecosystem's branded app, if it has one, else the tenant's.
+		app := h.cfg.DefaultGitHubApp()
+		if t := tenant.FromCtx(c); t != nil && t.GitHubAppID != nil {
+			var ok bool
+			app, ok = h.cfg.GitHubApp(*t.GitHubAppID)
+			if !ok {
+				slog.Error("tenant github app is not configured", "tenant", t.Slug, "app_id", *t.GitHubAppID)
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+			}
+		}
+		var ecosystemID *uuid.UUID
+		if raw := strings.TrimSpace(c.Query("ecosystem_id")); raw != "" {
+			ecoID, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+			}
+			var appID *string
+			err = h.db.Pool.QueryRow(c.Context(), `
+SELECT github_app_id FROM ecosystems WHERE id = $1 AND status = 'active'
+`, ecoID).Scan(&appID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+			}
+			if appID != nil {
+				var ok bool
+				app, ok = h.cfg.GitHubApp(*appID)
+				if !ok {
+					slog.Error("ecosystem github app is not configured", "ecosystem_id", ecoID, "app_id", *appID)
+					return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+				}
+			}
+			ecosystemID = &ecoID
+		}
+
 		// Generate state for installation callback
 		state := randomState(32)
 		expiresAt := time.Now().UTC().Add(10 * time.Minute)
 
 		_, err = h.db.Pool.Exec(c.Context(), `
-INSERT INTO oauth_states (state, user_id, kind, expires_at)
-VALUES ($1, $2, 'github_app_install', $3)
-`, state, userID, expiresAt)
+INSERT INTO oauth_states (state, user_id, kind, expires_at, github_app_id, ecosystem_id)
+VALUES ($1, $2, 'github_app_install', $3, $4, $5)
+`, state, userID, expiresAt, app.ID, ecosystemID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "state_create_failed"})
 		}
@@ -63,10 +102,10 @@ VALUES ($1, $2, 'github_app_install', $3)
 		// Build GitHub App installation URL
 		// Format: https://github.com/apps/{app-slug}/installations/new
 		// Or: https://github.com/apps/{app-slug}/installations/new?state={state}
-		appSlug := h.cfg.GitHubAppSlug
+		appSlug := app.Slug
 		if appSlug == "" {
 			// Fallback: use app ID if slug not configured
-			appSlug = h.cfg.GitHubAppID
+			appSlug = app.ID
 		}
 
 		installURL := "https://github.com/apps/" + appSlug + "/installations/new"
@@ -97,7 +136,7 @@ VALUES ($1, $2, 'github_app_install', $3)
 		slog.Info("GitHub App installation started",
 			"user_id", userID,
 			"app_slug", appSlug,
-			"app_id", h.cfg.GitHubAppID,
+			"app_id", app.ID,
 			"state", state,
 			"install_url", installURL,
 			"expected_callback_url", h.cfg.PublicBaseURL+"/auth/github/app/install/callback",
@@ -172,16 +211,20 @@ func (h *GitHubAppHandler) HandleInstallationCallback() fiber.Handler {
 
 		// Verify state and get user ID
 		var userID uuid.UUID
+		var ecosystemID *uuid.UUID
+		// GitHub redirects to the API host, so the tenant comes from the state saved when the install started.
+		var tenantID *uuid.UUID
 		if state != "" {
 			var storedUserID *uuid.UUID
 			var storedKind string
+			var storedAppID *string
 			err := h.db.Pool.QueryRow(c.Context(), `
-SELECT user_id, kind
+SELECT user_id, kind, github_app_id, ecosystem_id, tenant_id
 FROM oauth_states
 WHERE state = $1
   AND expires_at > now()
   AND kind = 'github_app_install'
-`, state).Scan(&storedUserID, &storedKind)
+`, state).Scan(&storedUserID, &storedKind, &storedAppID, &ecosystemID, &tenantID)
 			if errors.Is(err, pgx.ErrNoRows) {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_or_expired_state"})
 			}
@@ -192,6 +235,11 @@ WHERE state = $1
 			if storedUserID != nil {
 				userID = *storedUserID
 			}
+			if storedAppID != nil {
+				if err := github.RecordInstallationApp(c.Context(), h.db.Pool, installationID, *storedAppID); err != nil {
+					slog.Error("failed to record installation app", "installation_id", installationID, "error", err)
+				}
+			}
 
 			// Clean up state
 			_, _ = h.db.Pool.Exec(c.Context(), `DELETE FROM oauth_states WHERE state = $1`, state)
@@ -204,12 +252,23 @@ WHERE state = $1
 				"state", state,
 			)
 		} else {
-			// Sync repositories in background (don't block redirect)
-			go h.syncInstallationRepositories(c.Context(), userID, installationID)
+			// Sync repositories in background (don't block redirect), into the tenant the install started on.
+			ctx := context.Background()
+			if tenantID != nil {
+				ctx = tenant.WithID(ctx, *tenantID)
+			}
+			go h.syncInstallationRepositories(ctx, userID, installationID, ecosystemID)
 		}
 
 		// Redirect to frontend with success message
 		redirectURL := h.cfg.FrontendBaseURL
+		if tenantID != nil {
+			var frontendURL *string
+			_ = h.db.Pool.QueryRow(c.Context(), `SELECT frontend_url FROM tenants WHERE id = $1`, *tenantID).Scan(&frontendURL)
+			if frontendURL != nil && *frontendURL != "" {
+				redirectURL = *frontendURL
+			}
+		}
 		if redirectURL == "" {
 			// Fallback for development
 			redirectURL = "http://localhost:5173"
@@ -247,8 +306,9 @@ WHERE state = $1
 	}
 }
 
-// syncInstallationRepositories syncs repositories from a GitHub App installation
-func (h *GitHubAppHandler) syncInstallationRepositories(ctx context.Context, userID uuid.UUID, installationID string) {
+// syncInstallationRepositories syncs repositories from a GitHub App installation. New projects join
+// ecosystemID when the install was started for an ecosystem, otherwise the default ecosystem.
+func (h *GitHubAppHandler) syncInstallationRepositories(ctx context.Context, userID uuid.UUID, installationID string, ecosystemID *uuid.UUID) {
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
@@ -267,7 +327,7 @@ func (h *GitHubAppHandler) syncInstallationRepositories(ctx context.Context, use
 	}
 
 	// Create GitHub App client
-	appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
+	appClient, err := h.apps.ForInstallation(ctx, installationID)
 	if err != nil {
 		slog.Error("failed to create GitHub App client", "error", err)
 		return
@@ -294,13 +354,17 @@ func (h *GitHubAppHandler) syncInstallationRepositories(ctx context.Context, use
 
 	// Get default ecosystem (or use a fallback)
 	var defaultEcosystemID uuid.UUID
-	err = h.db.Pool.QueryRow(ctx, `
+	if ecosystemID != nil {
+		defaultEcosystemID = *ecosystemID
+	} else {
+		err = h.db.Pool.QueryRow(ctx, `
 SELECT id FROM ecosystems WHERE status = 'active' ORDER BY created_at ASC LIMIT 1
 `).Scan(&defaultEcosystemID)
-	if err != nil {
-		slog.Warn("no active ecosystem found, repositories will be created without ecosystem",
-			"error", err,
-		)
+		if err != nil {
+			slog.Warn("no active ecosystem found, repositories will be created without ecosystem",
+				"error", err,
+			)
+		}
 	}
 
 	// Create projects for each repository (never add or restore private repos)
@@ -375,9 +439,9 @@ VALUES ($1, 'sync_issues', 'pending', now()),
 
 		// Insert project
 		var projectID uuid.UUID
-		var ecosystemID *uuid.UUID
+		var projectEcosystemID *uuid.UUID
 		if defaultEcosystemID != (uuid.UUID{}) {
-			ecosystemID = &defaultEcosystemID
+			projectEcosystemID = &defaultEcosystemID
 		}
 
 		// Only insert public repos; private repos are never added
@@ -390,7 +454,7 @@ ON CONFLICT (github_full_name) DO UPDATE SET
   deleted_at = NULL,
   updated_at = now()
 RETURNING id
-`, userID, repo.FullName, ecosystemID, repo.Language, tagsJSON, installationID).Scan(&projectID)
+`, userID, repo.FullName, projectEcosystemID, repo.Language, tagsJSON, installationID).Scan(&projectID)
 		if err != nil {
 			slog.Error("failed to create project",
 				"error", err,
@@ -84,15 +84,13 @@ WHERE github_app_installation_id IS NOT NULL
 		"count", len(installationIDs),
 	)
 
-	// Create GitHub App client
-	appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
-	if err != nil {
-		slog.Error("failed to create GitHub App client", "error", err)
-		return
-	}
-
-	// Check each installation
+	// Check each installation with the app it belongs to
 	for _, installationID := range installationIDs {
+		appClient, err := github.NewInstallationAppClient(ctx, h.pool, h.cfg, installationID)
+		if err != nil {
+			slog.Error("failed to create GitHub App client", "installation_id", installationID, "error", err)
+			continue
+		}
 		h.checkSingleInstallation(ctx, appClient, installationID)
 	}
 }
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// GitHubDiscussionsHandler serves the GitHub Discussions synced for projects that track them, so ecosystems that
+// reward support work can see and credit answered questions.
+type GitHubDiscussionsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewGitHubDiscussionsHandler(cfg config.Config, d *db.DB) *GitHubDiscussionsHandler {
+	return &GitHubDiscussionsHandler{cfg: cfg, db: d}
+}
+
+type trackDiscussionsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SetTracking serves PUT /projects/:id/github-discussions: turns discussion syncing on or off. Turning it on
+// queues a sync right away; turning it off keeps what was synced.
+func (h *GitHubDiscussionsHandler) SetTracking() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		var req trackDiscussionsRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE projects SET track_discussions = $2, updated_at = now() WHERE id = $1
+`, projectID, req.Enabled); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussions_update_failed"})
+		}
+		if req.Enabled {
+			_, _ = h.db.Pool.Exec(c.Context(), `
+INSERT INTO sync_jobs (project_id, job_type, status, run_at)
+VALUES ($1, 'sync_discussions', 'pending', now())
+`, projectID)
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "track_discussions": req.Enabled})
+	}
+}
+
+// List serves GET /projects/:id/github-discussions: the project's synced discussions, most recently updated
+// first. ?answered=true|false keeps only answered or only open answerable questions.
+func (h *GitHubDiscussionsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		var answered *bool
+		switch c.Query("answered") {
+		case "":
+		case "true":
+			t := true
+			answered = &t
+		case "false":
+			f := false
+			answered = &f
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_answered"})
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+		offset := max(c.QueryInt("offset", 0), 0)
+
+		var tracking bool
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT track_discussions FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&tracking)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT number, title, url, COALESCE(author_login, ''), category, answerable, closed,
+       COALESCE(answer_author_login, ''), COALESCE(answer_url, ''), answered_at, created_at_github, updated_at_github
+FROM github_discussions
+WHERE project_id = $1
+  AND ($2::boolean IS NULL OR (answerable AND (answer_author_login IS NOT NULL) = $2))
+ORDER BY updated_at_github DESC NULLS LAST, number DESC
+LIMIT $3 OFFSET $4
+`, projectID, answered, limit, offset)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussions_fetch_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var number int
+			var title, url, author, category, answerAuthor, answerURL string
+			var answerable, closed bool
+			var answeredAt, createdAt, updatedAt *time.Time
+			if err := rows.Scan(&number, &title, &url, &author, &category, &answerable, &closed, &answerAuthor, &answerURL, &answeredAt, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussions_fetch_failed"})
+			}
+			d := fiber.Map{
+				"number":     number,
+				"title":      title,
+				"url":        url,
+				"author":     author,
+				"category":   category,
+				"answerable": answerable,
+				"closed":     closed,
+				"answered":   answerAuthor != "",
+				"created_at": createdAt,
+				"updated_at": updatedAt,
+			}
+			if answerAuthor != "" {
+				d["answer"] = fiber.Map{"author": answerAuthor, "url": answerURL, "chosen_at": answeredAt}
+			}
+			out = append(out, d)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussions_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"tracking": tracking, "discussions": out, "limit": limit, "offset": offset})
+	}
+}
+
+// EcosystemAnswers serves GET /ecosystems/:id/discussion-answers: who answered the most questions in the
+// ecosystem's projects, by accepted answers. ?since= (RFC 3339) counts only answers chosen after it.
+// Answerers with a Grainlify account carry its user id, so programs can credit them.
+func (h *GitHubDiscussionsHandler) EcosystemAnswers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ecoID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+		}
+		var since *time.Time
+		if raw := c.Query("since"); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_since"})
+			}
+			since = &t
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+
+		var exists bool
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM ecosystems WHERE id = $1 AND status = 'active')
+`, ecoID).Scan(&exists)
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT a.login, a.answers, a.projects, a.last_answered_at, ga.user_id
+FROM (
+  SELECT MIN(d.answer_author_login) AS login, COUNT(*) AS answers, COUNT(DISTINCT d.project_id) AS projects,
+         MAX(d.answered_at) AS last_answered_at
+  FROM github_discussions d
+  JOIN projects p ON p.id = d.project_id
+  WHERE p.ecosystem_id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND p.track_discussions
+    AND d.answerable AND d.answer_author_login IS NOT NULL
+    -- Answering your own question earns nothing.
+    AND LOWER(d.answer_author_login) <> LOWER(COALESCE(d.author_login, ''))
+    AND ($2::timestamptz IS NULL OR d.answered_at >= $2)
+  GROUP BY LOWER(d.answer_author_login)
+) a
+LEFT JOIN github_accounts ga ON LOWER(ga.login) = LOWER(a.login)
+ORDER BY a.answers DESC, a.last_answered_at DESC
+LIMIT $3
+`, ecoID, since, limit)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_answers_fetch_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var login string
+			var answers, projects int
+			var last *time.Time
+			var userID *uuid.UUID
+			if err := rows.Scan(&login, &answers, &projects, &last, &userID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_answers_fetch_failed"})
+			}
+			out = append(out, fiber.Map{
+				"login":            login,
+				"user_id":          userID,
+				"answers":          answers,
+				"projects":         projects,
+				"last_answered_at": last,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_answers_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"answerers": out})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/dryrun"
+)
+
+// GitHubDryRunHandler exposes dry-run mode: per-request opt-in for destructive maintainer actions, and the
+// record of mutations that were intercepted instead of sent to GitHub.
+type GitHubDryRunHandler struct {
+	db *db.DB
+}
+
+func NewGitHubDryRunHandler(d *db.DB) *GitHubDryRunHandler {
+	return &GitHubDryRunHandler{db: d}
+}
+
+// Mark turns the request into a dry run when it asks for one with ?dry_run=true or an X-Dry-Run: true header.
+// Grainlify's own records are still updated; only the GitHub side (assignees, comments, deletions) is skipped.
+func (h *GitHubDryRunHandler) Mark() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		v := c.Query("dry_run")
+		if v == "" {
+			v = c.Get("X-Dry-Run")
+		}
+		if on, _ := strconv.ParseBool(strings.TrimSpace(v)); on {
+			c.Locals(dryrun.Key, &dryrun.Collector{})
+		}
+		return c.Next()
+	}
+}
+
+// withDryRun adds the intercepted GitHub mutations to a response when the request ran in dry-run mode.
+func withDryRun(c *fiber.Ctx, out fiber.Map) fiber.Map {
+	if col, ok := c.Locals(dryrun.Key).(*dryrun.Collector); ok {
+		out["dry_run"] = true
+		out["github_mutations"] = col.Mutations()
+	} else if dryrun.Enabled() {
+		out["dry_run"] = true
+	}
+	return out
+}
+
+// List returns recorded mutations, newest first. Optional: scope (environment|request), limit, offset.
+func (h *GitHubDryRunHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+		offset := c.QueryInt("offset", 0)
+		if offset < 0 {
+			offset = 0
+		}
+		scope := strings.TrimSpace(c.Query("scope"))
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, method, url, body, scope, created_at
+FROM github_dry_run_mutations
+WHERE ($1 = '' OR scope = $1)
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`, scope, limit, offset)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dry_run_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var method, url, sc string
+			var body []byte
+			var createdAt time.Time
+			if err := rows.Scan(&id, &method, &url, &body, &sc, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "dry_run_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":         id.String(),
+				"method":     method,
+				"url":        url,
+				"body":       json.RawMessage(body),
+				"scope":      sc,
+				"created_at": createdAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"enabled":   dryrun.Enabled(),
+			"mutations": out,
+			"limit":     limit,
+			"offset":    offset,
+		})
+	}
+}
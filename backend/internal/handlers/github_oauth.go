@@ -19,6 +19,7 @@ import (
 	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
 )
 
 // isAllowedRedirectURI validates that a redirect URI is from an allowed origin.
@@ -75,10 +76,11 @@ func isAllowedRedirectURI(redirectURI string, cfg config.Config) bool {
 type GitHubOAuthHandler struct {
 	cfg config.Config
 	db  *db.DB
+	gh  github.API
 }
 
-func NewGitHubOAuthHandler(cfg config.Config, d *db.DB) *GitHubOAuthHandler {
-	return &GitHubOAuthHandler{cfg: cfg, db: d}
+func NewGitHubOAuthHandler(cfg config.Config, d *db.DB, gh github.API) *GitHubOAuthHandler {
+	return &GitHubOAuthHandler{cfg: cfg, db: d, gh: gh}
 }
 
 func (h *GitHubOAuthHandler) Start() fiber.Handler {
@@ -147,7 +149,7 @@ func (h *GitHubOAuthHandler) LoginStart() fiber.Handler {
 
 			// Security: Only allow redirects to whitelisted origins
 			// This prevents open redirect vulnerabilities
-			if !isAllowedRedirectURI(redirectURI, h.cfg) {
+			if !isAllowedRedirectURI(redirectURI, h.cfg) && !tenant.FromCtx(c).HasHost(parsedURL.Host) {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 					"error":   "redirect_uri_not_allowed",
 					"message": "Redirect URI must be from an allowed origin (localhost, *.vercel.app, or configured CORS origins)",
@@ -320,7 +322,7 @@ WHERE state = $1
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "token_exchange_failed"})
 		}
 
-		encKey, err := cryptox.KeyFromB64(h.cfg.TokenEncKeyB64)
+		encKey, err := cryptox.KeyFromB64(h.cfg.TokenEncKey())
 		if err != nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "token_encryption_not_configured"})
 		}
@@ -329,7 +331,7 @@ WHERE state = $1
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "token_encrypt_failed"})
 		}
 
-		gh := github.NewClient()
+		gh := h.gh
 		u, err := gh.GetUser(c.Context(), tr.AccessToken)
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "github_user_fetch_failed"})
@@ -85,7 +85,8 @@ func (h *GitHubWebhooksHandler) Receive() fiber.Handler {
 			"body_size", bodySize,
 		)
 
-		if h.cfg.GitHubWebhookSecret == "" {
+		secrets := h.cfg.GitHubWebhookSecrets()
+		if len(secrets) == 0 {
 			slog.Error("GitHub webhook secret not configured - rejecting request",
 				"delivery_id", delivery,
 				"event", event,
@@ -104,7 +105,15 @@ func (h *GitHubWebhooksHandler) Receive() fiber.Handler {
 			sigPreview = sigPreview[:20] + "..."
 		}
 
-		if !verifyGitHubSignature(h.cfg.GitHubWebhookSecret, body, sig) {
+		// Each configured GitHub App delivers webhooks signed with its own secret.
+		verified := false
+		for _, secret := range secrets {
+			if verifyGitHubSignature(secret, body, sig) {
+				verified = true
+				break
+			}
+		}
+		if !verified {
 			slog.Warn("GitHub webhook signature verification FAILED",
 				"delivery_id", delivery,
 				"event", event,
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/mailer"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+// IdentityLoginHandler signs users in with Google or an emailed link, for ecosystem admins and observers
+// who have no GitHub account. Such users can view dashboards; applying to issues still needs GitHub linked.
+type IdentityLoginHandler struct {
+	cfg  config.Config
+	db   *db.DB
+	mail mailer.Mailer
+}
+
+func NewIdentityLoginHandler(cfg config.Config, d *db.DB) *IdentityLoginHandler {
+	m, err := mailer.New(cfg.SMTPURL, cfg.MailFrom)
+	if err != nil {
+		slog.Warn("sign-in link emails disabled", "error", err)
+		m, _ = mailer.New("", "")
+	}
+	return &IdentityLoginHandler{cfg: cfg, db: d, mail: m}
+}
+
+// GoogleLoginStart begins Google sign-in. Like GitHub login it accepts a 'redirect' query parameter naming
+// the frontend origin to return to.
+func (h *IdentityLoginHandler) GoogleLoginStart() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.cfg.GoogleOAuthClientID == "" || h.cfg.GoogleOAuthRedirectURL == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "google_login_not_configured"})
+		}
+		redirectURI := c.Query("redirect")
+		if code := checkLoginRedirect(c, h.cfg, redirectURI); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+
+		state := randomState(32)
+		_, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO oauth_states (state, user_id, kind, expires_at, redirect_uri)
+VALUES ($1, NULL, 'google_login', $2, $3)
+`, state, time.Now().UTC().Add(10*time.Minute), redirectURI)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "state_create_failed"})
+		}
+		authURL, err := auth.GoogleAuthorizeURL(h.cfg.GoogleOAuthClientID, h.cfg.GoogleOAuthRedirectURL, state)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "auth_url_failed"})
+		}
+		return c.Redirect(authURL, fiber.StatusFound)
+	}
+}
+
+// GoogleLoginCallback finishes Google sign-in and redirects to the frontend with a session token.
+func (h *IdentityLoginHandler) GoogleLoginCallback() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.cfg.GoogleOAuthClientID == "" || h.cfg.GoogleOAuthClientSecret == "" || h.cfg.GoogleOAuthRedirectURL == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "google_login_not_configured"})
+		}
+		if h.cfg.JWTSecret == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "jwt_not_configured"})
+		}
+		code, state := c.Query("code"), c.Query("state")
+		if code == "" || state == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing_code_or_state"})
+		}
+
+		var redirectURI *string
+		err := h.db.Pool.QueryRow(c.Context(), `
+DELETE FROM oauth_states
+WHERE state = $1 AND kind = 'google_login' AND expires_at > now()
+RETURNING redirect_uri
+`, state).Scan(&redirectURI)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_or_expired_state"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "state_lookup_failed"})
+		}
+
+		gu, err := auth.GoogleUserFromCode(c.Context(), h.cfg.GoogleOAuthClientID, h.cfg.GoogleOAuthClientSecret, h.cfg.GoogleOAuthRedirectURL, code)
+		if err != nil {
+			slog.Warn("google sign-in failed", "error", err)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "token_exchange_failed"})
+		}
+		email, err := auth.NormalizeEmail(gu.Email)
+		if err != nil || !gu.EmailVerified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "email_not_verified"})
+		}
+		user, err := auth.UpsertIdentityUser(c.Context(), h.db.Pool, auth.ProviderGoogle, gu.Subject, email)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_upsert_failed"})
+		}
+		stored := ""
+		if redirectURI != nil {
+			stored = *redirectURI
+		}
+		return h.finishLogin(c, user, auth.ProviderGoogle, email, stored)
+	}
+}
+
+type magicLinkRequest struct {
+	Email    string `json:"email"`
+	Redirect string `json:"redirect"`
+}
+
+// MagicLinkStart emails a single-use sign-in link. It answers the same whether or not the address has an
+// account, so it can't be used to probe for users.
+func (h *IdentityLoginHandler) MagicLinkStart() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if strings.TrimSpace(h.cfg.PublicBaseURL) == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "email_login_not_configured"})
+		}
+		var req magicLinkRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		email, err := auth.NormalizeEmail(req.Email)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_email"})
+		}
+		if code := checkLoginRedirect(c, h.cfg, req.Redirect); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+
+		token, err := auth.CreateMagicLink(c.Context(), h.db.Pool, email, req.Redirect)
+		if errors.Is(err, auth.ErrTooManyMagicLinks) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "too_many_requests"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "magic_link_create_failed"})
+		}
+		link := strings.TrimSuffix(h.cfg.PublicBaseURL, "/") + "/auth/email/callback?" + url.Values{"token": {token}}.Encode()
+		if err := h.mail.Send(c.Context(), mailer.Message{
+			To:      []string{email},
+			Subject: "Your Grainlify sign-in link",
+			Text: "Use this link to sign in to Grainlify:\n\n" + link + "\n\n" +
+				fmt.Sprintf("It works once and expires in %d minutes. If you didn't ask for it, you can ignore this email.\n", int(auth.MagicLinkTTL/time.Minute)),
+		}); err != nil {
+			slog.Warn("failed to send sign-in link", "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "email_send_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "expires_in_seconds": int(auth.MagicLinkTTL.Seconds())})
+	}
+}
+
+// MagicLinkCallback is the emailed link: it signs the user in and redirects to the frontend.
+func (h *IdentityLoginHandler) MagicLinkCallback() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.cfg.JWTSecret == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "jwt_not_configured"})
+		}
+		token := c.Query("token")
+		if token == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing_token"})
+		}
+		email, redirectURI, err := auth.ConsumeMagicLink(c.Context(), h.db.Pool, token)
+		if errors.Is(err, auth.ErrInvalidOrExpiredLink) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_or_expired_link"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "magic_link_lookup_failed"})
+		}
+		// Receiving the link proves the address, so it serves as both subject and verified email.
+		user, err := auth.UpsertIdentityUser(c.Context(), h.db.Pool, auth.ProviderEmail, email, email)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_upsert_failed"})
+		}
+		return h.finishLogin(c, user, auth.ProviderEmail, email, redirectURI)
+	}
+}
+
+// checkLoginRedirect validates a frontend origin to return to after sign-in, as GitHub login does.
+func checkLoginRedirect(c *fiber.Ctx, cfg config.Config, redirectURI string) string {
+	if redirectURI == "" {
+		return ""
+	}
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "invalid_redirect_uri"
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "invalid_redirect_uri_scheme"
+	}
+	if !isAllowedRedirectURI(redirectURI, cfg) && !tenant.FromCtx(c).HasHost(u.Host) {
+		return "redirect_uri_not_allowed"
+	}
+	return ""
+}
+
+// finishLogin issues the session token and hands it to the frontend.
+func (h *IdentityLoginHandler) finishLogin(c *fiber.Ctx, user auth.User, provider, email, redirectURI string) error {
+	token, err := auth.IssueJWT(h.cfg.JWTSecret, user.ID, user.Role, "", "", 60*time.Minute)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "token_issue_failed"})
+	}
+	return redirectWithToken(c, h.cfg, token, user, provider, email, redirectURI)
+}
+
+// redirectWithToken sends the browser to the frontend's /auth/callback with the session token, or returns
+// the token as JSON when there is nowhere to redirect.
+func redirectWithToken(c *fiber.Ctx, cfg config.Config, token string, user auth.User, provider, email, redirectURI string) error {
+	base := redirectURI
+	if base == "" {
+		base = cfg.FrontendBaseURL
+	}
+	if base != "" {
+		if ru, err := url.Parse(strings.TrimSuffix(base, "/") + "/auth/callback"); err == nil {
+			q := ru.Query()
+			q.Set("token", token)
+			q.Set("provider", provider)
+			ru.RawQuery = q.Encode()
+			return c.Redirect(ru.String(), fiber.StatusFound)
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"token":    token,
+		"user":     fiber.Map{"id": user.ID.String(), "role": user.Role},
+		"provider": provider,
+		"email":    email,
+	})
+}
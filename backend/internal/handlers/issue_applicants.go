@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/availability"
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+	"github.com/jagadeesh/grainlify/backend/internal/skills"
+)
+
+// Applicants lists an issue's applications for its maintainers, ranked by fit: pending applications
+// first (those held while the applicant is paused come after), then by how many of the applicant's
+// declared skills match the project's language, the issue's labels and the project's tags, then by PRs
+// they already had merged in the project. Each applicant with an account carries their availability
+// (time zone, weekly hours, time away). On projects with anonymous applications, applicants who haven't
+// been assigned are listed under an alias, without their login or user id. Assigned applicants carry
+// the progress of their work: a draft or ready pull request for the issue and when they last pushed.
+func (h *IssueApplicationsHandler) Applicants() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		var language string
+		var tagsJSON []byte
+		var anonymous bool
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id, COALESCE(language, ''), COALESCE(tags, '[]'::jsonb), anonymous_applications
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner, &language, &tagsJSON, &anonymous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		var labelsJSON []byte
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT COALESCE(labels, '[]'::jsonb) FROM github_issues WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber).Scan(&labelsJSON)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+		labels := recommend.LabelNames(labelsJSON)
+		tags := recommend.LabelNames(tagsJSON)
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT a.id, a.user_id, a.github_login, a.source, a.status, a.message, a.created_at, a.held_at, a.reviewed_at,
+       a.stage, a.stage_details, a.stage_due_at,
+       CASE WHEN u.paused_until > now() THEN u.paused_until END,
+       COALESCE(u.skills, '{}'), u.availability,
+       (SELECT COUNT(*) FROM github_pull_requests pr
+        WHERE pr.project_id = a.project_id AND pr.merged = true AND LOWER(pr.author_login) = LOWER(a.github_login))
+FROM issue_applications a
+LEFT JOIN users u ON u.id = a.user_id
+WHERE a.project_id = $1 AND a.issue_number = $2 AND a.status <> 'withdrawn'
+`, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "applications_lookup_failed"})
+		}
+		defer rows.Close()
+
+		type applicant struct {
+			ID          uuid.UUID  `json:"id"`
+			UserID      *uuid.UUID `json:"user_id"`
+			GitHubLogin string     `json:"github_login"`
+			Source      string     `json:"source"`
+			Status      string     `json:"status"`
+			Message     *string    `json:"message"`
+			CreatedAt   time.Time  `json:"created_at"`
+			// HeldAt is set while the applicant is paused (vacation mode) until PausedUntil.
+			HeldAt      *time.Time `json:"held_at"`
+			PausedUntil *time.Time `json:"paused_until"`
+			ReviewedAt  *time.Time `json:"reviewed_at"`
+			// Stage is where the application is between applying and assignment (see package appstage).
+			Stage         string     `json:"stage"`
+			StageDetails  *string    `json:"stage_details"`
+			StageDueAt    *time.Time `json:"stage_due_at"`
+			Skills        []string   `json:"skills"`
+			MatchedSkills []string   `json:"matched_skills"`
+			MergedPRs     int        `json:"merged_prs_in_project"`
+			// Availability is nil for applicants without an account; Set is false when they use the default.
+			Availability *applicantAvailability `json:"availability"`
+			// Anonymous applicants have an alias as GitHubLogin and no UserID; act on them by application id.
+			Anonymous bool `json:"anonymous"`
+			// Progress is set on the current assignment: draft or ready pull request and the last push.
+			Progress *assignmentProgress `json:"progress,omitempty"`
+		}
+		now := time.Now()
+		out := []applicant{}
+		for rows.Next() {
+			var a applicant
+			var availabilityJSON []byte
+			if err := rows.Scan(&a.ID, &a.UserID, &a.GitHubLogin, &a.Source, &a.Status, &a.Message, &a.CreatedAt,
+				&a.HeldAt, &a.ReviewedAt, &a.Stage, &a.StageDetails, &a.StageDueAt, &a.PausedUntil, &a.Skills, &availabilityJSON, &a.MergedPRs); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "applications_lookup_failed"})
+			}
+			a.MatchedSkills = skills.Match(a.Skills, language, labels, tags)
+			if a.MatchedSkills == nil {
+				a.MatchedSkills = []string{}
+			}
+			if a.UserID != nil {
+				sched, set := parseAvailability(availabilityJSON)
+				a.Availability = &applicantAvailability{
+					TimeZone:     sched.TimeZone,
+					HoursPerWeek: sched.HoursPerWeek(),
+					Weekly:       sched.Weekly,
+					Away:         sched.Away(now),
+					NextAway:     sched.NextAway(now),
+					Set:          set,
+				}
+			}
+			if anonymous && !applicationRevealed(a.Status) {
+				a.Anonymous = true
+				a.UserID = nil
+				a.GitHubLogin = applicantAlias(a.ID)
+			}
+			out = append(out, a)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "applications_lookup_failed"})
+		}
+		rows.Close()
+		for i := range out {
+			if out[i].Status != "assigned" {
+				continue
+			}
+			p, err := h.assignmentProgress(c.Context(), projectID, issueNumber, out[i].GitHubLogin)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "applications_lookup_failed"})
+			}
+			out[i].Progress = &p
+		}
+
+		sort.SliceStable(out, func(i, j int) bool {
+			a, b := out[i], out[j]
+			if aOpen, bOpen := a.Status == "pending" && a.HeldAt == nil, b.Status == "pending" && b.HeldAt == nil; aOpen != bOpen {
+				return aOpen
+			}
+			if len(a.MatchedSkills) != len(b.MatchedSkills) {
+				return len(a.MatchedSkills) > len(b.MatchedSkills)
+			}
+			if a.MergedPRs != b.MergedPRs {
+				return a.MergedPRs > b.MergedPRs
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"applications": out})
+	}
+}
+
+// applicationRevealed reports whether an anonymous application shows who applied: once it was accepted.
+func applicationRevealed(status string) bool {
+	return status == "assigned" || status == "unassigned"
+}
+
+// applicantAlias names an anonymous applicant, stably across requests.
+func applicantAlias(applicationID uuid.UUID) string {
+	return "Applicant " + strings.ToUpper(applicationID.String()[:6])
+}
+
+// applicantAvailability summarises an applicant's schedule (see package availability) for maintainers.
+type applicantAvailability struct {
+	TimeZone     string                           `json:"timezone"`
+	HoursPerWeek float64                          `json:"hours_per_week"`
+	Weekly       map[string][]availability.Window `json:"weekly"`
+	Away         *availability.Period             `json:"away"`
+	NextAway     *availability.Period             `json:"next_away"`
+	Set          bool                             `json:"set"`
+}
+
+// SkillVocabulary serves GET /skills: the skills contributors can declare on their profile.
+func SkillVocabulary() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Cache-Control", "public, max-age=3600")
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"skills": skills.All(), "max_per_user": skills.MaxPerUser})
+	}
+}
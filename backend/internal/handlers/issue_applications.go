@@ -1,34 +1,58 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/availability"
+	"github.com/jagadeesh/grainlify/backend/internal/botcomments"
+	"github.com/jagadeesh/grainlify/backend/internal/botmessages"
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/mdsanitize"
+	"github.com/jagadeesh/grainlify/backend/internal/moderation"
+	"github.com/jagadeesh/grainlify/backend/internal/pause"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+	"github.com/jagadeesh/grainlify/backend/internal/waitlist"
 )
 
 
 type IssueApplicationsHandler struct {
-	cfg config.Config
-	db  *db.DB
+	cfg  config.Config
+	db   *db.DB
+	gh   github.API
+	apps github.Apps
 }
 
-func NewIssueApplicationsHandler(cfg config.Config, d *db.DB) *IssueApplicationsHandler {
-	return &IssueApplicationsHandler{cfg: cfg, db: d}
+func NewIssueApplicationsHandler(cfg config.Config, d *db.DB, gh github.API, apps github.Apps) *IssueApplicationsHandler {
+	return &IssueApplicationsHandler{cfg: cfg, db: d, gh: gh, apps: apps}
 }
 
+const (
+	maxApplicationMessageLen = 5000
+	// maxBotCommentLen is well under GitHub's 65536-character comment limit.
+	maxBotCommentLen = 32000
+)
+
 type applyToIssueRequest struct {
-	Message string `json:"message"`
+	Message string              `json:"message"`
+	Answers []applicationAnswer `json:"answers"`
 }
 
 func (h *IssueApplicationsHandler) Apply() fiber.Handler {
@@ -36,7 +60,7 @@ func (h *IssueApplicationsHandler) Apply() fiber.Handler {
 		if h.db == nil || h.db.Pool == nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
 		}
-		if strings.TrimSpace(h.cfg.TokenEncKeyB64) == "" {
+		if strings.TrimSpace(h.cfg.TokenEncKey()) == "" {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "token_encryption_not_configured"})
 		}
 
@@ -60,32 +84,40 @@ func (h *IssueApplicationsHandler) Apply() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
 		}
 		req.Message = strings.TrimSpace(req.Message)
-		if req.Message == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "message_required"})
+		var v validate.Validator
+		if v.Required("message", req.Message) {
+			v.MaxLen("message", req.Message, maxApplicationMessageLen)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
 		}
-		if len(req.Message) > 5000 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "message_too_long"})
+		if out := moderate(c.Context(), h.cfg, moderation.KindApplicationMessage, "message", req.Message); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
 		}
 
-		linked, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+		linked, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
 		}
+		// Paused contributors resume first; their existing applications are on hold meanwhile.
+		if until, err := pause.UntilForLogin(c.Context(), h.db.Pool, linked.Login); err == nil && until != nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "contributor_paused", "paused_until": until})
+		}
 
 		// Load repo + issue state, issue URL, and github_issue_id for dashboard deep link.
-		var fullName, issueURL string
+		var fullName, issueURL, visibility string
 		var state string
 		var authorLogin string
-		var assigneesJSON []byte
+		var assigneesJSON, commentsJSON []byte
 		var githubIssueID int64
 		if err := h.db.Pool.QueryRow(c.Context(), `
-SELECT p.github_full_name, gi.state, gi.author_login, gi.assignees, COALESCE(gi.url, ''), gi.github_issue_id
+SELECT p.github_full_name, p.application_visibility, gi.state, gi.author_login, gi.assignees, COALESCE(gi.comments, '[]'::jsonb), COALESCE(gi.url, ''), gi.github_issue_id
 FROM projects p
 JOIN github_issues gi ON gi.project_id = p.id
 WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
   AND gi.number = $2
 LIMIT 1
-`, projectID, issueNumber).Scan(&fullName, &state, &authorLogin, &assigneesJSON, &issueURL, &githubIssueID); err != nil {
+`, projectID, issueNumber).Scan(&fullName, &visibility, &state, &authorLogin, &assigneesJSON, &commentsJSON, &issueURL, &githubIssueID); err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
 		}
 
@@ -103,28 +135,62 @@ LIMIT 1
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issue_already_assigned"})
 		}
 
-		// Build Drips Wave–style template: header, blockquote for message, maintainer instructions with links.
-		quotedLines := strings.Split(req.Message, "\n")
-		for i := range quotedLines {
-			quotedLines[i] = "> " + quotedLines[i]
+		// One application per user per issue. Applications made before they were tracked in
+		// issue_applications are only visible as comments, so fall back to scanning those.
+		var previousStatus string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT status FROM issue_applications
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3)
+ORDER BY (status = 'pending') DESC, created_at DESC
+LIMIT 1
+`, projectID, issueNumber, linked.Login).Scan(&previousStatus)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_lookup_failed"})
+		}
+		if previousStatus == "pending" || (errors.Is(err, pgx.ErrNoRows) && hasApplicationComment(commentsJSON, linked.Login)) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "already_applied"})
+		}
+
+		// Answers to the project's Q&A checklist are optional here; they can also be submitted later.
+		if len(req.Answers) > 0 {
+			if code, err := saveApplicationAnswers(c.Context(), h.db.Pool, projectID, issueNumber, userID, req.Answers); code != "" {
+				if err != nil {
+					slog.Error("failed to save application answers", "error", err, "project_id", projectID.String(), "issue_number", issueNumber)
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": code})
+				}
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+			}
+		}
+
+		// Reserve the application before commenting so concurrent double-submits can't both post
+		// (only one pending application per login is allowed by a unique index).
+		var applicationID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO issue_applications (project_id, issue_number, user_id, github_login, source, message)
+VALUES ($1, $2, $3, $4, 'dashboard', $5)
+ON CONFLICT DO NOTHING
+RETURNING id
+`, projectID, issueNumber, userID, linked.Login, req.Message).Scan(&applicationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "already_applied"})
 		}
-		quotedMsg := strings.Join(quotedLines, "\n")
-		// Deep link to this issue in the dashboard so "review their application" opens the exact issue.
-		base := strings.TrimSpace(strings.TrimRight(h.cfg.FrontendBaseURL, "/"))
-		reviewURL := fmt.Sprintf("%s/dashboard?tab=browse&project=%s&issue=%d", base, projectID.String(), githubIssueID)
-		if base == "" || !strings.HasPrefix(base, "http") {
-			// Fallback: relative path only if FrontendBaseURL not configured (link will use current origin)
-			reviewURL = fmt.Sprintf("/dashboard?tab=browse&project=%s&issue=%d", projectID.String(), githubIssueID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_create_failed"})
 		}
-		if issueURL == "" {
-			issueURL = fmt.Sprintf("https://github.com/%s/issues/%d", fullName, issueNumber)
+
+		// Projects can keep applications off GitHub; maintainers still see them in the dashboard. Native
+		// tasks have no GitHub issue to comment on.
+		if visibility == "private" || tasks.IsNative(issueNumber) {
+			h.refreshStatusComment(c.Context(), projectID, issueNumber)
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "application_id": applicationID.String(), "visibility": visibility})
 		}
-		commentBody := fmt.Sprintf("**📋 Grainlify Application**\n\n**@%s has applied to work on this issue as part of the Grainlify program.**\n\n%s\n\n---\n\n**Repo Maintainers:** To accept this application, [review their application](%s) or [assign @%s](%s) to this issue.",
-			linked.Login, quotedMsg, reviewURL, linked.Login, issueURL)
-		gh := github.NewClient()
+		commentBody := h.applicationCommentBody(projectID, githubIssueID, fullName, issueNumber, issueURL, linked.Login, applicationCommentMessage(visibility, req.Message))
+
+		gh := h.gh
 		// Post as the applicant (user token) so the commenter is the user, not the bot (like Drips Wave: user + "with Drips Wave").
 		ghComment, err := gh.CreateIssueComment(c.Context(), linked.AccessToken, fullName, issueNumber, commentBody)
 		if err != nil {
+			_, _ = h.db.Pool.Exec(c.Context(), `DELETE FROM issue_applications WHERE id = $1`, applicationID)
 			slog.Warn("failed to create github issue comment for application",
 				"project_id", projectID.String(),
 				"issue_number", issueNumber,
@@ -147,8 +213,16 @@ SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
 WHERE project_id = $1 AND number = $2
 `, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
 
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE issue_applications SET github_comment_id = $2, updated_at = now() WHERE id = $1
+`, applicationID, ghComment.ID)
+
+		h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
 			"ok": true,
+			"application_id": applicationID.String(),
+			"visibility": visibility,
 			"comment": fiber.Map{
 				"id": ghComment.ID,
 				"body": ghComment.Body,
@@ -160,12 +234,80 @@ WHERE project_id = $1 AND number = $2
 	}
 }
 
+// ApplyPreview renders the comment Apply would post, without posting it or recording an application.
+func (h *IssueApplicationsHandler) ApplyPreview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req applyToIssueRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Message = strings.TrimSpace(req.Message)
+		var v validate.Validator
+		if !v.MaxLen("message", req.Message, maxApplicationMessageLen) {
+			return validationFailed(c, v.Err())
+		}
+		if out := moderate(c.Context(), h.cfg, moderation.KindApplicationMessage, "message", req.Message); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
+		}
+
+		var login string
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1`, userID).Scan(&login); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		}
+
+		var fullName, issueURL, visibility string
+		var githubIssueID int64
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT p.github_full_name, p.application_visibility, COALESCE(gi.url, ''), gi.github_issue_id
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND gi.number = $2
+LIMIT 1
+`, projectID, issueNumber).Scan(&fullName, &visibility, &issueURL, &githubIssueID); err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+
+		// A private application posts nothing, so there is no body to preview.
+		body := ""
+		if visibility != "private" {
+			body = h.applicationCommentBody(projectID, githubIssueID, fullName, issueNumber, issueURL, login, applicationCommentMessage(visibility, req.Message))
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"body": body, "visibility": visibility})
+	}
+}
+
 type botCommentRequest struct {
-	Body string `json:"body"`
+	Body       string            `json:"body"`
+	TemplateID string            `json:"template_id"`
+	Variables  map[string]string `json:"variables"`
+	// Optional scheduling: either an absolute send_at or a delay from now.
+	SendAt       *time.Time `json:"send_at"`
+	DelayMinutes int        `json:"delay_minutes"`
 }
 
 // PostBotComment posts a comment on a GitHub issue as the Grainlify GitHub App (bot).
 // Requires project maintainer (owner) or admin. Project must have GitHub App installed.
+// The body may come from a saved template (template_id) and may contain {{variables}};
+// when send_at/delay_minutes is set the comment is queued for the scheduler instead. Over the issue's bot
+// comment limit (see botcomments.Post) the message is folded into the status comment and 202 is returned.
 func (h *IssueApplicationsHandler) PostBotComment() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -183,6 +325,9 @@ func (h *IssueApplicationsHandler) PostBotComment() fiber.Handler {
 		if err != nil || issueNumber <= 0 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
 		}
+		if tasks.IsNative(issueNumber) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "native_task_not_on_github"})
+		}
 
 		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
 		userID, err := uuid.Parse(userIDStr)
@@ -195,12 +340,38 @@ func (h *IssueApplicationsHandler) PostBotComment() fiber.Handler {
 		if err := c.BodyParser(&req); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
 		}
+		var templateID *uuid.UUID
+		if strings.TrimSpace(req.TemplateID) != "" {
+			tid, err := uuid.Parse(strings.TrimSpace(req.TemplateID))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_template_id"})
+			}
+			var tplBody string
+			err = h.db.Pool.QueryRow(c.Context(), `
+SELECT body FROM bot_comment_templates WHERE id = $1 AND project_id = $2
+`, tid, projectID).Scan(&tplBody)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "template_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "template_lookup_failed"})
+			}
+			templateID = &tid
+			req.Body = tplBody
+		}
 		req.Body = strings.TrimSpace(req.Body)
-		if req.Body == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_required"})
+		var v validate.Validator
+		if v.Required("body", req.Body) {
+			v.MaxLen("body", req.Body, maxBotCommentLen)
 		}
-		if len(req.Body) > 32000 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_too_long"})
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		if out := moderate(c.Context(), h.cfg, moderation.KindBotComment, "body", req.Body); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
+		}
+		if req.DelayMinutes < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_delay_minutes"})
 		}
 
 		var owner uuid.UUID
@@ -216,60 +387,95 @@ WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
 		}
-		if owner != userID && role != "admin" {
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
 		}
 		if installationID == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
 		}
 
-		appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
-		if err != nil {
-			slog.Error("failed to create GitHub App client for bot comment", "error", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_app_client_failed"})
+		if req.SendAt != nil || req.DelayMinutes > 0 {
+			sendAt := time.Now().Add(time.Duration(req.DelayMinutes) * time.Minute)
+			if req.SendAt != nil {
+				sendAt = *req.SendAt
+			}
+			varsJSON, _ := json.Marshal(req.Variables)
+			if req.Variables == nil {
+				varsJSON = []byte("{}")
+			}
+			var scheduledID uuid.UUID
+			if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO scheduled_bot_comments (project_id, issue_number, template_id, body, variables, send_at, created_by_user_id)
+VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7)
+RETURNING id
+`, projectID, issueNumber, templateID, req.Body, varsJSON, sendAt, userID).Scan(&scheduledID); err != nil {
+				slog.Error("failed to schedule bot comment", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "bot_comment_schedule_failed"})
+			}
+			return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+				"ok":           true,
+				"scheduled_id": scheduledID.String(),
+				"send_at":      sendAt,
+			})
 		}
-		token, err := appClient.GetInstallationToken(c.Context(), installationID)
+
+		builtins, _, err := botcomments.IssueVariables(c.Context(), h.db.Pool, projectID, issueNumber)
 		if err != nil {
-			slog.Warn("failed to get installation token for bot comment",
-				"project_id", projectID.String(),
-				"installation_id", installationID,
-				"error", err,
-			)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "installation_token_failed"})
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
 		}
+		vars := bottemplate.Merge(builtins, req.Variables)
+		req.Body = botcomments.SanitizeBody(bottemplate.Render(req.Body, vars), vars)
 
-		gh := github.NewClient()
-		ghComment, err := gh.CreateIssueComment(c.Context(), token, fullName, issueNumber, req.Body)
-		if err != nil {
-			slog.Warn("failed to post bot comment on GitHub",
-				"project_id", projectID.String(),
-				"issue_number", issueNumber,
-				"github_full_name", fullName,
-				"error", err,
-			)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_comment_create_failed"})
+		status, out := h.postBotComment(c.Context(), projectID, issueNumber, fullName, installationID, req.Body)
+		if status == fiber.StatusBadGateway {
+			return h.queueForRetry(c, projectID, issueNumber, "comment", botCommentRetry{Body: req.Body}, out)
 		}
+		return c.Status(status).JSON(out)
+	}
+}
 
-		commentJSON, _ := json.Marshal(ghComment)
-		_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues
-SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
-    comments_count = COALESCE(comments_count, 0) + 1,
-    updated_at_github = $4,
-    last_seen_at = now()
-WHERE project_id = $1 AND number = $2
-`, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
+// postBotComment posts an already rendered bot comment, shared with the action queue.
+func (h *IssueApplicationsHandler) postBotComment(ctx context.Context, projectID uuid.UUID, issueNumber int, fullName, installationID, body string) (int, fiber.Map) {
+	appClient, err := h.apps.ForInstallation(ctx, installationID)
+	if err != nil {
+		slog.Error("failed to create GitHub App client for bot comment", "error", err)
+		return fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		slog.Warn("failed to get installation token for bot comment",
+			"project_id", projectID.String(),
+			"installation_id", installationID,
+			"error", err,
+		)
+		return fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
+	}
 
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"ok": true,
-			"comment": fiber.Map{
-				"id": ghComment.ID,
-				"body": ghComment.Body,
-				"user": fiber.Map{"login": ghComment.User.Login},
-				"created_at": ghComment.CreatedAt,
-				"updated_at": ghComment.UpdatedAt,
-			},
-		})
+	ghComment, coalesced, err := botcomments.Post(ctx, h.db.Pool, h.gh, token, fullName, projectID, issueNumber, body)
+	if err != nil {
+		slog.Warn("failed to post bot comment on GitHub",
+			"project_id", projectID.String(),
+			"issue_number", issueNumber,
+			"github_full_name", fullName,
+			"error", err,
+		)
+		return fiber.StatusBadGateway, fiber.Map{"error": "github_comment_create_failed"}
+	}
+	if coalesced {
+		// Over the issue's bot comment limit: the message shows in the status comment instead.
+		h.refreshStatusComment(ctx, projectID, issueNumber)
+		return fiber.StatusAccepted, fiber.Map{"ok": true, "coalesced": true}
+	}
+
+	return fiber.StatusOK, fiber.Map{
+		"ok": true,
+		"comment": fiber.Map{
+			"id":         ghComment.ID,
+			"body":       ghComment.Body,
+			"user":       fiber.Map{"login": ghComment.User.Login},
+			"created_at": ghComment.CreatedAt,
+			"updated_at": ghComment.UpdatedAt,
+		},
 	}
 }
 
@@ -283,7 +489,7 @@ func (h *IssueApplicationsHandler) Withdraw() fiber.Handler {
 		if h.db == nil || h.db.Pool == nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
 		}
-		if strings.TrimSpace(h.cfg.TokenEncKeyB64) == "" {
+		if strings.TrimSpace(h.cfg.TokenEncKey()) == "" {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "token_encryption_not_configured"})
 		}
 
@@ -306,11 +512,15 @@ func (h *IssueApplicationsHandler) Withdraw() fiber.Handler {
 		if err := c.BodyParser(&req); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
 		}
+		// Applications to native tasks have no comment to delete.
+		if tasks.IsNative(issueNumber) {
+			return h.withdrawTaskApplication(c, projectID, issueNumber, userID)
+		}
 		if req.CommentID <= 0 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "comment_id_required"})
 		}
 
-		linked, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+		linked, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
 		}
@@ -354,7 +564,7 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment_not_found"})
 		}
 
-		gh := github.NewClient()
+		gh := h.gh
 		if err := gh.DeleteIssueComment(c.Context(), linked.AccessToken, fullName, req.CommentID); err != nil {
 			var ghErr *github.GitHubAPIError
 			if errors.As(err, &ghErr) {
@@ -383,15 +593,31 @@ last_seen_at = now()
 WHERE project_id = $1 AND number = $2
 `, projectID, issueNumber, req.CommentID)
 
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE issue_applications SET status = 'withdrawn', updated_at = now()
+WHERE project_id = $1 AND github_comment_id = $2
+`, projectID, req.CommentID)
+
+		h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
+		return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true}))
 	}
 }
 
 type assignRequest struct {
 	Assignee string `json:"assignee"`
+	// Force assigns someone who hasn't applied (e.g. a maintainer picking a known contributor).
+	Force bool `json:"force"`
+	// Optional deadline, either absolute or in days from now. Days count only the days the assignee is
+	// available (see package availability), ending at midnight in their time zone.
+	DeadlineAt   *time.Time `json:"deadline_at"`
+	DeadlineDays int        `json:"deadline_days"`
+	// Undoable holds the assignment for UndoWindow before it reaches GitHub.
+	Undoable bool `json:"undoable"`
 }
 
 // Assign adds the applicant as assignee on GitHub and posts a congratulations bot comment. Maintainer only.
+// With "undoable" it answers 202 and runs after UndoWindow unless cancelled.
 func (h *IssueApplicationsHandler) Assign() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -425,77 +651,216 @@ func (h *IssueApplicationsHandler) Assign() fiber.Handler {
 		if req.Assignee == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "assignee_required"})
 		}
+		deadline := req.DeadlineAt
+		if req.DeadlineDays < 0 || (deadline != nil && !deadline.After(time.Now())) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_deadline"})
+		}
+		if req.Undoable {
+			return h.deferIssueAction(c, issueNumber, "assign", req)
+		}
 
-		var owner uuid.UUID
-		var fullName, installationID string
-		err = h.db.Pool.QueryRow(c.Context(), `
+		status, out := h.assign(c.Context(), userID, role, projectID, issueNumber, req)
+		if status == fiber.StatusBadGateway {
+			return h.queueForRetry(c, projectID, issueNumber, "assign", req, out)
+		}
+		if status == fiber.StatusOK {
+			out = withDryRun(c, out)
+		}
+		return c.Status(status).JSON(out)
+	}
+}
+
+// assign is Assign for one applicant, shared with the bulk actions endpoint. It returns the response
+// status and body.
+func (h *IssueApplicationsHandler) assign(ctx context.Context, userID uuid.UUID, role string, projectID uuid.UUID, issueNumber int, req assignRequest) (status int, body fiber.Map) {
+	deadline := req.DeadlineAt
+
+	var owner uuid.UUID
+	var fullName, installationID string
+	err := h.db.Pool.QueryRow(ctx, `
 SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, '')
 FROM projects
 WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
 `, projectID).Scan(&owner, &fullName, &installationID)
-		if errors.Is(err, pgx.ErrNoRows) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusNotFound, fiber.Map{"error": "project_not_found"}
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "project_lookup_failed"}
+	}
+	if !h.maintainsIssue(ctx, owner, userID, role, projectID, issueNumber) {
+		return fiber.StatusForbidden, fiber.Map{"error": "forbidden"}
+	}
+	if installationID == "" && !tasks.IsNative(issueNumber) {
+		return fiber.StatusBadRequest, fiber.Map{"error": "project_has_no_github_app_installation"}
+	}
+
+	// Only applicants can be assigned unless the maintainer explicitly overrides.
+	if !req.Force {
+		var pending bool
+		var commentsJSON []byte
+		if err := h.db.Pool.QueryRow(ctx, `
+SELECT
+  EXISTS (SELECT 1 FROM issue_applications
+          WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status = 'pending'),
+  COALESCE((SELECT comments FROM github_issues WHERE project_id = $1 AND number = $2), '[]'::jsonb)
+`, projectID, issueNumber, req.Assignee).Scan(&pending, &commentsJSON); err != nil {
+			return fiber.StatusInternalServerError, fiber.Map{"error": "application_lookup_failed"}
 		}
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		if !pending && !hasApplicationComment(commentsJSON, req.Assignee) {
+			return fiber.StatusConflict, fiber.Map{"error": "assignee_not_applicant", "assignee": req.Assignee}
 		}
-		if owner != userID && role != "admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+
+	// A paused contributor's applications are on hold until they are back.
+	if !req.Force {
+		if until, err := pause.UntilForLogin(ctx, h.db.Pool, req.Assignee); err == nil && until != nil {
+			return fiber.StatusConflict, fiber.Map{"error": "assignee_paused", "assignee": req.Assignee, "paused_until": until}
 		}
-		if installationID == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
+	}
+
+	limit, current, err := assignmentLoad(ctx, h.db.Pool, h.cfg.MaxConcurrentAssignments, projectID, req.Assignee)
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "assignment_limit_lookup_failed"}
+	}
+	if limit > 0 && current >= limit {
+		return fiber.StatusConflict, fiber.Map{
+			"error":    "assignment_limit_reached",
+			"assignee": req.Assignee,
+			"limit":    limit,
+			"current":  current,
 		}
+	}
 
-		appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
-		if err != nil {
-			slog.Error("failed to create GitHub App client for assign", "error", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_app_client_failed"})
+	// Block assignment until the assignee has answered every required checklist question.
+	var assigneeUserID uuid.UUID
+	_ = h.db.Pool.QueryRow(ctx, `SELECT user_id FROM github_accounts WHERE LOWER(login) = LOWER($1)`, req.Assignee).Scan(&assigneeUserID)
+	missing, err := missingRequiredAnswers(ctx, h.db.Pool, projectID, issueNumber, assigneeUserID)
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "answers_lookup_failed"}
+	}
+	if len(missing) > 0 {
+		return fiber.StatusConflict, fiber.Map{
+			"error":                "required_answers_missing",
+			"missing_question_ids": uuidStrings(missing),
 		}
-		token, err := appClient.GetInstallationToken(c.Context(), installationID)
-		if err != nil {
-			slog.Warn("failed to get installation token for assign", "project_id", projectID.String(), "error", err)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "installation_token_failed"})
+	}
+
+	if deadline == nil && req.DeadlineDays > 0 {
+		sched := availability.Default()
+		if assigneeUserID != uuid.Nil {
+			if s, _, err := loadAvailability(ctx, h.db.Pool, assigneeUserID); err == nil {
+				sched = s
+			}
 		}
+		d := sched.Deadline(time.Now(), req.DeadlineDays)
+		deadline = &d
+	}
+
+	// Escrow the issue's reward before assigning; it goes back to the budget if the assignment fails.
+	lockedReward, err := rewards.Lock(ctx, h.db.Pool, projectID, issueNumber, req.Assignee)
+	if errors.Is(err, rewards.ErrInsufficientBudget) {
+		return fiber.StatusConflict, fiber.Map{"error": "reward_budget_insufficient"}
+	}
+	if err != nil {
+		slog.Error("failed to lock issue reward", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+		return fiber.StatusInternalServerError, fiber.Map{"error": "reward_lock_failed"}
+	}
+	if lockedReward {
+		defer func() {
+			if status != fiber.StatusOK {
+				if err := rewards.Return(context.WithoutCancel(ctx), h.db.Pool, projectID, issueNumber, "assignment failed"); err != nil {
+					slog.Error("failed to return issue reward", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+				}
+			}
+		}()
+	}
+
+	if tasks.IsNative(issueNumber) {
+		return h.assignTask(ctx, projectID, issueNumber, req.Assignee, assigneeUserID, deadline)
+	}
 
-		gh := github.NewClient()
-		if err := gh.AddIssueAssignees(c.Context(), token, fullName, issueNumber, []string{req.Assignee}); err != nil {
-			slog.Warn("failed to add assignee on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "assignee", req.Assignee, "error", err)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_assign_failed"})
+	appClient, err := h.apps.ForInstallation(ctx, installationID)
+	if err != nil {
+		slog.Error("failed to create GitHub App client for assign", "error", err)
+		return fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		slog.Warn("failed to get installation token for assign", "project_id", projectID.String(), "error", err)
+		return fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
+	}
+
+	gh := h.gh
+	// GitHub accepts the request but silently drops assignees without repo access, so check first.
+	if ok, err := gh.CheckAssignee(ctx, token, fullName, req.Assignee); err == nil && !ok {
+		return fiber.StatusUnprocessableEntity, fiber.Map{
+			"error":    "assignee_not_assignable",
+			"assignee": req.Assignee,
+			"message":  "GitHub does not allow this user to be assigned. They may need to comment on the issue or be added as a collaborator.",
+		}
+	}
+	version := h.issueVersion(ctx, projectID, issueNumber)
+	if err := gh.AddIssueAssignees(ctx, token, fullName, issueNumber, []string{req.Assignee}); err != nil {
+		slog.Warn("failed to add assignee on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "assignee", req.Assignee, "error", err)
+		var ghErr *github.GitHubAPIError
+		if errors.As(err, &ghErr) {
+			return fiber.StatusBadGateway, fiber.Map{
+				"error":          "github_assign_failed",
+				"github_status":  ghErr.StatusCode,
+				"github_message": ghErr.Message,
+			}
 		}
+		return fiber.StatusBadGateway, fiber.Map{"error": "github_assign_failed"}
+	}
 
-		assigneesJSON, _ := json.Marshal([]map[string]string{{"login": req.Assignee}})
-		_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues SET assignees = $3, last_seen_at = now()
+	assigneesJSON, _ := json.Marshal([]map[string]string{{"login": req.Assignee}})
+	h.setIssueAssignees(ctx, token, fullName, projectID, issueNumber, version, assigneesJSON)
+	_ = h.db.Pool.QueryRow(ctx, `
+UPDATE github_issues SET deadline_at = COALESCE($3, deadline_at)
 WHERE project_id = $1 AND number = $2
-`, projectID, issueNumber, assigneesJSON)
+RETURNING deadline_at
+`, projectID, issueNumber, deadline).Scan(&deadline)
+
+	var githubIssueID int64
+	_ = h.db.Pool.QueryRow(ctx, `SELECT github_issue_id FROM github_issues WHERE project_id = $1 AND number = $2`, projectID, issueNumber).Scan(&githubIssueID)
+	base := strings.TrimSpace(strings.TrimRight(h.cfg.FrontendBaseURL, "/"))
+	manageURL := base + "/dashboard?tab=browse&project=" + projectID.String() + "&issue=" + fmt.Sprintf("%d", githubIssueID)
+	if base == "" || !strings.HasPrefix(base, "http") {
+		manageURL = "/dashboard?tab=browse&project=" + projectID.String() + "&issue=" + fmt.Sprintf("%d", githubIssueID)
+	}
+	botBody := botmessages.Render(ctx, h.db.Pool, projectID, botmessages.Assigned,
+		map[string]string{"assignee": req.Assignee, "manage_url": manageURL})
+	if deadline != nil {
+		botBody += "\n\n" + botmessages.Render(ctx, h.db.Pool, projectID, botmessages.AssignedDeadline,
+			map[string]string{"deadline": deadline.UTC().Format("Jan 2, 2006 15:04 MST")})
+	}
 
-		var githubIssueID int64
-		_ = h.db.Pool.QueryRow(c.Context(), `SELECT github_issue_id FROM github_issues WHERE project_id = $1 AND number = $2`, projectID, issueNumber).Scan(&githubIssueID)
-		base := strings.TrimSpace(strings.TrimRight(h.cfg.FrontendBaseURL, "/"))
-		manageURL := base + "/dashboard?tab=browse&project=" + projectID.String() + "&issue=" + fmt.Sprintf("%d", githubIssueID)
-		if base == "" || !strings.HasPrefix(base, "http") {
-			manageURL = "/dashboard?tab=browse&project=" + projectID.String() + "&issue=" + fmt.Sprintf("%d", githubIssueID)
-		}
-		botBody := fmt.Sprintf("Congratulations, **@%s**! 🎉 Your application was accepted by the repo's maintainers.\n\n"+
-			"Please resolve the issue such that the repo's maintainers have enough time to review your contribution.\n\n"+
-			"> ⚠️ **Warning:** When opening a PR, please link it to this issue to ensure it gets tracked accurately.\n\n"+
-			"**Repo maintainers:** You can manage this issue, including adjusting complexity and points, [here](%s).",
-			req.Assignee, manageURL)
-
-		ghComment, err := gh.CreateIssueComment(c.Context(), token, fullName, issueNumber, botBody)
-		if err != nil {
-			slog.Warn("assign: bot congratulations comment failed", "error", err)
-		} else {
-			commentJSON, _ := json.Marshal(ghComment)
-			_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
-  comments_count = COALESCE(comments_count, 0) + 1, updated_at_github = $4, last_seen_at = now()
-WHERE project_id = $1 AND number = $2
-`, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
-		}
+	if _, _, err := botcomments.Post(ctx, h.db.Pool, gh, token, fullName, projectID, issueNumber, botBody); err != nil {
+		slog.Warn("assign: bot congratulations comment failed", "error", err)
+	}
 
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	// Queue any templates the maintainer configured to fire after assignment (e.g. reminders).
+	assignVars, _ := json.Marshal(map[string]string{"applicant": req.Assignee})
+	if _, err := h.db.Pool.Exec(ctx, `
+INSERT INTO scheduled_bot_comments (project_id, issue_number, template_id, body, variables, send_at, created_by_user_id)
+SELECT project_id, $2, id, body, $3::jsonb, now() + make_interval(mins => delay_minutes), $4
+FROM bot_comment_templates
+WHERE project_id = $1 AND trigger_event = 'assigned'
+`, projectID, issueNumber, assignVars, userID); err != nil {
+		slog.Warn("assign: failed to schedule triggered bot comments", "project_id", projectID.String(), "error", err)
 	}
+
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'assigned', decided_at = now(), updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status = 'pending'
+`, projectID, issueNumber, req.Assignee)
+	waitlist.Promote(ctx, h.db.Pool, projectID, issueNumber, req.Assignee)
+
+	checkruns.MarkIssueChanged(ctx, h.db.Pool, projectID, issueNumber)
+	h.refreshStatusComment(ctx, projectID, issueNumber)
+
+	return fiber.StatusOK, fiber.Map{"ok": true, "deadline_at": deadline}
 }
 
 // Unassign removes the current assignee(s) from the GitHub issue and posts a bot comment. Maintainer only.
@@ -504,9 +869,6 @@ func (h *IssueApplicationsHandler) Unassign() fiber.Handler {
 		if h.db == nil || h.db.Pool == nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
 		}
-		if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
-			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
-		}
 
 		projectID, err := uuid.Parse(c.Params("id"))
 		if err != nil {
@@ -516,6 +878,10 @@ func (h *IssueApplicationsHandler) Unassign() fiber.Handler {
 		if err != nil || issueNumber <= 0 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
 		}
+		native := tasks.IsNative(issueNumber)
+		if !native && (strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "") {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+		}
 
 		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
 		userID, err := uuid.Parse(userIDStr)
@@ -539,10 +905,10 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
 		}
-		if owner != userID && role != "admin" {
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
 		}
-		if installationID == "" {
+		if installationID == "" && !native {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
 		}
 
@@ -562,8 +928,12 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number
 		if len(logins) == 0 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issue_has_no_assignees"})
 		}
+		if native {
+			h.unassignTask(c.Context(), projectID, issueNumber, logins)
+			return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true}))
+		}
 
-		appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
+		appClient, err := h.apps.ForInstallation(c.Context(), installationID)
 		if err != nil {
 			slog.Error("failed to create GitHub App client for unassign", "error", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_app_client_failed"})
@@ -574,44 +944,60 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number
 			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "installation_token_failed"})
 		}
 
-		gh := github.NewClient()
+		gh := h.gh
+		version := h.issueVersion(c.Context(), projectID, issueNumber)
 		if err := gh.RemoveIssueAssignees(c.Context(), token, fullName, issueNumber, logins); err != nil {
 			slog.Warn("failed to remove assignees on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
 			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_unassign_failed"})
 		}
 
+		h.setIssueAssignees(c.Context(), token, fullName, projectID, issueNumber, version, []byte("[]"))
 		_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues SET assignees = '[]'::jsonb, last_seen_at = now()
-WHERE project_id = $1 AND number = $2
+UPDATE github_issues SET deadline_at = NULL WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber)
+		if err := rewards.Return(c.Context(), h.db.Pool, projectID, issueNumber, "unassigned"); err != nil {
+			slog.Error("failed to return issue reward", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+		}
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE assignment_extension_requests SET status = 'denied', decided_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'pending'
 `, projectID, issueNumber)
 
 		who := "@" + logins[0]
 		if len(logins) > 1 {
 			who = "@" + strings.Join(logins, ", @")
 		}
-		botBody := fmt.Sprintf("%s has been unassigned from this issue. The maintainer may assign another contributor.", who)
+		botBody := botmessages.Render(c.Context(), h.db.Pool, projectID, botmessages.Unassigned, map[string]string{"assignees": who})
+		if next, err := waitlist.NotifyNext(c.Context(), h.db.Pool, projectID, issueNumber); err != nil {
+			slog.Warn("unassign: failed to notify waitlist", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+		} else if next != "" {
+			botBody += "\n\n" + botmessages.Render(c.Context(), h.db.Pool, projectID, botmessages.WaitlistNext, map[string]string{"next": next})
+		}
 
-		ghComment, err := gh.CreateIssueComment(c.Context(), token, fullName, issueNumber, botBody)
-		if err != nil {
+		if _, _, err := botcomments.Post(c.Context(), h.db.Pool, gh, token, fullName, projectID, issueNumber, botBody); err != nil {
 			slog.Warn("unassign: bot comment failed", "error", err)
-		} else {
-			commentJSON, _ := json.Marshal(ghComment)
-			_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
-  comments_count = COALESCE(comments_count, 0) + 1, updated_at_github = $4, last_seen_at = now()
-WHERE project_id = $1 AND number = $2
-`, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
 		}
 
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE issue_applications SET status = 'unassigned', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'assigned' AND LOWER(github_login) = ANY($3)
+`, projectID, issueNumber, lowerAll(logins))
+
+		checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+		h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
+		return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true}))
 	}
 }
 
 type rejectRequest struct {
 	Assignee string `json:"assignee"`
+	// Undoable holds the rejection for UndoWindow before it reaches GitHub.
+	Undoable bool `json:"undoable"`
 }
 
 // Reject posts a bot comment that the applicant's application was not accepted. Maintainer only.
+// With "undoable" it answers 202 and runs after UndoWindow unless cancelled.
 func (h *IssueApplicationsHandler) Reject() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -645,53 +1031,165 @@ func (h *IssueApplicationsHandler) Reject() fiber.Handler {
 		if req.Assignee == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "assignee_required"})
 		}
-
-		var owner uuid.UUID
-		var fullName, installationID string
-		err = h.db.Pool.QueryRow(c.Context(), `
-SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, '')
-FROM projects
-WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
-`, projectID).Scan(&owner, &fullName, &installationID)
-		if errors.Is(err, pgx.ErrNoRows) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		if req.Undoable {
+			return h.deferIssueAction(c, issueNumber, "reject", req)
 		}
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+
+		status, out := h.reject(c.Context(), userID, role, projectID, issueNumber, req.Assignee)
+		if status == fiber.StatusBadGateway {
+			return h.queueForRetry(c, projectID, issueNumber, "reject", req, out)
 		}
-		if owner != userID && role != "admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		if status == fiber.StatusOK {
+			out = withDryRun(c, out)
 		}
+		return c.Status(status).JSON(out)
+	}
+}
+
+// reject is Reject for one applicant, shared with the bulk actions endpoint. Private applications (see
+// SetApplicationVisibility) are rejected without a GitHub comment, which would name the applicant.
+func (h *IssueApplicationsHandler) reject(ctx context.Context, userID uuid.UUID, role string, projectID uuid.UUID, issueNumber int, login string) (int, fiber.Map) {
+	var owner uuid.UUID
+	var fullName, installationID, visibility string
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, ''), application_visibility
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner, &fullName, &installationID, &visibility)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusNotFound, fiber.Map{"error": "project_not_found"}
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "project_lookup_failed"}
+	}
+	if !h.maintainsIssue(ctx, owner, userID, role, projectID, issueNumber) {
+		return fiber.StatusForbidden, fiber.Map{"error": "forbidden"}
+	}
+
+	if visibility != "private" && !tasks.IsNative(issueNumber) {
 		if installationID == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
+			return fiber.StatusBadRequest, fiber.Map{"error": "project_has_no_github_app_installation"}
 		}
-
-		appClient, err := github.NewGitHubAppClient(h.cfg.GitHubAppID, h.cfg.GitHubAppPrivateKey)
+		appClient, err := h.apps.ForInstallation(ctx, installationID)
 		if err != nil {
 			slog.Error("failed to create GitHub App client for reject", "error", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_app_client_failed"})
+			return fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
 		}
-		token, err := appClient.GetInstallationToken(c.Context(), installationID)
+		token, err := appClient.GetInstallationToken(ctx, installationID)
 		if err != nil {
 			slog.Warn("failed to get installation token for reject", "project_id", projectID.String(), "error", err)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "installation_token_failed"})
+			return fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
 		}
 
-		botBody := fmt.Sprintf("@%s your application was not accepted for this issue. The maintainer may assign another contributor.", req.Assignee)
-		gh := github.NewClient()
-		ghComment, err := gh.CreateIssueComment(c.Context(), token, fullName, issueNumber, botBody)
-		if err != nil {
+		botBody := botmessages.Render(ctx, h.db.Pool, projectID, botmessages.Rejected, map[string]string{"applicant": login})
+		if _, _, err := botcomments.Post(ctx, h.db.Pool, h.gh, token, fullName, projectID, issueNumber, botBody); err != nil {
 			slog.Warn("reject: bot comment failed", "error", err)
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_comment_create_failed"})
+			return fiber.StatusBadGateway, fiber.Map{"error": "github_comment_create_failed"}
 		}
-		commentJSON, _ := json.Marshal(ghComment)
-		_, _ = h.db.Pool.Exec(c.Context(), `
-UPDATE github_issues SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
-  comments_count = COALESCE(comments_count, 0) + 1, updated_at_github = $4, last_seen_at = now()
-WHERE project_id = $1 AND number = $2
-`, projectID, issueNumber, commentJSON, ghComment.UpdatedAt)
+	}
+
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'rejected', decided_at = now(), updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status = 'pending'
+`, projectID, issueNumber, login)
+
+	h.refreshStatusComment(ctx, projectID, issueNumber)
 
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	return fiber.StatusOK, fiber.Map{"ok": true}
+}
+
+// assignmentLoad returns the concurrent-assignment cap that applies to a project (its ecosystem's,
+// else platformLimit; 0 = unlimited) and how many open issues login is assigned to within that scope.
+func assignmentLoad(ctx context.Context, pool *pgxpool.Pool, platformLimit int, projectID uuid.UUID, login string) (int, int, error) {
+	var ecosystemID *uuid.UUID
+	var ecosystemLimit *int
+	if err := pool.QueryRow(ctx, `
+SELECT p.ecosystem_id, e.max_concurrent_assignments
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, projectID).Scan(&ecosystemID, &ecosystemLimit); err != nil {
+		return 0, 0, err
+	}
+	limit := platformLimit
+	if ecosystemLimit != nil {
+		limit = *ecosystemLimit
+	} else {
+		ecosystemID = nil // platform cap counts assignments across all projects
 	}
+	if limit <= 0 {
+		return 0, 0, nil
+	}
+
+	var current int
+	err := pool.QueryRow(ctx, `
+SELECT COUNT(*)
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE gi.state = 'open' AND p.deleted_at IS NULL
+  AND ($2::uuid IS NULL OR p.ecosystem_id = $2)
+  AND EXISTS (
+    SELECT 1 FROM jsonb_array_elements(COALESCE(gi.assignees, '[]'::jsonb)) a
+    WHERE LOWER(a->>'login') = LOWER($1)
+  )
+`, login, ecosystemID).Scan(&current)
+	return limit, current, err
 }
 
+// applicationCommentBody renders the GitHub comment posted for an application (Drips Wave–style:
+// header, the message as a blockquote, and maintainer instructions with links).
+func (h *IssueApplicationsHandler) applicationCommentBody(projectID uuid.UUID, githubIssueID int64, fullName string, issueNumber int, issueURL string, login string, message string) string {
+	// Contributor text is quoted verbatim otherwise: no pings, no raw HTML, no runaway code fences.
+	quotedMsg := "_The application message is visible to maintainers on Grainlify._"
+	if message != "" {
+		message = mdsanitize.Sanitize(message, mdsanitize.Options{EscapeHTML: true})
+		quotedLines := strings.Split(message, "\n")
+		for i := range quotedLines {
+			quotedLines[i] = "> " + quotedLines[i]
+		}
+		quotedMsg = strings.Join(quotedLines, "\n")
+	}
+	// Deep link to this issue in the dashboard so "review their application" opens the exact issue.
+	base := strings.TrimSpace(strings.TrimRight(h.cfg.FrontendBaseURL, "/"))
+	reviewURL := fmt.Sprintf("%s/dashboard?tab=browse&project=%s&issue=%d", base, projectID.String(), githubIssueID)
+	if base == "" || !strings.HasPrefix(base, "http") {
+		// Fallback: relative path only if FrontendBaseURL not configured (link will use current origin)
+		reviewURL = fmt.Sprintf("/dashboard?tab=browse&project=%s&issue=%d", projectID.String(), githubIssueID)
+	}
+	if issueURL == "" {
+		issueURL = fmt.Sprintf("https://github.com/%s/issues/%d", fullName, issueNumber)
+	}
+	return fmt.Sprintf(botcomments.ApplicationMarker+"\n\n**@%s has applied to work on this issue as part of the Grainlify program.**\n\n%s\n\n---\n\n**Repo Maintainers:** To accept this application, [review their application](%s) or [assign @%s](%s) to this issue.",
+		login, quotedMsg, reviewURL, login, issueURL)
+}
+
+// applicationCommentMessage is the part of an application message that goes into its GitHub comment: all
+// of it for public applications, none (just a pointer to Grainlify) when the project shows both.
+func applicationCommentMessage(visibility, message string) string {
+	if visibility == "both" {
+		return ""
+	}
+	return message
+}
+
+// hasApplicationComment reports whether login has an application comment among an issue's stored comments.
+func hasApplicationComment(commentsJSON []byte, login string) bool {
+	var comments []github.IssueComment
+	if json.Unmarshal(commentsJSON, &comments) != nil {
+		return false
+	}
+	for _, com := range comments {
+		if strings.EqualFold(strings.TrimSpace(com.User.Login), strings.TrimSpace(login)) && strings.Contains(com.Body, botcomments.ApplicationMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+func lowerAll(in []string) []string {
+	out := make([]string, len(in))
+	for i, s := range in {
+		out[i] = strings.ToLower(s)
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+)
+
+// maxBulkApplicationActions caps one bulk request; each accept or reject makes several GitHub calls.
+const maxBulkApplicationActions = 100
+
+type bulkApplicationItem struct {
+	ApplicationID string `json:"application_id"`
+	// Action is accept, reject, mark_reviewed or unmark_reviewed; defaults to the request's action.
+	Action string `json:"action"`
+	// Accept options, as for POST .../assign.
+	Force        bool       `json:"force"`
+	DeadlineAt   *time.Time `json:"deadline_at"`
+	DeadlineDays int        `json:"deadline_days"`
+}
+
+type bulkApplicationsRequest struct {
+	Action string `json:"action"`
+	// ApplicationIDs is shorthand for items that only carry an id.
+	ApplicationIDs []string              `json:"application_ids"`
+	Items          []bulkApplicationItem `json:"items"`
+}
+
+// BulkApplications serves POST /applications/bulk: accepts (assigns), rejects or marks as reviewed many
+// applications, across issues and projects, in one request. Items run in order and independently; each
+// gets its own result with the status and body the single-item endpoint would have returned.
+func (h *IssueApplicationsHandler) BulkApplications() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req bulkApplicationsRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		items := req.Items
+		for _, id := range req.ApplicationIDs {
+			items = append(items, bulkApplicationItem{ApplicationID: id})
+		}
+		if len(items) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "items_required"})
+		}
+		if len(items) > maxBulkApplicationActions {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "too_many_items", "max": maxBulkApplicationActions})
+		}
+
+		accepted := map[string]bool{} // project/issue pairs accepted earlier in this request
+		results := make([]fiber.Map, 0, len(items))
+		succeeded := 0
+		for i, item := range items {
+			if item.Action == "" {
+				item.Action = req.Action
+			}
+			status, out := h.bulkApplicationAction(c.Context(), userID, role, item, accepted)
+			out["index"] = i
+			out["application_id"] = item.ApplicationID
+			out["action"] = item.Action
+			out["status"] = status
+			if status == fiber.StatusOK {
+				succeeded++
+			}
+			results = append(results, out)
+		}
+		return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(items) - succeeded,
+		}))
+	}
+}
+
+func (h *IssueApplicationsHandler) bulkApplicationAction(ctx context.Context, userID uuid.UUID, role string, item bulkApplicationItem, accepted map[string]bool) (int, fiber.Map) {
+	switch item.Action {
+	case "accept", "reject", "mark_reviewed", "unmark_reviewed":
+	default:
+		return fiber.StatusBadRequest, fiber.Map{"error": "invalid_action"}
+	}
+	appID, err := uuid.Parse(strings.TrimSpace(item.ApplicationID))
+	if err != nil {
+		return fiber.StatusBadRequest, fiber.Map{"error": "invalid_application_id"}
+	}
+
+	var projectID, owner uuid.UUID
+	var issueNumber int
+	var login, appStatus string
+	err = h.db.Pool.QueryRow(ctx, `
+SELECT a.project_id, a.issue_number, a.github_login, a.status, p.owner_user_id
+FROM issue_applications a
+JOIN projects p ON p.id = a.project_id AND p.deleted_at IS NULL
+WHERE a.id = $1
+`, appID).Scan(&projectID, &issueNumber, &login, &appStatus, &owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusNotFound, fiber.Map{"error": "application_not_found"}
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "application_lookup_failed"}
+	}
+	if owner != userID && role != "admin" {
+		return fiber.StatusForbidden, fiber.Map{"error": "forbidden"}
+	}
+
+	switch item.Action {
+	case "mark_reviewed", "unmark_reviewed":
+		reviewed := item.Action == "mark_reviewed"
+		if _, err := h.db.Pool.Exec(ctx, `
+UPDATE issue_applications
+SET reviewed_at = CASE WHEN $2 THEN COALESCE(reviewed_at, now()) END,
+    reviewed_by_user_id = CASE WHEN $2 THEN COALESCE(reviewed_by_user_id, $3) END
+WHERE id = $1
+`, appID, reviewed, userID); err != nil {
+			return fiber.StatusInternalServerError, fiber.Map{"error": "application_update_failed"}
+		}
+		return fiber.StatusOK, fiber.Map{"ok": true}
+	}
+
+	if appStatus != "pending" {
+		return fiber.StatusConflict, fiber.Map{"error": "application_not_pending", "application_status": appStatus}
+	}
+	if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+		return fiber.StatusServiceUnavailable, fiber.Map{"error": "github_app_not_configured"}
+	}
+	if item.Action == "reject" {
+		return h.reject(ctx, userID, role, projectID, issueNumber, login)
+	}
+
+	key := fmt.Sprintf("%s#%d", projectID, issueNumber)
+	if accepted[key] {
+		return fiber.StatusConflict, fiber.Map{"error": "issue_already_accepted_in_request"}
+	}
+	if item.DeadlineDays < 0 || (item.DeadlineAt != nil && !item.DeadlineAt.After(time.Now())) {
+		return fiber.StatusBadRequest, fiber.Map{"error": "invalid_deadline"}
+	}
+	status, out := h.assign(ctx, userID, role, projectID, issueNumber, assignRequest{
+		Assignee:     login,
+		Force:        item.Force,
+		DeadlineAt:   item.DeadlineAt,
+		DeadlineDays: item.DeadlineDays,
+	})
+	if status == fiber.StatusOK {
+		accepted[key] = true
+	}
+	return status, out
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/botcomments"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/moderation"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// botCommentTarget is the resolved context for editing or deleting a bot comment.
+type botCommentTarget struct {
+	projectID   uuid.UUID
+	issueNumber int
+	commentID   int64
+	fullName    string
+	token       string
+}
+
+// resolveBotCommentTarget checks maintainer access, verifies the comment is a bot comment stored
+// on the issue, and returns an installation token for the project. On failure it returns a status and code.
+func (h *IssueApplicationsHandler) resolveBotCommentTarget(c *fiber.Ctx, logAction string) (*botCommentTarget, int, string) {
+	if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+		return nil, fiber.StatusServiceUnavailable, "github_app_not_configured"
+	}
+
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return nil, fiber.StatusBadRequest, "invalid_issue_number"
+	}
+	commentID, err := strconv.ParseInt(c.Params("commentId"), 10, 64)
+	if err != nil || commentID <= 0 {
+		return nil, fiber.StatusBadRequest, "invalid_comment_id"
+	}
+
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	var fullName, installationID string
+	var commentsJSON []byte
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.owner_user_id, p.github_full_name, COALESCE(p.github_app_installation_id, ''), COALESCE(gi.comments, '[]'::jsonb)
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND gi.number = $2
+`, projectID, issueNumber).Scan(&owner, &fullName, &installationID, &commentsJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fiber.StatusNotFound, "issue_not_found"
+	}
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+		return nil, fiber.StatusForbidden, "forbidden"
+	}
+	if installationID == "" {
+		return nil, fiber.StatusBadRequest, "project_has_no_github_app_installation"
+	}
+
+	// Only comments authored by a GitHub App ("<slug>[bot]") can be changed through these endpoints.
+	var comments []github.IssueComment
+	if err := json.Unmarshal(commentsJSON, &comments); err != nil {
+		return nil, fiber.StatusInternalServerError, "comments_parse_failed"
+	}
+	found := false
+	for _, com := range comments {
+		if com.ID == commentID {
+			if !strings.HasSuffix(strings.ToLower(com.User.Login), "[bot]") {
+				return nil, fiber.StatusForbidden, "not_a_bot_comment"
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fiber.StatusNotFound, "comment_not_found"
+	}
+
+	appClient, err := h.apps.ForInstallation(c.Context(), installationID)
+	if err != nil {
+		slog.Error("failed to create GitHub App client for "+logAction, "error", err)
+		return nil, fiber.StatusInternalServerError, "github_app_client_failed"
+	}
+	token, err := appClient.GetInstallationToken(c.Context(), installationID)
+	if err != nil {
+		slog.Warn("failed to get installation token for "+logAction, "project_id", projectID.String(), "error", err)
+		return nil, fiber.StatusBadGateway, "installation_token_failed"
+	}
+
+	return &botCommentTarget{
+		projectID:   projectID,
+		issueNumber: issueNumber,
+		commentID:   commentID,
+		fullName:    fullName,
+		token:       token,
+	}, 0, ""
+}
+
+// UpdateBotComment edits a bot comment in place instead of posting a new one. Maintainer only.
+func (h *IssueApplicationsHandler) UpdateBotComment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		var req botCommentRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Body = strings.TrimSpace(req.Body)
+		var v validate.Validator
+		if v.Required("body", req.Body) {
+			v.MaxLen("body", req.Body, maxBotCommentLen)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+		if out := moderate(c.Context(), h.cfg, moderation.KindBotComment, "body", req.Body); out != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(out)
+		}
+
+		t, status, code := h.resolveBotCommentTarget(c, "bot comment update")
+		if t == nil {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		req.Body = botcomments.SanitizeBody(req.Body, nil)
+
+		gh := h.gh
+		ghComment, err := gh.UpdateIssueComment(c.Context(), t.token, t.fullName, t.commentID, req.Body)
+		if err != nil {
+			var ghErr *github.GitHubAPIError
+			if errors.As(err, &ghErr) && ghErr.StatusCode == 404 {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment_not_found"})
+			}
+			slog.Warn("failed to update bot comment on GitHub",
+				"project_id", t.projectID.String(), "issue_number", t.issueNumber, "comment_id", t.commentID, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_comment_update_failed"})
+		}
+
+		h.replaceStoredIssueComment(c.Context(), t.projectID, t.issueNumber, ghComment)
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"ok": true,
+			"comment": fiber.Map{
+				"id":         ghComment.ID,
+				"body":       ghComment.Body,
+				"user":       fiber.Map{"login": ghComment.User.Login},
+				"created_at": ghComment.CreatedAt,
+				"updated_at": ghComment.UpdatedAt,
+			},
+		})
+	}
+}
+
+// DeleteBotComment removes a bot comment from the GitHub issue. Maintainer only.
+func (h *IssueApplicationsHandler) DeleteBotComment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		t, status, code := h.resolveBotCommentTarget(c, "bot comment delete")
+		if t == nil {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		gh := h.gh
+		if err := gh.DeleteIssueComment(c.Context(), t.token, t.fullName, t.commentID); err != nil {
+			var ghErr *github.GitHubAPIError
+			if !errors.As(err, &ghErr) || ghErr.StatusCode != 404 {
+				slog.Warn("failed to delete bot comment on GitHub",
+					"project_id", t.projectID.String(), "issue_number", t.issueNumber, "comment_id", t.commentID, "error", err)
+				return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_comment_delete_failed"})
+			}
+			// Already gone on GitHub: still drop it from our copy below.
+		}
+
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues
+SET comments = (
+  SELECT COALESCE(jsonb_agg(elem), '[]'::jsonb)
+  FROM jsonb_array_elements(COALESCE(comments, '[]'::jsonb)) AS elem
+  WHERE (elem->>'id')::bigint != $3
+),
+comments_count = GREATEST(0, COALESCE(comments_count, 0) - 1),
+last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, t.projectID, t.issueNumber, t.commentID)
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// replaceStoredIssueComment swaps the stored copy of a comment (matched by id) with the updated one.
+func (h *IssueApplicationsHandler) replaceStoredIssueComment(ctx context.Context, projectID uuid.UUID, issueNumber int, com github.IssueComment) {
+	commentJSON, _ := json.Marshal(com)
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE github_issues
+SET comments = (
+  SELECT COALESCE(jsonb_agg(CASE WHEN (elem->>'id')::bigint = $3 THEN $4::jsonb ELSE elem END), '[]'::jsonb)
+  FROM jsonb_array_elements(COALESCE(comments, '[]'::jsonb)) AS elem
+),
+updated_at_github = $5,
+last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, com.ID, commentJSON, com.UpdatedAt)
+}
+
+// refreshStatusComment updates the bot's persistent status comment after a state change.
+// Best-effort: the triggering action has already succeeded, so failures are only logged.
+func (h *IssueApplicationsHandler) refreshStatusComment(ctx context.Context, projectID uuid.UUID, issueNumber int) {
+	if err := botcomments.RefreshStatusComment(ctx, h.cfg, h.db.Pool, projectID, issueNumber); err != nil {
+		slog.Warn("failed to refresh issue status comment",
+			"project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+	}
+}
+
+type issueStatusRequest struct {
+	Points        *int       `json:"points"`
+	DeadlineAt    *time.Time `json:"deadline_at"`
+	ClearDeadline bool       `json:"clear_deadline"`
+}
+
+// UpdateIssueStatus sets points/deadline on an issue and refreshes its status comment. Maintainer only.
+func (h *IssueApplicationsHandler) UpdateIssueStatus() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req issueStatusRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		if req.Points != nil && *req.Points < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_points"})
+		}
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues
+SET points = COALESCE($3, points),
+    deadline_at = CASE WHEN $5 THEN NULL ELSE COALESCE($4, deadline_at) END
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, req.Points, req.DeadlineAt, req.ClearDeadline)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+
+		checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+		h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/completions"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// maxCreditShares bounds how many contributors an issue's credit can be split between.
+const maxCreditShares = 20
+
+type creditRequest struct {
+	Shares []completions.Share `json:"shares"`
+}
+
+// Credit serves GET /projects/:id/issues/:number/credit: how the issue's points are split between
+// contributors, what the completions job last applied, and a suggested even split between the authors of
+// the merged pull requests that close the issue. Maintainer only.
+func (h *IssueApplicationsHandler) Credit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, issueNumber, status, code := h.estimateTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		shares, err := h.creditShares(c.Context(), projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "credit_lookup_failed"})
+		}
+		credits := []completions.Credit{}
+		var creditsJSON []byte
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT credits FROM issue_completions WHERE project_id = $1 AND issue_number = $2
+`, projectID, issueNumber).Scan(&creditsJSON)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "credit_lookup_failed"})
+		}
+		if len(creditsJSON) > 0 {
+			_ = json.Unmarshal(creditsJSON, &credits)
+		}
+		authors, err := h.linkedAuthors(c.Context(), projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "credit_lookup_failed"})
+		}
+		suggested := completions.EvenShares(authors)
+		if suggested == nil {
+			suggested = []completions.Share{}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"issue_number": issueNumber,
+			"shares":       shares,
+			"credits":      credits,
+			"suggested":    suggested,
+		})
+	}
+}
+
+// SetCredit serves PUT /projects/:id/issues/:number/credit: replaces the split of the issue's points.
+// Shares name GitHub logins and add up to 100 percent; an empty list gives all the points back to the
+// assignee. Only points are split: a money reward is still paid to the assignee. The completions job
+// applies the split on its next run, and contributors in it with an account are notified.
+func (h *IssueApplicationsHandler) SetCredit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, issueNumber, status, code := h.estimateTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req creditRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		var v validate.Validator
+		if v.MaxItems("shares", len(req.Shares), maxCreditShares) {
+			for i := range req.Shares {
+				req.Shares[i].Login = strings.TrimPrefix(strings.TrimSpace(req.Shares[i].Login), "@")
+				v.Required(validate.Index("shares", i, "login"), req.Shares[i].Login)
+				v.Range(validate.Index("shares", i, "percent"), req.Shares[i].Percent, 1, 100)
+			}
+			if v.Err() == nil {
+				if err := completions.ValidateShares(req.Shares); err != nil {
+					v.Fail("shares", validate.Invalid, err.Error())
+				}
+			}
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		var exists bool
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS (SELECT 1 FROM github_issues WHERE project_id = $1 AND number = $2)
+`, projectID, issueNumber).Scan(&exists); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+
+		actor := contentActor(c)
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(c.Context(), `
+DELETE FROM issue_credit_splits WHERE project_id = $1 AND issue_number = $2
+`, projectID, issueNumber); err != nil {
+				return err
+			}
+			for _, s := range req.Shares {
+				if _, err := tx.Exec(c.Context(), `
+INSERT INTO issue_credit_splits (project_id, issue_number, login, percent, set_by_user_id)
+VALUES ($1, $2, $3, $4, $5)
+`, projectID, issueNumber, s.Login, s.Percent, actor); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "credit_update_failed"})
+		}
+
+		for _, s := range req.Shares {
+			var userID uuid.UUID
+			if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT user_id FROM github_accounts WHERE LOWER(login) = LOWER($1)
+`, s.Login).Scan(&userID); err != nil {
+				continue
+			}
+			notifyUser(c.Context(), h.db.Pool, userID, "credit_split", fiber.Map{
+				"project_id": projectID.String(), "issue_number": issueNumber, "percent": s.Percent,
+			})
+		}
+		shares, err := h.creditShares(c.Context(), projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "credit_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "shares": shares})
+	}
+}
+
+// creditShares loads an issue's split, largest share first.
+func (h *IssueApplicationsHandler) creditShares(ctx context.Context, projectID uuid.UUID, issueNumber int) ([]completions.Share, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT login, percent FROM issue_credit_splits
+WHERE project_id = $1 AND issue_number = $2
+ORDER BY percent DESC, LOWER(login)
+`, projectID, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []completions.Share{}
+	for rows.Next() {
+		var s completions.Share
+		if err := rows.Scan(&s.Login, &s.Percent); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// linkedAuthors returns the authors of merged pull requests that close the issue, in merge order.
+func (h *IssueApplicationsHandler) linkedAuthors(ctx context.Context, projectID uuid.UUID, issueNumber int) ([]string, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT COALESCE(author_login, ''), COALESCE(body, '')
+FROM github_pull_requests
+WHERE project_id = $1 AND merged AND body LIKE '%#' || $2::int || '%'
+ORDER BY merged_at_github
+`, projectID, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	seen := map[string]bool{}
+	var out []string
+	for rows.Next() {
+		var author, body string
+		if err := rows.Scan(&author, &body); err != nil {
+			return nil, err
+		}
+		if author == "" || seen[strings.ToLower(author)] || !closesIssue(body, issueNumber) {
+			continue
+		}
+		seen[strings.ToLower(author)] = true
+		out = append(out, author)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// IssueDiscussionsHandler serves private discussion threads between project maintainers and
+// an applicant on an issue. Threads live only in Grainlify and are never posted to GitHub.
+type IssueDiscussionsHandler struct {
+	db *db.DB
+}
+
+func NewIssueDiscussionsHandler(d *db.DB) *IssueDiscussionsHandler {
+	return &IssueDiscussionsHandler{db: d}
+}
+
+// discussionAccess is the resolved caller context for a discussion thread.
+type discussionAccess struct {
+	projectID    uuid.UUID
+	issueNumber  int
+	userID       uuid.UUID
+	ownerUserID  uuid.UUID
+	threadUserID uuid.UUID
+	isMaintainer bool
+}
+
+// resolveAccess validates params and works out which thread the caller may see.
+// Maintainers pick the thread via ?user_id= (or body user_id); applicants always get their own.
+func (h *IssueDiscussionsHandler) resolveAccess(c *fiber.Ctx, requestedThreadUser string) (*discussionAccess, int, string) {
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return nil, fiber.StatusBadRequest, "invalid_issue_number"
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var ownerUserID uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+
+	a := &discussionAccess{
+		projectID:    projectID,
+		issueNumber:  issueNumber,
+		userID:       userID,
+		ownerUserID:  ownerUserID,
+		threadUserID: userID,
+		isMaintainer: ownerUserID == userID || role == "admin",
+	}
+	if a.isMaintainer {
+		requestedThreadUser = strings.TrimSpace(requestedThreadUser)
+		if requestedThreadUser == "" {
+			return nil, fiber.StatusBadRequest, "user_id_required"
+		}
+		a.threadUserID, err = uuid.Parse(requestedThreadUser)
+		if err != nil {
+			return nil, fiber.StatusBadRequest, "invalid_user_id"
+		}
+	}
+	return a, 0, ""
+}
+
+// List returns the comments of one thread. Hidden comments are shown to maintainers only.
+func (h *IssueDiscussionsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		a, status, code := h.resolveAccess(c, c.Query("user_id"))
+		if a == nil {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT d.id, d.author_user_id, COALESCE(ga.login, ''), COALESCE(ga.avatar_url, ''), d.body,
+  d.hidden_at, d.hidden_reason, d.created_at, d.updated_at
+FROM issue_discussion_comments d
+LEFT JOIN github_accounts ga ON ga.user_id = d.author_user_id
+WHERE d.project_id = $1 AND d.issue_number = $2 AND d.thread_user_id = $3
+  AND d.deleted_at IS NULL
+  AND ($4 = true OR d.hidden_at IS NULL)
+ORDER BY d.created_at ASC
+`, a.projectID, a.issueNumber, a.threadUserID, a.isMaintainer)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id, authorID uuid.UUID
+			var login, avatar, body string
+			var hiddenAt *time.Time
+			var hiddenReason *string
+			var createdAt, updatedAt time.Time
+			if err := rows.Scan(&id, &authorID, &login, &avatar, &body, &hiddenAt, &hiddenReason, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":             id.String(),
+				"author_user_id": authorID.String(),
+				"author_login":   login,
+				"author_avatar":  avatar,
+				"body":           body,
+				"hidden":         hiddenAt != nil,
+				"hidden_reason":  hiddenReason,
+				"created_at":     createdAt,
+				"updated_at":     updatedAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"thread_user_id": a.threadUserID.String(),
+			"comments":       out,
+		})
+	}
+}
+
+type discussionCommentRequest struct {
+	Body   string `json:"body"`
+	UserID string `json:"user_id"`
+}
+
+// Post adds a comment to a thread and notifies the other side.
+func (h *IssueDiscussionsHandler) Post() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req discussionCommentRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Body = strings.TrimSpace(req.Body)
+		var v validate.Validator
+		if v.Required("body", req.Body) {
+			v.MaxLen("body", req.Body, 5000)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		a, status, code := h.resolveAccess(c, req.UserID)
+		if a == nil {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		var id uuid.UUID
+		var createdAt time.Time
+		if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO issue_discussion_comments (project_id, issue_number, thread_user_id, author_user_id, body)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, created_at
+`, a.projectID, a.issueNumber, a.threadUserID, a.userID, req.Body).Scan(&id, &createdAt); err != nil {
+			slog.Error("failed to store discussion comment", "error", err, "project_id", a.projectID, "issue_number", a.issueNumber)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_post_failed"})
+		}
+
+		recipient := a.ownerUserID
+		if a.isMaintainer {
+			recipient = a.threadUserID
+		}
+		if recipient != a.userID {
+			notifyUser(c.Context(), h.db.Pool, recipient, "issue_discussion_comment", fiber.Map{
+				"project_id":     a.projectID.String(),
+				"issue_number":   a.issueNumber,
+				"thread_user_id": a.threadUserID.String(),
+				"comment_id":     id.String(),
+			})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String(), "created_at": createdAt})
+	}
+}
+
+// Edit lets the author change the body of their own comment.
+func (h *IssueDiscussionsHandler) Edit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		commentID, err := uuid.Parse(c.Params("commentId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_comment_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var req discussionCommentRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Body = strings.TrimSpace(req.Body)
+		var v validate.Validator
+		if v.Required("body", req.Body) {
+			v.MaxLen("body", req.Body, 5000)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_discussion_comments
+SET body = $3, updated_at = now()
+WHERE id = $1 AND author_user_id = $2 AND deleted_at IS NULL AND hidden_at IS NULL
+`, commentID, userID, req.Body)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Delete soft-deletes the caller's own comment.
+func (h *IssueDiscussionsHandler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		commentID, err := uuid.Parse(c.Params("commentId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_comment_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_discussion_comments
+SET deleted_at = now(), updated_at = now()
+WHERE id = $1 AND author_user_id = $2 AND deleted_at IS NULL
+`, commentID, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+type moderateDiscussionRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+// Moderate lets a project maintainer (or admin) hide or unhide a comment in any thread on the project.
+func (h *IssueDiscussionsHandler) Moderate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		commentID, err := uuid.Parse(c.Params("commentId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_comment_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req moderateDiscussionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		var reason *string
+		if r := strings.TrimSpace(req.Reason); r != "" && req.Hidden {
+			reason = &r
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_discussion_comments
+SET hidden_at = CASE WHEN $3 THEN now() ELSE NULL END,
+    hidden_by_user_id = CASE WHEN $3 THEN $4::uuid ELSE NULL END,
+    hidden_reason = $5,
+    updated_at = now()
+WHERE id = $1 AND project_id = $2 AND deleted_at IS NULL
+`, commentID, projectID, req.Hidden, userID, reason)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "discussion_moderation_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "hidden": req.Hidden})
+	}
+}
@@ -0,0 +1,422 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// Limits match GitHub's: 256 characters for titles and 65536 for bodies.
+const (
+	maxDraftTitleLength = 256
+	maxDraftBodyLength  = 65536
+	maxDraftLabels      = 20
+)
+
+type issueDraft struct {
+	ID                uuid.UUID  `json:"id"`
+	ProjectID         uuid.UUID  `json:"project_id"`
+	CreatedByUserID   *uuid.UUID `json:"created_by_user_id"`
+	Title             string     `json:"title"`
+	Body              string     `json:"body"`
+	Labels            []string   `json:"labels"`
+	Points            *int       `json:"points"`
+	GitHubIssueID     *int64     `json:"github_issue_id"`
+	GitHubIssueNumber *int       `json:"github_issue_number"`
+	PublishedAt       *time.Time `json:"published_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+const issueDraftColumns = `id, project_id, created_by_user_id, title, body, labels, points, github_issue_id, github_issue_number,
+       published_at, created_at, updated_at`
+
+func scanIssueDraft(row pgx.Row) (issueDraft, error) {
+	var d issueDraft
+	err := row.Scan(&d.ID, &d.ProjectID, &d.CreatedByUserID, &d.Title, &d.Body, &d.Labels, &d.Points,
+		&d.GitHubIssueID, &d.GitHubIssueNumber, &d.PublishedAt, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+type issueDraftRequest struct {
+	Title  *string   `json:"title"`
+	Body   *string   `json:"body"`
+	Labels *[]string `json:"labels"`
+	Points *int      `json:"points"`
+	// ClearPoints unsets points on update.
+	ClearPoints bool `json:"clear_points"`
+	// Publish publishes the draft to GitHub right after saving it.
+	Publish bool `json:"publish"`
+}
+
+// validate trims the request in place and returns an error code, or "".
+func (r *issueDraftRequest) validate(creating bool) string {
+	if r.Title != nil {
+		t := strings.TrimSpace(*r.Title)
+		r.Title = &t
+		if t == "" || utf8.RuneCountInString(t) > maxDraftTitleLength {
+			return "invalid_title"
+		}
+	} else if creating {
+		return "title_required"
+	}
+	if r.Body != nil && len(*r.Body) > maxDraftBodyLength {
+		return "body_too_long"
+	}
+	if r.Labels != nil {
+		names := make([]string, 0, len(*r.Labels))
+		for _, n := range *r.Labels {
+			n = strings.TrimSpace(n)
+			if n == "" || len(n) > maxLabelNameLength {
+				return "invalid_label"
+			}
+			if !containsLabel(names, n) {
+				names = append(names, n)
+			}
+		}
+		if len(names) > maxDraftLabels {
+			return "too_many_labels"
+		}
+		r.Labels = &names
+	}
+	if r.Points != nil && *r.Points < 0 {
+		return "invalid_points"
+	}
+	return ""
+}
+
+// ListIssueDrafts serves GET /projects/:id/issue-drafts: unpublished drafts first, then published ones,
+// newest first. Maintainer only.
+func (h *IssueApplicationsHandler) ListIssueDrafts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+issueDraftColumns+`
+FROM issue_drafts
+WHERE project_id = $1
+ORDER BY published_at IS NOT NULL, created_at DESC
+LIMIT 200
+`, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "drafts_lookup_failed"})
+		}
+		defer rows.Close()
+		out := []issueDraft{}
+		for rows.Next() {
+			d, err := scanIssueDraft(rows)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "drafts_lookup_failed"})
+			}
+			out = append(out, d)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "drafts_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"drafts": out})
+	}
+}
+
+// CreateIssueDraft serves POST /projects/:id/issue-drafts. With "publish": true the draft is published to
+// GitHub in the same call. Maintainer only.
+func (h *IssueApplicationsHandler) CreateIssueDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req issueDraftRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if code := req.validate(true); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		projectID, userID, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		if req.Body == nil {
+			req.Body = new(string)
+		}
+		if req.Labels == nil {
+			req.Labels = &[]string{}
+		}
+		return h.createIssueDraft(c, projectID, userID, *req.Title, *req.Body, *req.Labels, req.Points, req.Publish)
+	}
+}
+
+// createIssueDraft stores a new draft, publishing it when asked, and writes the response.
+func (h *IssueApplicationsHandler) createIssueDraft(c *fiber.Ctx, projectID, userID uuid.UUID, title, body string, labels []string, points *int, publish bool) error {
+	d, err := scanIssueDraft(h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO issue_drafts (project_id, created_by_user_id, title, body, labels, points)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING `+issueDraftColumns+`
+`, projectID, userID, title, body, labels, points))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "draft_create_failed"})
+	}
+	if publish {
+		status, out := h.publishIssueDraft(c.Context(), d)
+		if status == fiber.StatusOK {
+			out = withDryRun(c, out)
+		}
+		return c.Status(status).JSON(out)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"draft": d})
+}
+
+// UpdateIssueDraft serves PATCH /projects/:id/issue-drafts/:draftId. Published drafts are read-only; edit
+// the issue on GitHub instead. Maintainer only.
+func (h *IssueApplicationsHandler) UpdateIssueDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		draftID, err := uuid.Parse(c.Params("draftId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_draft_id"})
+		}
+		var req issueDraftRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if code := req.validate(false); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		projectID, _, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		d, status, body := h.loadIssueDraft(c.Context(), projectID, draftID)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		if d.PublishedAt != nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "draft_already_published"})
+		}
+		d, err = scanIssueDraft(h.db.Pool.QueryRow(c.Context(), `
+UPDATE issue_drafts
+SET title = COALESCE($3, title),
+    body = COALESCE($4, body),
+    labels = COALESCE($5, labels),
+    points = CASE WHEN $7 THEN NULL ELSE COALESCE($6, points) END,
+    updated_at = now()
+WHERE project_id = $1 AND id = $2 AND published_at IS NULL
+RETURNING `+issueDraftColumns+`
+`, projectID, draftID, req.Title, req.Body, req.Labels, req.Points, req.ClearPoints))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "draft_already_published"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "draft_update_failed"})
+		}
+		if req.Publish {
+			status, out := h.publishIssueDraft(c.Context(), d)
+			if status == fiber.StatusOK {
+				out = withDryRun(c, out)
+			}
+			return c.Status(status).JSON(out)
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"draft": d})
+	}
+}
+
+// DeleteIssueDraft serves DELETE /projects/:id/issue-drafts/:draftId for unpublished drafts. Maintainer only.
+func (h *IssueApplicationsHandler) DeleteIssueDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		draftID, err := uuid.Parse(c.Params("draftId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_draft_id"})
+		}
+		projectID, _, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		d, status, body := h.loadIssueDraft(c.Context(), projectID, draftID)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		if d.PublishedAt != nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "draft_already_published"})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM issue_drafts WHERE project_id = $1 AND id = $2 AND published_at IS NULL
+`, projectID, draftID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "draft_delete_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// PublishIssueDraft serves POST /projects/:id/issue-drafts/:draftId/publish. Maintainer only.
+func (h *IssueApplicationsHandler) PublishIssueDraft() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		draftID, err := uuid.Parse(c.Params("draftId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_draft_id"})
+		}
+		projectID, _, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		d, status, body := h.loadIssueDraft(c.Context(), projectID, draftID)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		status, out := h.publishIssueDraft(c.Context(), d)
+		if status == fiber.StatusOK {
+			out = withDryRun(c, out)
+		}
+		return c.Status(status).JSON(out)
+	}
+}
+
+// publishIssueDraft opens the draft as a GitHub issue through the App installation, records its IDs on the
+// draft and stores the issue (with the draft's points) so it shows up without waiting for the webhook.
+func (h *IssueApplicationsHandler) publishIssueDraft(ctx context.Context, d issueDraft) (int, fiber.Map) {
+	if d.PublishedAt != nil {
+		return fiber.StatusConflict, fiber.Map{"error": "draft_already_published", "draft": d}
+	}
+	if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+		return fiber.StatusServiceUnavailable, fiber.Map{"error": "github_app_not_configured"}
+	}
+	var fullName, installationID string
+	if err := h.db.Pool.QueryRow(ctx, `
+SELECT github_full_name, COALESCE(github_app_installation_id, '') FROM projects WHERE id = $1
+`, d.ProjectID).Scan(&fullName, &installationID); err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "project_lookup_failed"}
+	}
+	if installationID == "" {
+		return fiber.StatusBadRequest, fiber.Map{"error": "project_has_no_github_app_installation"}
+	}
+
+	appClient, err := h.apps.ForInstallation(ctx, installationID)
+	if err != nil {
+		slog.Error("failed to create GitHub App client for draft publish", "error", err)
+		return fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
+	}
+	token, err := appClient.GetInstallationToken(ctx, installationID)
+	if err != nil {
+		slog.Warn("failed to get installation token for draft publish", "project_id", d.ProjectID.String(), "error", err)
+		return fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
+	}
+
+	it, err := h.gh.CreateIssue(ctx, token, fullName, d.Title, d.Body)
+	if err != nil {
+		slog.Warn("failed to create issue on GitHub", "project_id", d.ProjectID.String(), "draft_id", d.ID.String(), "error", err)
+		return fiber.StatusBadGateway, fiber.Map{"error": "github_issue_create_failed"}
+	}
+	if it.Number <= 0 {
+		// Dry run: nothing was created, so the draft stays unpublished.
+		return fiber.StatusOK, fiber.Map{"ok": true, "draft": d}
+	}
+
+	// Labels are added separately so standard ones can be created with their color first. The issue already
+	// exists, so a failure here is reported but does not undo the publish.
+	labels := []github.Label{}
+	labelsFailed := false
+	if len(d.Labels) > 0 {
+		for _, n := range d.Labels {
+			l := github.Label{Name: n, Color: "ededed"}
+			if std, ok := standardLabel(n); ok {
+				if _, err := h.gh.CreateLabel(ctx, token, fullName, std); err != nil {
+					slog.Warn("failed to create standard label", "project_id", d.ProjectID.String(), "label", std.Name, "error", err)
+				}
+				l = github.Label{Name: std.Name, Color: std.Color}
+			}
+			labels = append(labels, l)
+		}
+		if err := h.gh.AddIssueLabels(ctx, token, fullName, it.Number, d.Labels); err != nil {
+			slog.Warn("failed to label published draft", "project_id", d.ProjectID.String(), "issue_number", it.Number, "error", err)
+			labels, labelsFailed = []github.Label{}, true
+		}
+	}
+	labelsJSON, _ := json.Marshal(labels)
+	err = pgx.BeginFunc(ctx, h.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO github_issues (project_id, github_issue_id, number, state, title, body, author_login, url, assignees, labels, points,
+                           created_at_github, updated_at_github, last_seen_at)
+VALUES ($1, $2, $3, 'open', $4, $5, $6, $7, '[]'::jsonb, $8, $9, now(), now(), now())
+ON CONFLICT (project_id, github_issue_id) DO UPDATE SET points = COALESCE(EXCLUDED.points, github_issues.points)
+`, d.ProjectID, it.ID, it.Number, it.Title, it.Body, it.User.Login, it.HTMLURL, labelsJSON, d.Points); err != nil {
+			return err
+		}
+		var err error
+		d, err = scanIssueDraft(tx.QueryRow(ctx, `
+UPDATE issue_drafts
+SET github_issue_id = $2, github_issue_number = $3, published_at = now(), updated_at = now()
+WHERE id = $1
+RETURNING `+issueDraftColumns+`
+`, d.ID, it.ID, it.Number))
+		return err
+	})
+	if err != nil {
+		// The issue exists on GitHub; the webhook or next sync will still pick it up.
+		slog.Error("failed to record published draft", "draft_id", d.ID.String(), "issue_number", it.Number, "error", err)
+		return fiber.StatusInternalServerError, fiber.Map{"error": "draft_publish_record_failed", "github_issue_number": it.Number, "url": it.HTMLURL}
+	}
+
+	checkruns.MarkIssueChanged(ctx, h.db.Pool, d.ProjectID, it.Number)
+	if d.Points != nil {
+		h.refreshStatusComment(ctx, d.ProjectID, it.Number)
+	}
+	return fiber.StatusOK, fiber.Map{"ok": true, "draft": d, "url": it.HTMLURL, "labels_failed": labelsFailed}
+}
+
+// draftProject parses :id and checks the caller maintains the project, returning the project and caller, or
+// the error response to send.
+func (h *IssueApplicationsHandler) draftProject(c *fiber.Ctx) (uuid.UUID, uuid.UUID, int, fiber.Map) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, uuid.Nil, fiber.StatusServiceUnavailable, fiber.Map{"error": "db_not_configured"}
+	}
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.StatusBadRequest, fiber.Map{"error": "invalid_project_id"}
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.StatusUnauthorized, fiber.Map{"error": "invalid_user"}
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, uuid.Nil, fiber.StatusNotFound, fiber.Map{"error": "project_not_found"}
+	}
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.StatusInternalServerError, fiber.Map{"error": "project_lookup_failed"}
+	}
+	if owner != userID && role != "admin" {
+		return uuid.Nil, uuid.Nil, fiber.StatusForbidden, fiber.Map{"error": "forbidden"}
+	}
+	return projectID, userID, fiber.StatusOK, nil
+}
+
+func (h *IssueApplicationsHandler) loadIssueDraft(ctx context.Context, projectID, draftID uuid.UUID) (issueDraft, int, fiber.Map) {
+	d, err := scanIssueDraft(h.db.Pool.QueryRow(ctx, `
+SELECT `+issueDraftColumns+`
+FROM issue_drafts
+WHERE project_id = $1 AND id = $2
+`, projectID, draftID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return issueDraft{}, fiber.StatusNotFound, fiber.Map{"error": "draft_not_found"}
+	}
+	if err != nil {
+		return issueDraft{}, fiber.StatusInternalServerError, fiber.Map{"error": "draft_lookup_failed"}
+	}
+	return d, fiber.StatusOK, nil
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/estimate"
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+)
+
+// estimateHistoryLimit bounds how many completed issues feed an estimate.
+const estimateHistoryLimit = 500
+
+// Estimate serves GET /projects/:id/issues/:number/estimate: a suggested complexity and point value for
+// the issue, with the signals behind it. Maintainer only; nothing is written.
+func (h *IssueApplicationsHandler) Estimate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, issueNumber, status, code := h.estimateTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		suggestion, current, err := h.estimateIssue(c.Context(), projectID, issueNumber)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "estimate_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"issue_number":   issueNumber,
+			"current_points": current,
+			"suggestion":     suggestion,
+		})
+	}
+}
+
+// AcceptEstimate serves POST /projects/:id/issues/:number/estimate/accept: sets the issue's points to the
+// current suggestion and refreshes its status comment, like PUT .../status with those points.
+func (h *IssueApplicationsHandler) AcceptEstimate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, issueNumber, status, code := h.estimateTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		suggestion, _, err := h.estimateIssue(c.Context(), projectID, issueNumber)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "estimate_failed"})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues SET points = $3 WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, suggestion.Points); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_update_failed"})
+		}
+
+		checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+		h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "points": suggestion.Points, "suggestion": suggestion})
+	}
+}
+
+// estimateTarget parses the route and checks the caller maintains the project.
+func (h *IssueApplicationsHandler) estimateTarget(c *fiber.Ctx) (uuid.UUID, int, int, string) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, 0, fiber.StatusServiceUnavailable, "db_not_configured"
+	}
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, 0, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return uuid.Nil, 0, fiber.StatusBadRequest, "invalid_issue_number"
+	}
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, 0, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, 0, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, 0, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+		return uuid.Nil, 0, fiber.StatusForbidden, "forbidden"
+	}
+	return projectID, issueNumber, 0, ""
+}
+
+// estimateIssue loads the issue and the project's completed, pointed issues and runs the estimator.
+// Completion time runs from the first assignment (or the issue's creation) to its close.
+func (h *IssueApplicationsHandler) estimateIssue(ctx context.Context, projectID uuid.UUID, issueNumber int) (estimate.Suggestion, *int, error) {
+	var issue estimate.Issue
+	var labelsJSON []byte
+	var current *int
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT COALESCE(title, ''), COALESCE(body, ''), COALESCE(labels, '[]'::jsonb), points
+FROM github_issues
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber).Scan(&issue.Title, &issue.Body, &labelsJSON, &current)
+	if err != nil {
+		return estimate.Suggestion{}, nil, err
+	}
+	issue.Labels = recommend.LabelNames(labelsJSON)
+
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT COALESCE(gi.labels, '[]'::jsonb), gi.points,
+       COALESCE(EXTRACT(EPOCH FROM gi.closed_at_github - COALESCE(
+         (SELECT MIN(a.created_at) FROM issue_applications a
+          WHERE a.project_id = gi.project_id AND a.issue_number = gi.number AND a.status = 'assigned'),
+         gi.created_at_github)) / 3600, 0)::float8
+FROM github_issues gi
+WHERE gi.project_id = $1 AND gi.number <> $2
+  AND gi.state = 'closed' AND gi.closed_at_github IS NOT NULL AND gi.points > 0
+ORDER BY gi.closed_at_github DESC
+LIMIT $3
+`, projectID, issueNumber, estimateHistoryLimit)
+	if err != nil {
+		return estimate.Suggestion{}, nil, err
+	}
+	defer rows.Close()
+	var history []estimate.Sample
+	for rows.Next() {
+		var s estimate.Sample
+		var sampleLabels []byte
+		if err := rows.Scan(&sampleLabels, &s.Points, &s.Hours); err != nil {
+			return estimate.Suggestion{}, nil, err
+		}
+		s.Labels = recommend.LabelNames(sampleLabels)
+		history = append(history, s)
+	}
+	if err := rows.Err(); err != nil {
+		return estimate.Suggestion{}, nil, err
+	}
+	return estimate.Estimate(issue, history), current, nil
+}
@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/botcomments"
+	"github.com/jagadeesh/grainlify/backend/internal/botmessages"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/pause"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+type handoffRequest struct {
+	To     string `json:"to"` // GitHub login of a Grainlify contributor
+	Reason string `json:"reason"`
+}
+
+// RequestHandoff lets the issue's assignee ask the maintainer to hand the issue over to another
+// contributor. The maintainer and the proposed contributor are notified.
+func (h *IssueApplicationsHandler) RequestHandoff() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req handoffRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.To, req.Reason = strings.TrimPrefix(strings.TrimSpace(req.To), "@"), strings.TrimSpace(req.Reason)
+		var v validate.Validator
+		v.Required("to", req.To)
+		v.MaxLen("reason", req.Reason, 2000)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		var login string
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1`, userID).Scan(&login); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		}
+		if strings.EqualFold(login, req.To) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "handoff_to_self"})
+		}
+		var toUserID uuid.UUID
+		var toLogin string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT user_id, login FROM github_accounts WHERE LOWER(login) = LOWER($1)`, req.To).Scan(&toUserID, &toLogin)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "handoff_target_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_lookup_failed"})
+		}
+
+		var owner uuid.UUID
+		var assigneesJSON []byte
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.owner_user_id, COALESCE(gi.assignees, '[]'::jsonb)
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number = $2 AND gi.state = 'open'
+`, projectID, issueNumber).Scan(&owner, &assigneesJSON)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+		if !hasAssignee(assigneesJSON, login) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "not_assignee"})
+		}
+		if hasAssignee(assigneesJSON, toLogin) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "handoff_target_already_assigned"})
+		}
+
+		var id uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO issue_handoffs (project_id, issue_number, from_user_id, from_login, to_user_id, to_login, reason)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+ON CONFLICT DO NOTHING
+RETURNING id
+`, projectID, issueNumber, userID, login, toUserID, toLogin, req.Reason).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "handoff_already_requested"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "handoff_request_failed"})
+		}
+
+		payload := fiber.Map{
+			"handoff_id":   id.String(),
+			"project_id":   projectID.String(),
+			"issue_number": issueNumber,
+			"from_login":   login,
+			"to_login":     toLogin,
+			"reason":       req.Reason,
+		}
+		notifyUser(c.Context(), h.db.Pool, owner, "handoff_requested", payload)
+		notifyUser(c.Context(), h.db.Pool, toUserID, "handoff_proposed", payload)
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String(), "status": "pending"})
+	}
+}
+
+// ListHandoffs returns an issue's hand-off requests, newest first. Maintainer, admin, or the contributors
+// a request names.
+func (h *IssueApplicationsHandler) ListHandoffs() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		maintainer := h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber)
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, from_login, to_login, COALESCE(reason, ''), status, decided_at, created_at
+FROM issue_handoffs
+WHERE project_id = $1 AND issue_number = $2 AND ($3 OR from_user_id = $4 OR to_user_id = $4)
+ORDER BY created_at DESC
+`, projectID, issueNumber, maintainer, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "handoffs_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var from, to, reason, status string
+			var decidedAt *time.Time
+			var createdAt time.Time
+			if err := rows.Scan(&id, &from, &to, &reason, &status, &decidedAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "handoffs_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":         id.String(),
+				"from_login": from,
+				"to_login":   to,
+				"reason":     reason,
+				"status":     status,
+				"decided_at": decidedAt,
+				"created_at": createdAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"handoffs": out})
+	}
+}
+
+// CancelHandoff withdraws a pending hand-off request. Requester only.
+func (h *IssueApplicationsHandler) CancelHandoff() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		handoffID, err := uuid.Parse(c.Params("handoffId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_handoff_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_handoffs SET status = 'cancelled', decided_at = now()
+WHERE id = $1 AND project_id = $2 AND issue_number = $3 AND from_user_id = $4 AND status = 'pending'
+`, handoffID, projectID, issueNumber, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "handoff_cancel_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "handoff_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": "cancelled"})
+	}
+}
+
+type handoffDecisionRequest struct {
+	Approve bool `json:"approve"`
+}
+
+// DecideHandoff approves or denies a pending hand-off request. Maintainer only. Approval moves the
+// assignment: on GitHub the requester is swapped for the new contributor, their applications follow,
+// the escrowed reward moves to the new assignee and the deadline stays as it was.
+func (h *IssueApplicationsHandler) DecideHandoff() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		handoffID, err := uuid.Parse(c.Params("handoffId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_handoff_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req handoffDecisionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+
+		var owner uuid.UUID
+		var fullName, installationID string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner, &fullName, &installationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		var fromUserID, toUserID *uuid.UUID
+		var from, to string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT from_user_id, from_login, to_user_id, to_login FROM issue_handoffs
+WHERE id = $1 AND project_id = $2 AND issue_number = $3 AND status = 'pending'
+`, handoffID, projectID, issueNumber).Scan(&fromUserID, &from, &toUserID, &to)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "handoff_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "handoff_lookup_failed"})
+		}
+
+		if req.Approve {
+			status, out := h.handOff(c.Context(), projectID, issueNumber, fullName, installationID, from, to, toUserID)
+			if status != fiber.StatusOK {
+				return c.Status(status).JSON(out)
+			}
+		}
+
+		status := "denied"
+		if req.Approve {
+			status = "approved"
+		}
+		_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE issue_handoffs SET status = $2, decided_by_user_id = $3, decided_at = now()
+WHERE id = $1 AND status = 'pending'
+`, handoffID, status, userID)
+
+		payload := fiber.Map{
+			"handoff_id":   handoffID.String(),
+			"project_id":   projectID.String(),
+			"issue_number": issueNumber,
+			"from_login":   from,
+			"to_login":     to,
+		}
+		if fromUserID != nil {
+			notifyUser(c.Context(), h.db.Pool, *fromUserID, "handoff_"+status, payload)
+		}
+		if toUserID != nil {
+			notifyUser(c.Context(), h.db.Pool, *toUserID, "handoff_"+status, payload)
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": status})
+	}
+}
+
+// handOff moves an issue's assignment from one contributor to another. It returns the response status
+// and, on failure, body.
+func (h *IssueApplicationsHandler) handOff(ctx context.Context, projectID uuid.UUID, issueNumber int, fullName, installationID, from, to string, toUserID *uuid.UUID) (int, fiber.Map) {
+	var assigneesJSON []byte
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT COALESCE(assignees, '[]'::jsonb) FROM github_issues WHERE project_id = $1 AND number = $2 AND state = 'open'
+`, projectID, issueNumber).Scan(&assigneesJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusConflict, fiber.Map{"error": "issue_not_open"}
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "issue_lookup_failed"}
+	}
+	if !hasAssignee(assigneesJSON, from) {
+		return fiber.StatusConflict, fiber.Map{"error": "requester_no_longer_assigned"}
+	}
+	if until, err := pause.UntilForLogin(ctx, h.db.Pool, to); err == nil && until != nil {
+		return fiber.StatusConflict, fiber.Map{"error": "assignee_paused", "assignee": to, "paused_until": until}
+	}
+	limit, current, err := assignmentLoad(ctx, h.db.Pool, h.cfg.MaxConcurrentAssignments, projectID, to)
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "assignment_limit_lookup_failed"}
+	}
+	if limit > 0 && current >= limit {
+		return fiber.StatusConflict, fiber.Map{"error": "assignment_limit_reached", "assignee": to, "limit": limit, "current": current}
+	}
+
+	newUserID := uuid.Nil
+	if toUserID != nil {
+		newUserID = *toUserID
+	}
+	if tasks.IsNative(issueNumber) {
+		if status, out := h.assignTask(ctx, projectID, issueNumber, to, newUserID, nil); status != fiber.StatusOK {
+			return status, out
+		}
+	} else {
+		if installationID == "" {
+			return fiber.StatusBadRequest, fiber.Map{"error": "project_has_no_github_app_installation"}
+		}
+		appClient, err := h.apps.ForInstallation(ctx, installationID)
+		if err != nil {
+			slog.Error("failed to create GitHub App client for hand-off", "error", err)
+			return fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
+		}
+		token, err := appClient.GetInstallationToken(ctx, installationID)
+		if err != nil {
+			slog.Warn("failed to get installation token for hand-off", "project_id", projectID.String(), "error", err)
+			return fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
+		}
+		gh := h.gh
+		if ok, err := gh.CheckAssignee(ctx, token, fullName, to); err == nil && !ok {
+			return fiber.StatusUnprocessableEntity, fiber.Map{"error": "assignee_not_assignable", "assignee": to}
+		}
+		version := h.issueVersion(ctx, projectID, issueNumber)
+		// Add the new assignee first, so a failure part-way leaves the issue assigned to someone.
+		if err := gh.AddIssueAssignees(ctx, token, fullName, issueNumber, []string{to}); err != nil {
+			slog.Warn("failed to add hand-off assignee on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+			return fiber.StatusBadGateway, fiber.Map{"error": "github_assign_failed"}
+		}
+		if err := gh.RemoveIssueAssignees(ctx, token, fullName, issueNumber, []string{from}); err != nil {
+			slog.Warn("failed to remove handed-off assignee on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+		}
+		var assignees []map[string]any
+		_ = json.Unmarshal(assigneesJSON, &assignees)
+		kept := []map[string]any{{"login": to}}
+		for _, a := range assignees {
+			if l, _ := a["login"].(string); !strings.EqualFold(l, from) {
+				kept = append(kept, a)
+			}
+		}
+		newAssignees, _ := json.Marshal(kept)
+		h.setIssueAssignees(ctx, token, fullName, projectID, issueNumber, version, newAssignees)
+
+		botBody := botmessages.Render(ctx, h.db.Pool, projectID, botmessages.HandedOff, map[string]string{"from": from, "to": to})
+		if _, _, err := botcomments.Post(ctx, h.db.Pool, gh, token, fullName, projectID, issueNumber, botBody); err != nil {
+			slog.Warn("hand-off: bot comment failed", "error", err)
+		}
+		_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'assigned', decided_at = now(), updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status = 'pending'
+`, projectID, issueNumber, to)
+	}
+
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'unassigned', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'assigned' AND LOWER(github_login) = LOWER($3)
+`, projectID, issueNumber, from)
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE assignment_extension_requests SET status = 'denied', decided_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'pending' AND LOWER(github_login) = LOWER($3)
+`, projectID, issueNumber, from)
+	// An escrowed reward follows the assignment without touching the budget.
+	if _, err := rewards.Lock(ctx, h.db.Pool, projectID, issueNumber, to); err != nil {
+		slog.Error("failed to move issue reward on hand-off", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+	}
+
+	checkruns.MarkIssueChanged(ctx, h.db.Pool, projectID, issueNumber)
+	h.refreshStatusComment(ctx, projectID, issueNumber)
+	return fiber.StatusOK, nil
+}
+
+// hasAssignee reports whether login is among an issue's assignees.
+func hasAssignee(assigneesJSON []byte, login string) bool {
+	var assignees []struct {
+		Login string `json:"login"`
+	}
+	_ = json.Unmarshal(assigneesJSON, &assignees)
+	for _, a := range assignees {
+		if strings.EqualFold(a.Login, login) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+)
+
+// standardLabels is the label set Grainlify looks for: "grainlify" marks issues open to contributors and the
+// difficulty labels are read by the complexity estimator.
+var standardLabels = []github.Label{
+	{Name: "grainlify", Color: "6d28d9", Description: "Open for contributions through Grainlify"},
+	{Name: "difficulty: beginner", Color: "0e8a16", Description: "Small, well-scoped; good first contribution"},
+	{Name: "difficulty: intermediate", Color: "fbca04", Description: "Needs some familiarity with the codebase"},
+	{Name: "difficulty: advanced", Color: "d93f0b", Description: "Large or cross-cutting change"},
+}
+
+// maxLabelNameLength is GitHub's limit on label names.
+const maxLabelNameLength = 50
+
+type issueLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+// AddIssueLabels adds labels to an issue through the App installation. Labels missing from the repository
+// are created (standard labels with their color). Maintainer only.
+func (h *IssueApplicationsHandler) AddIssueLabels() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req issueLabelsRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		names := make([]string, 0, len(req.Labels))
+		for _, n := range req.Labels {
+			n = strings.TrimSpace(n)
+			if n == "" || len(n) > maxLabelNameLength {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_label"})
+			}
+			if !containsLabel(names, n) {
+				names = append(names, n)
+			}
+		}
+		if len(names) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "labels_required"})
+		}
+		return h.changeIssueLabels(c, names, nil)
+	}
+}
+
+// RemoveIssueLabel removes one label from an issue through the App installation. Maintainer only.
+func (h *IssueApplicationsHandler) RemoveIssueLabel() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Label names often contain spaces and colons, so the path parameter arrives escaped.
+		name, err := url.PathUnescape(c.Params("name"))
+		name = strings.TrimSpace(name)
+		if err != nil || name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_label"})
+		}
+		return h.changeIssueLabels(c, nil, []string{name})
+	}
+}
+
+func (h *IssueApplicationsHandler) changeIssueLabels(c *fiber.Ctx, add []string, remove []string) error {
+	if h.db == nil || h.db.Pool == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+	}
+	if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+	}
+
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+	}
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+	}
+	if tasks.IsNative(issueNumber) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "native_task_not_on_github"})
+	}
+
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	var fullName, installationID string
+	var labelsJSON []byte
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.owner_user_id, p.github_full_name, COALESCE(p.github_app_installation_id, ''), COALESCE(gi.labels, '[]'::jsonb)
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number = $2
+`, projectID, issueNumber).Scan(&owner, &fullName, &installationID, &labelsJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+	}
+	if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+	if installationID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
+	}
+
+	token, status, body := h.installationToken(c, projectID, installationID)
+	if status != fiber.StatusOK {
+		return c.Status(status).JSON(body)
+	}
+
+	var labels []github.Label
+	_ = json.Unmarshal(labelsJSON, &labels)
+
+	if len(add) > 0 {
+		// Create standard labels first so they get their color rather than GitHub's default grey.
+		for _, n := range add {
+			if std, ok := standardLabel(n); ok && !hasLabel(labels, n) {
+				if _, err := h.gh.CreateLabel(c.Context(), token, fullName, std); err != nil {
+					slog.Warn("failed to create standard label", "project_id", projectID.String(), "label", std.Name, "error", err)
+				}
+			}
+		}
+		if err := h.gh.AddIssueLabels(c.Context(), token, fullName, issueNumber, add); err != nil {
+			slog.Warn("failed to add labels on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_label_failed"})
+		}
+		for _, n := range add {
+			if hasLabel(labels, n) {
+				continue
+			}
+			l := github.Label{Name: n, Color: "ededed"}
+			if std, ok := standardLabel(n); ok {
+				l = github.Label{Name: std.Name, Color: std.Color}
+			}
+			labels = append(labels, l)
+		}
+	}
+	for _, n := range remove {
+		if err := h.gh.RemoveIssueLabel(c.Context(), token, fullName, issueNumber, n); err != nil {
+			slog.Warn("failed to remove label on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_label_failed"})
+		}
+		kept := labels[:0]
+		for _, l := range labels {
+			if !strings.EqualFold(l.Name, n) {
+				kept = append(kept, l)
+			}
+		}
+		labels = kept
+	}
+
+	// The issues webhook will confirm this; update now so the dashboard reflects it immediately.
+	if labels == nil {
+		labels = []github.Label{}
+	}
+	newJSON, _ := json.Marshal(labels)
+	_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues SET labels = $3, last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, newJSON)
+	checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+
+	return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true, "labels": labels}))
+}
+
+// SetupStandardLabels creates the standard Grainlify labels in the project's repository, leaving existing
+// labels of the same name untouched. Maintainer only.
+func (h *IssueApplicationsHandler) SetupStandardLabels() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var owner uuid.UUID
+		var fullName, installationID string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id, github_full_name, COALESCE(github_app_installation_id, '')
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&owner, &fullName, &installationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if owner != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+		if installationID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
+		}
+
+		token, status, body := h.installationToken(c, projectID, installationID)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+
+		results := make([]fiber.Map, 0, len(standardLabels))
+		for _, l := range standardLabels {
+			created, err := h.gh.CreateLabel(c.Context(), token, fullName, l)
+			r := fiber.Map{"name": l.Name, "color": l.Color}
+			switch {
+			case err != nil:
+				slog.Warn("failed to create standard label", "project_id", projectID.String(), "label", l.Name, "error", err)
+				r["status"] = "failed"
+			case created:
+				r["status"] = "created"
+			default:
+				r["status"] = "exists"
+			}
+			results = append(results, r)
+		}
+		return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"labels": results}))
+	}
+}
+
+// installationToken returns an installation token for the project's App installation, or the error
+// response to send.
+func (h *IssueApplicationsHandler) installationToken(c *fiber.Ctx, projectID uuid.UUID, installationID string) (string, int, fiber.Map) {
+	appClient, err := h.apps.ForInstallation(c.Context(), installationID)
+	if err != nil {
+		slog.Error("failed to create GitHub App client", "error", err)
+		return "", fiber.StatusInternalServerError, fiber.Map{"error": "github_app_client_failed"}
+	}
+	token, err := appClient.GetInstallationToken(c.Context(), installationID)
+	if err != nil {
+		slog.Warn("failed to get installation token", "project_id", projectID.String(), "error", err)
+		return "", fiber.StatusBadGateway, fiber.Map{"error": "installation_token_failed"}
+	}
+	return token, fiber.StatusOK, nil
+}
+
+func standardLabel(name string) (github.Label, bool) {
+	for _, l := range standardLabels {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	return github.Label{}, false
+}
+
+func hasLabel(labels []github.Label, name string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLabel(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+type issueRewardRequest struct {
+	// Amount is in the currency's base units; "" or null removes the reward.
+	Amount *string `json:"amount"`
+	// Currency defaults to the ecosystem's reward currency.
+	Currency string `json:"currency"`
+}
+
+// IssueReward serves GET /projects/:id/issues/:number/reward: the issue's reward and whether it is locked
+// in escrow for an assignee.
+func (h *IssueApplicationsHandler) IssueReward() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		out, err := h.issueReward(c, projectID, issueNumber)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reward_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+// SetIssueReward serves PUT /projects/:id/issues/:number/reward: sets or removes the issue's reward.
+// Maintainer only. A reward locked for an assignee can't change until it is released or returned.
+func (h *IssueApplicationsHandler) SetIssueReward() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, issueNumber, status, code := h.estimateTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req issueRewardRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		amount := ""
+		if req.Amount != nil {
+			amount = strings.TrimSpace(*req.Amount)
+		}
+		currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+		if amount != "" {
+			var v validate.Validator
+			if n, ok := new(big.Int).SetString(amount, 10); !ok || n.Sign() <= 0 || len(amount) > 78 {
+				v.Fail("amount", validate.Invalid, "must be a positive whole number of base units")
+			}
+			if err := v.Err(); err != nil {
+				return validationFailed(c, err)
+			}
+		}
+
+		var locked bool
+		var ecoCurrency *string
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS (SELECT 1 FROM reward_locks WHERE project_id = $1 AND issue_number = $2 AND status = 'locked'),
+       (SELECT e.reward_currency FROM projects p JOIN ecosystems e ON e.id = p.ecosystem_id WHERE p.id = $1)
+`, projectID, issueNumber).Scan(&locked, &ecoCurrency)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reward_lookup_failed"})
+		}
+		if locked {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "reward_locked"})
+		}
+		if amount != "" {
+			if currency == "" && ecoCurrency != nil {
+				currency = *ecoCurrency
+			}
+			if currency == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "reward_currency_required"})
+			}
+			var active bool
+			_ = h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM currencies WHERE code = $1 AND active)`, currency).Scan(&active)
+			if !active {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown_currency"})
+			}
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues SET reward_amount = NULLIF($3, '')::numeric, reward_currency = NULLIF($4, '')
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, amount, currency)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reward_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		out, err := h.issueReward(c, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reward_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+func (h *IssueApplicationsHandler) issueReward(c *fiber.Ctx, projectID uuid.UUID, issueNumber int) (fiber.Map, error) {
+	var amount, currency *string
+	var decimals *int
+	var lockStatus, lockAssignee, lockAmount *string
+	var lockedAt *time.Time
+	err := h.db.Pool.QueryRow(c.Context(), `
+SELECT gi.reward_amount::text, gi.reward_currency, cur.decimals,
+       l.status, l.assignee_login, l.amount::text, l.locked_at
+FROM github_issues gi
+LEFT JOIN currencies cur ON cur.code = gi.reward_currency
+LEFT JOIN LATERAL (
+  SELECT status, assignee_login, amount, locked_at FROM reward_locks
+  WHERE project_id = gi.project_id AND issue_number = gi.number
+  ORDER BY locked_at DESC LIMIT 1
+) l ON true
+WHERE gi.project_id = $1 AND gi.number = $2
+`, projectID, issueNumber).Scan(&amount, &currency, &decimals, &lockStatus, &lockAssignee, &lockAmount, &lockedAt)
+	if err != nil {
+		return nil, err
+	}
+	var escrow fiber.Map
+	if lockStatus != nil {
+		escrow = fiber.Map{"status": *lockStatus, "assignee": lockAssignee, "amount": lockAmount, "locked_at": lockedAt}
+	}
+	return fiber.Map{
+		"issue_number": issueNumber,
+		"amount":       amount,
+		"currency":     currency,
+		"decimals":     decimals,
+		"escrow":       escrow,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+	"github.com/jagadeesh/grainlify/backend/internal/waitlist"
+)
+
+type closeIssueRequest struct {
+	// Reason is completed (the default) or not_planned.
+	Reason string `json:"reason"`
+}
+
+// CloseIssue closes an issue on GitHub through the App installation and mirrors the new state locally.
+// Closing a native task as completed is what completes it. Maintainer only.
+func (h *IssueApplicationsHandler) CloseIssue() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req closeIssueRequest
+		if len(c.Body()) > 0 {
+			if err := json.Unmarshal(c.Body(), &req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+			}
+		}
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			reason = "completed"
+		}
+		if reason != "completed" && reason != "not_planned" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_reason"})
+		}
+		return h.setIssueState(c, "closed", reason)
+	}
+}
+
+// ReopenIssue reopens a closed issue on GitHub through the App installation. Maintainer only.
+func (h *IssueApplicationsHandler) ReopenIssue() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.setIssueState(c, "open", "reopened")
+	}
+}
+
+func (h *IssueApplicationsHandler) setIssueState(c *fiber.Ctx, state string, reason string) error {
+	if h.db == nil || h.db.Pool == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+	}
+
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+	}
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+	}
+	// Native tasks have no GitHub issue; their state only lives here.
+	native := tasks.IsNative(issueNumber)
+	if !native && (strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "") {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "github_app_not_configured"})
+	}
+
+	userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	var fullName, installationID, current string
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.owner_user_id, p.github_full_name, COALESCE(p.github_app_installation_id, ''), COALESCE(gi.state, '')
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number = $2
+`, projectID, issueNumber).Scan(&owner, &fullName, &installationID, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+	}
+	if !h.maintainsIssue(c.Context(), owner, userID, role, projectID, issueNumber) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+	if installationID == "" && !native {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_has_no_github_app_installation"})
+	}
+	// Closing a closed issue is allowed: it changes the reason (e.g. completed -> not planned).
+	if state == "open" && current == "open" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "issue_already_open"})
+	}
+
+	if !native {
+		appClient, err := h.apps.ForInstallation(c.Context(), installationID)
+		if err != nil {
+			slog.Error("failed to create GitHub App client for issue state change", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "github_app_client_failed"})
+		}
+		token, err := appClient.GetInstallationToken(c.Context(), installationID)
+		if err != nil {
+			slog.Warn("failed to get installation token for issue state change", "project_id", projectID.String(), "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "installation_token_failed"})
+		}
+		if err := h.gh.UpdateIssueState(c.Context(), token, fullName, issueNumber, state, reason); err != nil {
+			if errors.Is(err, github.ErrIssueGone) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found_on_github"})
+			}
+			slog.Warn("failed to change issue state on GitHub", "project_id", projectID.String(), "issue_number", issueNumber, "state", state, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "github_issue_state_failed"})
+		}
+	}
+
+	// The issues webhook will confirm this; update now so the dashboard reflects it immediately.
+	_, _ = h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues
+SET state = $3, state_reason = $4,
+    closed_at_github = CASE WHEN $3 = 'closed' THEN COALESCE(closed_at_github, now()) END,
+    updated_at_github = now(), last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, state, reason)
+
+	if native && state == "closed" {
+		// No webhook will follow, so do what the issues webhook does on close.
+		waitlist.Close(c.Context(), h.db.Pool, projectID, issueNumber)
+		if err := rewards.Settle(c.Context(), h.db.Pool, projectID, issueNumber, reason); err != nil {
+			slog.Error("failed to settle task reward", "project_id", projectID.String(), "task_number", issueNumber, "error", err)
+		}
+		if reason == "completed" {
+			h.notifyTaskCompleted(c.Context(), projectID, issueNumber)
+		}
+	}
+
+	checkruns.MarkIssueChanged(c.Context(), h.db.Pool, projectID, issueNumber)
+	h.refreshStatusComment(c.Context(), projectID, issueNumber)
+
+	return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true, "state": state, "state_reason": reason}))
+}
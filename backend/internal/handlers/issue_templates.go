@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/issuetemplates"
+)
+
+// IssueTemplatesHandler manages an ecosystem's issue templates (admin).
+type IssueTemplatesHandler struct {
+	db *db.DB
+}
+
+func NewIssueTemplatesHandler(d *db.DB) *IssueTemplatesHandler {
+	return &IssueTemplatesHandler{db: d}
+}
+
+// Ecosystem serves GET /admin/ecosystems/:id/issue-templates: the effective templates (built-ins and the
+// ecosystem's own) with the variables each uses. Admin only (route-guarded).
+func (h *IssueTemplatesHandler) Ecosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		list, err := issuetemplates.ForEcosystem(c.Context(), h.db.Pool, &ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_templates_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"templates": templatesResponse(list)})
+	}
+}
+
+// UpsertEcosystem serves PUT /admin/ecosystems/:id/issue-templates/:key. Using a built-in key overrides
+// that template within the ecosystem. Admin only (route-guarded).
+func (h *IssueTemplatesHandler) UpsertEcosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var t issuetemplates.Template
+		if err := json.Unmarshal(c.Body(), &t); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		t.Key = c.Params("key")
+		if err := issuetemplates.Validate(&t); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_template", "message": err.Error()})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO issue_templates (ecosystem_id, key, name, description, title, body, labels, points)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (ecosystem_id, key) DO UPDATE SET
+  name = EXCLUDED.name,
+  description = EXCLUDED.description,
+  title = EXCLUDED.title,
+  body = EXCLUDED.body,
+  labels = EXCLUDED.labels,
+  points = EXCLUDED.points,
+  updated_at = now()
+`, ecoID, t.Key, t.Name, t.Description, t.Title, t.Body, t.Labels, t.Points); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_template_save_failed"})
+		}
+		t.Source = "ecosystem"
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"template": templateResponse(t)})
+	}
+}
+
+// DeleteEcosystem serves DELETE /admin/ecosystems/:id/issue-templates/:key. Deleting an override brings the
+// built-in template back. Admin only (route-guarded).
+func (h *IssueTemplatesHandler) DeleteEcosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM issue_templates WHERE ecosystem_id = $1 AND key = $2
+`, ecoID, strings.ToLower(c.Params("key")))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_template_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_template_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+func (h *IssueTemplatesHandler) ecosystem(c *fiber.Ctx) (uuid.UUID, int, string) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, fiber.StatusServiceUnavailable, "db_not_configured"
+	}
+	ecoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_ecosystem_id"
+	}
+	var exists bool
+	if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "ecosystem_lookup_failed"
+	}
+	if !exists {
+		return uuid.Nil, fiber.StatusNotFound, "ecosystem_not_found"
+	}
+	return ecoID, 0, ""
+}
+
+// ProjectIssueTemplates serves GET /projects/:id/issue-templates: the templates of the project's ecosystem.
+// Maintainer only.
+func (h *IssueApplicationsHandler) ProjectIssueTemplates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		var ecosystemID *uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT ecosystem_id FROM projects WHERE id = $1`, projectID).Scan(&ecosystemID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		list, err := issuetemplates.ForEcosystem(c.Context(), h.db.Pool, ecosystemID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_templates_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"templates":           templatesResponse(list),
+			"automatic_variables": issuetemplates.Automatic,
+		})
+	}
+}
+
+type templateDraftRequest struct {
+	Variables map[string]string `json:"variables"`
+	// Labels and Points replace the template's when set.
+	Labels *[]string `json:"labels"`
+	Points *int      `json:"points"`
+	// AllowMissing creates the draft even when variables have no value; they stay as {{name}} to fill in.
+	AllowMissing bool `json:"allow_missing"`
+	Publish      bool `json:"publish"`
+}
+
+// DraftFromTemplate serves POST /projects/:id/issue-templates/:key/draft: instantiates a template into an
+// issue draft, optionally publishing it in the same call. {{project}}, {{repo}} and {{ecosystem}} are
+// filled from the project. Maintainer only.
+func (h *IssueApplicationsHandler) DraftFromTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req templateDraftRequest
+		if len(c.Body()) > 0 {
+			if err := json.Unmarshal(c.Body(), &req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+			}
+		}
+		projectID, userID, status, body := h.draftProject(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+
+		var fullName string
+		var ecosystemID *uuid.UUID
+		var ecosystemName *string
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT p.github_full_name, p.ecosystem_id, e.name
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, projectID).Scan(&fullName, &ecosystemID, &ecosystemName)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		list, err := issuetemplates.ForEcosystem(c.Context(), h.db.Pool, ecosystemID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_templates_lookup_failed"})
+		}
+		t, ok := issuetemplates.Find(list, strings.ToLower(c.Params("key")))
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_template_not_found"})
+		}
+
+		automatic := map[string]string{"project": fullName, "repo": fullName}
+		if i := strings.Index(fullName, "/"); i >= 0 {
+			automatic["project"] = fullName[i+1:]
+		}
+		if ecosystemName != nil {
+			automatic["ecosystem"] = *ecosystemName
+		}
+		title, text, missing := t.Instantiate(bottemplate.Merge(automatic, req.Variables))
+		if len(missing) > 0 && !req.AllowMissing {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing_variables", "variables": missing})
+		}
+
+		draft := issueDraftRequest{Title: &title, Body: &text, Labels: &t.Labels, Points: t.Points}
+		if req.Labels != nil {
+			draft.Labels = req.Labels
+		}
+		if req.Points != nil {
+			draft.Points = req.Points
+		}
+		if code := draft.validate(true); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		return h.createIssueDraft(c, projectID, userID, *draft.Title, *draft.Body, *draft.Labels, draft.Points, req.Publish)
+	}
+}
+
+func templatesResponse(list []issuetemplates.Template) []fiber.Map {
+	out := make([]fiber.Map, 0, len(list))
+	for _, t := range list {
+		out = append(out, templateResponse(t))
+	}
+	return out
+}
+
+func templateResponse(t issuetemplates.Template) fiber.Map {
+	labels := t.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return fiber.Map{
+		"key":         t.Key,
+		"name":        t.Name,
+		"description": t.Description,
+		"title":       t.Title,
+		"body":        t.Body,
+		"labels":      labels,
+		"points":      t.Points,
+		"source":      t.Source,
+		"variables":   t.Variables(),
+	}
+}
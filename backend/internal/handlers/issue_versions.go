@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/ingest"
+)
+
+// issueVersion reads github_issues.row_version before a GitHub call whose result the caller stores, for
+// setIssueAssignees.
+func (h *IssueApplicationsHandler) issueVersion(ctx context.Context, projectID uuid.UUID, issueNumber int) int64 {
+	var v int64
+	_ = h.db.Pool.QueryRow(ctx, `SELECT row_version FROM github_issues WHERE project_id = $1 AND number = $2`, projectID, issueNumber).Scan(&v)
+	return v
+}
+
+// setIssueAssignees stores the assignees a handler just set on GitHub, unless a webhook or sync changed the
+// issue since version was read. Then the handler's view is stale (GitHub may hold other assignees too),
+// so the issue is re-fetched from GitHub instead.
+func (h *IssueApplicationsHandler) setIssueAssignees(ctx context.Context, token, fullName string, projectID uuid.UUID, issueNumber int, version int64, assigneesJSON []byte) {
+	ct, err := h.db.Pool.Exec(ctx, `
+UPDATE github_issues SET assignees = $3::jsonb, last_seen_at = now()
+WHERE project_id = $1 AND number = $2 AND row_version = $4
+`, projectID, issueNumber, assigneesJSON, version)
+	if err != nil || ct.RowsAffected() > 0 {
+		return
+	}
+	if err := ingest.RefreshIssue(ctx, h.db.Pool, h.gh, token, fullName, projectID, issueNumber); err != nil {
+		slog.Warn("failed to refresh issue after concurrent update",
+			"project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+	}
+}
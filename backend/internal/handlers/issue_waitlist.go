@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+)
+
+// JoinWaitlist queues the caller for an issue that is currently assigned to someone else.
+func (h *IssueApplicationsHandler) JoinWaitlist() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var login string
+		if err := h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1`, userID).Scan(&login); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		}
+
+		var state string
+		var assigneesJSON []byte
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT gi.state, COALESCE(gi.assignees, '[]'::jsonb)
+FROM projects p
+JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL AND gi.number = $2
+`, projectID, issueNumber).Scan(&state, &assigneesJSON)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+		if strings.ToLower(strings.TrimSpace(state)) != "open" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issue_not_open"})
+		}
+		var assignees []struct {
+			Login string `json:"login"`
+		}
+		_ = json.Unmarshal(assigneesJSON, &assignees)
+		if len(assignees) == 0 {
+			// Unassigned issues take applications, not a waitlist.
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issue_not_assigned"})
+		}
+		for _, a := range assignees {
+			if strings.EqualFold(a.Login, login) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "already_assigned"})
+			}
+		}
+
+		var id uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO issue_waitlist (project_id, issue_number, user_id, github_login)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING
+RETURNING id
+`, projectID, issueNumber, userID, login).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "already_waitlisted"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "waitlist_join_failed"})
+		}
+
+		var position int
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT COUNT(*) FROM issue_waitlist
+WHERE project_id = $1 AND issue_number = $2 AND status = 'waiting'
+  AND created_at <= (SELECT created_at FROM issue_waitlist WHERE id = $3)
+`, projectID, issueNumber, id).Scan(&position)
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String(), "position": position})
+	}
+}
+
+// LeaveWaitlist removes the caller from an issue's waitlist.
+func (h *IssueApplicationsHandler) LeaveWaitlist() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_waitlist SET status = 'left', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND user_id = $3 AND status IN ('waiting', 'notified')
+`, projectID, issueNumber, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "waitlist_leave_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "not_waitlisted"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Waitlist returns the active waitlist of an issue in queue order.
+func (h *IssueApplicationsHandler) Waitlist() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT github_login, status, notified_at, created_at
+FROM issue_waitlist
+WHERE project_id = $1 AND issue_number = $2 AND status IN ('waiting', 'notified')
+ORDER BY (status = 'notified') DESC, created_at
+`, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "waitlist_lookup_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var login, status string
+			var notifiedAt *time.Time
+			var createdAt time.Time
+			if err := rows.Scan(&login, &status, &notifiedAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "waitlist_lookup_failed"})
+			}
+			out = append(out, fiber.Map{
+				"position":     len(out) + 1,
+				"github_login": login,
+				"status":       status,
+				"notified_at":  notifiedAt,
+				"joined_at":    createdAt,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"waitlist": out})
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxBatchIssues caps one POST /issues/batch.
+const maxBatchIssues = 100
+
+type issueRef struct {
+	ProjectID string `json:"project_id"`
+	Number    int    `json:"number"`
+}
+
+type issuesBatchRequest struct {
+	Issues []issueRef `json:"issues"`
+}
+
+// IssuesBatch serves POST /issues/batch: the issues named by (project_id, number) pairs, in one response,
+// for views that show issues from many projects (a contributor's applications, bookmarks). Items are as
+// in IssuesPublic plus project_id, in request order; pairs naming no issue of a verified project are
+// listed under not_found. ?fields= and ?include=labels work as for IssuesPublic.
+func (h *ProjectsPublicHandler) IssuesBatch() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req issuesBatchRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if len(req.Issues) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issues_required"})
+		}
+		if len(req.Issues) > maxBatchIssues {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "too_many_issues", "max": maxBatchIssues})
+		}
+		fields, ok := requestFields(c, "labels")
+		if !ok {
+			return nil
+		}
+
+		projectIDs := make([]uuid.UUID, len(req.Issues))
+		numbers := make([]int32, len(req.Issues))
+		for i, ref := range req.Issues {
+			id, err := uuid.Parse(ref.ProjectID)
+			if err != nil || ref.Number <= 0 || ref.Number > math.MaxInt32 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_ref", "index": i})
+			}
+			projectIDs[i], numbers[i] = id, int32(ref.Number)
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT r.ord, gi.project_id, gi.github_issue_id, gi.number, gi.state, gi.title, CASE WHEN $3 THEN gi.body END,
+       gi.author_login, gi.url, CASE WHEN $4 THEN gi.labels END, gi.updated_at_github, gi.last_seen_at,
+       gi.is_mentored, gi.deadline_at
+FROM unnest($1::uuid[], $2::int[]) WITH ORDINALITY AS r(project_id, number, ord)
+JOIN projects p ON p.id = r.project_id AND p.status = 'verified' AND p.deleted_at IS NULL
+JOIN github_issues gi ON gi.project_id = r.project_id AND gi.number = r.number
+ORDER BY r.ord
+`, projectIDs, numbers, fields.Wants("description"), fields.Wants("labels"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_batch_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		found := make([]bool, len(req.Issues))
+		for rows.Next() {
+			var ord int
+			var projectID uuid.UUID
+			var gid int64
+			var number int
+			var state, title, author, url string
+			var body *string
+			var labelsJSON []byte
+			var updated *time.Time
+			var lastSeen time.Time
+			var mentored bool
+			var deadline *time.Time
+			if err := rows.Scan(&ord, &projectID, &gid, &number, &state, &title, &body, &author, &url, &labelsJSON, &updated, &lastSeen, &mentored, &deadline); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_batch_failed"})
+			}
+			found[ord-1] = true
+
+			var labels []any
+			if len(labelsJSON) > 0 {
+				_ = json.Unmarshal(labelsJSON, &labels)
+			}
+			out = append(out, fields.Apply(fiber.Map{
+				"project_id":      projectID,
+				"github_issue_id": gid,
+				"number":          number,
+				"state":           state,
+				"title":           title,
+				"description":     body,
+				"author_login":    author,
+				"labels":          labels,
+				"url":             url,
+				"updated_at":      updated,
+				"last_seen_at":    lastSeen,
+				"mentored":        mentored,
+				"deadline_at":     deadline,
+			}))
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_batch_failed"})
+		}
+
+		notFound := []issueRef{}
+		for i, ok := range found {
+			if !ok {
+				notFound = append(notFound, req.Issues[i])
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"issues": out, "not_found": notFound})
+	}
+}
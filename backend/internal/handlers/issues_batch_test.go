@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/migrate"
+)
+
+func postIssuesBatch(t *testing.T, pool *pgxpool.Pool, refs []issueRef) (int, map[string]any) {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/issues/batch", NewProjectsPublicHandler(config.Config{}, &db.DB{Pool: pool}, nil, nil).IssuesBatch())
+
+	body, _ := json.Marshal(issuesBatchRequest{Issues: refs})
+	req := httptest.NewRequest("POST", "/issues/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, out
+}
+
+func randomRefs(n int) []issueRef {
+	out := make([]issueRef, n)
+	for i := range out {
+		out[i] = issueRef{ProjectID: uuid.NewString(), Number: i + 1}
+	}
+	return out
+}
+
+func TestIssuesBatchLimits(t *testing.T) {
+	// The pool never connects: requests rejected before the query don't need a database, and those that
+	// get to it fail with issues_batch_failed.
+	pool, err := pgxpool.New(context.Background(), "postgres://grainlify@127.0.0.1:1/grainlify?connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	cases := []struct {
+		name   string
+		refs   []issueRef
+		status int
+		code   string
+	}{
+		{"empty", nil, fiber.StatusBadRequest, "issues_required"},
+		{"over the limit", randomRefs(maxBatchIssues + 1), fiber.StatusBadRequest, "too_many_issues"},
+		{"at the limit", randomRefs(maxBatchIssues), fiber.StatusInternalServerError, "issues_batch_failed"},
+		{"bad project id", []issueRef{{ProjectID: "nope", Number: 1}}, fiber.StatusBadRequest, "invalid_issue_ref"},
+		{"bad number", []issueRef{{ProjectID: uuid.NewString()}}, fiber.StatusBadRequest, "invalid_issue_ref"},
+		{"number too large", []issueRef{{ProjectID: uuid.NewString(), Number: math.MaxInt32 + 1}}, fiber.StatusBadRequest, "invalid_issue_ref"},
+	}
+	for _, tc := range cases {
+		status, out := postIssuesBatch(t, pool, tc.refs)
+		if status != tc.status || out["error"] != tc.code {
+			t.Errorf("%s: got %d %v, want %d %s", tc.name, status, out["error"], tc.status, tc.code)
+		}
+	}
+	if _, out := postIssuesBatch(t, pool, randomRefs(maxBatchIssues+1)); out["max"] != float64(maxBatchIssues) {
+		t.Errorf("too_many_issues max = %v", out["max"])
+	}
+}
+
+// TestIssuesBatch_Integration needs a disposable Postgres database in TEST_DATABASE_URL; it applies the
+// migrations and adds (then removes) a user, two projects and their issues.
+func TestIssuesBatch_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+	}
+	ctx := context.Background()
+	d, err := db.Connect(ctx, dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if err := migrate.Up(ctx, d.Pool); err != nil {
+		t.Fatal(err)
+	}
+
+	var owner uuid.UUID
+	if err := d.Pool.QueryRow(ctx, `INSERT INTO users DEFAULT VALUES RETURNING id`).Scan(&owner); err != nil {
+		t.Fatal(err)
+	}
+	project := func(status string) uuid.UUID {
+		var id uuid.UUID
+		if err := d.Pool.QueryRow(ctx, `
+INSERT INTO projects (owner_user_id, github_full_name, status) VALUES ($1, $2, $3) RETURNING id
+`, owner, "batch-test/"+uuid.NewString(), status).Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		for n := 1; n <= 3; n++ {
+			if _, err := d.Pool.Exec(ctx, `
+INSERT INTO github_issues (project_id, github_issue_id, number, state, title, author_login, url)
+VALUES ($1, $2, $3, 'open', $4, 'ada', '')
+`, id, int64(id.ID())*10+int64(n), n, fmt.Sprintf("issue %d", n)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return id
+	}
+	verified, unverified := project("verified"), project("pending_verification")
+	t.Cleanup(func() {
+		_, _ = d.Pool.Exec(ctx, `DELETE FROM projects WHERE id = ANY($1)`, []uuid.UUID{verified, unverified})
+		_, _ = d.Pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, owner)
+	})
+
+	missingProject := issueRef{ProjectID: uuid.NewString(), Number: 1}
+	status, out := postIssuesBatch(t, d.Pool, []issueRef{
+		{ProjectID: verified.String(), Number: 3},
+		{ProjectID: unverified.String(), Number: 1},
+		{ProjectID: verified.String(), Number: 1},
+		missingProject,
+		{ProjectID: verified.String(), Number: 99},
+		{ProjectID: verified.String(), Number: 2},
+	})
+	if status != fiber.StatusOK {
+		t.Fatalf("status %d: %v", status, out)
+	}
+
+	// Found issues come back in request order, not table or number order.
+	issues, _ := out["issues"].([]any)
+	var got []float64
+	for _, it := range issues {
+		item := it.(map[string]any)
+		if item["project_id"] != verified.String() {
+			t.Errorf("issue from project %v", item["project_id"])
+		}
+		got = append(got, item["number"].(float64))
+	}
+	if fmt.Sprint(got) != "[3 1 2]" {
+		t.Errorf("numbers = %v, want [3 1 2]", got)
+	}
+
+	// Unverified and missing projects, and missing issues, are not_found, in request order.
+	raw, _ := json.Marshal(out["not_found"])
+	var notFound []issueRef
+	if err := json.Unmarshal(raw, &notFound); err != nil {
+		t.Fatal(err)
+	}
+	want := []issueRef{
+		{ProjectID: unverified.String(), Number: 1},
+		missingProject,
+		{ProjectID: verified.String(), Number: 99},
+	}
+	if !reflect.DeepEqual(notFound, want) {
+		t.Errorf("not_found = %+v, want %+v", notFound, want)
+	}
+}
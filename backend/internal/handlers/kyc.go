@@ -14,6 +14,7 @@ import (
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/didit"
+	"github.com/jagadeesh/grainlify/backend/internal/kyc"
 )
 
 // extractKYCInfo extracts structured information from Didit response data
@@ -98,17 +99,21 @@ type KYCHandler struct {
 	cfg   config.Config
 	db    *db.DB
 	didit *didit.Client
+	// provider, when configured, verifies users instead of Didit (see kyc_provider.go).
+	provider kyc.Provider
 }
 
 func NewKYCHandler(cfg config.Config, d *db.DB) *KYCHandler {
+	provider := kyc.FromSettings(cfg.KYCProviderURL, cfg.KYCProviderToken, cfg.KYCWebhookSecret)
 	var diditClient *didit.Client
-	if cfg.DiditAPIKey != "" {
+	if cfg.DiditAPIKey != "" && provider == nil {
 		diditClient = didit.NewClient(cfg.DiditAPIKey)
 	}
 	return &KYCHandler{
-		cfg:   cfg,
-		db:    d,
-		didit: diditClient,
+		cfg:      cfg,
+		db:       d,
+		didit:    diditClient,
+		provider: provider,
 	}
 }
 
@@ -118,6 +123,9 @@ func (h *KYCHandler) Start() fiber.Handler {
 		if h.db == nil || h.db.Pool == nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
 		}
+		if h.provider != nil {
+			return h.startWithProvider(c)
+		}
 		if h.didit == nil {
 			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "kyc_not_configured", "message": "DIDIT_API_KEY and DIDIT_WORKFLOW_ID must be set"})
 		}
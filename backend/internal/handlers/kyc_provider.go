@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/kyc"
+)
+
+const kycNotRequired = "not_required"
+
+// startWithProvider is Start for a configured KYC provider. A user with a session still open, or already
+// verified, gets 409 kyc_session_exists; one whose session was rejected or expired can start over.
+func (h *KYCHandler) startWithProvider(c *fiber.Ctx) error {
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+	}
+	if h.cfg.PublicBaseURL == "" {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "kyc_not_configured", "message": "PUBLIC_BASE_URL must be set"})
+	}
+
+	var sessionID, status *string
+	var data []byte
+	if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT kyc_session_id, kyc_status, kyc_data FROM users WHERE id = $1
+`, userID).Scan(&sessionID, &status, &data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_lookup_failed"})
+	}
+	if sessionID != nil && status != nil && *status != kyc.StatusRejected && *status != kyc.StatusExpired {
+		var stored struct {
+			SessionURL string `json:"session_url"`
+		}
+		_ = json.Unmarshal(data, &stored)
+		response := fiber.Map{
+			"error":      "kyc_session_exists",
+			"message":    fmt.Sprintf("You already have a KYC verification session (status: %s).", *status),
+			"session_id": *sessionID,
+			"status":     *status,
+		}
+		if stored.SessionURL != "" {
+			response["url"] = stored.SessionURL
+		}
+		return c.Status(fiber.StatusConflict).JSON(response)
+	}
+
+	baseURL := strings.TrimRight(h.cfg.PublicBaseURL, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "https://" + baseURL
+	}
+	session, err := h.provider.StartSession(c.Context(), userID, baseURL+"/webhooks/kyc")
+	if err != nil {
+		slog.Error("kyc provider create session failed", "error", err, "provider", h.provider.Name(), "user_id", userID)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "kyc_session_create_failed"})
+	}
+
+	sessionData, _ := json.Marshal(map[string]any{"session_url": session.URL})
+	if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE users
+SET kyc_session_id = $2,
+    kyc_status = 'not_started',
+    kyc_data = $3,
+    kyc_provider = $4,
+    kyc_updated_at = now(),
+    updated_at = now()
+WHERE id = $1
+`, userID, session.ID, sessionData, h.provider.Name()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "kyc_session_store_failed"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"session_id": session.ID, "url": session.URL})
+}
+
+// Webhook serves POST /webhooks/kyc: the configured provider reporting a session's outcome.
+func (h *KYCHandler) Webhook() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.provider == nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "kyc_not_configured"})
+		}
+		header := http.Header{}
+		c.Request().Header.VisitAll(func(k, v []byte) {
+			header.Add(string(k), string(v))
+		})
+		ev, err := h.provider.ParseWebhook(header, c.Body())
+		if errors.Is(err, kyc.ErrInvalidSignature) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_signature"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_event"})
+		}
+		userID, err := kyc.Record(c.Context(), h.db.Pool, h.provider.Name(), ev)
+		if err != nil {
+			slog.Error("failed to record kyc event", "error", err, "session_id", ev.SessionID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "kyc_update_failed"})
+		}
+		if userID == uuid.Nil {
+			// Not ours, or a session that was since replaced; acknowledge so the provider stops retrying.
+			slog.Warn("kyc webhook for unknown session", "session_id", ev.SessionID)
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "ignored": true})
+		}
+		slog.Info("kyc status updated", "user_id", userID, "status", ev.Status, "provider", h.provider.Name())
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
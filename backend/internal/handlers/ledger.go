@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/ledger"
+)
+
+// LedgerHandler serves the double-entry ledger: contributors' own balances, and accounts, transactions
+// and an integrity check for admins.
+type LedgerHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewLedgerHandler(cfg config.Config, d *db.DB) *LedgerHandler {
+	return &LedgerHandler{cfg: cfg, db: d}
+}
+
+type ledgerAccount struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	Owner     string    `json:"owner"`
+	Asset     string    `json:"asset"`
+	Decimals  *int      `json:"decimals"` // nil for points
+	Balance   string    `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ledgerEntry struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Kind      string    `json:"account_kind"`
+	Owner     string    `json:"owner"`
+	Asset     string    `json:"asset"`
+	Amount    string    `json:"amount"` // positive credits the account, negative debits it
+}
+
+type ledgerTransaction struct {
+	ID          uuid.UUID     `json:"id"`
+	Kind        string        `json:"kind"`
+	EcosystemID *uuid.UUID    `json:"ecosystem_id"`
+	ProjectID   *uuid.UUID    `json:"project_id"`
+	IssueNumber *int          `json:"issue_number"`
+	Memo        *string       `json:"memo"`
+	CreatedBy   *uuid.UUID    `json:"created_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Entries     []ledgerEntry `json:"entries"`
+}
+
+const ledgerAccountColumns = `a.id, a.kind, a.owner, a.asset, c.decimals, a.balance::text, a.updated_at`
+
+func listLedgerAccounts(ctx context.Context, pool *pgxpool.Pool, where string, args ...any) ([]ledgerAccount, error) {
+	rows, err := pool.Query(ctx, `
+SELECT `+ledgerAccountColumns+`
+FROM ledger_accounts a
+LEFT JOIN currencies c ON c.code = a.asset
+WHERE `+where+`
+ORDER BY a.kind, a.owner, a.asset
+LIMIT 1000
+`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ledgerAccount{}
+	for rows.Next() {
+		var a ledgerAccount
+		if err := rows.Scan(&a.ID, &a.Kind, &a.Owner, &a.Asset, &a.Decimals, &a.Balance, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ledgerFilter narrows listLedgerTransactions; zero fields don't filter.
+type ledgerFilter struct {
+	ecosystemID *uuid.UUID
+	accountIDs  []uuid.UUID
+	kind        string
+	limit       int
+}
+
+// listLedgerTransactions returns the newest transactions matching f, each with all its entries.
+func listLedgerTransactions(ctx context.Context, pool *pgxpool.Pool, f ledgerFilter) ([]ledgerTransaction, error) {
+	if f.limit < 1 || f.limit > 1000 {
+		f.limit = 100
+	}
+	rows, err := pool.Query(ctx, `
+SELECT t.id, t.kind, t.ecosystem_id, t.project_id, t.issue_number, t.memo, t.created_by, t.created_at
+FROM ledger_transactions t
+WHERE ($1::uuid IS NULL OR t.ecosystem_id = $1)
+  AND (NULLIF($2, '') IS NULL OR t.kind = $2)
+  AND ($3::uuid[] IS NULL OR EXISTS (SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.id AND e.account_id = ANY($3)))
+ORDER BY t.created_at DESC, t.id
+LIMIT $4
+`, f.ecosystemID, f.kind, f.accountIDs, f.limit)
+	if err != nil {
+		return nil, err
+	}
+	out := []ledgerTransaction{}
+	index := map[uuid.UUID]int{}
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var t ledgerTransaction
+		if err := rows.Scan(&t.ID, &t.Kind, &t.EcosystemID, &t.ProjectID, &t.IssueNumber, &t.Memo, &t.CreatedBy, &t.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.Entries = []ledgerEntry{}
+		index[t.ID] = len(out)
+		ids = append(ids, t.ID)
+		out = append(out, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(ids) == 0 {
+		return out, err
+	}
+
+	rows, err = pool.Query(ctx, `
+SELECT e.transaction_id, a.id, a.kind, a.owner, a.asset, e.amount::text
+FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+WHERE e.transaction_id = ANY($1)
+ORDER BY e.id
+`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var txID uuid.UUID
+		var e ledgerEntry
+		if err := rows.Scan(&txID, &e.AccountID, &e.Kind, &e.Owner, &e.Asset, &e.Amount); err != nil {
+			return nil, err
+		}
+		i := index[txID]
+		out[i].Entries = append(out[i].Entries, e)
+	}
+	return out, rows.Err()
+}
+
+// MyBalances serves GET /me/balances: the signed-in contributor's points and what they are owed per
+// currency (released rewards not yet paid out), with the transactions behind them.
+func (h *LedgerHandler) MyBalances() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		accounts, err := listLedgerAccounts(c.Context(), h.db.Pool,
+			`a.kind = $1 AND a.owner IN (SELECT LOWER(login) FROM github_accounts WHERE user_id = $2)`,
+			ledger.AccountContributor, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "balances_fetch_failed"})
+		}
+		ids := make([]uuid.UUID, 0, len(accounts))
+		for _, a := range accounts {
+			ids = append(ids, a.ID)
+		}
+		txs := []ledgerTransaction{}
+		if len(ids) > 0 {
+			txs, err = listLedgerTransactions(c.Context(), h.db.Pool, ledgerFilter{accountIDs: ids, limit: c.QueryInt("limit", 100)})
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "balances_fetch_failed"})
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"accounts": accounts, "transactions": txs})
+	}
+}
+
+// Accounts serves GET /admin/ledger/accounts. ?kind=, ?owner= and ?asset= filter.
+func (h *LedgerHandler) Accounts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		accounts, err := listLedgerAccounts(c.Context(), h.db.Pool, `
+(NULLIF($1, '') IS NULL OR a.kind = $1) AND (NULLIF($2, '') IS NULL OR a.owner = LOWER($2))
+AND (NULLIF($3, '') IS NULL OR a.asset = UPPER($3))`,
+			c.Query("kind"), c.Query("owner"), c.Query("asset"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"accounts": accounts})
+	}
+}
+
+// Transactions serves GET /admin/ledger/transactions, newest first. ?account_id=, ?ecosystem_id=, ?kind=
+// and ?limit= (at most 1000) filter.
+func (h *LedgerHandler) Transactions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		f := ledgerFilter{kind: c.Query("kind"), limit: c.QueryInt("limit", 100)}
+		if s := c.Query("account_id"); s != "" {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_account_id"})
+			}
+			f.accountIDs = []uuid.UUID{id}
+		}
+		if s := c.Query("ecosystem_id"); s != "" {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_ecosystem_id"})
+			}
+			f.ecosystemID = &id
+		}
+		txs, err := listLedgerTransactions(c.Context(), h.db.Pool, f)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"transactions": txs})
+	}
+}
+
+// Check serves GET /admin/ledger/check: recomputes every balance from its entries and every
+// transaction's sum per asset, and lists what doesn't add up. ok is true when nothing does.
+func (h *LedgerHandler) Check() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT a.id, a.balance::text, COALESCE(SUM(e.amount), 0)::text
+FROM ledger_accounts a
+LEFT JOIN ledger_entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+		}
+		drifted := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var balance, entries string
+			if err := rows.Scan(&id, &balance, &entries); err != nil {
+				rows.Close()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+			}
+			drifted = append(drifted, fiber.Map{"account_id": id, "balance": balance, "sum_of_entries": entries})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+		}
+
+		rows, err = h.db.Pool.Query(c.Context(), `
+SELECT e.transaction_id, a.asset, SUM(e.amount)::text
+FROM ledger_entries e
+JOIN ledger_accounts a ON a.id = e.account_id
+GROUP BY e.transaction_id, a.asset
+HAVING SUM(e.amount) <> 0
+`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+		}
+		defer rows.Close()
+		unbalanced := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var asset, sum string
+			if err := rows.Scan(&id, &asset, &sum); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+			}
+			unbalanced = append(unbalanced, fiber.Map{"transaction_id": id, "asset": asset, "sum": sum})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ledger_check_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"ok":                      len(drifted) == 0 && len(unbalanced) == 0,
+			"drifted_accounts":        drifted,
+			"unbalanced_transactions": unbalanced,
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/fieldset"
+)
+
+// requestFields parses ?fields= and ?include= for list endpoints; expandable names the fields ?include=
+// chooses from. ok is false when either was invalid and a 400 has been sent.
+func requestFields(c *fiber.Ctx, expandable ...string) (fieldset.Set, bool) {
+	fields, err := fieldset.Parse(c.Query("fields"))
+	if err == nil {
+		fields, err = fields.WithIncludes(c.Query("include"), expandable...)
+	}
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_fields", "message": err.Error()})
+		return fieldset.Set{}, false
+	}
+	return fields, true
+}
@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+type MentorshipHandler struct {
+	db *db.DB
+}
+
+func NewMentorshipHandler(d *db.DB) *MentorshipHandler {
+	return &MentorshipHandler{db: d}
+}
+
+type setIssueMentoredRequest struct {
+	Mentored     bool    `json:"mentored"`
+	MentorUserID *string `json:"mentor_user_id"`
+}
+
+// SetIssueMentored lets a project maintainer flag (or unflag) an issue as mentored.
+// The mentor defaults to the caller; anyone else must be a project member (its owner or a sub-project
+// maintainer).
+func (h *MentorshipHandler) SetIssueMentored() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		issueNumber, err := c.ParamsInt("number")
+		if err != nil || issueNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		var req setIssueMentoredRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		var mentorID *uuid.UUID
+		if req.Mentored {
+			m := userID
+			if req.MentorUserID != nil && strings.TrimSpace(*req.MentorUserID) != "" {
+				m, err = uuid.Parse(strings.TrimSpace(*req.MentorUserID))
+				if err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_mentor_user_id"})
+				}
+			}
+			if m != userID {
+				var exists, member bool
+				err := h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS (SELECT 1 FROM users WHERE id = $2),
+       EXISTS (SELECT 1 FROM projects WHERE id = $1 AND owner_user_id = $2)
+       OR EXISTS (
+         SELECT 1 FROM project_subprojects s
+         JOIN subproject_maintainers sm ON sm.subproject_id = s.id
+         WHERE s.project_id = $1 AND sm.user_id = $2
+       )
+`, projectID, m).Scan(&exists, &member)
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentor_lookup_failed"})
+				}
+				if !exists {
+					return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mentor_not_found"})
+				}
+				if !member {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "mentor_not_project_member"})
+				}
+			}
+			mentorID = &m
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues
+SET is_mentored = $3, mentor_user_id = $4
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber, req.Mentored, mentorID)
+		if err != nil {
+			slog.Error("failed to update mentored flag", "error", err, "project_id", projectID, "issue_number", issueNumber)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "mentored": req.Mentored, "mentor_user_id": mentorID})
+	}
+}
+
+type requestMentorRequest struct {
+	ProjectID   string `json:"project_id"`
+	IssueNumber *int   `json:"issue_number"`
+	Message     string `json:"message"`
+}
+
+// Request creates a mentorship request. The mentor is the issue's assigned mentor when the
+// issue is mentored, otherwise the project owner.
+func (h *MentorshipHandler) Request() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req requestMentorRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		projectID, err := uuid.Parse(strings.TrimSpace(req.ProjectID))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		req.Message = strings.TrimSpace(req.Message)
+		if len(req.Message) > 2000 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "message_too_long"})
+		}
+
+		var mentorID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&mentorID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+
+		if req.IssueNumber != nil {
+			var isMentored bool
+			var issueMentor *uuid.UUID
+			err = h.db.Pool.QueryRow(c.Context(), `
+SELECT is_mentored, mentor_user_id
+FROM github_issues
+WHERE project_id = $1 AND number = $2
+`, projectID, *req.IssueNumber).Scan(&isMentored, &issueMentor)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+			}
+			if isMentored && issueMentor != nil {
+				mentorID = *issueMentor
+			}
+		}
+
+		if mentorID == userID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot_mentor_self"})
+		}
+
+		var msg *string
+		if req.Message != "" {
+			msg = &req.Message
+		}
+
+		var id uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO mentorships (mentor_user_id, mentee_user_id, project_id, issue_number, message)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (mentor_user_id, mentee_user_id, project_id) WHERE status IN ('requested', 'active') DO NOTHING
+RETURNING id
+`, mentorID, userID, projectID, req.IssueNumber, msg).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "mentorship_already_open"})
+		}
+		if err != nil {
+			slog.Error("failed to create mentorship request", "error", err, "project_id", projectID, "user_id", userID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_request_failed"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"id":             id.String(),
+			"mentor_user_id": mentorID.String(),
+			"status":         "requested",
+		})
+	}
+}
+
+// Mine lists mentorships where the current user is the mentor or the mentee.
+func (h *MentorshipHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT m.id, m.mentor_user_id, COALESCE(mg.login, ''), m.mentee_user_id, COALESCE(eg.login, ''),
+  m.project_id, p.github_full_name, m.issue_number, m.status, m.message, m.created_at, m.updated_at
+FROM mentorships m
+JOIN projects p ON p.id = m.project_id
+LEFT JOIN github_accounts mg ON mg.user_id = m.mentor_user_id
+LEFT JOIN github_accounts eg ON eg.user_id = m.mentee_user_id
+WHERE m.mentor_user_id = $1 OR m.mentee_user_id = $1
+ORDER BY m.updated_at DESC
+LIMIT 200
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorships_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id, mentorID, menteeID, projectID uuid.UUID
+			var mentorLogin, menteeLogin, fullName, status string
+			var issueNumber *int
+			var message *string
+			var createdAt, updatedAt time.Time
+			if err := rows.Scan(&id, &mentorID, &mentorLogin, &menteeID, &menteeLogin, &projectID, &fullName, &issueNumber, &status, &message, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorships_list_failed"})
+			}
+			myRole := "mentee"
+			if mentorID == userID {
+				myRole = "mentor"
+			}
+			out = append(out, fiber.Map{
+				"id":               id.String(),
+				"role":             myRole,
+				"mentor_user_id":   mentorID.String(),
+				"mentor_login":     mentorLogin,
+				"mentee_user_id":   menteeID.String(),
+				"mentee_login":     menteeLogin,
+				"project_id":       projectID.String(),
+				"github_full_name": fullName,
+				"issue_number":     issueNumber,
+				"status":           status,
+				"message":          message,
+				"created_at":       createdAt,
+				"updated_at":       updatedAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"mentorships": out})
+	}
+}
+
+// Transition moves a mentorship to a new status. Only the mentor can accept or decline a
+// request; either participant can mark an active mentorship completed.
+func (h *MentorshipHandler) Transition(to string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_mentorship_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var mentorID, menteeID uuid.UUID
+		var status string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT mentor_user_id, mentee_user_id, status FROM mentorships WHERE id = $1
+`, id).Scan(&mentorID, &menteeID, &status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mentorship_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_lookup_failed"})
+		}
+
+		switch to {
+		case "active", "declined":
+			if userID != mentorID {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+			if status != "requested" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invalid_status_transition"})
+			}
+		case "completed":
+			if userID != mentorID && userID != menteeID {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+			}
+			if status != "active" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invalid_status_transition"})
+			}
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_status"})
+		}
+
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE mentorships SET status = $2, updated_at = now() WHERE id = $1
+`, id, to); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_update_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": to})
+	}
+}
+
+// Notes lists session notes for a mentorship. Participants only.
+func (h *MentorshipHandler) Notes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_mentorship_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var isParticipant bool
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM mentorships WHERE id = $1 AND (mentor_user_id = $2 OR mentee_user_id = $2))
+`, id, userID).Scan(&isParticipant); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_lookup_failed"})
+		}
+		if !isParticipant {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mentorship_not_found"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT n.id, n.author_user_id, COALESCE(ga.login, ''), n.body, n.session_at, n.created_at
+FROM mentorship_notes n
+LEFT JOIN github_accounts ga ON ga.user_id = n.author_user_id
+WHERE n.mentorship_id = $1
+ORDER BY n.created_at ASC
+`, id)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_notes_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var noteID, authorID uuid.UUID
+			var login, body string
+			var sessionAt *time.Time
+			var createdAt time.Time
+			if err := rows.Scan(&noteID, &authorID, &login, &body, &sessionAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_notes_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":             noteID.String(),
+				"author_user_id": authorID.String(),
+				"author_login":   login,
+				"body":           body,
+				"session_at":     sessionAt,
+				"created_at":     createdAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"notes": out})
+	}
+}
+
+type addMentorshipNoteRequest struct {
+	Body      string     `json:"body"`
+	SessionAt *time.Time `json:"session_at"`
+}
+
+// AddNote records a session note on an active or completed mentorship.
+func (h *MentorshipHandler) AddNote() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_mentorship_id"})
+		}
+		userIDStr, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req addMentorshipNoteRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+		req.Body = strings.TrimSpace(req.Body)
+		if req.Body == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_required"})
+		}
+		if len(req.Body) > 10000 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "body_too_long"})
+		}
+
+		var status string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT status FROM mentorships WHERE id = $1 AND (mentor_user_id = $2 OR mentee_user_id = $2)
+`, id, userID).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mentorship_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_lookup_failed"})
+		}
+		if status != "active" && status != "completed" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "mentorship_not_active"})
+		}
+
+		var noteID uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO mentorship_notes (mentorship_id, author_user_id, body, session_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id
+`, id, userID, req.Body, req.SessionAt).Scan(&noteID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "mentorship_note_create_failed"})
+		}
+		_, _ = h.db.Pool.Exec(c.Context(), `UPDATE mentorships SET updated_at = now() WHERE id = $1`, id)
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": noteID.String()})
+	}
+}
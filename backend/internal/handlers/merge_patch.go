@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// patchField is one member of a JSON Merge Patch (RFC 7396) body: absent leaves the stored value alone,
+// null clears it, anything else sets it.
+type patchField[T any] struct {
+	Set   bool // present in the body
+	Null  bool // present as null
+	Value T
+}
+
+func (f *patchField[T]) UnmarshalJSON(b []byte) error {
+	f.Set = true
+	if string(b) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(b, &f.Value)
+}
+
+// patchText maps a clearable text member to the SQL convention used by the update queries: nil leaves the
+// column alone, "" clears it. A blank string clears like null does.
+func patchText(f patchField[string]) *string {
+	if !f.Set {
+		return nil
+	}
+	v := ""
+	if !f.Null {
+		v = strings.TrimSpace(f.Value)
+	}
+	return &v
+}
+
+// patchJSON maps a JSON member to nil (leave alone), the column's empty value on null, or the new value.
+func patchJSON(f patchField[json.RawMessage], empty string) []byte {
+	if !f.Set {
+		return nil
+	}
+	if f.Null {
+		return []byte(empty)
+	}
+	return f.Value
+}
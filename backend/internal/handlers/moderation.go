@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/moderation"
+)
+
+// moderate runs user text through the configured moderation pipeline before it is published. It returns
+// the 422 body to send when the text is rejected, nil otherwise.
+func moderate(ctx context.Context, cfg config.Config, kind, field, text string) fiber.Map {
+	p := moderation.FromSettings(cfg.ModerationBlockedWords, cfg.ModerationLinkHosts, cfg.ModerationMaxLinks, cfg.ModerationAPIURL, cfg.ModerationAPIToken)
+	d := p.Review(ctx, kind, text)
+	if !d.Rejected {
+		return nil
+	}
+	return fiber.Map{"error": "content_rejected", "field": field, "check": d.Check, "reason": d.Reason}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+type NotificationsHandler struct {
+	db *db.DB
+}
+
+func NewNotificationsHandler(d *db.DB) *NotificationsHandler {
+	return &NotificationsHandler{db: d}
+}
+
+// List returns the current user's most recent notifications (?unread=true for unread only).
+func (h *NotificationsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		unreadOnly := c.QueryBool("unread", false)
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, kind, payload, read_at, created_at
+FROM notifications
+WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+ORDER BY created_at DESC
+LIMIT 100
+`, userID, unreadOnly)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "notifications_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var kind string
+			var payloadJSON []byte
+			var readAt *time.Time
+			var createdAt time.Time
+			if err := rows.Scan(&id, &kind, &payloadJSON, &readAt, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "notifications_list_failed"})
+			}
+			var payload map[string]any
+			_ = json.Unmarshal(payloadJSON, &payload)
+			out = append(out, fiber.Map{
+				"id":         id.String(),
+				"kind":       kind,
+				"payload":    payload,
+				"read_at":    readAt,
+				"created_at": createdAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"notifications": out})
+	}
+}
+
+// MarkRead marks one notification (or all, when :id is "all") as read.
+func (h *NotificationsHandler) MarkRead() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		if c.Params("id") == "all" {
+			if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL
+`, userID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "notification_update_failed"})
+			}
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+		}
+
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_notification_id"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2
+`, id, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "notification_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "notification_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// notifyUser stores an in-app notification through notify.Store. Best-effort: failures are only logged.
+func notifyUser(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, kind string, payload fiber.Map) {
+	_ = notify.Store(ctx, pool, userID, kind, payload)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+type OnboardingHandler struct {
+	db *db.DB
+}
+
+func NewOnboardingHandler(d *db.DB) *OnboardingHandler {
+	return &OnboardingHandler{db: d}
+}
+
+// Status serves GET /me/onboarding: the dashboard's setup checklist in one call. Each step reports
+// whether it is done and, when known, when it was first completed.
+func (h *OnboardingHandler) Status() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		// Applications made through quick apply before signing up only carry the GitHub login.
+		var githubLinked, appInstalled, firstApplication, walletAdded *time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+WITH ga AS (SELECT login, created_at FROM github_accounts WHERE user_id = $1)
+SELECT
+  (SELECT created_at FROM ga),
+  (SELECT MIN(created_at) FROM projects
+   WHERE owner_user_id = $1 AND deleted_at IS NULL AND COALESCE(github_app_installation_id, '') <> ''),
+  (SELECT MIN(created_at) FROM issue_applications
+   WHERE user_id = $1 OR LOWER(github_login) = (SELECT LOWER(login) FROM ga)),
+  (SELECT MIN(created_at) FROM wallets WHERE user_id = $1)
+`, userID).Scan(&githubLinked, &appInstalled, &firstApplication, &walletAdded)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "onboarding_lookup_failed"})
+		}
+
+		steps := []fiber.Map{}
+		completed := 0
+		for _, s := range []struct {
+			id string
+			at *time.Time
+		}{
+			{"github_linked", githubLinked},
+			{"app_installed", appInstalled},
+			{"first_application", firstApplication},
+			{"wallet_added", walletAdded},
+		} {
+			if s.at != nil {
+				completed++
+			}
+			steps = append(steps, fiber.Map{"id": s.id, "done": s.at != nil, "completed_at": s.at})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"steps":     steps,
+			"completed": completed,
+			"total":     len(steps),
+			"done":      completed == len(steps),
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/kyc"
+	"github.com/jagadeesh/grainlify/backend/internal/payouts"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// PayoutsHandler tracks payouts owed to contributors. Approved payouts are executed by the payout job when
+// an executor is configured (see package payouts).
+type PayoutsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewPayoutsHandler(cfg config.Config, d *db.DB) *PayoutsHandler {
+	return &PayoutsHandler{cfg: cfg, db: d}
+}
+
+type payout struct {
+	ID               uuid.UUID  `json:"id"`
+	RecipientUserID  uuid.UUID  `json:"recipient_user_id"`
+	RecipientLogin   *string    `json:"recipient_login"`
+	RecipientAddress *string    `json:"recipient_address"`
+	ProjectID        *uuid.UUID `json:"project_id"`
+	IssueNumber      *int       `json:"issue_number"`
+	Currency         string     `json:"currency"`
+	Amount           string     `json:"amount"`
+	Decimals         int        `json:"decimals"`
+	Note             *string    `json:"note"`
+	Status           string     `json:"status"`
+	TaxStatus        string     `json:"tax_status"` // not_required, missing, or the recipient's tax info status
+	KYCStatus        string     `json:"kyc_status"` // not_required, or the recipient's KYC status
+	Executor         *string    `json:"executor"`
+	ExternalID       *string    `json:"external_id"`
+	TxHash           *string    `json:"tx_hash"`
+	Error            *string    `json:"error"`
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at"`
+	CreatedBy        *uuid.UUID `json:"created_by"`
+	ApprovedBy       *uuid.UUID `json:"approved_by"`
+	ApprovedAt       *time.Time `json:"approved_at"`
+	ExecutedAt       *time.Time `json:"executed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+const payoutColumns = `p.id, p.recipient_user_id, (SELECT login FROM github_accounts WHERE user_id = p.recipient_user_id),
+       p.recipient_address, p.project_id, p.issue_number, p.currency_code, p.amount::text,
+       (SELECT decimals FROM currencies WHERE code = p.currency_code), p.note, p.status, p.executor, p.external_id,
+       p.tx_hash, p.error, p.attempts, p.next_attempt_at, p.created_by, p.approved_by, p.approved_at, p.executed_at,
+       p.created_at, p.updated_at, ` + payouts.TaxStatus + `, ` + payouts.KYCStatus
+
+func scanPayout(row pgx.Row) (payout, error) {
+	var p payout
+	err := row.Scan(&p.ID, &p.RecipientUserID, &p.RecipientLogin, &p.RecipientAddress, &p.ProjectID, &p.IssueNumber,
+		&p.Currency, &p.Amount, &p.Decimals, &p.Note, &p.Status, &p.Executor, &p.ExternalID, &p.TxHash, &p.Error,
+		&p.Attempts, &p.NextAttemptAt, &p.CreatedBy, &p.ApprovedBy, &p.ApprovedAt, &p.ExecutedAt, &p.CreatedAt, &p.UpdatedAt,
+		&p.TaxStatus, &p.KYCStatus)
+	return p, err
+}
+
+// List serves GET /admin/payouts: payouts, newest first. ?status= and ?user_id= filter them.
+func (h *PayoutsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		status := strings.TrimSpace(c.Query("status"))
+		var userID *uuid.UUID
+		if raw := c.Query("user_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_user_id"})
+			}
+			userID = &id
+		}
+		return h.list(c, status, userID)
+	}
+}
+
+// Mine serves GET /me/payouts: the caller's payouts, newest first.
+func (h *PayoutsHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		return h.list(c, "", &userID)
+	}
+}
+
+func (h *PayoutsHandler) list(c *fiber.Ctx, status string, userID *uuid.UUID) error {
+	rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+payoutColumns+`
+FROM payouts p
+WHERE (NULLIF($1, '') IS NULL OR p.status = $1)
+  AND ($2::uuid IS NULL OR p.recipient_user_id = $2)
+ORDER BY p.created_at DESC
+LIMIT 500
+`, status, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_fetch_failed"})
+	}
+	defer rows.Close()
+	out := []payout{}
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_fetch_failed"})
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payouts_fetch_failed"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"payouts": out})
+}
+
+type payoutCreateRequest struct {
+	RecipientUserID  string     `json:"recipient_user_id"`
+	RecipientAddress string     `json:"recipient_address"`
+	ProjectID        *uuid.UUID `json:"project_id"`
+	IssueNumber      *int       `json:"issue_number"`
+	Currency         string     `json:"currency"`
+	// Amount is in the currency's base units (cents, stroops), as a string so large amounts stay exact.
+	Amount string `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// Create serves POST /admin/payouts: records a payout owed to a user, pending approval. Token payouts go
+// to recipient_address, or the recipient's most recently linked wallet on the currency's chain.
+func (h *PayoutsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req payoutCreateRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+		req.RecipientAddress, req.Amount, req.Note = strings.TrimSpace(req.RecipientAddress), strings.TrimSpace(req.Amount), strings.TrimSpace(req.Note)
+
+		var v validate.Validator
+		recipient, err := uuid.Parse(strings.TrimSpace(req.RecipientUserID))
+		if err != nil {
+			v.Fail("recipient_user_id", validate.Invalid, "must be a user id")
+		}
+		v.Required("currency", req.Currency)
+		if amount, ok := new(big.Int).SetString(req.Amount, 10); !ok || amount.Sign() <= 0 || len(req.Amount) > 78 {
+			v.Fail("amount", validate.Invalid, "must be a positive whole number of base units")
+		}
+		if req.IssueNumber != nil && req.ProjectID == nil {
+			v.Fail("issue_number", validate.Invalid, "needs a project_id")
+		}
+		v.MaxLen("recipient_address", req.RecipientAddress, 200)
+		v.MaxLen("note", req.Note, 1000)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		var kind, chain string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT kind, COALESCE(chain, '') FROM currencies WHERE code = $1 AND active`, req.Currency).Scan(&kind, &chain)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "unknown_currency"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payout_create_failed"})
+		}
+		var exists bool
+		_ = h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, recipient).Scan(&exists)
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
+		}
+		if kind == "token" && req.RecipientAddress == "" {
+			// Stellar wallets are ed25519 or secp256k1 keys; other chains are EVM.
+			walletTypes := []string{"evm"}
+			if chain == "stellar" {
+				walletTypes = []string{"stellar_ed25519", "stellar_secp256k1"}
+			}
+			_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT address FROM wallets WHERE user_id = $1 AND wallet_type = ANY($2) ORDER BY created_at DESC LIMIT 1
+`, recipient, walletTypes).Scan(&req.RecipientAddress)
+			if req.RecipientAddress == "" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "recipient_has_no_wallet", "chain": chain})
+			}
+		}
+
+		p, err := scanPayout(h.db.Pool.QueryRow(c.Context(), `
+WITH inserted AS (
+  INSERT INTO payouts (recipient_user_id, recipient_address, project_id, issue_number, currency_code, amount, note, created_by)
+  VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6::numeric, NULLIF($7, ''), $8)
+  RETURNING *
+)
+SELECT `+payoutColumns+` FROM inserted p
+`, recipient, req.RecipientAddress, req.ProjectID, req.IssueNumber, req.Currency, req.Amount, req.Note, contentActor(c)))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payout_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(p)
+	}
+}
+
+// Approve serves POST /admin/payouts/:id/approve: accepts a pending payout for execution. Payouts that need
+// the recipient's tax information wait until it is verified.
+func (h *PayoutsHandler) Approve() fiber.Handler {
+	return h.cleared(h.transition(`status = 'pending'`, `status = 'approved', approved_by = $2, approved_at = now()`))
+}
+
+// Cancel serves POST /admin/payouts/:id/cancel. Payouts being executed or already paid can't be cancelled.
+func (h *PayoutsHandler) Cancel() fiber.Handler {
+	return h.transition(`status IN ('pending', 'approved', 'failed')`, `status = 'cancelled', cancelled_by = $2, next_attempt_at = NULL`)
+}
+
+// Retry serves POST /admin/payouts/:id/retry: approves a failed payout again, with fresh attempts.
+func (h *PayoutsHandler) Retry() fiber.Handler {
+	return h.cleared(h.transition(`status = 'failed'`, `status = 'approved', attempts = 0, error = NULL, approved_by = $2, approved_at = now()`))
+}
+
+// cleared answers 409 tax_info_required, with the tax status, for a payout whose recipient's tax
+// information is required but not verified, and 409 kyc_required, with the KYC status, for one whose
+// recipient must be but isn't verified; only a payout known to be clear is handed over to next.
+func (h *PayoutsHandler) cleared(next fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_payout_id"})
+		}
+		var taxStatus, kycStatus string
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT `+payouts.TaxStatus+`, `+payouts.KYCStatus+` FROM payouts p WHERE p.id = $1`,
+			id).Scan(&taxStatus, &kycStatus)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "payout_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payout_gate_check_failed"})
+		}
+		if taxStatus != taxNotRequired && taxStatus != taxVerified {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "tax_info_required", "tax_status": taxStatus})
+		}
+		if kycStatus != kycNotRequired && kycStatus != kyc.StatusVerified {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "kyc_required", "kyc_status": kycStatus})
+		}
+		return next(c)
+	}
+}
+
+// transition applies set to the payout if it matches from, else answers 409 with its current status.
+func (h *PayoutsHandler) transition(from, set string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_payout_id"})
+		}
+		p, err := scanPayout(h.db.Pool.QueryRow(c.Context(), `
+WITH updated AS (
+  UPDATE payouts SET `+set+`, updated_at = now()
+  WHERE id = $1 AND `+from+`
+  RETURNING *
+)
+SELECT `+payoutColumns+` FROM updated p
+`, id, contentActor(c)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			var status string
+			if err := h.db.Pool.QueryRow(c.Context(), `SELECT status FROM payouts WHERE id = $1`, id).Scan(&status); err != nil {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "payout_not_found"})
+			}
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invalid_payout_status", "status": status})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "payout_update_failed"})
+		}
+		if p.Status == payouts.StatusApproved && h.cfg.PayoutProviderURL == "" {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"payout": p, "executor_configured": false})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"payout": p})
+	}
+}
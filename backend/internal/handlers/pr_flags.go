@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PRFlags serves GET /projects/:id/pr-flags: pull requests closing tracked issues whose provenance needs
+// a maintainer's look (see checkruns.Review): from someone else's fork, with no commits by the author, or
+// by someone other than the assignee. Unreviewed ones come first; ?all=false leaves out reviewed ones.
+// Project owner or admin.
+func (h *ProjectsHandler) PRFlags() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT r.pr_number, r.author_login, COALESCE(pr.title, ''), COALESCE(pr.url, ''), r.issue_numbers,
+       r.head_repo_full_name, r.flags, r.flags_reviewed_at, r.updated_at
+FROM pr_check_runs r
+LEFT JOIN github_pull_requests pr ON pr.project_id = r.project_id AND pr.number = r.pr_number
+WHERE r.project_id = $1 AND r.flags <> '{}' AND ($2 OR r.flags_reviewed_at IS NULL)
+ORDER BY r.flags_reviewed_at IS NOT NULL, r.updated_at DESC
+LIMIT 200
+`, projectID, c.QueryBool("all", true))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_flags_lookup_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var number int
+			var author, title, url string
+			var issues []int32
+			var headRepo *string
+			var flags []string
+			var reviewedAt *time.Time
+			var updatedAt time.Time
+			if err := rows.Scan(&number, &author, &title, &url, &issues, &headRepo, &flags, &reviewedAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_flags_lookup_failed"})
+			}
+			out = append(out, fiber.Map{
+				"pr_number":     number,
+				"author_login":  author,
+				"title":         title,
+				"url":           url,
+				"issue_numbers": issues,
+				"head_repo":     headRepo,
+				"flags":         flags,
+				"reviewed_at":   reviewedAt,
+				"updated_at":    updatedAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_flags_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"pull_requests": out})
+	}
+}
+
+// ReviewPRFlags serves POST /projects/:id/pr-flags/:number/review: a maintainer checked the flagged pull
+// request and accepts it. Its check run is republished without holding it back; new flags (e.g. after
+// another push) need another review. Project owner or admin.
+func (h *ProjectsHandler) ReviewPRFlags() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		prNumber, err := c.ParamsInt("number")
+		if err != nil || prNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_pr_number"})
+		}
+		tag, err := h.db.Pool.Exec(c.Context(), `
+UPDATE pr_check_runs
+SET flags_reviewed_at = now(), flags_reviewed_by_user_id = $3, needs_publish = true, attempts = 0, updated_at = now()
+WHERE project_id = $1 AND pr_number = $2 AND flags <> '{}'
+`, projectID, prNumber, contentActor(c))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_flags_update_failed"})
+		}
+		if tag.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "pr_not_flagged"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PRSimilarity serves GET /projects/:id/pr-similarity: pairs of pull requests closing the same tracked
+// issue whose diffs are alike (see package similarity), the later one being a possible copy of the earlier.
+// ?issue= narrows to one issue; ?all=false leaves out reviewed pairs. Project owner or admin.
+func (h *ProjectsHandler) PRSimilarity() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		issue := c.QueryInt("issue", 0)
+		if issue < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issue_number"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT f.pr_number, COALESCE(a.author_login, ''), COALESCE(a.title, ''), COALESCE(a.url, ''), a.created_at_github,
+       f.similar_pr_number, COALESCE(b.author_login, ''), COALESCE(b.title, ''), COALESCE(b.url, ''), b.created_at_github,
+       f.issue_numbers, f.similarity, f.reviewed_at, f.updated_at
+FROM pr_similarity_flags f
+LEFT JOIN github_pull_requests a ON a.project_id = f.project_id AND a.number = f.pr_number
+LEFT JOIN github_pull_requests b ON b.project_id = f.project_id AND b.number = f.similar_pr_number
+WHERE f.project_id = $1 AND ($2 = 0 OR $2 = ANY(f.issue_numbers)) AND ($3 OR f.reviewed_at IS NULL)
+ORDER BY f.reviewed_at IS NOT NULL, f.similarity DESC, f.updated_at DESC
+LIMIT 200
+`, projectID, issue, c.QueryBool("all", true))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_similarity_lookup_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var number, similarNumber int
+			var author, title, url, similarAuthor, similarTitle, similarURL string
+			var createdAt, similarCreatedAt, reviewedAt *time.Time
+			var issues []int32
+			var score float32
+			var updatedAt time.Time
+			if err := rows.Scan(&number, &author, &title, &url, &createdAt,
+				&similarNumber, &similarAuthor, &similarTitle, &similarURL, &similarCreatedAt,
+				&issues, &score, &reviewedAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_similarity_lookup_failed"})
+			}
+			out = append(out, fiber.Map{
+				"pull_request": fiber.Map{
+					"number": number, "author_login": author, "title": title, "url": url, "created_at": createdAt,
+				},
+				"similar_to": fiber.Map{
+					"number": similarNumber, "author_login": similarAuthor, "title": similarTitle, "url": similarURL, "created_at": similarCreatedAt,
+				},
+				"issue_numbers": issues,
+				"similarity":    score,
+				"reviewed_at":   reviewedAt,
+				"updated_at":    updatedAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_similarity_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"pairs": out})
+	}
+}
+
+// ReviewPRSimilarity serves POST /projects/:id/pr-similarity/:number/:similar/review: a maintainer looked
+// at the pair and dismisses it. The flag stays for the record and isn't raised again. Project owner or admin.
+func (h *ProjectsHandler) ReviewPRSimilarity() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		prNumber, err := c.ParamsInt("number")
+		if err != nil || prNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_pr_number"})
+		}
+		similarNumber, err := c.ParamsInt("similar")
+		if err != nil || similarNumber <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_pr_number"})
+		}
+		tag, err := h.db.Pool.Exec(c.Context(), `
+UPDATE pr_similarity_flags
+SET reviewed_at = now(), reviewed_by_user_id = $4, updated_at = now()
+WHERE project_id = $1 AND ((pr_number = $2 AND similar_pr_number = $3) OR (pr_number = $3 AND similar_pr_number = $2))
+`, projectID, prNumber, similarNumber, contentActor(c))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pr_similarity_update_failed"})
+		}
+		if tag.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "pr_pair_not_flagged"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
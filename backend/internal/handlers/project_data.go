@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/jagadeesh/grainlify/backend/internal/activity"
 	"github.com/jagadeesh/grainlify/backend/internal/auth"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 )
@@ -44,20 +45,29 @@ SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND status = 'verified' AND d
 	return projectID, nil
 }
 
+// Issues lists the project's 50 most recently updated issues with their comments. ?fields= trims the
+// items (see package fieldset), e.g. fields=-comments.body, and ?include= picks among comments, labels
+// and assignees; whatever is left out, and the description, isn't loaded at all.
 func (h *ProjectDataHandler) Issues() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		projectID, err := h.projectIDForRead(c)
 		if err != nil {
 			return err
 		}
+		fields, ok := requestFields(c, "comments", "labels", "assignees")
+		if !ok {
+			return nil
+		}
 
 		rows, err := h.db.Pool.Query(c.Context(), `
-SELECT github_issue_id, number, state, title, body, author_login, url, assignees, labels, comments_count, comments, updated_at_github, last_seen_at
+SELECT github_issue_id, number, state, title, CASE WHEN $5 THEN body END, author_login, url,
+       CASE WHEN $4 THEN assignees END, CASE WHEN $3 THEN labels END, comments_count,
+       CASE WHEN $2 THEN comments END, updated_at_github, last_seen_at
 FROM github_issues
 WHERE project_id = $1
 ORDER BY COALESCE(updated_at_github, last_seen_at) DESC
 LIMIT 50
-`, projectID)
+`, projectID, fields.Wants("comments"), fields.Wants("labels"), fields.Wants("assignees"), fields.Wants("description"))
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
 		}
@@ -91,7 +101,7 @@ LIMIT 50
 				_ = json.Unmarshal(commentsJSON, &comments)
 			}
 			
-			out = append(out, fiber.Map{
+			out = append(out, fields.Apply(fiber.Map{
 				"github_issue_id": gid,
 				"number":          number,
 				"state":           state,
@@ -105,7 +115,7 @@ LIMIT 50
 				"url":             url,
 				"updated_at":      updated,
 				"last_seen_at":    lastSeen,
-			})
+			}))
 		}
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"issues": out})
 	}
@@ -117,10 +127,15 @@ func (h *ProjectDataHandler) PRs() fiber.Handler {
 		if err != nil {
 			return err
 		}
+		fields, ok := requestFields(c)
+		if !ok {
+			return nil
+		}
 
 		rows, err := h.db.Pool.Query(c.Context(), `
 SELECT github_pr_id, number, state, title, author_login, url, merged, 
-       created_at_github, updated_at_github, closed_at_github, merged_at_github, last_seen_at
+       created_at_github, updated_at_github, closed_at_github, merged_at_github, last_seen_at,
+       additions, deletions, changed_files
 FROM github_pull_requests
 WHERE project_id = $1
 ORDER BY COALESCE(updated_at_github, last_seen_at) DESC
@@ -139,64 +154,41 @@ LIMIT 50
 			var merged bool
 			var createdAt, updated, closedAt, mergedAt *time.Time
 			var lastSeen time.Time
-			if err := rows.Scan(&gid, &number, &state, &title, &author, &url, &merged, &createdAt, &updated, &closedAt, &mergedAt, &lastSeen); err != nil {
+			var additions, deletions, changedFiles *int
+			if err := rows.Scan(&gid, &number, &state, &title, &author, &url, &merged, &createdAt, &updated, &closedAt, &mergedAt, &lastSeen,
+				&additions, &deletions, &changedFiles); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "prs_list_failed"})
 			}
-			out = append(out, fiber.Map{
-				"github_pr_id":    gid,
-				"number":          number,
-				"state":           state,
-				"title":           title,
-				"author_login":    author,
-				"url":             url,
-				"merged":          merged,
-				"created_at":       createdAt,
-				"updated_at":      updated,
-				"closed_at":       closedAt,
-				"merged_at":       mergedAt,
-				"last_seen_at":    lastSeen,
-			})
+			out = append(out, fields.Apply(fiber.Map{
+				"github_pr_id":  gid,
+				"number":        number,
+				"state":         state,
+				"title":         title,
+				"author_login":  author,
+				"url":           url,
+				"merged":        merged,
+				"created_at":    createdAt,
+				"updated_at":    updated,
+				"closed_at":     closedAt,
+				"merged_at":     mergedAt,
+				"last_seen_at":  lastSeen,
+				"additions":     additions,
+				"deletions":     deletions,
+				"changed_files": changedFiles,
+			}))
 		}
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"prs": out})
 	}
 }
 
+// Events returns the project's curated activity feed (see ActivityHandler) for signed-in users.
 func (h *ProjectDataHandler) Events() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		projectID, err := h.projectIDForRead(c)
 		if err != nil {
 			return err
 		}
-
-		rows, err := h.db.Pool.Query(c.Context(), `
-SELECT delivery_id, event, action, received_at
-FROM github_events
-WHERE project_id = $1
-ORDER BY received_at DESC
-LIMIT 50
-`, projectID)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "events_list_failed"})
-		}
-		defer rows.Close()
-
-		var out []fiber.Map
-		for rows.Next() {
-			var deliveryID string
-			var event string
-			var action *string
-			var receivedAt time.Time
-			if err := rows.Scan(&deliveryID, &event, &action, &receivedAt); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "events_list_failed"})
-			}
-			out = append(out, fiber.Map{
-				"delivery_id":  deliveryID,
-				"event":        event,
-				"action":       action,
-				"received_at":  receivedAt,
-			})
-		}
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"events": out})
+		return respondActivity(c, h.db, activity.Filter{ProjectID: &projectID})
 	}
 }
 
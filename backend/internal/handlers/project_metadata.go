@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+)
+
+// Contact links a project can list, and the limits on maintainer-edited metadata.
+var projectLinkKeys = map[string]bool{"website": true, "docs": true, "discord": true, "telegram": true, "twitter": true, "forum": true}
+
+const (
+	maxProjectDescription = 2000
+	maxProjectTags        = 20
+	maxProjectTagLen      = 50
+	maxProjectShortField  = 50
+	maxProjectURLLen      = 2048
+)
+
+// projectMetadataPatch is a partial update: omitted fields are left alone, "" clears a field.
+type projectMetadataPatch struct {
+	Description   *string            `json:"description"`
+	EcosystemID   *string            `json:"ecosystem_id"`
+	EcosystemName *string            `json:"ecosystem_name"`
+	Language      *string            `json:"language"`
+	Tags          *[]string          `json:"tags"`
+	Category      *string            `json:"category"`
+	LogoURL       *string            `json:"logo_url"`
+	Links         *map[string]string `json:"links"` // replaces all links; "" values are dropped
+}
+
+// GetMetadata serves GET /projects/:id/metadata for the project's maintainers (owner or admin).
+func (h *ProjectsHandler) GetMetadata() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		out, err := h.loadMetadata(c.Context(), projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "metadata_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+// PatchMetadata serves PATCH /projects/:id/metadata: validates and applies a partial update, then
+// recomputes needs_metadata (a project needs a description and an ecosystem before it is listed).
+func (h *ProjectsHandler) PatchMetadata() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+
+		var req projectMetadataPatch
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if code := normalizeProjectMetadata(&req); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+
+		var updates []string
+		var args []interface{}
+		set := func(column string, value interface{}) {
+			args = append(args, value)
+			updates = append(updates, fmt.Sprintf("%s = $%d", column, len(args)+1))
+		}
+		if req.Description != nil {
+			set("description", nullIfEmpty(*req.Description))
+		}
+		if req.Language != nil {
+			set("language", nullIfEmpty(*req.Language))
+		}
+		if req.Category != nil {
+			set("category", nullIfEmpty(*req.Category))
+		}
+		if req.LogoURL != nil {
+			set("logo_url", nullIfEmpty(*req.LogoURL))
+		}
+		if req.Tags != nil {
+			tagsJSON, _ := json.Marshal(*req.Tags)
+			set("tags", tagsJSON)
+		}
+		if req.Links != nil {
+			linksJSON, _ := json.Marshal(*req.Links)
+			set("links", linksJSON)
+		}
+
+		ecoName := req.EcosystemName
+		if req.EcosystemID != nil {
+			ecoName = nil
+		}
+		switch {
+		case req.EcosystemID != nil && *req.EcosystemID == "", ecoName != nil && *ecoName == "":
+			set("ecosystem_id", nil)
+		case req.EcosystemID != nil || ecoName != nil:
+			var ecoID uuid.UUID
+			var err error
+			if req.EcosystemID != nil {
+				err = h.db.Pool.QueryRow(c.Context(), `
+SELECT id FROM ecosystems WHERE id::text = $1 AND status = 'active'
+`, *req.EcosystemID).Scan(&ecoID)
+			} else {
+				err = h.db.Pool.QueryRow(c.Context(), `
+SELECT id FROM ecosystems WHERE LOWER(TRIM(name)) = LOWER(TRIM($1)) AND status = 'active'
+`, *ecoName).Scan(&ecoID)
+			}
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ecosystem_not_found"})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+			}
+			set("ecosystem_id", ecoID)
+		}
+
+		if len(updates) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "no_fields_to_update"})
+		}
+
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(c.Context(), fmt.Sprintf(`
+UPDATE projects SET %s, updated_at = now() WHERE id = $1
+`, strings.Join(updates, ", ")), append([]interface{}{projectID}, args...)...); err != nil {
+				return err
+			}
+			_, err := tx.Exec(c.Context(), `
+UPDATE projects
+SET needs_metadata = (description IS NULL OR TRIM(description) = '' OR ecosystem_id IS NULL)
+WHERE id = $1
+`, projectID)
+			return err
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "metadata_update_failed"})
+		}
+
+		out, err := h.loadMetadata(c.Context(), projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "metadata_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+func (h *ProjectsHandler) loadMetadata(ctx context.Context, projectID uuid.UUID) (fiber.Map, error) {
+	var fullName string
+	var description, language, category, logoURL, ecosystemName *string
+	var ecosystemID *uuid.UUID
+	var tagsJSON, linksJSON []byte
+	var needsMetadata bool
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT p.github_full_name, p.description, p.ecosystem_id, e.name, p.language, COALESCE(p.tags, '[]'::jsonb),
+       p.category, p.logo_url, p.links, p.needs_metadata
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, projectID).Scan(&fullName, &description, &ecosystemID, &ecosystemName, &language, &tagsJSON,
+		&category, &logoURL, &linksJSON, &needsMetadata)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	_ = json.Unmarshal(tagsJSON, &tags)
+	links := map[string]string{}
+	_ = json.Unmarshal(linksJSON, &links)
+
+	missing := []string{}
+	if description == nil || strings.TrimSpace(*description) == "" {
+		missing = append(missing, "description")
+	}
+	if ecosystemID == nil {
+		missing = append(missing, "ecosystem")
+	}
+	return fiber.Map{
+		"id":               projectID.String(),
+		"github_full_name": fullName,
+		"description":      description,
+		"ecosystem_id":     ecosystemID,
+		"ecosystem_name":   ecosystemName,
+		"language":         language,
+		"tags":             tags,
+		"category":         category,
+		"logo_url":         logoURL,
+		"links":            links,
+		"needs_metadata":   needsMetadata,
+		"missing":          missing,
+	}, nil
+}
+
+// requireMaintainer resolves :id to a live project the caller owns (or any project, for admins).
+func (h *ProjectsHandler) requireMaintainer(c *fiber.Ctx) (uuid.UUID, int, string) {
+	projectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_project_id"
+	}
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, fiber.StatusUnauthorized, "invalid_user"
+	}
+	role, _ := c.Locals(auth.LocalRole).(string)
+
+	var owner uuid.UUID
+	err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fiber.StatusNotFound, "project_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "project_lookup_failed"
+	}
+	if owner != userID && role != "admin" {
+		return uuid.Nil, fiber.StatusForbidden, "forbidden"
+	}
+	return projectID, 0, ""
+}
+
+// normalizeProjectMetadata trims the patch in place and returns an error code if it is invalid.
+// Tags are lowercased and de-duplicated, like GitHub topics.
+func normalizeProjectMetadata(req *projectMetadataPatch) string {
+	trim := func(p *string) {
+		if p != nil {
+			*p = strings.TrimSpace(*p)
+		}
+	}
+	for _, p := range []*string{req.Description, req.EcosystemID, req.EcosystemName, req.Language, req.Category, req.LogoURL} {
+		trim(p)
+	}
+	if req.Description != nil && utf8.RuneCountInString(*req.Description) > maxProjectDescription {
+		return "description_too_long"
+	}
+	if req.EcosystemID != nil && *req.EcosystemID != "" {
+		if _, err := uuid.Parse(*req.EcosystemID); err != nil {
+			return "invalid_ecosystem_id"
+		}
+	}
+	if req.Language != nil && utf8.RuneCountInString(*req.Language) > maxProjectShortField {
+		return "language_too_long"
+	}
+	if req.Category != nil && utf8.RuneCountInString(*req.Category) > maxProjectShortField {
+		return "category_too_long"
+	}
+	if req.LogoURL != nil && *req.LogoURL != "" && !isWebURL(*req.LogoURL) {
+		return "invalid_logo_url"
+	}
+	if req.Tags != nil {
+		seen := map[string]bool{}
+		tags := []string{}
+		for _, t := range *req.Tags {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" || seen[t] {
+				continue
+			}
+			if utf8.RuneCountInString(t) > maxProjectTagLen {
+				return "tag_too_long"
+			}
+			seen[t] = true
+			tags = append(tags, t)
+		}
+		if len(tags) > maxProjectTags {
+			return "too_many_tags"
+		}
+		*req.Tags = tags
+	}
+	if req.Links != nil {
+		links := map[string]string{}
+		for k, v := range *req.Links {
+			k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+			if !projectLinkKeys[k] {
+				return "invalid_link_key"
+			}
+			if v == "" {
+				continue
+			}
+			if !isWebURL(v) {
+				return "invalid_link_url"
+			}
+			links[k] = v
+		}
+		*req.Links = links
+	}
+	return ""
+}
+
+// isWebURL reports whether s is an absolute http(s) URL of reasonable length.
+func isWebURL(s string) bool {
+	if len(s) > maxProjectURLLen {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/syncjobs"
+)
+
+// Reconcile serves POST /projects/:id/reconcile: re-fetches the project's issues, pull requests and
+// assignees from GitHub now instead of waiting for the next scheduled sync, and reports what it corrected.
+// Refused with 429 when the owner's GitHub rate limit is running low. Project owner or admin.
+func (h *ProjectsHandler) Reconcile() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		res, err := syncjobs.New(h.cfg, h.db.Pool).Reconcile(c.Context(), projectID)
+		var budget *syncjobs.RateBudgetError
+		switch {
+		case errors.As(err, &budget):
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":     "rate_budget_exhausted",
+				"remaining": budget.Remaining,
+				"needed":    budget.Needed,
+				"reset_at":  budget.ResetAt,
+			})
+		case errors.Is(err, syncjobs.ErrReconcileRunning):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "reconcile_in_progress"})
+		case errors.Is(err, syncjobs.ErrGitHubNotLinked):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "github_not_linked"})
+		case err != nil:
+			slog.Error("project reconcile failed", "project_id", projectID, "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "reconcile_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(res)
+	}
+}
@@ -17,15 +17,17 @@ import (
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
 )
 
 type ProjectsHandler struct {
 	cfg config.Config
 	db  *db.DB
+	gh  github.API
 }
 
-func NewProjectsHandler(cfg config.Config, d *db.DB) *ProjectsHandler {
-	return &ProjectsHandler{cfg: cfg, db: d}
+func NewProjectsHandler(cfg config.Config, d *db.DB, gh github.API) *ProjectsHandler {
+	return &ProjectsHandler{cfg: cfg, db: d, gh: gh}
 }
 
 type createProjectRequest struct {
@@ -168,7 +170,9 @@ SELECT
   p.tags,
   p.category,
   p.description,
-  p.needs_metadata
+  p.needs_metadata,
+  p.application_visibility,
+  p.anonymous_applications
 FROM projects p
 LEFT JOIN ecosystems e ON p.ecosystem_id = e.id
 WHERE p.owner_user_id = $1
@@ -186,13 +190,13 @@ ORDER BY p.created_at DESC
 		defer rows.Close()
 
 		// Get user's GitHub access token for fetching repo data
-		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+		linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 		var accessToken string
 		if err == nil {
 			accessToken = linkedAccount.AccessToken
 		}
 
-		gh := github.NewClient()
+		gh := h.gh
 		var out []fiber.Map
 		for rows.Next() {
 			var id uuid.UUID
@@ -210,8 +214,10 @@ ORDER BY p.created_at DESC
 			var category *string
 			var description *string
 			var needsMetadata bool
+			var applicationVisibility string
+			var anonymousApplications bool
 
-			if err := rows.Scan(&id, &fullName, &status, &repoID, &verifiedAt, &verErr, &webhookID, &webhookURL, &webhookCreatedAt, &createdAt, &updatedAt, &ecosystemName, &language, &tagsJSON, &category, &description, &needsMetadata); err != nil {
+			if err := rows.Scan(&id, &fullName, &status, &repoID, &verifiedAt, &verErr, &webhookID, &webhookURL, &webhookCreatedAt, &createdAt, &updatedAt, &ecosystemName, &language, &tagsJSON, &category, &description, &needsMetadata, &applicationVisibility, &anonymousApplications); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "projects_list_failed"})
 			}
 
@@ -266,6 +272,9 @@ WHERE id = $1
 				"category":           category,
 				"description":        description,
 				"needs_metadata":     needsMetadata,
+
+				"application_visibility": applicationVisibility,
+				"anonymous_applications": anonymousApplications,
 			}
 
 			// Add owner avatar if available
@@ -449,6 +458,174 @@ WHERE id = $1
 	}
 }
 
+type quickApplyRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SetQuickApply turns "/apply" issue comments on or off for a project. When enabled, contributors can
+// apply from GitHub without opening the dashboard; applications are recorded by webhook ingest.
+func (h *ProjectsHandler) SetQuickApply() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		var req quickApplyRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE projects SET quick_apply_enabled = $2, updated_at = now() WHERE id = $1
+`, projectID, req.Enabled); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "quick_apply_update_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "quick_apply_enabled": req.Enabled})
+	}
+}
+
+type applicationVisibilityRequest struct {
+	Visibility string `json:"visibility"`
+	// Anonymous hides who applied from maintainers until they assign the issue; private visibility only.
+	// Omitted keeps the current setting (switching away from private turns it off).
+	Anonymous *bool `json:"anonymous"`
+}
+
+// SetApplicationVisibility chooses where dashboard applications to the project's issues appear: "public"
+// (a GitHub comment with the message, the default), "private" (Grainlify only) or "both" (a GitHub comment
+// announcing the application, the message in Grainlify only), and whether private applications are
+// anonymous. Maintainer only.
+func (h *ProjectsHandler) SetApplicationVisibility() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		var req applicationVisibilityRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		var v validate.Validator
+		v.OneOf("visibility", req.Visibility, "public", "private", "both")
+		if req.Anonymous != nil && *req.Anonymous && req.Visibility != "private" {
+			v.Fail("anonymous", validate.Invalid, "requires private visibility")
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		var anonymous bool
+		if err := h.db.Pool.QueryRow(c.Context(), `
+UPDATE projects
+SET application_visibility = $2,
+    anonymous_applications = COALESCE($3, anonymous_applications) AND $2 = 'private',
+    updated_at = now()
+WHERE id = $1
+RETURNING anonymous_applications
+`, projectID, req.Visibility, req.Anonymous).Scan(&anonymous); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_visibility_update_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "application_visibility": req.Visibility, "anonymous_applications": anonymous})
+	}
+}
+
+// Reactivate clears a project's stale flag; the owner confirming the project is maintained counts as activity.
+func (h *ProjectsHandler) Reactivate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		var ownerUserID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&ownerUserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+		if ownerUserID != userID && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		}
+
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE projects SET stale_at = NULL, reactivated_at = now(), updated_at = now() WHERE id = $1
+`, projectID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_reactivate_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
 func (h *ProjectsHandler) Verify() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -509,13 +686,13 @@ func (h *ProjectsHandler) verifyAndWebhook(ctx context.Context, projectID uuid.U
 		return
 	}
 
-	linked, err := github.GetLinkedAccount(ctx, h.db.Pool, ownerUserID, h.cfg.TokenEncKeyB64)
+	linked, err := github.GetLinkedAccount(ctx, h.db.Pool, ownerUserID, h.cfg.TokenEncKey())
 	if err != nil {
 		h.recordProjectError(ctx, projectID, "github_not_linked")
 		return
 	}
 
-	gh := github.NewClient()
+	gh := h.gh
 	repo, err := gh.GetRepo(ctx, linked.AccessToken, fullName)
 	if err != nil {
 		h.recordProjectError(ctx, projectID, fmt.Sprintf("repo_fetch_failed: %v", err))
@@ -554,7 +731,7 @@ WHERE id = $1
 	wh, err := gh.CreateWebhook(ctx, linked.AccessToken, fullName, github.CreateWebhookRequest{
 		URL:    webhookURL,
 		Secret: h.cfg.GitHubWebhookSecret,
-		Events: []string{"issues", "pull_request", "pull_request_review", "push"},
+		Events: []string{"issues", "pull_request", "pull_request_review", "push", "discussion", "discussion_comment"},
 		Active: true,
 	})
 	if err != nil {
@@ -19,11 +19,12 @@ import (
 )
 
 type ProjectsPublicHandler struct {
-	db  *db.DB
-	cfg config.Config
+	db   *db.DB
+	cfg  config.Config
+	gh   github.API
+	apps github.Apps
 
-	// GitHub App enrichment helpers (best-effort).
-	appClient  *github.GitHubAppClient
+	// GitHub App enrichment (best-effort): installation tokens cached per installation.
 	tokenMu    sync.Mutex
 	tokenCache map[string]struct {
 		token     string
@@ -31,30 +32,22 @@ type ProjectsPublicHandler struct {
 	}
 }
 
-func NewProjectsPublicHandler(cfg config.Config, d *db.DB) *ProjectsPublicHandler {
+func NewProjectsPublicHandler(cfg config.Config, d *db.DB, gh github.API, apps github.Apps) *ProjectsPublicHandler {
 	h := &ProjectsPublicHandler{
-		db:  d,
-		cfg: cfg,
+		db:   d,
+		cfg:  cfg,
+		gh:   gh,
+		apps: apps,
 		tokenCache: map[string]struct {
 			token     string
 			expiresAt time.Time
 		}{},
 	}
-
-	// Initialize GitHub App client if configured.
-	if strings.TrimSpace(cfg.GitHubAppID) != "" && strings.TrimSpace(cfg.GitHubAppPrivateKey) != "" {
-		appClient, err := github.NewGitHubAppClient(cfg.GitHubAppID, cfg.GitHubAppPrivateKey)
-		if err != nil {
-			slog.Warn("failed to init github app client (will skip github enrichment auth)", "error", err)
-		} else {
-			h.appClient = appClient
-		}
-	}
 	return h
 }
 
 func (h *ProjectsPublicHandler) installationToken(ctx context.Context, installationID string) string {
-	if h.appClient == nil || strings.TrimSpace(installationID) == "" {
+	if strings.TrimSpace(installationID) == "" {
 		return ""
 	}
 
@@ -65,8 +58,17 @@ func (h *ProjectsPublicHandler) installationToken(ctx context.Context, installat
 		return cached.token
 	}
 
+	if strings.TrimSpace(h.cfg.GitHubAppID) == "" || strings.TrimSpace(h.cfg.GitHubAppPrivateKey) == "" {
+		return ""
+	}
+	appClient, err := h.apps.ForInstallation(ctx, installationID)
+	if err != nil {
+		slog.Warn("failed to init github app client (will skip github enrichment auth)", "installation_id", installationID, "error", err)
+		return ""
+	}
+
 	// Installation tokens typically last 1 hour; refresh proactively.
-	tok, err := h.appClient.GetInstallationToken(ctx, installationID)
+	tok, err := appClient.GetInstallationToken(ctx, installationID)
 	if err != nil {
 		slog.Warn("failed to get github app installation token (continuing without auth)",
 			"installation_id", installationID,
@@ -120,6 +122,8 @@ func (h *ProjectsPublicHandler) Get() fiber.Handler {
 		var openIssuesCount, openPRsCount, contributorsCount int
 		var createdAt, updatedAt time.Time
 		var ecosystemName, ecosystemSlug *string
+		var description, logoURL *string
+		var linksJSON []byte
 
 		err = h.db.Pool.QueryRow(c.Context(), `
 SELECT 
@@ -129,6 +133,9 @@ SELECT
   p.language,
   p.tags,
   p.category,
+  p.description,
+  p.logo_url,
+  p.links,
   p.stars_count,
   p.forks_count,
   (
@@ -157,8 +164,8 @@ FROM projects p
 LEFT JOIN ecosystems e ON p.ecosystem_id = e.id
 WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
 `, projectID).Scan(
-			&id, &fullName, &installationID, &language, &tagsJSON, &category, &starsCount, &forksCount,
-			&openIssuesCount, &openPRsCount, &contributorsCount,
+			&id, &fullName, &installationID, &language, &tagsJSON, &category, &description, &logoURL, &linksJSON,
+			&starsCount, &forksCount, &openIssuesCount, &openPRsCount, &contributorsCount,
 			&createdAt, &updatedAt, &ecosystemName, &ecosystemSlug,
 		)
 		if err == pgx.ErrNoRows {
@@ -174,6 +181,9 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
 			_ = json.Unmarshal(tagsJSON, &tags)
 		}
 
+		links := map[string]string{}
+		_ = json.Unmarshal(linksJSON, &links)
+
 		// Default stars/forks to 0 if nil
 		stars := 0
 		if starsCount != nil {
@@ -187,7 +197,7 @@ WHERE p.id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
 		// Enrich from GitHub (best effort).
 		ctx, cancel := context.WithTimeout(c.Context(), 6*time.Second)
 		defer cancel()
-		gh := github.NewClient()
+		gh := h.gh
 		token := ""
 		if installationID != nil {
 			token = h.installationToken(ctx, *installationID)
@@ -269,6 +279,9 @@ WHERE id=$1
 			"language":           language,
 			"tags":               tags,
 			"category":           category,
+			"description":        description,
+			"logo_url":           logoURL,
+			"links":              links,
 			"stars_count":        stars,
 			"forks_count":        forks,
 			"contributors_count": contributorsCount,
@@ -282,6 +295,28 @@ WHERE id=$1
 			"readme":             readmeContent,
 		}
 
+		// Maintainer responsiveness (computed nightly; absent until the first run).
+		var decisionSecs, firstReviewSecs *int64
+		var decisions, reviewedPRs, windowDays int
+		var healthScore *int
+		var computedAt time.Time
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT window_days, median_application_decision_seconds, application_decisions_count,
+       median_first_review_seconds, reviewed_prs_count, health_score, computed_at
+FROM project_response_metrics
+WHERE project_id = $1
+`, projectID).Scan(&windowDays, &decisionSecs, &decisions, &firstReviewSecs, &reviewedPRs, &healthScore, &computedAt); err == nil {
+			resp["health_score"] = healthScore
+			resp["response_metrics"] = fiber.Map{
+				"window_days":                         windowDays,
+				"median_application_decision_seconds": decisionSecs,
+				"application_decisions_count":         decisions,
+				"median_first_review_seconds":         firstReviewSecs,
+				"reviewed_prs_count":                  reviewedPRs,
+				"computed_at":                         computedAt,
+			}
+		}
+
 		if repoOK {
 			resp["repo"] = fiber.Map{
 				"full_name":         repo.FullName,
@@ -298,7 +333,8 @@ WHERE id=$1
 	}
 }
 
-// IssuesPublic returns recent issues for a verified project (read-only, no auth).
+// IssuesPublic returns recent issues for a verified project (read-only, no auth). ?fields= trims the items
+// and ?include=labels keeps labels; the description and labels aren't loaded when left out.
 func (h *ProjectsPublicHandler) IssuesPublic() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -319,13 +355,21 @@ SELECT EXISTS(
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
 		}
 
+		// ?mentored=true restricts to issues a maintainer has flagged as mentored.
+		mentoredOnly := c.QueryBool("mentored", false)
+		fields, okFields := requestFields(c, "labels")
+		if !okFields {
+			return nil
+		}
+
 		rows, err := h.db.Pool.Query(c.Context(), `
-SELECT github_issue_id, number, state, title, body, author_login, url, labels, updated_at_github, last_seen_at
+SELECT github_issue_id, number, state, title, CASE WHEN $3 THEN body END, author_login, url, CASE WHEN $4 THEN labels END,
+       updated_at_github, last_seen_at, is_mentored, deadline_at
 FROM github_issues
-WHERE project_id = $1
+WHERE project_id = $1 AND ($2 = false OR is_mentored = true)
 ORDER BY COALESCE(updated_at_github, last_seen_at) DESC
 LIMIT 50
-`, projectID)
+`, projectID, mentoredOnly, fields.Wants("description"), fields.Wants("labels"))
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
 		}
@@ -340,7 +384,9 @@ LIMIT 50
 			var labelsJSON []byte
 			var updated *time.Time
 			var lastSeen time.Time
-			if err := rows.Scan(&gid, &number, &state, &title, &body, &author, &url, &labelsJSON, &updated, &lastSeen); err != nil {
+			var mentored bool
+			var deadline *time.Time
+			if err := rows.Scan(&gid, &number, &state, &title, &body, &author, &url, &labelsJSON, &updated, &lastSeen, &mentored, &deadline); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
 			}
 
@@ -350,7 +396,7 @@ LIMIT 50
 				_ = json.Unmarshal(labelsJSON, &labels)
 			}
 
-			out = append(out, fiber.Map{
+			out = append(out, fields.Apply(fiber.Map{
 				"github_issue_id": gid,
 				"number":          number,
 				"state":           state,
@@ -361,14 +407,17 @@ LIMIT 50
 				"url":             url,
 				"updated_at":      updated,
 				"last_seen_at":    lastSeen,
-			})
+				"mentored":        mentored,
+				"deadline_at":     deadline,
+			}))
 		}
 
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"issues": out})
 	}
 }
 
-// PRsPublic returns recent PRs for a verified project (read-only, no auth).
+// PRsPublic returns recent PRs for a verified project (read-only, no auth). ?fields= trims the items and
+// ?include=contributors keeps co-authorship, which is only looked up when included.
 func (h *ProjectsPublicHandler) PRsPublic() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -387,10 +436,15 @@ SELECT EXISTS(
 `, projectID).Scan(&ok); err != nil || !ok {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
 		}
+		fields, okFields := requestFields(c, "contributors")
+		if !okFields {
+			return nil
+		}
 
 		rows, err := h.db.Pool.Query(c.Context(), `
 SELECT github_pr_id, number, state, title, author_login, url, merged, 
-       created_at_github, updated_at_github, closed_at_github, merged_at_github, last_seen_at
+       created_at_github, updated_at_github, closed_at_github, merged_at_github, last_seen_at,
+       additions, deletions, changed_files
 FROM github_pull_requests
 WHERE project_id = $1
 ORDER BY COALESCE(updated_at_github, last_seen_at) DESC
@@ -409,25 +463,70 @@ LIMIT 50
 			var merged bool
 			var createdAt, updated, closedAt, mergedAt *time.Time
 			var lastSeen time.Time
-			if err := rows.Scan(&gid, &number, &state, &title, &author, &url, &merged, &createdAt, &updated, &closedAt, &mergedAt, &lastSeen); err != nil {
+			var additions, deletions, changedFiles *int
+			if err := rows.Scan(&gid, &number, &state, &title, &author, &url, &merged, &createdAt, &updated, &closedAt, &mergedAt, &lastSeen,
+				&additions, &deletions, &changedFiles); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "prs_list_failed"})
 			}
 			out = append(out, fiber.Map{
-				"github_pr_id": gid,
-				"number":       number,
-				"state":        state,
-				"title":        title,
-				"author_login": author,
-				"url":          url,
-				"merged":       merged,
-				"created_at":   createdAt,
-				"updated_at":   updated,
-				"closed_at":    closedAt,
-				"merged_at":    mergedAt,
-				"last_seen_at": lastSeen,
+				"github_pr_id":  gid,
+				"number":        number,
+				"state":         state,
+				"title":         title,
+				"author_login":  author,
+				"url":           url,
+				"merged":        merged,
+				"created_at":    createdAt,
+				"updated_at":    updated,
+				"closed_at":     closedAt,
+				"merged_at":     mergedAt,
+				"last_seen_at":  lastSeen,
+				"additions":     additions,
+				"deletions":     deletions,
+				"changed_files": changedFiles,
+				"contributors":  []fiber.Map{},
 			})
 		}
 
+		// Attach co-authorship for merged PRs (author + Co-authored-by trailers). Emails are not exposed.
+		if len(out) > 0 && fields.Wants("contributors") {
+			byNumber := make(map[int]int, len(out))
+			numbers := make([]int, 0, len(out))
+			for i, pr := range out {
+				n := pr["number"].(int)
+				byNumber[n] = i
+				numbers = append(numbers, n)
+			}
+			crows, err := h.db.Pool.Query(c.Context(), `
+SELECT pr_number, COALESCE(login, ''), COALESCE(name, ''), role, commits, share::float8
+FROM github_pr_contributors
+WHERE project_id = $1 AND pr_number = ANY($2)
+ORDER BY pr_number, role ASC, commits DESC
+`, projectID, numbers)
+			if err == nil {
+				defer crows.Close()
+				for crows.Next() {
+					var n, commits int
+					var login, name, role string
+					var share float64
+					if err := crows.Scan(&n, &login, &name, &role, &commits, &share); err != nil {
+						continue
+					}
+					i := byNumber[n]
+					out[i]["contributors"] = append(out[i]["contributors"].([]fiber.Map), fiber.Map{
+						"login":   login,
+						"name":    name,
+						"role":    role,
+						"commits": commits,
+						"share":   share,
+					})
+				}
+			}
+		}
+
+		for _, pr := range out {
+			fields.Apply(pr)
+		}
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"prs": out})
 	}
 }
@@ -435,11 +534,18 @@ LIMIT 50
 // List returns a filtered list of verified projects.
 // Query parameters:
 //   - ecosystem: filter by ecosystem name (case-insensitive)
-//   - language: filter by programming language
+//   - language: filter by programming language (primary or any in the repo's breakdown)
 //   - category: filter by category
 //   - tags: comma-separated list of tags (project must have ALL tags)
+//   - topics: comma-separated GitHub topics (project must have ALL topics)
+//   - license: SPDX license identifier
+//   - mentored: only projects with open mentored issues
+//   - min_health: minimum health score (0-100)
+//   - sort: "health" to order by health score (default: newest first)
 //   - limit: max results (default 50, max 200)
 //   - offset: pagination offset (default 0)
+//   - fields / include: trim the items (see package fieldset); include picks among languages and topics.
+//     The issue, pull request and contributor counts are only computed when part of the response.
 func (h *ProjectsPublicHandler) List() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -460,6 +566,17 @@ func (h *ProjectsPublicHandler) List() fiber.Handler {
 		if offset < 0 {
 			offset = 0
 		}
+		fields, okFields := requestFields(c, "languages", "topics")
+		if !okFields {
+			return nil
+		}
+		// count is the subquery for a count field, or 0 when the field is left out.
+		count := func(field, subquery string) string {
+			if fields.Wants(field) {
+				return subquery
+			}
+			return "0"
+		}
 
 		// Build WHERE clause and args
 		var conditions []string
@@ -483,13 +600,36 @@ func (h *ProjectsPublicHandler) List() fiber.Handler {
 			argPos++
 		}
 
-		// Filter by language
+		// Filter by language: the primary language or any language in the synced breakdown
 		if language != "" {
-			conditions = append(conditions, fmt.Sprintf("LOWER(TRIM(p.language)) = LOWER($%d)", argPos))
+			conditions = append(conditions, fmt.Sprintf("(LOWER(TRIM(p.language)) = LOWER($%d) OR EXISTS (SELECT 1 FROM jsonb_array_elements(p.languages) l WHERE LOWER(l->>'name') = LOWER($%d)))", argPos, argPos))
 			args = append(args, language)
 			argPos++
 		}
 
+		// Filter by GitHub topics (must have ALL specified topics)
+		if topicsParam := strings.TrimSpace(c.Query("topics")); topicsParam != "" {
+			var topics []string
+			for _, t := range strings.Split(topicsParam, ",") {
+				if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+					topics = append(topics, t)
+				}
+			}
+			if len(topics) > 0 {
+				conditions = append(conditions, fmt.Sprintf("p.topics @> $%d::jsonb", argPos))
+				topicsJSON, _ := json.Marshal(topics)
+				args = append(args, string(topicsJSON))
+				argPos++
+			}
+		}
+
+		// Filter by license (SPDX identifier, e.g. MIT, Apache-2.0)
+		if license := strings.TrimSpace(c.Query("license")); license != "" {
+			conditions = append(conditions, fmt.Sprintf("LOWER(p.license_spdx) = LOWER($%d)", argPos))
+			args = append(args, license)
+			argPos++
+		}
+
 		// Filter by category
 		if category != "" {
 			conditions = append(conditions, fmt.Sprintf("LOWER(TRIM(p.category)) = LOWER($%d)", argPos))
@@ -515,8 +655,30 @@ func (h *ProjectsPublicHandler) List() fiber.Handler {
 			argPos++
 		}
 
+		// Hide projects flagged stale (no maintainer activity) when delisting is enabled
+		if h.cfg.StaleProjectDelist {
+			conditions = append(conditions, "p.stale_at IS NULL")
+		}
+
+		// Filter by minimum health score (projects not yet scored are excluded)
+		if minHealth := c.QueryInt("min_health", 0); minHealth > 0 {
+			conditions = append(conditions, fmt.Sprintf("prm.health_score >= $%d", argPos))
+			args = append(args, minHealth)
+			argPos++
+		}
+
+		// Filter to projects with at least one open mentored issue
+		if c.QueryBool("mentored", false) {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM github_issues gi WHERE gi.project_id = p.id AND gi.state = 'open' AND gi.is_mentored = true)")
+		}
+
 		whereClause := strings.Join(conditions, " AND ")
 
+		orderBy := "p.created_at DESC"
+		if c.Query("sort") == "health" {
+			orderBy = "prm.health_score DESC NULLS LAST, p.created_at DESC"
+		}
+
 		// Build query
 		query := fmt.Sprintf(`
 SELECT 
@@ -528,35 +690,41 @@ SELECT
   p.category,
   p.stars_count,
   p.forks_count,
-  (
+  %s AS open_issues_count,
+  %s AS open_prs_count,
+  %s AS contributors_count,
+  p.created_at,
+  p.updated_at,
+  e.name AS ecosystem_name,
+  e.slug AS ecosystem_slug,
+  p.description,
+  p.stale_at,
+  prm.health_score,
+  p.languages,
+  p.topics,
+  p.license_spdx
+FROM projects p
+LEFT JOIN ecosystems e ON p.ecosystem_id = e.id
+LEFT JOIN project_response_metrics prm ON prm.project_id = p.id
+WHERE %s
+ORDER BY %s
+LIMIT $%d OFFSET $%d
+`, count("open_issues_count", `(
     SELECT COUNT(*)
     FROM github_issues gi
     WHERE gi.project_id = p.id AND gi.state = 'open'
-  ) AS open_issues_count,
-  (
+  )`), count("open_prs_count", `(
     SELECT COUNT(*)
     FROM github_pull_requests gpr
     WHERE gpr.project_id = p.id AND gpr.state = 'open'
-  ) AS open_prs_count,
-  (
+  )`), count("contributors_count", `(
     SELECT COUNT(DISTINCT a.author_login)
     FROM (
       SELECT author_login FROM github_issues WHERE project_id = p.id AND author_login IS NOT NULL AND author_login != ''
       UNION
       SELECT author_login FROM github_pull_requests WHERE project_id = p.id AND author_login IS NOT NULL AND author_login != ''
     ) a
-  ) AS contributors_count,
-  p.created_at,
-  p.updated_at,
-  e.name AS ecosystem_name,
-  e.slug AS ecosystem_slug,
-  p.description
-FROM projects p
-LEFT JOIN ecosystems e ON p.ecosystem_id = e.id
-WHERE %s
-ORDER BY p.created_at DESC
-LIMIT $%d OFFSET $%d
-`, whereClause, argPos, argPos+1)
+  )`), whereClause, orderBy, argPos, argPos+1)
 		args = append(args, limit, offset)
 
 		rows, err := h.db.Pool.Query(c.Context(), query, args...)
@@ -577,8 +745,12 @@ LIMIT $%d OFFSET $%d
 			var createdAt, updatedAt time.Time
 			var ecosystemName, ecosystemSlug *string
 			var description *string
+			var staleAt *time.Time
+			var healthScore *int
+			var languagesJSON, topicsJSON []byte
+			var license *string
 
-			if err := rows.Scan(&id, &fullName, &installationID, &language, &tagsJSON, &category, &starsCount, &forksCount, &openIssuesCount, &openPRsCount, &contributorsCount, &createdAt, &updatedAt, &ecosystemName, &ecosystemSlug, &description); err != nil {
+			if err := rows.Scan(&id, &fullName, &installationID, &language, &tagsJSON, &category, &starsCount, &forksCount, &openIssuesCount, &openPRsCount, &contributorsCount, &createdAt, &updatedAt, &ecosystemName, &ecosystemSlug, &description, &staleAt, &healthScore, &languagesJSON, &topicsJSON, &license); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "projects_list_failed", "details": err.Error()})
 			}
 
@@ -603,7 +775,12 @@ LIMIT $%d OFFSET $%d
 				descVal = *description
 			}
 
-			out = append(out, fiber.Map{
+			languages := []fiber.Map{}
+			_ = json.Unmarshal(languagesJSON, &languages)
+			topics := []string{}
+			_ = json.Unmarshal(topicsJSON, &topics)
+
+			out = append(out, fields.Apply(fiber.Map{
 				"id":                 id.String(),
 				"github_full_name":   fullName,
 				"language":           language,
@@ -617,9 +794,14 @@ LIMIT $%d OFFSET $%d
 				"ecosystem_name":     ecosystemName,
 				"ecosystem_slug":     ecosystemSlug,
 				"description":        descVal,
+				"stale":              staleAt != nil,
+				"health_score":       healthScore,
+				"languages":          languages,
+				"topics":             topics,
+				"license":            license,
 				"created_at":         createdAt,
 				"updated_at":         updatedAt,
-			})
+			}))
 		}
 
 		// Get total count for pagination
@@ -627,6 +809,7 @@ LIMIT $%d OFFSET $%d
 SELECT COUNT(*)
 FROM projects p
 LEFT JOIN ecosystems e ON p.ecosystem_id = e.id
+LEFT JOIN project_response_metrics prm ON prm.project_id = p.id
 WHERE %s
 `, whereClause)
 		countArgs := args[:len(args)-2] // Remove limit and offset
@@ -853,3 +1036,128 @@ ORDER BY tag
 		})
 	}
 }
+
+// Health returns a project's health score with the inputs it was computed from.
+func (h *ProjectsPublicHandler) Health() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		var healthScore *int
+		var decisionSecs, firstReviewSecs *int64
+		var windowDays, openUnassigned, merged, closedUnmerged, recent int
+		var computedAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT prm.health_score, prm.window_days, prm.median_application_decision_seconds, prm.median_first_review_seconds,
+       prm.open_unassigned_issues, prm.merged_prs_count, prm.closed_unmerged_prs_count, prm.recent_activity_count, prm.computed_at
+FROM project_response_metrics prm
+JOIN projects p ON p.id = prm.project_id
+WHERE prm.project_id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
+`, projectID).Scan(&healthScore, &windowDays, &decisionSecs, &firstReviewSecs, &openUnassigned, &merged, &closedUnmerged, &recent, &computedAt)
+		if err == pgx.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "health_not_computed"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "health_lookup_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"health_score": healthScore,
+			"window_days":  windowDays,
+			"inputs": fiber.Map{
+				"median_application_decision_seconds": decisionSecs,
+				"median_first_review_seconds":         firstReviewSecs,
+				"open_unassigned_issues":              openUnassigned,
+				"merged_prs":                          merged,
+				"closed_unmerged_prs":                 closedUnmerged,
+				"recent_activity":                     recent,
+			},
+			"computed_at": computedAt,
+		})
+	}
+}
+
+// Docs returns the project's cached README and CONTRIBUTING guidelines as GitHub-rendered HTML.
+// The cache is filled by the repo sync job; on a miss it is filled on demand with the App token.
+func (h *ProjectsPublicHandler) Docs() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+
+		var fullName string
+		var installationID *string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT github_full_name, github_app_installation_id
+FROM projects
+WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&fullName, &installationID)
+		if err == pgx.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+
+		load := func() (fiber.Map, error) {
+			rows, err := h.db.Pool.Query(c.Context(), `
+SELECT kind, html, COALESCE(source_path, ''), fetched_at FROM project_docs WHERE project_id = $1
+`, projectID)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+			docs := fiber.Map{}
+			for rows.Next() {
+				var kind, html, path string
+				var fetchedAt time.Time
+				if err := rows.Scan(&kind, &html, &path, &fetchedAt); err != nil {
+					return nil, err
+				}
+				docs[kind] = fiber.Map{"html": html, "path": path, "fetched_at": fetchedAt}
+			}
+			return docs, rows.Err()
+		}
+
+		docs, err := load()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "docs_lookup_failed"})
+		}
+		if len(docs) == 0 && installationID != nil {
+			ctx, cancel := context.WithTimeout(c.Context(), 8*time.Second)
+			defer cancel()
+			token := h.installationToken(ctx, *installationID)
+			gh := h.gh
+			if html, err := gh.GetReadmeHTML(ctx, token, fullName); err == nil {
+				_, _ = h.db.Pool.Exec(c.Context(), `
+INSERT INTO project_docs (project_id, kind, html) VALUES ($1, 'readme', $2)
+ON CONFLICT (project_id, kind) DO NOTHING
+`, projectID, html)
+			}
+			if html, path, err := gh.GetContributingHTML(ctx, token, fullName); err == nil {
+				_, _ = h.db.Pool.Exec(c.Context(), `
+INSERT INTO project_docs (project_id, kind, html, source_path) VALUES ($1, 'contributing', $2, $3)
+ON CONFLICT (project_id, kind) DO NOTHING
+`, projectID, html, path)
+			}
+			if docs, err = load(); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "docs_lookup_failed"})
+			}
+		}
+
+		c.Set("Cache-Control", "public, max-age=300")
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"readme":       docs["readme"],
+			"contributing": docs["contributing"],
+		})
+	}
+}
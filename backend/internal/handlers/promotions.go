@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/promotions"
+)
+
+// maxPromotionDelay is how far ahead a promotion can be scheduled.
+const maxPromotionDelay = 30 * 24 * time.Hour
+
+// PromotionChannelsHandler manages the channels an ecosystem's promoted issues are posted to (admin).
+type PromotionChannelsHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewPromotionChannelsHandler(cfg config.Config, d *db.DB) *PromotionChannelsHandler {
+	return &PromotionChannelsHandler{cfg: cfg, db: d}
+}
+
+type promotionChannelRequest struct {
+	Kind    *string `json:"kind"`
+	Name    *string `json:"name"`
+	URL     *string `json:"url"`
+	Enabled *bool   `json:"enabled"`
+}
+
+// List serves GET /admin/ecosystems/:id/promotion-channels. Webhook URLs are shown masked. Admin only
+// (route-guarded).
+func (h *PromotionChannelsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		key, _ := cryptox.KeyFromB64(h.cfg.TokenEncKey())
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT id, kind, name, url_enc, enabled, created_at, updated_at
+FROM promotion_channels
+WHERE ecosystem_id = $1
+ORDER BY created_at
+`, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channels_lookup_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var kind, name string
+			var urlEnc []byte
+			var enabled bool
+			var createdAt, updatedAt time.Time
+			if err := rows.Scan(&id, &kind, &name, &urlEnc, &enabled, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channels_lookup_failed"})
+			}
+			hint := ""
+			if key != nil {
+				if raw, err := cryptox.DecryptAESGCM(key, urlEnc); err == nil {
+					hint = maskWebhookURL(string(raw))
+				}
+			}
+			out = append(out, fiber.Map{
+				"id":         id.String(),
+				"kind":       kind,
+				"name":       name,
+				"url_hint":   hint,
+				"enabled":    enabled,
+				"created_at": createdAt,
+				"updated_at": updatedAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channels_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"channels": out})
+	}
+}
+
+// Create serves POST /admin/ecosystems/:id/promotion-channels. Admin only (route-guarded).
+func (h *PromotionChannelsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req promotionChannelRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if req.Kind == nil || req.Name == nil || req.URL == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kind_name_and_url_required"})
+		}
+		kind := strings.ToLower(strings.TrimSpace(*req.Kind))
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || utf8.RuneCountInString(name) > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_name"})
+		}
+		urlEnc, status, body := h.encryptURL(kind, *req.URL)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		enabled := req.Enabled == nil || *req.Enabled
+
+		var id uuid.UUID
+		if err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO promotion_channels (ecosystem_id, kind, name, url_enc, enabled)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id
+`, ecoID, kind, name, urlEnc, enabled).Scan(&id); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channel_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"id":       id.String(),
+			"kind":     kind,
+			"name":     name,
+			"url_hint": maskWebhookURL(strings.TrimSpace(*req.URL)),
+			"enabled":  enabled,
+		})
+	}
+}
+
+// Update serves PATCH /admin/ecosystems/:id/promotion-channels/:channelId: rename, replace the URL, or
+// enable/disable. Disabling keeps queued deliveries; they go out if the channel is enabled again. Admin
+// only (route-guarded).
+func (h *PromotionChannelsHandler) Update() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		channelID, err := uuid.Parse(c.Params("channelId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_channel_id"})
+		}
+		var req promotionChannelRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if req.Kind != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kind_cannot_change"})
+		}
+
+		var kind string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT kind FROM promotion_channels WHERE id = $1 AND ecosystem_id = $2
+`, channelID, ecoID).Scan(&kind)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "promotion_channel_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channel_lookup_failed"})
+		}
+
+		var name *string
+		if req.Name != nil {
+			n := strings.TrimSpace(*req.Name)
+			if n == "" || utf8.RuneCountInString(n) > 100 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_name"})
+			}
+			name = &n
+		}
+		var urlEnc []byte
+		if req.URL != nil {
+			enc, status, body := h.encryptURL(kind, *req.URL)
+			if status != fiber.StatusOK {
+				return c.Status(status).JSON(body)
+			}
+			urlEnc = enc
+		}
+
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE promotion_channels SET
+  name = COALESCE($3, name),
+  url_enc = COALESCE($4, url_enc),
+  enabled = COALESCE($5, enabled),
+  updated_at = now()
+WHERE id = $1 AND ecosystem_id = $2
+`, channelID, ecoID, name, urlEnc, req.Enabled); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channel_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Delete serves DELETE /admin/ecosystems/:id/promotion-channels/:channelId, dropping its queued
+// deliveries. Admin only (route-guarded).
+func (h *PromotionChannelsHandler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ecoID, status, code := h.ecosystem(c)
+		if code != "" {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		channelID, err := uuid.Parse(c.Params("channelId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_channel_id"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM promotion_channels WHERE id = $1 AND ecosystem_id = $2
+`, channelID, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_channel_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "promotion_channel_not_found"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+func (h *PromotionChannelsHandler) ecosystem(c *fiber.Ctx) (uuid.UUID, int, string) {
+	if h.db == nil || h.db.Pool == nil {
+		return uuid.Nil, fiber.StatusServiceUnavailable, "db_not_configured"
+	}
+	ecoID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, fiber.StatusBadRequest, "invalid_ecosystem_id"
+	}
+	var exists bool
+	if err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM ecosystems WHERE id = $1)`, ecoID).Scan(&exists); err != nil {
+		return uuid.Nil, fiber.StatusInternalServerError, "ecosystem_lookup_failed"
+	}
+	if !exists {
+		return uuid.Nil, fiber.StatusNotFound, "ecosystem_not_found"
+	}
+	return ecoID, 0, ""
+}
+
+// encryptURL validates a channel URL for kind and encrypts it, or returns the error response to send.
+func (h *PromotionChannelsHandler) encryptURL(kind, raw string) ([]byte, int, fiber.Map) {
+	raw = strings.TrimSpace(raw)
+	if err := promotions.ValidateURL(kind, raw); err != nil {
+		return nil, fiber.StatusBadRequest, fiber.Map{"error": "invalid_channel_url", "message": err.Error()}
+	}
+	key, err := cryptox.KeyFromB64(h.cfg.TokenEncKey())
+	if err != nil {
+		return nil, fiber.StatusServiceUnavailable, fiber.Map{"error": "token_encryption_not_configured"}
+	}
+	enc, err := cryptox.EncryptAESGCM(key, []byte(raw))
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, fiber.Map{"error": "url_encrypt_failed"}
+	}
+	return enc, fiber.StatusOK, nil
+}
+
+// maskWebhookURL keeps the host and the last four characters; the rest of a webhook URL is its secret.
+func maskWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	tail := ""
+	if len(raw) > 4 {
+		tail = raw[len(raw)-4:]
+	}
+	return u.Scheme + "://" + u.Host + "/…" + tail
+}
+
+type promoteIssueRequest struct {
+	// At schedules the posts; empty means now.
+	At *time.Time `json:"at"`
+}
+
+// PromoteIssue serves POST /projects/:id/issues/:number/promote: marks the issue promoted and queues a
+// post to each enabled promotion channel of the project's ecosystem. Promoting again reschedules posts
+// not yet sent; a channel never gets the same issue twice. Maintainer only.
+func (h *IssueApplicationsHandler) PromoteIssue() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req promoteIssueRequest
+		if len(c.Body()) > 0 {
+			if err := json.Unmarshal(c.Body(), &req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+			}
+		}
+		projectID, userID, issueNumber, status, body := h.promotionIssue(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+
+		at := time.Now()
+		if req.At != nil && req.At.After(at) {
+			if req.At.Sub(at) > maxPromotionDelay {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "schedule_too_far_ahead"})
+			}
+			at = *req.At
+		}
+
+		var state string
+		var promotedAt *time.Time
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT COALESCE(state, ''), promoted_at FROM github_issues WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber).Scan(&state, &promotedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_lookup_failed"})
+		}
+		if state != "open" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "issue_not_open"})
+		}
+
+		if err := h.db.Pool.QueryRow(c.Context(), `
+UPDATE github_issues SET promoted_at = COALESCE(promoted_at, now()), promoted_by_user_id = COALESCE(promoted_by_user_id, $3)
+WHERE project_id = $1 AND number = $2
+RETURNING promoted_at
+`, projectID, issueNumber, userID).Scan(&promotedAt); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_promote_failed"})
+		}
+		queued, err := promotions.Enqueue(c.Context(), h.db.Pool, projectID, issueNumber, at)
+		if err != nil {
+			slog.Error("failed to queue issue promotions", "project_id", projectID.String(), "issue_number", issueNumber, "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_queue_failed"})
+		}
+		list, err := h.issuePromotions(c, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotions_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"promoted_at":   promotedAt,
+			"scheduled_for": at,
+			"queued":        queued,
+			"promotions":    list,
+		})
+	}
+}
+
+// UnpromoteIssue serves DELETE /projects/:id/issues/:number/promote: clears the promoted mark and cancels
+// posts not yet sent. Maintainer only.
+func (h *IssueApplicationsHandler) UnpromoteIssue() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, issueNumber, status, body := h.promotionIssue(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE github_issues SET promoted_at = NULL, promoted_by_user_id = NULL
+WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issue_unpromote_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue_not_found"})
+		}
+		cancelled, err := promotions.Cancel(c.Context(), h.db.Pool, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotion_cancel_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "cancelled": cancelled})
+	}
+}
+
+// IssuePromotions serves GET /projects/:id/issues/:number/promotions: the post to each channel and its
+// delivery status. Maintainer only.
+func (h *IssueApplicationsHandler) IssuePromotions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, issueNumber, status, body := h.promotionIssue(c)
+		if status != fiber.StatusOK {
+			return c.Status(status).JSON(body)
+		}
+		list, err := h.issuePromotions(c, projectID, issueNumber)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "promotions_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"promotions": list})
+	}
+}
+
+func (h *IssueApplicationsHandler) promotionIssue(c *fiber.Ctx) (uuid.UUID, uuid.UUID, int, int, fiber.Map) {
+	issueNumber, err := c.ParamsInt("number")
+	if err != nil || issueNumber <= 0 {
+		return uuid.Nil, uuid.Nil, 0, fiber.StatusBadRequest, fiber.Map{"error": "invalid_issue_number"}
+	}
+	projectID, userID, status, body := h.draftProject(c)
+	return projectID, userID, issueNumber, status, body
+}
+
+func (h *IssueApplicationsHandler) issuePromotions(c *fiber.Ctx, projectID uuid.UUID, issueNumber int) ([]fiber.Map, error) {
+	rows, err := h.db.Pool.Query(c.Context(), `
+SELECT ip.id, pc.id, pc.kind, pc.name, ip.status, ip.scheduled_for, ip.attempts, ip.last_error, ip.sent_at
+FROM issue_promotions ip
+JOIN promotion_channels pc ON pc.id = ip.channel_id
+WHERE ip.project_id = $1 AND ip.issue_number = $2
+ORDER BY pc.name
+`, projectID, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []fiber.Map{}
+	for rows.Next() {
+		var id, channelID uuid.UUID
+		var kind, name, status string
+		var scheduledFor time.Time
+		var attempts int
+		var lastError *string
+		var sentAt *time.Time
+		if err := rows.Scan(&id, &channelID, &kind, &name, &status, &scheduledFor, &attempts, &lastError, &sentAt); err != nil {
+			return nil, err
+		}
+		out = append(out, fiber.Map{
+			"id":            id.String(),
+			"channel_id":    channelID.String(),
+			"channel_kind":  kind,
+			"channel_name":  name,
+			"status":        status,
+			"scheduled_for": scheduledFor,
+			"attempts":      attempts,
+			"last_error":    lastError,
+			"sent_at":       sentAt,
+		})
+	}
+	return out, rows.Err()
+}
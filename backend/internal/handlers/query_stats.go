@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+// QueryStatsHandler reports the database's most expensive statements from pg_stat_statements, to find
+// what to index or rewrite next and to compare before and after a change.
+type QueryStatsHandler struct {
+	db *db.DB
+}
+
+func NewQueryStatsHandler(d *db.DB) *QueryStatsHandler {
+	return &QueryStatsHandler{db: d}
+}
+
+// queryStatsOrder maps ?sort= to the pg_stat_statements column it orders by.
+var queryStatsOrder = map[string]string{
+	"total": "total_exec_time",
+	"mean":  "mean_exec_time",
+	"max":   "max_exec_time",
+	"calls": "calls",
+	"rows":  "rows",
+}
+
+// available reports whether pg_stat_statements can be queried: the extension is installed and its
+// module loaded (reading the view fails otherwise).
+func (h *QueryStatsHandler) available(c *fiber.Ctx) bool {
+	var ok bool
+	err := h.db.Pool.QueryRow(c.Context(), `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')`).Scan(&ok)
+	if err != nil || !ok {
+		return false
+	}
+	_, err = h.db.Pool.Exec(c.Context(), `SELECT 1 FROM pg_stat_statements LIMIT 1`)
+	return err == nil
+}
+
+// Report serves GET /admin/query-stats: this database's statements ordered by ?sort= (total, the
+// default, mean, max, calls or rows), at most ?limit= (default 50, max 500). Times are in milliseconds;
+// cache_hit_ratio is the share of blocks read from shared buffers.
+func (h *QueryStatsHandler) Report() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		order, ok := queryStatsOrder[c.Query("sort", "total")]
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_sort"})
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit <= 0 || limit > 500 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_limit"})
+		}
+		if !h.available(c) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "pg_stat_statements_unavailable"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT queryid, query, calls, total_exec_time, mean_exec_time, max_exec_time, rows,
+       shared_blks_hit, shared_blks_read
+FROM pg_stat_statements
+WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+ORDER BY `+order+` DESC
+LIMIT $1
+`, limit)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "query_stats_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var queryID *int64
+			var query string
+			var calls, rowCount, hit, read int64
+			var total, mean, maxTime float64
+			if err := rows.Scan(&queryID, &query, &calls, &total, &mean, &maxTime, &rowCount, &hit, &read); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "query_stats_failed"})
+			}
+			var hitRatio *float64
+			if hit+read > 0 {
+				r := float64(hit) / float64(hit+read)
+				hitRatio = &r
+			}
+			out = append(out, fiber.Map{
+				"query_id":        queryID,
+				"query":           query,
+				"calls":           calls,
+				"total_ms":        total,
+				"mean_ms":         mean,
+				"max_ms":          maxTime,
+				"rows":            rowCount,
+				"cache_hit_ratio": hitRatio,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "query_stats_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"statements": out})
+	}
+}
+
+// Reset serves POST /admin/query-stats/reset: clears the statistics, to measure a change from a clean slate.
+func (h *QueryStatsHandler) Reset() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if !h.available(c) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "pg_stat_statements_unavailable"})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `SELECT pg_stat_statements_reset()`); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "query_stats_reset_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+)
+
+type RecommendationsHandler struct {
+	db *db.DB
+}
+
+func NewRecommendationsHandler(d *db.DB) *RecommendationsHandler {
+	return &RecommendationsHandler{db: d}
+}
+
+// Issues serves GET /me/recommended-issues: the caller's stored recommendations (see package recommend)
+// that are still open and unassigned, best first, each with the reasons it was picked.
+func (h *RecommendationsHandler) Issues() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		limit := c.QueryInt("limit", 20)
+		if limit <= 0 || limit > recommend.PerUser {
+			limit = 20
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT gi.id, p.id, p.github_full_name, gi.number, COALESCE(gi.title, ''), COALESCE(gi.url, ''),
+       COALESCE(gi.labels, '[]'::jsonb), gi.points, p.language, e.name, r.score, r.reasons, r.computed_at
+FROM issue_recommendations r
+JOIN github_issues gi ON gi.id = r.issue_id
+JOIN projects p ON p.id = gi.project_id
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE r.user_id = $1
+  AND gi.state = 'open' AND COALESCE(jsonb_array_length(gi.assignees), 0) = 0
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM issue_applications a
+    WHERE a.project_id = gi.project_id AND a.issue_number = gi.number AND a.user_id = r.user_id
+  )
+ORDER BY r.score DESC
+LIMIT $2
+`, userID, limit)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "recommendations_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		var computedAt *time.Time
+		for rows.Next() {
+			var issueID, projectID uuid.UUID
+			var fullName, title, url string
+			var number int
+			var labelsJSON, reasonsJSON []byte
+			var points *int
+			var language, ecosystem *string
+			var score float64
+			var at time.Time
+			if err := rows.Scan(&issueID, &projectID, &fullName, &number, &title, &url, &labelsJSON, &points,
+				&language, &ecosystem, &score, &reasonsJSON, &at); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "recommendations_failed"})
+			}
+			if computedAt == nil || at.After(*computedAt) {
+				computedAt = &at
+			}
+			var labels []any
+			_ = json.Unmarshal(labelsJSON, &labels)
+			out = append(out, fiber.Map{
+				"issue": fiber.Map{
+					"id":               issueID.String(),
+					"project_id":       projectID.String(),
+					"github_full_name": fullName,
+					"number":           number,
+					"title":            title,
+					"url":              url,
+					"labels":           labels,
+					"points":           points,
+					"language":         language,
+					"ecosystem_name":   ecosystem,
+				},
+				"score":   math.Round(score*100) / 100,
+				"reasons": json.RawMessage(reasonsJSON),
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "recommendations_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"recommendations": out, "computed_at": computedAt})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Reviewers returns code reviewers ranked by reviews submitted on other people's PRs in verified projects.
+// Scoped to a project when mounted under /projects/:id, or to an ecosystem with ?ecosystem=<slug>.
+// Self-reviews and bot accounts are not counted.
+func (h *LeaderboardHandler) Reviewers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		limit := c.QueryInt("limit", 10)
+		if limit < 1 {
+			limit = 10
+		}
+		if limit > 100 {
+			limit = 100
+		}
+		offset := c.QueryInt("offset", 0)
+		if offset < 0 {
+			offset = 0
+		}
+
+		var projectID *uuid.UUID
+		if raw := c.Params("id"); raw != "" {
+			pid, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+			}
+			projectID = &pid
+		}
+		var ecosystemSlug *string
+		if slug := strings.TrimSpace(c.Query("ecosystem")); slug != "" {
+			ecosystemSlug = &slug
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+WITH scoped_projects AS (
+  SELECT p.id
+  FROM projects p
+  LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+  WHERE p.status = 'verified' AND p.deleted_at IS NULL
+    AND ($1::uuid IS NULL OR p.id = $1)
+    AND ($2::text IS NULL OR e.slug = $2)
+),
+reviews AS (
+  SELECT r.reviewer_login AS login, r.project_id, r.pr_number, r.state
+  FROM github_pr_reviews r
+  JOIN scoped_projects sp ON sp.id = r.project_id
+  JOIN github_pull_requests pr ON pr.project_id = r.project_id AND pr.number = r.pr_number
+  WHERE LOWER(r.reviewer_login) <> LOWER(COALESCE(pr.author_login, ''))
+    AND r.reviewer_login NOT LIKE '%[bot]'
+),
+review_comments AS (
+  SELECT rc.reviewer_login AS login, COUNT(*) AS cnt
+  FROM github_pr_review_comments rc
+  JOIN scoped_projects sp ON sp.id = rc.project_id
+  JOIN github_pull_requests pr ON pr.project_id = rc.project_id AND pr.number = rc.pr_number
+  WHERE LOWER(rc.reviewer_login) <> LOWER(COALESCE(pr.author_login, ''))
+    AND rc.reviewer_login NOT LIKE '%[bot]'
+  GROUP BY rc.reviewer_login
+),
+stats AS (
+  SELECT
+    MIN(login) AS login,
+    COUNT(*) AS reviews,
+    COUNT(DISTINCT (project_id, pr_number)) AS prs_reviewed,
+    COUNT(*) FILTER (WHERE state = 'APPROVED') AS approvals,
+    COUNT(*) FILTER (WHERE state = 'CHANGES_REQUESTED') AS changes_requested
+  FROM reviews
+  GROUP BY LOWER(login)
+)
+SELECT
+  s.login,
+  COALESCE(ga.avatar_url, ''),
+  COALESCE(ga.user_id::text, ''),
+  s.reviews,
+  s.prs_reviewed,
+  s.approvals,
+  s.changes_requested,
+  COALESCE((SELECT SUM(rc.cnt) FROM review_comments rc WHERE LOWER(rc.login) = LOWER(s.login)), 0)::bigint
+FROM stats s
+LEFT JOIN github_accounts ga ON LOWER(ga.login) = LOWER(s.login)
+ORDER BY s.prs_reviewed DESC, s.reviews DESC, s.login ASC
+LIMIT $3 OFFSET $4
+`, projectID, ecosystemSlug, limit, offset)
+		if err != nil {
+			slog.Error("failed to fetch reviewer leaderboard", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reviewers_fetch_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		rank := offset + 1
+		for rows.Next() {
+			var login, avatar, userID string
+			var reviews, prsReviewed, approvals, changesRequested, reviewComments int64
+			if err := rows.Scan(&login, &avatar, &userID, &reviews, &prsReviewed, &approvals, &changesRequested, &reviewComments); err != nil {
+				slog.Error("failed to scan reviewer row", "error", err)
+				continue
+			}
+			if avatar == "" {
+				avatar = fmt.Sprintf("https://github.com/%s.png?size=200", login)
+			}
+			out = append(out, fiber.Map{
+				"rank":              rank,
+				"username":          login,
+				"avatar":            avatar,
+				"user_id":           userID,
+				"prs_reviewed":      prsReviewed,
+				"reviews":           reviews,
+				"approvals":         approvals,
+				"changes_requested": changesRequested,
+				"review_comments":   reviewComments,
+			})
+			rank++
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
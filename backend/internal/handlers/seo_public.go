@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/publicstats"
+	"github.com/jagadeesh/grainlify/backend/internal/seo"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+// SEOHandler serves sitemap.xml and the small page payloads the marketing site renders server side for
+// public project and ecosystem pages.
+type SEOHandler struct {
+	cfg   config.Config
+	db    *db.DB
+	stats *publicstats.Cache
+}
+
+func NewSEOHandler(cfg config.Config, d *db.DB, stats *publicstats.Cache) *SEOHandler {
+	return &SEOHandler{cfg: cfg, db: d, stats: stats}
+}
+
+// site returns the base URL public pages live under: the tenant's frontend, SITE_BASE_URL, or
+// FRONTEND_BASE_URL.
+func (h *SEOHandler) site(c *fiber.Ctx) (string, string) {
+	name := "Grainlify"
+	t := tenant.FromCtx(c)
+	if t != nil && !t.IsDefault {
+		name = t.Name
+		if t.FrontendURL != nil && *t.FrontendURL != "" {
+			return strings.TrimSuffix(*t.FrontendURL, "/"), name
+		}
+	}
+	if h.cfg.SiteBaseURL != "" {
+		return strings.TrimSuffix(h.cfg.SiteBaseURL, "/"), name
+	}
+	return strings.TrimSuffix(h.cfg.FrontendBaseURL, "/"), name
+}
+
+// Sitemap serves GET /sitemap.xml: active ecosystems and verified projects.
+func (h *SEOHandler) Sitemap() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		site, _ := h.site(c)
+		if site == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "site_url_not_configured"})
+		}
+
+		urls := []seo.URL{{Loc: site + "/", ChangeFreq: "daily", Priority: "1.0"}}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT slug, updated_at FROM ecosystems WHERE status = 'active' ORDER BY name
+`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sitemap_failed"})
+		}
+		for rows.Next() {
+			var slug string
+			var updatedAt time.Time
+			if err := rows.Scan(&slug, &updatedAt); err != nil {
+				rows.Close()
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sitemap_failed"})
+			}
+			urls = append(urls, seo.URL{Loc: seo.EcosystemURL(site, slug), LastMod: seo.LastMod(updatedAt), ChangeFreq: "daily", Priority: "0.8"})
+		}
+		rows.Close()
+
+		rows, err = h.db.Pool.Query(c.Context(), `
+SELECT p.github_full_name, GREATEST(p.updated_at, COALESCE(MAX(gi.updated_at_github), p.updated_at))
+FROM projects p
+LEFT JOIN github_issues gi ON gi.project_id = p.id
+WHERE p.status = 'verified' AND p.deleted_at IS NULL AND p.needs_metadata = false
+GROUP BY p.id
+ORDER BY p.github_full_name
+LIMIT $1
+`, seo.MaxURLs-len(urls))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sitemap_failed"})
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var fullName string
+			var lastMod time.Time
+			if err := rows.Scan(&fullName, &lastMod); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sitemap_failed"})
+			}
+			urls = append(urls, seo.URL{Loc: seo.ProjectURL(site, fullName), LastMod: seo.LastMod(lastMod), ChangeFreq: "daily", Priority: "0.6"})
+		}
+
+		var buf bytes.Buffer
+		if err := seo.WriteSitemap(&buf, urls); err != nil {
+			slog.Error("failed to write sitemap", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sitemap_failed"})
+		}
+		c.Set("Cache-Control", "public, max-age=3600")
+		c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+}
+
+// Project serves GET /public/pages/projects/:owner/:repo.
+func (h *SEOHandler) Project() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		fullName := c.Params("owner") + "/" + c.Params("repo")
+		stats, err := h.stats.Get(tenant.IDFromContext(c.Context())+":project:"+fullName, func() (publicstats.Stats, error) {
+			return publicstats.Project(c.Context(), h.db.Pool, fullName)
+		})
+		if errors.Is(err, publicstats.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+
+		var description, language, category, ecoName, ecoSlug *string
+		var tagsJSON []byte
+		var stars, forks *int
+		var updatedAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT p.description, p.language, p.category, p.tags, p.stars_count, p.forks_count, p.updated_at, e.name, e.slug
+FROM projects p
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE p.id = $1
+`, stats.ID).Scan(&description, &language, &category, &tagsJSON, &stars, &forks, &updatedAt, &ecoName, &ecoSlug)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "project_lookup_failed"})
+		}
+
+		site, siteName := h.site(c)
+		pageURL := seo.ProjectURL(site, stats.Name)
+		desc := ""
+		if description != nil {
+			desc = *description
+		}
+		if desc == "" {
+			desc = fmt.Sprintf("%d open issue%s to contribute to on %s.", stats.OpenBounties, plural(stats.OpenBounties), stats.Name)
+		}
+		var ecosystem fiber.Map
+		if ecoSlug != nil {
+			ecosystem = fiber.Map{"name": ecoName, "slug": ecoSlug, "url": seo.EcosystemURL(site, *ecoSlug)}
+		}
+
+		c.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.stats.TTL().Seconds())))
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"id":               stats.ID.String(),
+			"github_full_name": stats.Name,
+			"description":      description,
+			"language":         language,
+			"category":         category,
+			"tags":             rawJSONOr(tagsJSON, "[]"),
+			"stars_count":      stars,
+			"forks_count":      forks,
+			"ecosystem":        ecosystem,
+			"stats":            stats,
+			"updated_at":       updatedAt,
+			"url":              pageURL,
+			"opengraph": seo.NewOpenGraph(siteName, stats.Name+" on "+siteName, desc, pageURL,
+				"https://opengraph.githubassets.com/1/"+stats.Name),
+		})
+	}
+}
+
+// Ecosystem serves GET /public/pages/ecosystems/:slug.
+func (h *SEOHandler) Ecosystem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		slug := c.Params("slug")
+		stats, err := h.stats.Get(tenant.IDFromContext(c.Context())+":ecosystem:"+slug, func() (publicstats.Stats, error) {
+			return publicstats.Ecosystem(c.Context(), h.db.Pool, slug)
+		})
+		if errors.Is(err, publicstats.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+		}
+
+		var description, about, website, logoURL *string
+		var technologiesJSON []byte
+		var updatedAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT description, about, website_url, logo_url, technologies, updated_at
+FROM ecosystems
+WHERE id = $1
+`, stats.ID).Scan(&description, &about, &website, &logoURL, &technologiesJSON, &updatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+		}
+
+		site, siteName := h.site(c)
+		pageURL := seo.EcosystemURL(site, slug)
+		desc := ""
+		if description != nil {
+			desc = *description
+		}
+		if desc == "" {
+			desc = fmt.Sprintf("%d project%s and %d open issue%s in the %s ecosystem.",
+				stats.Projects, plural(stats.Projects), stats.OpenBounties, plural(stats.OpenBounties), stats.Name)
+		}
+		image := ""
+		if logoURL != nil {
+			image = *logoURL
+		}
+
+		c.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.stats.TTL().Seconds())))
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"id":           stats.ID.String(),
+			"slug":         slug,
+			"name":         stats.Name,
+			"description":  description,
+			"about":        about,
+			"website_url":  website,
+			"logo_url":     logoURL,
+			"technologies": rawJSONOr(technologiesJSON, "[]"),
+			"stats":        stats,
+			"updated_at":   updatedAt,
+			"url":          pageURL,
+			"opengraph":    seo.NewOpenGraph(siteName, stats.Name+" on "+siteName, desc, pageURL, image),
+		})
+	}
+}
+
+// rawJSONOr passes a JSONB column through as-is, or def when it is NULL.
+func rawJSONOr(b []byte, def string) json.RawMessage {
+	if len(b) == 0 {
+		return json.RawMessage(def)
+	}
+	return json.RawMessage(b)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// EcosystemIssuesFeed serves GET /ecosystems/:id/issues.atom (:id may also be the slug): the newest
+// open, unassigned issues with points in the ecosystem's verified projects, for feed readers.
+func (h *SEOHandler) EcosystemIssuesFeed() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var ecoID uuid.UUID
+		var slug, name string
+		var logoURL *string
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT id, slug, name, logo_url
+FROM ecosystems
+WHERE (id::text = $1 OR slug = $1) AND status = 'active'
+`, c.Params("id")).Scan(&ecoID, &slug, &name, &logoURL)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "ecosystem_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "ecosystem_lookup_failed"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT p.github_full_name, gi.number, COALESCE(gi.title, ''), COALESCE(gi.url, ''), COALESCE(gi.author_login, ''),
+       gi.points, COALESCE(gi.labels, '[]'::jsonb), gi.created_at_github, gi.updated_at_github
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE p.ecosystem_id = $1 AND p.status = 'verified' AND p.deleted_at IS NULL
+  AND gi.state = 'open' AND COALESCE(jsonb_array_length(gi.assignees), 0) = 0 AND gi.points > 0
+ORDER BY gi.created_at_github DESC NULLS LAST
+LIMIT 50
+`, ecoID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "feed_failed"})
+		}
+		defer rows.Close()
+
+		site, siteName := h.site(c)
+		self := strings.TrimSuffix(h.cfg.PublicBaseURL, "/") + "/ecosystems/" + ecoID.String() + "/issues.atom"
+		feed := seo.Feed{
+			ID:       self,
+			Title:    name + " issues open for contributors",
+			Subtitle: "Unassigned issues with rewards in " + name + " projects on " + siteName,
+			Links:    []seo.Link{{Rel: "self", Type: "application/atom+xml", Href: self}},
+		}
+		if site != "" {
+			feed.Links = append(feed.Links, seo.Link{Rel: "alternate", Type: "text/html", Href: seo.EcosystemURL(site, slug)})
+		}
+		if logoURL != nil {
+			feed.Icon = *logoURL
+		}
+		for rows.Next() {
+			var fullName, title, url, author string
+			var number, points int
+			var labelsJSON []byte
+			var createdAt, updatedAt *time.Time
+			if err := rows.Scan(&fullName, &number, &title, &url, &author, &points, &labelsJSON, &createdAt, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "feed_failed"})
+			}
+			e := seo.Entry{
+				ID:      url,
+				Title:   fmt.Sprintf("[%s#%d] %s (%d point%s)", fullName, number, title, points, plural(points)),
+				Links:   []seo.Link{{Rel: "alternate", Type: "text/html", Href: url}},
+				Summary: &seo.Text{Type: "text", Body: fmt.Sprintf("%s#%d is open and unassigned, worth %d point%s.", fullName, number, points, plural(points))},
+			}
+			if createdAt != nil {
+				e.Published = seo.AtomTime(*createdAt)
+				e.Updated = e.Published
+			}
+			if updatedAt != nil {
+				e.Updated = seo.AtomTime(*updatedAt)
+			}
+			if author != "" {
+				e.Author = &seo.Person{Name: author, URI: "https://github.com/" + author}
+			}
+			var labels []struct {
+				Name string `json:"name"`
+			}
+			_ = json.Unmarshal(labelsJSON, &labels)
+			for _, l := range labels {
+				if l.Name != "" {
+					e.Categories = append(e.Categories, seo.Category{Term: l.Name})
+				}
+			}
+			feed.Entries = append(feed.Entries, e)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "feed_failed"})
+		}
+
+		var buf bytes.Buffer
+		if err := seo.WriteFeed(&buf, feed); err != nil {
+			slog.Error("failed to write atom feed", "ecosystem_id", ecoID, "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "feed_failed"})
+		}
+		c.Set("Cache-Control", "public, max-age=600")
+		c.Set(fiber.HeaderContentType, "application/atom+xml; charset=utf-8")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+}
@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/oidc"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+// SSOHandler is OpenID Connect single sign-on for a tenant's admins: the IdP configuration (per tenant,
+// managed by its admins), the sign-in flow, and enforcement on admin endpoints.
+type SSOHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewSSOHandler(cfg config.Config, d *db.DB) *SSOHandler {
+	return &SSOHandler{cfg: cfg, db: d}
+}
+
+type ssoConfig struct {
+	issuer           string
+	clientID         string
+	clientSecretEnc  []byte
+	allowedDomains   []string
+	enabled          bool
+	requireForAdmins bool
+	updatedAt        time.Time
+}
+
+func (h *SSOHandler) load(c *fiber.Ctx, tenantID uuid.UUID) (ssoConfig, error) {
+	var s ssoConfig
+	err := h.db.Pool.QueryRow(c.Context(), `
+SELECT issuer, client_id, client_secret_enc, allowed_domains, enabled, require_for_admins, updated_at
+FROM tenant_sso
+WHERE tenant_id = $1
+`, tenantID).Scan(&s.issuer, &s.clientID, &s.clientSecretEnc, &s.allowedDomains, &s.enabled, &s.requireForAdmins, &s.updatedAt)
+	return s, err
+}
+
+// redirectURL is the callback to register with the IdP.
+func (h *SSOHandler) redirectURL() string {
+	return strings.TrimSuffix(h.cfg.PublicBaseURL, "/") + "/auth/sso/callback"
+}
+
+// requestTenant is the tenant serving the request; requests the tenant middleware skips count as the
+// default tenant.
+func requestTenant(c *fiber.Ctx) uuid.UUID {
+	if t := tenant.FromCtx(c); t != nil {
+		return t.ID
+	}
+	return tenant.DefaultID
+}
+
+// Config serves GET /admin/sso: this tenant's SSO settings, without the client secret. Admin only.
+func (h *SSOHandler) Config() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		s, err := h.load(c, requestTenant(c))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"configured": false, "redirect_url": h.redirectURL()})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sso_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"configured":         true,
+			"issuer":             s.issuer,
+			"client_id":          s.clientID,
+			"allowed_domains":    s.allowedDomains,
+			"enabled":            s.enabled,
+			"require_for_admins": s.requireForAdmins,
+			"redirect_url":       h.redirectURL(),
+			"updated_at":         s.updatedAt,
+		})
+	}
+}
+
+type ssoConfigRequest struct {
+	Issuer           string   `json:"issuer"`
+	ClientID         string   `json:"client_id"`
+	ClientSecret     string   `json:"client_secret"`
+	AllowedDomains   []string `json:"allowed_domains"`
+	Enabled          *bool    `json:"enabled"`
+	RequireForAdmins bool     `json:"require_for_admins"`
+}
+
+// UpdateConfig serves PUT /admin/sso. The client secret may be omitted to keep the stored one. Turning on
+// require_for_admins needs a session that itself signed in through this tenant's SSO, so a broken IdP
+// configuration can't lock every admin out. Admin only.
+func (h *SSOHandler) UpdateConfig() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if strings.TrimSpace(h.cfg.PublicBaseURL) == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "public_base_url_not_configured"})
+		}
+		var req ssoConfigRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Issuer = strings.TrimSuffix(strings.TrimSpace(req.Issuer), "/")
+		req.ClientID = strings.TrimSpace(req.ClientID)
+		if err := oidc.ValidateIssuer(req.Issuer); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_issuer", "message": err.Error()})
+		}
+		if req.ClientID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "client_id_required"})
+		}
+		domains := make([]string, 0, len(req.AllowedDomains))
+		for _, d := range req.AllowedDomains {
+			d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+			if d == "" || !strings.Contains(d, ".") || strings.ContainsAny(d, "@/ ") {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_domain", "domain": d})
+			}
+			domains = append(domains, d)
+		}
+		enabled := req.Enabled == nil || *req.Enabled
+
+		tenantID := requestTenant(c)
+		if req.RequireForAdmins {
+			if sso, _ := c.Locals(auth.LocalSSOTenant).(string); sso != tenantID.String() {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "sso_session_required_to_enforce"})
+			}
+			if !enabled {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot_require_disabled_sso"})
+			}
+		}
+		if _, err := oidc.Discover(c.Context(), req.Issuer); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "issuer_discovery_failed", "message": err.Error()})
+		}
+
+		var secretEnc []byte
+		if req.ClientSecret != "" {
+			key, err := cryptox.KeyFromB64(h.cfg.TokenEncKey())
+			if err != nil {
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "token_encryption_not_configured"})
+			}
+			if secretEnc, err = cryptox.EncryptAESGCM(key, []byte(req.ClientSecret)); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "secret_encrypt_failed"})
+			}
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO tenant_sso (tenant_id, issuer, client_id, client_secret_enc, allowed_domains, enabled, require_for_admins)
+SELECT $1, $2, $3, $4, $5, $6, $7
+WHERE $4::bytea IS NOT NULL OR EXISTS (SELECT 1 FROM tenant_sso WHERE tenant_id = $1)
+ON CONFLICT (tenant_id) DO UPDATE SET
+  issuer = EXCLUDED.issuer,
+  client_id = EXCLUDED.client_id,
+  client_secret_enc = COALESCE($4, tenant_sso.client_secret_enc),
+  allowed_domains = EXCLUDED.allowed_domains,
+  enabled = EXCLUDED.enabled,
+  require_for_admins = EXCLUDED.require_for_admins,
+  updated_at = now()
+`, tenantID, req.Issuer, req.ClientID, secretEnc, domains, enabled, req.RequireForAdmins)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sso_save_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "client_secret_required"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "redirect_url": h.redirectURL()})
+	}
+}
+
+// DeleteConfig serves DELETE /admin/sso, removing the tenant's SSO (and with it the admin requirement).
+// Admin only.
+func (h *SSOHandler) DeleteConfig() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `DELETE FROM tenant_sso WHERE tenant_id = $1`, requestTenant(c))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sso_delete_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "sso_not_configured"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Start serves GET /auth/sso/start: sign-in through the IdP of the tenant serving the request. Accepts the
+// same 'redirect' query parameter as the other logins.
+func (h *SSOHandler) Start() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		tenantID := requestTenant(c)
+		s, err := h.load(c, tenantID)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && !s.enabled) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "sso_not_configured"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sso_lookup_failed"})
+		}
+		redirectURI := c.Query("redirect")
+		if code := checkLoginRedirect(c, h.cfg, redirectURI); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		p, err := oidc.Discover(c.Context(), s.issuer)
+		if err != nil {
+			slog.Warn("sso discovery failed", "tenant_id", tenantID.String(), "error", err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "issuer_discovery_failed"})
+		}
+
+		state, nonce, verifier := oidc.Random(), oidc.Random(), oidc.Random()
+		_, err = h.db.Pool.Exec(c.Context(), `
+INSERT INTO oauth_states (state, user_id, kind, expires_at, redirect_uri, tenant_id, nonce, code_verifier)
+VALUES ($1, NULL, 'sso_login', $2, $3, $4, $5, $6)
+`, state, time.Now().UTC().Add(10*time.Minute), redirectURI, tenantID, nonce, verifier)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "state_create_failed"})
+		}
+		return c.Redirect(p.AuthorizeURL(s.clientID, h.redirectURL(), state, nonce, verifier), fiber.StatusFound)
+	}
+}
+
+// Callback serves GET /auth/sso/callback, the redirect URL registered with every tenant's IdP. The tenant
+// comes from the stored state, since the IdP redirects to the shared API host.
+func (h *SSOHandler) Callback() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		if h.cfg.JWTSecret == "" {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "jwt_not_configured"})
+		}
+		if e := c.Query("error"); e != "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "sso_denied", "message": e})
+		}
+		code, state := c.Query("code"), c.Query("state")
+		if code == "" || state == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing_code_or_state"})
+		}
+
+		var redirectURI *string
+		var tenantID uuid.UUID
+		var nonce, verifier string
+		err := h.db.Pool.QueryRow(c.Context(), `
+DELETE FROM oauth_states
+WHERE state = $1 AND kind = 'sso_login' AND expires_at > now()
+RETURNING redirect_uri, tenant_id, nonce, code_verifier
+`, state).Scan(&redirectURI, &tenantID, &nonce, &verifier)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_or_expired_state"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "state_lookup_failed"})
+		}
+
+		s, err := h.load(c, tenantID)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && !s.enabled) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "sso_not_configured"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "sso_lookup_failed"})
+		}
+		key, err := cryptox.KeyFromB64(h.cfg.TokenEncKey())
+		if err != nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "token_encryption_not_configured"})
+		}
+		secret, err := cryptox.DecryptAESGCM(key, s.clientSecretEnc)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "secret_decrypt_failed"})
+		}
+		p, err := oidc.Discover(c.Context(), s.issuer)
+		if err != nil {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "issuer_discovery_failed"})
+		}
+		rawIDToken, err := p.Exchange(c.Context(), s.clientID, string(secret), h.redirectURL(), code, verifier)
+		if err != nil {
+			slog.Warn("sso token exchange failed", "tenant_id", tenantID.String(), "error", err)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "token_exchange_failed"})
+		}
+		id, err := oidc.ParseIDToken(rawIDToken, s.issuer, s.clientID, nonce, time.Now())
+		if err != nil {
+			slog.Warn("sso id token rejected", "tenant_id", tenantID.String(), "error", err)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_id_token"})
+		}
+		email, err := auth.NormalizeEmail(id.Email)
+		if err != nil || !id.EmailVerified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "email_not_verified"})
+		}
+		if !oidc.DomainAllowed(email, s.allowedDomains) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "email_domain_not_allowed"})
+		}
+
+		user, err := auth.UpsertIdentityUser(c.Context(), h.db.Pool, auth.ProviderOIDC, s.issuer+"|"+id.Subject, email)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_upsert_failed"})
+		}
+		token, err := auth.IssueSSOJWT(h.cfg.JWTSecret, user.ID, user.Role, tenantID, 60*time.Minute)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "token_issue_failed"})
+		}
+		stored := ""
+		if redirectURI != nil {
+			stored = *redirectURI
+		}
+		return redirectWithToken(c, h.cfg, token, user, "sso", email, stored)
+	}
+}
+
+// RequireForAdmins rejects admin sessions (the admin role, or staff holding admin scopes) that did not sign
+// in through the tenant's SSO when the tenant requires it. Other users pass through; the admin routes check
+// roles and scopes themselves.
+func (h *SSOHandler) RequireForAdmins() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Next()
+		}
+		tenantID := requestTenant(c)
+		if sso, _ := c.Locals(auth.LocalSSOTenant).(string); sso == tenantID.String() {
+			return c.Next()
+		}
+		role, _ := c.Locals(auth.LocalRole).(string)
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, _ := uuid.Parse(sub)
+		var required bool
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT s.enabled AND s.require_for_admins
+  AND ($2 = 'admin' OR EXISTS (SELECT 1 FROM users u WHERE u.id = $3 AND cardinality(u.admin_scopes) > 0))
+FROM tenant_sso s
+WHERE s.tenant_id = $1
+`, tenantID, role, userID).Scan(&required)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "sso_lookup_failed"})
+		}
+		if required {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "sso_required", "login_url": "/auth/sso/start"})
+		}
+		return c.Next()
+	}
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/publicstats"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
 )
 
 type LandingStatsHandler struct {
@@ -70,3 +76,108 @@ SELECT
 		return c.Status(fiber.StatusOK).JSON(resp)
 	}
 }
+
+// PublicStatsHandler serves per-project and per-ecosystem stats for README badges and embeddable
+// widgets. The routes are unauthenticated, so results are cached and the routes are rate limited.
+type PublicStatsHandler struct {
+	db    *db.DB
+	cache *publicstats.Cache
+}
+
+func NewPublicStatsHandler(d *db.DB, cache *publicstats.Cache) *PublicStatsHandler {
+	return &PublicStatsHandler{db: d, cache: cache}
+}
+
+// Project serves GET /public/stats/projects/:owner/:repo.
+func (h *PublicStatsHandler) Project() fiber.Handler {
+	return h.serve("project", func(c *fiber.Ctx) string { return c.Params("owner") + "/" + c.Params("repo") }, publicstats.Project)
+}
+
+// Ecosystem serves GET /public/stats/ecosystems/:slug.
+func (h *PublicStatsHandler) Ecosystem() fiber.Handler {
+	return h.serve("ecosystem", func(c *fiber.Ctx) string { return c.Params("slug") }, publicstats.Ecosystem)
+}
+
+// OpenBountiesBadge serves GET /badge/projects/:id/open-bounties.svg (svg) and .json (a shields.io
+// endpoint badge): the project's open, unassigned issues and the points on them.
+func (h *PublicStatsHandler) OpenBountiesBadge(format string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		stats, err := h.lookup(c, "project", c.Params("id"), publicstats.ProjectByID)
+		if errors.Is(err, publicstats.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "stats_fetch_failed"})
+		}
+		b := h.badge(c, stats, "open_bounties")
+		if format == "svg" {
+			c.Set(fiber.HeaderContentType, "image/svg+xml; charset=utf-8")
+			return c.Status(fiber.StatusOK).Send(publicstats.SVG(b))
+		}
+		return c.Status(fiber.StatusOK).JSON(b)
+	}
+}
+
+// serve returns the full stats as JSON (widget data), or with ?format=shields&metric=... a shields.io
+// endpoint badge for one metric.
+func (h *PublicStatsHandler) serve(kind string, key func(*fiber.Ctx) string, load statsLoader) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		format := c.Query("format", "json")
+		metric := c.Query("metric")
+		if format != "json" && format != "shields" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_format"})
+		}
+		if _, ok := publicstats.Metrics[metric]; format == "shields" && !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_metric"})
+		}
+
+		stats, err := h.lookup(c, kind, key(c), load)
+		if errors.Is(err, publicstats.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": kind + "_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "stats_fetch_failed"})
+		}
+		if format == "shields" {
+			return c.Status(fiber.StatusOK).JSON(h.badge(c, stats, metric))
+		}
+		return c.Status(fiber.StatusOK).JSON(stats)
+	}
+}
+
+type statsLoader func(ctx context.Context, pool *pgxpool.Pool, key string) (publicstats.Stats, error)
+
+// lookup loads stats through the cache and sets the caching and CORS headers for a successful response.
+func (h *PublicStatsHandler) lookup(c *fiber.Ctx, kind, key string, load statsLoader) (publicstats.Stats, error) {
+	cacheKey := tenant.IDFromContext(c.Context()) + ":" + kind + ":" + key
+	ctx := c.Context()
+	stats, err := h.cache.Get(cacheKey, func() (publicstats.Stats, error) { return load(ctx, h.db.Pool, key) })
+	if err != nil {
+		if !errors.Is(err, publicstats.ErrNotFound) {
+			slog.Error("failed to compute public stats", "kind", kind, "key", key, "error", err)
+		}
+		return stats, err
+	}
+	c.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cache.TTL().Seconds())))
+	// Widgets are embedded on arbitrary sites; these responses hold nothing private.
+	if len(c.Response().Header.Peek(fiber.HeaderAccessControlAllowOrigin)) == 0 {
+		c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+	}
+	return stats, nil
+}
+
+// badge renders metric for shields.io, honouring ?label=.
+func (h *PublicStatsHandler) badge(c *fiber.Ctx, stats publicstats.Stats, metric string) publicstats.Shields {
+	b, _ := publicstats.Badge(stats, metric)
+	if label := c.Query("label"); label != "" {
+		b.Label = label
+	}
+	b.CacheSeconds = int(h.cache.TTL().Seconds())
+	return b
+}
@@ -0,0 +1,515 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+	"github.com/jagadeesh/grainlify/backend/internal/subprojects"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// Limits on a sub-project's mapping and maintainers.
+const (
+	maxSubProjectPaths       = 50
+	maxSubProjectLabels      = 50
+	maxSubProjectMaintainers = 20
+	// maxSubProjectIssueScan bounds how many of the project's open issues a sub-project listing looks at.
+	maxSubProjectIssueScan = 2000
+)
+
+// maintainsIssue reports whether the user can act on the issue as a maintainer: the project owner, an
+// admin, or a maintainer of a sub-project the issue belongs to.
+func (h *IssueApplicationsHandler) maintainsIssue(ctx context.Context, owner, userID uuid.UUID, role string, projectID uuid.UUID, issueNumber int) bool {
+	if owner == userID || role == "admin" {
+		return true
+	}
+	ok, err := subprojects.Maintains(ctx, h.db.Pool, projectID, issueNumber, userID)
+	if err != nil {
+		slog.Warn("sub-project maintainer check failed", "project_id", projectID, "issue_number", issueNumber, "error", err)
+	}
+	return ok
+}
+
+type subProject struct {
+	ID          uuid.UUID  `json:"id"`
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	EcosystemID *uuid.UUID `json:"ecosystem_id"`
+	subprojects.Mapping
+	Maintainers []string  `json:"maintainers"` // GitHub logins
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const subProjectColumns = `s.id, s.slug, s.name, s.description, s.ecosystem_id, s.paths, s.labels, s.exclude_labels,
+       COALESCE((SELECT array_agg(ga.login ORDER BY LOWER(ga.login))
+                 FROM subproject_maintainers m JOIN github_accounts ga ON ga.user_id = m.user_id
+                 WHERE m.subproject_id = s.id), '{}'),
+       s.created_at, s.updated_at`
+
+func scanSubProject(row pgx.Row) (subProject, error) {
+	var s subProject
+	err := row.Scan(&s.ID, &s.Slug, &s.Name, &s.Description, &s.EcosystemID, &s.Paths, &s.Labels, &s.ExcludeLabels,
+		&s.Maintainers, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+// ListSubProjects serves GET /projects/:id/subprojects: how a monorepo project is split, with each
+// sub-project's mapping and maintainers.
+func (h *ProjectsHandler) ListSubProjects() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+subProjectColumns+`
+FROM project_subprojects s
+JOIN projects p ON p.id = s.project_id AND p.status = 'verified' AND p.deleted_at IS NULL
+WHERE s.project_id = $1
+ORDER BY s.name
+`, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subprojects_list_failed"})
+		}
+		defer rows.Close()
+		out := []subProject{}
+		for rows.Next() {
+			s, err := scanSubProject(rows)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subprojects_list_failed"})
+			}
+			out = append(out, s)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subprojects_list_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"subprojects": out})
+	}
+}
+
+type subProjectRequest struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	EcosystemID string `json:"ecosystem_id"`
+	subprojects.Mapping
+}
+
+// CreateSubProject serves POST /projects/:id/subprojects. The slug defaults to one made from the name;
+// the mapping needs at least one path or label. Project owner or admin.
+func (h *ProjectsHandler) CreateSubProject() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, status, code := h.requireMaintainer(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req subProjectRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Name, req.Description = strings.TrimSpace(req.Name), strings.TrimSpace(req.Description)
+		req.Slug = normalizeSlug(req.Slug)
+		if req.Slug == "" {
+			req.Slug = normalizeSlug(req.Name)
+		}
+
+		var v validate.Validator
+		if v.Required("name", req.Name) {
+			v.MaxLen("name", req.Name, 100)
+			if v.Required("slug", req.Slug) {
+				v.MaxLen("slug", req.Slug, 100)
+			}
+		}
+		v.MaxLen("description", req.Description, maxProjectDescription)
+		validateSubProjectMapping(&v, &req.Mapping)
+		ecosystemID := h.subProjectEcosystem(c.Context(), &v, req.EcosystemID)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		s, err := scanSubProject(h.db.Pool.QueryRow(c.Context(), `
+WITH s AS (
+  INSERT INTO project_subprojects (project_id, slug, name, description, ecosystem_id, paths, labels, exclude_labels)
+  VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+  RETURNING *
+)
+SELECT `+subProjectColumns+` FROM s
+`, projectID, req.Slug, req.Name, req.Description, ecosystemID, req.Paths, req.Labels, req.ExcludeLabels))
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "subproject_slug_taken"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(s)
+	}
+}
+
+// subProjectPatchRequest is the body of UpdateSubProject, a JSON Merge Patch. Lists replace the current
+// ones; null empties them.
+type subProjectPatchRequest struct {
+	Slug          patchField[string]   `json:"slug"`
+	Name          patchField[string]   `json:"name"`
+	Description   patchField[string]   `json:"description"`
+	EcosystemID   patchField[string]   `json:"ecosystem_id"`
+	Paths         patchField[[]string] `json:"paths"`
+	Labels        patchField[[]string] `json:"labels"`
+	ExcludeLabels patchField[[]string] `json:"exclude_labels"`
+}
+
+// UpdateSubProject serves PATCH /projects/:id/subprojects/:subId. Project owner or admin.
+func (h *ProjectsHandler) UpdateSubProject() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, s, status, code := h.subProjectTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req subProjectPatchRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+
+		var v validate.Validator
+		if name := patchText(req.Name); name != nil && v.Required("name", *name) && v.MaxLen("name", *name, 100) {
+			s.Name = *name
+		}
+		if slug := patchText(req.Slug); slug != nil {
+			*slug = normalizeSlug(*slug)
+			if v.Required("slug", *slug) && v.MaxLen("slug", *slug, 100) {
+				s.Slug = *slug
+			}
+		}
+		if desc := patchText(req.Description); desc != nil && v.MaxLen("description", *desc, maxProjectDescription) {
+			s.Description = nil
+			if *desc != "" {
+				s.Description = desc
+			}
+		}
+		if eco := patchText(req.EcosystemID); eco != nil {
+			s.EcosystemID = h.subProjectEcosystem(c.Context(), &v, *eco)
+		}
+		for _, f := range []struct {
+			field patchField[[]string]
+			dst   *[]string
+		}{{req.Paths, &s.Paths}, {req.Labels, &s.Labels}, {req.ExcludeLabels, &s.ExcludeLabels}} {
+			if f.field.Set {
+				*f.dst = f.field.Value
+			}
+		}
+		validateSubProjectMapping(&v, &s.Mapping)
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		out, err := scanSubProject(h.db.Pool.QueryRow(c.Context(), `
+WITH s AS (
+  UPDATE project_subprojects
+  SET slug = $3, name = $4, description = $5, ecosystem_id = $6, paths = $7, labels = $8, exclude_labels = $9,
+      updated_at = now()
+  WHERE project_id = $1 AND id = $2
+  RETURNING *
+)
+SELECT `+subProjectColumns+` FROM s
+`, projectID, s.ID, s.Slug, s.Name, s.Description, s.EcosystemID, s.Paths, s.Labels, s.ExcludeLabels))
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "subproject_slug_taken"})
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "subproject_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+// DeleteSubProject serves DELETE /projects/:id/subprojects/:subId. Its issues go back to the project's
+// maintainers only. Project owner or admin.
+func (h *ProjectsHandler) DeleteSubProject() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, s, status, code := h.subProjectTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `
+DELETE FROM project_subprojects WHERE project_id = $1 AND id = $2
+`, projectID, s.ID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_delete_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+type subProjectMaintainersRequest struct {
+	Logins []string `json:"logins"`
+}
+
+// SetSubProjectMaintainers serves PUT /projects/:id/subprojects/:subId/maintainers: replaces the
+// sub-project's maintainers, given as GitHub logins of Grainlify users. They can then review
+// applications, assign, label and close the sub-project's issues. New maintainers are notified. Project
+// owner or admin.
+func (h *ProjectsHandler) SetSubProjectMaintainers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, s, status, code := h.subProjectTarget(c)
+		if status != 0 {
+			return c.Status(status).JSON(fiber.Map{"error": code})
+		}
+		var req subProjectMaintainersRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+		}
+
+		var v validate.Validator
+		userIDs := map[uuid.UUID]bool{}
+		if v.MaxItems("logins", len(req.Logins), maxSubProjectMaintainers) {
+			for i, login := range req.Logins {
+				login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+				field := validate.Index("logins", i, "")
+				if !v.Required(field, login) {
+					continue
+				}
+				var userID uuid.UUID
+				err := h.db.Pool.QueryRow(c.Context(), `
+SELECT user_id FROM github_accounts WHERE LOWER(login) = LOWER($1)
+`, login).Scan(&userID)
+				if errors.Is(err, pgx.ErrNoRows) {
+					v.Fail(field, validate.Invalid, "not a Grainlify user")
+					continue
+				}
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "user_lookup_failed"})
+				}
+				userIDs[userID] = true
+			}
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		actor := contentActor(c)
+		var added []uuid.UUID
+		err := pgx.BeginFunc(c.Context(), h.db.Pool, func(tx pgx.Tx) error {
+			added = nil
+			ids := make([]uuid.UUID, 0, len(userIDs))
+			for id := range userIDs {
+				ids = append(ids, id)
+			}
+			if _, err := tx.Exec(c.Context(), `
+DELETE FROM subproject_maintainers WHERE subproject_id = $1 AND NOT (user_id = ANY($2))
+`, s.ID, ids); err != nil {
+				return err
+			}
+			for _, id := range ids {
+				tag, err := tx.Exec(c.Context(), `
+INSERT INTO subproject_maintainers (subproject_id, user_id, added_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`, s.ID, id, actor)
+				if err != nil {
+					return err
+				}
+				if tag.RowsAffected() > 0 {
+					added = append(added, id)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_update_failed"})
+		}
+		for _, id := range added {
+			notifyUser(c.Context(), h.db.Pool, id, "subproject_maintainer_added", fiber.Map{
+				"project_id": projectID.String(), "subproject_id": s.ID.String(), "subproject": s.Name,
+			})
+		}
+
+		out, err := scanSubProject(h.db.Pool.QueryRow(c.Context(), `
+SELECT `+subProjectColumns+` FROM project_subprojects s WHERE s.id = $1
+`, s.ID))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+// SubProjectIssues serves GET /projects/:id/subprojects/:slug/issues: the project's open issues that
+// belong to the sub-project, most recently updated first.
+func (h *ProjectsHandler) SubProjectIssues() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		s, err := scanSubProject(h.db.Pool.QueryRow(c.Context(), `
+SELECT `+subProjectColumns+`
+FROM project_subprojects s
+JOIN projects p ON p.id = s.project_id AND p.status = 'verified' AND p.deleted_at IS NULL
+WHERE s.project_id = $1 AND s.slug = $2
+`, projectID, strings.ToLower(c.Params("slug"))))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "subproject_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "subproject_lookup_failed"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT number, COALESCE(title, ''), COALESCE(body, ''), COALESCE(url, ''), COALESCE(labels, '[]'::jsonb),
+       points, updated_at_github
+FROM github_issues
+WHERE project_id = $1 AND state = 'open'
+ORDER BY updated_at_github DESC NULLS LAST
+LIMIT $2
+`, projectID, maxSubProjectIssueScan)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var number int
+			var title, body, url string
+			var labelsJSON []byte
+			var points *int
+			var updatedAt *time.Time
+			if err := rows.Scan(&number, &title, &body, &url, &labelsJSON, &points, &updatedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
+			}
+			labels := recommend.LabelNames(labelsJSON)
+			if !s.Matches(subprojects.Issue{Title: title, Body: body, Labels: labels}) {
+				continue
+			}
+			if labels == nil {
+				labels = []string{}
+			}
+			out = append(out, fiber.Map{
+				"number":     number,
+				"title":      title,
+				"url":        url,
+				"labels":     labels,
+				"points":     points,
+				"updated_at": updatedAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "issues_list_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"subproject": s, "issues": out})
+	}
+}
+
+// subProjectTarget checks the caller maintains the project and loads the sub-project in the route.
+func (h *ProjectsHandler) subProjectTarget(c *fiber.Ctx) (uuid.UUID, subProject, int, string) {
+	projectID, status, code := h.requireMaintainer(c)
+	if status != 0 {
+		return uuid.Nil, subProject{}, status, code
+	}
+	subID, err := uuid.Parse(c.Params("subId"))
+	if err != nil {
+		return uuid.Nil, subProject{}, fiber.StatusBadRequest, "invalid_subproject_id"
+	}
+	s, err := scanSubProject(h.db.Pool.QueryRow(c.Context(), `
+SELECT `+subProjectColumns+` FROM project_subprojects s WHERE s.project_id = $1 AND s.id = $2
+`, projectID, subID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, subProject{}, fiber.StatusNotFound, "subproject_not_found"
+	}
+	if err != nil {
+		return uuid.Nil, subProject{}, fiber.StatusInternalServerError, "subproject_lookup_failed"
+	}
+	return projectID, s, 0, ""
+}
+
+// subProjectEcosystem resolves an ecosystem id ("" for none) to an active ecosystem.
+func (h *ProjectsHandler) subProjectEcosystem(ctx context.Context, v *validate.Validator, id string) *uuid.UUID {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil
+	}
+	var ecoID uuid.UUID
+	err := h.db.Pool.QueryRow(ctx, `
+SELECT id FROM ecosystems WHERE id::text = $1 AND status = 'active'
+`, id).Scan(&ecoID)
+	if err != nil {
+		v.Fail("ecosystem_id", validate.Invalid, "not an active ecosystem")
+		return nil
+	}
+	return &ecoID
+}
+
+// validateSubProjectMapping normalizes the mapping in place (clean paths, trimmed and de-duplicated
+// labels) and checks it can match something.
+func validateSubProjectMapping(v *validate.Validator, m *subprojects.Mapping) {
+	paths := []string{}
+	if v.MaxItems("paths", len(m.Paths), maxSubProjectPaths) {
+		seen := map[string]bool{}
+		for i, p := range m.Paths {
+			clean, ok := subprojects.NormalizePath(p)
+			if !ok {
+				v.Fail(validate.Index("paths", i, ""), validate.Invalid, "must be a directory in the repository")
+				continue
+			}
+			if !seen[clean] {
+				seen[clean] = true
+				paths = append(paths, clean)
+			}
+		}
+	}
+	m.Paths = paths
+	labels := func(field string, in []string) []string {
+		out := []string{}
+		if !v.MaxItems(field, len(in), maxSubProjectLabels) {
+			return out
+		}
+		seen := map[string]bool{}
+		for i, l := range in {
+			l = strings.TrimSpace(l)
+			f := validate.Index(field, i, "")
+			if !v.Required(f, l) || !v.MaxLen(f, l, 50) || seen[strings.ToLower(l)] {
+				continue
+			}
+			seen[strings.ToLower(l)] = true
+			out = append(out, l)
+		}
+		return out
+	}
+	m.Labels = labels("labels", m.Labels)
+	m.ExcludeLabels = labels("exclude_labels", m.ExcludeLabels)
+	if m.Empty() {
+		v.Fail("paths", validate.Invalid, "map at least one path or label")
+	}
+}
@@ -53,7 +53,12 @@ func (h *SyncHandler) EnqueueFullSync() fiber.Handler {
 		_, _ = h.db.Pool.Exec(c.Context(), `
 INSERT INTO sync_jobs (project_id, job_type, status, run_at)
 VALUES ($1, 'sync_issues', 'pending', now()),
-       ($1, 'sync_prs', 'pending', now())
+       ($1, 'sync_prs', 'pending', now()),
+       ($1, 'sync_repo', 'pending', now())
+`, projectID)
+		_, _ = h.db.Pool.Exec(c.Context(), `
+INSERT INTO sync_jobs (project_id, job_type, status, run_at)
+SELECT id, 'sync_discussions', 'pending', now() FROM projects WHERE id = $1 AND track_discussions
 `, projectID)
 
 		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"queued": true})
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+	"github.com/jagadeesh/grainlify/backend/internal/waitlist"
+)
+
+type taskRequest struct {
+	Type   *string `json:"type"`
+	Title  *string `json:"title"`
+	Body   *string `json:"body"`
+	Points *int    `json:"points"`
+	// ClearPoints unsets points on update.
+	ClearPoints bool `json:"clear_points"`
+}
+
+// validate trims the request in place and checks it; creating requires a type and a title.
+func (r *taskRequest) validate(creating bool) error {
+	var v validate.Validator
+	if r.Type != nil {
+		*r.Type = strings.ToLower(strings.TrimSpace(*r.Type))
+		v.OneOf("type", *r.Type, tasks.NativeTypes...)
+	} else if creating {
+		v.Required("type", "")
+	}
+	if r.Title != nil {
+		*r.Title = strings.TrimSpace(*r.Title)
+		if v.Required("title", *r.Title) {
+			v.MaxLen("title", *r.Title, maxDraftTitleLength)
+		}
+	} else if creating {
+		v.Required("title", "")
+	}
+	if r.Body != nil {
+		*r.Body = strings.TrimSpace(*r.Body)
+		v.MaxLen("body", *r.Body, maxDraftBodyLength)
+	}
+	if r.Points != nil {
+		v.Range("points", *r.Points, 0, 1_000_000)
+	}
+	return v.Err()
+}
+
+type nativeTask struct {
+	Number     int        `json:"number"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	State      string     `json:"state"`
+	Reason     *string    `json:"state_reason"`
+	URL        string     `json:"url"`
+	Points     *int       `json:"points"`
+	DeadlineAt *time.Time `json:"deadline_at"`
+	Assignees  []string   `json:"assignees"`
+	Applicants int        `json:"pending_applications"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+}
+
+const nativeTaskColumns = `gi.number, gi.task_type, COALESCE(gi.title, ''), COALESCE(gi.body, ''), COALESCE(gi.state, ''), gi.state_reason,
+       COALESCE(gi.url, ''), gi.points, gi.deadline_at, COALESCE(gi.assignees, '[]'::jsonb),
+       (SELECT COUNT(*) FROM issue_applications a WHERE a.project_id = gi.project_id AND a.issue_number = gi.number AND a.status = 'pending'),
+       gi.created_at_github, gi.updated_at_github, gi.closed_at_github`
+
+func scanNativeTask(row pgx.Row) (nativeTask, error) {
+	var t nativeTask
+	var assigneesJSON []byte
+	err := row.Scan(&t.Number, &t.Type, &t.Title, &t.Body, &t.State, &t.Reason, &t.URL, &t.Points, &t.DeadlineAt,
+		&assigneesJSON, &t.Applicants, &t.CreatedAt, &t.UpdatedAt, &t.ClosedAt)
+	if err != nil {
+		return t, err
+	}
+	var assignees []struct {
+		Login string `json:"login"`
+	}
+	_ = json.Unmarshal(assigneesJSON, &assignees)
+	t.Assignees = []string{}
+	for _, a := range assignees {
+		t.Assignees = append(t.Assignees, a.Login)
+	}
+	return t, nil
+}
+
+// ListTasks serves GET /projects/:id/tasks: the project's native tasks, open ones first, newest first.
+// ?type= and ?state=open|closed filter them. Apply, assign, close and set points through the issue
+// endpoints with the task's number.
+func (h *IssueApplicationsHandler) ListTasks() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		projectID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_project_id"})
+		}
+		taskType := strings.ToLower(strings.TrimSpace(c.Query("type")))
+		if taskType != "" && !tasks.ValidNativeType(taskType) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_task_type", "valid_types": tasks.NativeTypes})
+		}
+		state := strings.ToLower(strings.TrimSpace(c.Query("state")))
+		if state != "" && state != "open" && state != "closed" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_state"})
+		}
+
+		var exists bool
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND status = 'verified' AND deleted_at IS NULL)
+`, projectID).Scan(&exists)
+		if !exists {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project_not_found"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT `+nativeTaskColumns+`
+FROM github_issues gi
+WHERE gi.project_id = $1 AND gi.task_type <> 'code'
+  AND (NULLIF($2, '') IS NULL OR gi.task_type = $2)
+  AND (NULLIF($3, '') IS NULL OR gi.state = $3)
+ORDER BY (gi.state = 'open') DESC, gi.number DESC
+LIMIT 200
+`, projectID, taskType, state)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tasks_fetch_failed"})
+		}
+		defer rows.Close()
+		out := []nativeTask{}
+		for rows.Next() {
+			t, err := scanNativeTask(rows)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tasks_fetch_failed"})
+			}
+			out = append(out, t)
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tasks_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"tasks": out})
+	}
+}
+
+// CreateTask serves POST /projects/:id/tasks: opens a native task for documentation, translation, design
+// or other non-code work. Maintainer only.
+func (h *IssueApplicationsHandler) CreateTask() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, userID, status, errBody := h.draftProject(c)
+		if errBody != nil {
+			return c.Status(status).JSON(errBody)
+		}
+		var req taskRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if err := req.validate(true); err != nil {
+			return validationFailed(c, err)
+		}
+		body := ""
+		if req.Body != nil {
+			body = *req.Body
+		}
+
+		// The creator is the task's author, so they can't apply to their own task.
+		var authorLogin string
+		_ = h.db.Pool.QueryRow(c.Context(), `SELECT login FROM github_accounts WHERE user_id = $1`, userID).Scan(&authorLogin)
+
+		// Two tasks created at once can pick the same number; the loser retries with the next one.
+		var t nativeTask
+		var err error
+		for attempt := 0; attempt < 3; attempt++ {
+			t, err = scanNativeTask(h.db.Pool.QueryRow(c.Context(), `
+WITH next AS (
+  SELECT GREATEST(COALESCE(MAX(number) + 1, $2), $2) AS number
+  FROM github_issues WHERE project_id = $1 AND number >= $2
+), inserted AS (
+  INSERT INTO github_issues (project_id, github_issue_id, number, state, title, body, author_login, url,
+                             assignees, labels, comments, comments_count, points, task_type, created_by_user_id,
+                             created_at_github, updated_at_github, last_seen_at)
+  SELECT $1, -next.number, next.number, 'open', $3, $4, NULLIF($5, ''), $6 || (-next.number)::text,
+         '[]'::jsonb, '[]'::jsonb, '[]'::jsonb, 0, $7, $8, $9, now(), now(), now()
+  FROM next
+  RETURNING *
+)
+SELECT `+nativeTaskColumns+` FROM inserted gi
+`, projectID, tasks.FirstNumber, *req.Title, body, authorLogin, h.taskURLPrefix(projectID), req.Points, *req.Type, userID))
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+				break
+			}
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "task_create_failed"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(t)
+	}
+}
+
+// UpdateTask serves PATCH /projects/:id/tasks/:number: edits a native task's type, title, body or points.
+// Maintainer only.
+func (h *IssueApplicationsHandler) UpdateTask() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		projectID, _, status, errBody := h.draftProject(c)
+		if errBody != nil {
+			return c.Status(status).JSON(errBody)
+		}
+		number, err := c.ParamsInt("number")
+		if err != nil || !tasks.IsNative(number) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_task_number"})
+		}
+		var req taskRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if err := req.validate(false); err != nil {
+			return validationFailed(c, err)
+		}
+
+		t, err := scanNativeTask(h.db.Pool.QueryRow(c.Context(), `
+WITH updated AS (
+  UPDATE github_issues
+  SET task_type = COALESCE($3, task_type),
+      title = COALESCE($4, title),
+      body = COALESCE($5, body),
+      points = CASE WHEN $7 THEN NULL ELSE COALESCE($6, points) END,
+      updated_at_github = now(), last_seen_at = now()
+  WHERE project_id = $1 AND number = $2 AND task_type <> 'code'
+  RETURNING *
+)
+SELECT `+nativeTaskColumns+` FROM updated gi
+`, projectID, number, req.Type, req.Title, req.Body, req.Points, req.ClearPoints))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "task_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "task_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(t)
+	}
+}
+
+// taskURLPrefix is a native task's URL without its github_issue_id: the dashboard page GitHub issues link to.
+func (h *IssueApplicationsHandler) taskURLPrefix(projectID uuid.UUID) string {
+	base := strings.TrimSpace(strings.TrimRight(h.cfg.FrontendBaseURL, "/"))
+	if !strings.HasPrefix(base, "http") {
+		base = ""
+	}
+	return fmt.Sprintf("%s/dashboard?tab=browse&project=%s&issue=", base, projectID)
+}
+
+// assignTask finishes assign for a native task: there is no GitHub issue, so the assignment is recorded here
+// and the assignee is told in-app instead of by a bot comment.
+func (h *IssueApplicationsHandler) assignTask(ctx context.Context, projectID uuid.UUID, number int, assignee string, assigneeUserID uuid.UUID, deadline *time.Time) (int, fiber.Map) {
+	assigneesJSON, _ := json.Marshal([]map[string]string{{"login": assignee}})
+	var title string
+	err := h.db.Pool.QueryRow(ctx, `
+UPDATE github_issues
+SET assignees = $3::jsonb, deadline_at = COALESCE($4, deadline_at), updated_at_github = now(), last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+RETURNING COALESCE(title, ''), deadline_at
+`, projectID, number, assigneesJSON, deadline).Scan(&title, &deadline)
+	if err != nil {
+		return fiber.StatusInternalServerError, fiber.Map{"error": "task_assign_failed"}
+	}
+
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'assigned', decided_at = now(), updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status = 'pending'
+`, projectID, number, assignee)
+	waitlist.Promote(ctx, h.db.Pool, projectID, number, assignee)
+	if assigneeUserID != uuid.Nil {
+		notifyUser(ctx, h.db.Pool, assigneeUserID, "task_assigned", fiber.Map{
+			"project_id":  projectID.String(),
+			"task_number": number,
+			"task_title":  title,
+			"deadline_at": deadline,
+		})
+	}
+	checkruns.MarkIssueChanged(ctx, h.db.Pool, projectID, number)
+
+	return fiber.StatusOK, fiber.Map{"ok": true, "deadline_at": deadline}
+}
+
+// unassignTask is Unassign for a native task.
+func (h *IssueApplicationsHandler) unassignTask(ctx context.Context, projectID uuid.UUID, number int, logins []string) {
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE github_issues SET assignees = '[]'::jsonb, deadline_at = NULL, updated_at_github = now(), last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, number)
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE assignment_extension_requests SET status = 'denied', decided_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'pending'
+`, projectID, number)
+	_, _ = h.db.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'unassigned', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'assigned' AND LOWER(github_login) = ANY($3)
+`, projectID, number, lowerAll(logins))
+	if err := rewards.Return(ctx, h.db.Pool, projectID, number, "unassigned"); err != nil {
+		slog.Error("failed to return task reward", "project_id", projectID.String(), "task_number", number, "error", err)
+	}
+	if _, err := waitlist.NotifyNext(ctx, h.db.Pool, projectID, number); err != nil {
+		slog.Warn("unassign: failed to notify waitlist", "project_id", projectID.String(), "task_number", number, "error", err)
+	}
+	checkruns.MarkIssueChanged(ctx, h.db.Pool, projectID, number)
+}
+
+// withdrawTaskApplication is Withdraw for a native task: it withdraws the caller's pending application.
+func (h *IssueApplicationsHandler) withdrawTaskApplication(c *fiber.Ctx, projectID uuid.UUID, number int, userID uuid.UUID) error {
+	ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE issue_applications SET status = 'withdrawn', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'pending'
+  AND (user_id = $3 OR LOWER(github_login) IN (SELECT LOWER(login) FROM github_accounts WHERE user_id = $3))
+`, projectID, number, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "application_withdraw_failed"})
+	}
+	if ct.RowsAffected() == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "application_not_found"})
+	}
+	return c.Status(fiber.StatusOK).JSON(withDryRun(c, fiber.Map{"ok": true}))
+}
+
+// notifyTaskCompleted tells a native task's assignees it was accepted as completed, with the points earned.
+func (h *IssueApplicationsHandler) notifyTaskCompleted(ctx context.Context, projectID uuid.UUID, number int) {
+	rows, err := h.db.Pool.Query(ctx, `
+SELECT ga.user_id, COALESCE(gi.title, ''), gi.points
+FROM github_issues gi
+CROSS JOIN LATERAL jsonb_array_elements(COALESCE(gi.assignees, '[]'::jsonb)) x
+JOIN github_accounts ga ON LOWER(ga.login) = LOWER(x->>'login')
+WHERE gi.project_id = $1 AND gi.number = $2
+`, projectID, number)
+	if err != nil {
+		slog.Warn("failed to load task assignees", "project_id", projectID.String(), "task_number", number, "error", err)
+		return
+	}
+	type recipient struct {
+		userID uuid.UUID
+		title  string
+		points *int
+	}
+	var list []recipient
+	for rows.Next() {
+		var r recipient
+		if err := rows.Scan(&r.userID, &r.title, &r.points); err == nil {
+			list = append(list, r)
+		}
+	}
+	rows.Close()
+	for _, r := range list {
+		notifyUser(ctx, h.db.Pool, r.userID, "task_completed", fiber.Map{
+			"project_id":  projectID.String(),
+			"task_number": number,
+			"task_title":  r.title,
+			"points":      r.points,
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/payouts"
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// TaxInfoHandler collects contributors' tax information for ecosystems paying fiat. Identifying fields are
+// encrypted with TAX_ENC_KEY_B64 one by one; admins review submissions by status and last four digits,
+// and only holders of the tax:read scope can export the decrypted fields.
+type TaxInfoHandler struct {
+	cfg config.Config
+	db  *db.DB
+}
+
+func NewTaxInfoHandler(cfg config.Config, d *db.DB) *TaxInfoHandler {
+	return &TaxInfoHandler{cfg: cfg, db: d}
+}
+
+// Tax statuses. A payout's tax status is one of these, or not_required / missing.
+const (
+	taxSubmitted   = "submitted"
+	taxVerified    = "verified"
+	taxRejected    = "rejected"
+	taxNotRequired = "not_required"
+	taxMissing     = "missing"
+)
+
+var countryCodeRe = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type taxInfoRequest struct {
+	FormType  string `json:"form_type"` // w9|w8ben|w8bene|other
+	Country   string `json:"country"`   // ISO 3166-1 alpha-2
+	LegalName string `json:"legal_name"`
+	TaxID     string `json:"tax_id"`
+	Address   string `json:"address"`
+	// Signature is the name typed to certify the form; Certify must be true.
+	Signature string `json:"signature"`
+	Certify   bool   `json:"certify"`
+}
+
+func (r *taxInfoRequest) validate() error {
+	r.FormType = strings.ToLower(strings.TrimSpace(r.FormType))
+	r.Country = strings.ToUpper(strings.TrimSpace(r.Country))
+	r.LegalName, r.Address, r.Signature = strings.TrimSpace(r.LegalName), strings.TrimSpace(r.Address), strings.TrimSpace(r.Signature)
+	r.TaxID = strings.Join(strings.Fields(r.TaxID), "")
+
+	var v validate.Validator
+	v.OneOf("form_type", r.FormType, "w9", "w8ben", "w8bene", "other")
+	if !countryCodeRe.MatchString(r.Country) {
+		v.Fail("country", validate.Invalid, "must be an ISO 3166-1 alpha-2 code")
+	}
+	if v.Required("legal_name", r.LegalName) {
+		v.MaxLen("legal_name", r.LegalName, 200)
+	}
+	if v.Required("tax_id", r.TaxID) {
+		v.MaxLen("tax_id", r.TaxID, 64)
+	}
+	if v.Required("address", r.Address) {
+		v.MaxLen("address", r.Address, 1000)
+	}
+	if v.Required("signature", r.Signature) {
+		v.MaxLen("signature", r.Signature, 200)
+	}
+	if !r.Certify {
+		v.Fail("certify", validate.Invalid, "must be true to certify the information is correct")
+	}
+	return v.Err()
+}
+
+// key returns the tax encryption key, or false after answering 503 when it isn't configured.
+func (h *TaxInfoHandler) key(c *fiber.Ctx) ([]byte, bool) {
+	key, err := cryptox.KeyFromB64(h.cfg.TaxEncKey())
+	if err != nil {
+		_ = c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "tax_info_not_configured"})
+		return nil, false
+	}
+	return key, true
+}
+
+func taxUser(c *fiber.Ctx) (uuid.UUID, bool) {
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	id, err := uuid.Parse(sub)
+	return id, err == nil
+}
+
+// Mine serves GET /me/tax-info: the caller's tax information, with the tax ID masked, and whether any of
+// their payouts is waiting on it.
+func (h *TaxInfoHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := taxUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var waiting int
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT COUNT(*) FROM payouts p
+WHERE p.recipient_user_id = $1 AND p.status = 'pending' AND (`+payouts.TaxStatus+`) <> ALL($2)
+`, userID, []string{taxNotRequired, taxVerified}).Scan(&waiting)
+
+		var formType, country, last4, status string
+		var legalNameEnc, addressEnc []byte
+		var note *string
+		var certifiedAt, submittedAt time.Time
+		var reviewedAt *time.Time
+		err := h.db.Pool.QueryRow(c.Context(), `
+SELECT form_type, country, tax_id_last4, legal_name_enc, address_enc, status, review_note, certified_at, submitted_at, reviewed_at
+FROM tax_profiles WHERE user_id = $1
+`, userID).Scan(&formType, &country, &last4, &legalNameEnc, &addressEnc, &status, &note, &certifiedAt, &submittedAt, &reviewedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": taxMissing, "payouts_waiting": waiting})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_fetch_failed"})
+		}
+		key, ok := h.key(c)
+		if !ok {
+			return nil
+		}
+		legalName, err1 := cryptox.DecryptAESGCM(key, legalNameEnc)
+		address, err2 := cryptox.DecryptAESGCM(key, addressEnc)
+		if err1 != nil || err2 != nil {
+			slog.Error("failed to decrypt tax info", "user_id", userID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_decrypt_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":          status,
+			"form_type":       formType,
+			"country":         country,
+			"legal_name":      string(legalName),
+			"address":         string(address),
+			"tax_id_last4":    last4,
+			"review_note":     note,
+			"certified_at":    certifiedAt,
+			"submitted_at":    submittedAt,
+			"reviewed_at":     reviewedAt,
+			"payouts_waiting": waiting,
+		})
+	}
+}
+
+// Submit serves PUT /me/tax-info: stores the caller's tax information, replacing any earlier submission,
+// for review.
+func (h *TaxInfoHandler) Submit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := taxUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var req taxInfoRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if err := req.validate(); err != nil {
+			return validationFailed(c, err)
+		}
+		key, ok := h.key(c)
+		if !ok {
+			return nil
+		}
+		enc := make([][]byte, 4)
+		for i, field := range []string{req.LegalName, req.TaxID, req.Address, req.Signature} {
+			b, err := cryptox.EncryptAESGCM(key, []byte(field))
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_encrypt_failed"})
+			}
+			enc[i] = b
+		}
+		last4 := req.TaxID
+		if len(last4) > 4 {
+			last4 = last4[len(last4)-4:]
+		}
+
+		_, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO tax_profiles (user_id, form_type, country, legal_name_enc, tax_id_enc, tax_id_last4, address_enc, signature_enc, certified_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+ON CONFLICT (user_id) DO UPDATE SET
+  form_type = EXCLUDED.form_type,
+  country = EXCLUDED.country,
+  legal_name_enc = EXCLUDED.legal_name_enc,
+  tax_id_enc = EXCLUDED.tax_id_enc,
+  tax_id_last4 = EXCLUDED.tax_id_last4,
+  address_enc = EXCLUDED.address_enc,
+  signature_enc = EXCLUDED.signature_enc,
+  certified_at = EXCLUDED.certified_at,
+  status = 'submitted', review_note = NULL, reviewed_by = NULL, reviewed_at = NULL,
+  submitted_at = now(), updated_at = now()
+`, userID, req.FormType, req.Country, enc[0], enc[1], last4, enc[2], enc[3])
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_save_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": taxSubmitted, "tax_id_last4": last4})
+	}
+}
+
+// Delete serves DELETE /me/tax-info: withdraws the caller's tax information. Payouts that need it can't
+// be approved until it is submitted and verified again.
+func (h *TaxInfoHandler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := taxUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		if _, err := h.db.Pool.Exec(c.Context(), `DELETE FROM tax_profiles WHERE user_id = $1`, userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_delete_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// List serves GET /admin/tax-info: submissions for review, oldest first, without the encrypted fields.
+// ?status= filters.
+func (h *TaxInfoHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT t.user_id, (SELECT login FROM github_accounts WHERE user_id = t.user_id), t.form_type, t.country,
+       t.tax_id_last4, t.status, t.review_note, t.submitted_at, t.reviewed_by, t.reviewed_at
+FROM tax_profiles t
+WHERE (NULLIF($1, '') IS NULL OR t.status = $1)
+ORDER BY t.submitted_at
+LIMIT 500
+`, strings.TrimSpace(c.Query("status")))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_fetch_failed"})
+		}
+		defer rows.Close()
+		out := []fiber.Map{}
+		for rows.Next() {
+			var userID uuid.UUID
+			var login, note *string
+			var formType, country, last4, status string
+			var submittedAt time.Time
+			var reviewedBy *uuid.UUID
+			var reviewedAt *time.Time
+			if err := rows.Scan(&userID, &login, &formType, &country, &last4, &status, &note, &submittedAt, &reviewedBy, &reviewedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_fetch_failed"})
+			}
+			out = append(out, fiber.Map{
+				"user_id":      userID,
+				"login":        login,
+				"form_type":    formType,
+				"country":      country,
+				"tax_id_last4": last4,
+				"status":       status,
+				"review_note":  note,
+				"submitted_at": submittedAt,
+				"reviewed_by":  reviewedBy,
+				"reviewed_at":  reviewedAt,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_fetch_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"tax_profiles": out})
+	}
+}
+
+type taxReviewRequest struct {
+	Status string `json:"status"` // verified|rejected
+	Note   string `json:"note"`
+}
+
+// Review serves POST /admin/tax-info/:user_id/review: verifies or rejects a submission. The contributor
+// is notified; a rejection's note tells them what to fix.
+func (h *TaxInfoHandler) Review() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, err := uuid.Parse(c.Params("user_id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_user_id"})
+		}
+		var req taxReviewRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Status, req.Note = strings.TrimSpace(req.Status), strings.TrimSpace(req.Note)
+		var v validate.Validator
+		v.OneOf("status", req.Status, taxVerified, taxRejected)
+		v.MaxLen("note", req.Note, 1000)
+		if req.Status == taxRejected {
+			v.Required("note", req.Note)
+		}
+		if err := v.Err(); err != nil {
+			return validationFailed(c, err)
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE tax_profiles
+SET status = $2, review_note = NULLIF($3, ''), reviewed_by = $4, reviewed_at = now(), updated_at = now()
+WHERE user_id = $1
+`, userID, req.Status, req.Note, contentActor(c))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_review_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "tax_info_not_found"})
+		}
+		notifyUser(c.Context(), h.db.Pool, userID, "tax_info_reviewed", fiber.Map{"status": req.Status, "note": req.Note})
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": req.Status})
+	}
+}
+
+// Export serves GET /admin/tax-info/export: the decrypted tax information as CSV, for filing. Requires
+// the tax:read scope; ?status= filters (default verified). Every export is logged with who made it.
+func (h *TaxInfoHandler) Export() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		key, ok := h.key(c)
+		if !ok {
+			return nil
+		}
+		status := strings.TrimSpace(c.Query("status", taxVerified))
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT t.user_id, COALESCE((SELECT login FROM github_accounts WHERE user_id = t.user_id), ''), t.form_type, t.country,
+       t.legal_name_enc, t.tax_id_enc, t.address_enc, t.signature_enc, t.certified_at, t.status, t.reviewed_at
+FROM tax_profiles t
+WHERE t.status = $1
+ORDER BY t.submitted_at
+`, status)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_export_failed"})
+		}
+		defer rows.Close()
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"user_id", "github_login", "form_type", "country", "legal_name", "tax_id", "address",
+			"signature", "certified_at", "status", "reviewed_at"})
+		n := 0
+		for rows.Next() {
+			var userID uuid.UUID
+			var login, formType, country, st string
+			var enc [4][]byte
+			var certifiedAt time.Time
+			var reviewedAt *time.Time
+			if err := rows.Scan(&userID, &login, &formType, &country, &enc[0], &enc[1], &enc[2], &enc[3], &certifiedAt, &st, &reviewedAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_export_failed"})
+			}
+			var plain [4]string
+			for i, b := range enc {
+				p, err := cryptox.DecryptAESGCM(key, b)
+				if err != nil {
+					slog.Error("failed to decrypt tax info", "user_id", userID)
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_decrypt_failed"})
+				}
+				plain[i] = string(p)
+			}
+			reviewed := ""
+			if reviewedAt != nil {
+				reviewed = reviewedAt.UTC().Format(time.RFC3339)
+			}
+			_ = w.Write([]string{userID.String(), login, formType, country, plain[0], plain[1], plain[2], plain[3],
+				certifiedAt.UTC().Format(time.RFC3339), st, reviewed})
+			n++
+		}
+		if err := rows.Err(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tax_info_export_failed"})
+		}
+		w.Flush()
+
+		actor, _ := c.Locals(auth.LocalUserID).(string)
+		slog.Info("tax info exported", "actor_user_id", actor, "status", status, "rows", n)
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="grainlify-tax-info-`+time.Now().UTC().Format("20060102")+`.csv"`)
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+}
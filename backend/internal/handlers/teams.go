@@ -0,0 +1,723 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+)
+
+type TeamsHandler struct {
+	db *db.DB
+}
+
+func NewTeamsHandler(d *db.DB) *TeamsHandler {
+	return &TeamsHandler{db: d}
+}
+
+type createTeamRequest struct {
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	Description *string `json:"description"`
+	IsOpen      bool    `json:"is_open"`
+}
+
+// Create creates a team owned by the current user.
+func (h *TeamsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		var req createTeamRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_required"})
+		}
+		if len(req.Name) > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_too_long"})
+		}
+		slug := normalizeSlug(req.Slug)
+		if slug == "" {
+			slug = normalizeSlug(req.Name)
+		}
+		if slug == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_slug"})
+		}
+
+		tx, err := h.db.Pool.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_create_failed"})
+		}
+		defer func() { _ = tx.Rollback(c.Context()) }()
+
+		var teamID uuid.UUID
+		err = tx.QueryRow(c.Context(), `
+INSERT INTO teams (slug, name, description, owner_user_id, is_open)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (slug) DO NOTHING
+RETURNING id
+`, slug, req.Name, req.Description, userID, req.IsOpen).Scan(&teamID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "team_slug_taken"})
+		}
+		if err != nil {
+			slog.Error("failed to create team", "error", err, "user_id", userID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_create_failed"})
+		}
+		if _, err := tx.Exec(c.Context(), `
+INSERT INTO team_members (team_id, user_id, role) VALUES ($1, $2, 'owner')
+`, teamID, userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_create_failed"})
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_create_failed"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"id":      teamID.String(),
+			"slug":    slug,
+			"name":    req.Name,
+			"is_open": req.IsOpen,
+		})
+	}
+}
+
+// Mine lists the teams the current user belongs to.
+func (h *TeamsHandler) Mine() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT t.id, t.slug, t.name, t.description, t.is_open, tm.role,
+  (SELECT COUNT(*) FROM team_members m WHERE m.team_id = t.id) AS member_count
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id
+WHERE tm.user_id = $1
+ORDER BY tm.joined_at DESC
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "teams_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var id uuid.UUID
+			var slug, name, role string
+			var desc *string
+			var isOpen bool
+			var memberCount int
+			if err := rows.Scan(&id, &slug, &name, &desc, &isOpen, &role, &memberCount); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "teams_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":           id.String(),
+				"slug":         slug,
+				"name":         name,
+				"description":  desc,
+				"is_open":      isOpen,
+				"role":         role,
+				"member_count": memberCount,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"teams": out})
+	}
+}
+
+// Get returns a team and its members. Public.
+func (h *TeamsHandler) Get() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		teamID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_team_id"})
+		}
+
+		var slug, name string
+		var desc *string
+		var isOpen bool
+		var ownerID uuid.UUID
+		var createdAt time.Time
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT slug, name, description, is_open, owner_user_id, created_at
+FROM teams
+WHERE id = $1
+`, teamID).Scan(&slug, &name, &desc, &isOpen, &ownerID, &createdAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_get_failed"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT tm.user_id, tm.role, tm.joined_at, COALESCE(ga.login, ''), COALESCE(ga.avatar_url, '')
+FROM team_members tm
+LEFT JOIN github_accounts ga ON ga.user_id = tm.user_id
+WHERE tm.team_id = $1
+ORDER BY tm.joined_at ASC
+`, teamID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_get_failed"})
+		}
+		defer rows.Close()
+
+		members := []fiber.Map{}
+		for rows.Next() {
+			var memberID uuid.UUID
+			var role, login, avatar string
+			var joinedAt time.Time
+			if err := rows.Scan(&memberID, &role, &joinedAt, &login, &avatar); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_get_failed"})
+			}
+			members = append(members, fiber.Map{
+				"user_id":   memberID.String(),
+				"role":      role,
+				"joined_at": joinedAt,
+				"login":     login,
+				"avatar":    avatar,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"id":            teamID.String(),
+			"slug":          slug,
+			"name":          name,
+			"description":   desc,
+			"is_open":       isOpen,
+			"owner_user_id": ownerID.String(),
+			"created_at":    createdAt,
+			"members":       members,
+		})
+	}
+}
+
+type inviteTeamMemberRequest struct {
+	Login string `json:"login"`
+}
+
+// Invite lets the team owner invite a user by GitHub login.
+func (h *TeamsHandler) Invite() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		teamID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_team_id"})
+		}
+
+		var req inviteTeamMemberRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		login := strings.TrimPrefix(strings.TrimSpace(req.Login), "@")
+		if login == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "login_required"})
+		}
+
+		if status, errCode := h.requireTeamOwner(c.Context(), teamID, userID); errCode != "" {
+			return c.Status(status).JSON(fiber.Map{"error": errCode})
+		}
+
+		var invitedID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT user_id FROM github_accounts WHERE LOWER(login) = LOWER($1)
+`, login).Scan(&invitedID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "user_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_failed"})
+		}
+
+		var isMember bool
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT EXISTS(SELECT 1 FROM team_members WHERE team_id = $1 AND user_id = $2)
+`, teamID, invitedID).Scan(&isMember)
+		if isMember {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "already_member"})
+		}
+
+		var inviteID uuid.UUID
+		err = h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO team_invites (team_id, invited_user_id, invited_by_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (team_id, invited_user_id) WHERE status = 'pending' DO NOTHING
+RETURNING id
+`, teamID, invitedID, userID).Scan(&inviteID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invite_already_pending"})
+		}
+		if err != nil {
+			slog.Error("failed to create team invite", "error", err, "team_id", teamID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_failed"})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": inviteID.String(), "status": "pending"})
+	}
+}
+
+// MyInvites lists pending invites for the current user.
+func (h *TeamsHandler) MyInvites() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+
+		rows, err := h.db.Pool.Query(c.Context(), `
+SELECT ti.id, t.id, t.slug, t.name, COALESCE(ga.login, ''), ti.created_at
+FROM team_invites ti
+JOIN teams t ON t.id = ti.team_id
+LEFT JOIN github_accounts ga ON ga.user_id = ti.invited_by_user_id
+WHERE ti.invited_user_id = $1 AND ti.status = 'pending'
+ORDER BY ti.created_at DESC
+`, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invites_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var inviteID, teamID uuid.UUID
+			var slug, name, invitedBy string
+			var createdAt time.Time
+			if err := rows.Scan(&inviteID, &teamID, &slug, &name, &invitedBy, &createdAt); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invites_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":         inviteID.String(),
+				"team_id":    teamID.String(),
+				"team_slug":  slug,
+				"team_name":  name,
+				"invited_by": invitedBy,
+				"created_at": createdAt,
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"invites": out})
+	}
+}
+
+// RespondInvite accepts or declines a pending invite addressed to the current user.
+func (h *TeamsHandler) RespondInvite(accept bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		inviteID, err := uuid.Parse(c.Params("inviteId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_invite_id"})
+		}
+
+		status := "declined"
+		if accept {
+			status = "accepted"
+		}
+
+		tx, err := h.db.Pool.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_update_failed"})
+		}
+		defer func() { _ = tx.Rollback(c.Context()) }()
+
+		var teamID uuid.UUID
+		err = tx.QueryRow(c.Context(), `
+UPDATE team_invites
+SET status = $3, responded_at = now()
+WHERE id = $1 AND invited_user_id = $2 AND status = 'pending'
+RETURNING team_id
+`, inviteID, userID, status).Scan(&teamID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "invite_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_update_failed"})
+		}
+		if accept {
+			if err := addTeamMember(c.Context(), tx, teamID, userID); err != nil {
+				slog.Error("failed to add team member", "error", err, "team_id", teamID, "user_id", userID)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_update_failed"})
+			}
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_invite_update_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true, "status": status, "team_id": teamID.String()})
+	}
+}
+
+// Join adds the current user to an open team.
+func (h *TeamsHandler) Join() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		teamID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_team_id"})
+		}
+
+		var isOpen bool
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT is_open FROM teams WHERE id = $1`, teamID).Scan(&isOpen)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_join_failed"})
+		}
+		if !isOpen {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "team_invite_only"})
+		}
+
+		tx, err := h.db.Pool.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_join_failed"})
+		}
+		defer func() { _ = tx.Rollback(c.Context()) }()
+
+		if err := addTeamMember(c.Context(), tx, teamID, userID); err != nil {
+			slog.Error("failed to add team member", "error", err, "team_id", teamID, "user_id", userID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_join_failed"})
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_join_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// Leave removes the current user from a team. Owners cannot leave their own team.
+func (h *TeamsHandler) Leave() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		teamID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_team_id"})
+		}
+
+		var role string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT role FROM team_members WHERE team_id = $1 AND user_id = $2
+`, teamID, userID).Scan(&role)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "not_a_member"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_leave_failed"})
+		}
+		if role == "owner" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "owner_cannot_leave"})
+		}
+
+		// Past campaign attribution is kept; only upcoming/running registrations are dropped.
+		if _, err := h.db.Pool.Exec(c.Context(), `
+WITH removed AS (
+  DELETE FROM team_members WHERE team_id = $1 AND user_id = $2
+)
+DELETE FROM team_event_members tem
+USING open_source_week_events e
+WHERE tem.event_id = e.id
+  AND tem.team_id = $1
+  AND tem.user_id = $2
+  AND e.status IN ('upcoming', 'running')
+`, teamID, userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_leave_failed"})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// RegisterForEvent enters a team into a campaign. Every current member's contributions
+// during the event are attributed to this team; a member can only represent one team per event.
+func (h *TeamsHandler) RegisterForEvent() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		userID, ok := teamsCurrentUser(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		teamID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_team_id"})
+		}
+		eventID, err := uuid.Parse(c.Params("eventId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_event_id"})
+		}
+
+		if status, errCode := h.requireTeamOwner(c.Context(), teamID, userID); errCode != "" {
+			return c.Status(status).JSON(fiber.Map{"error": errCode})
+		}
+
+		var eventStatus string
+		err = h.db.Pool.QueryRow(c.Context(), `
+SELECT status FROM open_source_week_events WHERE id = $1
+`, eventID).Scan(&eventStatus)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "event_not_found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_register_failed"})
+		}
+		if eventStatus != "upcoming" && eventStatus != "running" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "event_not_open"})
+		}
+
+		ct, err := h.db.Pool.Exec(c.Context(), `
+INSERT INTO team_event_members (event_id, user_id, team_id)
+SELECT $1, tm.user_id, tm.team_id
+FROM team_members tm
+WHERE tm.team_id = $2
+ON CONFLICT (event_id, user_id) DO NOTHING
+`, eventID, teamID)
+		if err != nil {
+			slog.Error("failed to register team for event", "error", err, "team_id", teamID, "event_id", eventID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "team_register_failed"})
+		}
+
+		var registered int
+		_ = h.db.Pool.QueryRow(c.Context(), `
+SELECT COUNT(*) FROM team_event_members WHERE event_id = $1 AND team_id = $2
+`, eventID, teamID).Scan(&registered)
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"ok":                 true,
+			"added_members":      ct.RowsAffected(),
+			"registered_members": registered,
+		})
+	}
+}
+
+// Leaderboard ranks teams by the contributions of their members in verified projects.
+// With ?event_id= it only counts work done during that campaign by members registered for it.
+func (h *TeamsHandler) Leaderboard() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+
+		limit := c.QueryInt("limit", 10)
+		if limit < 1 {
+			limit = 10
+		}
+		if limit > 100 {
+			limit = 100
+		}
+		offset := c.QueryInt("offset", 0)
+		if offset < 0 {
+			offset = 0
+		}
+
+		var rows pgx.Rows
+		var err error
+		if raw := strings.TrimSpace(c.Query("event_id")); raw != "" {
+			eventID, perr := uuid.Parse(raw)
+			if perr != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_event_id"})
+			}
+			rows, err = h.db.Pool.Query(c.Context(), `
+WITH ev AS (
+  SELECT id, start_at, end_at FROM open_source_week_events WHERE id = $1
+),
+members AS (
+  SELECT tem.team_id, LOWER(ga.login) AS login
+  FROM team_event_members tem
+  JOIN ev ON ev.id = tem.event_id
+  JOIN github_accounts ga ON ga.user_id = tem.user_id
+),
+contribs AS (
+  SELECT LOWER(i.author_login) AS login, COUNT(*) AS n
+  FROM github_issues i
+  JOIN projects p ON p.id = i.project_id
+  CROSS JOIN ev
+  WHERE p.status = 'verified' AND p.deleted_at IS NULL
+    AND i.created_at_github BETWEEN ev.start_at AND ev.end_at
+  GROUP BY 1
+  UNION ALL
+  SELECT LOWER(pr.author_login) AS login, COUNT(*) AS n
+  FROM github_pull_requests pr
+  JOIN projects p ON p.id = pr.project_id
+  CROSS JOIN ev
+  WHERE p.status = 'verified' AND p.deleted_at IS NULL
+    AND pr.created_at_github BETWEEN ev.start_at AND ev.end_at
+  GROUP BY 1
+)
+SELECT t.id, t.slug, t.name,
+  COUNT(DISTINCT m.login) AS member_count,
+  COALESCE(SUM(ct.n), 0)::bigint AS contribution_count
+FROM members m
+JOIN teams t ON t.id = m.team_id
+LEFT JOIN contribs ct ON ct.login = m.login
+GROUP BY t.id, t.slug, t.name
+ORDER BY contribution_count DESC, t.name ASC
+LIMIT $2 OFFSET $3
+`, eventID, limit, offset)
+		} else {
+			rows, err = h.db.Pool.Query(c.Context(), `
+WITH members AS (
+  SELECT tm.team_id, LOWER(ga.login) AS login
+  FROM team_members tm
+  JOIN github_accounts ga ON ga.user_id = tm.user_id
+),
+contribs AS (
+  SELECT LOWER(i.author_login) AS login, COUNT(*) AS n
+  FROM github_issues i
+  JOIN projects p ON p.id = i.project_id
+  WHERE p.status = 'verified' AND p.deleted_at IS NULL
+  GROUP BY 1
+  UNION ALL
+  SELECT LOWER(pr.author_login) AS login, COUNT(*) AS n
+  FROM github_pull_requests pr
+  JOIN projects p ON p.id = pr.project_id
+  WHERE p.status = 'verified' AND p.deleted_at IS NULL
+  GROUP BY 1
+)
+SELECT t.id, t.slug, t.name,
+  COUNT(DISTINCT m.login) AS member_count,
+  COALESCE(SUM(ct.n), 0)::bigint AS contribution_count
+FROM members m
+JOIN teams t ON t.id = m.team_id
+LEFT JOIN contribs ct ON ct.login = m.login
+GROUP BY t.id, t.slug, t.name
+ORDER BY contribution_count DESC, t.name ASC
+LIMIT $1 OFFSET $2
+`, limit, offset)
+		}
+		if err != nil {
+			slog.Error("failed to fetch team leaderboard", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "leaderboard_fetch_failed"})
+		}
+		defer rows.Close()
+
+		leaderboard := []fiber.Map{}
+		rank := offset + 1
+		for rows.Next() {
+			var id uuid.UUID
+			var slug, name string
+			var memberCount int
+			var contributionCount int64
+			if err := rows.Scan(&id, &slug, &name, &memberCount, &contributionCount); err != nil {
+				slog.Error("failed to scan team leaderboard row", "error", err)
+				continue
+			}
+			rankTier := GetRankTier(rank)
+			leaderboard = append(leaderboard, fiber.Map{
+				"rank":           rank,
+				"rank_tier":      string(rankTier),
+				"rank_tier_name": GetRankTierDisplayName(rankTier),
+				"team_id":        id.String(),
+				"slug":           slug,
+				"name":           name,
+				"members":        memberCount,
+				"contributions":  contributionCount,
+				"score":          contributionCount,
+			})
+			rank++
+		}
+
+		return c.Status(fiber.StatusOK).JSON(leaderboard)
+	}
+}
+
+func teamsCurrentUser(c *fiber.Ctx) (uuid.UUID, bool) {
+	sub, _ := c.Locals(auth.LocalUserID).(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// requireTeamOwner returns a non-empty error code when userID does not own the team.
+func (h *TeamsHandler) requireTeamOwner(ctx context.Context, teamID, userID uuid.UUID) (int, string) {
+	var ownerID uuid.UUID
+	err := h.db.Pool.QueryRow(ctx, `SELECT owner_user_id FROM teams WHERE id = $1`, teamID).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fiber.StatusNotFound, "team_not_found"
+	}
+	if err != nil {
+		return fiber.StatusInternalServerError, "team_lookup_failed"
+	}
+	if ownerID != userID {
+		return fiber.StatusForbidden, "forbidden"
+	}
+	return 0, ""
+}
+
+// addTeamMember inserts the membership and attributes the user to the team for any
+// upcoming/running campaign the team is registered in (unless already attributed elsewhere).
+func addTeamMember(ctx context.Context, tx pgx.Tx, teamID, userID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+INSERT INTO team_members (team_id, user_id, role)
+VALUES ($1, $2, 'member')
+ON CONFLICT (team_id, user_id) DO NOTHING
+`, teamID, userID); err != nil {
+		return fmt.Errorf("insert team member: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO team_event_members (event_id, user_id, team_id)
+SELECT DISTINCT tem.event_id, $2::uuid, $1::uuid
+FROM team_event_members tem
+JOIN open_source_week_events e ON e.id = tem.event_id
+WHERE tem.team_id = $1 AND e.status IN ('upcoming', 'running')
+ON CONFLICT (event_id, user_id) DO NOTHING
+`, teamID, userID); err != nil {
+		return fmt.Errorf("attribute team events: %w", err)
+	}
+	return nil
+}
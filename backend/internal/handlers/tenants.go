@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/tenant"
+)
+
+type TenantsHandler struct {
+	cfg      config.Config
+	db       *db.DB
+	resolver *tenant.Resolver
+}
+
+func NewTenantsHandler(cfg config.Config, d *db.DB, resolver *tenant.Resolver) *TenantsHandler {
+	return &TenantsHandler{cfg: cfg, db: d, resolver: resolver}
+}
+
+// Current returns the public profile of the tenant serving this request, for the frontend's branding.
+func (h *TenantsHandler) Current() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		t := tenant.FromCtx(c)
+		if t == nil {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"slug": "default", "name": "Grainlify", "branding": fiber.Map{}})
+		}
+		out := fiber.Map{
+			"id":       t.ID.String(),
+			"slug":     t.Slug,
+			"name":     t.Name,
+			"branding": t.Branding,
+		}
+		if t.GitHubAppID != nil {
+			if app, ok := h.cfg.GitHubApp(*t.GitHubAppID); ok {
+				out["github_app_slug"] = app.Slug
+			}
+		}
+		return c.Status(fiber.StatusOK).JSON(out)
+	}
+}
+
+// RequireDefaultTenant limits tenant management to admins signed in on the default tenant's hosts.
+func (h *TenantsHandler) RequireDefaultTenant() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if t := tenant.FromCtx(c); t != nil && !t.IsDefault {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "default_tenant_only"})
+		}
+		return c.Next()
+	}
+}
+
+func (h *TenantsHandler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		// Counts span every tenant, so the query can't be scoped to the one serving this request.
+		rows, err := h.db.Pool.Query(tenant.Unscoped(c.Context()), `
+SELECT t.id, t.slug, t.name, t.hosts, t.branding, t.github_app_id, t.frontend_url, t.is_default,
+       (SELECT COUNT(*) FROM ecosystems e WHERE e.tenant_id = t.id),
+       (SELECT COUNT(*) FROM projects p WHERE p.tenant_id = t.id)
+FROM tenants t
+ORDER BY t.is_default DESC, t.created_at
+`)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenants_list_failed"})
+		}
+		defer rows.Close()
+
+		out := []fiber.Map{}
+		for rows.Next() {
+			var t tenant.Tenant
+			var ecosystems, projects int64
+			if err := rows.Scan(&t.ID, &t.Slug, &t.Name, &t.Hosts, &t.Branding, &t.GitHubAppID, &t.FrontendURL, &t.IsDefault, &ecosystems, &projects); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenants_list_failed"})
+			}
+			out = append(out, fiber.Map{
+				"id":              t.ID.String(),
+				"slug":            t.Slug,
+				"name":            t.Name,
+				"hosts":           t.Hosts,
+				"branding":        t.Branding,
+				"github_app_id":   t.GitHubAppID,
+				"frontend_url":    t.FrontendURL,
+				"is_default":      t.IsDefault,
+				"ecosystem_count": ecosystems,
+				"project_count":   projects,
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"tenants": out})
+	}
+}
+
+type tenantUpsertRequest struct {
+	Slug        *string         `json:"slug"`
+	Name        *string         `json:"name"`
+	Hosts       []string        `json:"hosts"`
+	Branding    json.RawMessage `json:"branding"`
+	GitHubAppID *string         `json:"github_app_id"`
+	FrontendURL *string         `json:"frontend_url"`
+}
+
+// validate normalizes req in place and returns an error code for the first invalid field.
+func (h *TenantsHandler) validate(req *tenantUpsertRequest) string {
+	if req.Slug != nil {
+		slug := normalizeSlug(*req.Slug)
+		if slug == "" {
+			return "invalid_slug"
+		}
+		req.Slug = &slug
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return "name_required"
+		}
+		req.Name = &name
+	}
+	if req.Hosts != nil {
+		hosts := make([]string, 0, len(req.Hosts))
+		for _, raw := range req.Hosts {
+			host := tenant.NormalizeHost(raw)
+			if host == "" || strings.ContainsAny(host, "/ ") {
+				return "invalid_host"
+			}
+			hosts = append(hosts, host)
+		}
+		req.Hosts = hosts
+	}
+	if len(req.Branding) > 0 {
+		var obj map[string]any
+		if err := json.Unmarshal(req.Branding, &obj); err != nil {
+			return "invalid_branding"
+		}
+	}
+	if req.GitHubAppID != nil {
+		appID := strings.TrimSpace(*req.GitHubAppID)
+		if appID != "" {
+			if _, ok := h.cfg.GitHubApp(appID); !ok {
+				return "unknown_github_app"
+			}
+		}
+		req.GitHubAppID = &appID
+	}
+	if req.FrontendURL != nil {
+		v := strings.TrimSpace(*req.FrontendURL)
+		if v != "" {
+			u, err := url.Parse(v)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return "invalid_frontend_url"
+			}
+		}
+		req.FrontendURL = &v
+	}
+	return ""
+}
+
+func (h *TenantsHandler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		var req tenantUpsertRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if req.Name == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name_required"})
+		}
+		if req.Slug == nil {
+			req.Slug = req.Name
+		}
+		if code := h.validate(&req); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		if req.Hosts == nil {
+			req.Hosts = []string{}
+		}
+		if len(req.Branding) == 0 {
+			req.Branding = json.RawMessage("{}")
+		}
+		if taken, err := h.hostsTaken(c, req.Hosts, uuid.Nil); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenant_create_failed"})
+		} else if len(taken) > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "host_taken", "hosts": taken})
+		}
+
+		var id uuid.UUID
+		err := h.db.Pool.QueryRow(c.Context(), `
+INSERT INTO tenants (slug, name, hosts, branding, github_app_id, frontend_url)
+VALUES ($1, $2, $3, $4::jsonb, NULLIF($5, ''), NULLIF($6, ''))
+RETURNING id
+`, *req.Slug, *req.Name, req.Hosts, req.Branding, req.GitHubAppID, req.FrontendURL).Scan(&id)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "tenant_slug_taken"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenant_create_failed"})
+		}
+		h.resolver.Invalidate()
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id.String()})
+	}
+}
+
+func (h *TenantsHandler) Update() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_tenant_id"})
+		}
+		var req tenantUpsertRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if code := h.validate(&req); code != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": code})
+		}
+		if taken, err := h.hostsTaken(c, req.Hosts, id); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenant_update_failed"})
+		} else if len(taken) > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "host_taken", "hosts": taken})
+		}
+
+		var branding *string
+		if len(req.Branding) > 0 {
+			s := string(req.Branding)
+			branding = &s
+		}
+		ct, err := h.db.Pool.Exec(c.Context(), `
+UPDATE tenants
+SET slug = COALESCE($2, slug),
+    name = COALESCE($3, name),
+    hosts = COALESCE($4::text[], hosts),
+    branding = COALESCE($5::jsonb, branding),
+    github_app_id = CASE WHEN $6::text IS NULL THEN github_app_id ELSE NULLIF($6::text, '') END,
+    frontend_url = CASE WHEN $7::text IS NULL THEN frontend_url ELSE NULLIF($7::text, '') END,
+    updated_at = now()
+WHERE id = $1
+`, id, req.Slug, req.Name, req.Hosts, branding, req.GitHubAppID, req.FrontendURL)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "tenant_slug_taken"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "tenant_update_failed"})
+		}
+		if ct.RowsAffected() == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "tenant_not_found"})
+		}
+		h.resolver.Invalidate()
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"ok": true})
+	}
+}
+
+// hostsTaken returns the hosts already claimed by a tenant other than except: a host must resolve to one tenant.
+func (h *TenantsHandler) hostsTaken(c *fiber.Ctx, hosts []string, except uuid.UUID) ([]string, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	var taken []string
+	err := h.db.Pool.QueryRow(c.Context(), `
+SELECT COALESCE(array_agg(DISTINCT h), '{}')
+FROM tenants t, unnest(t.hosts) AS h
+WHERE t.id <> $2 AND h = ANY($1)
+`, hosts, except).Scan(&taken)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return taken, err
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/availability"
+)
+
+// Availability serves GET /profile/availability: the caller's schedule, or the default (weekdays, UTC)
+// with is_default set when they never saved one.
+func (h *UserProfileHandler) Availability() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		s, set, err := loadAvailability(c.Context(), h.db.Pool, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "availability_lookup_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(availabilityResponse(s, !set, time.Now()))
+	}
+}
+
+// UpdateAvailability serves PUT /profile/availability with a full schedule.
+func (h *UserProfileHandler) UpdateAvailability() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var s availability.Schedule
+		if err := json.Unmarshal(c.Body(), &s); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		now := time.Now()
+		if err := s.Validate(now); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_availability", "message": err.Error()})
+		}
+		raw, _ := json.Marshal(s)
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE users SET availability = $2, updated_at = now() WHERE id = $1
+`, userID, raw); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "availability_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(availabilityResponse(s, false, now))
+	}
+}
+
+func availabilityResponse(s availability.Schedule, isDefault bool, now time.Time) fiber.Map {
+	return fiber.Map{
+		"timezone":       s.TimeZone,
+		"weekly":         s.Weekly,
+		"unavailable":    s.Unavailable,
+		"hours_per_week": s.HoursPerWeek(),
+		"away":           s.Away(now),
+		"is_default":     isDefault,
+	}
+}
+
+// loadAvailability returns the user's schedule and whether they set one; unset schedules are
+// availability.Default().
+func loadAvailability(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (availability.Schedule, bool, error) {
+	var raw []byte
+	err := pool.QueryRow(ctx, `SELECT availability FROM users WHERE id = $1`, userID).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return availability.Default(), false, nil
+	}
+	if err != nil {
+		return availability.Schedule{}, false, err
+	}
+	s, set := parseAvailability(raw)
+	return s, set, nil
+}
+
+// parseAvailability decodes a users.availability value, falling back to the default when unset.
+func parseAvailability(raw []byte) (availability.Schedule, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return availability.Default(), false
+	}
+	var s availability.Schedule
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return availability.Default(), false
+	}
+	if s.Unavailable == nil {
+		s.Unavailable = []availability.Period{}
+	}
+	return s, true
+}
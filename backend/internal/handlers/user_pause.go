@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/pause"
+)
+
+type pauseRequest struct {
+	Until  *time.Time `json:"until"`
+	Reason *string    `json:"reason"`
+}
+
+// PauseStatus serves GET /profile/pause.
+func (h *UserProfileHandler) PauseStatus() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var pausedAt, until *time.Time
+		var reason *string
+		var held int
+		if err := h.db.Pool.QueryRow(c.Context(), `
+SELECT u.paused_at, u.paused_until, u.pause_reason,
+       (SELECT COUNT(*) FROM issue_applications a WHERE a.user_id = u.id AND a.held_at IS NOT NULL)
+FROM users u
+WHERE u.id = $1
+`, userID).Scan(&pausedAt, &until, &reason, &held); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pause_lookup_failed"})
+		}
+		paused := until != nil && until.After(time.Now())
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"paused":            paused,
+			"paused_at":         pausedAt,
+			"until":             until,
+			"reason":            reason,
+			"held_applications": held,
+		})
+	}
+}
+
+// Pause serves PUT /profile/pause: pauses the caller until the given time (at most pause.MaxDays ahead),
+// holding their pending applications. Calling it again while paused changes the end date.
+func (h *UserProfileHandler) Pause() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var req pauseRequest
+		if err := json.Unmarshal(c.Body(), &req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		now := time.Now()
+		if req.Until == nil || !req.Until.After(now) || req.Until.After(now.AddDate(0, 0, pause.MaxDays)) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_until", "max_days": pause.MaxDays})
+		}
+		if req.Reason != nil {
+			r := strings.TrimSpace(*req.Reason)
+			if len(r) > 500 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "reason_too_long"})
+			}
+			req.Reason = nullIfEmpty(r)
+		}
+		held, err := pause.Start(c.Context(), h.db.Pool, userID, req.Until.UTC(), req.Reason)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "pause_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"paused": true, "until": req.Until.UTC(), "held_applications": held})
+	}
+}
+
+// Resume serves DELETE /profile/pause: ends the pause early and releases held applications.
+func (h *UserProfileHandler) Resume() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		released, err := pause.Resume(c.Context(), h.db.Pool, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "resume_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"paused": false, "released_applications": released})
+	}
+}
@@ -13,15 +13,17 @@ import (
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/db"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/skills"
 )
 
 type UserProfileHandler struct {
 	cfg config.Config
 	db  *db.DB
+	gh  github.API
 }
 
-func NewUserProfileHandler(cfg config.Config, d *db.DB) *UserProfileHandler {
-	return &UserProfileHandler{cfg: cfg, db: d}
+func NewUserProfileHandler(cfg config.Config, d *db.DB, gh github.API) *UserProfileHandler {
+	return &UserProfileHandler{cfg: cfg, db: d, gh: gh}
 }
 
 // Profile returns the user's profile statistics including:
@@ -222,11 +224,12 @@ WHERE login = $1
 		// Get user profile fields (bio, website, social links, kyc) from users table
 		var bio, website, telegram, linkedin, whatsapp, twitter, discord *string
 		var kycStatus *string
+		var userSkills []string
 		_ = h.db.Pool.QueryRow(c.Context(), `
-SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status
+SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status, skills
 FROM users
 WHERE id = $1
-`, userID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus)
+`, userID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus, &userSkills)
 
 		// Count distinct projects user has contributed to (via issues or PRs)
 		var projectsContributedToCount int
@@ -300,6 +303,9 @@ WHERE p.status = 'verified'
 		if discord != nil && *discord != "" {
 			response["discord"] = *discord
 		}
+		if len(userSkills) > 0 {
+			response["skills"] = userSkills
+		}
 
 		return c.Status(fiber.StatusOK).JSON(response)
 	}
@@ -541,6 +547,25 @@ FROM github_pull_requests pr
 INNER JOIN projects p ON pr.project_id = p.id
 WHERE pr.author_login = $1 AND p.status = 'verified' AND pr.created_at_github IS NOT NULL
 
+UNION ALL
+
+-- PRs the user co-authored (Co-authored-by trailers or commits) but did not open
+SELECT 
+  'co_authored_pull_request' as contribution_type,
+  pr.id,
+  pr.number,
+  pr.title,
+  pr.url,
+  pr.created_at_github,
+  pr.state,
+  p.github_full_name as project_name,
+  p.id as project_id
+FROM github_pr_contributors gc
+INNER JOIN github_pull_requests pr ON pr.project_id = gc.project_id AND pr.number = gc.pr_number
+INNER JOIN projects p ON pr.project_id = p.id
+WHERE LOWER(gc.login) = LOWER($1) AND gc.role = 'co_author' AND pr.author_login != $1
+  AND p.status = 'verified' AND pr.created_at_github IS NOT NULL
+
 ORDER BY created_at_github DESC
 LIMIT $2 OFFSET $3
 `, *githubLogin, limit, offset)
@@ -597,6 +622,12 @@ SELECT
   (SELECT COUNT(*) FROM github_pull_requests pr
    INNER JOIN projects p ON pr.project_id = p.id
    WHERE pr.author_login = $1 AND p.status = 'verified' AND pr.created_at_github IS NOT NULL)
+  +
+  (SELECT COUNT(*) FROM github_pr_contributors gc
+   INNER JOIN github_pull_requests pr ON pr.project_id = gc.project_id AND pr.number = gc.pr_number
+   INNER JOIN projects p ON pr.project_id = p.id
+   WHERE LOWER(gc.login) = LOWER($1) AND gc.role = 'co_author' AND pr.author_login != $1
+     AND p.status = 'verified' AND pr.created_at_github IS NOT NULL)
 `, *githubLogin).Scan(&total)
 		if err != nil {
 			slog.Error("failed to count total activities", "error", err)
@@ -704,7 +735,7 @@ LIMIT 10
 			// It's the authenticated user, try to get access token
 			sub, _ := c.Locals(auth.LocalUserID).(string)
 			if userID, parseErr := uuid.Parse(sub); parseErr == nil {
-				linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKeyB64)
+				linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, userID, h.cfg.TokenEncKey())
 				if err == nil {
 					accessToken = linkedAccount.AccessToken
 				}
@@ -712,14 +743,14 @@ LIMIT 10
 		} else if userIDParam != "" {
 			// Try to get access token for the specified user
 			if parsedUserID, parseErr := uuid.Parse(userIDParam); parseErr == nil {
-				linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, parsedUserID, h.cfg.TokenEncKeyB64)
+				linkedAccount, err := github.GetLinkedAccount(c.Context(), h.db.Pool, parsedUserID, h.cfg.TokenEncKey())
 				if err == nil {
 					accessToken = linkedAccount.AccessToken
 				}
 			}
 		}
 
-		gh := github.NewClient()
+		gh := h.gh
 		var projects []fiber.Map
 		for rows.Next() {
 			var id uuid.UUID
@@ -806,10 +837,10 @@ ORDER BY p.github_full_name ASC
 		defer rows.Close()
 
 		var accessToken string
-		if linkedAccount, errLA := github.GetLinkedAccount(c.Context(), h.db.Pool, *targetUserID, h.cfg.TokenEncKeyB64); errLA == nil {
+		if linkedAccount, errLA := github.GetLinkedAccount(c.Context(), h.db.Pool, *targetUserID, h.cfg.TokenEncKey()); errLA == nil {
 			accessToken = linkedAccount.AccessToken
 		}
-		gh := github.NewClient()
+		gh := h.gh
 		var projects []fiber.Map
 		for rows.Next() {
 			var id uuid.UUID
@@ -860,6 +891,7 @@ func (h *UserProfileHandler) PublicProfile() fiber.Handler {
 		var userID *uuid.UUID
 		var bio, website, telegram, linkedin, whatsapp, twitter, discord *string
 		var kycStatus *string
+		var userSkills []string
 
 		// If user_id is provided, get GitHub login from it
 		if userIDParam != "" {
@@ -881,10 +913,10 @@ WHERE user_id = $1
 
 			// Get profile fields
 			_ = h.db.Pool.QueryRow(c.Context(), `
-SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status
+SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status, skills
 FROM users
 WHERE id = $1
-`, parsedUserID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus)
+`, parsedUserID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus, &userSkills)
 		} else {
 			// If login is provided, get user_id from it
 			loginParamLower := strings.ToLower(loginParam)
@@ -918,10 +950,10 @@ WHERE LOWER(ga.login) = $1
 
 			// Get profile fields
 			_ = h.db.Pool.QueryRow(c.Context(), `
-SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status
+SELECT bio, website, telegram, linkedin, whatsapp, twitter, discord, kyc_status, skills
 FROM users
 WHERE id = $1
-`, foundUserID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus)
+`, foundUserID).Scan(&bio, &website, &telegram, &linkedin, &whatsapp, &twitter, &discord, &kycStatus, &userSkills)
 		}
 
 		if githubLogin == nil || *githubLogin == "" {
@@ -1174,6 +1206,9 @@ WHERE u.id = $1
 		if discord != nil && *discord != "" {
 			response["discord"] = *discord
 		}
+		if len(userSkills) > 0 {
+			response["skills"] = userSkills
+		}
 
 		return c.Status(fiber.StatusOK).JSON(response)
 	}
@@ -1205,7 +1240,8 @@ func calculateContributionLevel(count int, maxCount int) int {
 	}
 }
 
-// UpdateProfile updates user profile information (first_name, last_name, location, website, bio)
+// UpdateProfile updates user profile information (first_name, last_name, location, website, bio, skills).
+// Skills must come from the controlled vocabulary served at GET /skills.
 func (h *UserProfileHandler) UpdateProfile() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if h.db == nil || h.db.Pool == nil {
@@ -1220,16 +1256,17 @@ func (h *UserProfileHandler) UpdateProfile() fiber.Handler {
 		}
 
 		var req struct {
-			FirstName *string `json:"first_name,omitempty"`
-			LastName  *string `json:"last_name,omitempty"`
-			Location  *string `json:"location,omitempty"`
-			Website   *string `json:"website,omitempty"`
-			Bio       *string `json:"bio,omitempty"`
-			Telegram  *string `json:"telegram,omitempty"`
-			LinkedIn  *string `json:"linkedin,omitempty"`
-			WhatsApp  *string `json:"whatsapp,omitempty"`
-			Twitter   *string `json:"twitter,omitempty"`
-			Discord   *string `json:"discord,omitempty"`
+			FirstName *string   `json:"first_name,omitempty"`
+			LastName  *string   `json:"last_name,omitempty"`
+			Location  *string   `json:"location,omitempty"`
+			Website   *string   `json:"website,omitempty"`
+			Bio       *string   `json:"bio,omitempty"`
+			Telegram  *string   `json:"telegram,omitempty"`
+			LinkedIn  *string   `json:"linkedin,omitempty"`
+			WhatsApp  *string   `json:"whatsapp,omitempty"`
+			Twitter   *string   `json:"twitter,omitempty"`
+			Discord   *string   `json:"discord,omitempty"`
+			Skills    *[]string `json:"skills,omitempty"`
 		}
 
 		if err := c.BodyParser(&req); err != nil {
@@ -1291,6 +1328,18 @@ func (h *UserProfileHandler) UpdateProfile() fiber.Handler {
 			args = append(args, strings.TrimSpace(*req.Discord))
 			argPos++
 		}
+		if req.Skills != nil {
+			ids, unknown := skills.Normalize(*req.Skills)
+			if len(unknown) > 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_skills", "unknown": unknown})
+			}
+			if len(ids) > skills.MaxPerUser {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "too_many_skills", "max": skills.MaxPerUser})
+			}
+			updates = append(updates, fmt.Sprintf("skills = $%d", argPos))
+			args = append(args, ids)
+			argPos++
+		}
 
 		if len(updates) == 0 {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "no_fields_to_update"})
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/jagadeesh/grainlify/backend/internal/auth"
+	"github.com/jagadeesh/grainlify/backend/internal/reminders"
+)
+
+// ReminderPreferences serves GET /profile/reminders: when and how the caller gets reminders, or the
+// defaults with is_default set when they never saved any.
+func (h *UserProfileHandler) ReminderPreferences() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var raw []byte
+		err = h.db.Pool.QueryRow(c.Context(), `SELECT reminder_preferences FROM users WHERE id = $1`, userID).Scan(&raw)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reminder_preferences_lookup_failed"})
+		}
+		p, set := reminders.Parse(raw)
+		return c.Status(fiber.StatusOK).JSON(reminderPreferencesResponse(p, !set))
+	}
+}
+
+// UpdateReminderPreferences serves PUT /profile/reminders with the full preferences.
+func (h *UserProfileHandler) UpdateReminderPreferences() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if h.db == nil || h.db.Pool == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "db_not_configured"})
+		}
+		sub, _ := c.Locals(auth.LocalUserID).(string)
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_user"})
+		}
+		var p reminders.Preferences
+		if err := json.Unmarshal(c.Body(), &p); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_json"})
+		}
+		if err := p.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_reminder_preferences", "message": err.Error()})
+		}
+		raw, _ := json.Marshal(p)
+		if _, err := h.db.Pool.Exec(c.Context(), `
+UPDATE users SET reminder_preferences = $2, updated_at = now() WHERE id = $1
+`, userID, raw); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "reminder_preferences_update_failed"})
+		}
+		return c.Status(fiber.StatusOK).JSON(reminderPreferencesResponse(p, false))
+	}
+}
+
+func reminderPreferencesResponse(p reminders.Preferences, isDefault bool) fiber.Map {
+	return fiber.Map{
+		"timezone":       p.TimeZone,
+		"quiet_hours":    p.Quiet,
+		"deadline":       p.Deadline,
+		"pending_review": p.PendingReview,
+		"max_lead_hours": reminders.MaxLeadHours,
+		"is_default":     isDefault,
+	}
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/jagadeesh/grainlify/backend/internal/validate"
+)
+
+// validationFailed responds 400 with every field that failed in "fields". "error" stays the single code
+// clients matched on before the per-field details (e.g. message_too_long).
+func validationFailed(c *fiber.Ctx, err error) error {
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_request", "message": err.Error()})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": errs.Code(), "fields": errs})
+}
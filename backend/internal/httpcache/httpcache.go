@@ -0,0 +1,68 @@
+// Package httpcache lets clients revalidate GET responses instead of downloading them again: every
+// successful response gets an ETag from its body, handlers that know when their data last changed set
+// Last-Modified, and matching If-None-Match / If-Modified-Since requests get 304 Not Modified.
+package httpcache
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// New returns the conditional GET middleware. Handlers that set their own ETag (e.g. for If-Match on
+// updates) keep it.
+func New() fiber.Handler {
+	tags := etag.New(etag.Config{
+		Weak: true,
+		Next: func(c *fiber.Ctx) bool { return !readOnly(c) },
+	})
+	return func(c *fiber.Ctx) error {
+		if err := tags(c); err != nil {
+			return err
+		}
+		if !readOnly(c) || c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+		// If-None-Match takes precedence (RFC 9110 13.1.3); the ETag middleware has already answered it.
+		if c.Get(fiber.HeaderIfNoneMatch) != "" {
+			return nil
+		}
+		if NotModified(string(c.Response().Header.Peek(fiber.HeaderLastModified)), c.Get(fiber.HeaderIfModifiedSince)) {
+			c.Context().ResetBody()
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return nil
+	}
+}
+
+func readOnly(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead
+}
+
+// SetLastModified records when the data behind the response last changed. Zero times are ignored. Only
+// set it when every change to that data moves t (the issue and PR lists don't: Grainlify changes those rows
+// without touching their GitHub timestamps); otherwise the body's ETag alone is the safe validator.
+func SetLastModified(c *fiber.Ctx, t time.Time) {
+	if !t.IsZero() {
+		c.Set(fiber.HeaderLastModified, t.UTC().Format(http.TimeFormat))
+	}
+}
+
+// NotModified reports whether a response last modified at lastModified is unchanged for a client that
+// has the version from ifModifiedSince (both HTTP dates, second precision).
+func NotModified(lastModified, ifModifiedSince string) bool {
+	if lastModified == "" || ifModifiedSince == "" {
+		return false
+	}
+	lm, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	ims, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lm.After(ims)
+}
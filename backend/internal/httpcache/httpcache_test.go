@@ -0,0 +1,81 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNotModified(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lm := at.Format(http.TimeFormat)
+	cases := []struct {
+		lastModified, since string
+		want                bool
+	}{
+		{lm, lm, true},
+		{lm, at.Add(time.Hour).Format(http.TimeFormat), true},
+		{lm, at.Add(-time.Second).Format(http.TimeFormat), false},
+		{"", lm, false},
+		{lm, "", false},
+		{lm, "yesterday", false},
+	}
+	for _, tc := range cases {
+		if got := NotModified(tc.lastModified, tc.since); got != tc.want {
+			t.Errorf("NotModified(%q, %q) = %v, want %v", tc.lastModified, tc.since, got, tc.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	changed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/items", func(c *fiber.Ctx) error {
+		SetLastModified(c, changed)
+		return c.JSON(fiber.Map{"items": []int{1, 2, 3}})
+	})
+	app.Post("/items", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"ok": true})
+	})
+
+	do := func(method string, header map[string]string) *http.Response {
+		req := httptest.NewRequest(method, "/items", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	first := do(fiber.MethodGet, nil)
+	tag := first.Header.Get(fiber.HeaderETag)
+	if first.StatusCode != fiber.StatusOK || tag == "" || first.Header.Get(fiber.HeaderLastModified) == "" {
+		t.Fatalf("first GET: status %d, ETag %q, Last-Modified %q", first.StatusCode, tag, first.Header.Get(fiber.HeaderLastModified))
+	}
+	if r := do(fiber.MethodGet, map[string]string{fiber.HeaderIfNoneMatch: tag}); r.StatusCode != fiber.StatusNotModified {
+		t.Errorf("If-None-Match: status %d, want 304", r.StatusCode)
+	}
+	if r := do(fiber.MethodGet, map[string]string{fiber.HeaderIfModifiedSince: changed.Format(http.TimeFormat)}); r.StatusCode != fiber.StatusNotModified {
+		t.Errorf("If-Modified-Since: status %d, want 304", r.StatusCode)
+	} else if body, _ := io.ReadAll(r.Body); len(body) != 0 {
+		t.Errorf("304 carries a body: %q", body)
+	}
+	stale := map[string]string{
+		fiber.HeaderIfNoneMatch:     `W/"0-0"`,
+		fiber.HeaderIfModifiedSince: changed.Format(http.TimeFormat),
+	}
+	if r := do(fiber.MethodGet, stale); r.StatusCode != fiber.StatusOK {
+		t.Errorf("a stale ETag wins over If-Modified-Since: status %d, want 200", r.StatusCode)
+	}
+	if r := do(fiber.MethodPost, nil); r.Header.Get(fiber.HeaderETag) != "" {
+		t.Errorf("POST got an ETag %q", r.Header.Get(fiber.HeaderETag))
+	}
+}
@@ -0,0 +1,87 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can subscribe to.
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event is one calendar entry. A deadline is an event that starts and ends at the same instant.
+type Event struct {
+	UID         string // stable across feed refreshes, so apps update the entry rather than duplicate it
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+}
+
+// Calendar is a named list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+const timeFormat = "20060102T150405Z"
+
+// Render returns the calendar as an iCalendar document. stamp is the DTSTAMP of every event (when the feed
+// was generated).
+func Render(cal Calendar, stamp time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		fold(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Grainlify//Deadlines//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		end := e.End
+		if end.Before(e.Start) {
+			end = e.Start
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", escape(e.UID))
+		line("DTSTAMP", stamp.UTC().Format(timeFormat))
+		line("DTSTART", e.Start.UTC().Format(timeFormat))
+		line("DTEND", end.UTC().Format(timeFormat))
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// fold writes a content line, breaking it every 75 octets (without splitting a character) and ending it
+// with CRLF as the format requires.
+func fold(b *strings.Builder, s string) {
+	limit := 75
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		b.WriteString(s[:i])
+		b.WriteString("\r\n ")
+		s = s[i:]
+		limit = 74 // the leading space of a continuation line counts
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,59 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	at := time.Date(2026, 3, 1, 17, 0, 0, 0, time.FixedZone("CET", 3600))
+	out := Render(Calendar{Name: "Deadlines", Events: []Event{{
+		UID:         "deadline-1@grainlify",
+		Summary:     "Due: fix parser, lexer; docs",
+		Description: "line one\nline two",
+		URL:         "https://github.com/o/r/issues/1",
+		Start:       at,
+		End:         at,
+	}}}, at)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Deadlines\r\n",
+		"UID:deadline-1@grainlify\r\n",
+		"DTSTART:20260301T160000Z\r\n",
+		"DTEND:20260301T160000Z\r\n",
+		`SUMMARY:Due: fix parser\, lexer\; docs` + "\r\n",
+		`DESCRIPTION:line one\nline two` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	var b strings.Builder
+	fold(&b, "SUMMARY:"+strings.Repeat("é", 100))
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("not folded: %q", b.String())
+	}
+	var joined strings.Builder
+	for i, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line %d is %d octets", i, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Errorf("continuation line %d does not start with a space", i)
+			}
+			l = l[1:]
+		}
+		joined.WriteString(l)
+	}
+	if joined.String() != "SUMMARY:"+strings.Repeat("é", 100) {
+		t.Errorf("unfolded = %q", joined.String())
+	}
+}
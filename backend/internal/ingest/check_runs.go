@@ -0,0 +1,71 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+)
+
+// trackPullRequestCheckRun records which tracked issues a PR closes so the check run publisher can
+// create or refresh its Grainlify check run. No external calls are made here.
+func (i *GitHubWebhookIngestor) trackPullRequestCheckRun(ctx context.Context, projectID string, action string, pr *ghPullRequestPayload) {
+	switch action {
+	case "opened", "reopened", "edited", "synchronize", "ready_for_review":
+	default:
+		return
+	}
+	headSHA := strings.TrimSpace(pr.Head.SHA)
+	if headSHA == "" || pr.Number <= 0 {
+		return
+	}
+	var headRepo, headOwner string
+	if pr.Head.Repo != nil {
+		headRepo, headOwner = pr.Head.Repo.FullName, pr.Head.Repo.Owner.Login
+	}
+	linked := checkruns.LinkedIssues(pr.Body)
+	issueNumbers := make([]int32, 0, len(linked))
+	for _, n := range linked {
+		issueNumbers = append(issueNumbers, int32(n))
+	}
+
+	if len(issueNumbers) == 0 {
+		// The PR no longer references any issue: refresh an existing check run, but don't start one.
+		_, _ = i.Pool.Exec(ctx, `
+UPDATE pr_check_runs
+SET issue_numbers = '{}',
+    check_run_id = CASE WHEN head_sha = $3 THEN check_run_id END,
+    head_sha = $3, head_repo_full_name = NULLIF($4, ''), head_repo_owner = NULLIF($5, ''),
+    needs_publish = true, attempts = 0, updated_at = now()
+WHERE project_id = $1::uuid AND pr_number = $2
+`, projectID, pr.Number, headSHA, headRepo, headOwner)
+		return
+	}
+
+	// A new head commit needs a new check run, so the stored ID is dropped when the SHA changes.
+	_, _ = i.Pool.Exec(ctx, `
+INSERT INTO pr_check_runs (project_id, pr_number, head_sha, author_login, issue_numbers, head_repo_full_name, head_repo_owner)
+VALUES ($1::uuid, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''))
+ON CONFLICT (project_id, pr_number) DO UPDATE SET
+  check_run_id = CASE WHEN pr_check_runs.head_sha = EXCLUDED.head_sha THEN pr_check_runs.check_run_id END,
+  head_sha = EXCLUDED.head_sha,
+  head_repo_full_name = EXCLUDED.head_repo_full_name,
+  head_repo_owner = EXCLUDED.head_repo_owner,
+  author_login = EXCLUDED.author_login,
+  issue_numbers = EXCLUDED.issue_numbers,
+  needs_publish = true,
+  attempts = 0,
+  updated_at = now()
+`, projectID, pr.Number, headSHA, pr.User.Login, issueNumbers, headRepo, headOwner)
+}
+
+// markIssueCheckRuns flags check runs of PRs linked to an issue after the issue changed on GitHub.
+func (i *GitHubWebhookIngestor) markIssueCheckRuns(ctx context.Context, projectID string, issueNumber int) {
+	pid, err := uuid.Parse(projectID)
+	if err != nil {
+		return
+	}
+	checkruns.MarkIssueChanged(ctx, i.Pool, pid, issueNumber)
+}
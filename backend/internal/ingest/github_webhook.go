@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/jagadeesh/grainlify/backend/internal/events"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
 )
 
 type GitHubWebhookIngestor struct {
@@ -55,12 +56,17 @@ ON CONFLICT (delivery_id) DO NOTHING
 	if projectID != nil {
 		if e.Event == "issues" && env.Issue != nil {
 			issue := env.Issue
+			assigneesJSON, _ := json.Marshal(issue.Assignees)
+			if issue.Assignees == nil {
+				assigneesJSON = []byte("[]")
+			}
 			_, _ = i.Pool.Exec(ctx, `
-INSERT INTO github_issues (project_id, github_issue_id, number, state, title, body, author_login, url, created_at_github, updated_at_github, closed_at_github, last_seen_at)
-VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now())
+INSERT INTO github_issues (project_id, github_issue_id, number, state, state_reason, title, body, author_login, url, created_at_github, updated_at_github, closed_at_github, assignees, last_seen_at)
+VALUES ($1::uuid, $2, $3, $4, $13, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, now())
 ON CONFLICT (project_id, github_issue_id) DO UPDATE SET
   number = EXCLUDED.number,
   state = EXCLUDED.state,
+  state_reason = EXCLUDED.state_reason,
   title = EXCLUDED.title,
   body = EXCLUDED.body,
   author_login = EXCLUDED.author_login,
@@ -68,15 +74,25 @@ ON CONFLICT (project_id, github_issue_id) DO UPDATE SET
   created_at_github = EXCLUDED.created_at_github,
   updated_at_github = EXCLUDED.updated_at_github,
   closed_at_github = EXCLUDED.closed_at_github,
+  assignees = EXCLUDED.assignees,
   last_seen_at = now()
-`, *projectID, issue.ID, issue.Number, issue.State, issue.Title, issue.Body, issue.User.Login, issue.HTMLURL, issue.CreatedAt, issue.UpdatedAt, issue.ClosedAt)
+-- An older snapshot (out-of-order delivery, a sync page fetched before a newer change) must not win.
+WHERE EXCLUDED.updated_at_github IS NULL OR github_issues.updated_at_github IS NULL
+   OR EXCLUDED.updated_at_github >= github_issues.updated_at_github
+`, *projectID, issue.ID, issue.Number, issue.State, issue.Title, issue.Body, issue.User.Login, issue.HTMLURL, issue.CreatedAt, issue.UpdatedAt, issue.ClosedAt, string(assigneesJSON), issue.StateReason)
+
+			i.markIssueCheckRuns(ctx, *projectID, issue.Number)
+
+			i.updateWaitlist(ctx, *projectID, action, issue, env.Assignee)
 		}
 
 		if (e.Event == "pull_request" || e.Event == "pull_request_review") && env.PullRequest != nil {
 			pr := env.PullRequest
+			// New commits (or the PR itself) count as a push; other actions keep the last one.
+			pushed := e.Event == "pull_request" && (action == "opened" || action == "synchronize" || action == "reopened")
 			_, _ = i.Pool.Exec(ctx, `
-INSERT INTO github_pull_requests (project_id, github_pr_id, number, state, title, body, author_login, url, merged, merged_at_github, created_at_github, updated_at_github, closed_at_github, last_seen_at)
-VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now())
+INSERT INTO github_pull_requests (project_id, github_pr_id, number, state, title, body, author_login, url, merged, merged_at_github, created_at_github, updated_at_github, closed_at_github, draft, last_pushed_at, additions, deletions, changed_files, last_seen_at)
+VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, CASE WHEN $15 THEN now() ELSE $11 END, $16, $17, $18, now())
 ON CONFLICT (project_id, github_pr_id) DO UPDATE SET
   number = EXCLUDED.number,
   state = EXCLUDED.state,
@@ -89,8 +105,25 @@ ON CONFLICT (project_id, github_pr_id) DO UPDATE SET
   created_at_github = EXCLUDED.created_at_github,
   updated_at_github = EXCLUDED.updated_at_github,
   closed_at_github = EXCLUDED.closed_at_github,
+  draft = EXCLUDED.draft,
+  last_pushed_at = CASE WHEN $15 THEN now() ELSE COALESCE(github_pull_requests.last_pushed_at, EXCLUDED.last_pushed_at) END,
+  additions = COALESCE(EXCLUDED.additions, github_pull_requests.additions),
+  deletions = COALESCE(EXCLUDED.deletions, github_pull_requests.deletions),
+  changed_files = COALESCE(EXCLUDED.changed_files, github_pull_requests.changed_files),
   last_seen_at = now()
-`, *projectID, pr.ID, pr.Number, pr.State, pr.Title, pr.Body, pr.User.Login, pr.HTMLURL, pr.Merged, pr.MergedAt, pr.CreatedAt, pr.UpdatedAt, pr.ClosedAt)
+`, *projectID, pr.ID, pr.Number, pr.State, pr.Title, pr.Body, pr.User.Login, pr.HTMLURL, pr.Merged, pr.MergedAt, pr.CreatedAt, pr.UpdatedAt, pr.ClosedAt, pr.Draft, pushed, pr.Additions, pr.Deletions, pr.ChangedFiles)
+
+			if e.Event == "pull_request" {
+				i.trackPullRequestCheckRun(ctx, *projectID, action, pr)
+			}
+		}
+
+		if e.Event == "pull_request_review" || e.Event == "pull_request_review_comment" {
+			i.upsertPullRequestReview(ctx, *projectID, e.Event, action, env)
+		}
+
+		if e.Event == "issue_comment" {
+			i.handleIssueComment(ctx, *projectID, action, env)
 		}
 	}
 
@@ -100,6 +133,12 @@ ON CONFLICT (project_id, github_pr_id) DO UPDATE SET
 INSERT INTO sync_jobs (project_id, job_type, status, run_at)
 VALUES ($1::uuid, 'sync_issues', 'pending', now()),
        ($1::uuid, 'sync_prs', 'pending', now())
+`, *projectID)
+	}
+	if projectID != nil && (e.Event == "discussion" || e.Event == "discussion_comment") {
+		_, _ = i.Pool.Exec(ctx, `
+INSERT INTO sync_jobs (project_id, job_type, status, run_at)
+SELECT id, 'sync_discussions', 'pending', now() FROM projects WHERE id = $1::uuid AND track_discussions
 `, *projectID)
 	}
 
@@ -133,6 +172,13 @@ func (i *GitHubWebhookIngestor) handleInstallationEvent(ctx context.Context, e e
 		"installation_id", installationID,
 	)
 
+	// Remember which GitHub App owns the installation so bot actions use that app's identity.
+	if appID := installationPayload.Installation.AppID.String(); appID != "" && action != "deleted" {
+		if err := github.RecordInstallationApp(ctx, i.Pool, installationID, appID); err != nil {
+			slog.Warn("failed to record installation app", "installation_id", installationID, "app_id", appID, "error", err)
+		}
+	}
+
 	if action == "deleted" {
 		// Installation was completely uninstalled - mark all projects from this installation as deleted
 		result, err := i.Pool.Exec(ctx, `
@@ -216,6 +262,10 @@ type ghWebhookEnvelope struct {
 	Repository  *ghRepoPayload       `json:"repository"`
 	Issue       *ghIssuePayload      `json:"issue"`
 	PullRequest *ghPullRequestPayload `json:"pull_request"`
+	Comment     *ghCommentPayload     `json:"comment"`
+	Review      *ghReviewPayload      `json:"review"`
+	// The user added or removed by "assigned"/"unassigned" issue events.
+	Assignee *ghUserPayload `json:"assignee"`
 }
 
 type ghRepoPayload struct {
@@ -227,31 +277,78 @@ type ghUserPayload struct {
 }
 
 type ghIssuePayload struct {
-	ID        int64         `json:"id"`
-	Number    int           `json:"number"`
-	State     string        `json:"state"`
-	Title     string        `json:"title"`
-	Body      string        `json:"body"`
-	HTMLURL   string        `json:"html_url"`
-	User      ghUserPayload `json:"user"`
-	CreatedAt *time.Time    `json:"created_at"`
-	UpdatedAt *time.Time    `json:"updated_at"`
-	ClosedAt  *time.Time    `json:"closed_at"`
+	ID          int64           `json:"id"`
+	Number      int             `json:"number"`
+	State       string          `json:"state"`
+	StateReason *string         `json:"state_reason"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	HTMLURL     string          `json:"html_url"`
+	User        ghUserPayload   `json:"user"`
+	Assignees   []ghUserPayload `json:"assignees"`
+	// Set when the "issue" is a pull request (issue_comment events fire for both).
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+type ghCommentPayload struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"user"`
+	// Set on pull_request_review_comment events.
+	Path                string     `json:"path"`
+	PullRequestReviewID *int64     `json:"pull_request_review_id"`
+	CreatedAt           *time.Time `json:"created_at"`
+	UpdatedAt           *time.Time `json:"updated_at"`
+}
+
+type ghReviewPayload struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	// Webhooks send the state lower-cased ("approved"); the REST API upper-cases it.
+	State string `json:"state"`
+	User  struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	} `json:"user"`
+	SubmittedAt *time.Time `json:"submitted_at"`
 }
 
 type ghPullRequestPayload struct {
-	ID        int64         `json:"id"`
-	Number    int           `json:"number"`
-	State     string        `json:"state"`
-	Title     string        `json:"title"`
-	Body      string        `json:"body"`
-	HTMLURL   string        `json:"html_url"`
-	User      ghUserPayload `json:"user"`
-	Merged    bool          `json:"merged"`
-	MergedAt  *time.Time    `json:"merged_at"`
-	CreatedAt *time.Time    `json:"created_at"`
-	UpdatedAt *time.Time    `json:"updated_at"`
-	ClosedAt  *time.Time    `json:"closed_at"`
+	ID      int64         `json:"id"`
+	Number  int           `json:"number"`
+	State   string        `json:"state"`
+	Title   string        `json:"title"`
+	Body    string        `json:"body"`
+	HTMLURL string        `json:"html_url"`
+	User    ghUserPayload `json:"user"`
+	Head    struct {
+		SHA  string `json:"sha"`
+		Repo *struct {
+			FullName string `json:"full_name"`
+			Owner    struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repo"` // nil when the fork was deleted
+	} `json:"head"`
+	Draft bool `json:"draft"`
+	// Diff stats are only in pull_request events; review events leave them out (nil).
+	Additions    *int       `json:"additions"`
+	Deletions    *int       `json:"deletions"`
+	ChangedFiles *int       `json:"changed_files"`
+	Merged       bool       `json:"merged"`
+	MergedAt     *time.Time `json:"merged_at"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
 }
 
 type ghInstallationPayload struct {
@@ -263,7 +360,8 @@ type ghInstallationPayload struct {
 }
 
 type ghInstallationInfo struct {
-	ID json.Number `json:"id"` // GitHub returns installation ID as a number
+	ID    json.Number `json:"id"` // GitHub returns installation ID as a number
+	AppID json.Number `json:"app_id"`
 }
 
 func nullIfEmpty(s string) any {
@@ -0,0 +1,42 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// RefreshIssue re-fetches one issue from GitHub and stores its state, assignees and labels. Writers whose
+// compare-and-set on github_issues.row_version lost use it to replace their stale view with GitHub's.
+func RefreshIssue(ctx context.Context, pool *pgxpool.Pool, gh github.API, token, fullName string, projectID uuid.UUID, number int) error {
+	it, err := gh.GetIssue(ctx, token, fullName, number)
+	if err != nil {
+		return err
+	}
+	// Same shapes as the sync job stores.
+	assigneesJSON, _ := json.Marshal(it.Assignees)
+	labelsJSON, _ := json.Marshal(it.Labels)
+	var updatedAt, closedAt *time.Time
+	if it.UpdatedAt != nil {
+		if t, err := time.Parse(time.RFC3339, *it.UpdatedAt); err == nil {
+			updatedAt = &t
+		}
+	}
+	if it.ClosedAt != nil {
+		if t, err := time.Parse(time.RFC3339, *it.ClosedAt); err == nil {
+			closedAt = &t
+		}
+	}
+	_, err = pool.Exec(ctx, `
+UPDATE github_issues
+SET state = $3, state_reason = $4, title = $5, assignees = $6::jsonb, labels = $7::jsonb,
+    updated_at_github = COALESCE($8, updated_at_github), closed_at_github = $9, last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, projectID, number, it.State, it.StateReason, it.Title, assigneesJSON, labelsJSON, updatedAt, closedAt)
+	return err
+}
@@ -0,0 +1,52 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+)
+
+// upsertPullRequestReview stores a review or inline review comment from a webhook delivery.
+func (i *GitHubWebhookIngestor) upsertPullRequestReview(ctx context.Context, projectID string, event string, action string, env ghWebhookEnvelope) {
+	if env.PullRequest == nil {
+		return
+	}
+	prNumber := env.PullRequest.Number
+
+	if event == "pull_request_review" && env.Review != nil {
+		r := env.Review
+		if r.User.Login == "" || strings.EqualFold(r.State, "pending") {
+			return
+		}
+		_, _ = i.Pool.Exec(ctx, `
+INSERT INTO github_pr_reviews (project_id, pr_number, github_review_id, reviewer_login, reviewer_github_id, state, body, submitted_at_github, last_seen_at)
+VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, now())
+ON CONFLICT (project_id, github_review_id) DO UPDATE SET
+  state = EXCLUDED.state,
+  body = EXCLUDED.body,
+  submitted_at_github = COALESCE(EXCLUDED.submitted_at_github, github_pr_reviews.submitted_at_github),
+  last_seen_at = now()
+`, projectID, prNumber, r.ID, r.User.Login, r.User.ID, strings.ToUpper(r.State), r.Body, r.SubmittedAt)
+		return
+	}
+
+	if event == "pull_request_review_comment" && env.Comment != nil {
+		com := env.Comment
+		if action == "deleted" {
+			_, _ = i.Pool.Exec(ctx, `
+DELETE FROM github_pr_review_comments WHERE project_id = $1::uuid AND github_comment_id = $2
+`, projectID, com.ID)
+			return
+		}
+		if com.User.Login == "" {
+			return
+		}
+		_, _ = i.Pool.Exec(ctx, `
+INSERT INTO github_pr_review_comments (project_id, pr_number, github_comment_id, github_review_id, reviewer_login, reviewer_github_id, body, path, created_at_github, updated_at_github, last_seen_at)
+VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, now())
+ON CONFLICT (project_id, github_comment_id) DO UPDATE SET
+  body = EXCLUDED.body,
+  updated_at_github = EXCLUDED.updated_at_github,
+  last_seen_at = now()
+`, projectID, prNumber, com.ID, com.PullRequestReviewID, com.User.Login, com.User.ID, com.Body, com.Path, com.CreatedAt, com.UpdatedAt)
+	}
+}
@@ -0,0 +1,135 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/botmessages"
+)
+
+// quickApplyTrigger is the phrase a contributor comments on a GitHub issue to apply without opening the dashboard.
+// GitHub does not deliver webhooks for reactions, so a 👋 reaction cannot be observed; the comment trigger is the supported path.
+const quickApplyTrigger = "/apply"
+
+// isQuickApplyComment reports whether the first line of a comment starts with the trigger phrase.
+func isQuickApplyComment(body string) bool {
+	first := strings.TrimSpace(strings.SplitN(strings.TrimSpace(body), "\n", 2)[0])
+	if len(first) < len(quickApplyTrigger) || !strings.EqualFold(first[:len(quickApplyTrigger)], quickApplyTrigger) {
+		return false
+	}
+	// "/apply" on its own or followed by a message; not "/applying".
+	rest := first[len(quickApplyTrigger):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// handleIssueComment records quick applications from "/apply" comments on projects that opted in.
+// Like the rest of ingest it makes no external calls: the acknowledgement is queued for the bot comment scheduler.
+func (i *GitHubWebhookIngestor) handleIssueComment(ctx context.Context, projectID string, action string, env ghWebhookEnvelope) {
+	if env.Issue == nil || env.Comment == nil || env.Issue.PullRequest != nil {
+		return
+	}
+	issue := env.Issue
+	com := env.Comment
+
+	if action == "deleted" {
+		// Deleting the "/apply" comment on GitHub withdraws the quick application.
+		_, _ = i.Pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'withdrawn', updated_at = now()
+WHERE project_id = $1::uuid AND github_comment_id = $2 AND status = 'pending'
+`, projectID, com.ID)
+		return
+	}
+	if action != "created" || !isQuickApplyComment(com.Body) {
+		return
+	}
+	login := strings.TrimSpace(com.User.Login)
+	if login == "" || strings.EqualFold(com.User.Type, "Bot") || strings.HasSuffix(login, "[bot]") {
+		return
+	}
+
+	var enabled bool
+	if err := i.Pool.QueryRow(ctx, `
+SELECT quick_apply_enabled FROM projects
+WHERE id = $1::uuid AND status = 'verified' AND deleted_at IS NULL
+`, projectID).Scan(&enabled); err != nil || !enabled {
+		return
+	}
+
+	// Same rules as dashboard applications: open, unassigned, and not the issue author.
+	if !strings.EqualFold(strings.TrimSpace(issue.State), "open") || len(issue.Assignees) > 0 {
+		return
+	}
+	if strings.EqualFold(strings.TrimSpace(issue.User.Login), login) {
+		return
+	}
+
+	// Keep the stored comments in step with GitHub so maintainers see the comment before the next sync.
+	stored, _ := json.Marshal(ghStoredComment{
+		ID:        com.ID,
+		Body:      com.Body,
+		User:      ghUserPayload{Login: login},
+		CreatedAt: formatTime(com.CreatedAt),
+		UpdatedAt: formatTime(com.UpdatedAt),
+	})
+	_, _ = i.Pool.Exec(ctx, `
+UPDATE github_issues
+SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
+    comments_count = COALESCE(comments_count, 0) + 1,
+    updated_at_github = COALESCE($4, updated_at_github),
+    last_seen_at = now()
+WHERE project_id = $1::uuid AND number = $2
+  AND NOT COALESCE(comments, '[]'::jsonb) @> jsonb_build_array(jsonb_build_object('id', $5::bigint))
+`, projectID, issue.Number, string(stored), com.UpdatedAt, com.ID)
+
+	message := strings.TrimSpace(strings.TrimSpace(com.Body)[len(quickApplyTrigger):])
+
+	// One open application per contributor per issue; the unique comment index makes redeliveries no-ops.
+	tag, err := i.Pool.Exec(ctx, `
+INSERT INTO issue_applications (project_id, issue_number, user_id, github_login, github_comment_id, source, message)
+SELECT $1::uuid, $2, (SELECT user_id FROM github_accounts WHERE github_user_id = $6 OR LOWER(login) = LOWER($3) LIMIT 1),
+       $3, $4, 'quick_apply', $5
+WHERE NOT EXISTS (
+  SELECT 1 FROM issue_applications
+  WHERE project_id = $1::uuid AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status IN ('pending', 'assigned')
+)
+ON CONFLICT DO NOTHING
+`, projectID, issue.Number, login, com.ID, nullIfEmpty(message), com.User.ID)
+	if err != nil {
+		slog.Warn("quick apply: failed to record application",
+			"project_id", projectID, "issue_number", issue.Number, "github_login", login, "error", err)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		return
+	}
+
+	pid, _ := uuid.Parse(projectID)
+	ack := botmessages.Render(ctx, i.Pool, pid, botmessages.QuickApplyAck, map[string]string{"applicant": login})
+	_, _ = i.Pool.Exec(ctx, `
+INSERT INTO scheduled_bot_comments (project_id, issue_number, body, send_at)
+VALUES ($1::uuid, $2, $3, now())
+`, projectID, issue.Number, ack)
+
+	slog.Info("quick apply: application recorded", "project_id", projectID, "issue_number", issue.Number, "github_login", login)
+}
+
+// ghStoredComment mirrors the shape of comments stored in github_issues.comments.
+type ghStoredComment struct {
+	ID        int64         `json:"id"`
+	Body      string        `json:"body"`
+	User      ghUserPayload `json:"user"`
+	CreatedAt string        `json:"created_at"`
+	UpdatedAt string        `json:"updated_at"`
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,21 @@
+package ingest
+
+import "testing"
+
+func TestIsQuickApplyComment(t *testing.T) {
+	cases := map[string]bool{
+		"/apply":                           true,
+		"  /apply\n":                       true,
+		"/Apply I'd like to work on this":  true,
+		"/apply\nI have done similar work": true,
+		"/applying":                        false,
+		"I will /apply later":              false,
+		"second line\n/apply":              false,
+		"":                                 false,
+	}
+	for body, want := range cases {
+		if got := isQuickApplyComment(body); got != want {
+			t.Errorf("isQuickApplyComment(%q) = %v, want %v", body, got, want)
+		}
+	}
+}
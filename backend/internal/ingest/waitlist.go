@@ -0,0 +1,40 @@
+package ingest
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/waitlist"
+)
+
+// updateWaitlist keeps issue waitlists moving, and issue rewards escrowed, when assignments change on
+// GitHub directly.
+func (i *GitHubWebhookIngestor) updateWaitlist(ctx context.Context, projectID string, action string, issue *ghIssuePayload, assignee *ghUserPayload) {
+	pid, err := uuid.Parse(projectID)
+	if err != nil {
+		return
+	}
+	var rerr error
+	switch {
+	case action == "closed":
+		waitlist.Close(ctx, i.Pool, pid, issue.Number)
+		reason := ""
+		if issue.StateReason != nil {
+			reason = *issue.StateReason
+		}
+		rerr = rewards.Settle(ctx, i.Pool, pid, issue.Number, reason)
+	case action == "unassigned" && issue.State == "open" && len(issue.Assignees) == 0:
+		_, _ = waitlist.NotifyNext(ctx, i.Pool, pid, issue.Number)
+		rerr = rewards.Return(ctx, i.Pool, pid, issue.Number, "unassigned on GitHub")
+	case action == "assigned" && assignee != nil:
+		waitlist.Promote(ctx, i.Pool, pid, issue.Number, assignee.Login)
+		// Assignments made on GitHub can't be refused, so one without budget just isn't escrowed.
+		_, rerr = rewards.Lock(ctx, i.Pool, pid, issue.Number, assignee.Login)
+	}
+	if rerr != nil {
+		slog.Warn("failed to update issue reward escrow", "project_id", projectID, "issue_number", issue.Number, "action", action, "error", rerr)
+	}
+}
@@ -0,0 +1,208 @@
+// Package issuetemplates is the library of issue templates (bug bounty, feature bounty, docs task) that
+// maintainers start Grainlify-drafted issues from. The built-in templates apply everywhere; an ecosystem
+// can override one by key or add its own. Placeholders use the bot template {{name}} syntax.
+package issuetemplates
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/bottemplate"
+)
+
+// Template is one issue template. Points, when set, is the suggested point value for the issue.
+type Template struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Labels      []string `json:"labels"`
+	Points      *int     `json:"points"`
+	// Source is "builtin" or "ecosystem".
+	Source string `json:"source"`
+}
+
+// Automatic variables are filled from the project when instantiating; callers may still override them.
+var Automatic = []string{"project", "repo", "ecosystem"}
+
+const (
+	maxNameLength  = 100
+	maxTitleLength = 256
+	maxBodyLength  = 65536
+	maxLabels      = 20
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+func points(n int) *int { return &n }
+
+// Builtin are the default templates.
+var Builtin = []Template{
+	{
+		Key:         "bug_bounty",
+		Name:        "Bug bounty",
+		Description: "A reproducible bug with a reward for the fix.",
+		Title:       "[Bug] {{summary}}",
+		Body: "## Description\n\n{{description}}\n\n" +
+			"## Steps to reproduce\n\n{{steps}}\n\n" +
+			"## Expected behaviour\n\n{{expected}}\n\n" +
+			"## Acceptance criteria\n\n- [ ] The bug no longer reproduces\n- [ ] A regression test covers it\n\n" +
+			"---\nThis is a Grainlify bounty for {{project}}. Apply on Grainlify to be assigned before starting.",
+		Labels: []string{"grainlify", "bug"},
+		Points: points(3),
+		Source: "builtin",
+	},
+	{
+		Key:         "feature_bounty",
+		Name:        "Feature bounty",
+		Description: "A scoped feature with a reward for implementing it.",
+		Title:       "[Feature] {{summary}}",
+		Body: "## Motivation\n\n{{motivation}}\n\n" +
+			"## Proposed solution\n\n{{proposal}}\n\n" +
+			"## Acceptance criteria\n\n{{acceptance_criteria}}\n\n" +
+			"---\nThis is a Grainlify bounty for {{project}}. Apply on Grainlify to be assigned before starting.",
+		Labels: []string{"grainlify", "enhancement"},
+		Points: points(5),
+		Source: "builtin",
+	},
+	{
+		Key:         "docs_task",
+		Name:        "Documentation task",
+		Description: "Write or improve documentation.",
+		Title:       "[Docs] {{summary}}",
+		Body: "## What needs documenting\n\n{{description}}\n\n" +
+			"## Where\n\n{{location}}\n\n" +
+			"## Acceptance criteria\n\n- [ ] Docs are accurate for the current release\n- [ ] Examples build and run\n\n" +
+			"---\nThis is a Grainlify task for {{project}}. Apply on Grainlify to be assigned before starting.",
+		Labels: []string{"grainlify", "documentation", "difficulty: beginner"},
+		Points: points(1),
+		Source: "builtin",
+	},
+}
+
+// Variables returns the distinct placeholders used by the title and body, in order of first use.
+func (t Template) Variables() []string {
+	return bottemplate.Variables(t.Title + "\n" + t.Body)
+}
+
+// Instantiate renders the title and body with vars (keys are case-insensitive). missing lists variables
+// without a value; they are left in place as {{name}}.
+func (t Template) Instantiate(vars map[string]string) (title, body string, missing []string) {
+	vars = bottemplate.Merge(nil, vars)
+	for _, v := range t.Variables() {
+		if strings.TrimSpace(vars[v]) == "" {
+			delete(vars, v)
+			missing = append(missing, v)
+		}
+	}
+	return strings.TrimSpace(bottemplate.Render(t.Title, vars)), bottemplate.Render(t.Body, vars), missing
+}
+
+// Validate normalises t in place and reports the first problem.
+func Validate(t *Template) error {
+	t.Key = strings.TrimSpace(strings.ToLower(t.Key))
+	t.Name = strings.TrimSpace(t.Name)
+	t.Description = strings.TrimSpace(t.Description)
+	t.Title = strings.TrimSpace(t.Title)
+	if !keyPattern.MatchString(t.Key) {
+		return fmt.Errorf("key must be 1-50 lowercase letters, digits, '-' or '_'")
+	}
+	if t.Name == "" || utf8.RuneCountInString(t.Name) > maxNameLength {
+		return fmt.Errorf("name is required (at most %d characters)", maxNameLength)
+	}
+	if t.Title == "" || utf8.RuneCountInString(t.Title) > maxTitleLength {
+		return fmt.Errorf("title is required (at most %d characters)", maxTitleLength)
+	}
+	if len(t.Body) > maxBodyLength {
+		return fmt.Errorf("body is longer than %d bytes", maxBodyLength)
+	}
+	if t.Points != nil && *t.Points < 0 {
+		return fmt.Errorf("points must not be negative")
+	}
+	labels := make([]string, 0, len(t.Labels))
+	seen := map[string]bool{}
+	for _, l := range t.Labels {
+		l = strings.TrimSpace(l)
+		if l == "" || len(l) > 50 {
+			return fmt.Errorf("labels must be 1-50 characters")
+		}
+		if !seen[strings.ToLower(l)] {
+			seen[strings.ToLower(l)] = true
+			labels = append(labels, l)
+		}
+	}
+	if len(labels) > maxLabels {
+		return fmt.Errorf("at most %d labels", maxLabels)
+	}
+	t.Labels = labels
+	return nil
+}
+
+// ForEcosystem returns the templates available in an ecosystem: the built-ins, replaced by ecosystem
+// templates with the same key, followed by the ecosystem's own templates by name. ecosystemID may be nil.
+func ForEcosystem(ctx context.Context, pool *pgxpool.Pool, ecosystemID *uuid.UUID) ([]Template, error) {
+	custom := map[string]Template{}
+	if ecosystemID != nil {
+		rows, err := pool.Query(ctx, `
+SELECT key, name, description, title, body, labels, points
+FROM issue_templates
+WHERE ecosystem_id = $1
+`, *ecosystemID)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			t := Template{Source: "ecosystem"}
+			if err := rows.Scan(&t.Key, &t.Name, &t.Description, &t.Title, &t.Body, &t.Labels, &t.Points); err != nil {
+				return nil, err
+			}
+			custom[t.Key] = t
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return merge(custom), nil
+}
+
+func merge(custom map[string]Template) []Template {
+	out := make([]Template, 0, len(Builtin)+len(custom))
+	for _, b := range Builtin {
+		if t, ok := custom[b.Key]; ok {
+			out = append(out, t)
+			delete(custom, b.Key)
+			continue
+		}
+		out = append(out, b)
+	}
+	rest := make([]Template, 0, len(custom))
+	for _, t := range custom {
+		rest = append(rest, t)
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i].Name != rest[j].Name {
+			return rest[i].Name < rest[j].Name
+		}
+		return rest[i].Key < rest[j].Key
+	})
+	return append(out, rest...)
+}
+
+// Find returns the template with key from list.
+func Find(list []Template, key string) (Template, bool) {
+	for _, t := range list {
+		if t.Key == key {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
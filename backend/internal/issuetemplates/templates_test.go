@@ -0,0 +1,64 @@
+package issuetemplates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInstantiate(t *testing.T) {
+	tpl, ok := Find(Builtin, "bug_bounty")
+	if !ok {
+		t.Fatal("bug_bounty missing")
+	}
+	title, body, missing := tpl.Instantiate(map[string]string{
+		"Summary":     "Crash on empty input",
+		"description": "The parser panics.",
+		"project":     "acme/widgets",
+		"steps":       "  ",
+	})
+	if title != "[Bug] Crash on empty input" {
+		t.Errorf("title = %q", title)
+	}
+	if !strings.Contains(body, "The parser panics.") || !strings.Contains(body, "bounty for acme/widgets") {
+		t.Errorf("body = %q", body)
+	}
+	if !strings.Contains(body, "{{steps}}") {
+		t.Errorf("blank variable should stay visible: %q", body)
+	}
+	if want := []string{"steps", "expected"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestValidateAndMerge(t *testing.T) {
+	tpl := Template{Key: " Docs_Task ", Name: " Docs ", Title: "Docs: {{summary}}", Labels: []string{"docs", " Docs ", "grainlify"}}
+	if err := Validate(&tpl); err != nil {
+		t.Fatal(err)
+	}
+	if tpl.Key != "docs_task" || tpl.Name != "Docs" || !reflect.DeepEqual(tpl.Labels, []string{"docs", "grainlify"}) {
+		t.Errorf("normalised = %+v", tpl)
+	}
+	for name, bad := range map[string]Template{
+		"key":    {Key: "has space", Name: "x", Title: "x"},
+		"name":   {Key: "k", Title: "x"},
+		"title":  {Key: "k", Name: "x"},
+		"points": {Key: "k", Name: "x", Title: "x", Points: points(-1)},
+	} {
+		if err := Validate(&bad); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	tpl.Source = "ecosystem"
+	custom := Template{Key: "audit", Name: "Audit", Title: "Audit", Source: "ecosystem"}
+	got := merge(map[string]Template{tpl.Key: tpl, custom.Key: custom})
+	keys := make([]string, len(got))
+	for i, g := range got {
+		keys[i] = g.Key + ":" + g.Source
+	}
+	want := []string{"bug_bounty:builtin", "feature_bounty:builtin", "docs_task:ecosystem", "audit:ecosystem"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("merge = %v, want %v", keys, want)
+	}
+}
@@ -0,0 +1,148 @@
+// Package jobs starts Grainlify's background jobs: GitHub sync, scheduled bot comments, check runs,
+// notifications and reports, exports and payouts. They run in exactly one kind of process: cmd/worker when
+// NATS is configured, else the API itself (a single-process deployment, or local development).
+package jobs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jagadeesh/grainlify/backend/internal/botcomments"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/completions"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/digests"
+	"github.com/jagadeesh/grainlify/backend/internal/exports"
+	"github.com/jagadeesh/grainlify/backend/internal/freshness"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/handlers"
+	"github.com/jagadeesh/grainlify/backend/internal/mailer"
+	"github.com/jagadeesh/grainlify/backend/internal/overdue"
+	"github.com/jagadeesh/grainlify/backend/internal/pause"
+	"github.com/jagadeesh/grainlify/backend/internal/payouts"
+	"github.com/jagadeesh/grainlify/backend/internal/promotions"
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+	"github.com/jagadeesh/grainlify/backend/internal/reminders"
+	"github.com/jagadeesh/grainlify/backend/internal/responsemetrics"
+	"github.com/jagadeesh/grainlify/backend/internal/retention"
+	"github.com/jagadeesh/grainlify/backend/internal/staleness"
+	"github.com/jagadeesh/grainlify/backend/internal/syncjobs"
+)
+
+// Start runs every background job in its own goroutine until ctx is done.
+func Start(ctx context.Context, cfg config.Config, database *db.DB) {
+	worker := syncjobs.New(cfg, database.Pool)
+	go func() {
+		slog.Info("background worker started")
+		_ = worker.Run(ctx)
+	}()
+
+	scheduler := botcomments.New(cfg, database.Pool)
+	go func() {
+		slog.Info("bot comment scheduler started")
+		_ = scheduler.Run(ctx)
+	}()
+
+	checkRuns := checkruns.New(cfg, database.Pool)
+	go func() {
+		slog.Info("check run publisher started")
+		_ = checkRuns.Run(ctx)
+	}()
+
+	responseMetrics := responsemetrics.New(database.Pool)
+	go func() {
+		slog.Info("response metrics job started")
+		_ = responseMetrics.Run(ctx)
+	}()
+
+	staleProjects := staleness.New(cfg, database.Pool)
+	go func() {
+		slog.Info("stale project job started", "weeks", cfg.StaleProjectWeeks, "delist", cfg.StaleProjectDelist)
+		_ = staleProjects.Run(ctx)
+	}()
+
+	issueFreshness := freshness.New(cfg, database.Pool)
+	go func() {
+		slog.Info("issue freshness job started", "hours", cfg.IssueFreshnessHours)
+		_ = issueFreshness.Run(ctx)
+	}()
+
+	overdueAssignments := overdue.New(cfg, database.Pool)
+	go func() {
+		slog.Info("overdue assignment job started", "enabled", cfg.AutoUnassignOverdue, "grace_hours", cfg.OverdueGraceHours)
+		_ = overdueAssignments.Run(ctx)
+	}()
+
+	eventRetention := retention.New(cfg, database.Pool)
+	go func() {
+		slog.Info("event retention job started", "days", cfg.EventRetentionDays, "archive", cfg.EventArchiveURL != "")
+		if err := eventRetention.Run(ctx); err != nil {
+			slog.Error("event retention job stopped", "error", err)
+		}
+	}()
+
+	exportJobs := exports.NewJob(database.Pool)
+	go func() {
+		slog.Info("export job started")
+		_ = exportJobs.Run(ctx)
+	}()
+
+	reportMailer, err := mailer.New(cfg.SMTPURL, cfg.MailFrom)
+	if err != nil {
+		slog.Error("invalid mail settings, ecosystem reports will only be logged", "error", err)
+		reportMailer, _ = mailer.New("", "")
+	}
+	ecosystemReports := digests.New(cfg, database.Pool, reportMailer)
+	go func() {
+		slog.Info("ecosystem report job started", "smtp", cfg.SMTPURL != "")
+		_ = ecosystemReports.Run(ctx)
+	}()
+
+	userReminders := reminders.New(cfg, database.Pool, reportMailer)
+	go func() {
+		slog.Info("reminder job started", "interval", reminders.Interval)
+		_ = userReminders.Run(ctx)
+	}()
+
+	if executor := payouts.FromSettings(cfg.PayoutProviderURL, cfg.PayoutProviderToken); executor != nil {
+		payoutExecution := payouts.New(database.Pool, executor)
+		go func() {
+			slog.Info("payout execution job started", "executor", executor.Name())
+			_ = payoutExecution.Run(ctx)
+		}()
+	}
+
+	issueRecommendations := recommend.New(database.Pool)
+	go func() {
+		slog.Info("issue recommendation job started", "interval", recommend.Interval)
+		_ = issueRecommendations.Run(ctx)
+	}()
+
+	issueCompletions := completions.New(database.Pool)
+	go func() {
+		slog.Info("issue completion analytics job started", "interval", completions.Interval)
+		_ = issueCompletions.Run(ctx)
+	}()
+
+	pausedContributors := pause.New(database.Pool)
+	go func() {
+		slog.Info("contributor pause job started")
+		_ = pausedContributors.Run(ctx)
+	}()
+
+	issuePromotions := promotions.New(cfg, database.Pool)
+	go func() {
+		slog.Info("issue promotion job started", "interval", promotions.Interval)
+		_ = issuePromotions.Run(ctx)
+	}()
+
+	// Undoable assign/reject actions, and the ones queued for retry after a GitHub failure.
+	deferredActions := handlers.NewDeferredIssueActions(cfg, database, github.NewClient(), github.NewApps(cfg, database.Pool))
+	go func() {
+		slog.Info("deferred issue actions started", "undo_window", handlers.UndoWindow)
+		_ = deferredActions.Run(ctx)
+	}()
+
+	// GitHub App cleanup is handled via webhooks (installation.deleted events); no periodic polling.
+}
@@ -0,0 +1,131 @@
+// Package kyc verifies contributors' identity before large payouts. A Provider runs the verification: the
+// contributor completes a session at the provider, which reports the outcome to Grainlify's webhook, and
+// Record keeps the latest status on the user (with every event in kyc_events). Payouts above their
+// currency's KYC threshold can't be approved until the recipient is verified.
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// Statuses, as stored in users.kyc_status.
+const (
+	StatusNotStarted = "not_started"
+	StatusPending    = "pending"
+	StatusInReview   = "in_review"
+	StatusVerified   = "verified"
+	StatusRejected   = "rejected"
+	StatusExpired    = "expired"
+)
+
+// Session is a verification session the user completes at URL.
+type Session struct {
+	ID  string
+	URL string
+}
+
+// Event is a provider's report on a session.
+type Event struct {
+	SessionID string
+	Status    string // one of the statuses above
+	Data      map[string]any
+}
+
+// ErrInvalidSignature is returned by ParseWebhook for deliveries that don't authenticate.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider verifies identities.
+type Provider interface {
+	Name() string
+	// StartSession opens a session for userID; the outcome is delivered to callbackURL.
+	StartSession(ctx context.Context, userID uuid.UUID, callbackURL string) (Session, error)
+	// ParseWebhook authenticates a webhook delivery and returns its event.
+	ParseWebhook(header http.Header, body []byte) (Event, error)
+}
+
+// Normalize maps a provider's status word to one of the statuses above, or "" if it is unknown.
+func Normalize(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "not_started", "created", "new":
+		return StatusNotStarted
+	case "pending", "in_progress", "started", "submitted":
+		return StatusPending
+	case "in_review", "review", "manual_review":
+		return StatusInReview
+	case "verified", "approved", "completed", "passed":
+		return StatusVerified
+	case "rejected", "declined", "failed", "denied":
+		return StatusRejected
+	case "expired", "abandoned", "cancelled", "canceled":
+		return StatusExpired
+	}
+	return ""
+}
+
+// Required reports whether a payout of amount needs a verified recipient, given its currency's threshold
+// (empty for none). Amounts above the threshold need one.
+func Required(amount, threshold string) bool {
+	if threshold == "" {
+		return false
+	}
+	a, ok1 := new(big.Int).SetString(amount, 10)
+	t, ok2 := new(big.Int).SetString(threshold, 10)
+	// Amounts that don't parse are treated as large.
+	return !ok1 || !ok2 || a.Cmp(t) > 0
+}
+
+// Record applies ev from provider to the user whose session it is and logs it. It returns the user, or
+// uuid.Nil when no user has the session. The user is notified when the status changes.
+func Record(ctx context.Context, pool *pgxpool.Pool, provider string, ev Event) (uuid.UUID, error) {
+	data, _ := json.Marshal(ev.Data)
+	if ev.Data == nil {
+		data = []byte("{}")
+	}
+	var userID uuid.UUID
+	var previous *string
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+SELECT id, kyc_status FROM users WHERE kyc_session_id = $1 FOR UPDATE
+`, ev.SessionID).Scan(&userID, &previous)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+UPDATE users
+SET kyc_status = $2,
+    kyc_data = COALESCE(kyc_data, '{}'::jsonb) || jsonb_build_object('decision', $3::jsonb),
+    kyc_verified_at = CASE WHEN $2 = 'verified' THEN now() ELSE kyc_verified_at END,
+    kyc_provider = $4,
+    kyc_updated_at = now(),
+    updated_at = now()
+WHERE id = $1
+`, userID, ev.Status, data, provider); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+INSERT INTO kyc_events (user_id, provider, session_id, status, data) VALUES ($1, $2, $3, $4, $5::jsonb)
+`, userID, provider, ev.SessionID, ev.Status, data)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if previous == nil || *previous != ev.Status {
+		_ = notify.Store(ctx, pool, userID, "kyc_status_changed", map[string]any{"status": ev.Status})
+	}
+	return userID, nil
+}
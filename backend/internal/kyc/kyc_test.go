@@ -0,0 +1,96 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"Approved":    StatusVerified,
+		"declined":    StatusRejected,
+		"in_progress": StatusPending,
+		"in_review":   StatusInReview,
+		"abandoned":   StatusExpired,
+		"created":     StatusNotStarted,
+		"bogus":       "",
+	} {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRequired(t *testing.T) {
+	for _, tc := range []struct {
+		amount, threshold string
+		want              bool
+	}{
+		{"500", "", false},
+		{"500", "1000", false},
+		{"1000", "1000", false},
+		{"1001", "1000", true},
+		{"123456789012345678901234567890", "1000", true},
+		{"0", "0", false},
+		{"x", "1000", true},
+	} {
+		if got := Required(tc.amount, tc.threshold); got != tc.want {
+			t.Errorf("Required(%s, %q) = %v, want %v", tc.amount, tc.threshold, got, tc.want)
+		}
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	userID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sessions" || r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in["reference"] != userID.String() || in["callback_url"] != "https://api.example.com/webhooks/kyc" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"bad request"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"session_id":"s-1","url":"https://kyc.example.com/s-1"}`))
+	}))
+	defer srv.Close()
+
+	p := &HTTPProvider{URL: srv.URL + "/", Token: "tok", Secret: "shh"}
+	s, err := p.StartSession(context.Background(), userID, "https://api.example.com/webhooks/kyc")
+	if err != nil || s.ID != "s-1" || s.URL != "https://kyc.example.com/s-1" {
+		t.Fatalf("StartSession = %+v, %v", s, err)
+	}
+	if _, err := p.StartSession(context.Background(), userID, "https://elsewhere"); err == nil {
+		t.Error("StartSession ignored a 400")
+	}
+
+	body := []byte(`{"session_id":"s-1","status":"approved","data":{"document":"passport"}}`)
+	h := http.Header{}
+	h.Set(SignatureHeader, Sign("shh", body))
+	ev, err := p.ParseWebhook(h, body)
+	if err != nil || ev.SessionID != "s-1" || ev.Status != StatusVerified || ev.Data["document"] != "passport" {
+		t.Fatalf("ParseWebhook = %+v, %v", ev, err)
+	}
+
+	h.Set(SignatureHeader, Sign("wrong", body))
+	if _, err := p.ParseWebhook(h, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong secret: err = %v", err)
+	}
+	unknown := []byte(`{"session_id":"s-1","status":"weird"}`)
+	h.Set(SignatureHeader, Sign("shh", unknown))
+	if _, err := p.ParseWebhook(h, unknown); err == nil {
+		t.Error("unknown status accepted")
+	}
+	if _, err := (&HTTPProvider{URL: srv.URL}).ParseWebhook(h, unknown); !errors.Is(err, ErrInvalidSignature) {
+		t.Error("webhook accepted without a secret configured")
+	}
+}
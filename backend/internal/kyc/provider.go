@@ -0,0 +1,111 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignatureHeader carries a webhook delivery's signature: "sha256=" and the hex HMAC-SHA256 of the body
+// with the webhook secret.
+const SignatureHeader = "X-KYC-Signature"
+
+// HTTPProvider verifies identities through a KYC provider's API. StartSession POSTs
+//
+//	{"reference": user id, "callback_url"}
+//
+// to URL + "/sessions" (with a bearer Token if set) and expects {"session_id","url"}. The provider then
+// POSTs {"session_id","status","data"} to the callback URL, signed with Secret (see SignatureHeader).
+type HTTPProvider struct {
+	URL    string
+	Token  string
+	Secret string
+	HTTP   *http.Client
+}
+
+func (p *HTTPProvider) Name() string { return "provider" }
+
+func (p *HTTPProvider) StartSession(ctx context.Context, userID uuid.UUID, callbackURL string) (Session, error) {
+	body, _ := json.Marshal(map[string]string{"reference": userID.String(), "callback_url": callbackURL})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.URL, "/")+"/sessions", bytes.NewReader(body))
+	if err != nil {
+		return Session{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	client := p.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Session{}, err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		SessionID string `json:"session_id"`
+		URL       string `json:"url"`
+		Error     string `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	decodeErr := json.Unmarshal(raw, &out)
+	if resp.StatusCode/100 != 2 {
+		if out.Error != "" {
+			return Session{}, fmt.Errorf("kyc provider returned %s: %s", resp.Status, out.Error)
+		}
+		return Session{}, fmt.Errorf("kyc provider returned %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return Session{}, fmt.Errorf("decode kyc provider response: %w", decodeErr)
+	}
+	if out.SessionID == "" || out.URL == "" {
+		return Session{}, fmt.Errorf("kyc provider returned a session without an id or url")
+	}
+	return Session{ID: out.SessionID, URL: out.URL}, nil
+}
+
+func (p *HTTPProvider) ParseWebhook(header http.Header, body []byte) (Event, error) {
+	if p.Secret == "" || !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(p.Secret, body))) {
+		return Event{}, ErrInvalidSignature
+	}
+	var in struct {
+		SessionID string         `json:"session_id"`
+		Status    string         `json:"status"`
+		Data      map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil {
+		return Event{}, err
+	}
+	status := Normalize(in.Status)
+	if in.SessionID == "" || status == "" {
+		return Event{}, fmt.Errorf("kyc webhook without a session id or a known status")
+	}
+	return Event{SessionID: in.SessionID, Status: status, Data: in.Data}, nil
+}
+
+// Sign returns the SignatureHeader value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// FromSettings returns the provider the settings configure, or nil when none is.
+func FromSettings(providerURL, token, webhookSecret string) Provider {
+	if providerURL == "" {
+		return nil
+	}
+	return &HTTPProvider{URL: providerURL, Token: token, Secret: webhookSecret}
+}
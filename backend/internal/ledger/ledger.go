@@ -0,0 +1,177 @@
+// Package ledger is the double-entry ledger behind contributor points and reward money. Every movement is
+// a transaction of entries that sum to zero per asset: what one account is debited another is credited.
+// Entries are append-only and account balances are kept in step with them by the database, so any
+// balance can be audited back to the transactions that made it.
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// AssetPoints is the asset contributor points are kept in; every other asset is a currency code.
+const AssetPoints = "POINTS"
+
+// Account kinds.
+const (
+	// AccountExternal is the outside world: deposits come from it and payouts go to it. It is the only
+	// account that may go negative.
+	AccountExternal    = "external"
+	AccountBudget      = "ecosystem_budget"
+	AccountEscrow      = "ecosystem_escrow"
+	AccountContributor = "contributor"
+)
+
+// Transaction kinds.
+const (
+	KindDeposit     = "deposit"
+	KindWithdraw    = "withdraw"
+	KindLock        = "lock"
+	KindRelease     = "release"
+	KindReturn      = "return"
+	KindPayout      = "payout"
+	KindPointsAward = "points_award"
+)
+
+var (
+	// ErrUnbalanced is returned for a transaction whose entries don't sum to zero per asset.
+	ErrUnbalanced = errors.New("ledger transaction does not balance")
+	// ErrInsufficientFunds is returned when a transaction would take an account below zero.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+// Account identifies a ledger account. Accounts are created the first time they are posted to.
+type Account struct {
+	Kind  string
+	Owner string // ecosystem ID, lowercased GitHub login, or "" for external
+	Asset string
+}
+
+func External(asset string) Account { return Account{Kind: AccountExternal, Asset: asset} }
+
+func Budget(ecosystemID uuid.UUID, asset string) Account {
+	return Account{Kind: AccountBudget, Owner: ecosystemID.String(), Asset: asset}
+}
+
+func Escrow(ecosystemID uuid.UUID, asset string) Account {
+	return Account{Kind: AccountEscrow, Owner: ecosystemID.String(), Asset: asset}
+}
+
+func Contributor(login, asset string) Account {
+	return Account{Kind: AccountContributor, Owner: strings.ToLower(strings.TrimSpace(login)), Asset: asset}
+}
+
+// Entry is one side of a transaction. Amount is the change to the account's balance: positive credits
+// it, negative debits it.
+type Entry struct {
+	Account Account
+	Amount  *big.Int
+}
+
+// Transfer returns the entries moving amount from one account to another.
+func Transfer(from, to Account, amount *big.Int) []Entry {
+	return []Entry{{Account: from, Amount: new(big.Int).Neg(amount)}, {Account: to, Amount: new(big.Int).Set(amount)}}
+}
+
+// Transaction is a balanced set of entries and what they were for.
+type Transaction struct {
+	Kind string
+	// Key makes posting idempotent: a transaction whose key was already posted is skipped.
+	Key         string
+	EcosystemID *uuid.UUID
+	ProjectID   *uuid.UUID
+	IssueNumber *int
+	Memo        string
+	CreatedBy   *uuid.UUID
+	Entries     []Entry
+}
+
+// Validate checks that t has entries, none of them zero, summing to zero per asset.
+func (t Transaction) Validate() error {
+	if t.Kind == "" {
+		return fmt.Errorf("ledger transaction without a kind")
+	}
+	if len(t.Entries) < 2 {
+		return ErrUnbalanced
+	}
+	sums := map[string]*big.Int{}
+	for _, e := range t.Entries {
+		if e.Account.Kind == "" || e.Account.Asset == "" {
+			return fmt.Errorf("ledger entry without an account")
+		}
+		if e.Amount == nil || e.Amount.Sign() == 0 {
+			return fmt.Errorf("ledger entry without an amount")
+		}
+		if sums[e.Account.Asset] == nil {
+			sums[e.Account.Asset] = new(big.Int)
+		}
+		sums[e.Account.Asset].Add(sums[e.Account.Asset], e.Amount)
+	}
+	for _, s := range sums {
+		if s.Sign() != 0 {
+			return ErrUnbalanced
+		}
+	}
+	return nil
+}
+
+// Post records t in tx. It reports false, without error, when t's key was already posted. A transaction
+// that would overdraw an account fails with ErrInsufficientFunds, which aborts tx.
+func Post(ctx context.Context, tx pgx.Tx, t Transaction) (bool, error) {
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `
+INSERT INTO ledger_transactions (kind, idempotency_key, ecosystem_id, project_id, issue_number, memo, created_by)
+VALUES ($1, NULLIF($2, ''), $3, $4, $5, NULLIF($6, ''), $7)
+ON CONFLICT (idempotency_key) DO NOTHING
+RETURNING id
+`, t.Kind, t.Key, t.EcosystemID, t.ProjectID, t.IssueNumber, t.Memo, t.CreatedBy).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Entries go in account order, so concurrent transactions lock accounts in the same order.
+	entries := append([]Entry(nil), t.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Account.Kind != b.Account.Kind {
+			return a.Account.Kind < b.Account.Kind
+		}
+		if a.Account.Owner != b.Account.Owner {
+			return a.Account.Owner < b.Account.Owner
+		}
+		return a.Account.Asset < b.Account.Asset
+	})
+	for _, e := range entries {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO ledger_accounts (kind, owner, asset) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+`, e.Account.Kind, e.Account.Owner, e.Account.Asset); err != nil {
+			return false, err
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO ledger_entries (transaction_id, account_id, amount)
+SELECT $1, id, $5::numeric FROM ledger_accounts WHERE kind = $2 AND owner = $3 AND asset = $4
+`, id, e.Account.Kind, e.Account.Owner, e.Account.Asset, e.Amount.String())
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			return false, ErrInsufficientFunds
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
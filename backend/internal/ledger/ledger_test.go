@@ -0,0 +1,89 @@
+package ledger
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidate(t *testing.T) {
+	eco := uuid.New()
+	ok := Transaction{Kind: KindLock, Entries: Transfer(Budget(eco, "USDC"), Escrow(eco, "USDC"), big.NewInt(500))}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if ok.Entries[0].Amount.Int64() != -500 || ok.Entries[1].Amount.Int64() != 500 {
+		t.Errorf("transfer entries = %v, %v", ok.Entries[0].Amount, ok.Entries[1].Amount)
+	}
+
+	split := Transaction{Kind: KindRelease, Entries: []Entry{
+		{Account: Escrow(eco, "USDC"), Amount: big.NewInt(-300)},
+		{Account: Contributor("Alice", "USDC"), Amount: big.NewInt(200)},
+		{Account: Contributor("bob", "USDC"), Amount: big.NewInt(100)},
+	}}
+	if err := split.Validate(); err != nil {
+		t.Errorf("split: %v", err)
+	}
+
+	for name, tx := range map[string]Transaction{
+		"one entry": {Kind: KindDeposit, Entries: []Entry{{Account: External("USDC"), Amount: big.NewInt(1)}}},
+		"unbalanced": {Kind: KindDeposit, Entries: []Entry{
+			{Account: External("USDC"), Amount: big.NewInt(-10)},
+			{Account: Budget(eco, "USDC"), Amount: big.NewInt(9)},
+		}},
+		// Balanced in total but not per asset.
+		"mixed assets": {Kind: KindDeposit, Entries: []Entry{
+			{Account: External("USDC"), Amount: big.NewInt(-10)},
+			{Account: Budget(eco, "XLM"), Amount: big.NewInt(10)},
+		}},
+	} {
+		if err := tx.Validate(); !errors.Is(err, ErrUnbalanced) {
+			t.Errorf("%s: err = %v, want ErrUnbalanced", name, err)
+		}
+	}
+	zero := Transaction{Kind: KindDeposit, Entries: Transfer(External("USDC"), Budget(eco, "USDC"), new(big.Int))}
+	if err := zero.Validate(); err == nil {
+		t.Error("zero amount accepted")
+	}
+	if err := (Transaction{Entries: ok.Entries}).Validate(); err == nil {
+		t.Error("missing kind accepted")
+	}
+
+	if got := Contributor(" Alice ", AssetPoints).Owner; got != "alice" {
+		t.Errorf("contributor owner = %q", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	p := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	current := []Award{
+		{ProjectID: p, IssueNumber: 1, Login: "alice", Points: 5},
+		{ProjectID: p, IssueNumber: 2, Login: "bob", Points: 3},
+		{ProjectID: p, IssueNumber: 3, Login: "carol", Points: 8},
+	}
+	desired := []Award{
+		{ProjectID: p, IssueNumber: 1, Login: "Alice", Points: 5},  // unchanged
+		{ProjectID: p, IssueNumber: 2, Login: "dave", Points: 3},   // reassigned
+		{ProjectID: p, IssueNumber: 3, Login: "carol", Points: 13}, // points raised
+		{ProjectID: p, IssueNumber: 4, Login: "erin", Points: 2},   // new
+	}
+	want := []Award{
+		{ProjectID: p, IssueNumber: 2, Login: "bob", Points: -3},
+		{ProjectID: p, IssueNumber: 2, Login: "dave", Points: 3},
+		{ProjectID: p, IssueNumber: 3, Login: "carol", Points: 5},
+		{ProjectID: p, IssueNumber: 4, Login: "erin", Points: 2},
+	}
+	if got := Diff(current, desired); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %+v\nwant %+v", got, want)
+	}
+	if got := Diff(desired, desired); len(got) != 0 {
+		t.Errorf("Diff of equal sets = %+v", got)
+	}
+	// Everything withdrawn.
+	if got := Diff(current, nil); len(got) != 3 || got[0].Points != -5 {
+		t.Errorf("Diff to nothing = %+v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Award is the points a contributor earned for an issue.
+type Award struct {
+	ProjectID   uuid.UUID
+	IssueNumber int
+	Login       string
+	Points      int64
+}
+
+type awardKey struct {
+	projectID uuid.UUID
+	number    int
+	login     string
+}
+
+// Diff returns the adjustments that take the points credited so far (current) to what was earned
+// (desired): an award per contributor and issue whose Points is the difference, negative to take points
+// back. Awards missing from desired are taken back in full.
+func Diff(current, desired []Award) []Award {
+	sum := func(list []Award) map[awardKey]int64 {
+		m := map[awardKey]int64{}
+		for _, a := range list {
+			m[awardKey{a.ProjectID, a.IssueNumber, strings.ToLower(a.Login)}] += a.Points
+		}
+		return m
+	}
+	have, want := sum(current), sum(desired)
+	for k := range have {
+		if _, ok := want[k]; !ok {
+			want[k] = 0
+		}
+	}
+	var out []Award
+	for k, w := range want {
+		if d := w - have[k]; d != 0 {
+			out = append(out, Award{ProjectID: k.projectID, IssueNumber: k.number, Login: k.login, Points: d})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID.String() < b.ProjectID.String()
+		}
+		if a.IssueNumber != b.IssueNumber {
+			return a.IssueNumber < b.IssueNumber
+		}
+		return a.Login < b.Login
+	})
+	return out
+}
+
+// SyncPoints makes contributors' point balances match desired, the points earned for every issue
+// completed since the given time, by posting an award (or a correction) for each difference. Awards for
+// issues closed before since are history and stay as they are. Concurrent syncs wait for each other. It
+// returns how many transactions it posted.
+func SyncPoints(ctx context.Context, pool *pgxpool.Pool, desired []Award, since time.Time) (int, error) {
+	wanted := map[awardKey]bool{}
+	for _, a := range desired {
+		wanted[awardKey{a.ProjectID, a.IssueNumber, ""}] = true
+	}
+	posted := 0
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		posted = 0
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('ledger_points'))`); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, `
+SELECT t.project_id, t.issue_number, a.owner, SUM(e.amount)::bigint, MAX(gi.closed_at_github)
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+JOIN ledger_accounts a ON a.id = e.account_id
+LEFT JOIN github_issues gi ON gi.project_id = t.project_id AND gi.number = t.issue_number
+WHERE t.kind = $1 AND a.kind = $2 AND a.asset = $3
+GROUP BY t.project_id, t.issue_number, a.owner
+HAVING SUM(e.amount) <> 0
+`, KindPointsAward, AccountContributor, AssetPoints)
+		if err != nil {
+			return err
+		}
+		var current []Award
+		for rows.Next() {
+			var a Award
+			var closedAt *time.Time
+			if err := rows.Scan(&a.ProjectID, &a.IssueNumber, &a.Login, &a.Points, &closedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			if !wanted[awardKey{a.ProjectID, a.IssueNumber, ""}] && closedAt != nil && closedAt.Before(since) {
+				continue
+			}
+			current = append(current, a)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, a := range Diff(current, desired) {
+			from, to, amount := External(AssetPoints), Contributor(a.Login, AssetPoints), big.NewInt(a.Points)
+			memo := "completed"
+			if a.Points < 0 {
+				from, to, amount = to, from, amount.Neg(amount)
+				memo = "completion withdrawn or points lowered"
+			}
+			projectID, number := a.ProjectID, a.IssueNumber
+			if _, err := Post(ctx, tx, Transaction{
+				Kind: KindPointsAward, ProjectID: &projectID, IssueNumber: &number, Memo: memo,
+				Entries: Transfer(from, to, amount),
+			}); err != nil {
+				return fmt.Errorf("points for %s#%d to %s: %w", projectID, number, a.Login, err)
+			}
+			posted++
+		}
+		return nil
+	})
+	return posted, err
+}
@@ -0,0 +1,128 @@
+// Package locale normalises language tags and picks the best of a set of available locales for a request's
+// Accept-Language header. Tags are the common subset of BCP 47: a language, optionally a script and a
+// region ("pt", "pt-BR", "zh-Hant-TW").
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Normalize returns tag in canonical case ("pt-br" becomes "pt-BR", "zh_hant" becomes "zh-Hant") and
+// whether it is a well-formed tag.
+func Normalize(tag string) (string, bool) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if len(parts) > 3 || !letters(parts[0], 2, 3) {
+		return "", false
+	}
+	out := []string{strings.ToLower(parts[0])}
+	rest := parts[1:]
+	if len(rest) > 0 && letters(rest[0], 4, 4) {
+		out = append(out, strings.ToUpper(rest[0][:1])+strings.ToLower(rest[0][1:]))
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		if !letters(rest[0], 2, 2) && !digits(rest[0], 3) {
+			return "", false
+		}
+		out = append(out, strings.ToUpper(rest[0]))
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", false
+	}
+	return strings.Join(out, "-"), true
+}
+
+// Base returns the language of a normalised tag ("pt" for "pt-BR").
+func Base(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return base
+}
+
+// Negotiate returns the available locale that best matches an Accept-Language header, or "" when none
+// does (or only "*" does), meaning the default content. Preferences are taken in q order; each matches an
+// exact locale first, then another locale of the same language, the bare language preferred ("pt" for a
+// "pt-PT" request when only "pt" and "pt-BR" exist). Available locales must be normalised.
+func Negotiate(header string, available []string) string {
+	if len(available) == 0 {
+		return ""
+	}
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q <= 0 {
+			continue
+		}
+		if tag == "*" {
+			prefs = append(prefs, pref{tag: "*", q: q})
+			continue
+		}
+		if t, ok := Normalize(tag); ok {
+			prefs = append(prefs, pref{tag: t, q: q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	for _, p := range prefs {
+		if p.tag == "*" {
+			return ""
+		}
+		for _, a := range available {
+			if a == p.tag {
+				return a
+			}
+		}
+		base, match := Base(p.tag), ""
+		for _, a := range available {
+			if a == base {
+				return a
+			}
+			if match == "" && Base(a) == base {
+				match = a
+			}
+		}
+		if match != "" {
+			return match
+		}
+	}
+	return ""
+}
+
+func letters(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func digits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package locale
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"en":         "en",
+		"PT-br":      "pt-BR",
+		"zh_hant_tw": "zh-Hant-TW",
+		"es-419":     "es-419",
+		" fr ":       "fr",
+	} {
+		if got, ok := Normalize(in); !ok || got != want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "e", "english", "en-", "en-US-x", "en-U5", "de-DE-1996"} {
+		if got, ok := Normalize(in); ok {
+			t.Errorf("Normalize(%q) = %q, want invalid", in, got)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	available := []string{"es", "pt", "pt-BR", "zh-Hant"}
+	for header, want := range map[string]string{
+		"":                           "",
+		"en-US,en;q=0.9":             "",
+		"pt-BR,pt;q=0.8":             "pt-BR",
+		"pt-PT":                      "pt",
+		"es-MX":                      "es",
+		"zh-TW":                      "zh-Hant",
+		"en;q=0.9, es;q=0.5":         "es",
+		"fr;q=0.2, pt-br;q=0.7, es":  "es",
+		"*;q=0.8, es;q=0.5":          "",
+		"es;q=0":                     "",
+		"es;q=abc, pt;q=0.1":         "pt",
+		"de, *;q=0.5, zh-hant;q=0.1": "",
+	} {
+		if got := Negotiate(header, available); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+	if got := Negotiate("es", nil); got != "" {
+		t.Errorf("Negotiate with nothing available = %q", got)
+	}
+}
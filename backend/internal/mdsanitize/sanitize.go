@@ -0,0 +1,144 @@
+// Package mdsanitize cleans user-supplied Markdown before it is posted to GitHub: it neutralises
+// unintended @-mentions, hidden HTML comments, raw HTML and invisible control characters, and
+// closes unbalanced code fences so they can't swallow the text we append after them.
+package mdsanitize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Options control what Sanitize keeps.
+type Options struct {
+	// AllowMentions are logins that may always be @-mentioned (case-insensitive).
+	AllowMentions []string
+	// MaxMentions is how many other distinct logins may be mentioned; later ones are escaped.
+	MaxMentions int
+	// EscapeHTML neutralises raw HTML tags (for contributor text quoted in our comments).
+	EscapeHTML bool
+}
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTag     = regexp.MustCompile(`<([A-Za-z/!?])`)
+	mention     = regexp.MustCompile(`@([A-Za-z0-9][A-Za-z0-9-]{0,38})(/[A-Za-z0-9_.-]+)?`)
+	blankRuns   = regexp.MustCompile(`\n{3,}`)
+)
+
+// Sanitize returns s cleaned according to opt.
+func Sanitize(s string, opt Options) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || isBidiControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	// Hidden comments could smuggle our own markers or invisible content.
+	s = htmlComment.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "<!--", "&lt;!--")
+
+	allowed := map[string]bool{}
+	for _, l := range opt.AllowMentions {
+		allowed[strings.ToLower(l)] = true
+	}
+	kept := map[string]bool{}
+
+	lines := strings.Split(s, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := fenceOf(trimmed); f != "" {
+			fence = f
+			continue
+		}
+		lines[i] = outsideInlineCode(line, func(text string) string {
+			if opt.EscapeHTML {
+				text = htmlTag.ReplaceAllString(text, "&lt;$1")
+			}
+			return escapeMentions(text, allowed, kept, opt.MaxMentions)
+		})
+	}
+	s = strings.Join(lines, "\n")
+	if fence != "" {
+		s += "\n" + fence
+	}
+
+	s = blankRuns.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// fenceOf returns the fence marker if line opens a fenced code block.
+func fenceOf(line string) string {
+	for _, f := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, f) {
+			return f
+		}
+	}
+	return ""
+}
+
+// outsideInlineCode applies fn to the parts of line that are not inside `inline code`.
+func outsideInlineCode(line string, fn func(string) string) string {
+	parts := strings.Split(line, "`")
+	if len(parts)%2 == 0 {
+		// Unbalanced backtick: it is literal, so treat the trailing part as plain text.
+		parts[len(parts)-2] += "`" + parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = fn(parts[i])
+	}
+	return strings.Join(parts, "`")
+}
+
+// escapeMentions wraps mentions in code spans (which GitHub doesn't notify) unless the login is
+// allowed or still within the max budget of distinct logins.
+func escapeMentions(text string, allowed, kept map[string]bool, max int) string {
+	matches := mention.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if start > 0 && isWordByte(text[start-1]) {
+			continue // e-mail address or similar, not a mention
+		}
+		login := strings.ToLower(text[m[2]:m[3]])
+		if m[4] < 0 && (allowed[login] || kept[login]) {
+			continue
+		}
+		if m[4] < 0 && len(kept) < max {
+			kept[login] = true
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString("`" + text[start:end] + "`")
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '/' || c == '@' || c == '-' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isBidiControl(r rune) bool {
+	return (r >= 0x202A && r <= 0x202E) || (r >= 0x2066 && r <= 0x2069) || r == 0x200E || r == 0x200F
+}
@@ -0,0 +1,35 @@
+package mdsanitize
+
+import "testing"
+
+func TestSanitizeMentions(t *testing.T) {
+	got := Sanitize("ping @alice and @bob, mail me at me@example.com, cc @org/team", Options{AllowMentions: []string{"Alice"}})
+	want := "ping @alice and `@bob`, mail me at me@example.com, cc `@org/team`"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = Sanitize("@a @b @a @c", Options{MaxMentions: 2})
+	want = "@a @b @a `@c`"
+	if got != want {
+		t.Errorf("budget: got %q, want %q", got, want)
+	}
+}
+
+func TestSanitizeCode(t *testing.T) {
+	in := "use `@decorator` here\n```\n@Override\n<div>"
+	got := Sanitize(in, Options{EscapeHTML: true})
+	want := "use `@decorator` here\n```\n@Override\n<div>\n```"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSanitizeHTMLAndWhitespace(t *testing.T) {
+	in := "hi<!-- grainlify:status -->\r\n\r\n\r\n\r\n<img src=x onerror=1>\u202eevil"
+	got := Sanitize(in, Options{EscapeHTML: true})
+	want := "hi\n\n&lt;img src=x onerror=1>evil"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,207 @@
+// Package moderation checks user-generated text (application messages, bot comments, ecosystem
+// descriptions) before it is published to GitHub or the public API. A Pipeline runs a list of Checkers;
+// the first that objects rejects the text.
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kinds of text, passed to checkers (an external service may apply different rules to each).
+const (
+	KindApplicationMessage   = "application_message"
+	KindBotComment           = "bot_comment"
+	KindEcosystemDescription = "ecosystem_description"
+)
+
+// Checker is one moderation rule. It returns a reason when the text must not be published.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, kind, text string) (reason string, err error)
+}
+
+// Decision is the outcome of Review.
+type Decision struct {
+	Rejected bool
+	Check    string // the checker that rejected the text
+	Reason   string
+}
+
+// Pipeline runs checkers in order. A nil or empty Pipeline allows everything.
+type Pipeline struct {
+	checkers []Checker
+}
+
+func New(checkers ...Checker) *Pipeline {
+	return &Pipeline{checkers: checkers}
+}
+
+// Review runs text through the checkers. A checker that fails (e.g. an unreachable external service) is
+// logged and skipped: moderation must not take posting down with it.
+func (p *Pipeline) Review(ctx context.Context, kind, text string) Decision {
+	if p == nil || strings.TrimSpace(text) == "" {
+		return Decision{}
+	}
+	for _, c := range p.checkers {
+		reason, err := c.Check(ctx, kind, text)
+		if err != nil {
+			slog.Warn("moderation check failed", "check", c.Name(), "kind", kind, "error", err)
+			continue
+		}
+		if reason != "" {
+			slog.Info("moderation rejected text", "check", c.Name(), "kind", kind, "reason", reason)
+			return Decision{Rejected: true, Check: c.Name(), Reason: reason}
+		}
+	}
+	return Decision{}
+}
+
+// BlockedWords rejects text containing any of the words, matched case-insensitively as whole words.
+type BlockedWords struct {
+	words map[string]bool
+}
+
+func NewBlockedWords(words []string) BlockedWords {
+	b := BlockedWords{words: map[string]bool{}}
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			b.words[w] = true
+		}
+	}
+	return b
+}
+
+func (BlockedWords) Name() string { return "blocked_words" }
+
+func (b BlockedWords) Check(_ context.Context, _, text string) (string, error) {
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if b.words[w] {
+			return "contains a blocked word", nil
+		}
+	}
+	return "", nil
+}
+
+var linkRe = regexp.MustCompile(`(?i)\bhttps?://[^\s<>()\[\]"'` + "`" + `]+`)
+
+// LinkPolicy limits the links in text: with Hosts set every link must point at one of them (or a
+// subdomain), and MaxLinks caps how many links a text may have (0 = no cap).
+type LinkPolicy struct {
+	Hosts    []string
+	MaxLinks int
+}
+
+func (LinkPolicy) Name() string { return "links" }
+
+func (l LinkPolicy) Check(_ context.Context, _, text string) (string, error) {
+	links := linkRe.FindAllString(text, -1)
+	if l.MaxLinks > 0 && len(links) > l.MaxLinks {
+		return fmt.Sprintf("has more than %d links", l.MaxLinks), nil
+	}
+	if len(l.Hosts) == 0 {
+		return "", nil
+	}
+	for _, link := range links {
+		u, err := url.Parse(link)
+		if err != nil || !l.allowed(strings.ToLower(u.Hostname())) {
+			return "links to a site that is not allowed: " + link, nil
+		}
+	}
+	return "", nil
+}
+
+func (l LinkPolicy) allowed(host string) bool {
+	for _, h := range l.Hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" && (host == h || strings.HasSuffix(host, "."+h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// External asks a moderation service. It POSTs {"kind","text"} to URL (with a bearer Token if set) and
+// expects {"flagged": bool, "reason": string}.
+type External struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+func (External) Name() string { return "external" }
+
+func (e External) Check(ctx context.Context, kind, text string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"kind": kind, "text": text})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.Token)
+	}
+	client := e.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("moderation service returned %s", resp.Status)
+	}
+	var out struct {
+		Flagged bool   `json:"flagged"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode moderation response: %w", err)
+	}
+	if !out.Flagged {
+		return "", nil
+	}
+	if out.Reason == "" {
+		out.Reason = "flagged by moderation service"
+	}
+	return out.Reason, nil
+}
+
+// FromSettings builds the pipeline from comma-separated blocked words and allowed link hosts, a link cap,
+// and an optional moderation service URL. Unset settings add no checker.
+func FromSettings(blockedWords, linkHosts string, maxLinks int, apiURL, apiToken string) *Pipeline {
+	var checkers []Checker
+	if words := splitList(blockedWords); len(words) > 0 {
+		checkers = append(checkers, NewBlockedWords(words))
+	}
+	if hosts := splitList(linkHosts); len(hosts) > 0 || maxLinks > 0 {
+		checkers = append(checkers, LinkPolicy{Hosts: hosts, MaxLinks: maxLinks})
+	}
+	if apiURL = strings.TrimSpace(apiURL); apiURL != "" {
+		checkers = append(checkers, External{URL: apiURL, Token: apiToken})
+	}
+	return New(checkers...)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBlockedWords(t *testing.T) {
+	b := NewBlockedWords([]string{" Spam ", ""})
+	for text, rejected := range map[string]bool{
+		"Buy SPAM now!":        true,
+		"spam.":                true,
+		"antispam measures":    false,
+		"a perfectly fine PR.": false,
+	} {
+		reason, _ := b.Check(context.Background(), KindBotComment, text)
+		if (reason != "") != rejected {
+			t.Errorf("%q: reason = %q, want rejected=%v", text, reason, rejected)
+		}
+	}
+}
+
+func TestLinkPolicy(t *testing.T) {
+	l := LinkPolicy{Hosts: []string{"github.com"}, MaxLinks: 2}
+	for text, rejected := range map[string]bool{
+		"see https://github.com/o/r/pull/1":                              false,
+		"see [docs](https://docs.github.com/x)":                          false,
+		"see https://evil.example/github.com":                            true,
+		"see https://notgithub.com/x":                                    true,
+		"https://github.com/a https://github.com/b https://github.com/c": true,
+		"no links at all":                                                false,
+	} {
+		reason, _ := l.Check(context.Background(), KindApplicationMessage, text)
+		if (reason != "") != rejected {
+			t.Errorf("%q: reason = %q, want rejected=%v", text, reason, rejected)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var gotKind string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in struct{ Kind, Text string }
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		gotKind = in.Kind
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"flagged": in.Text == "bad", "reason": "nope"})
+	}))
+	defer srv.Close()
+
+	p := FromSettings("spam", "", 0, srv.URL, "tok")
+	if d := p.Review(context.Background(), KindBotComment, "spam"); !d.Rejected || d.Check != "blocked_words" {
+		t.Errorf("blocked word: %+v", d)
+	}
+	if d := p.Review(context.Background(), KindEcosystemDescription, "bad"); !d.Rejected || d.Check != "external" || d.Reason != "nope" || gotKind != KindEcosystemDescription {
+		t.Errorf("external: %+v (kind %q)", d, gotKind)
+	}
+	if d := p.Review(context.Background(), KindBotComment, "fine"); d.Rejected {
+		t.Errorf("fine text rejected: %+v", d)
+	}
+
+	// A failing service is skipped rather than blocking every post.
+	p = FromSettings("", "", 0, srv.URL, "wrong")
+	if d := p.Review(context.Background(), KindBotComment, "bad"); d.Rejected {
+		t.Errorf("failing service rejected: %+v", d)
+	}
+	if d := (*Pipeline)(nil).Review(context.Background(), KindBotComment, "spam"); d.Rejected {
+		t.Errorf("nil pipeline rejected: %+v", d)
+	}
+}
@@ -0,0 +1,28 @@
+// Package notify stores in-app notifications, which users read from GET /notifications. Every
+// notification is written through Store.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store stores an in-app notification of the given kind for userID. Failures are logged as well as
+// returned, so callers for whom the notification is best-effort can ignore the error.
+func Store(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, kind string, payload map[string]any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		b = []byte("{}")
+	}
+	if _, err := pool.Exec(ctx, `
+INSERT INTO notifications (user_id, kind, payload) VALUES ($1, $2, $3::jsonb)
+`, userID, kind, b); err != nil {
+		slog.Warn("failed to store notification", "error", err, "user_id", userID, "kind", kind)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package oidc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func token(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("idp-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestParseIDToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	base := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://idp.example.org/", "aud": []string{"grainlify"}, "sub": "u-1",
+			"exp": now.Add(5 * time.Minute).Unix(), "nonce": "n1", "email": "ada@example.org",
+			"email_verified": true,
+		}
+	}
+	id, err := ParseIDToken(token(t, base()), "https://idp.example.org", "grainlify", "n1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != "u-1" || id.Email != "ada@example.org" || !id.EmailVerified {
+		t.Errorf("identity = %+v", id)
+	}
+
+	cases := map[string]func(jwt.MapClaims){
+		"issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example" },
+		"audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":  func(c jwt.MapClaims) { c["exp"] = now.Add(-2 * time.Minute).Unix() },
+		"nonce":    func(c jwt.MapClaims) { c["nonce"] = "n2" },
+		"subject":  func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, mutate := range cases {
+		c := base()
+		mutate(c)
+		if _, err := ParseIDToken(token(t, c), "https://idp.example.org", "grainlify", "n1", now); err == nil || !strings.Contains(err.Error(), name[:4]) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	c := base()
+	c["email_verified"] = false
+	if id, _ := ParseIDToken(token(t, c), "https://idp.example.org", "grainlify", "n1", now); id.EmailVerified {
+		t.Error("email_verified=false ignored")
+	}
+	c = base()
+	delete(c, "email_verified")
+	if id, _ := ParseIDToken(token(t, c), "https://idp.example.org", "grainlify", "n1", now); id.EmailVerified {
+		t.Error("missing email_verified counted as verified")
+	}
+}
+
+func TestChallengeAndDomains(t *testing.T) {
+	// RFC 7636 appendix B.
+	if got := Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("Challenge = %s", got)
+	}
+	if !DomainAllowed("a@Example.org", []string{"@example.org"}) || DomainAllowed("a@example.com", []string{"example.org"}) || !DomainAllowed("a@x.y", nil) {
+		t.Error("DomainAllowed")
+	}
+	if ValidateIssuer("http://idp.example.org") == nil || ValidateIssuer("https://idp.example.org/realms/x") != nil {
+		t.Error("ValidateIssuer")
+	}
+}
@@ -0,0 +1,228 @@
+// Package overdue unassigns contributors whose assignment deadline has passed (plus a grace period)
+// without a pending extension request, freeing the issue for someone else.
+package overdue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/botcomments"
+	"github.com/jagadeesh/grainlify/backend/internal/botmessages"
+	"github.com/jagadeesh/grainlify/backend/internal/checkruns"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/ingest"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+	"github.com/jagadeesh/grainlify/backend/internal/tasks"
+	"github.com/jagadeesh/grainlify/backend/internal/waitlist"
+)
+
+// Job periodically releases overdue assignments.
+type Job struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+	gh   *github.Client
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Job {
+	return &Job{cfg: cfg, pool: pool, gh: github.NewClient()}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	if !j.cfg.AutoUnassignOverdue || strings.TrimSpace(j.cfg.GitHubAppID) == "" || strings.TrimSpace(j.cfg.GitHubAppPrivateKey) == "" {
+		return nil
+	}
+	t := time.NewTicker(15 * time.Minute)
+	defer t.Stop()
+
+	for {
+		if err := j.Release(ctx); err != nil {
+			slog.Error("overdue assignment release failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+type assignment struct {
+	projectID      uuid.UUID
+	ownerID        uuid.UUID
+	fullName       string
+	installationID string
+	number         int
+	deadline       time.Time
+	logins         []string
+	// version is github_issues.row_version when the assignment was read.
+	version int64
+}
+
+// Release unassigns every overdue assignment.
+func (j *Job) Release(ctx context.Context) error {
+	rows, err := j.pool.Query(ctx, `
+SELECT gi.project_id, p.owner_user_id, p.github_full_name, p.github_app_installation_id, gi.number, gi.deadline_at, gi.assignees,
+       gi.row_version
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+WHERE gi.state = 'open'
+  AND gi.deadline_at < now() - make_interval(hours => $1)
+  AND COALESCE(jsonb_array_length(gi.assignees), 0) > 0
+  AND p.status = 'verified' AND p.deleted_at IS NULL AND p.github_app_installation_id IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM assignment_extension_requests x
+    WHERE x.project_id = gi.project_id AND x.issue_number = gi.number AND x.status = 'pending'
+  )
+ORDER BY gi.deadline_at
+LIMIT 100
+`, j.cfg.OverdueGraceHours)
+	if err != nil {
+		return err
+	}
+	var list []assignment
+	for rows.Next() {
+		var a assignment
+		var assigneesJSON []byte
+		if err := rows.Scan(&a.projectID, &a.ownerID, &a.fullName, &a.installationID, &a.number, &a.deadline, &assigneesJSON, &a.version); err != nil {
+			rows.Close()
+			return err
+		}
+		var assignees []struct {
+			Login string `json:"login"`
+		}
+		_ = json.Unmarshal(assigneesJSON, &assignees)
+		for _, as := range assignees {
+			if as.Login != "" {
+				a.logins = append(a.logins, as.Login)
+			}
+		}
+		if len(a.logins) > 0 {
+			list = append(list, a)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	released := 0
+	for _, a := range list {
+		appClient, err := github.NewInstallationAppClient(ctx, j.pool, j.cfg, a.installationID)
+		if err != nil {
+			slog.Warn("overdue: github app client failed", "project_id", a.projectID, "error", err)
+			continue
+		}
+		token, err := appClient.GetInstallationToken(ctx, a.installationID)
+		if err != nil {
+			slog.Warn("overdue: installation token failed", "project_id", a.projectID, "error", err)
+			continue
+		}
+		if err := j.release(ctx, token, a); err != nil {
+			slog.Warn("overdue: unassign failed", "project_id", a.projectID, "issue_number", a.number, "error", err)
+			continue
+		}
+		released++
+	}
+	slog.Info("overdue assignments released", "released", released, "candidates", len(list))
+	return nil
+}
+
+func (j *Job) release(ctx context.Context, token string, a assignment) error {
+	// Native tasks have no GitHub issue: the assignment and the notice only live here.
+	native := tasks.IsNative(a.number)
+	if !native {
+		if err := j.gh.RemoveIssueAssignees(ctx, token, a.fullName, a.number, a.logins); err != nil {
+			return err
+		}
+	}
+
+	ct, err := j.pool.Exec(ctx, `
+UPDATE github_issues SET assignees = '[]'::jsonb, deadline_at = NULL, last_seen_at = now()
+WHERE project_id = $1 AND number = $2 AND row_version = $3
+`, a.projectID, a.number, a.version)
+	if err == nil && ct.RowsAffected() == 0 && native {
+		_, _ = j.pool.Exec(ctx, `
+UPDATE github_issues SET assignees = '[]'::jsonb, deadline_at = NULL, last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, a.projectID, a.number)
+	} else if err == nil && ct.RowsAffected() == 0 {
+		// A webhook or sync changed the issue since it was read: take GitHub's assignees instead.
+		if err := ingest.RefreshIssue(ctx, j.pool, j.gh, token, a.fullName, a.projectID, a.number); err != nil {
+			slog.Warn("overdue: issue refresh failed", "project_id", a.projectID, "issue_number", a.number, "error", err)
+		}
+		_, _ = j.pool.Exec(ctx, `UPDATE github_issues SET deadline_at = NULL WHERE project_id = $1 AND number = $2`, a.projectID, a.number)
+	}
+
+	lower := make([]string, len(a.logins))
+	for i, l := range a.logins {
+		lower[i] = strings.ToLower(l)
+	}
+	_, _ = j.pool.Exec(ctx, `
+UPDATE issue_applications SET status = 'unassigned', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'assigned' AND LOWER(github_login) = ANY($3)
+`, a.projectID, a.number, lower)
+
+	if err := rewards.Return(ctx, j.pool, a.projectID, a.number, "unassigned overdue"); err != nil {
+		slog.Error("overdue: failed to return issue reward", "project_id", a.projectID, "issue_number", a.number, "error", err)
+	}
+
+	who := "@" + strings.Join(a.logins, ", @")
+	body := botmessages.Render(ctx, j.pool, a.projectID, botmessages.UnassignedOverdue,
+		map[string]string{"assignees": who, "deadline": a.deadline.UTC().Format("Jan 2, 2006 15:04 MST")})
+	if next, err := waitlist.NotifyNext(ctx, j.pool, a.projectID, a.number); err != nil {
+		slog.Warn("overdue: failed to notify waitlist", "project_id", a.projectID, "issue_number", a.number, "error", err)
+	} else if next != "" {
+		body += "\n\n" + botmessages.Render(ctx, j.pool, a.projectID, botmessages.WaitlistNext, map[string]string{"next": next})
+	}
+	if !native {
+		if com, err := j.gh.CreateIssueComment(ctx, token, a.fullName, a.number, body); err == nil {
+			commentJSON, _ := json.Marshal(com)
+			_, _ = j.pool.Exec(ctx, `
+UPDATE github_issues SET comments = COALESCE(comments, '[]'::jsonb) || $3::jsonb,
+  comments_count = COALESCE(comments_count, 0) + 1, updated_at_github = $4, last_seen_at = now()
+WHERE project_id = $1 AND number = $2
+`, a.projectID, a.number, commentJSON, com.UpdatedAt)
+		}
+	}
+
+	// The unassigned contributors (those with a Grainlify account) and the project owner are told.
+	recipients := []uuid.UUID{}
+	if rows, err := j.pool.Query(ctx, `SELECT user_id FROM github_accounts WHERE LOWER(login) = ANY($1)`, lower); err == nil {
+		for rows.Next() {
+			var id uuid.UUID
+			if rows.Scan(&id) == nil {
+				recipients = append(recipients, id)
+			}
+		}
+		rows.Close()
+	}
+	for _, userID := range append(recipients, a.ownerID) {
+		_ = notify.Store(ctx, j.pool, userID, "assignment_expired", map[string]any{
+			"project_id":       a.projectID.String(),
+			"github_full_name": a.fullName,
+			"issue_number":     a.number,
+			"deadline_at":      a.deadline,
+		})
+	}
+
+	checkruns.MarkIssueChanged(ctx, j.pool, a.projectID, a.number)
+	if err := botcomments.RefreshStatusComment(ctx, j.cfg, j.pool, a.projectID, a.number); err != nil {
+		slog.Warn("overdue: failed to refresh status comment", "project_id", a.projectID, "issue_number", a.number, "error", err)
+	}
+	return nil
+}
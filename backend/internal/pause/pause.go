@@ -0,0 +1,137 @@
+// Package pause is the contributor vacation mode. Pausing holds the contributor's pending applications
+// (maintainers see them as held and cannot assign them without forcing) and defers scheduled bot comments
+// addressed to them; both resume automatically at the chosen date, or earlier when the contributor
+// resumes by hand.
+package pause
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MaxDays is the longest pause that can be set at once.
+const MaxDays = 365
+
+// applicationsOf matches a user's applications, including quick applies made before they signed up.
+const applicationsOf = `(a.user_id = $1 OR LOWER(a.github_login) IN (SELECT LOWER(login) FROM github_accounts WHERE user_id = $1))`
+
+// Start pauses userID until the given time and holds their pending applications. It returns how many
+// applications were held.
+func Start(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, until time.Time, reason *string) (int64, error) {
+	var held int64
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+UPDATE users
+SET paused_at = COALESCE(paused_at, now()), paused_until = $2, pause_reason = $3, updated_at = now()
+WHERE id = $1
+`, userID, until, reason); err != nil {
+			return err
+		}
+		ct, err := tx.Exec(ctx, `
+UPDATE issue_applications a SET held_at = now(), updated_at = now()
+WHERE `+applicationsOf+` AND a.status = 'pending' AND a.held_at IS NULL
+`, userID)
+		held = ct.RowsAffected()
+		return err
+	})
+	return held, err
+}
+
+// Resume ends userID's pause and releases held applications, returning how many were released.
+func Resume(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (int64, error) {
+	var released int64
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+UPDATE users SET paused_at = NULL, paused_until = NULL, pause_reason = NULL, updated_at = now()
+WHERE id = $1
+`, userID); err != nil {
+			return err
+		}
+		ct, err := tx.Exec(ctx, `
+UPDATE issue_applications a SET held_at = NULL, updated_at = now()
+WHERE `+applicationsOf+` AND a.held_at IS NOT NULL
+`, userID)
+		released = ct.RowsAffected()
+		return err
+	})
+	return released, err
+}
+
+// UntilForLogin returns when the user behind a GitHub login is back, or nil when they are not paused.
+func UntilForLogin(ctx context.Context, pool *pgxpool.Pool, login string) (*time.Time, error) {
+	var until *time.Time
+	err := pool.QueryRow(ctx, `
+SELECT u.paused_until
+FROM users u
+JOIN github_accounts ga ON ga.user_id = u.id
+WHERE LOWER(ga.login) = LOWER($1) AND u.paused_until > now()
+`, login).Scan(&until)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return until, err
+}
+
+// Job resumes contributors whose pause has ended.
+type Job struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(15 * time.Minute)
+	defer t.Stop()
+	for {
+		if err := j.ResumeDue(ctx); err != nil {
+			slog.Error("pause resume failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// ResumeDue resumes every pause whose end date has passed.
+func (j *Job) ResumeDue(ctx context.Context) error {
+	rows, err := j.pool.Query(ctx, `SELECT id FROM users WHERE paused_until IS NOT NULL AND paused_until <= now()`)
+	if err != nil {
+		return err
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		released, err := Resume(ctx, j.pool, id)
+		if err != nil {
+			slog.Warn("failed to resume paused contributor", "user_id", id, "error", err)
+			continue
+		}
+		slog.Info("paused contributor resumed", "user_id", id, "applications_released", released)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package payouts
+
+// TaxStatus is the tax status of payout p: not_required unless it is a fiat payout in an ecosystem that
+// requires tax information, else the recipient's tax profile status, or missing.
+const TaxStatus = `CASE WHEN NOT EXISTS (
+         SELECT 1 FROM currencies cur, projects pr JOIN ecosystems eco ON eco.id = pr.ecosystem_id
+         WHERE cur.code = p.currency_code AND cur.kind = 'fiat' AND pr.id = p.project_id AND eco.requires_tax_info)
+       THEN 'not_required'
+       ELSE COALESCE((SELECT status FROM tax_profiles WHERE user_id = p.recipient_user_id), 'missing') END`
+
+// KYCStatus is the KYC status of payout p: not_required unless its currency has a KYC threshold the amount
+// is above, else the recipient's KYC status (not_started if they never began).
+const KYCStatus = `CASE WHEN NOT EXISTS (
+         SELECT 1 FROM currencies cur WHERE cur.code = p.currency_code AND p.amount > cur.kyc_threshold)
+       THEN 'not_required'
+       ELSE COALESCE((SELECT kyc_status FROM users WHERE id = p.recipient_user_id), 'not_started') END`
+
+// cleared is true for payout p when nothing the payout gates check holds it back. Approving checks the
+// same, but a tax profile can be rejected or a KYC verification lapse after approval, so the job checks
+// again before paying.
+const cleared = `(` + TaxStatus + `) IN ('not_required', 'verified') AND (` + KYCStatus + `) IN ('not_required', 'verified')`
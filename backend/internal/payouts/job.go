@@ -0,0 +1,159 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+	"github.com/jagadeesh/grainlify/backend/internal/rewards"
+)
+
+// Interval is how often approved payouts are looked for.
+const Interval = time.Minute
+
+// lease is how long an executing payout belongs to the instance that claimed it. A payout still executing
+// after that (its instance died mid-call) is claimed again; executors are idempotent, so that is safe.
+const lease = 10 * time.Minute
+
+const batchSize = 20
+
+// Job executes approved payouts with its executor.
+type Job struct {
+	pool *pgxpool.Pool
+	exec Executor
+}
+
+func New(pool *pgxpool.Pool, exec Executor) *Job {
+	return &Job{pool: pool, exec: exec}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	if j.exec == nil {
+		return fmt.Errorf("no payout executor configured")
+	}
+	t := time.NewTicker(Interval)
+	defer t.Stop()
+	for {
+		if err := j.ExecuteDue(ctx, time.Now()); err != nil {
+			slog.Error("payout execution failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+type claimed struct {
+	Payout
+	userID   uuid.UUID
+	attempts int
+}
+
+// ExecuteDue executes the approved payouts, and executing ones whose retry time or lease is up. Payouts the
+// gates hold back (see cleared) are skipped until they clear or are cancelled.
+func (j *Job) ExecuteDue(ctx context.Context, now time.Time) error {
+	rows, err := j.pool.Query(ctx, `
+UPDATE payouts p
+SET status = 'executing', executor = $3, next_attempt_at = $1::timestamptz + make_interval(secs => $2), updated_at = now()
+FROM currencies c
+WHERE c.code = p.currency_code AND p.id IN (
+  SELECT p.id FROM payouts p
+  WHERE (p.status = 'approved' OR (p.status = 'executing' AND p.next_attempt_at <= $1)) AND `+cleared+`
+  ORDER BY p.approved_at
+  LIMIT $4
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING p.id, p.recipient_user_id, COALESCE(p.recipient_address, ''), p.currency_code, COALESCE(c.chain, ''),
+          COALESCE(c.contract_id, ''), p.amount::text, c.decimals, p.attempts
+`, now, int(lease.Seconds()), j.exec.Name(), batchSize)
+	if err != nil {
+		return err
+	}
+	var batch []claimed
+	for rows.Next() {
+		var c claimed
+		if err := rows.Scan(&c.ID, &c.userID, &c.Recipient, &c.Currency, &c.Chain, &c.ContractID, &c.Amount, &c.Decimals, &c.attempts); err != nil {
+			rows.Close()
+			return err
+		}
+		c.RecipientUserID = c.userID.String()
+		batch = append(batch, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range batch {
+		res, err := j.exec.Execute(ctx, c.Payout)
+		if err := j.record(ctx, now, c, res, err); err != nil {
+			slog.Error("failed to record payout outcome", "payout_id", c.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// record stores the outcome of executing c.
+func (j *Job) record(ctx context.Context, now time.Time, c claimed, res Result, execErr error) error {
+	switch {
+	case execErr == nil:
+		if _, err := j.pool.Exec(ctx, `
+UPDATE payouts
+SET status = 'paid', tx_hash = $2, external_id = NULLIF($3, ''), error = NULL, next_attempt_at = NULL,
+    executed_at = now(), updated_at = now()
+WHERE id = $1
+`, c.ID, res.TxHash, res.ExternalID); err != nil {
+			return err
+		}
+		slog.Info("payout executed", "payout_id", c.ID, "executor", j.exec.Name(), "tx_hash", res.TxHash)
+		if err := rewards.PaidOut(ctx, j.pool, c.ID); err != nil {
+			slog.Error("failed to record payout in the ledger", "payout_id", c.ID, "error", err)
+		}
+		_ = notify.Store(ctx, j.pool, c.userID, "payout_paid", map[string]any{
+			"payout_id": c.ID,
+			"currency":  c.Currency,
+			"amount":    c.Amount,
+			"decimals":  c.Decimals,
+			"tx_hash":   res.TxHash,
+		})
+		return nil
+
+	case errors.Is(execErr, ErrPending):
+		_, err := j.pool.Exec(ctx, `
+UPDATE payouts SET external_id = COALESCE(NULLIF($2, ''), external_id), next_attempt_at = $3, updated_at = now()
+WHERE id = $1
+`, c.ID, res.ExternalID, now.Add(Interval))
+		return err
+
+	case IsPermanent(execErr) || c.attempts+1 >= MaxAttempts:
+		slog.Warn("payout failed", "payout_id", c.ID, "executor", j.exec.Name(), "attempts", c.attempts+1, "error", execErr)
+		if _, err := j.pool.Exec(ctx, `
+UPDATE payouts
+SET status = 'failed', attempts = attempts + 1, error = $2, external_id = COALESCE(NULLIF($3, ''), external_id),
+    next_attempt_at = NULL, updated_at = now()
+WHERE id = $1
+`, c.ID, execErr.Error(), res.ExternalID); err != nil {
+			return err
+		}
+		return nil
+
+	default:
+		slog.Warn("payout attempt failed, will retry", "payout_id", c.ID, "executor", j.exec.Name(), "attempts", c.attempts+1, "error", execErr)
+		_, err := j.pool.Exec(ctx, `
+UPDATE payouts SET attempts = attempts + 1, error = $2, next_attempt_at = $3, updated_at = now()
+WHERE id = $1
+`, c.ID, execErr.Error(), now.Add(Backoff(c.attempts+1)))
+		return err
+	}
+}
@@ -0,0 +1,100 @@
+// Package payouts executes approved payouts. An Executor sends the funds (on chain, or through a payouts
+// provider) and reports the transaction; the Job hands it approved payouts, records the outcome and
+// retries failures with backoff. Executing is optional: without an executor configured, payouts are
+// approved in Grainlify and paid by other means.
+package payouts
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Statuses.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusExecuting = "executing"
+	StatusPaid      = "paid"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Payout is what an executor is asked to pay.
+type Payout struct {
+	ID string
+	// Recipient is the recipient's address on the currency's chain; empty for fiat.
+	Recipient string
+	// RecipientUserID lets providers that pay fiat match the payout to their own account records.
+	RecipientUserID string
+	Currency        string
+	Chain           string
+	ContractID      string
+	// Amount is in base units (cents, stroops): Decimals places.
+	Amount   string
+	Decimals int
+}
+
+// Result is a completed payout.
+type Result struct {
+	TxHash     string
+	ExternalID string
+}
+
+// Executor pays payouts. Execute must be idempotent per payout ID: after a crash or a timeout the same
+// payout is executed again, and must not be paid twice.
+type Executor interface {
+	Name() string
+	Execute(ctx context.Context, p Payout) (Result, error)
+}
+
+// ErrPending is returned by Execute when the payout was accepted but has no transaction yet; it is
+// executed again later to pick up the result.
+var ErrPending = errors.New("payout pending")
+
+// PermanentError is a failure retrying can't fix (an invalid address, insufficient funds). The payout
+// fails at once instead of being retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps err as a PermanentError.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err is, or wraps, a PermanentError.
+func IsPermanent(err error) bool {
+	var p *PermanentError
+	return errors.As(err, &p)
+}
+
+// MaxAttempts is how many times a payout is tried before it fails.
+const MaxAttempts = 8
+
+// Backoff is how long to wait before trying a payout again after attempts failures: doubling from a
+// minute, at most six hours.
+func Backoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < 6*time.Hour; i++ {
+		d *= 2
+	}
+	if d > 6*time.Hour {
+		d = 6 * time.Hour
+	}
+	return d
+}
+
+// FromSettings returns the executor the settings configure, or nil when none is.
+func FromSettings(providerURL, providerToken string) Executor {
+	if providerURL == "" {
+		return nil
+	}
+	return &Provider{URL: providerURL, Token: providerToken}
+}
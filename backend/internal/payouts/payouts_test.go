@@ -0,0 +1,74 @@
+package payouts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		4:  8 * time.Minute,
+		20: 6 * time.Hour,
+	} {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestProvider(t *testing.T) {
+	var reply map[string]any
+	status := http.StatusOK
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/payouts" || r.Header.Get("Idempotency-Key") != "p1" || r.Header.Get("Authorization") != "Bearer k" {
+			t.Errorf("request %s %v", r.URL.Path, r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	defer srv.Close()
+	p := FromSettings(srv.URL+"/", "k")
+	payout := Payout{ID: "p1", Recipient: "GABC", Currency: "USDC", Chain: "stellar", Amount: "12500000", Decimals: 7}
+
+	reply = map[string]any{"id": "x1", "status": "completed", "tx_hash": "0xfeed"}
+	res, err := p.Execute(context.Background(), payout)
+	if err != nil || res.TxHash != "0xfeed" || res.ExternalID != "x1" {
+		t.Fatalf("completed: %+v, %v", res, err)
+	}
+	if got["reference"] != "p1" || got["amount"] != "12500000" || got["recipient"] != "GABC" {
+		t.Errorf("body = %v", got)
+	}
+
+	reply = map[string]any{"id": "x1", "status": "pending"}
+	if _, err := p.Execute(context.Background(), payout); !errors.Is(err, ErrPending) {
+		t.Errorf("pending: %v", err)
+	}
+
+	reply = map[string]any{"id": "x1", "status": "failed", "error": "insufficient funds"}
+	if _, err := p.Execute(context.Background(), payout); !IsPermanent(err) || err.Error() != "insufficient funds" {
+		t.Errorf("failed: %v", err)
+	}
+
+	status, reply = http.StatusUnprocessableEntity, map[string]any{"error": "invalid address"}
+	if _, err := p.Execute(context.Background(), payout); !IsPermanent(err) {
+		t.Errorf("4xx: %v", err)
+	}
+
+	status = http.StatusBadGateway
+	if _, err := p.Execute(context.Background(), payout); err == nil || IsPermanent(err) {
+		t.Errorf("5xx: %v", err)
+	}
+
+	if FromSettings("", "k") != nil {
+		t.Error("executor without settings")
+	}
+}
@@ -0,0 +1,100 @@
+package payouts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider executes payouts through a payouts provider's API. It POSTs the payout to URL + "/payouts"
+// with the payout ID as Idempotency-Key (and a bearer Token if set):
+//
+//	{"reference","recipient","recipient_user_id","currency","chain","contract_id","amount","decimals"}
+//
+// and expects {"id","status","tx_hash","error"}, status being "completed", "pending" or "failed". Posting
+// the same payout again returns its current state, which is how pending payouts are followed up. 4xx
+// responses and failed payouts are permanent failures; 5xx and network errors are retried.
+type Provider struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+func (p *Provider) Name() string { return "provider" }
+
+func (p *Provider) Execute(ctx context.Context, payout Payout) (Result, error) {
+	body, _ := json.Marshal(map[string]any{
+		"reference":         payout.ID,
+		"recipient":         payout.Recipient,
+		"recipient_user_id": payout.RecipientUserID,
+		"currency":          payout.Currency,
+		"chain":             payout.Chain,
+		"contract_id":       payout.ContractID,
+		"amount":            payout.Amount,
+		"decimals":          payout.Decimals,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(p.URL, "/")+"/payouts", bytes.NewReader(body))
+	if err != nil {
+		return Result{}, Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", payout.ID)
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	client := p.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		TxHash string `json:"tx_hash"`
+		Error  string `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	decodeErr := json.Unmarshal(raw, &out)
+	if resp.StatusCode/100 != 2 {
+		err := fmt.Errorf("payouts provider returned %s", resp.Status)
+		if out.Error != "" {
+			err = fmt.Errorf("payouts provider returned %s: %s", resp.Status, out.Error)
+		}
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusConflict {
+			return Result{}, Permanent(err)
+		}
+		return Result{}, err
+	}
+	if decodeErr != nil {
+		return Result{}, fmt.Errorf("decode payouts provider response: %w", decodeErr)
+	}
+
+	res := Result{TxHash: out.TxHash, ExternalID: out.ID}
+	switch out.Status {
+	case "completed":
+		if out.TxHash == "" {
+			return res, errors.New("payouts provider completed the payout without a transaction hash")
+		}
+		return res, nil
+	case "pending":
+		return res, ErrPending
+	case "failed":
+		if out.Error == "" {
+			out.Error = "failed at the payouts provider"
+		}
+		return res, Permanent(errors.New(out.Error))
+	default:
+		return res, fmt.Errorf("payouts provider returned unknown status %q", out.Status)
+	}
+}
@@ -0,0 +1,200 @@
+package promotions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
+)
+
+// MaxAttempts is how many times a delivery is tried before it is marked failed.
+const MaxAttempts = 5
+
+// Interval is how often due deliveries are posted; scheduled promotions go out within a minute.
+const Interval = time.Minute
+
+// lease is how long a claimed delivery belongs to the instance posting it. One still sending after that
+// (the instance stopped mid-post) is claimed again.
+const lease = 15 * time.Minute
+
+// Job periodically posts due promotions.
+type Job struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+	http *http.Client
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Job {
+	return &Job{cfg: cfg, pool: pool, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(Interval)
+	defer t.Stop()
+
+	for {
+		if err := j.Deliver(ctx); err != nil {
+			slog.Error("issue promotion delivery failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+type delivery struct {
+	id       uuid.UUID
+	kind     string
+	urlEnc   []byte
+	attempts int
+	state    string
+	msg      Message
+}
+
+// Deliver posts every due delivery. Deliveries are claimed ('sending') first, so several instances may run
+// the job without posting twice. Deliveries for issues closed in the meantime are cancelled.
+func (j *Job) Deliver(ctx context.Context) error {
+	rows, err := j.pool.Query(ctx, `
+WITH claimed AS (
+  UPDATE issue_promotions
+  SET status = 'sending', scheduled_for = now() + make_interval(secs => $1)
+  WHERE id IN (
+    SELECT ip.id
+    FROM issue_promotions ip
+    JOIN promotion_channels pc ON pc.id = ip.channel_id
+    JOIN projects p ON p.id = ip.project_id
+    JOIN github_issues gi ON gi.project_id = ip.project_id AND gi.number = ip.issue_number
+    WHERE ip.status IN ('pending', 'sending') AND ip.scheduled_for <= now() AND pc.enabled AND p.deleted_at IS NULL
+    ORDER BY ip.scheduled_for
+    LIMIT 50
+    FOR UPDATE OF ip SKIP LOCKED
+  )
+  RETURNING id, channel_id, project_id, issue_number, attempts
+)
+SELECT c.id, pc.kind, pc.url_enc, c.attempts, COALESCE(gi.state, ''),
+  COALESCE(e.name, ''), p.github_full_name, gi.number, COALESCE(gi.title, ''), COALESCE(gi.url, ''), gi.points, COALESCE(gi.labels, '[]'::jsonb)
+FROM claimed c
+JOIN promotion_channels pc ON pc.id = c.channel_id
+JOIN projects p ON p.id = c.project_id
+LEFT JOIN ecosystems e ON e.id = pc.ecosystem_id
+JOIN github_issues gi ON gi.project_id = c.project_id AND gi.number = c.issue_number
+`, lease.Seconds())
+	if err != nil {
+		return err
+	}
+	var list []delivery
+	for rows.Next() {
+		var d delivery
+		var labelsJSON []byte
+		if err := rows.Scan(&d.id, &d.kind, &d.urlEnc, &d.attempts, &d.state,
+			&d.msg.Ecosystem, &d.msg.Repo, &d.msg.IssueNumber, &d.msg.Title, &d.msg.URL, &d.msg.Points, &labelsJSON); err != nil {
+			rows.Close()
+			return err
+		}
+		var labels []struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(labelsJSON, &labels)
+		for _, l := range labels {
+			if l.Name != "" {
+				d.msg.Labels = append(d.msg.Labels, l.Name)
+			}
+		}
+		if d.msg.URL == "" {
+			d.msg.URL = fmt.Sprintf("https://github.com/%s/issues/%d", d.msg.Repo, d.msg.IssueNumber)
+		}
+		list = append(list, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	key, err := cryptox.KeyFromB64(j.cfg.TokenEncKey())
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, d := range list {
+		if d.state != "open" {
+			_, _ = j.pool.Exec(ctx, `UPDATE issue_promotions SET status = 'cancelled', last_error = 'issue closed' WHERE id = $1 AND status = 'sending'`, d.id)
+			continue
+		}
+		if err := j.post(ctx, key, d); err != nil {
+			slog.Warn("issue promotion post failed", "promotion_id", d.id, "kind", d.kind, "attempt", d.attempts+1, "error", err)
+			j.fail(ctx, d, err)
+			continue
+		}
+		_, _ = j.pool.Exec(ctx, `
+UPDATE issue_promotions SET status = 'sent', sent_at = now(), attempts = attempts + 1, last_error = NULL
+WHERE id = $1 AND status = 'sending'
+`, d.id)
+		sent++
+	}
+	slog.Info("issue promotions delivered", "sent", sent, "due", len(list))
+	return nil
+}
+
+func (j *Job) post(ctx context.Context, key []byte, d delivery) error {
+	rawURL, err := cryptox.DecryptAESGCM(key, d.urlEnc)
+	if err != nil {
+		return fmt.Errorf("decrypt channel url: %w", err)
+	}
+	body, err := Payload(d.kind, d.msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, string(rawURL), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "grainlify-promotions")
+	resp, err := j.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return fmt.Errorf("channel returned %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+// fail records a failed attempt, retrying after attempts² minutes until MaxAttempts.
+func (j *Job) fail(ctx context.Context, d delivery, cause error) {
+	attempts := d.attempts + 1
+	msg := cause.Error()
+	if len(msg) > 500 {
+		msg = msg[:500]
+	}
+	if attempts >= MaxAttempts {
+		_, _ = j.pool.Exec(ctx, `
+UPDATE issue_promotions SET status = 'failed', attempts = $2, last_error = $3 WHERE id = $1 AND status = 'sending'
+`, d.id, attempts, msg)
+		return
+	}
+	_, _ = j.pool.Exec(ctx, `
+UPDATE issue_promotions SET status = 'pending', attempts = $2, last_error = $3, scheduled_for = now() + make_interval(mins => $4)
+WHERE id = $1 AND status = 'sending'
+`, d.id, attempts, msg, attempts*attempts)
+}
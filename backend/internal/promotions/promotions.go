@@ -0,0 +1,178 @@
+// Package promotions cross-posts promoted issues to an ecosystem's promotion channels: Discord and Slack
+// incoming webhooks, or a generic JSON webhook (for relays that post to Twitter and similar). Promoting an
+// issue queues one delivery per enabled channel, optionally scheduled for later; the job posts due
+// deliveries and retries failures with backoff. A channel never receives the same issue twice.
+package promotions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel kinds.
+const (
+	KindDiscord = "discord"
+	KindSlack   = "slack"
+	KindWebhook = "webhook"
+)
+
+// maxTextLength keeps the short text within a tweet.
+const maxTextLength = 280
+
+// Message is a promoted issue as posted to a channel.
+type Message struct {
+	Ecosystem   string
+	Repo        string
+	IssueNumber int
+	Title       string
+	URL         string
+	Points      *int
+	Labels      []string
+}
+
+// ValidateURL checks that raw is an https webhook URL of the given kind.
+func ValidateURL(kind, raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url must be an https URL")
+	}
+	host := strings.ToLower(u.Hostname())
+	switch kind {
+	case KindDiscord:
+		if (host != "discord.com" && host != "discordapp.com") || !strings.HasPrefix(u.Path, "/api/webhooks/") {
+			return fmt.Errorf("url must be a Discord webhook URL (https://discord.com/api/webhooks/...)")
+		}
+	case KindSlack:
+		if host != "hooks.slack.com" {
+			return fmt.Errorf("url must be a Slack incoming webhook URL (https://hooks.slack.com/...)")
+		}
+	case KindWebhook:
+	default:
+		return fmt.Errorf("kind must be discord, slack or webhook")
+	}
+	return nil
+}
+
+// Text is the one-line announcement, shortened to fit a tweet. The URL is always kept whole.
+func Text(m Message) string {
+	prefix := "New issue"
+	if m.Points != nil && *m.Points > 0 {
+		prefix = "New " + strconv.Itoa(*m.Points) + "-point issue"
+	}
+	prefix += " in " + m.Repo + ": "
+	suffix := " " + m.URL
+	title := strings.TrimSpace(m.Title)
+	room := maxTextLength - utf8.RuneCountInString(prefix) - utf8.RuneCountInString(suffix)
+	if room < 1 {
+		return strings.TrimSpace(prefix + suffix)
+	}
+	if utf8.RuneCountInString(title) > room {
+		title = string([]rune(title)[:room-1]) + "…"
+	}
+	return prefix + title + suffix
+}
+
+// Payload is the JSON body posted to a channel of the given kind.
+func Payload(kind string, m Message) ([]byte, error) {
+	switch kind {
+	case KindDiscord:
+		fields := []map[string]any{}
+		if m.Points != nil {
+			fields = append(fields, map[string]any{"name": "Points", "value": strconv.Itoa(*m.Points), "inline": true})
+		}
+		if len(m.Labels) > 0 {
+			fields = append(fields, map[string]any{"name": "Labels", "value": strings.Join(m.Labels, ", "), "inline": true})
+		}
+		title := fmt.Sprintf("%s#%d: %s", m.Repo, m.IssueNumber, m.Title)
+		if utf8.RuneCountInString(title) > 256 {
+			title = string([]rune(title)[:255]) + "…"
+		}
+		return json.Marshal(map[string]any{
+			"content": Text(m),
+			"embeds": []map[string]any{{
+				"title":  title,
+				"url":    m.URL,
+				"fields": fields,
+			}},
+			// Issue titles are user-written; never let them ping anyone.
+			"allowed_mentions": map[string]any{"parse": []string{}},
+		})
+	case KindSlack:
+		line := fmt.Sprintf("<%s|%s#%d: %s>", m.URL, m.Repo, m.IssueNumber, slackEscape(m.Title))
+		if m.Points != nil {
+			line += fmt.Sprintf(" · %d points", *m.Points)
+		}
+		return json.Marshal(map[string]any{
+			"text": Text(m),
+			"blocks": []map[string]any{{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": line},
+			}},
+		})
+	case KindWebhook:
+		labels := m.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		return json.Marshal(map[string]any{
+			"event":        "issue.promoted",
+			"text":         Text(m),
+			"ecosystem":    m.Ecosystem,
+			"repo":         m.Repo,
+			"issue_number": m.IssueNumber,
+			"title":        m.Title,
+			"url":          m.URL,
+			"points":       m.Points,
+			"labels":       labels,
+		})
+	}
+	return nil, fmt.Errorf("unknown channel kind %q", kind)
+}
+
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Enqueue queues the issue for every enabled channel of the project's ecosystem, to be posted at at.
+// Deliveries already sent or being sent are left alone; pending, failed or cancelled ones are rescheduled. It returns
+// how many deliveries were queued.
+func Enqueue(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, at time.Time) (int64, error) {
+	ct, err := pool.Exec(ctx, `
+INSERT INTO issue_promotions (channel_id, project_id, issue_number, scheduled_for)
+SELECT pc.id, p.id, $2, $3
+FROM projects p
+JOIN promotion_channels pc ON pc.ecosystem_id = p.ecosystem_id AND pc.enabled
+WHERE p.id = $1
+ON CONFLICT (channel_id, project_id, issue_number) DO UPDATE SET
+  status = 'pending',
+  scheduled_for = EXCLUDED.scheduled_for,
+  attempts = 0,
+  last_error = NULL
+WHERE issue_promotions.status NOT IN ('sent', 'sending')
+`, projectID, issueNumber, at)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+// Cancel cancels the issue's pending deliveries and returns how many were cancelled.
+func Cancel(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) (int64, error) {
+	ct, err := pool.Exec(ctx, `
+UPDATE issue_promotions SET status = 'cancelled'
+WHERE project_id = $1 AND issue_number = $2 AND status = 'pending'
+`, projectID, issueNumber)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,76 @@
+package promotions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateURL(t *testing.T) {
+	ok := map[string]string{
+		KindDiscord: "https://discord.com/api/webhooks/1/abc",
+		KindSlack:   "https://hooks.slack.com/services/T/B/x",
+		KindWebhook: "https://relay.example.com/hook",
+	}
+	for kind, u := range ok {
+		if err := ValidateURL(kind, u); err != nil {
+			t.Errorf("%s %s: %v", kind, u, err)
+		}
+	}
+	bad := [][2]string{
+		{KindDiscord, "http://discord.com/api/webhooks/1/abc"},
+		{KindDiscord, "https://example.com/api/webhooks/1/abc"},
+		{KindSlack, "https://slack.com/services/T/B/x"},
+		{"twitter", "https://relay.example.com/hook"},
+	}
+	for _, b := range bad {
+		if err := ValidateURL(b[0], b[1]); err == nil {
+			t.Errorf("%s %s: accepted", b[0], b[1])
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	points := 5
+	m := Message{Repo: "acme/widgets", IssueNumber: 7, Title: "Fix the parser", URL: "https://github.com/acme/widgets/issues/7", Points: &points}
+	if got, want := Text(m), "New 5-point issue in acme/widgets: Fix the parser https://github.com/acme/widgets/issues/7"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+	m.Title = strings.Repeat("long ", 100)
+	got := Text(m)
+	if utf8.RuneCountInString(got) > maxTextLength || !strings.HasSuffix(got, "… "+m.URL) {
+		t.Errorf("long Text = %q (%d runes)", got, utf8.RuneCountInString(got))
+	}
+}
+
+func TestPayload(t *testing.T) {
+	m := Message{Repo: "acme/widgets", IssueNumber: 7, Title: "<@everyone> & co", URL: "https://github.com/acme/widgets/issues/7", Labels: []string{"bug"}}
+	for _, kind := range []string{KindDiscord, KindSlack, KindWebhook} {
+		body, err := Payload(kind, m)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		var v map[string]any
+		if err := json.Unmarshal(body, &v); err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		switch kind {
+		case KindDiscord:
+			if am, _ := v["allowed_mentions"].(map[string]any); am == nil {
+				t.Errorf("discord payload without allowed_mentions: %s", body)
+			}
+		case KindSlack:
+			if !strings.Contains(string(body), "\\u0026lt;@everyone\\u0026gt; \\u0026amp; co") {
+				t.Errorf("slack title not escaped: %s", body)
+			}
+		case KindWebhook:
+			if v["event"] != "issue.promoted" || v["issue_number"] != float64(7) {
+				t.Errorf("webhook payload = %s", body)
+			}
+		}
+	}
+	if _, err := Payload("twitter", m); err == nil {
+		t.Error("unknown kind accepted")
+	}
+}
@@ -0,0 +1,87 @@
+package publicstats
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Metrics lists the values a badge can show, with their default labels.
+var Metrics = map[string]string{
+	"contributors":     "contributors",
+	"open_issues":      "open issues",
+	"completed_issues": "completed issues",
+	"merged_prs":       "merged PRs",
+	"rewards_paid":     "rewards paid",
+	"projects":         "projects",
+	"open_bounties":    "open bounties",
+}
+
+// Shields is the response format of a shields.io endpoint badge
+// (https://shields.io/badges/endpoint-badge).
+type Shields struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	CacheSeconds  int    `json:"cacheSeconds,omitempty"`
+}
+
+// Badge renders metric of s for shields.io. ok is false for unknown metrics.
+func Badge(s Stats, metric string) (Shields, bool) {
+	label, ok := Metrics[metric]
+	if !ok {
+		return Shields{}, false
+	}
+	b := Shields{SchemaVersion: 1, Label: label, Color: "blue"}
+	switch metric {
+	case "contributors":
+		b.Message = Compact(int64(s.Contributors))
+	case "open_issues":
+		b.Message = Compact(int64(s.OpenIssues))
+		if s.OpenIssues > 0 {
+			b.Color = "brightgreen"
+		}
+	case "completed_issues":
+		b.Message = Compact(int64(s.CompletedIssues))
+	case "merged_prs":
+		b.Message = Compact(int64(s.MergedPRs))
+	case "projects":
+		b.Message = Compact(int64(s.Projects))
+	case "open_bounties":
+		b.Message, b.Color = Compact(int64(s.OpenBounties)), "lightgrey"
+		if s.OpenBounties > 0 {
+			b.Color = "brightgreen"
+		}
+		if s.OpenPoints > 0 {
+			b.Message += " | " + Compact(int64(s.OpenPoints)) + " pts"
+		}
+	case "rewards_paid":
+		if s.RewardsPaidUSD == nil {
+			b.Message, b.Color = "n/a", "lightgrey"
+		} else {
+			b.Message, b.Color = "$"+Compact(*s.RewardsPaidUSD), "brightgreen"
+		}
+	}
+	return b, true
+}
+
+// Compact formats n for a badge: 999, 1.2k, 34k, 5.6M.
+func Compact(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	for _, u := range []struct {
+		div    int64
+		suffix string
+	}{{1_000_000_000, "B"}, {1_000_000, "M"}, {1_000, "k"}} {
+		if n < u.div {
+			continue
+		}
+		if n < 10*u.div && n%u.div >= u.div/10 {
+			return fmt.Sprintf("%s%.1f%s", sign, float64(n/(u.div/10))/10, u.suffix)
+		}
+		return sign + strconv.FormatInt(n/u.div, 10) + u.suffix
+	}
+	return sign + strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,60 @@
+package publicstats
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEntries bounds the cache; keys come from unauthenticated URLs.
+const maxEntries = 10000
+
+// Cache keeps computed Stats for a fixed TTL. Lookups that fail (including ErrNotFound) aren't cached.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	stats   Stats
+	expires time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+// Get returns the cached Stats for key, calling load on a miss.
+func (c *Cache) Get(key string, load func() (Stats, error)) (Stats, error) {
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.stats, nil
+	}
+
+	s, err := load()
+	if err != nil {
+		return s, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxEntries {
+			c.entries = map[string]cacheEntry{}
+		}
+	}
+	c.entries[key] = cacheEntry{stats: s, expires: now.Add(c.ttl)}
+	return s, nil
+}
+
+// TTL is how long entries are kept, for Cache-Control headers.
+func (c *Cache) TTL() time.Duration { return c.ttl }
@@ -0,0 +1,79 @@
+package publicstats
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	for n, want := range map[int64]string{
+		0: "0", 999: "999", 1000: "1k", 1049: "1k", 1250: "1.2k", 9999: "9.9k", 12500: "12k",
+		1_500_000: "1.5M", 250_000_000: "250M", 3_000_000_000: "3B", -1250: "-1.2k",
+	} {
+		if got := Compact(n); got != want {
+			t.Errorf("Compact(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestBadge(t *testing.T) {
+	paid := int64(12500)
+	s := Stats{Contributors: 42, RewardsPaidUSD: &paid}
+	if b, ok := Badge(s, "contributors"); !ok || b.Message != "42" || b.Label != "contributors" || b.SchemaVersion != 1 {
+		t.Errorf("contributors badge = %+v", b)
+	}
+	if b, _ := Badge(s, "rewards_paid"); b.Message != "$12k" {
+		t.Errorf("rewards badge = %+v", b)
+	}
+	if b, _ := Badge(Stats{}, "rewards_paid"); b.Message != "n/a" {
+		t.Errorf("unattributed rewards badge = %+v", b)
+	}
+	if b, _ := Badge(Stats{OpenBounties: 3, OpenPoints: 1500}, "open_bounties"); b.Message != "3 | 1.5k pts" || b.Color != "brightgreen" {
+		t.Errorf("open bounties badge = %+v", b)
+	}
+	if b, _ := Badge(Stats{}, "open_bounties"); b.Message != "0" || b.Color != "lightgrey" {
+		t.Errorf("no open bounties badge = %+v", b)
+	}
+	if _, ok := Badge(s, "stars"); ok {
+		t.Error("unknown metric accepted")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	svg := string(SVG(Shields{Label: "a<b", Message: "3", Color: "brightgreen"}))
+	for _, want := range []string{`aria-label="a&lt;b: 3"`, `fill="#4c1"`, `<text x=`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg missing %q:\n%s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "a<b") {
+		t.Error("label not escaped")
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(time.Minute)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	calls := 0
+	load := func() (Stats, error) { calls++; return Stats{Contributors: calls}, nil }
+
+	c.Get("a", load)
+	if s, _ := c.Get("a", load); s.Contributors != 1 || calls != 1 {
+		t.Fatalf("cached value not reused: %+v, %d loads", s, calls)
+	}
+	now = now.Add(2 * time.Minute)
+	if s, _ := c.Get("a", load); s.Contributors != 2 {
+		t.Fatalf("expired value reused: %+v", s)
+	}
+
+	fail := func() (Stats, error) { return Stats{}, ErrNotFound }
+	if _, err := c.Get("b", fail); !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+	if _, err := c.Get("b", load); err != nil {
+		t.Fatal("error was cached")
+	}
+}
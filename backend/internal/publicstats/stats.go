@@ -0,0 +1,141 @@
+// Package publicstats computes the high-level numbers shown in README badges and embeddable widgets
+// (contributors, completed issues, merged PRs, rewards paid) for a project or an ecosystem. Results are
+// cached in memory because the endpoints are unauthenticated and badges are fetched on every page view.
+package publicstats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Stats counts only verified, non-deleted projects. Contributors are distinct GitHub authors of issues
+// and PRs, as on the landing page.
+type Stats struct {
+	Kind            string    `json:"kind"` // project|ecosystem
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Projects        int       `json:"projects"`
+	Contributors    int       `json:"contributors"`
+	OpenIssues      int       `json:"open_issues"`
+	CompletedIssues int       `json:"completed_issues"`
+	MergedPRs       int       `json:"merged_prs"`
+	// OpenBounties are open issues nobody is assigned to yet; OpenPoints sums their points.
+	OpenBounties int `json:"open_bounties"`
+	OpenPoints   int `json:"open_points"`
+	// RewardsPaidUSD is the sum of on-chain payouts from the ecosystem's program escrow. It is nil for
+	// projects and for ecosystems without a program_id, since those payouts can't be attributed.
+	RewardsPaidUSD *int64    `json:"rewards_paid_usd"`
+	GeneratedAt    time.Time `json:"generated_at"`
+}
+
+// Project returns the stats for a verified project by its "owner/repo" name.
+func Project(ctx context.Context, pool *pgxpool.Pool, fullName string) (Stats, error) {
+	return project(ctx, pool, `LOWER(github_full_name) = LOWER($1)`, fullName)
+}
+
+// ProjectByID returns the stats for a verified project by id.
+func ProjectByID(ctx context.Context, pool *pgxpool.Pool, id string) (Stats, error) {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return Stats{Kind: "project"}, ErrNotFound
+	}
+	return project(ctx, pool, `id = $1`, projectID)
+}
+
+func project(ctx context.Context, pool *pgxpool.Pool, where string, arg any) (Stats, error) {
+	s := Stats{Kind: "project", Projects: 1}
+	err := pool.QueryRow(ctx, `
+SELECT id, github_full_name
+FROM projects
+WHERE `+where+` AND status = 'verified' AND deleted_at IS NULL
+`, arg).Scan(&s.ID, &s.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	if err != nil {
+		return s, err
+	}
+	return s, aggregate(ctx, pool, &s, []uuid.UUID{s.ID})
+}
+
+// Ecosystem returns the stats for an active ecosystem by slug.
+func Ecosystem(ctx context.Context, pool *pgxpool.Pool, slug string) (Stats, error) {
+	s := Stats{Kind: "ecosystem"}
+	var programID *string
+	err := pool.QueryRow(ctx, `
+SELECT id, name, program_id
+FROM ecosystems
+WHERE slug = $1 AND status = 'active'
+`, slug).Scan(&s.ID, &s.Name, &programID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	if err != nil {
+		return s, err
+	}
+
+	rows, err := pool.Query(ctx, `
+SELECT id FROM projects WHERE ecosystem_id = $1 AND status = 'verified' AND deleted_at IS NULL
+`, s.ID)
+	if err != nil {
+		return s, err
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return s, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return s, err
+	}
+	s.Projects = len(ids)
+	if err := aggregate(ctx, pool, &s, ids); err != nil {
+		return s, err
+	}
+
+	if programID != nil && *programID != "" {
+		var paid int64
+		if err := pool.QueryRow(ctx, `
+SELECT COALESCE(SUM(amount), 0)
+FROM onchain_events
+WHERE topic IN ('f_rel', 'Payout', 'BatchPay') AND program_id = $1
+`, *programID).Scan(&paid); err != nil {
+			return s, err
+		}
+		s.RewardsPaidUSD = &paid
+	}
+	return s, nil
+}
+
+func aggregate(ctx context.Context, pool *pgxpool.Pool, s *Stats, projectIDs []uuid.UUID) error {
+	s.GeneratedAt = time.Now().UTC()
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	return pool.QueryRow(ctx, `
+WITH contributors AS (
+  SELECT author_login AS login FROM github_issues WHERE project_id = ANY($1)
+  UNION
+  SELECT author_login AS login FROM github_pull_requests WHERE project_id = ANY($1)
+)
+SELECT
+  (SELECT COUNT(DISTINCT LOWER(login)) FROM contributors WHERE login IS NOT NULL AND login <> ''),
+  (SELECT COUNT(*) FROM github_issues WHERE project_id = ANY($1) AND state = 'open'),
+  (SELECT COUNT(*) FROM github_issues WHERE project_id = ANY($1) AND state = 'closed'),
+  (SELECT COUNT(*) FROM github_pull_requests WHERE project_id = ANY($1) AND merged = true),
+  (SELECT COUNT(*) FROM github_issues WHERE project_id = ANY($1) AND state = 'open' AND COALESCE(jsonb_array_length(assignees), 0) = 0),
+  (SELECT COALESCE(SUM(points), 0) FROM github_issues WHERE project_id = ANY($1) AND state = 'open' AND COALESCE(jsonb_array_length(assignees), 0) = 0)
+`, projectIDs).Scan(&s.Contributors, &s.OpenIssues, &s.CompletedIssues, &s.MergedPRs, &s.OpenBounties, &s.OpenPoints)
+}
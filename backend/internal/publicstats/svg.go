@@ -0,0 +1,64 @@
+package publicstats
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+)
+
+var colors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"blue":        "#007ec6",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"lightgrey":   "#9f9f9f",
+}
+
+// SVG renders b as a flat badge in the shields.io style, for READMEs that embed the image directly.
+func SVG(b Shields) []byte {
+	color, ok := colors[b.Color]
+	if !ok {
+		color = colors["lightgrey"]
+	}
+	lw, mw := textWidth(b.Label)+10, textWidth(b.Message)+10
+	w := lw + mw
+	label, msg := html.EscapeString(b.Label), html.EscapeString(b.Message)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, w, label, msg)
+	fmt.Fprintf(&buf, `<title>%s: %s</title>`, label, msg)
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, w)
+	fmt.Fprintf(&buf, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`, lw, lw, mw, color, w)
+	buf.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, t := range []struct {
+		x    int
+		text string
+	}{{lw / 2, label}, {lw + mw/2, msg}} {
+		fmt.Fprintf(&buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, t.x, t.text, t.x, t.text)
+	}
+	buf.WriteString(`</g></svg>`)
+	return buf.Bytes()
+}
+
+// textWidth approximates the rendered width of s in 11px Verdana.
+func textWidth(s string) int {
+	w := 0.0
+	for _, r := range s {
+		switch {
+		case r == 'i' || r == 'l' || r == '.' || r == ',' || r == ':' || r == ';' || r == '|' || r == '\'' || r == '!':
+			w += 3.5
+		case r == ' ':
+			w += 3.9
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W':
+			w += 10.5
+		case r >= 'A' && r <= 'Z':
+			w += 7.6
+		default:
+			w += 7
+		}
+	}
+	return int(math.Ceil(w))
+}
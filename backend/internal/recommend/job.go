@@ -0,0 +1,290 @@
+package recommend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Interval is how often recommendations are recomputed.
+	Interval = 6 * time.Hour
+	// PerUser is how many recommendations are stored per contributor.
+	PerUser = 50
+	// historyDays is how far back contributions count towards a profile.
+	historyDays = 365
+	// maxCandidates caps the open issues considered (newest first).
+	maxCandidates = 5000
+)
+
+// Job recomputes issue_recommendations for every contributor with history or declared skills.
+type Job struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(15 * time.Minute)
+	defer t.Stop()
+	for {
+		if j.due(ctx) {
+			if err := j.Compute(ctx, time.Now()); err != nil {
+				slog.Error("issue recommendations failed", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (j *Job) due(ctx context.Context) bool {
+	var last *time.Time
+	if err := j.pool.QueryRow(ctx, `SELECT MAX(computed_at) FROM issue_recommendations`).Scan(&last); err != nil {
+		return false
+	}
+	return last == nil || time.Since(*last) >= Interval
+}
+
+// Compute scores the current open issues against every profile and replaces the stored results.
+func (j *Job) Compute(ctx context.Context, now time.Time) error {
+	started := time.Now()
+	candidates, err := j.candidates(ctx)
+	if err != nil {
+		return fmt.Errorf("candidates: %w", err)
+	}
+	profiles, err := j.profiles(ctx)
+	if err != nil {
+		return fmt.Errorf("profiles: %w", err)
+	}
+
+	stored := 0
+	for _, p := range profiles {
+		matches := Top(*p, candidates, PerUser, now)
+		if err := j.store(ctx, p.UserID, matches, now); err != nil {
+			slog.Error("failed to store issue recommendations", "user_id", p.UserID, "error", err)
+			continue
+		}
+		stored += len(matches)
+	}
+	// Contributors who no longer have a profile (or matches) lose their old recommendations.
+	if _, err := j.pool.Exec(ctx, `DELETE FROM issue_recommendations WHERE computed_at < $1`, now); err != nil {
+		return err
+	}
+	slog.Info("issue recommendations computed", "contributors", len(profiles), "candidates", len(candidates),
+		"stored", stored, "duration", time.Since(started))
+	return nil
+}
+
+func (j *Job) store(ctx context.Context, userID uuid.UUID, matches []Match, now time.Time) error {
+	return pgx.BeginFunc(ctx, j.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM issue_recommendations WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		rows := make([][]any, 0, len(matches))
+		for _, m := range matches {
+			reasons, _ := json.Marshal(m.Reasons)
+			rows = append(rows, []any{userID, m.IssueID, m.Score, reasons, now})
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"issue_recommendations"},
+			[]string{"user_id", "issue_id", "score", "reasons", "computed_at"}, pgx.CopyFromRows(rows))
+		return err
+	})
+}
+
+func (j *Job) candidates(ctx context.Context) ([]Candidate, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT gi.id, p.id, p.github_full_name, COALESCE(p.language, ''), p.ecosystem_id, COALESCE(e.name, ''),
+       COALESCE(gi.labels, '[]'::jsonb), COALESCE(p.tags, '[]'::jsonb), COALESCE(gi.points, 0),
+       COALESCE(gi.is_mentored, false), gi.created_at_github
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE gi.state = 'open' AND COALESCE(jsonb_array_length(gi.assignees), 0) = 0
+  AND p.status = 'verified' AND p.deleted_at IS NULL AND p.needs_metadata = false
+  AND (e.id IS NULL OR e.status = 'active')
+ORDER BY gi.created_at_github DESC NULLS LAST
+LIMIT $1
+`, maxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Candidate
+	for rows.Next() {
+		var c Candidate
+		var labelsJSON, tagsJSON []byte
+		var createdAt *time.Time
+		if err := rows.Scan(&c.IssueID, &c.ProjectID, &c.ProjectName, &c.Language, &c.EcosystemID, &c.EcosystemName,
+			&labelsJSON, &tagsJSON, &c.Points, &c.Mentored, &createdAt); err != nil {
+			return nil, err
+		}
+		c.Labels = LabelNames(labelsJSON)
+		c.Tags = LabelNames(tagsJSON)
+		if createdAt != nil {
+			c.CreatedAt = *createdAt
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (j *Job) profiles(ctx context.Context) (map[uuid.UUID]*Profile, error) {
+	profiles := map[uuid.UUID]*Profile{}
+	get := func(userID uuid.UUID, login string) *Profile {
+		p, ok := profiles[userID]
+		if !ok {
+			p = &Profile{UserID: userID, Login: login, Languages: map[string]int{}, Ecosystems: map[uuid.UUID]int{},
+				Labels: map[string]int{}, Projects: map[uuid.UUID]int{}, Applied: map[uuid.UUID]bool{}}
+			profiles[userID] = p
+		}
+		return p
+	}
+
+	rows, err := j.pool.Query(ctx, `
+SELECT ga.user_id, ga.login, p.id, LOWER(COALESCE(p.language, '')), p.ecosystem_id, COUNT(*)
+FROM github_pull_requests pr
+JOIN projects p ON p.id = pr.project_id AND p.deleted_at IS NULL
+JOIN github_accounts ga ON LOWER(ga.login) = LOWER(pr.author_login)
+WHERE pr.merged = true AND pr.merged_at_github >= now() - make_interval(days => $1)
+GROUP BY 1, 2, 3, 4, 5
+`, historyDays)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var userID, projectID uuid.UUID
+		var login, language string
+		var ecosystemID *uuid.UUID
+		var n int
+		if err := rows.Scan(&userID, &login, &projectID, &language, &ecosystemID, &n); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p := get(userID, login)
+		p.MergedPRs += n
+		p.Projects[projectID] += n
+		if language != "" {
+			p.Languages[language] += n
+		}
+		if ecosystemID != nil {
+			p.Ecosystems[*ecosystemID] += n
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = j.pool.Query(ctx, `
+SELECT ga.user_id, ga.login, COALESCE(gi.labels, '[]'::jsonb)
+FROM github_issues gi
+JOIN github_accounts ga ON gi.assignees @> jsonb_build_array(jsonb_build_object('login', ga.login))
+WHERE gi.state = 'closed' AND gi.closed_at_github >= now() - make_interval(days => $1)
+`, historyDays)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var userID uuid.UUID
+		var login string
+		var labelsJSON []byte
+		if err := rows.Scan(&userID, &login, &labelsJSON); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p := get(userID, login)
+		for _, l := range LabelNames(labelsJSON) {
+			p.Labels[strings.ToLower(l)]++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = j.pool.Query(ctx, `
+SELECT u.id, COALESCE(ga.login, ''), u.skills
+FROM users u
+LEFT JOIN github_accounts ga ON ga.user_id = u.id
+WHERE cardinality(u.skills) > 0
+`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var userID uuid.UUID
+		var login string
+		var declared []string
+		if err := rows.Scan(&userID, &login, &declared); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		get(userID, login).Skills = declared
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = j.pool.Query(ctx, `
+SELECT a.user_id, gi.id
+FROM issue_applications a
+JOIN github_issues gi ON gi.project_id = a.project_id AND gi.number = a.issue_number
+WHERE a.user_id IS NOT NULL
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID, issueID uuid.UUID
+		if err := rows.Scan(&userID, &issueID); err != nil {
+			return nil, err
+		}
+		if p, ok := profiles[userID]; ok {
+			p.Applied[issueID] = true
+		}
+	}
+	return profiles, rows.Err()
+}
+
+// LabelNames reads a github_issues.labels value: an array of label objects ({"name": ...}) or strings.
+func LabelNames(labelsJSON []byte) []string {
+	var raw []json.RawMessage
+	if json.Unmarshal(labelsJSON, &raw) != nil {
+		return nil
+	}
+	var out []string
+	for _, r := range raw {
+		var obj struct {
+			Name string `json:"name"`
+		}
+		var s string
+		if json.Unmarshal(r, &obj) == nil && obj.Name != "" {
+			out = append(out, obj.Name)
+		} else if json.Unmarshal(r, &s) == nil && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+// Package recommend ranks open, unassigned issues for each contributor by how well they match what the
+// contributor has done before: the languages and ecosystems of projects where their PRs were merged,
+// the labels of issues they completed, projects they already know, and the skills declared on their
+// profile (see package skills). A periodic job stores the top
+// matches with the reasons behind each, and GET /me/recommended-issues serves them.
+package recommend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/skills"
+)
+
+// Profile summarises a contributor's history. Maps count merged PRs (languages, ecosystems, projects)
+// or completed issues (labels).
+type Profile struct {
+	UserID     uuid.UUID
+	Login      string
+	Languages  map[string]int
+	Ecosystems map[uuid.UUID]int
+	Labels     map[string]int
+	Projects   map[uuid.UUID]int
+	MergedPRs  int
+	// Skills are the skill ids declared on the contributor's profile.
+	Skills []string
+	// Applied holds issues the contributor already applied to; they are never recommended.
+	Applied map[uuid.UUID]bool
+}
+
+// Candidate is an open, unassigned issue.
+type Candidate struct {
+	IssueID       uuid.UUID
+	ProjectID     uuid.UUID
+	ProjectName   string
+	Language      string
+	EcosystemID   *uuid.UUID
+	EcosystemName string
+	Labels        []string
+	Tags          []string
+	Points        int
+	Mentored      bool
+	CreatedAt     time.Time
+}
+
+// Reason explains one part of a recommendation's score.
+type Reason struct {
+	Kind   string `json:"kind"` // language|ecosystem|skills|labels|project|mentored|reward
+	Detail string `json:"detail,omitempty"`
+	Text   string `json:"text"`
+}
+
+// Match is a scored candidate.
+type Match struct {
+	IssueID uuid.UUID
+	Score   float64
+	Reasons []Reason
+}
+
+// Weights of each signal; a perfect language and ecosystem match outweighs everything else.
+const (
+	wLanguage  = 3.0
+	wEcosystem = 2.0
+	wSkills    = 2.0
+	wLabels    = 2.0
+	wProject   = 1.0
+	wMentored  = 0.5
+	wReward    = 0.5
+)
+
+// Score rates c for p. Candidates sharing nothing with the profile score 0 and get no reasons.
+func Score(p Profile, c Candidate, now time.Time) Match {
+	m := Match{IssueID: c.IssueID}
+	if p.MergedPRs == 0 && len(p.Labels) == 0 && len(p.Skills) == 0 {
+		return m
+	}
+	if n := p.Languages[strings.ToLower(c.Language)]; n > 0 && p.MergedPRs > 0 {
+		m.Score += wLanguage * float64(n) / float64(p.MergedPRs)
+		m.Reasons = append(m.Reasons, Reason{Kind: "language", Detail: c.Language,
+			Text: fmt.Sprintf("You've had %d %s PR%s merged", n, c.Language, plural(n))})
+	}
+	if c.EcosystemID != nil && p.MergedPRs > 0 {
+		if n := p.Ecosystems[*c.EcosystemID]; n > 0 {
+			m.Score += wEcosystem * float64(n) / float64(p.MergedPRs)
+			m.Reasons = append(m.Reasons, Reason{Kind: "ecosystem", Detail: c.EcosystemName,
+				Text: fmt.Sprintf("You've contributed %d PR%s in the %s ecosystem", n, plural(n), c.EcosystemName)})
+		}
+	}
+	if ids := skills.Match(p.Skills, c.Language, c.Labels, c.Tags); len(ids) > 0 {
+		names := strings.Join(skills.Names(ids), ", ")
+		m.Score += wSkills * min(float64(len(ids))/2, 1)
+		m.Reasons = append(m.Reasons, Reason{Kind: "skills", Detail: names,
+			Text: "Matches skills on your profile: " + names})
+	}
+	var matched []string
+	for _, l := range c.Labels {
+		if p.Labels[strings.ToLower(l)] > 0 {
+			matched = append(matched, l)
+		}
+	}
+	if len(matched) > 0 {
+		m.Score += wLabels * float64(len(matched)) / float64(len(c.Labels))
+		m.Reasons = append(m.Reasons, Reason{Kind: "labels", Detail: strings.Join(matched, ", "),
+			Text: "Labelled like issues you've completed: " + strings.Join(matched, ", ")})
+	}
+	if n := p.Projects[c.ProjectID]; n > 0 {
+		m.Score += wProject
+		m.Reasons = append(m.Reasons, Reason{Kind: "project", Detail: c.ProjectName,
+			Text: fmt.Sprintf("You've already contributed to %s", c.ProjectName)})
+	}
+	if m.Score == 0 {
+		return m
+	}
+	if c.Mentored {
+		m.Score += wMentored
+		m.Reasons = append(m.Reasons, Reason{Kind: "mentored", Text: "A mentor is available for this issue"})
+	}
+	if c.Points > 0 {
+		m.Score += wReward * min(float64(c.Points)/100, 1)
+		m.Reasons = append(m.Reasons, Reason{Kind: "reward", Detail: fmt.Sprint(c.Points),
+			Text: fmt.Sprintf("Worth %d point%s", c.Points, plural(c.Points))})
+	}
+	// Prefer fresh issues: lose up to a quarter of the score over 90 days.
+	if age := now.Sub(c.CreatedAt); !c.CreatedAt.IsZero() && age > 0 {
+		m.Score *= 1 - 0.25*min(age.Hours()/(90*24), 1)
+	}
+	return m
+}
+
+// Top returns the best n matches for p, highest score first, skipping issues p applied to.
+func Top(p Profile, candidates []Candidate, n int, now time.Time) []Match {
+	var out []Match
+	for _, c := range candidates {
+		if p.Applied[c.IssueID] {
+			continue
+		}
+		if m := Score(p, c, now); m.Score > 0 {
+			out = append(out, m)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
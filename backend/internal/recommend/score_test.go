@@ -0,0 +1,85 @@
+package recommend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTop(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	stellar, other := uuid.New(), uuid.New()
+	known := uuid.New()
+	p := Profile{
+		MergedPRs:  10,
+		Languages:  map[string]int{"go": 8, "rust": 2},
+		Ecosystems: map[uuid.UUID]int{stellar: 10},
+		Labels:     map[string]int{"backend": 3},
+		Projects:   map[uuid.UUID]int{known: 4},
+		Applied:    map[uuid.UUID]bool{},
+	}
+	goStellar := Candidate{IssueID: uuid.New(), ProjectID: known, ProjectName: "acme/api", Language: "Go",
+		EcosystemID: &stellar, EcosystemName: "Stellar", Labels: []string{"Backend", "docs"}, Points: 50, CreatedAt: now}
+	rustOther := Candidate{IssueID: uuid.New(), ProjectID: uuid.New(), Language: "Rust", EcosystemID: &other, CreatedAt: now}
+	unrelated := Candidate{IssueID: uuid.New(), ProjectID: uuid.New(), Language: "Haskell", Points: 500, Mentored: true, CreatedAt: now}
+	applied := Candidate{IssueID: uuid.New(), ProjectID: known, Language: "Go", CreatedAt: now}
+	p.Applied[applied.IssueID] = true
+
+	got := Top(p, []Candidate{rustOther, unrelated, applied, goStellar}, 10, now)
+	if len(got) != 2 || got[0].IssueID != goStellar.IssueID || got[1].IssueID != rustOther.IssueID {
+		t.Fatalf("Top = %+v", got)
+	}
+	var kinds []string
+	for _, r := range got[0].Reasons {
+		kinds = append(kinds, r.Kind)
+	}
+	if want := []string{"language", "ecosystem", "labels", "project", "reward"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("reasons = %v, want %v", kinds, want)
+	}
+	if got[0].Reasons[2].Detail != "Backend" {
+		t.Errorf("labels reason = %+v", got[0].Reasons[2])
+	}
+
+	if got := Top(p, []Candidate{goStellar}, 0, now); len(got) != 0 {
+		t.Error("limit 0 returned matches")
+	}
+	if got := Top(Profile{}, []Candidate{goStellar}, 10, now); len(got) != 0 {
+		t.Errorf("empty profile got %+v", got)
+	}
+}
+
+func TestScoreAgeDecay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	p := Profile{MergedPRs: 1, Languages: map[string]int{"go": 1}}
+	fresh := Score(p, Candidate{Language: "Go", CreatedAt: now}, now)
+	old := Score(p, Candidate{Language: "Go", CreatedAt: now.AddDate(-1, 0, 0)}, now)
+	if fresh.Score != 3 || old.Score != 2.25 {
+		t.Errorf("fresh = %v, old = %v", fresh.Score, old.Score)
+	}
+}
+
+func TestScoreSkills(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	// A newcomer with declared skills but no history still gets matches.
+	p := Profile{Skills: []string{"rust", "soroban"}}
+	c := Candidate{Language: "Rust", Labels: []string{"soroban"}, CreatedAt: now}
+	m := Score(p, c, now)
+	if m.Score != 2 || len(m.Reasons) != 1 || m.Reasons[0].Kind != "skills" || m.Reasons[0].Detail != "Rust, Soroban" {
+		t.Errorf("Score = %+v", m)
+	}
+	if m := Score(p, Candidate{Language: "Go", Tags: []string{"react"}, CreatedAt: now}, now); m.Score != 0 {
+		t.Errorf("unrelated candidate scored %+v", m)
+	}
+}
+
+func TestLabelNames(t *testing.T) {
+	got := LabelNames([]byte(`[{"name":"bug","color":"f00"},"help wanted",{"color":"0f0"}]`))
+	if !reflect.DeepEqual(got, []string{"bug", "help wanted"}) {
+		t.Errorf("got %v", got)
+	}
+	if LabelNames([]byte(`null`)) != nil {
+		t.Error("null labels")
+	}
+}
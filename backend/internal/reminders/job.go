@@ -0,0 +1,275 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/mailer"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// Interval is how often due reminders are looked for; a reminder goes out at most this late.
+const Interval = 15 * time.Minute
+
+// sentRetention is how long sent reminders are remembered: longer than any reminder can stay due.
+const sentRetention = 60 * 24 * time.Hour
+
+// Job sends due reminders. Each is claimed in reminders_sent before it is sent, so with several instances
+// running only one sends it.
+type Job struct {
+	cfg    config.Config
+	pool   *pgxpool.Pool
+	mailer mailer.Mailer
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool, m mailer.Mailer) *Job {
+	return &Job{cfg: cfg, pool: pool, mailer: m}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(Interval)
+	defer t.Stop()
+	for {
+		if err := j.SendDue(ctx, time.Now()); err != nil {
+			slog.Error("reminders failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reminder is one due reminder for one user.
+type reminder struct {
+	userID   uuid.UUID
+	prefs    Preferences
+	loc      *time.Location
+	kind     string
+	ref      string
+	due      time.Time // when the user's lead time says to send it
+	payload  map[string]any
+	subject  string
+	text     string
+	linkURL  string
+	linkText string
+}
+
+// SendDue sends every reminder that is due at now and not held back by quiet hours.
+func (j *Job) SendDue(ctx context.Context, now time.Time) error {
+	deadlines, err := j.deadlineReminders(ctx, now)
+	if err != nil {
+		return fmt.Errorf("deadline reminders: %w", err)
+	}
+	reviews, err := j.pendingReviewReminders(ctx, now)
+	if err != nil {
+		return fmt.Errorf("pending review reminders: %w", err)
+	}
+
+	sent := 0
+	for _, r := range append(deadlines, reviews...) {
+		rule := r.prefs.Deadline
+		if r.kind == KindPendingReview {
+			rule = r.prefs.PendingReview
+		}
+		if !rule.Enabled || now.Before(r.due) || r.prefs.QuietAt(now, r.loc) {
+			continue
+		}
+		ok, err := j.send(ctx, r, rule)
+		if err != nil {
+			slog.Error("failed to send reminder", "user_id", r.userID, "kind", r.kind, "ref", r.ref, "error", err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	_, _ = j.pool.Exec(ctx, `DELETE FROM reminders_sent WHERE sent_at < $1`, now.Add(-sentRetention))
+	if sent > 0 {
+		slog.Info("reminders sent", "count", sent)
+	}
+	return nil
+}
+
+// deadlineReminders returns a reminder for each assignee of an open issue whose deadline is within the
+// longest lead time; SendDue keeps those within the assignee's own.
+func (j *Job) deadlineReminders(ctx context.Context, now time.Time) ([]reminder, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT ref, user_id, reminder_preferences, timezone, project_id, github_full_name, number, title, url, deadline_at
+FROM (
+  SELECT gi.project_id::text || '#' || gi.number::text || '@' || EXTRACT(EPOCH FROM gi.deadline_at)::bigint::text AS ref,
+         ga.user_id, u.reminder_preferences, COALESCE(u.availability->>'timezone', '') AS timezone,
+         gi.project_id, p.github_full_name, gi.number, COALESCE(gi.title, '') AS title, COALESCE(gi.url, '') AS url,
+         gi.deadline_at
+  FROM github_issues gi
+  JOIN projects p ON p.id = gi.project_id
+  CROSS JOIN LATERAL jsonb_array_elements(COALESCE(gi.assignees, '[]'::jsonb)) x
+  JOIN github_accounts ga ON LOWER(ga.login) = LOWER(x->>'login')
+  JOIN users u ON u.id = ga.user_id
+  WHERE gi.state = 'open' AND gi.deadline_at > $1 AND gi.deadline_at <= $1 + make_interval(hours => $2)
+    AND p.status = 'verified' AND p.deleted_at IS NULL
+) d
+WHERE NOT EXISTS (SELECT 1 FROM reminders_sent r WHERE r.user_id = d.user_id AND r.kind = $3 AND r.ref = d.ref)
+`, now, MaxLeadHours, KindDeadline)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []reminder
+	for rows.Next() {
+		var r reminder
+		var prefsJSON []byte
+		var timezone, fullName, title, issueURL string
+		var projectID uuid.UUID
+		var number int
+		var deadline time.Time
+		if err := rows.Scan(&r.ref, &r.userID, &prefsJSON, &timezone, &projectID, &fullName, &number, &title, &issueURL, &deadline); err != nil {
+			return nil, err
+		}
+		r.prefs, _ = Parse(prefsJSON)
+		r.loc = location(timezone)
+		r.kind = KindDeadline
+		r.due = deadline.Add(-time.Duration(r.prefs.Deadline.LeadHours) * time.Hour)
+		r.payload = map[string]any{
+			"project_id":       projectID.String(),
+			"github_full_name": fullName,
+			"issue_number":     number,
+			"issue_title":      title,
+			"deadline_at":      deadline,
+		}
+		r.subject = fmt.Sprintf("Deadline approaching: %s#%d", fullName, number)
+		r.text = fmt.Sprintf("Your assignment %s#%d (%s) is due %s.", fullName, number, title, deadline.UTC().Format("Mon Jan 2 15:04 MST"))
+		r.linkURL, r.linkText = issueURL, "Open the issue"
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// pendingReviewReminders returns a reminder for each project owner with applications to an issue that
+// have waited at least an hour without review; SendDue keeps those that waited the owner's lead time. A
+// reminder covers the issue's oldest waiting application, so deciding it makes way for a new reminder.
+func (j *Job) pendingReviewReminders(ctx context.Context, now time.Time) ([]reminder, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT ref, user_id, reminder_preferences, timezone, project_id, github_full_name, issue_number, waiting, oldest
+FROM (
+  SELECT a.project_id::text || '#' || a.issue_number::text || '@' || (array_agg(a.id::text ORDER BY a.created_at))[1] AS ref,
+         p.owner_user_id AS user_id, u.reminder_preferences, COALESCE(u.availability->>'timezone', '') AS timezone,
+         a.project_id, p.github_full_name, a.issue_number, COUNT(*) AS waiting, MIN(a.created_at) AS oldest
+  FROM issue_applications a
+  JOIN projects p ON p.id = a.project_id
+  JOIN users u ON u.id = p.owner_user_id
+  WHERE a.status = 'pending' AND a.held_at IS NULL AND a.reviewed_at IS NULL AND a.created_at <= $1 - interval '1 hour'
+    AND p.status = 'verified' AND p.deleted_at IS NULL
+  GROUP BY a.project_id, a.issue_number, p.id, u.id
+) w
+WHERE NOT EXISTS (SELECT 1 FROM reminders_sent r WHERE r.user_id = w.user_id AND r.kind = $2 AND r.ref = w.ref)
+`, now, KindPendingReview)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dashboardURL := ""
+	if j.cfg.FrontendBaseURL != "" {
+		dashboardURL = strings.TrimSuffix(j.cfg.FrontendBaseURL, "/") + "/dashboard"
+	}
+	var out []reminder
+	for rows.Next() {
+		var r reminder
+		var prefsJSON []byte
+		var timezone, fullName string
+		var projectID uuid.UUID
+		var number, waiting int
+		var oldest time.Time
+		if err := rows.Scan(&r.ref, &r.userID, &prefsJSON, &timezone, &projectID, &fullName, &number, &waiting, &oldest); err != nil {
+			return nil, err
+		}
+		r.prefs, _ = Parse(prefsJSON)
+		r.loc = location(timezone)
+		r.kind = KindPendingReview
+		r.due = oldest.Add(time.Duration(r.prefs.PendingReview.LeadHours) * time.Hour)
+		r.payload = map[string]any{
+			"project_id":       projectID.String(),
+			"github_full_name": fullName,
+			"issue_number":     number,
+			"waiting":          waiting,
+			"oldest_at":        oldest,
+		}
+		r.subject = fmt.Sprintf("Applications waiting for review: %s#%d", fullName, number)
+		r.text = fmt.Sprintf("%d application(s) to %s#%d are waiting for your review, the oldest since %s.",
+			waiting, fullName, number, oldest.UTC().Format("Mon Jan 2 15:04 MST"))
+		r.linkURL, r.linkText = dashboardURL, "Review applications"
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// send claims r and delivers it on the rule's channels. It reports false if another instance had it.
+// Email goes to the address of the user's most recent email sign-in; users without one get the reminder
+// in-app instead, so it isn't lost.
+func (j *Job) send(ctx context.Context, r reminder, rule Rule) (bool, error) {
+	var sentAt time.Time
+	err := j.pool.QueryRow(ctx, `
+INSERT INTO reminders_sent (user_id, kind, ref) VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+RETURNING sent_at
+`, r.userID, r.kind, r.ref).Scan(&sentAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	inApp := rule.Has(ChannelInApp)
+	if rule.Has(ChannelEmail) {
+		var email string
+		err := j.pool.QueryRow(ctx, `
+SELECT email FROM user_identities WHERE user_id = $1 ORDER BY last_login_at DESC LIMIT 1
+`, r.userID).Scan(&email)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			inApp = true
+		case err != nil:
+			return true, err
+		default:
+			text := r.text
+			if r.linkURL != "" {
+				text += "\n\n" + r.linkText + ": " + r.linkURL
+			}
+			text += "\n\nChange when and how you get reminders in your Grainlify settings."
+			if err := j.mailer.Send(ctx, mailer.Message{To: []string{email}, Subject: r.subject, Text: text}); err != nil {
+				slog.Warn("failed to email reminder, sending it in-app", "user_id", r.userID, "kind", r.kind, "error", err)
+				inApp = true
+			}
+		}
+	}
+	if inApp {
+		if err := notify.Store(ctx, j.pool, r.userID, r.kind, r.payload); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func location(name string) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	return time.UTC
+}
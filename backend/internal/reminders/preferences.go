@@ -0,0 +1,146 @@
+// Package reminders sends reminders ahead of assignment deadlines (to the assignee) and about applications
+// waiting for review (to the project owner), on each user's terms: which reminders, by which channels, how
+// long in advance, and never during their quiet hours.
+package reminders
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reminder kinds, also the notification kinds of in-app reminders.
+const (
+	KindDeadline      = "deadline_approaching"
+	KindPendingReview = "applications_pending_review"
+)
+
+// Channels.
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+)
+
+// MaxLeadHours bounds how early (deadlines) or late (pending reviews) a reminder can be set.
+const MaxLeadHours = 14 * 24
+
+const clockLayout = "15:04"
+
+// Rule configures one kind of reminder. For deadlines LeadHours is how long before the deadline to remind;
+// for pending reviews it is how long an application has waited.
+type Rule struct {
+	Enabled   bool     `json:"enabled"`
+	Channels  []string `json:"channels"`
+	LeadHours int      `json:"lead_hours"`
+}
+
+// QuietHours is a daily range, "HH:MM" to "HH:MM" in the preferences' time zone, during which no reminder
+// is sent; it may wrap past midnight. Reminders due during quiet hours go out when they end.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type Preferences struct {
+	// TimeZone is an IANA zone for quiet hours. Empty uses the time zone of the user's availability.
+	TimeZone      string      `json:"timezone"`
+	Quiet         *QuietHours `json:"quiet_hours"`
+	Deadline      Rule        `json:"deadline"`
+	PendingReview Rule        `json:"pending_review"`
+}
+
+// Default is assumed for users who never set preferences: both reminders, in-app, a day ahead of
+// deadlines and after three days of waiting for review, no quiet hours.
+func Default() Preferences {
+	return Preferences{
+		Deadline:      Rule{Enabled: true, Channels: []string{ChannelInApp}, LeadHours: 24},
+		PendingReview: Rule{Enabled: true, Channels: []string{ChannelInApp}, LeadHours: 72},
+	}
+}
+
+// Validate checks p and normalises it in place.
+func (p *Preferences) Validate() error {
+	p.TimeZone = strings.TrimSpace(p.TimeZone)
+	if p.TimeZone != "" {
+		if _, err := time.LoadLocation(p.TimeZone); err != nil {
+			return fmt.Errorf("unknown time zone %q", p.TimeZone)
+		}
+	}
+	if p.Quiet != nil {
+		p.Quiet.Start, p.Quiet.End = strings.TrimSpace(p.Quiet.Start), strings.TrimSpace(p.Quiet.End)
+		_, err1 := time.Parse(clockLayout, p.Quiet.Start)
+		_, err2 := time.Parse(clockLayout, p.Quiet.End)
+		if err1 != nil || err2 != nil || p.Quiet.Start == p.Quiet.End {
+			return fmt.Errorf("invalid quiet hours %s-%s", p.Quiet.Start, p.Quiet.End)
+		}
+	}
+	for _, rule := range []struct {
+		name string
+		r    *Rule
+	}{{"deadline", &p.Deadline}, {"pending_review", &p.PendingReview}} {
+		name, r := rule.name, rule.r
+		if r.LeadHours < 1 || r.LeadHours > MaxLeadHours {
+			return fmt.Errorf("%s: lead_hours must be between 1 and %d", name, MaxLeadHours)
+		}
+		channels := []string{}
+		seen := map[string]bool{}
+		for _, c := range r.Channels {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if c != ChannelInApp && c != ChannelEmail {
+				return fmt.Errorf("%s: unknown channel %q", name, c)
+			}
+			if !seen[c] {
+				seen[c] = true
+				channels = append(channels, c)
+			}
+		}
+		if r.Enabled && len(channels) == 0 {
+			return fmt.Errorf("%s: at least one channel is required", name)
+		}
+		r.Channels = channels
+	}
+	return nil
+}
+
+// Has reports whether r sends on channel.
+func (r Rule) Has(channel string) bool {
+	for _, c := range r.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// QuietAt reports whether t falls in p's quiet hours, in loc (p's own time zone when it has one).
+func (p Preferences) QuietAt(t time.Time, loc *time.Location) bool {
+	if p.Quiet == nil {
+		return false
+	}
+	if p.TimeZone != "" {
+		if l, err := time.LoadLocation(p.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := t.In(loc).Format(clockLayout)
+	if p.Quiet.Start < p.Quiet.End {
+		return now >= p.Quiet.Start && now < p.Quiet.End
+	}
+	return now >= p.Quiet.Start || now < p.Quiet.End
+}
+
+// Parse decodes a users.reminder_preferences value, falling back to the defaults when unset.
+func Parse(raw []byte) (Preferences, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return Default(), false
+	}
+	var p Preferences
+	if err := json.Unmarshal(raw, &p); err != nil || p.Validate() != nil {
+		return Default(), false
+	}
+	return p, true
+}
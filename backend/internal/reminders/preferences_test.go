@@ -0,0 +1,68 @@
+package reminders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	p := Default()
+	p.Deadline.Channels = []string{" Email ", "in_app", "email"}
+	p.Quiet = &QuietHours{Start: "22:00", End: "07:30"}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if got := p.Deadline.Channels; len(got) != 2 || got[0] != ChannelEmail || got[1] != ChannelInApp {
+		t.Errorf("channels = %v", got)
+	}
+
+	for name, mutate := range map[string]func(*Preferences){
+		"time zone":     func(p *Preferences) { p.TimeZone = "Mars/Olympus" },
+		"quiet hours":   func(p *Preferences) { p.Quiet = &QuietHours{Start: "25:00", End: "07:00"} },
+		"empty quiet":   func(p *Preferences) { p.Quiet = &QuietHours{Start: "07:00", End: "07:00"} },
+		"lead hours":    func(p *Preferences) { p.PendingReview.LeadHours = 0 },
+		"channel":       func(p *Preferences) { p.Deadline.Channels = []string{"sms"} },
+		"no channel":    func(p *Preferences) { p.Deadline.Channels = nil },
+		"too far ahead": func(p *Preferences) { p.Deadline.LeadHours = MaxLeadHours + 1 },
+	} {
+		p := Default()
+		mutate(&p)
+		if err := p.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil", name)
+		}
+	}
+
+	off := Default()
+	off.Deadline = Rule{Enabled: false, LeadHours: 24}
+	if err := off.Validate(); err != nil {
+		t.Errorf("disabled rule without channels: %v", err)
+	}
+}
+
+func TestQuietAt(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2026, 5, 4, hour, min, 0, 0, time.UTC) }
+
+	overnight := Preferences{Quiet: &QuietHours{Start: "22:00", End: "07:00"}}
+	for tm, want := range map[time.Time]bool{
+		at(23, 0): true,
+		at(3, 0):  true,
+		at(7, 0):  false,
+		at(12, 0): false,
+		at(22, 0): true,
+	} {
+		if got := overnight.QuietAt(tm, time.UTC); got != want {
+			t.Errorf("overnight QuietAt(%s) = %v, want %v", tm.Format("15:04"), got, want)
+		}
+	}
+
+	daytime := Preferences{Quiet: &QuietHours{Start: "09:00", End: "17:00"}, TimeZone: "Asia/Tokyo"}
+	if !daytime.QuietAt(at(1, 0), time.UTC) { // 10:00 in Tokyo
+		t.Error("daytime quiet hours ignored the preferences' time zone")
+	}
+	if daytime.QuietAt(at(12, 0), time.UTC) { // 21:00 in Tokyo
+		t.Error("quiet outside the range")
+	}
+	if (Preferences{}).QuietAt(at(3, 0), time.UTC) {
+		t.Error("no quiet hours set but quiet")
+	}
+}
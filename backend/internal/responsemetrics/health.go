@@ -0,0 +1,64 @@
+package responsemetrics
+
+import "math"
+
+// HealthInputs are the per-project signals combined into a health score.
+type HealthInputs struct {
+	MedianDecisionSeconds    *int64
+	MedianFirstReviewSeconds *int64
+	OpenUnassignedIssues     int
+	MergedPRs                int
+	ClosedUnmergedPRs        int
+	RecentActivity           int // issues and PRs opened plus PRs merged in the last 30 days
+}
+
+// Component weights; they add up to 100.
+const (
+	weightResponsiveness = 30
+	weightIssueSupply    = 25
+	weightMergeRate      = 25
+	weightActivity       = 20
+)
+
+// HealthScore combines responsiveness, open-unassigned issue supply, merge rate, and recent
+// activity into a 0-100 score. Signals with no data count as neutral (half marks).
+func HealthScore(in HealthInputs) int {
+	responsiveness := 0.5
+	var parts []float64
+	for _, secs := range []*int64{in.MedianDecisionSeconds, in.MedianFirstReviewSeconds} {
+		if secs != nil {
+			parts = append(parts, responseScore(*secs))
+		}
+	}
+	if len(parts) > 0 {
+		sum := 0.0
+		for _, p := range parts {
+			sum += p
+		}
+		responsiveness = sum / float64(len(parts))
+	}
+
+	supply := math.Min(float64(in.OpenUnassignedIssues)/10, 1)
+
+	mergeRate := 0.5
+	if total := in.MergedPRs + in.ClosedUnmergedPRs; total > 0 {
+		mergeRate = float64(in.MergedPRs) / float64(total)
+	}
+
+	activity := math.Min(float64(in.RecentActivity)/20, 1)
+
+	score := responsiveness*weightResponsiveness + supply*weightIssueSupply + mergeRate*weightMergeRate + activity*weightActivity
+	return int(math.Round(score))
+}
+
+// responseScore is 1 for a response within a day, falling linearly to 0 at two weeks.
+func responseScore(secs int64) float64 {
+	const day = 24 * 60 * 60
+	if secs <= day {
+		return 1
+	}
+	if secs >= 14*day {
+		return 0
+	}
+	return 1 - float64(secs-day)/float64(13*day)
+}
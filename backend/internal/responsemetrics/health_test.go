@@ -0,0 +1,32 @@
+package responsemetrics
+
+import "testing"
+
+func TestHealthScore(t *testing.T) {
+	hour := int64(3600)
+	twoWeeks := int64(14 * 24 * 3600)
+
+	if got := HealthScore(HealthInputs{}); got != 28 {
+		// neutral responsiveness (15) + neutral merge rate (12.5) rounds to 28
+		t.Errorf("empty project score = %d, want 28", got)
+	}
+
+	healthy := HealthInputs{
+		MedianDecisionSeconds:    &hour,
+		MedianFirstReviewSeconds: &hour,
+		OpenUnassignedIssues:     12,
+		MergedPRs:                9,
+		ClosedUnmergedPRs:        1,
+		RecentActivity:           40,
+	}
+	if got := HealthScore(healthy); got != 98 {
+		t.Errorf("healthy score = %d, want 98", got)
+	}
+
+	slow := healthy
+	slow.MedianDecisionSeconds = &twoWeeks
+	slow.MedianFirstReviewSeconds = &twoWeeks
+	if got := HealthScore(slow); got != 68 {
+		t.Errorf("slow score = %d, want 68", got)
+	}
+}
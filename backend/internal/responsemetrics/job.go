@@ -0,0 +1,165 @@
+// Package responsemetrics is the nightly per-project aggregation: how quickly maintainers respond
+// (median time from application to decision and from PR opened to first review) and a health score
+// combining responsiveness with issue supply, merge rate, and recent activity.
+package responsemetrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WindowDays is the trailing window the metrics are computed over.
+const WindowDays = 90
+
+// runHourUTC is the hour of day the nightly recomputation happens.
+const runHourUTC = 2
+
+// Job recomputes project_response_metrics once a night.
+type Job struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Job {
+	return &Job{pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	t := time.NewTicker(15 * time.Minute)
+	defer t.Stop()
+
+	for {
+		if j.due(ctx) {
+			if err := j.Compute(ctx); err != nil {
+				slog.Error("response metrics computation failed", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// due reports whether metrics are missing, or it is past the nightly hour and they were last computed before it.
+func (j *Job) due(ctx context.Context) bool {
+	var last *time.Time
+	if err := j.pool.QueryRow(ctx, `SELECT MAX(computed_at) FROM project_response_metrics`).Scan(&last); err != nil {
+		return false
+	}
+	if last == nil {
+		return true
+	}
+	now := time.Now().UTC()
+	todaysRun := time.Date(now.Year(), now.Month(), now.Day(), runHourUTC, 0, 0, 0, time.UTC)
+	return now.After(todaysRun) && last.Before(todaysRun)
+}
+
+// Compute recomputes metrics for all verified projects in a single statement.
+func (j *Job) Compute(ctx context.Context) error {
+	started := time.Now()
+	ct, err := j.pool.Exec(ctx, `
+WITH scoped AS (
+  SELECT id FROM projects WHERE status = 'verified' AND deleted_at IS NULL
+),
+decisions AS (
+  SELECT a.project_id, EXTRACT(EPOCH FROM (a.decided_at - a.created_at)) AS secs
+  FROM issue_applications a
+  JOIN scoped s ON s.id = a.project_id
+  WHERE a.decided_at IS NOT NULL AND a.decided_at >= now() - make_interval(days => $1)
+),
+first_reviews AS (
+  SELECT pr.project_id, EXTRACT(EPOCH FROM (MIN(r.submitted_at_github) - pr.created_at_github)) AS secs
+  FROM github_pull_requests pr
+  JOIN scoped s ON s.id = pr.project_id
+  JOIN github_pr_reviews r ON r.project_id = pr.project_id AND r.pr_number = pr.number
+  WHERE pr.created_at_github >= now() - make_interval(days => $1)
+    AND r.submitted_at_github IS NOT NULL
+    AND LOWER(r.reviewer_login) <> LOWER(COALESCE(pr.author_login, ''))
+    AND r.reviewer_login NOT LIKE '%[bot]'
+  GROUP BY pr.project_id, pr.id, pr.created_at_github
+)
+INSERT INTO project_response_metrics (project_id, window_days, median_application_decision_seconds, application_decisions_count,
+  median_first_review_seconds, reviewed_prs_count, open_unassigned_issues, merged_prs_count, closed_unmerged_prs_count,
+  recent_activity_count, computed_at)
+SELECT
+  s.id,
+  $1,
+  (SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY d.secs) FROM decisions d WHERE d.project_id = s.id)::bigint,
+  (SELECT COUNT(*) FROM decisions d WHERE d.project_id = s.id),
+  (SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY f.secs) FROM first_reviews f WHERE f.project_id = s.id AND f.secs >= 0)::bigint,
+  (SELECT COUNT(*) FROM first_reviews f WHERE f.project_id = s.id AND f.secs >= 0),
+  (SELECT COUNT(*) FROM github_issues gi
+   WHERE gi.project_id = s.id AND gi.state = 'open' AND COALESCE(jsonb_array_length(gi.assignees), 0) = 0),
+  (SELECT COUNT(*) FROM github_pull_requests pr
+   WHERE pr.project_id = s.id AND pr.merged AND pr.merged_at_github >= now() - make_interval(days => $1)),
+  (SELECT COUNT(*) FROM github_pull_requests pr
+   WHERE pr.project_id = s.id AND pr.state = 'closed' AND NOT pr.merged AND pr.closed_at_github >= now() - make_interval(days => $1)),
+  (SELECT COUNT(*) FROM github_issues gi WHERE gi.project_id = s.id AND gi.created_at_github >= now() - interval '30 days')
+  + (SELECT COUNT(*) FROM github_pull_requests pr WHERE pr.project_id = s.id AND pr.created_at_github >= now() - interval '30 days')
+  + (SELECT COUNT(*) FROM github_pull_requests pr WHERE pr.project_id = s.id AND pr.merged_at_github >= now() - interval '30 days'),
+  now()
+FROM scoped s
+ON CONFLICT (project_id) DO UPDATE SET
+  window_days = EXCLUDED.window_days,
+  median_application_decision_seconds = EXCLUDED.median_application_decision_seconds,
+  application_decisions_count = EXCLUDED.application_decisions_count,
+  median_first_review_seconds = EXCLUDED.median_first_review_seconds,
+  reviewed_prs_count = EXCLUDED.reviewed_prs_count,
+  open_unassigned_issues = EXCLUDED.open_unassigned_issues,
+  merged_prs_count = EXCLUDED.merged_prs_count,
+  closed_unmerged_prs_count = EXCLUDED.closed_unmerged_prs_count,
+  recent_activity_count = EXCLUDED.recent_activity_count,
+  computed_at = EXCLUDED.computed_at
+`, WindowDays)
+	if err != nil {
+		return err
+	}
+	if err := j.scoreHealth(ctx); err != nil {
+		return fmt.Errorf("health score: %w", err)
+	}
+	slog.Info("response metrics computed", "projects", ct.RowsAffected(), "duration", time.Since(started).String())
+	return nil
+}
+
+// scoreHealth derives health_score from the freshly computed metrics.
+func (j *Job) scoreHealth(ctx context.Context) error {
+	rows, err := j.pool.Query(ctx, `
+SELECT project_id, median_application_decision_seconds, median_first_review_seconds,
+       open_unassigned_issues, merged_prs_count, closed_unmerged_prs_count, recent_activity_count
+FROM project_response_metrics
+`)
+	if err != nil {
+		return err
+	}
+	scores := map[uuid.UUID]int{}
+	for rows.Next() {
+		var id uuid.UUID
+		var in HealthInputs
+		if err := rows.Scan(&id, &in.MedianDecisionSeconds, &in.MedianFirstReviewSeconds,
+			&in.OpenUnassignedIssues, &in.MergedPRs, &in.ClosedUnmergedPRs, &in.RecentActivity); err != nil {
+			rows.Close()
+			return err
+		}
+		scores[id] = HealthScore(in)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for id, score := range scores {
+		batch.Queue(`UPDATE project_response_metrics SET health_score = $2 WHERE project_id = $1`, id, score)
+	}
+	return j.pool.SendBatch(ctx, batch).Close()
+}
@@ -0,0 +1,69 @@
+package retention
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ArchivedEvent is one github_events row as stored in an archive object.
+type ArchivedEvent struct {
+	DeliveryID   string          `json:"delivery_id"`
+	ProjectID    *string         `json:"project_id"`
+	RepoFullName *string         `json:"repo_full_name"`
+	Event        string          `json:"event"`
+	Action       *string         `json:"action"`
+	Payload      json.RawMessage `json:"payload"`
+	ReceivedAt   time.Time       `json:"received_at"`
+}
+
+// ObjectKey names the archive object for a batch, grouped by the day of its oldest event.
+func ObjectKey(from, to time.Time, count int) string {
+	from, to = from.UTC(), to.UTC()
+	return fmt.Sprintf("github_events/%s/%s-%s-%d.jsonl.gz",
+		from.Format("2006/01/02"), from.Format("20060102T150405Z"), to.Format("20060102T150405Z"), count)
+}
+
+// Encode writes events as gzipped JSON lines.
+func Encode(events []ArchivedEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reads an archive object written by Encode.
+func Decode(data []byte) ([]ArchivedEvent, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var out []ArchivedEvent
+	sc := bufio.NewScanner(zr)
+	// Payloads can be large (e.g. push events); allow lines up to 16 MiB.
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var ev ArchivedEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return nil, err
+		}
+		out = append(out, ev)
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,54 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	project := "3f1c2a9e-0000-4000-8000-000000000001"
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	in := []ArchivedEvent{
+		{DeliveryID: "a", ProjectID: &project, Event: "issues", Payload: json.RawMessage(`{"action":"opened"}`), ReceivedAt: at},
+		{DeliveryID: "b", Event: "ping", Payload: json.RawMessage(`{}`), ReceivedAt: at.Add(time.Hour)},
+	}
+	data, err := Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewStore("file://"+t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := ObjectKey(in[0].ReceivedAt, in[1].ReceivedAt, len(in))
+	if want := "github_events/2026/03/04/20260304T050607Z-20260304T060607Z-2.jsonl.gz"; key != want {
+		t.Errorf("key = %q, want %q", key, want)
+	}
+	if err := store.Put(context.Background(), key, data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Decode(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].DeliveryID != "a" || *out[0].ProjectID != project || out[1].ProjectID != nil ||
+		string(out[0].Payload) != `{"action":"opened"}` || !out[1].ReceivedAt.Equal(in[1].ReceivedAt) {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	if s, err := NewStore("", ""); s != nil || err != nil {
+		t.Errorf("empty url = (%v, %v), want no store", s, err)
+	}
+	if _, err := NewStore("ftp://example.com/x", ""); err == nil {
+		t.Error("ftp url should be rejected")
+	}
+}
@@ -0,0 +1,138 @@
+// Package retention prunes webhook events (github_events) past the configured retention period,
+// optionally archiving them to object storage first so old ranges can still be inspected.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+)
+
+// batchSize is how many events are archived (as one object) and deleted at a time.
+const batchSize = 5000
+
+// Job periodically prunes expired events.
+type Job struct {
+	cfg      config.Config
+	pool     *pgxpool.Pool
+	store    Store
+	storeErr error
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Job {
+	store, err := NewStore(cfg.EventArchiveURL, cfg.EventArchiveToken)
+	return &Job{cfg: cfg, pool: pool, store: store, storeErr: err}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	if j.cfg.EventRetentionDays <= 0 {
+		return nil
+	}
+	// Never prune when an archive was asked for but can't be used: the events would be lost.
+	if j.storeErr != nil {
+		return fmt.Errorf("event archive: %w", j.storeErr)
+	}
+	t := time.NewTicker(6 * time.Hour)
+	defer t.Stop()
+
+	for {
+		if err := j.Prune(ctx); err != nil {
+			slog.Error("event retention pruning failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Prune deletes events older than the retention period, oldest first. With an archive configured each
+// batch is written (and recorded in github_event_archives) before it is deleted; if archiving fails
+// nothing more is deleted.
+func (j *Job) Prune(ctx context.Context) error {
+	cutoff := time.Now().UTC().AddDate(0, 0, -j.cfg.EventRetentionDays)
+	pruned, archives := 0, 0
+	for {
+		batch, err := j.loadBatch(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if j.store != nil {
+			if err := j.archive(ctx, batch); err != nil {
+				return fmt.Errorf("archive: %w", err)
+			}
+			archives++
+		}
+
+		ids := make([]string, len(batch))
+		for i, ev := range batch {
+			ids[i] = ev.DeliveryID
+		}
+		ct, err := j.pool.Exec(ctx, `DELETE FROM github_events WHERE delivery_id = ANY($1)`, ids)
+		if err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+		pruned += int(ct.RowsAffected())
+		if len(batch) < batchSize {
+			break
+		}
+	}
+	if pruned > 0 {
+		slog.Info("expired webhook events pruned", "pruned", pruned, "archives", archives, "cutoff", cutoff)
+	}
+	return nil
+}
+
+func (j *Job) loadBatch(ctx context.Context, cutoff time.Time) ([]ArchivedEvent, error) {
+	rows, err := j.pool.Query(ctx, `
+SELECT delivery_id, project_id::text, repo_full_name, event, action, payload, received_at
+FROM github_events
+WHERE received_at < $1
+ORDER BY received_at, delivery_id
+LIMIT $2
+`, cutoff, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ArchivedEvent
+	for rows.Next() {
+		var ev ArchivedEvent
+		if err := rows.Scan(&ev.DeliveryID, &ev.ProjectID, &ev.RepoFullName, &ev.Event, &ev.Action, &ev.Payload, &ev.ReceivedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+func (j *Job) archive(ctx context.Context, batch []ArchivedEvent) error {
+	data, err := Encode(batch)
+	if err != nil {
+		return err
+	}
+	from, to := batch[0].ReceivedAt, batch[len(batch)-1].ReceivedAt
+	key := ObjectKey(from, to, len(batch))
+	if err := j.store.Put(ctx, key, data); err != nil {
+		return err
+	}
+	_, err = j.pool.Exec(ctx, `
+INSERT INTO github_event_archives (object_key, from_received_at, to_received_at, event_count, size_bytes)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (object_key) DO NOTHING
+`, key, from, to, len(batch), len(data))
+	return err
+}
@@ -0,0 +1,119 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store is where archived events are written to and read back from.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// NewStore builds a Store from an archive URL: file:///path for a local (or mounted) directory, or an
+// http(s):// prefix of an object storage bucket that accepts PUT/GET (e.g. a bucket proxy or pre-authorized
+// endpoint). An empty URL means no archive.
+func NewStore(rawURL string, token string) (Store, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("archive url %q has no path", rawURL)
+		}
+		return dirStore{root: u.Path}, nil
+	case "http", "https":
+		return httpStore{
+			base:  strings.TrimRight(rawURL, "/"),
+			token: token,
+			http:  &http.Client{Timeout: 60 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive url scheme %q", u.Scheme)
+	}
+}
+
+type dirStore struct {
+	root string
+}
+
+func (s dirStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s dirStore) Put(_ context.Context, key string, data []byte) error {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+	// Write-then-rename so a crash never leaves a truncated archive behind.
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (s dirStore) Get(_ context.Context, key string) ([]byte, error) {
+	return os.ReadFile(s.path(key))
+}
+
+type httpStore struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+func (s httpStore) do(ctx context.Context, method, key string, body []byte) ([]byte, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.base+"/"+key, r)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("archive %s %s: status %d", method, key, resp.StatusCode)
+	}
+	return b, nil
+}
+
+func (s httpStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, key, data)
+	return err
+}
+
+func (s httpStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, nil)
+}
@@ -0,0 +1,257 @@
+// Package rewards escrows issue rewards against ecosystem budgets. Admins fund an ecosystem's budget per
+// currency; assigning an issue with a reward locks the reward in its ecosystem's budget, completing the
+// issue releases it to the assignee as a pending payout, and unassigning (or closing the issue as not
+// planned) returns it. Budgets, escrow and what each contributor is owed are ledger accounts; paying out
+// a released reward settles the contributor's account.
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/ledger"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// Fund kinds.
+const (
+	KindDeposit  = ledger.KindDeposit
+	KindWithdraw = ledger.KindWithdraw
+)
+
+// ErrInsufficientBudget is returned when a budget hasn't enough available for a lock or withdrawal.
+var ErrInsufficientBudget = errors.New("insufficient budget")
+
+// post records t in the ledger, reporting an overdrawn budget as ErrInsufficientBudget.
+func post(ctx context.Context, tx pgx.Tx, t ledger.Transaction) error {
+	_, err := ledger.Post(ctx, tx, t)
+	if errors.Is(err, ledger.ErrInsufficientFunds) {
+		return ErrInsufficientBudget
+	}
+	return err
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return n, nil
+}
+
+// Fund deposits into or withdraws from (kind) an ecosystem's budget in currency.
+func Fund(ctx context.Context, pool *pgxpool.Pool, ecosystemID uuid.UUID, currency, kind, amount string, actor *uuid.UUID, note string) error {
+	n, err := parseAmount(amount)
+	if err != nil {
+		return err
+	}
+	from, to := ledger.External(currency), ledger.Budget(ecosystemID, currency)
+	switch kind {
+	case KindDeposit:
+	case KindWithdraw:
+		from, to = to, from
+	default:
+		return fmt.Errorf("cannot fund with %q", kind)
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		return post(ctx, tx, ledger.Transaction{
+			Kind: kind, EcosystemID: &ecosystemID, Memo: note, CreatedBy: actor, Entries: ledger.Transfer(from, to, n),
+		})
+	})
+}
+
+// Lock escrows an issue's reward for assignee. Issues without a reward, or in projects without an
+// ecosystem or currency, have nothing to lock. An issue already locked moves to the new assignee without
+// touching the budget. It reports whether it locked a reward that wasn't locked before.
+func Lock(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, assignee string) (bool, error) {
+	locked := false
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		var ecosystemID *uuid.UUID
+		var amount, currency *string
+		err := tx.QueryRow(ctx, `
+SELECT p.ecosystem_id, gi.reward_amount::text, COALESCE(gi.reward_currency, e.reward_currency)
+FROM github_issues gi
+JOIN projects p ON p.id = gi.project_id
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+WHERE gi.project_id = $1 AND gi.number = $2
+`, projectID, issueNumber).Scan(&ecosystemID, &amount, &currency)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ecosystemID == nil || amount == nil || currency == nil {
+			return nil
+		}
+
+		ct, err := tx.Exec(ctx, `
+UPDATE reward_locks SET assignee_login = $3
+WHERE project_id = $1 AND issue_number = $2 AND status = 'locked'
+`, projectID, issueNumber, assignee)
+		if err != nil || ct.RowsAffected() > 0 {
+			return err
+		}
+
+		var lockID uuid.UUID
+		if err := tx.QueryRow(ctx, `
+INSERT INTO reward_locks (ecosystem_id, project_id, issue_number, assignee_login, currency_code, amount)
+VALUES ($1, $2, $3, $4, $5, $6::numeric)
+RETURNING id
+`, *ecosystemID, projectID, issueNumber, assignee, *currency, *amount).Scan(&lockID); err != nil {
+			return err
+		}
+		n, err := parseAmount(*amount)
+		if err != nil {
+			return err
+		}
+		if err := post(ctx, tx, ledger.Transaction{
+			Kind: ledger.KindLock, Key: "lock:" + lockID.String(),
+			EcosystemID: ecosystemID, ProjectID: &projectID, IssueNumber: &issueNumber, Memo: "assigned to " + assignee,
+			Entries: ledger.Transfer(ledger.Budget(*ecosystemID, *currency), ledger.Escrow(*ecosystemID, *currency), n),
+		}); err != nil {
+			return err
+		}
+		locked = true
+		return nil
+	})
+	return locked, err
+}
+
+// settle ends an issue's active lock with status and moves the escrowed reward out: to the assignee when
+// released, back to the budget when returned. It returns the lock, or a nil ID when the issue had none.
+func settle(ctx context.Context, tx pgx.Tx, projectID uuid.UUID, issueNumber int, status, note string) (lockID uuid.UUID, assignee, currency, amount string, err error) {
+	var ecosystemID uuid.UUID
+	err = tx.QueryRow(ctx, `
+UPDATE reward_locks SET status = $3, settled_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status = 'locked'
+RETURNING id, ecosystem_id, assignee_login, currency_code, amount::text
+`, projectID, issueNumber, status).Scan(&lockID, &ecosystemID, &assignee, &currency, &amount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, "", "", "", nil
+	}
+	if err != nil {
+		return uuid.Nil, "", "", "", err
+	}
+	n, err := parseAmount(amount)
+	if err != nil {
+		return uuid.Nil, "", "", "", err
+	}
+	kind, to := ledger.KindReturn, ledger.Budget(ecosystemID, currency)
+	if status == "released" {
+		kind, to = ledger.KindRelease, ledger.Contributor(assignee, currency)
+	}
+	err = post(ctx, tx, ledger.Transaction{
+		Kind: kind, Key: kind + ":" + lockID.String(),
+		EcosystemID: &ecosystemID, ProjectID: &projectID, IssueNumber: &issueNumber, Memo: note,
+		Entries: ledger.Transfer(ledger.Escrow(ecosystemID, currency), to, n),
+	})
+	return lockID, assignee, currency, amount, err
+}
+
+// Return gives an issue's locked reward back to its ecosystem's budget, e.g. on unassignment. Issues
+// without a locked reward are left alone.
+func Return(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, note string) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		_, _, _, _, err := settle(ctx, tx, projectID, issueNumber, "returned", note)
+		return err
+	})
+}
+
+// Release pays an issue's locked reward to its assignee: the escrowed amount moves to the assignee's
+// account and a payout, pending approval, is created for them (when their GitHub account is linked).
+func Release(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) error {
+	var userID uuid.UUID
+	var payoutID *uuid.UUID
+	var assignee, currency, amount string
+	err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		var lockID uuid.UUID
+		var err error
+		lockID, assignee, currency, amount, err = settle(ctx, tx, projectID, issueNumber, "released", "completed")
+		if err != nil || lockID == uuid.Nil {
+			return err
+		}
+		// Token payouts go to the assignee's most recent wallet on the currency's chain.
+		err = tx.QueryRow(ctx, `
+INSERT INTO payouts (recipient_user_id, recipient_address, project_id, issue_number, currency_code, amount, note)
+SELECT ga.user_id,
+       CASE WHEN c.kind = 'token' THEN (
+         SELECT w.address FROM wallets w
+         WHERE w.user_id = ga.user_id
+           AND CASE WHEN c.chain = 'stellar' THEN w.wallet_type LIKE 'stellar%' ELSE w.wallet_type = 'evm' END
+         ORDER BY w.created_at DESC LIMIT 1)
+       END,
+       $2, $3, c.code, $5::numeric, 'Reward for completing the issue'
+FROM github_accounts ga, currencies c
+WHERE LOWER(ga.login) = LOWER($1) AND c.code = $4
+RETURNING id, recipient_user_id
+`, assignee, projectID, issueNumber, currency, amount).Scan(&payoutID, &userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("reward released to an assignee without a linked account, no payout created",
+				"project_id", projectID, "issue_number", issueNumber, "assignee", assignee)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE reward_locks SET payout_id = $2 WHERE id = $1`, lockID, payoutID)
+		return err
+	})
+	if err != nil || payoutID == nil {
+		return err
+	}
+	_ = notify.Store(ctx, pool, userID, "reward_released", map[string]any{
+		"project_id":   projectID,
+		"issue_number": issueNumber,
+		"payout_id":    payoutID,
+		"currency":     currency,
+		"amount":       amount,
+	})
+	return nil
+}
+
+// Settle releases or returns an issue's locked reward when it closes: completed issues are paid, others
+// (not planned, duplicates) give the reward back.
+func Settle(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, stateReason string) error {
+	if stateReason == "completed" || stateReason == "" {
+		return Release(ctx, pool, projectID, issueNumber)
+	}
+	return Return(ctx, pool, projectID, issueNumber, "closed as "+stateReason)
+}
+
+// PaidOut records that a payout was executed. A payout of a released reward settles the assignee's
+// account: the amount leaves the ledger. Other payouts weren't funded from a budget and are left alone.
+func PaidOut(ctx context.Context, pool *pgxpool.Pool, payoutID string) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		var ecosystemID, projectID uuid.UUID
+		var issueNumber int
+		var assignee, currency, amount string
+		err := tx.QueryRow(ctx, `
+SELECT ecosystem_id, project_id, issue_number, assignee_login, currency_code, amount::text
+FROM reward_locks WHERE payout_id = $1::uuid AND status = 'released'
+`, payoutID).Scan(&ecosystemID, &projectID, &issueNumber, &assignee, &currency, &amount)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n, err := parseAmount(amount)
+		if err != nil {
+			return err
+		}
+		_, err = ledger.Post(ctx, tx, ledger.Transaction{
+			Kind: ledger.KindPayout, Key: "payout:" + payoutID,
+			EcosystemID: &ecosystemID, ProjectID: &projectID, IssueNumber: &issueNumber, Memo: "paid out",
+			Entries: ledger.Transfer(ledger.Contributor(assignee, currency), ledger.External(currency), n),
+		})
+		return err
+	})
+}
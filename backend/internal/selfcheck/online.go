@@ -0,0 +1,157 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/db"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/migrate"
+)
+
+// RequiredTables must exist for the API to serve its core endpoints.
+var RequiredTables = []string{
+	"users",
+	"github_accounts",
+	"oauth_states",
+	"tenants",
+	"ecosystems",
+	"projects",
+	"github_issues",
+	"github_pull_requests",
+	"github_events",
+	"sync_jobs",
+	"issue_applications",
+	"github_app_installations",
+}
+
+// Online runs Static plus the checks that need Postgres and GitHub.
+func Online(ctx context.Context, cfg config.Config) []Result {
+	out := Static(cfg)
+	if strings.TrimSpace(cfg.DBURL) != "" {
+		out = append(out, database(ctx, cfg.DBURL)...)
+	}
+	for _, app := range apps(cfg) {
+		if _, err := github.NewGitHubAppClient(app.ID, app.PrivateKey); err != nil {
+			continue // already reported by Static
+		}
+		out = append(out, onlineApp(ctx, cfg, app)...)
+	}
+	return out
+}
+
+func database(ctx context.Context, dbURL string) []Result {
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	d, err := db.Connect(cctx, dbURL)
+	if err != nil {
+		return []Result{fail("db_connect", err.Error(),
+			"check DB_URL host, credentials and sslmode, and that the database accepts connections from here")}
+	}
+	defer d.Close()
+	out := []Result{ok("db_connect", "")}
+
+	if missing, err := missingTables(ctx, d.Pool); err != nil {
+		out = append(out, fail("db_tables", err.Error(), "check that the DB_URL role can read the public schema"))
+	} else if len(missing) > 0 {
+		out = append(out, fail("db_tables", "missing tables: "+strings.Join(missing, ", "),
+			"run migrations: `go run ./cmd/migrate` or start the API with AUTO_MIGRATE=true"))
+	} else {
+		out = append(out, ok("db_tables", ""))
+	}
+
+	if needs, err := migrate.NeedsMigration(ctx, d.Pool); err != nil {
+		out = append(out, warn("db_migrations", err.Error(), "run `go run ./cmd/migrate`"))
+	} else if needs {
+		out = append(out, warn("db_migrations", "database schema is behind this build (or dirty)",
+			"run `go run ./cmd/migrate` or start the API with AUTO_MIGRATE=true"))
+	} else {
+		out = append(out, ok("db_migrations", ""))
+	}
+	return append(out, tenancy(ctx, d.Pool)...)
+}
+
+// tenancy warns when tenants are isolated by row level security that the DB_URL role bypasses.
+func tenancy(ctx context.Context, pool *pgxpool.Pool) []Result {
+	var tenants int
+	var bypass bool
+	err := pool.QueryRow(ctx, `
+SELECT (SELECT COUNT(*) FROM tenants),
+       (SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user)
+`).Scan(&tenants, &bypass)
+	if err != nil || tenants <= 1 {
+		return nil // no tenants table yet (reported above), or nothing to isolate
+	}
+	if bypass {
+		return []Result{fail("tenant_isolation", fmt.Sprintf("%d tenants, but the DB_URL role is a superuser or has BYPASSRLS, so every tenant sees every other's data", tenants),
+			"connect as a role without SUPERUSER and BYPASSRLS (the table owner is fine)")}
+	}
+	return []Result{ok("tenant_isolation", fmt.Sprintf("%d tenants", tenants))}
+}
+
+func missingTables(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	var missing []string
+	for _, t := range RequiredTables {
+		var present bool
+		if err := pool.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, "public."+t).Scan(&present); err != nil {
+			return nil, err
+		}
+		if !present {
+			missing = append(missing, t)
+		}
+	}
+	return missing, nil
+}
+
+func onlineApp(ctx context.Context, cfg config.Config, app config.GitHubAppCredentials) []Result {
+	client, _ := github.NewGitHubAppClient(app.ID, app.PrivateKey)
+	cctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	info, err := client.GetApp(cctx)
+	if err != nil {
+		var apiErr *github.GitHubAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 401 {
+			return []Result{fail(appCheck(app, "github"), "GitHub rejected the App JWT (401)",
+				"the App ID and private key don't belong together, or the key was revoked; generate a new key on the App's settings page")}
+		}
+		return []Result{warn(appCheck(app, "github"), "could not reach GitHub: "+err.Error(), "check outbound network access to api.github.com")}
+	}
+
+	var out []Result
+	if strconv.FormatInt(info.ID, 10) != app.ID {
+		out = append(out, fail(appCheck(app, "github"), fmt.Sprintf("the private key belongs to App %d (%s), not %s", info.ID, info.Slug, app.ID),
+			"fix the App ID or use this App's key"))
+	} else if app.Slug != "" && !strings.EqualFold(app.Slug, info.Slug) {
+		out = append(out, warn(appCheck(app, "slug"), fmt.Sprintf("configured slug %q, GitHub says %q", app.Slug, info.Slug),
+			"installation links use the slug; set it to the App's actual slug"))
+	} else {
+		out = append(out, ok(appCheck(app, "github"), info.Slug))
+	}
+
+	hook, err := client.GetHookConfig(cctx)
+	if err != nil {
+		// Apps without a webhook URL have no hook config.
+		return append(out, warn(appCheck(app, "webhook"), "could not read webhook config: "+err.Error(),
+			"enable webhooks on the App's settings page"))
+	}
+	switch {
+	case hook.Secret == "" && strings.TrimSpace(app.WebhookSecret) != "":
+		out = append(out, fail(appCheck(app, "webhook"), "GitHub signs deliveries with no secret, but one is configured here, so all deliveries are rejected",
+			"set the webhook secret on the App's settings page to the configured value"))
+	case cfg.PublicBaseURL != "" && !strings.HasPrefix(hook.URL, strings.TrimRight(cfg.PublicBaseURL, "/")+"/webhooks/github"):
+		out = append(out, warn(appCheck(app, "webhook"), fmt.Sprintf("GitHub delivers to %q", hook.URL),
+			"set the App's webhook URL to "+strings.TrimRight(cfg.PublicBaseURL, "/")+"/webhooks/github"))
+	default:
+		// GitHub never reveals the secret, so whether it matches can only be seen from delivery results.
+		out = append(out, ok(appCheck(app, "webhook"), "delivering to "+hook.URL+" (secret match is only visible in recent deliveries)"))
+	}
+	return out
+}
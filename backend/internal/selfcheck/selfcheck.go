@@ -0,0 +1,203 @@
+// Package selfcheck validates configuration up front, so a misconfigured deployment fails with a
+// list of actionable errors instead of lazily, per request (503 db_not_configured and friends).
+//
+// Static checks only look at the config and run on every API start. Online checks also talk to
+// Postgres and GitHub; they run with `api check`, which exits non-zero when anything fails.
+package selfcheck
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jagadeesh/grainlify/backend/internal/assets"
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/cryptox"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+	"github.com/jagadeesh/grainlify/backend/internal/retention"
+)
+
+type Status string
+
+const (
+	OK   Status = "ok"
+	Warn Status = "warn"
+	Fail Status = "fail"
+)
+
+// Result is the outcome of one check. Fix says what to change when Status is not OK.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+func ok(check, detail string) Result { return Result{Check: check, Status: OK, Detail: detail} }
+
+func warn(check, detail, fix string) Result {
+	return Result{Check: check, Status: Warn, Detail: detail, Fix: fix}
+}
+
+func fail(check, detail, fix string) Result {
+	return Result{Check: check, Status: Fail, Detail: detail, Fix: fix}
+}
+
+// Static checks the config without any network access.
+func Static(cfg config.Config) []Result {
+	var out []Result
+	dev := cfg.Env == "dev"
+
+	unresolved := config.UnresolvedSecrets()
+	names := make([]string, 0, len(unresolved))
+	for name := range unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, fail("secret["+name+"]", "could not fetch "+unresolved[name],
+			"check the reference and that this host's credentials (AWS role, GCP service account, VAULT_TOKEN) can read it"))
+	}
+
+	switch {
+	case strings.TrimSpace(cfg.JWTSecret) == "":
+		out = append(out, fail("jwt_secret", "JWT_SECRET is empty: no session tokens can be issued or verified",
+			"set JWT_SECRET to a random string of at least 32 characters"))
+	case len(cfg.JWTSecret) < 32:
+		out = append(out, warn("jwt_secret", fmt.Sprintf("JWT_SECRET is only %d characters", len(cfg.JWTSecret)),
+			"use at least 32 random characters, e.g. `openssl rand -hex 32`"))
+	default:
+		out = append(out, ok("jwt_secret", ""))
+	}
+
+	if strings.TrimSpace(cfg.DBURL) == "" {
+		r := fail("db_url", "DB_URL is empty: every data endpoint answers 503 db_not_configured",
+			"set DB_URL to a postgres:// connection string")
+		if dev {
+			r.Status = Warn
+		}
+		out = append(out, r)
+	} else {
+		out = append(out, ok("db_url", ""))
+	}
+
+	if _, err := cryptox.KeyFromB64(cfg.TokenEncKey()); err != nil {
+		out = append(out, fail("token_enc_key", err.Error()+": GitHub login and linked-account actions answer 503",
+			"set TOKEN_ENC_KEY_B64 to 32 random bytes, base64 encoded: `openssl rand -base64 32`"))
+	} else {
+		out = append(out, ok("token_enc_key", ""))
+	}
+
+	idSet, secretSet := cfg.GitHubOAuthClientID != "", cfg.GitHubOAuthClientSecret != ""
+	switch {
+	case !idSet && !secretSet:
+		out = append(out, warn("github_oauth", "GitHub OAuth is not configured: users cannot sign in with GitHub",
+			"set GITHUB_OAUTH_CLIENT_ID and GITHUB_OAUTH_CLIENT_SECRET from the OAuth app settings"))
+	case idSet != secretSet:
+		out = append(out, fail("github_oauth", "only one of GITHUB_OAUTH_CLIENT_ID / GITHUB_OAUTH_CLIENT_SECRET is set",
+			"set both from the same GitHub OAuth app"))
+	case cfg.GitHubOAuthRedirectURL == "" && cfg.GitHubLoginRedirectURL == "":
+		out = append(out, fail("github_oauth", "no OAuth callback URL",
+			"set GITHUB_OAUTH_REDIRECT_URL to <public base>/auth/github/login/callback"))
+	default:
+		out = append(out, ok("github_oauth", ""))
+	}
+
+	if strings.TrimSpace(cfg.PublicBaseURL) == "" {
+		out = append(out, warn("public_base_url", "PUBLIC_BASE_URL is empty: repository webhooks cannot be registered",
+			"set PUBLIC_BASE_URL to the URL GitHub can reach this API at"))
+	} else if u, err := url.Parse(cfg.PublicBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		out = append(out, fail("public_base_url", fmt.Sprintf("PUBLIC_BASE_URL %q is not an absolute http(s) URL", cfg.PublicBaseURL),
+			"use the form https://api.example.com"))
+	} else {
+		out = append(out, ok("public_base_url", ""))
+	}
+
+	if strings.TrimSpace(os.Getenv("GITHUB_APPS")) != "" && len(cfg.GitHubApps) == 0 {
+		out = append(out, fail("github_apps", "GITHUB_APPS is set but no app could be parsed from it",
+			`GITHUB_APPS must be a JSON array of {"id","slug","private_key","webhook_secret"} objects`))
+	}
+	for _, app := range apps(cfg) {
+		out = append(out, staticApp(app)...)
+	}
+
+	if cfg.EventArchiveURL != "" {
+		if _, err := retention.NewStore(cfg.EventArchiveURL, cfg.EventArchiveToken); err != nil {
+			out = append(out, fail("event_archive", err.Error(),
+				"EVENT_ARCHIVE_URL must be a file:// directory or an http(s):// prefix; pruning is paused until fixed"))
+		} else {
+			out = append(out, ok("event_archive", ""))
+		}
+	}
+
+	if cfg.AssetStorageURL != "" {
+		if _, err := assets.NewStore(cfg.AssetStorageURL, assets.Options{SigningKey: []byte(cfg.JWTSecret),
+			KeyID: cfg.AssetStorageKeyID, Secret: cfg.AssetStorageSecret}); err != nil {
+			out = append(out, fail("asset_storage", err.Error(),
+				"ASSET_STORAGE_URL must be file:///dir, s3://bucket[/prefix] or gs://bucket[/prefix] (gs needs ASSET_STORAGE_KEY_ID/SECRET); logo uploads are disabled until fixed"))
+		} else {
+			out = append(out, ok("asset_storage", ""))
+		}
+	}
+	return out
+}
+
+// apps lists the configured GitHub Apps, default first. Empty when no App is configured at all.
+func apps(cfg config.Config) []config.GitHubAppCredentials {
+	var out []config.GitHubAppCredentials
+	if def := cfg.DefaultGitHubApp(); def.ID != "" || def.PrivateKey != "" {
+		out = append(out, def)
+	}
+	return append(out, cfg.GitHubApps...)
+}
+
+func appCheck(app config.GitHubAppCredentials, what string) string {
+	id := app.ID
+	if id == "" {
+		id = "default"
+	}
+	return "github_app[" + id + "]." + what
+}
+
+func staticApp(app config.GitHubAppCredentials) []Result {
+	var out []Result
+	if _, err := strconv.ParseInt(app.ID, 10, 64); err != nil {
+		out = append(out, fail(appCheck(app, "id"), fmt.Sprintf("App ID %q is not numeric", app.ID),
+			"use the numeric App ID from the GitHub App's settings page (not the client ID)"))
+	}
+	if _, err := github.NewGitHubAppClient(app.ID, app.PrivateKey); err != nil {
+		out = append(out, fail(appCheck(app, "private_key"), err.Error(),
+			"paste the .pem generated on the App's settings page, raw or base64 encoded"))
+	} else {
+		out = append(out, ok(appCheck(app, "private_key"), ""))
+	}
+	if strings.TrimSpace(app.WebhookSecret) == "" {
+		out = append(out, fail(appCheck(app, "webhook_secret"), "no webhook secret: every delivery from this App is rejected",
+			"set the same secret here (GITHUB_WEBHOOK_SECRET or webhook_secret in GITHUB_APPS) and on the App"))
+	}
+	return out
+}
+
+// Print writes results as a table and returns how many failed.
+func Print(w io.Writer, results []Result) int {
+	failed := 0
+	for _, r := range results {
+		if r.Status == Fail {
+			failed++
+		}
+		fmt.Fprintf(w, "%-5s %s", strings.ToUpper(string(r.Status)), r.Check)
+		if r.Detail != "" {
+			fmt.Fprintf(w, ": %s", r.Detail)
+		}
+		fmt.Fprintln(w)
+		if r.Fix != "" {
+			fmt.Fprintf(w, "      -> %s\n", r.Fix)
+		}
+	}
+	fmt.Fprintf(w, "\n%d checks, %d failed\n", len(results), failed)
+	return failed
+}
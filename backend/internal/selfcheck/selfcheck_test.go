@@ -0,0 +1,73 @@
+package selfcheck
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/github/githubtest"
+)
+
+func statusOf(results []Result, check string) Status {
+	for _, r := range results {
+		if r.Check == check {
+			return r.Status
+		}
+	}
+	return ""
+}
+
+func TestStatic(t *testing.T) {
+	cfg := config.Config{
+		Env:            "prod",
+		JWTSecret:      "short",
+		TokenEncKeyB64: base64.StdEncoding.EncodeToString([]byte("too short")),
+		PublicBaseURL:  "api.example.com",
+		GitHubAppID:    "123",
+		// Key and webhook secret missing.
+	}
+	got := Static(cfg)
+	want := map[string]Status{
+		"jwt_secret":                     Warn,
+		"db_url":                         Fail,
+		"token_enc_key":                  Fail,
+		"github_oauth":                   Warn,
+		"public_base_url":                Fail,
+		"github_app[123].private_key":    Fail,
+		"github_app[123].webhook_secret": Fail,
+	}
+	for check, status := range want {
+		if s := statusOf(got, check); s != status {
+			t.Errorf("%s = %q, want %q", check, s, status)
+		}
+	}
+	var b strings.Builder
+	if failed := Print(&b, got); failed != 5 {
+		t.Errorf("Print reported %d failures:\n%s", failed, b.String())
+	}
+}
+
+func TestOnlineApp(t *testing.T) {
+	gh := githubtest.New(t)
+	app := config.GitHubAppCredentials{ID: "123", Slug: "grainlify", PrivateKey: gh.PrivateKeyPEM(), WebhookSecret: "s3cret"}
+	cfg := config.Config{PublicBaseURL: "https://api.example.com"}
+
+	gh.SetApp(456, "someone-else", "https://api.example.com/webhooks/github", true)
+	if s := statusOf(onlineApp(context.Background(), cfg, app), "github_app[123].github"); s != Fail {
+		t.Errorf("mismatched App ID: got %q, want fail", s)
+	}
+
+	gh.SetApp(123, "grainlify", "https://api.example.com/webhooks/github", false)
+	if s := statusOf(onlineApp(context.Background(), cfg, app), "github_app[123].webhook"); s != Fail {
+		t.Errorf("unsigned deliveries: got %q, want fail", s)
+	}
+
+	gh.SetApp(123, "grainlify", "https://api.example.com/webhooks/github", true)
+	for _, r := range onlineApp(context.Background(), cfg, app) {
+		if r.Status != OK {
+			t.Errorf("%s = %q (%s)", r.Check, r.Status, r.Detail)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package seo
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Feed is an Atom 1.0 feed (RFC 4287).
+type Feed struct {
+	XMLName  xml.Name  `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string    `xml:"id"`
+	Title    string    `xml:"title"`
+	Subtitle string    `xml:"subtitle,omitempty"`
+	Updated  AtomTime  `xml:"updated"`
+	Links    []Link    `xml:"link"`
+	Entries  []Entry   `xml:"entry"`
+	Icon     string    `xml:"icon,omitempty"`
+	Author   *Person   `xml:"author,omitempty"`
+	Gen      Generator `xml:"generator"`
+}
+
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    AtomTime   `xml:"updated"`
+	Published  AtomTime   `xml:"published"`
+	Links      []Link     `xml:"link"`
+	Author     *Person    `xml:"author,omitempty"`
+	Categories []Category `xml:"category"`
+	Summary    *Text      `xml:"summary,omitempty"`
+}
+
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Text struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type Generator struct {
+	Name string `xml:",chardata"`
+}
+
+// AtomTime marshals as RFC 3339 in UTC.
+type AtomTime time.Time
+
+func (t AtomTime) MarshalText() ([]byte, error) {
+	return []byte(time.Time(t).UTC().Format(time.RFC3339)), nil
+}
+
+// WriteFeed writes f as an Atom document. Feed.Updated defaults to the newest entry.
+func WriteFeed(w io.Writer, f Feed) error {
+	if time.Time(f.Updated).IsZero() {
+		f.Updated = AtomTime(time.Unix(0, 0))
+		for _, e := range f.Entries {
+			if time.Time(e.Updated).After(time.Time(f.Updated)) {
+				f.Updated = e.Updated
+			}
+		}
+	}
+	if f.Gen.Name == "" {
+		f.Gen.Name = "Grainlify"
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,105 @@
+// Package seo builds sitemap.xml, Atom feeds and the OpenGraph fields the marketing site renders for
+// public project and ecosystem pages. Page URLs are <site>/projects/<owner>/<repo> and <site>/ecosystems/<slug>.
+package seo
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxURLs is the sitemap protocol's limit on URLs per file.
+const MaxURLs = 50000
+
+// URL is one sitemap entry.
+type URL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type urlset struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []URL    `xml:"url"`
+}
+
+// WriteSitemap writes urls (at most MaxURLs) as a sitemap.
+func WriteSitemap(w io.Writer, urls []URL) error {
+	if len(urls) > MaxURLs {
+		urls = urls[:MaxURLs]
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if err := enc.Encode(urlset{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: urls}); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// LastMod formats t for <lastmod>; zero times are left out.
+func LastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+func ProjectURL(site, fullName string) string {
+	owner, repo, _ := strings.Cut(fullName, "/")
+	return strings.TrimSuffix(site, "/") + "/projects/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+func EcosystemURL(site, slug string) string {
+	return strings.TrimSuffix(site, "/") + "/ecosystems/" + url.PathEscape(slug)
+}
+
+// OpenGraph holds the og:* (and twitter:card) values for a page.
+type OpenGraph struct {
+	Title       string `json:"og:title"`
+	Description string `json:"og:description"`
+	URL         string `json:"og:url"`
+	Image       string `json:"og:image,omitempty"`
+	Type        string `json:"og:type"`
+	SiteName    string `json:"og:site_name"`
+	TwitterCard string `json:"twitter:card"`
+}
+
+// NewOpenGraph fills in the card type and trims the description to what previews show.
+func NewOpenGraph(siteName, title, description, pageURL, image string) OpenGraph {
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+	return OpenGraph{
+		Title:       title,
+		Description: Truncate(strings.Join(strings.Fields(description), " "), 200),
+		URL:         pageURL,
+		Image:       image,
+		Type:        "website",
+		SiteName:    siteName,
+		TwitterCard: card,
+	}
+}
+
+// Truncate shortens s to at most n runes, cutting at a word boundary and adding an ellipsis.
+func Truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	cut := string(r[:n-1])
+	if r[n-1] != ' ' {
+		// Drop the partial word unless that would lose most of the text.
+		if i := strings.LastIndexByte(cut, ' '); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
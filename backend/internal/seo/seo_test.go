@@ -0,0 +1,84 @@
+package seo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSitemap(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteSitemap(&buf, []URL{
+		{Loc: ProjectURL("https://example.com/", "acme/a&b"), LastMod: LastMod(time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))},
+		{Loc: EcosystemURL("https://example.com", "stellar")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		`<loc>https://example.com/projects/acme/a&amp;b</loc><lastmod>2026-03-01</lastmod>`,
+		`<url><loc>https://example.com/ecosystems/stellar</loc></url>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("sitemap missing %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestNewOpenGraph(t *testing.T) {
+	og := NewOpenGraph("Grainlify", "acme/widget on Grainlify", "  A   widget\n library ", "https://example.com/projects/acme/widget", "")
+	if og.Description != "A widget library" || og.TwitterCard != "summary" || og.Type != "website" {
+		t.Errorf("og = %+v", og)
+	}
+	if og := NewOpenGraph("G", "t", "d", "u", "https://img"); og.TwitterCard != "summary_large_image" {
+		t.Errorf("card = %q", og.TwitterCard)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("short", 10); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	if got := Truncate("the quick brown fox jumps", 16); got != "the quick brown…" {
+		t.Errorf("got %q", got)
+	}
+	if got := Truncate("ééééééééééé", 5); got != "éééé…" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestWriteFeed(t *testing.T) {
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.FixedZone("x", 3600))
+	var buf bytes.Buffer
+	err := WriteFeed(&buf, Feed{
+		ID:    "https://api.example.com/ecosystems/1/issues.atom",
+		Title: "Stellar issues",
+		Entries: []Entry{{
+			ID:         "https://github.com/acme/widget/issues/7",
+			Title:      "[acme/widget#7] Fix <parser> (50 points)",
+			Published:  AtomTime(created),
+			Updated:    AtomTime(created.Add(time.Hour)),
+			Categories: []Category{{Term: "good first issue"}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<updated>2026-03-01T10:00:00Z</updated>`, // feed updated = newest entry, in UTC
+		`<published>2026-03-01T09:00:00Z</published>`,
+		`<title>[acme/widget#7] Fix &lt;parser&gt; (50 points)</title>`,
+		`<category term="good first issue"></category>`,
+		`<generator>Grainlify</generator>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("feed missing %s:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package similarity compares the diffs of pull requests closing the same issue, to point maintainers at
+// likely copies. A diff is reduced to a fingerprint: hashes of overlapping runs of its added lines, with
+// whitespace and case normalized, keeping only the smallest hashes so fingerprints stay small. Two
+// fingerprints' Jaccard index estimates how much added code the pull requests share.
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+const (
+	// ShingleLines is how many consecutive added lines make up one hashed run.
+	ShingleLines = 3
+	// MaxFingerprint caps a fingerprint's size (a bottom-k sketch).
+	MaxFingerprint = 2000
+	// FlagThreshold is the similarity from which a pair of pull requests is flagged.
+	FlagThreshold = 0.6
+	// minLineLen skips lines too short to say anything ("}", "end", "").
+	minLineLen = 4
+)
+
+// AddedLines returns the normalized lines a unified diff adds.
+func AddedLines(patch string) []string {
+	var out []string
+	for _, line := range strings.Split(patch, "\n") {
+		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		norm := strings.ToLower(strings.Join(strings.Fields(line[1:]), " "))
+		if len(norm) >= minLineLen {
+			out = append(out, norm)
+		}
+	}
+	return out
+}
+
+// Fingerprint hashes runs of ShingleLines added lines across the patches. Diffs with fewer added lines
+// than that hash each line on its own. The result is sorted and distinct.
+func Fingerprint(patches []string) []int64 {
+	var lines []string
+	for _, p := range patches {
+		lines = append(lines, AddedLines(p)...)
+	}
+	seen := map[int64]bool{}
+	add := func(parts []string) {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(parts, "\n")))
+		seen[int64(h.Sum64())] = true
+	}
+	if len(lines) < ShingleLines {
+		for _, l := range lines {
+			add([]string{l})
+		}
+	}
+	for i := 0; i+ShingleLines <= len(lines); i++ {
+		add(lines[i : i+ShingleLines])
+	}
+	out := make([]int64, 0, len(seen))
+	for h := range seen {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	if len(out) > MaxFingerprint {
+		out = out[:MaxFingerprint]
+	}
+	return out
+}
+
+// Jaccard is the share of hashes two sorted, distinct fingerprints have in common: 0 when either is
+// empty, 1 for identical ones.
+func Jaccard(a, b []int64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
@@ -0,0 +1,57 @@
+package similarity
+
+import (
+	"reflect"
+	"testing"
+)
+
+const patchA = `@@ -1,3 +1,8 @@
+ package main
++func Sum(xs []int) int {
++	total := 0
++	for _, x := range xs {
++		total += x
++	}
++	return total
++}
+-// old
+`
+
+func TestAddedLines(t *testing.T) {
+	got := AddedLines("+++ b/main.go\n+  Total  :=  0\n+}\n context\n-removed line\n+return total")
+	want := []string{"total := 0", "return total"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AddedLines = %q, want %q", got, want)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	a := Fingerprint([]string{patchA})
+	if len(a) == 0 {
+		t.Fatal("empty fingerprint")
+	}
+	// Reindented and with different case: the same code.
+	copied := Fingerprint([]string{"+func sum(xs []int) int {\n+  total := 0\n+  for _, x := range xs {\n+    total += x\n+  }\n+  return total\n+}"})
+	if got := Jaccard(a, copied); got != 1 {
+		t.Errorf("copy similarity = %v, want 1", got)
+	}
+	other := Fingerprint([]string{"+def mean(values):\n+    if not values:\n+        return None\n+    return sum(values) / len(values)"})
+	if got := Jaccard(a, other); got != 0 {
+		t.Errorf("unrelated similarity = %v, want 0", got)
+	}
+	if got := Jaccard(a, nil); got != 0 {
+		t.Errorf("empty similarity = %v", got)
+	}
+	if got := Jaccard([]int64{1, 2, 3}, []int64{2, 3, 4}); got != 0.5 {
+		t.Errorf("Jaccard = %v, want 0.5", got)
+	}
+}
+
+func TestFingerprintShortDiff(t *testing.T) {
+	if got := Fingerprint([]string{"+fix typo here"}); len(got) != 1 {
+		t.Errorf("short diff fingerprint = %v", got)
+	}
+	if got := Fingerprint([]string{"+}"}); len(got) != 0 {
+		t.Errorf("trivial diff fingerprint = %v", got)
+	}
+}
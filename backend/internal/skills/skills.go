@@ -0,0 +1,181 @@
+// Package skills is the controlled vocabulary contributors pick their skills from (languages and
+// frameworks), and the matching of declared skills against an issue's project language, labels and
+// tags. Profiles store skill ids; anything outside the vocabulary is rejected so skills stay comparable.
+package skills
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxPerUser caps how many skills a profile can declare.
+const MaxPerUser = 30
+
+type Kind string
+
+const (
+	Language  Kind = "language"
+	Framework Kind = "framework"
+)
+
+// Skill is one vocabulary entry. Language is the id of a framework's language, if any.
+type Skill struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Kind     Kind     `json:"kind"`
+	Language string   `json:"language,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// Languages use GitHub's language names, so they compare directly with projects.language.
+var vocabulary = []Skill{
+	{ID: "c", Name: "C", Kind: Language},
+	{ID: "cpp", Name: "C++", Kind: Language, Aliases: []string{"c++", "cplusplus"}},
+	{ID: "csharp", Name: "C#", Kind: Language, Aliases: []string{"c#", "dotnet", ".net"}},
+	{ID: "css", Name: "CSS", Kind: Language, Aliases: []string{"scss", "sass"}},
+	{ID: "dart", Name: "Dart", Kind: Language},
+	{ID: "elixir", Name: "Elixir", Kind: Language},
+	{ID: "go", Name: "Go", Kind: Language, Aliases: []string{"golang"}},
+	{ID: "haskell", Name: "Haskell", Kind: Language},
+	{ID: "html", Name: "HTML", Kind: Language},
+	{ID: "java", Name: "Java", Kind: Language},
+	{ID: "javascript", Name: "JavaScript", Kind: Language, Aliases: []string{"js", "ecmascript"}},
+	{ID: "kotlin", Name: "Kotlin", Kind: Language},
+	{ID: "lua", Name: "Lua", Kind: Language},
+	{ID: "move", Name: "Move", Kind: Language},
+	{ID: "php", Name: "PHP", Kind: Language},
+	{ID: "python", Name: "Python", Kind: Language, Aliases: []string{"py"}},
+	{ID: "ruby", Name: "Ruby", Kind: Language},
+	{ID: "rust", Name: "Rust", Kind: Language},
+	{ID: "scala", Name: "Scala", Kind: Language},
+	{ID: "shell", Name: "Shell", Kind: Language, Aliases: []string{"bash", "sh"}},
+	{ID: "solidity", Name: "Solidity", Kind: Language},
+	{ID: "sql", Name: "SQL", Kind: Language, Aliases: []string{"plpgsql"}},
+	{ID: "swift", Name: "Swift", Kind: Language},
+	{ID: "typescript", Name: "TypeScript", Kind: Language, Aliases: []string{"ts"}},
+	{ID: "zig", Name: "Zig", Kind: Language},
+
+	{ID: "react", Name: "React", Kind: Framework, Language: "javascript", Aliases: []string{"reactjs", "react.js"}},
+	{ID: "react-native", Name: "React Native", Kind: Framework, Language: "javascript"},
+	{ID: "nextjs", Name: "Next.js", Kind: Framework, Language: "javascript", Aliases: []string{"next", "next.js"}},
+	{ID: "vue", Name: "Vue", Kind: Framework, Language: "javascript", Aliases: []string{"vuejs", "vue.js"}},
+	{ID: "svelte", Name: "Svelte", Kind: Framework, Language: "javascript"},
+	{ID: "angular", Name: "Angular", Kind: Framework, Language: "typescript"},
+	{ID: "nodejs", Name: "Node.js", Kind: Framework, Language: "javascript", Aliases: []string{"node", "node.js"}},
+	{ID: "express", Name: "Express", Kind: Framework, Language: "javascript", Aliases: []string{"expressjs"}},
+	{ID: "tailwind", Name: "Tailwind CSS", Kind: Framework, Language: "css", Aliases: []string{"tailwindcss"}},
+	{ID: "django", Name: "Django", Kind: Framework, Language: "python"},
+	{ID: "flask", Name: "Flask", Kind: Framework, Language: "python"},
+	{ID: "fastapi", Name: "FastAPI", Kind: Framework, Language: "python"},
+	{ID: "rails", Name: "Ruby on Rails", Kind: Framework, Language: "ruby", Aliases: []string{"ruby-on-rails", "ror"}},
+	{ID: "spring", Name: "Spring", Kind: Framework, Language: "java", Aliases: []string{"spring-boot"}},
+	{ID: "laravel", Name: "Laravel", Kind: Framework, Language: "php"},
+	{ID: "flutter", Name: "Flutter", Kind: Framework, Language: "dart"},
+	{ID: "tokio", Name: "Tokio", Kind: Framework, Language: "rust"},
+	{ID: "soroban", Name: "Soroban", Kind: Framework, Language: "rust", Aliases: []string{"soroban-sdk"}},
+	{ID: "stellar-sdk", Name: "Stellar SDK", Kind: Framework, Aliases: []string{"stellar"}},
+	{ID: "ethers", Name: "ethers.js", Kind: Framework, Language: "javascript", Aliases: []string{"ethersjs", "viem"}},
+	{ID: "hardhat", Name: "Hardhat", Kind: Framework, Language: "solidity"},
+	{ID: "foundry", Name: "Foundry", Kind: Framework, Language: "solidity"},
+	{ID: "fiber", Name: "Fiber", Kind: Framework, Language: "go", Aliases: []string{"gofiber"}},
+	{ID: "gin", Name: "Gin", Kind: Framework, Language: "go"},
+	{ID: "graphql", Name: "GraphQL", Kind: Framework},
+	{ID: "postgres", Name: "PostgreSQL", Kind: Framework, Language: "sql", Aliases: []string{"postgresql"}},
+	{ID: "docker", Name: "Docker", Kind: Framework},
+	{ID: "kubernetes", Name: "Kubernetes", Kind: Framework, Aliases: []string{"k8s"}},
+	{ID: "terraform", Name: "Terraform", Kind: Framework},
+	{ID: "pytorch", Name: "PyTorch", Kind: Framework, Language: "python", Aliases: []string{"torch"}},
+	{ID: "tensorflow", Name: "TensorFlow", Kind: Framework, Language: "python"},
+}
+
+var lookup = func() map[string]*Skill {
+	m := map[string]*Skill{}
+	for i := range vocabulary {
+		s := &vocabulary[i]
+		m[key(s.ID)] = s
+		m[key(s.Name)] = s
+		for _, a := range s.Aliases {
+			m[key(a)] = s
+		}
+	}
+	return m
+}()
+
+func key(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), "-"))
+}
+
+// All returns the vocabulary, languages first, then by name.
+func All() []Skill {
+	out := append([]Skill(nil), vocabulary...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind == Language
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out
+}
+
+// Lookup resolves an id, name or alias (case-insensitive).
+func Lookup(s string) (Skill, bool) {
+	sk, ok := lookup[key(s)]
+	if !ok {
+		return Skill{}, false
+	}
+	return *sk, true
+}
+
+// Normalize maps input to canonical, de-duplicated ids in input order. Entries outside the vocabulary
+// are returned in unknown.
+func Normalize(input []string) (ids []string, unknown []string) {
+	seen := map[string]bool{}
+	ids = []string{}
+	for _, in := range input {
+		if strings.TrimSpace(in) == "" {
+			continue
+		}
+		sk, ok := Lookup(in)
+		if !ok {
+			unknown = append(unknown, in)
+			continue
+		}
+		if !seen[sk.ID] {
+			seen[sk.ID] = true
+			ids = append(ids, sk.ID)
+		}
+	}
+	return ids, unknown
+}
+
+// Match returns the declared skill ids relevant to work in language with the given labels and project
+// tags: the language itself, and frameworks named by a label or tag.
+func Match(declared []string, language string, labels, tags []string) []string {
+	relevant := map[string]bool{}
+	if sk, ok := Lookup(language); ok {
+		relevant[sk.ID] = true
+	}
+	for _, t := range append(append([]string(nil), labels...), tags...) {
+		if sk, ok := Lookup(t); ok {
+			relevant[sk.ID] = true
+		}
+	}
+	var out []string
+	for _, id := range declared {
+		if relevant[id] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Names maps ids to display names, skipping ids no longer in the vocabulary.
+func Names(ids []string) []string {
+	var out []string
+	for _, id := range ids {
+		if sk, ok := lookup[key(id)]; ok {
+			out = append(out, sk.Name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	ids, unknown := Normalize([]string{"Golang", "TypeScript", " react.js ", "go", "", "Next.js", "cobol"})
+	if want := []string{"go", "typescript", "react", "nextjs"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if !reflect.DeepEqual(unknown, []string{"cobol"}) {
+		t.Errorf("unknown = %v", unknown)
+	}
+	if ids, _ := Normalize(nil); ids == nil || len(ids) != 0 {
+		t.Errorf("Normalize(nil) = %#v, want empty", ids)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	declared := []string{"rust", "soroban", "react", "python"}
+	got := Match(declared, "Rust", []string{"good first issue", "Soroban"}, []string{"frontend"})
+	if !reflect.DeepEqual(got, []string{"rust", "soroban"}) {
+		t.Errorf("Match = %v", got)
+	}
+	if got := Match(declared, "", nil, nil); got != nil {
+		t.Errorf("Match with nothing = %v", got)
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+	seen := map[string]bool{}
+	all := All()
+	for i, s := range all {
+		if seen[s.ID] {
+			t.Errorf("duplicate id %q", s.ID)
+		}
+		seen[s.ID] = true
+		if s.Language != "" {
+			if l, ok := Lookup(s.Language); !ok || l.Kind != Language {
+				t.Errorf("%s: language %q is not a language", s.ID, s.Language)
+			}
+		}
+		if i > 0 && all[i-1].Kind == Framework && s.Kind == Language {
+			t.Error("languages not listed first")
+		}
+	}
+	if got := Names([]string{"cpp", "gone", "nextjs"}); !reflect.DeepEqual(got, []string{"C++", "Next.js"}) {
+		t.Errorf("Names = %v", got)
+	}
+}
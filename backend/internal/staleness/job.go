@@ -0,0 +1,112 @@
+// Package staleness flags projects whose maintainers have gone quiet and un-flags them when
+// activity resumes. Maintainer activity is an application decision, a merged PR, or the owner
+// reactivating the project.
+package staleness
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/config"
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// Job periodically re-evaluates project staleness.
+type Job struct {
+	cfg  config.Config
+	pool *pgxpool.Pool
+}
+
+func New(cfg config.Config, pool *pgxpool.Pool) *Job {
+	return &Job{cfg: cfg, pool: pool}
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	if j.pool == nil {
+		return fmt.Errorf("db not configured")
+	}
+	if j.cfg.StaleProjectWeeks <= 0 {
+		return nil
+	}
+	t := time.NewTicker(6 * time.Hour)
+	defer t.Stop()
+
+	for {
+		if err := j.Evaluate(ctx); err != nil {
+			slog.Error("stale project evaluation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// lastActivitySQL is the latest maintainer activity for project p (falls back to when it was added).
+const lastActivitySQL = `GREATEST(
+  p.created_at,
+  COALESCE(p.reactivated_at, p.created_at),
+  COALESCE((SELECT MAX(a.decided_at) FROM issue_applications a WHERE a.project_id = p.id), p.created_at),
+  COALESCE((SELECT MAX(pr.merged_at_github) FROM github_pull_requests pr WHERE pr.project_id = p.id), p.created_at)
+)`
+
+// Evaluate flags newly stale projects (notifying their owners) and clears projects with fresh activity.
+func (j *Job) Evaluate(ctx context.Context) error {
+	weeks := j.cfg.StaleProjectWeeks
+
+	cleared, err := j.pool.Exec(ctx, `
+UPDATE projects p SET stale_at = NULL, updated_at = now()
+WHERE p.stale_at IS NOT NULL
+  AND `+lastActivitySQL+` > now() - make_interval(weeks => $1)
+`, weeks)
+	if err != nil {
+		return fmt.Errorf("clear stale: %w", err)
+	}
+
+	rows, err := j.pool.Query(ctx, `
+UPDATE projects p SET stale_at = now(), updated_at = now()
+WHERE p.status = 'verified' AND p.deleted_at IS NULL AND p.stale_at IS NULL
+  AND `+lastActivitySQL+` <= now() - make_interval(weeks => $1)
+RETURNING p.id, p.owner_user_id, p.github_full_name
+`, weeks)
+	if err != nil {
+		return fmt.Errorf("flag stale: %w", err)
+	}
+	type flagged struct {
+		projectID, fullName string
+		ownerID             uuid.UUID
+	}
+	var list []flagged
+	for rows.Next() {
+		var f flagged
+		if err := rows.Scan(&f.projectID, &f.ownerID, &f.fullName); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, f := range list {
+		_ = notify.Store(ctx, j.pool, f.ownerID, "project_stale", map[string]any{
+			"project_id":       f.projectID,
+			"github_full_name": f.fullName,
+			"weeks":            weeks,
+			"delisted":         j.cfg.StaleProjectDelist,
+		})
+	}
+
+	if len(list) > 0 || cleared.RowsAffected() > 0 {
+		slog.Info("stale projects evaluated", "flagged", len(list), "cleared", cleared.RowsAffected())
+	}
+	return nil
+}
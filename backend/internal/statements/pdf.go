@@ -0,0 +1,138 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Line is one line of text in the PDF. A zero Line is a blank line.
+type Line struct {
+	Text string
+	Size float64
+	Bold bool
+}
+
+// A4 in points, with the margins used for every page.
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// PDF renders lines as a plain A4 document in Helvetica. Long lines wrap; text outside Latin-1 is
+// approximated, which is enough for names, titles and URLs.
+func PDF(lines []Line) []byte {
+	var pages [][]string
+	var ops []string
+	y := pageHeight - margin
+	for _, l := range lines {
+		size := l.Size
+		if size == 0 {
+			size = 10
+		}
+		font := "F1"
+		if l.Bold {
+			font = "F2"
+		}
+		// Helvetica averages about half an em per character.
+		width := int((pageWidth - 2*margin) / (size * 0.5))
+		for _, part := range wrap(l.Text, width) {
+			if y-size < margin {
+				pages = append(pages, ops)
+				ops = nil
+				y = pageHeight - margin
+			}
+			y -= size * 1.4
+			if part != "" {
+				ops = append(ops, fmt.Sprintf("BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET", font, size, margin, y, pdfString(part)))
+			}
+		}
+	}
+	pages = append(pages, ops)
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and its content stream per page.
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, p := range pages {
+		content := strings.Join(p, "\n")
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+// wrap splits s into lines of at most width characters, breaking at spaces where possible.
+func wrap(s string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var out []string
+	for utf8.RuneCountInString(s) > width {
+		r := []rune(s)
+		cut := width
+		for i := width; i > width/2; i-- {
+			if r[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		out = append(out, strings.TrimRight(string(r[:cut]), " "))
+		s = "    " + strings.TrimLeft(string(r[cut:]), " ")
+	}
+	return append(out, s)
+}
+
+// pdfString escapes s for a PDF literal string in WinAnsi encoding.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '…':
+			b.WriteString("...")
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		case r == '‘' || r == '’':
+			b.WriteByte('\'')
+		case r == '“' || r == '”':
+			b.WriteByte('"')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
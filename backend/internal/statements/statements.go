@@ -0,0 +1,218 @@
+// Package statements produces contribution statements: a snapshot of a contributor's completed Grainlify
+// contributions (assigned issues closed by their merged pull request) for grant or job applications. Each
+// statement is signed when issued and can be checked by anyone at its public verification URL; it renders
+// as JSON, Markdown or PDF.
+package statements
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Version is the statement format version.
+const Version = 1
+
+// MaxContributions caps a statement; the most recent are kept.
+const MaxContributions = 1000
+
+// Contribution is one completed issue.
+type Contribution struct {
+	Project     string    `json:"project"`
+	Ecosystem   string    `json:"ecosystem,omitempty"`
+	IssueNumber int       `json:"issue_number"`
+	IssueTitle  string    `json:"issue_title"`
+	IssueURL    string    `json:"issue_url"`
+	PRNumber    int       `json:"pr_number"`
+	PRURL       string    `json:"pr_url"`
+	AssignedAt  time.Time `json:"assigned_at"`
+	MergedAt    time.Time `json:"merged_at"`
+	Points      *int      `json:"points"`
+	Labels      []string  `json:"labels"`
+	// Diff size of the pull request, when known.
+	Additions    *int `json:"additions,omitempty"`
+	Deletions    *int `json:"deletions,omitempty"`
+	ChangedFiles *int `json:"changed_files,omitempty"`
+}
+
+// Totals summarises the contributions.
+type Totals struct {
+	Contributions int        `json:"contributions"`
+	Projects      int        `json:"projects"`
+	Points        int        `json:"points"`
+	FirstMergedAt *time.Time `json:"first_merged_at"`
+	LastMergedAt  *time.Time `json:"last_merged_at"`
+}
+
+// Statement is what gets signed. Field order is fixed so the signed JSON is reproducible.
+type Statement struct {
+	ID            uuid.UUID      `json:"id"`
+	Version       int            `json:"version"`
+	GitHubLogin   string         `json:"github_login"`
+	IssuedAt      time.Time      `json:"issued_at"`
+	Totals        Totals         `json:"totals"`
+	Contributions []Contribution `json:"contributions"`
+}
+
+// New builds a statement from contributions (most recent first) and computes the totals.
+func New(id uuid.UUID, login string, issuedAt time.Time, contributions []Contribution) Statement {
+	if contributions == nil {
+		contributions = []Contribution{}
+	}
+	s := Statement{ID: id, Version: Version, GitHubLogin: login, IssuedAt: issuedAt.UTC().Truncate(time.Second), Contributions: contributions}
+	projects := map[string]bool{}
+	for i := range contributions {
+		c := &contributions[i]
+		if c.Labels == nil {
+			c.Labels = []string{}
+		}
+		projects[strings.ToLower(c.Project)] = true
+		if c.Points != nil {
+			s.Totals.Points += *c.Points
+		}
+		m := c.MergedAt
+		if s.Totals.FirstMergedAt == nil || m.Before(*s.Totals.FirstMergedAt) {
+			s.Totals.FirstMergedAt = &m
+		}
+		if s.Totals.LastMergedAt == nil || m.After(*s.Totals.LastMergedAt) {
+			s.Totals.LastMergedAt = &m
+		}
+	}
+	s.Totals.Contributions = len(contributions)
+	s.Totals.Projects = len(projects)
+	return s
+}
+
+// Load returns the completed contributions of a user's linked GitHub accounts, most recent first. When a
+// maintainer split an issue's credit, everyone in the split gets it with their share of the points.
+func Load(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([]Contribution, error) {
+	rows, err := pool.Query(ctx, `
+WITH logins AS (SELECT LOWER(login) AS login FROM github_accounts WHERE user_id = $1)
+SELECT p.github_full_name, COALESCE(e.name, ''), ic.issue_number, COALESCE(gi.title, ''), COALESCE(gi.url, ''),
+       ic.pr_number, COALESCE(pr.url, ''), ic.assigned_at, ic.merged_at,
+       CASE WHEN jsonb_array_length(ic.credits) = 0 THEN ic.points
+            ELSE (SELECT SUM((cr->>'points')::int)::int FROM jsonb_array_elements(ic.credits) cr
+                  WHERE LOWER(cr->>'login') IN (SELECT login FROM logins)) END,
+       ic.labels, pr.additions, pr.deletions, pr.changed_files
+FROM issue_completions ic
+JOIN projects p ON p.id = ic.project_id
+LEFT JOIN ecosystems e ON e.id = p.ecosystem_id
+LEFT JOIN github_issues gi ON gi.project_id = ic.project_id AND gi.number = ic.issue_number
+LEFT JOIN github_pull_requests pr ON pr.project_id = ic.project_id AND pr.number = ic.pr_number
+WHERE (CASE WHEN jsonb_array_length(ic.credits) = 0 THEN LOWER(ic.assignee_login) IN (SELECT login FROM logins)
+            ELSE EXISTS (SELECT 1 FROM jsonb_array_elements(ic.credits) cr
+                         WHERE LOWER(cr->>'login') IN (SELECT login FROM logins)) END)
+  AND p.status = 'verified' AND p.deleted_at IS NULL
+ORDER BY ic.merged_at DESC
+LIMIT $2
+`, userID, MaxContributions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Contribution
+	for rows.Next() {
+		var c Contribution
+		if err := rows.Scan(&c.Project, &c.Ecosystem, &c.IssueNumber, &c.IssueTitle, &c.IssueURL,
+			&c.PRNumber, &c.PRURL, &c.AssignedAt, &c.MergedAt, &c.Points, &c.Labels,
+			&c.Additions, &c.Deletions, &c.ChangedFiles); err != nil {
+			return nil, err
+		}
+		if c.IssueURL == "" {
+			c.IssueURL = fmt.Sprintf("https://github.com/%s/issues/%d", c.Project, c.IssueNumber)
+		}
+		if c.PRURL == "" {
+			c.PRURL = fmt.Sprintf("https://github.com/%s/pull/%d", c.Project, c.PRNumber)
+		}
+		c.AssignedAt = c.AssignedAt.UTC()
+		c.MergedAt = c.MergedAt.UTC()
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Sign signs the exact statement JSON.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("contribution-statement:"))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func ValidSignature(secret string, payload []byte, signature string) bool {
+	return secret != "" && hmac.Equal([]byte(signature), []byte(Sign(secret, payload)))
+}
+
+// VerifyURL is the public page that confirms a statement.
+func VerifyURL(publicBaseURL string, id uuid.UUID) string {
+	return strings.TrimSuffix(publicBaseURL, "/") + "/verify/contributions/" + id.String()
+}
+
+// Markdown renders the statement for pasting into an application.
+func Markdown(s Statement, verifyURL, signature string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Grainlify contribution statement: @%s\n\n", s.GitHubLogin)
+	fmt.Fprintf(&b, "Issued %s. Verify at %s\n\n", s.IssuedAt.Format("January 2, 2006"), verifyURL)
+	b.WriteString(summaryLine(s) + "\n\n")
+	if len(s.Contributions) > 0 {
+		b.WriteString("| Merged | Project | Issue | Pull request | Points |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, c := range s.Contributions {
+			fmt.Fprintf(&b, "| %s | %s | [#%d %s](%s) | [#%d](%s) | %s |\n",
+				c.MergedAt.Format("2006-01-02"), mdCell(c.Project), c.IssueNumber, mdCell(c.IssueTitle), c.IssueURL,
+				c.PRNumber, c.PRURL, pointsText(c.Points))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Statement %s, signature `%s`.\n", s.ID, signature)
+	return b.String()
+}
+
+// Lines is the plain-text rendering used for the PDF.
+func Lines(s Statement, verifyURL, signature string) []Line {
+	lines := []Line{
+		{Text: "Grainlify contribution statement", Size: 18, Bold: true},
+		{Text: "@" + s.GitHubLogin, Size: 13, Bold: true},
+		{Text: "Issued " + s.IssuedAt.Format("January 2, 2006"), Size: 10},
+		{Text: "Verify at " + verifyURL, Size: 10},
+		{},
+		{Text: summaryLine(s), Size: 11},
+		{},
+	}
+	for _, c := range s.Contributions {
+		lines = append(lines,
+			Line{Text: fmt.Sprintf("%s  %s#%d  %s", c.MergedAt.Format("2006-01-02"), c.Project, c.IssueNumber, c.IssueTitle), Size: 10, Bold: true},
+			Line{Text: fmt.Sprintf("    Merged in pull request #%d (%s), points: %s", c.PRNumber, c.PRURL, pointsText(c.Points)), Size: 9},
+		)
+	}
+	return append(lines, Line{}, Line{Text: "Statement " + s.ID.String(), Size: 8}, Line{Text: "Signature " + signature, Size: 8})
+}
+
+func summaryLine(s Statement) string {
+	if s.Totals.Contributions == 0 {
+		return "No completed contributions yet."
+	}
+	line := fmt.Sprintf("%d completed contributions across %d projects, %d points", s.Totals.Contributions, s.Totals.Projects, s.Totals.Points)
+	if s.Totals.FirstMergedAt != nil && s.Totals.LastMergedAt != nil {
+		line += fmt.Sprintf(", %s to %s", s.Totals.FirstMergedAt.Format("Jan 2006"), s.Totals.LastMergedAt.Format("Jan 2006"))
+	}
+	return line + "."
+}
+
+func pointsText(p *int) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprint(*p)
+}
+
+func mdCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ", "[", "\\[", "]", "\\]").Replace(s)
+}
@@ -0,0 +1,74 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func sample() Statement {
+	three, five := 3, 5
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	return New(uuid.MustParse("6c1c2d1e-0000-4000-8000-000000000001"), "octo", day(20), []Contribution{
+		{Project: "acme/widgets", IssueNumber: 12, IssueTitle: "Fix | pipe (parser)", PRNumber: 14, MergedAt: day(10), Points: &five},
+		{Project: "Acme/Widgets", IssueNumber: 3, IssueTitle: "Docs", PRNumber: 4, MergedAt: day(2), Points: &three},
+		{Project: "acme/gears", IssueNumber: 1, IssueTitle: "Café", PRNumber: 2, MergedAt: day(5)},
+	})
+}
+
+func TestNewTotals(t *testing.T) {
+	s := sample()
+	if s.Totals.Contributions != 3 || s.Totals.Projects != 2 || s.Totals.Points != 8 {
+		t.Errorf("totals = %+v", s.Totals)
+	}
+	if s.Totals.FirstMergedAt.Day() != 2 || s.Totals.LastMergedAt.Day() != 10 {
+		t.Errorf("range = %v..%v", s.Totals.FirstMergedAt, s.Totals.LastMergedAt)
+	}
+	if s.Contributions[2].Labels == nil {
+		t.Error("labels should marshal as []")
+	}
+}
+
+func TestSign(t *testing.T) {
+	payload, _ := json.Marshal(sample())
+	sig := Sign("secret", payload)
+	if !ValidSignature("secret", payload, sig) {
+		t.Fatal("signature rejected")
+	}
+	tampered := bytes.Replace(payload, []byte(`"points":8`), []byte(`"points":80`), 1)
+	if ValidSignature("secret", tampered, sig) || ValidSignature("other", payload, sig) || ValidSignature("", payload, Sign("", payload)) {
+		t.Error("bad signature accepted")
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := sample()
+	md := Markdown(s, "https://api.example.com/verify/contributions/x", "abc")
+	for _, want := range []string{"@octo", "3 completed contributions across 2 projects, 8 points, Mar 2026 to Mar 2026.", `Fix \| pipe (parser)`, "`abc`"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	pdf := PDF(Lines(s, "https://api.example.com/verify/contributions/x", "abc"))
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("not a PDF")
+	}
+	for _, want := range []string{`Fix | pipe \(parser\)`, `Caf\351`, "/Count 1"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("pdf missing %q", want)
+		}
+	}
+
+	many := make([]Line, 200)
+	for i := range many {
+		many[i] = Line{Text: strings.Repeat("word ", 40)}
+	}
+	if !bytes.Contains(PDF(many), []byte("/Count ")) || bytes.Contains(PDF(many), []byte("/Count 1 ")) {
+		t.Error("long document should span several pages")
+	}
+}
@@ -0,0 +1,172 @@
+// Package subprojects splits a monorepo project into sub-projects, each with its own maintainers and
+// ecosystem. An issue belongs to a sub-project when it carries one of the sub-project's labels or
+// mentions a path under one of its directories (in the title, the body, or a link to a file on GitHub),
+// unless it carries one of the sub-project's excluded labels.
+package subprojects
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/recommend"
+)
+
+// MaxPathLen bounds a mapped directory.
+const MaxPathLen = 200
+
+// Mapping decides which of a project's issues belong to a sub-project.
+type Mapping struct {
+	Paths         []string `json:"paths"`
+	Labels        []string `json:"labels"`
+	ExcludeLabels []string `json:"exclude_labels"`
+}
+
+// Issue is what a mapping looks at.
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Empty reports whether the mapping can't match anything.
+func (m Mapping) Empty() bool {
+	return len(m.Paths) == 0 && len(m.Labels) == 0
+}
+
+// Matches reports whether the issue belongs to the sub-project.
+func (m Mapping) Matches(is Issue) bool {
+	for _, l := range is.Labels {
+		for _, x := range m.ExcludeLabels {
+			if strings.EqualFold(l, x) {
+				return false
+			}
+		}
+	}
+	for _, l := range is.Labels {
+		for _, x := range m.Labels {
+			if strings.EqualFold(l, x) {
+				return true
+			}
+		}
+	}
+	if len(m.Paths) == 0 {
+		return false
+	}
+	for _, mention := range MentionedPaths(is.Title + "\n" + is.Body) {
+		for _, p := range m.Paths {
+			if mention == p || strings.HasPrefix(mention, p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NormalizePath cleans a mapped directory: no leading "./" or "/", no trailing "/". It reports false for
+// paths that can't name a directory in the repository.
+func NormalizePath(p string) (string, bool) {
+	p = strings.TrimSpace(p)
+	p = strings.TrimPrefix(p, "./")
+	p = strings.Trim(p, "/")
+	if p == "" || len(p) > MaxPathLen || strings.Contains(p, "://") || strings.ContainsAny(p, " \t\n") {
+		return "", false
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", false
+		}
+	}
+	return p, true
+}
+
+// MentionedPaths returns the repository paths text mentions: slash-separated words like
+// packages/ui/button.tsx and the file part of github.com blob and tree links.
+func MentionedPaths(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || strings.ContainsRune("`'\"()[]<>,;", r)
+	})
+	var out []string
+	for _, f := range fields {
+		f = strings.TrimRight(f, ".:!?")
+		if i := strings.Index(f, "://"); i >= 0 {
+			f = linkedPath(f[i+3:])
+		}
+		if p, ok := NormalizePath(f); ok && strings.Contains(p, "/") {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// linkedPath is the path in a github.com/owner/repo/blob/ref/path (or tree) link, "" for other links.
+func linkedPath(u string) string {
+	parts := strings.SplitN(u, "/", 6)
+	if len(parts) < 6 || !strings.EqualFold(parts[0], "github.com") || (parts[3] != "blob" && parts[3] != "tree") {
+		return ""
+	}
+	p := parts[5]
+	if i := strings.IndexAny(p, "#?"); i >= 0 {
+		p = p[:i]
+	}
+	return p
+}
+
+// Maintains reports whether the user maintains a sub-project of the project that the issue belongs to.
+func Maintains(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, userID uuid.UUID) (bool, error) {
+	rows, err := pool.Query(ctx, `
+SELECT s.paths, s.labels, s.exclude_labels
+FROM project_subprojects s
+JOIN subproject_maintainers m ON m.subproject_id = s.id
+WHERE s.project_id = $1 AND m.user_id = $2
+`, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+	var mappings []Mapping
+	for rows.Next() {
+		var m Mapping
+		if err := rows.Scan(&m.Paths, &m.Labels, &m.ExcludeLabels); err != nil {
+			rows.Close()
+			return false, err
+		}
+		mappings = append(mappings, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(mappings) == 0 {
+		return false, err
+	}
+
+	is, err := LoadIssue(ctx, pool, projectID, issueNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, m := range mappings {
+		if m.Matches(is) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LoadIssue loads what a mapping looks at of a synced issue.
+func LoadIssue(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) (Issue, error) {
+	var is Issue
+	var labelsJSON []byte
+	err := pool.QueryRow(ctx, `
+SELECT COALESCE(title, ''), COALESCE(body, ''), COALESCE(labels, '[]'::jsonb)
+FROM github_issues WHERE project_id = $1 AND number = $2
+`, projectID, issueNumber).Scan(&is.Title, &is.Body, &labelsJSON)
+	if err != nil {
+		return Issue{}, err
+	}
+	is.Labels = recommend.LabelNames(labelsJSON)
+	return is, nil
+}
@@ -0,0 +1,57 @@
+package subprojects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"packages/ui":    "packages/ui",
+		"./packages/ui/": "packages/ui",
+		"/apps/web":      "apps/web",
+		"docs":           "docs",
+		"":               "",
+		"/":              "",
+		"a/../b":         "",
+		"a//b":           "",
+		"https://x.io/a": "",
+		"a b/c":          "",
+	} {
+		got, ok := NormalizePath(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("NormalizePath(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestMentionedPaths(t *testing.T) {
+	text := "Crash in `packages/ui/button.tsx` (see ./apps/web/index.ts).\n" +
+		"https://github.com/acme/mono/blob/main/services/api/main.go#L10 and https://example.com/a/b/c/d/e, fixes #12"
+	want := []string{"packages/ui/button.tsx", "apps/web/index.ts", "services/api/main.go"}
+	if got := MentionedPaths(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("MentionedPaths = %q, want %q", got, want)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	m := Mapping{Paths: []string{"packages/ui"}, Labels: []string{"area: ui"}, ExcludeLabels: []string{"wontfix"}}
+	for name, tc := range map[string]struct {
+		is   Issue
+		want bool
+	}{
+		"label":          {Issue{Title: "Buttons", Labels: []string{"Area: UI"}}, true},
+		"path":           {Issue{Title: "Fix packages/ui/button.tsx"}, true},
+		"path directory": {Issue{Body: "everything under packages/ui/"}, true},
+		"sibling path":   {Issue{Body: "packages/ui-kit/x.ts"}, false},
+		"excluded":       {Issue{Labels: []string{"area: ui", "wontfix"}}, false},
+		"unrelated":      {Issue{Title: "Docs typo", Labels: []string{"docs"}}, false},
+	} {
+		if got := m.Matches(tc.is); got != tc.want {
+			t.Errorf("%s: Matches = %v, want %v", name, got, tc.want)
+		}
+	}
+	if (Mapping{ExcludeLabels: []string{"x"}}).Matches(Issue{Title: "packages/ui/a"}) {
+		t.Error("empty mapping matched")
+	}
+}
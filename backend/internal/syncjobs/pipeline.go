@@ -0,0 +1,66 @@
+package syncjobs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxPages caps how many list pages one sync reads (100 items each).
+const maxPages = 500
+
+type pageResult[T any] struct {
+	page  int
+	items []T
+	err   error
+}
+
+// streamPages fetches pages 1, 2, ... in the background so GitHub calls for the next page overlap with
+// writing the current one; it runs at most a page ahead of the consumer. fetch reports whether more pages
+// follow. The stream ends after the last page, after an error (delivered as the final result), or when
+// ctx is done, so consumers that stop early must cancel ctx.
+func streamPages[T any](ctx context.Context, fetch func(ctx context.Context, page int) ([]T, bool, error)) <-chan pageResult[T] {
+	out := make(chan pageResult[T], 1)
+	go func() {
+		defer close(out)
+		for page := 1; page <= maxPages; page++ {
+			items, more, err := fetch(ctx, page)
+			select {
+			case out <- pageResult[T]{page: page, items: items, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || !more {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// execBatch sends the queued statements in one round trip. They run in one implicit transaction, so when
+// any fails the statements are retried one by one: a bad row costs only itself, as before batching. It
+// returns how many statements could not be applied; err is set only when the database is unreachable.
+func execBatch(ctx context.Context, pool *pgxpool.Pool, b *pgx.Batch) (int, error) {
+	if b.Len() == 0 {
+		return 0, nil
+	}
+	if err := pool.SendBatch(ctx, b).Close(); err == nil {
+		return 0, nil
+	} else if ctx.Err() != nil {
+		return b.Len(), ctx.Err()
+	}
+	failed := 0
+	for _, q := range b.QueuedQueries {
+		if _, err := pool.Exec(ctx, q.SQL, q.Arguments...); err != nil {
+			if ctx.Err() != nil {
+				return failed, ctx.Err()
+			}
+			failed++
+			slog.Warn("sync row write failed", "error", err)
+		}
+	}
+	return failed, nil
+}
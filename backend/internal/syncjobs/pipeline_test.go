@@ -0,0 +1,51 @@
+package syncjobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStreamPages(t *testing.T) {
+	fetch := func(last int, failAt int) func(context.Context, int) ([]int, bool, error) {
+		return func(_ context.Context, page int) ([]int, bool, error) {
+			if page == failAt {
+				return nil, false, errors.New("boom")
+			}
+			if page > last {
+				return nil, false, nil
+			}
+			return []int{page * 10, page*10 + 1}, true, nil
+		}
+	}
+
+	var pages []int
+	for p := range streamPages(context.Background(), fetch(3, 0)) {
+		if p.err != nil {
+			t.Fatalf("page %d: %v", p.page, p.err)
+		}
+		if len(p.items) > 0 && p.items[0] != p.page*10 {
+			t.Errorf("page %d carries %v", p.page, p.items)
+		}
+		pages = append(pages, p.page)
+	}
+	if len(pages) != 4 || pages[0] != 1 || pages[3] != 4 {
+		t.Errorf("pages = %v, want 1..4 (the last one empty)", pages)
+	}
+
+	var got []pageResult[int]
+	for p := range streamPages(context.Background(), fetch(5, 2)) {
+		got = append(got, p)
+	}
+	if len(got) != 2 || got[1].err == nil {
+		t.Errorf("stream after an error = %+v, want it to end with the error", got)
+	}
+
+	// A consumer that stops early cancels; the producer must not block forever.
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := streamPages(ctx, fetch(maxPages, 0))
+	<-stream
+	cancel()
+	for range stream {
+	}
+}
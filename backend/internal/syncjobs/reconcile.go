@@ -0,0 +1,218 @@
+package syncjobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jagadeesh/grainlify/backend/internal/apiusage"
+	"github.com/jagadeesh/grainlify/backend/internal/github"
+)
+
+// ReconcileReserve is how many calls of the owner's rate limit an on-demand reconciliation leaves for the
+// scheduled sync, webhooks and the owner's own use.
+const ReconcileReserve = 500
+
+var (
+	// ErrReconcileRunning means another reconciliation of the project is in progress.
+	ErrReconcileRunning = errors.New("reconcile already running")
+	// ErrGitHubNotLinked means the project owner has no GitHub token to sync with.
+	ErrGitHubNotLinked = errors.New("github not linked")
+)
+
+// RateBudgetError means the owner's token doesn't have enough calls left for a reconciliation.
+type RateBudgetError struct {
+	Needed    int
+	Remaining int
+	ResetAt   *time.Time
+}
+
+func (e *RateBudgetError) Error() string {
+	return fmt.Sprintf("rate budget too low: %d calls left, %d needed", e.Remaining, e.Needed)
+}
+
+// Correction is one field of an issue or pull request that GitHub had different from our copy.
+type Correction struct {
+	Kind   string `json:"kind"` // issue | pull_request
+	Number int    `json:"number"`
+	Field  string `json:"field"` // state | assignees | title | merged | draft | added
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Reconciliation is what an on-demand sync corrected.
+type Reconciliation struct {
+	Issues       int          `json:"issues"`
+	PullRequests int          `json:"pull_requests"`
+	Corrections  []Correction `json:"corrections"`
+	StartedAt    time.Time    `json:"started_at"`
+	FinishedAt   time.Time    `json:"finished_at"`
+}
+
+// estimateCalls is roughly what syncing a repository with this many issues and pull requests costs: the
+// issue listing (which includes pull requests) and pull request listing at 100 per page, plus a comments
+// call per issue with comments. Review and diff stat calls for changed pull requests come out of the reserve.
+func estimateCalls(issues, issuesWithComments, prs int) int {
+	return (issues+prs)/100 + 1 + prs/100 + 1 + issuesWithComments
+}
+
+type itemState struct {
+	state, title, assignees string
+	merged, draft           bool
+}
+
+// Reconcile re-fetches a project's issues and pull requests from GitHub right away, outside the job
+// queue, and reports the fields that changed. It refuses to start when the owner's rate limit (as last
+// observed) wouldn't cover it and ReconcileReserve, and runs at most once per project at a time.
+func (w *Worker) Reconcile(ctx context.Context, projectID uuid.UUID) (Reconciliation, error) {
+	res := Reconciliation{StartedAt: time.Now().UTC(), Corrections: []Correction{}}
+
+	conn, err := w.pool.Acquire(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer conn.Release()
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext('project_reconcile:' || $1::text))`, projectID).Scan(&locked); err != nil {
+		return res, err
+	}
+	if !locked {
+		return res, ErrReconcileRunning
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext('project_reconcile:' || $1::text))`, projectID)
+	}()
+
+	var fullName string
+	var ownerUserID uuid.UUID
+	if err := w.pool.QueryRow(ctx, `
+SELECT github_full_name, owner_user_id FROM projects WHERE id = $1 AND deleted_at IS NULL
+`, projectID).Scan(&fullName, &ownerUserID); err != nil {
+		return res, err
+	}
+	linked, err := github.GetLinkedAccount(ctx, w.pool, ownerUserID, w.cfg.TokenEncKey())
+	if err != nil {
+		return res, fmt.Errorf("%w: %v", ErrGitHubNotLinked, err)
+	}
+
+	var issues, withComments, prs int
+	if err := w.pool.QueryRow(ctx, `
+SELECT (SELECT COUNT(*) FROM github_issues WHERE project_id = $1),
+       (SELECT COUNT(*) FROM github_issues WHERE project_id = $1 AND COALESCE(comments_count, 0) > 0),
+       (SELECT COUNT(*) FROM github_pull_requests WHERE project_id = $1)
+`, projectID).Scan(&issues, &withComments, &prs); err != nil {
+		return res, err
+	}
+	needed := estimateCalls(issues, withComments, prs) + ReconcileReserve
+	remaining, resetAt, err := apiusage.Remaining(ctx, w.pool, apiusage.KindUser, linked.Login)
+	if err != nil {
+		return res, err
+	}
+	if remaining != nil && *remaining < needed {
+		return res, &RateBudgetError{Needed: needed, Remaining: *remaining, ResetAt: resetAt}
+	}
+
+	beforeIssues, beforePRs, err := w.snapshot(ctx, projectID)
+	if err != nil {
+		return res, err
+	}
+	if err := w.syncIssues(ctx, projectID, fullName, linked.AccessToken); err != nil {
+		return res, fmt.Errorf("sync issues: %w", err)
+	}
+	if err := w.syncPRs(ctx, projectID, fullName, linked.AccessToken); err != nil {
+		return res, fmt.Errorf("sync pull requests: %w", err)
+	}
+	afterIssues, afterPRs, err := w.snapshot(ctx, projectID)
+	if err != nil {
+		return res, err
+	}
+
+	res.Issues, res.PullRequests = len(afterIssues), len(afterPRs)
+	res.Corrections = append(res.Corrections, corrections("issue", beforeIssues, afterIssues)...)
+	res.Corrections = append(res.Corrections, corrections("pull_request", beforePRs, afterPRs)...)
+	res.FinishedAt = time.Now().UTC()
+	return res, nil
+}
+
+// snapshot reads the fields Reconcile reports on, keyed by number.
+func (w *Worker) snapshot(ctx context.Context, projectID uuid.UUID) (map[int]itemState, map[int]itemState, error) {
+	issues := map[int]itemState{}
+	rows, err := w.pool.Query(ctx, `
+SELECT number, state, title,
+       COALESCE((SELECT string_agg(LOWER(a->>'login'), ',' ORDER BY LOWER(a->>'login'))
+                 FROM jsonb_array_elements(COALESCE(assignees, '[]'::jsonb)) a), '')
+FROM github_issues WHERE project_id = $1
+`, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for rows.Next() {
+		var n int
+		var s itemState
+		if err := rows.Scan(&n, &s.state, &s.title, &s.assignees); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		issues[n] = s
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	prs := map[int]itemState{}
+	rows, err = w.pool.Query(ctx, `
+SELECT number, state, title, COALESCE(merged, false), COALESCE(draft, false) FROM github_pull_requests WHERE project_id = $1
+`, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n int
+		var s itemState
+		if err := rows.Scan(&n, &s.state, &s.title, &s.merged, &s.draft); err != nil {
+			return nil, nil, err
+		}
+		prs[n] = s
+	}
+	return issues, prs, rows.Err()
+}
+
+// corrections lists what differs between two snapshots, ordered by number.
+func corrections(kind string, before, after map[int]itemState) []Correction {
+	numbers := make([]int, 0, len(after))
+	for n := range after {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	var out []Correction
+	for _, n := range numbers {
+		a := after[n]
+		b, ok := before[n]
+		if !ok {
+			out = append(out, Correction{Kind: kind, Number: n, Field: "added", After: a.title})
+			continue
+		}
+		add := func(field, from, to string) {
+			if from != to {
+				out = append(out, Correction{Kind: kind, Number: n, Field: field, Before: from, After: to})
+			}
+		}
+		add("state", b.state, a.state)
+		add("title", b.title, a.title)
+		if kind == "issue" {
+			add("assignees", strings.ReplaceAll(b.assignees, ",", ", "), strings.ReplaceAll(a.assignees, ",", ", "))
+		} else {
+			add("merged", fmt.Sprint(b.merged), fmt.Sprint(a.merged))
+			add("draft", fmt.Sprint(b.draft), fmt.Sprint(a.draft))
+		}
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package syncjobs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCorrections(t *testing.T) {
+	before := map[int]itemState{
+		1: {state: "open", title: "Fix login", assignees: "alice"},
+		2: {state: "open", title: "Docs", assignees: "bob,carol"},
+		3: {state: "closed", title: "Gone from GitHub"},
+	}
+	after := map[int]itemState{
+		1: {state: "closed", title: "Fix login", assignees: ""},
+		2: {state: "open", title: "Docs", assignees: "bob,carol"},
+		4: {state: "open", title: "New one"},
+	}
+	got := corrections("issue", before, after)
+	want := []Correction{
+		{Kind: "issue", Number: 1, Field: "state", Before: "open", After: "closed"},
+		{Kind: "issue", Number: 1, Field: "assignees", Before: "alice", After: ""},
+		{Kind: "issue", Number: 4, Field: "added", After: "New one"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("corrections = %+v\nwant %+v", got, want)
+	}
+
+	prs := corrections("pull_request",
+		map[int]itemState{7: {state: "open", title: "Add x", draft: true}},
+		map[int]itemState{7: {state: "closed", title: "Add x", merged: true}})
+	if len(prs) != 3 || prs[1].Field != "merged" || prs[2].Field != "draft" || prs[2].After != "false" {
+		t.Errorf("pull request corrections = %+v", prs)
+	}
+}
+
+func TestEstimateCalls(t *testing.T) {
+	if got := estimateCalls(0, 0, 0); got != 2 {
+		t.Errorf("empty repo = %d calls, want 2", got)
+	}
+	if got := estimateCalls(250, 40, 120); got != 4+2+40 {
+		t.Errorf("estimate = %d, want %d", got, 4+2+40)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/time/rate"
 
+	"github.com/jagadeesh/grainlify/backend/internal/attribution"
 	"github.com/jagadeesh/grainlify/backend/internal/config"
 	"github.com/jagadeesh/grainlify/backend/internal/github"
 )
@@ -42,11 +44,16 @@ func (w *Worker) Run(ctx context.Context) error {
 	}
 	t := time.NewTicker(1 * time.Second)
 	defer t.Stop()
+	repoTicker := time.NewTicker(1 * time.Hour)
+	defer repoTicker.Stop()
 
+	w.enqueueRepoSyncs(ctx)
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-repoTicker.C:
+			w.enqueueRepoSyncs(ctx)
 		case <-t.C:
 			if err := w.processOne(ctx); err != nil && !errors.Is(err, pgx.ErrNoRows) {
 				slog.Error("sync worker error", "error", err)
@@ -55,6 +62,28 @@ func (w *Worker) Run(ctx context.Context) error {
 	}
 }
 
+// enqueueRepoSyncs schedules a daily repository metadata refresh for verified projects.
+func (w *Worker) enqueueRepoSyncs(ctx context.Context) {
+	ct, err := w.pool.Exec(ctx, `
+INSERT INTO sync_jobs (project_id, job_type, status, run_at)
+SELECT p.id, 'sync_repo', 'pending', now()
+FROM projects p
+WHERE p.status = 'verified' AND p.deleted_at IS NULL
+  AND (p.repo_synced_at IS NULL OR p.repo_synced_at < now() - interval '24 hours')
+  AND NOT EXISTS (
+    SELECT 1 FROM sync_jobs j
+    WHERE j.project_id = p.id AND j.job_type = 'sync_repo' AND j.status IN ('pending', 'running')
+  )
+`)
+	if err != nil {
+		slog.Warn("failed to enqueue repo metadata syncs", "error", err)
+		return
+	}
+	if ct.RowsAffected() > 0 {
+		slog.Info("enqueued repo metadata syncs", "count", ct.RowsAffected())
+	}
+}
+
 func (w *Worker) processOne(ctx context.Context) error {
 	tx, err := w.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -127,7 +156,7 @@ WHERE id = $1
 		return err
 	}
 
-	linked, err := github.GetLinkedAccount(ctx, w.pool, ownerUserID, w.cfg.TokenEncKeyB64)
+	linked, err := github.GetLinkedAccount(ctx, w.pool, ownerUserID, w.cfg.TokenEncKey())
 	if err != nil {
 		slog.Error("sync job failed: GitHub account not linked",
 			"job_id", jobID,
@@ -154,6 +183,13 @@ WHERE id = $1
 		syncErr = w.syncIssues(ctx, projectID, fullName, linked.AccessToken)
 	case "sync_prs":
 		syncErr = w.syncPRs(ctx, projectID, fullName, linked.AccessToken)
+	case "sync_repo":
+		syncErr = w.syncRepo(ctx, projectID, fullName, linked.AccessToken)
+		if syncErr == nil {
+			w.syncDocs(ctx, projectID, fullName, linked.AccessToken)
+		}
+	case "sync_discussions":
+		syncErr = w.syncDiscussions(ctx, projectID, fullName, linked.AccessToken)
 	default:
 		syncErr = fmt.Errorf("unknown job_type: %s", jobType)
 	}
@@ -178,119 +214,150 @@ WHERE id = $1
 	return nil
 }
 
+// issueRow is an issue from a list page with its comments, ready to upsert.
+type issueRow struct {
+	github.IssueListItem
+	commentsJSON []byte
+}
+
+const upsertIssueSQL = `
+INSERT INTO github_issues (project_id, github_issue_id, number, state, state_reason, title, body, author_login, url, assignees, labels, comments_count, comments, created_at_github, updated_at_github, closed_at_github, last_seen_at)
+VALUES ($1, $2, $3, $4, $16, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, now())
+ON CONFLICT (project_id, github_issue_id) DO UPDATE SET
+  number = EXCLUDED.number,
+  state = EXCLUDED.state,
+  state_reason = EXCLUDED.state_reason,
+  title = EXCLUDED.title,
+  body = EXCLUDED.body,
+  author_login = EXCLUDED.author_login,
+  url = EXCLUDED.url,
+  assignees = EXCLUDED.assignees,
+  labels = EXCLUDED.labels,
+  comments_count = EXCLUDED.comments_count,
+  comments = EXCLUDED.comments,
+  created_at_github = COALESCE(EXCLUDED.created_at_github, github_issues.created_at_github),
+  updated_at_github = COALESCE(EXCLUDED.updated_at_github, github_issues.updated_at_github),
+  closed_at_github = COALESCE(EXCLUDED.closed_at_github, github_issues.closed_at_github),
+  last_seen_at = now()
+-- An older snapshot (out-of-order delivery, a sync page fetched before a newer change) must not win.
+WHERE EXCLUDED.updated_at_github IS NULL OR github_issues.updated_at_github IS NULL
+   OR EXCLUDED.updated_at_github >= github_issues.updated_at_github
+`
+
+// parseGitHubTime parses an optional RFC 3339 timestamp from the GitHub API, logging (with attrs) values
+// it can't read.
+func parseGitHubTime(field string, v *string, attrs ...any) *time.Time {
+	if v == nil || *v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *v)
+	if err != nil {
+		slog.Warn("failed to parse "+field, append(attrs, field, *v, "error", err)...)
+		return nil
+	}
+	return &t
+}
+
+// syncIssues streams the repository's issues page by page (see streamPages), fetching each page's
+// comments along with it, and upserts every page in one batch.
 func (w *Worker) syncIssues(ctx context.Context, projectID uuid.UUID, fullName string, token string) error {
-	totalIssues := 0
-	for page := 1; page <= 50; page++ { // safety cap
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := streamPages(ctx, func(ctx context.Context, page int) ([]issueRow, bool, error) {
 		if err := w.limiter.Wait(ctx); err != nil {
-			return err
+			return nil, false, err
 		}
 		items, err := w.gh.ListIssuesPage(ctx, token, fullName, page)
-		if err != nil {
-			return err
-		}
-		if len(items) == 0 {
-			return nil
+		if err != nil || len(items) == 0 {
+			return nil, false, err
 		}
-
+		rows := make([]issueRow, 0, len(items))
 		for _, it := range items {
 			// Skip PRs from the issues endpoint.
 			if it.PullRequest != nil {
 				continue
 			}
-			totalIssues++
-			// Convert assignees to JSONB (array of login strings)
-			assigneesJSON, _ := json.Marshal(it.Assignees)
-			// Convert labels to JSONB (array of {name, color} objects)
-			labelsJSON, _ := json.Marshal(it.Labels)
-			
-			// Parse date strings from GitHub API
-			var createdAt, updatedAt, closedAt *time.Time
-			if it.CreatedAt != nil && *it.CreatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.CreatedAt); err == nil {
-					createdAt = &t
-				} else {
-					slog.Warn("failed to parse issue created_at",
-						"project_id", projectID,
-						"repo", fullName,
-						"issue_id", it.ID,
-						"created_at", *it.CreatedAt,
-						"error", err,
-					)
-				}
-			}
-			if it.UpdatedAt != nil && *it.UpdatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.UpdatedAt); err == nil {
-					updatedAt = &t
-				} else {
-					slog.Warn("failed to parse issue updated_at",
-						"project_id", projectID,
-						"repo", fullName,
-						"issue_id", it.ID,
-						"updated_at", *it.UpdatedAt,
-						"error", err,
-					)
-				}
-			}
-			if it.ClosedAt != nil && *it.ClosedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.ClosedAt); err == nil {
-					closedAt = &t
-				} else {
-					slog.Warn("failed to parse issue closed_at",
-						"project_id", projectID,
-						"repo", fullName,
-						"issue_id", it.ID,
-						"closed_at", *it.ClosedAt,
-						"error", err,
-					)
-				}
-			}
-			
 			// Fetch comments for this issue (if comments_count > 0)
-			var commentsJSON []byte = []byte("[]")
+			commentsJSON := []byte("[]")
 			if it.Comments > 0 {
 				if err := w.limiter.Wait(ctx); err == nil {
-					comments, err := w.gh.ListIssueComments(ctx, token, fullName, it.Number)
-					if err == nil {
+					if comments, err := w.gh.ListIssueComments(ctx, token, fullName, it.Number); err == nil {
 						commentsJSON, _ = json.Marshal(comments)
 					}
 				}
 			}
-			
-			_, _ = w.pool.Exec(ctx, `
-INSERT INTO github_issues (project_id, github_issue_id, number, state, title, body, author_login, url, assignees, labels, comments_count, comments, created_at_github, updated_at_github, closed_at_github, last_seen_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, now())
-ON CONFLICT (project_id, github_issue_id) DO UPDATE SET
-  number = EXCLUDED.number,
-  state = EXCLUDED.state,
-  title = EXCLUDED.title,
-  body = EXCLUDED.body,
-  author_login = EXCLUDED.author_login,
-  url = EXCLUDED.url,
-  assignees = EXCLUDED.assignees,
-  labels = EXCLUDED.labels,
-  comments_count = EXCLUDED.comments_count,
-  comments = EXCLUDED.comments,
-  created_at_github = COALESCE(EXCLUDED.created_at_github, github_issues.created_at_github),
-  updated_at_github = COALESCE(EXCLUDED.updated_at_github, github_issues.updated_at_github),
-  closed_at_github = COALESCE(EXCLUDED.closed_at_github, github_issues.closed_at_github),
-  last_seen_at = now()
-`, projectID, it.ID, it.Number, it.State, it.Title, it.Body, it.User.Login, it.HTMLURL, assigneesJSON, labelsJSON, it.Comments, commentsJSON, createdAt, updatedAt, closedAt)
+			rows = append(rows, issueRow{IssueListItem: it, commentsJSON: commentsJSON})
+		}
+		return rows, true, nil
+	})
+
+	totalIssues, failedRows := 0, 0
+	for p := range pages {
+		if p.err != nil {
+			return p.err
+		}
+		batch := &pgx.Batch{}
+		for _, it := range p.items {
+			// Convert assignees to JSONB (array of login strings)
+			assigneesJSON, _ := json.Marshal(it.Assignees)
+			// Convert labels to JSONB (array of {name, color} objects)
+			labelsJSON, _ := json.Marshal(it.Labels)
+			attrs := []any{"project_id", projectID, "repo", fullName, "issue_id", it.ID}
+			createdAt := parseGitHubTime("created_at", it.CreatedAt, attrs...)
+			updatedAt := parseGitHubTime("updated_at", it.UpdatedAt, attrs...)
+			closedAt := parseGitHubTime("closed_at", it.ClosedAt, attrs...)
+			batch.Queue(upsertIssueSQL, projectID, it.ID, it.Number, it.State, it.Title, it.Body, it.User.Login, it.HTMLURL, assigneesJSON, labelsJSON, it.Comments, it.commentsJSON, createdAt, updatedAt, closedAt, it.StateReason)
+		}
+		failed, err := execBatch(ctx, w.pool, batch)
+		if err != nil {
+			return err
 		}
+		totalIssues += len(p.items)
+		failedRows += failed
 	}
-	
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	slog.Info("sync issues completed",
 		"project_id", projectID,
 		"repo", fullName,
 		"total_issues", totalIssues,
+		"failed_rows", failedRows,
 	)
 	return nil
 }
 
+const upsertPRSQL = `
+INSERT INTO github_pull_requests (project_id, github_pr_id, number, state, title, body, author_login, url, merged, created_at_github, updated_at_github, closed_at_github, merged_at_github, draft, last_pushed_at, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $10, now())
+ON CONFLICT (project_id, github_pr_id) DO UPDATE SET
+  number = EXCLUDED.number,
+  state = EXCLUDED.state,
+  title = EXCLUDED.title,
+  body = EXCLUDED.body,
+  author_login = EXCLUDED.author_login,
+  url = EXCLUDED.url,
+  merged = EXCLUDED.merged,
+  created_at_github = EXCLUDED.created_at_github,
+  updated_at_github = EXCLUDED.updated_at_github,
+  closed_at_github = EXCLUDED.closed_at_github,
+  merged_at_github = EXCLUDED.merged_at_github,
+  draft = EXCLUDED.draft,
+  last_pushed_at = COALESCE(github_pull_requests.last_pushed_at, EXCLUDED.last_pushed_at),
+  last_seen_at = now()
+`
+
+// syncPRs streams the repository's pull requests page by page and upserts every page in one batch. Reviews,
+// diff stats and contributors are then fetched for the pull requests that changed since the last sync.
 func (w *Worker) syncPRs(ctx context.Context, projectID uuid.UUID, fullName string, token string) error {
-	totalPRs := 0
-	for page := 1; page <= 50; page++ { // safety cap
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := streamPages(ctx, func(ctx context.Context, page int) ([]github.PRListItem, bool, error) {
 		if err := w.limiter.Wait(ctx); err != nil {
-			return err
+			return nil, false, err
 		}
 		items, err := w.gh.ListPRsPage(ctx, token, fullName, page)
 		if err != nil {
@@ -300,65 +367,393 @@ func (w *Worker) syncPRs(ctx context.Context, projectID uuid.UUID, fullName stri
 				"page", page,
 				"error", err,
 			)
-			return err
+			return nil, false, err
 		}
-		if len(items) == 0 {
-			slog.Info("sync PRs completed",
-				"project_id", projectID,
-				"repo", fullName,
-				"total_prs", totalPRs,
-			)
-			return nil
+		return items, len(items) > 0, nil
+	})
+
+	totalPRs, failedRows := 0, 0
+	for p := range pages {
+		if p.err != nil {
+			return p.err
+		}
+		if len(p.items) == 0 {
+			continue
 		}
 
-		for _, it := range items {
-			totalPRs++
-			
-			// Parse date strings from GitHub API
-			var createdAt, updatedAt, closedAt, mergedAt *time.Time
-			if it.CreatedAt != nil && *it.CreatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.CreatedAt); err == nil {
-					createdAt = &t
+		// Reviews and diff stats only change when the PR does; skip the extra calls for PRs we already have at
+		// this version.
+		ids := make([]int64, len(p.items))
+		for i, it := range p.items {
+			ids[i] = it.ID
+		}
+		prevUpdatedAt := map[int64]time.Time{}
+		if rows, err := w.pool.Query(ctx, `
+SELECT github_pr_id, updated_at_github FROM github_pull_requests
+WHERE project_id = $1 AND github_pr_id = ANY($2) AND updated_at_github IS NOT NULL
+`, projectID, ids); err == nil {
+			for rows.Next() {
+				var id int64
+				var t time.Time
+				if rows.Scan(&id, &t) == nil {
+					prevUpdatedAt[id] = t
 				}
 			}
-			if it.UpdatedAt != nil && *it.UpdatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.UpdatedAt); err == nil {
-					updatedAt = &t
-				}
+			rows.Close()
+		}
+
+		type followUp struct {
+			number int
+			author string
+			merged bool
+		}
+		var stale []followUp
+		batch := &pgx.Batch{}
+		for _, it := range p.items {
+			createdAt := parseGitHubTime("created_at", it.CreatedAt)
+			updatedAt := parseGitHubTime("updated_at", it.UpdatedAt)
+			closedAt := parseGitHubTime("closed_at", it.ClosedAt)
+			mergedAt := parseGitHubTime("merged_at", it.MergedAt)
+			if prev, ok := prevUpdatedAt[it.ID]; !ok || updatedAt == nil || !prev.Equal(*updatedAt) {
+				stale = append(stale, followUp{number: it.Number, author: it.User.Login, merged: it.Merged || mergedAt != nil})
 			}
-			if it.ClosedAt != nil && *it.ClosedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.ClosedAt); err == nil {
-					closedAt = &t
-				}
+			batch.Queue(upsertPRSQL, projectID, it.ID, it.Number, it.State, it.Title, it.Body, it.User.Login, it.HTMLURL, it.Merged, createdAt, updatedAt, closedAt, mergedAt, it.Draft)
+		}
+		failed, err := execBatch(ctx, w.pool, batch)
+		if err != nil {
+			return err
+		}
+		totalPRs += len(p.items)
+		failedRows += failed
+
+		for _, pr := range stale {
+			if err := w.syncPRStats(ctx, projectID, fullName, token, pr.number); err != nil {
+				slog.Warn("failed to sync PR diff stats",
+					"project_id", projectID,
+					"repo", fullName,
+					"pr_number", pr.number,
+					"error", err,
+				)
+			}
+			if err := w.syncPRReviews(ctx, projectID, fullName, token, pr.number); err != nil {
+				slog.Warn("failed to sync PR reviews",
+					"project_id", projectID,
+					"repo", fullName,
+					"pr_number", pr.number,
+					"error", err,
+				)
 			}
-			if it.MergedAt != nil && *it.MergedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *it.MergedAt); err == nil {
-					mergedAt = &t
+			if pr.merged {
+				if err := w.syncPRContributors(ctx, projectID, fullName, token, pr.number, pr.author); err != nil {
+					slog.Warn("failed to sync PR contributors",
+						"project_id", projectID,
+						"repo", fullName,
+						"pr_number", pr.number,
+						"error", err,
+					)
 				}
 			}
-			
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	slog.Info("sync PRs completed",
+		"project_id", projectID,
+		"repo", fullName,
+		"total_prs", totalPRs,
+		"failed_rows", failedRows,
+	)
+	return nil
+}
+
+// syncDiscussions upserts the repository's discussions, most recently updated first.
+func (w *Worker) syncDiscussions(ctx context.Context, projectID uuid.UUID, fullName string, token string) error {
+	total := 0
+	cursor := ""
+	for page := 1; page <= 40; page++ { // safety cap
+		if err := w.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		items, next, err := w.gh.ListDiscussionsPage(ctx, token, fullName, cursor)
+		if err != nil {
+			return err
+		}
+		for _, d := range items {
+			total++
 			_, _ = w.pool.Exec(ctx, `
-INSERT INTO github_pull_requests (project_id, github_pr_id, number, state, title, body, author_login, url, merged, created_at_github, updated_at_github, closed_at_github, merged_at_github, last_seen_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now())
-ON CONFLICT (project_id, github_pr_id) DO UPDATE SET
-  number = EXCLUDED.number,
-  state = EXCLUDED.state,
+INSERT INTO github_discussions (project_id, node_id, number, title, url, author_login, category, answerable, closed, answer_author_login, answer_url, answered_at, created_at_github, updated_at_github, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), $12, $13, $14, now())
+ON CONFLICT (project_id, number) DO UPDATE SET
+  node_id = EXCLUDED.node_id,
   title = EXCLUDED.title,
-  body = EXCLUDED.body,
-  author_login = EXCLUDED.author_login,
   url = EXCLUDED.url,
-  merged = EXCLUDED.merged,
+  author_login = EXCLUDED.author_login,
+  category = EXCLUDED.category,
+  answerable = EXCLUDED.answerable,
+  closed = EXCLUDED.closed,
+  answer_author_login = EXCLUDED.answer_author_login,
+  answer_url = EXCLUDED.answer_url,
+  answered_at = EXCLUDED.answered_at,
   created_at_github = EXCLUDED.created_at_github,
   updated_at_github = EXCLUDED.updated_at_github,
-  closed_at_github = EXCLUDED.closed_at_github,
-  merged_at_github = EXCLUDED.merged_at_github,
   last_seen_at = now()
-`, projectID, it.ID, it.Number, it.State, it.Title, it.Body, it.User.Login, it.HTMLURL, it.Merged, createdAt, updatedAt, closedAt, mergedAt)
+`, projectID, d.NodeID, d.Number, d.Title, d.URL, d.Author, d.Category, d.Answerable, d.Closed, d.AnswerAuthor, d.AnswerURL, d.AnsweredAt, d.CreatedAt, d.UpdatedAt)
+		}
+		if next == "" {
+			break
 		}
+		cursor = next
 	}
+
+	slog.Info("sync discussions completed",
+		"project_id", projectID,
+		"repo", fullName,
+		"total_discussions", total,
+	)
 	return nil
 }
 
+// syncRepo refreshes stars, forks, license, topics, and the language breakdown of the project's repository.
+func (w *Worker) syncRepo(ctx context.Context, projectID uuid.UUID, fullName string, token string) error {
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	repo, err := w.gh.GetRepo(ctx, token, fullName)
+	if err != nil {
+		return err
+	}
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	langs, err := w.gh.GetRepoLanguages(ctx, token, fullName)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, v := range langs {
+		total += v
+	}
+	type langShare struct {
+		Name       string  `json:"name"`
+		Percentage float64 `json:"percentage"`
+	}
+	breakdown := make([]langShare, 0, len(langs))
+	primary := ""
+	var primaryBytes int64
+	for name, v := range langs {
+		if total > 0 {
+			breakdown = append(breakdown, langShare{Name: name, Percentage: float64(v) * 100.0 / float64(total)})
+		}
+		if v > primaryBytes {
+			primary, primaryBytes = name, v
+		}
+	}
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Percentage > breakdown[j].Percentage })
+	languagesJSON, _ := json.Marshal(breakdown)
+
+	topics := repo.Topics
+	if topics == nil {
+		topics = []string{}
+	}
+	topicsJSON, _ := json.Marshal(topics)
+
+	var license *string
+	if repo.License != nil && repo.License.SPDXID != "" && repo.License.SPDXID != "NOASSERTION" {
+		license = &repo.License.SPDXID
+	}
+
+	// The primary language only fills in a missing value; maintainers may have set it deliberately.
+	_, err = w.pool.Exec(ctx, `
+UPDATE projects
+SET stars_count = $2,
+    forks_count = $3,
+    languages = $4::jsonb,
+    topics = $5::jsonb,
+    license_spdx = $6,
+    language = COALESCE(NULLIF(language, ''), NULLIF($7, '')),
+    repo_synced_at = now(),
+    updated_at = now()
+WHERE id = $1
+`, projectID, repo.StargazersCount, repo.ForksCount, string(languagesJSON), string(topicsJSON), license, primary)
+	return err
+}
+
+// syncDocs caches the README and CONTRIBUTING guidelines rendered to HTML. The GitHub App token is
+// preferred (higher rate limits, no dependency on the owner's OAuth grant); userToken is the fallback.
+func (w *Worker) syncDocs(ctx context.Context, projectID uuid.UUID, fullName string, userToken string) {
+	token := userToken
+	var installationID string
+	_ = w.pool.QueryRow(ctx, `SELECT COALESCE(github_app_installation_id, '') FROM projects WHERE id = $1`, projectID).Scan(&installationID)
+	if installationID != "" && w.cfg.GitHubAppID != "" && w.cfg.GitHubAppPrivateKey != "" {
+		if appClient, err := github.NewInstallationAppClient(ctx, w.pool, w.cfg, installationID); err == nil {
+			if t, err := appClient.GetInstallationToken(ctx, installationID); err == nil {
+				token = t
+			}
+		}
+	}
+
+	store := func(kind, html, path string) {
+		_, _ = w.pool.Exec(ctx, `
+INSERT INTO project_docs (project_id, kind, html, source_path, fetched_at)
+VALUES ($1, $2, $3, NULLIF($4, ''), now())
+ON CONFLICT (project_id, kind) DO UPDATE SET html = EXCLUDED.html, source_path = EXCLUDED.source_path, fetched_at = now()
+`, projectID, kind, html, path)
+	}
+	remove := func(kind string) {
+		_, _ = w.pool.Exec(ctx, `DELETE FROM project_docs WHERE project_id = $1 AND kind = $2`, projectID, kind)
+	}
+
+	if err := w.limiter.Wait(ctx); err != nil {
+		return
+	}
+	if html, err := w.gh.GetReadmeHTML(ctx, token, fullName); err == nil {
+		store("readme", html, "")
+	} else if errors.Is(err, github.ErrDocNotFound) {
+		remove("readme")
+	} else {
+		slog.Warn("failed to fetch README", "project_id", projectID, "repo", fullName, "error", err)
+	}
+
+	if err := w.limiter.Wait(ctx); err != nil {
+		return
+	}
+	if html, path, err := w.gh.GetContributingHTML(ctx, token, fullName); err == nil {
+		store("contributing", html, path)
+	} else if errors.Is(err, github.ErrDocNotFound) {
+		remove("contributing")
+	} else {
+		slog.Warn("failed to fetch CONTRIBUTING", "project_id", projectID, "repo", fullName, "error", err)
+	}
+}
+
+// syncPRStats stores the additions, deletions and changed files of a pull request.
+func (w *Worker) syncPRStats(ctx context.Context, projectID uuid.UUID, fullName string, token string, prNumber int) error {
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	st, err := w.gh.GetPRStats(ctx, token, fullName, prNumber)
+	if err != nil {
+		return err
+	}
+	_, err = w.pool.Exec(ctx, `
+UPDATE github_pull_requests SET additions = $3, deletions = $4, changed_files = $5
+WHERE project_id = $1 AND number = $2
+`, projectID, prNumber, st.Additions, st.Deletions, st.ChangedFiles)
+	return err
+}
+
+// syncPRReviews stores the reviews and inline review comments of a pull request.
+func (w *Worker) syncPRReviews(ctx context.Context, projectID uuid.UUID, fullName string, token string, prNumber int) error {
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	reviews, err := w.gh.ListPRReviews(ctx, token, fullName, prNumber)
+	if err != nil {
+		return err
+	}
+	for _, r := range reviews {
+		// Pending reviews are drafts visible only to their author.
+		if r.State == "PENDING" || r.User.Login == "" {
+			continue
+		}
+		var submittedAt *time.Time
+		if r.SubmittedAt != nil {
+			if t, err := time.Parse(time.RFC3339, *r.SubmittedAt); err == nil {
+				submittedAt = &t
+			}
+		}
+		_, _ = w.pool.Exec(ctx, `
+INSERT INTO github_pr_reviews (project_id, pr_number, github_review_id, reviewer_login, reviewer_github_id, state, body, submitted_at_github, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+ON CONFLICT (project_id, github_review_id) DO UPDATE SET
+  state = EXCLUDED.state,
+  body = EXCLUDED.body,
+  submitted_at_github = COALESCE(EXCLUDED.submitted_at_github, github_pr_reviews.submitted_at_github),
+  last_seen_at = now()
+`, projectID, prNumber, r.ID, r.User.Login, r.User.ID, r.State, r.Body, submittedAt)
+	}
+
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	comments, err := w.gh.ListPRReviewComments(ctx, token, fullName, prNumber)
+	if err != nil {
+		return err
+	}
+	for _, com := range comments {
+		if com.User.Login == "" {
+			continue
+		}
+		var createdAt, updatedAt *time.Time
+		if t, err := time.Parse(time.RFC3339, com.CreatedAt); err == nil {
+			createdAt = &t
+		}
+		if t, err := time.Parse(time.RFC3339, com.UpdatedAt); err == nil {
+			updatedAt = &t
+		}
+		_, _ = w.pool.Exec(ctx, `
+INSERT INTO github_pr_review_comments (project_id, pr_number, github_comment_id, github_review_id, reviewer_login, reviewer_github_id, body, path, created_at_github, updated_at_github, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, now())
+ON CONFLICT (project_id, github_comment_id) DO UPDATE SET
+  body = EXCLUDED.body,
+  updated_at_github = EXCLUDED.updated_at_github,
+  last_seen_at = now()
+`, projectID, prNumber, com.ID, com.PullRequestReviewID, com.User.Login, com.User.ID, com.Body, com.Path, createdAt, updatedAt)
+	}
+	return nil
+}
+
+// syncPRContributors stores the author and co-authors of a merged PR, derived from its commits.
+func (w *Worker) syncPRContributors(ctx context.Context, projectID uuid.UUID, fullName string, token string, prNumber int, prAuthor string) error {
+	if err := w.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	commits, err := w.gh.ListPRCommits(ctx, token, fullName, prNumber)
+	if err != nil {
+		return err
+	}
+	in := make([]attribution.Commit, 0, len(commits))
+	for _, cm := range commits {
+		ac := attribution.Commit{
+			AuthorName:  cm.Commit.Author.Name,
+			AuthorEmail: cm.Commit.Author.Email,
+			Message:     cm.Commit.Message,
+		}
+		if cm.Author != nil {
+			ac.AuthorLogin = cm.Author.Login
+		}
+		in = append(in, ac)
+	}
+	contributors := attribution.Contributors(prAuthor, in)
+	if len(contributors) == 0 {
+		return nil
+	}
+	// Shares in basis points so they always add up to exactly 1.
+	shares := attribution.SplitPoints(10000, len(contributors))
+
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM github_pr_contributors WHERE project_id = $1 AND pr_number = $2`, projectID, prNumber); err != nil {
+		return err
+	}
+	for i, ct := range contributors {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO github_pr_contributors (project_id, pr_number, identity, login, name, email, role, commits, share)
+VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9::numeric / 10000)
+`, projectID, prNumber, ct.Identity(), ct.Login, ct.Name, ct.Email, ct.Role, ct.Commits, shares[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
 func hostname() string {
 	h, _ := os.Hostname()
 	if h == "" {
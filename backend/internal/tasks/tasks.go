@@ -0,0 +1,34 @@
+// Package tasks describes native tasks: non-code contributions (documentation, translations, design)
+// created in Grainlify without a GitHub issue. They are stored alongside synced issues in github_issues so
+// they go through the same application, assignment and points pipeline, with numbers from a range GitHub
+// never reaches so issue numbers stay unique per project. Anything that would call GitHub for an issue
+// checks IsNative first.
+package tasks
+
+// Task types. TypeCode is an ordinary GitHub issue; the others are native.
+const (
+	TypeCode        = "code"
+	TypeDocs        = "docs"
+	TypeTranslation = "translation"
+	TypeDesign      = "design"
+	TypeOther       = "other"
+)
+
+// NativeTypes are the types a native task can have.
+var NativeTypes = []string{TypeDocs, TypeTranslation, TypeDesign, TypeOther}
+
+// FirstNumber is the number of a project's first native task; later ones count up from it.
+const FirstNumber = 1_000_000_000
+
+// IsNative reports whether an issue number belongs to a native task.
+func IsNative(number int) bool { return number >= FirstNumber }
+
+// ValidNativeType reports whether t is a native task type.
+func ValidNativeType(t string) bool {
+	for _, n := range NativeTypes {
+		if t == n {
+			return true
+		}
+	}
+	return false
+}
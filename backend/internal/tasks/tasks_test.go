@@ -0,0 +1,14 @@
+package tasks
+
+import "testing"
+
+func TestIsNative(t *testing.T) {
+	for n, want := range map[int]bool{1: false, 48213: false, FirstNumber - 1: false, FirstNumber: true, FirstNumber + 41: true} {
+		if got := IsNative(n); got != want {
+			t.Errorf("IsNative(%d) = %v, want %v", n, got, want)
+		}
+	}
+	if ValidNativeType(TypeCode) || !ValidNativeType(TypeTranslation) || ValidNativeType("Docs") {
+		t.Error("ValidNativeType")
+	}
+}
@@ -0,0 +1,150 @@
+package tenant
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cacheTTL bounds how long a tenant change made on another instance takes to be picked up.
+const cacheTTL = time.Minute
+
+// Resolver maps hosts to tenants. The tenants table is small, so it is cached whole and reloaded
+// when older than cacheTTL (or after Invalidate).
+type Resolver struct {
+	pool *pgxpool.Pool
+
+	mu     sync.RWMutex
+	byHost map[string]*Tenant
+	def    *Tenant
+	loaded time.Time
+}
+
+func NewResolver(pool *pgxpool.Pool) *Resolver {
+	return &Resolver{pool: pool}
+}
+
+// Invalidate drops the cache, so the next lookup sees changes made through the admin API.
+func (r *Resolver) Invalidate() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.loaded = time.Time{}
+	r.mu.Unlock()
+}
+
+func (r *Resolver) load(ctx context.Context) error {
+	r.mu.RLock()
+	fresh := time.Since(r.loaded) < cacheTTL
+	r.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT id, slug, name, hosts, branding, github_app_id, frontend_url, is_default
+FROM tenants
+`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byHost := map[string]*Tenant{}
+	var def *Tenant
+	for rows.Next() {
+		var t Tenant
+		if err := rows.Scan(&t.ID, &t.Slug, &t.Name, &t.Hosts, &t.Branding, &t.GitHubAppID, &t.FrontendURL, &t.IsDefault); err != nil {
+			return err
+		}
+		for _, h := range t.Hosts {
+			if h = NormalizeHost(h); h != "" {
+				byHost[h] = &t
+			}
+		}
+		if t.IsDefault {
+			def = &t
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if def == nil {
+		def = &Tenant{ID: DefaultID, Slug: "default", IsDefault: true}
+	}
+
+	r.mu.Lock()
+	r.byHost, r.def, r.loaded = byHost, def, time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// Lookup returns the tenant for host, or nil when no tenant claims it.
+func (r *Resolver) Lookup(ctx context.Context, host string) (*Tenant, error) {
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byHost[NormalizeHost(host)], nil
+}
+
+// Default returns the default tenant.
+func (r *Resolver) Default(ctx context.Context) (*Tenant, error) {
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.def, nil
+}
+
+// AllowsOrigin reports whether origin is one of a tenant's hosts (for CORS). It only consults the
+// cache, which the middleware keeps loaded.
+func (r *Resolver) AllowsOrigin(origin string) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.byHost[NormalizeHost(origin)]
+	return ok
+}
+
+// Middleware resolves the request's tenant: the Origin header (a tenant's frontend calling a shared
+// API host), then the Host header (a tenant with its own API host), then the default tenant.
+// Webhooks are left unscoped: one GitHub delivery may concern any tenant.
+func (r *Resolver) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if r == nil || r.pool == nil || unscoped(c.Path()) {
+			return c.Next()
+		}
+		var t *Tenant
+		var err error
+		if origin := c.Get(fiber.HeaderOrigin); origin != "" {
+			t, err = r.Lookup(c.Context(), origin)
+		}
+		if t == nil && err == nil {
+			t, err = r.Lookup(c.Context(), c.Hostname())
+		}
+		if t == nil && err == nil {
+			t, err = r.Default(c.Context())
+		}
+		if err != nil {
+			slog.Error("failed to resolve tenant", "host", c.Hostname(), "error", err)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "tenant_lookup_failed"})
+		}
+		c.Locals(Key, t)
+		return c.Next()
+	}
+}
+
+func unscoped(path string) bool {
+	return strings.HasPrefix(path, "/webhooks/") || path == "/health" || path == "/ready"
+}
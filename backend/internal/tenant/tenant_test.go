@@ -0,0 +1,54 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	cases := map[string]string{
+		"Grants.Example.org":         "grants.example.org",
+		"grants.example.org:8443":    "grants.example.org",
+		"https://grants.example.org": "grants.example.org",
+		"http://localhost:5173/path": "localhost",
+		"grants.example.org.":        "grants.example.org",
+		"[::1]:8080":                 "::1",
+		"  api.grants.example.org  ": "api.grants.example.org",
+		"":                           "",
+	}
+	for in, want := range cases {
+		if got := NormalizeHost(in); got != want {
+			t.Errorf("NormalizeHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestHasHost(t *testing.T) {
+	tn := &Tenant{Hosts: []string{"grants.example.org", "API.grants.example.org"}}
+	if !tn.HasHost("https://grants.example.org") || !tn.HasHost("api.grants.example.org:443") {
+		t.Fatal("expected configured hosts to match")
+	}
+	if tn.HasHost("example.org") || tn.HasHost("") {
+		t.Fatal("unexpected match")
+	}
+	var none *Tenant
+	if none.HasHost("grants.example.org") {
+		t.Fatal("nil tenant has no hosts")
+	}
+}
+
+func TestContext(t *testing.T) {
+	id := uuid.New()
+	ctx := WithID(context.Background(), id)
+	if got := IDFromContext(ctx); got != id.String() {
+		t.Fatalf("IDFromContext = %q, want %q", got, id)
+	}
+	if got := IDFromContext(Unscoped(ctx)); got != "" {
+		t.Fatalf("Unscoped context still has tenant %q", got)
+	}
+	if got := IDFromContext(context.Background()); got != "" {
+		t.Fatalf("background context has tenant %q", got)
+	}
+}
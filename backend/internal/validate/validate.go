@@ -0,0 +1,171 @@
+// Package validate checks request fields and collects every failure with the field and the reason, so an
+// API client can point at the input that needs fixing instead of guessing from one error code.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Failure codes.
+const (
+	Required = "required"
+	TooLong  = "too_long"
+	TooMany  = "too_many"
+	Invalid  = "invalid"
+)
+
+// FieldError is one failed field. Field is a path into the request body, e.g. "links[2].url".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Max is the limit a too_long or too_many field exceeded.
+	Max int `json:"max,omitempty"`
+}
+
+// Errors is every field that failed, in the order checked.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, f := range e {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Code is the single error code for e, in the handlers' older style: "<field>_required", "<field>_too_long",
+// "<field>_too_many" or "invalid_<field>" for the first failure, using the top-level field of its path.
+func (e Errors) Code() string {
+	if len(e) == 0 {
+		return ""
+	}
+	field := e[0].Field
+	if i := strings.IndexAny(field, ".["); i > 0 {
+		field = field[:i]
+	}
+	if e[0].Code == Invalid {
+		return "invalid_" + field
+	}
+	return field + "_" + e[0].Code
+}
+
+// Validator accumulates failures. The zero value is ready to use.
+type Validator struct {
+	errs Errors
+}
+
+// Fail records a failure. The checks below use it; handlers call it for rules of their own.
+func (v *Validator) Fail(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required fails field if s is blank.
+func (v *Validator) Required(field, s string) bool {
+	if strings.TrimSpace(s) == "" {
+		v.Fail(field, Required, "is required")
+		return false
+	}
+	return true
+}
+
+// MaxLen fails field if s is longer than max characters.
+func (v *Validator) MaxLen(field, s string, max int) bool {
+	if utf8.RuneCountInString(s) > max {
+		v.errs = append(v.errs, FieldError{Field: field, Code: TooLong, Message: fmt.Sprintf("must be at most %d characters", max), Max: max})
+		return false
+	}
+	return true
+}
+
+// MaxItems fails field if it has more than max entries.
+func (v *Validator) MaxItems(field string, n, max int) bool {
+	if n > max {
+		v.errs = append(v.errs, FieldError{Field: field, Code: TooMany, Message: fmt.Sprintf("must have at most %d entries", max), Max: max})
+		return false
+	}
+	return true
+}
+
+// URL fails field unless *s is an absolute http(s) URL, and normalises it in place (see NormalizeURL). Blank
+// passes; combine with Required.
+func (v *Validator) URL(field string, s *string) bool {
+	if *s == "" {
+		return true
+	}
+	n, ok := NormalizeURL(*s)
+	if !ok {
+		v.Fail(field, Invalid, "must be an http(s) URL")
+		return false
+	}
+	*s = n
+	return true
+}
+
+// NormalizeURL returns s as an absolute http(s) URL with a lower-case scheme and host and without a default
+// port, so equal links compare equal. ok is false if s is not such a URL.
+func NormalizeURL(s string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", false
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if host == "" {
+		return "", false
+	}
+	if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	return u.String(), true
+}
+
+// OneOf fails field unless s is one of allowed.
+func (v *Validator) OneOf(field, s string, allowed ...string) bool {
+	for _, a := range allowed {
+		if s == a {
+			return true
+		}
+	}
+	v.Fail(field, Invalid, "must be one of "+strings.Join(allowed, ", "))
+	return false
+}
+
+// Range fails field unless min <= n <= max.
+func (v *Validator) Range(field string, n, min, max int) bool {
+	if n < min || n > max {
+		v.Fail(field, Invalid, fmt.Sprintf("must be between %d and %d", min, max))
+		return false
+	}
+	return true
+}
+
+// Err returns the failures as Errors, or nil if every check passed.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Index builds the path of a list element's field, e.g. Index("links", 2, "url") is "links[2].url".
+func Index(field string, i int, sub string) string {
+	p := fmt.Sprintf("%s[%d]", field, i)
+	if sub != "" {
+		p += "." + sub
+	}
+	return p
+}
@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	var v Validator
+	v.Required("name", "  ")
+	v.MaxLen("message", "héllo", 4)
+	bad, blank := "javascript:alert(1)", ""
+	v.URL(Index("links", 1, "url"), &bad)
+	v.URL("website_url", &blank)
+	v.OneOf("status", "archived", "active", "inactive")
+	v.Range("max", 3, 0, 10)
+	v.MaxItems("technologies", 3, 2)
+
+	var errs Errors
+	if !errors.As(v.Err(), &errs) {
+		t.Fatalf("Err() = %v", v.Err())
+	}
+	want := []FieldError{
+		{Field: "name", Code: Required},
+		{Field: "message", Code: TooLong, Max: 4},
+		{Field: "links[1].url", Code: Invalid},
+		{Field: "status", Code: Invalid},
+		{Field: "technologies", Code: TooMany, Max: 2},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errs = %+v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Field != w.Field || errs[i].Code != w.Code || errs[i].Max != w.Max || errs[i].Message == "" {
+			t.Errorf("errs[%d] = %+v, want %+v", i, errs[i], w)
+		}
+	}
+
+	var ok Validator
+	ok.MaxLen("message", "héllo", 5)
+	site := "HTTPS://Example.com:443/x"
+	ok.URL("website_url", &site)
+	if err := ok.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if site != "https://example.com/x" {
+		t.Errorf("normalised url = %q", site)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	for in, want := range map[string]string{
+		" https://Docs.Example.COM/Path?q=1 ": "https://docs.example.com/Path?q=1",
+		"http://example.com:80/":              "http://example.com/",
+		"https://example.com:8443":            "https://example.com:8443",
+		"https://[::1]:443/x":                 "https://[::1]/x",
+	} {
+		if got, ok := NormalizeURL(in); !ok || got != want {
+			t.Errorf("NormalizeURL(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "example.com", "ftp://example.com", "javascript:alert(1)", "https://user:pw@example.com", "https:///x"} {
+		if got, ok := NormalizeURL(in); ok {
+			t.Errorf("NormalizeURL(%q) = %q, want rejected", in, got)
+		}
+	}
+}
+
+func TestErrorsCode(t *testing.T) {
+	for _, tc := range []struct {
+		errs Errors
+		want string
+	}{
+		{Errors{{Field: "message", Code: TooLong}}, "message_too_long"},
+		{Errors{{Field: "name", Code: Required}, {Field: "status", Code: Invalid}}, "name_required"},
+		{Errors{{Field: "links[0].url", Code: Invalid}}, "invalid_links"},
+		{Errors{{Field: "status", Code: Invalid}}, "invalid_status"},
+		{nil, ""},
+	} {
+		if got := tc.errs.Code(); got != tc.want {
+			t.Errorf("%+v.Code() = %q, want %q", tc.errs, got, tc.want)
+		}
+	}
+}
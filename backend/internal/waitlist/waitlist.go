@@ -0,0 +1,59 @@
+// Package waitlist hands an issue to the next contributor in line when its assignee is released.
+package waitlist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/jagadeesh/grainlify/backend/internal/notify"
+)
+
+// NotifyNext marks the earliest waiting contributor on an issue as notified and sends them an
+// in-app notification. It returns their GitHub login, or "" when nobody is waiting.
+func NotifyNext(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) (string, error) {
+	var userID uuid.UUID
+	var login string
+	err := pool.QueryRow(ctx, `
+UPDATE issue_waitlist SET status = 'notified', notified_at = now(), updated_at = now()
+WHERE id = (
+  SELECT id FROM issue_waitlist
+  WHERE project_id = $1 AND issue_number = $2 AND status = 'waiting'
+  ORDER BY created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING user_id, github_login
+`, projectID, issueNumber).Scan(&userID, &login)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_ = notify.Store(ctx, pool, userID, "waitlist_turn", map[string]any{
+		"project_id":   projectID.String(),
+		"issue_number": issueNumber,
+	})
+	return login, nil
+}
+
+// Promote records that a waitlisted contributor was assigned the issue.
+func Promote(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int, login string) {
+	_, _ = pool.Exec(ctx, `
+UPDATE issue_waitlist SET status = 'promoted', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND LOWER(github_login) = LOWER($3) AND status IN ('waiting', 'notified')
+`, projectID, issueNumber, login)
+}
+
+// Close ends the waitlist of an issue that was closed.
+func Close(ctx context.Context, pool *pgxpool.Pool, projectID uuid.UUID, issueNumber int) {
+	_, _ = pool.Exec(ctx, `
+UPDATE issue_waitlist SET status = 'left', updated_at = now()
+WHERE project_id = $1 AND issue_number = $2 AND status IN ('waiting', 'notified')
+`, projectID, issueNumber)
+}
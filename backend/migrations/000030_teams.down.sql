@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS team_event_members;
+DROP TABLE IF EXISTS team_invites;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
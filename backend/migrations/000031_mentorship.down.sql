@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS mentorship_notes;
+DROP TABLE IF EXISTS mentorships;
+DROP INDEX IF EXISTS idx_github_issues_mentored;
+ALTER TABLE github_issues
+  DROP COLUMN IF EXISTS mentor_user_id,
+  DROP COLUMN IF EXISTS is_mentored;
@@ -0,0 +1,35 @@
+-- Mentorship: maintainers flag issues as mentored; contributors request a mentor.
+ALTER TABLE github_issues
+  ADD COLUMN IF NOT EXISTS is_mentored BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS mentor_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_github_issues_mentored ON github_issues(project_id) WHERE is_mentored = true;
+
+CREATE TABLE IF NOT EXISTS mentorships (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  mentor_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  mentee_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER,
+  status TEXT NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'active', 'declined', 'completed')),
+  message TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mentorships_open_pair
+  ON mentorships(mentor_user_id, mentee_user_id, project_id)
+  WHERE status IN ('requested', 'active');
+CREATE INDEX IF NOT EXISTS idx_mentorships_mentor ON mentorships(mentor_user_id, status);
+CREATE INDEX IF NOT EXISTS idx_mentorships_mentee ON mentorships(mentee_user_id, status);
+
+CREATE TABLE IF NOT EXISTS mentorship_notes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  mentorship_id UUID NOT NULL REFERENCES mentorships(id) ON DELETE CASCADE,
+  author_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  session_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentorship_notes_mentorship ON mentorship_notes(mentorship_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS issue_discussion_comments;
+DROP TABLE IF EXISTS notifications;
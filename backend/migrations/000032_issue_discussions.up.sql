@@ -0,0 +1,32 @@
+-- In-app notifications (delivered via API; no external channel yet).
+CREATE TABLE IF NOT EXISTS notifications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Private discussion threads between maintainers and an applicant on an issue.
+-- These are never mirrored to GitHub.
+CREATE TABLE IF NOT EXISTS issue_discussion_comments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  thread_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  author_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  hidden_at TIMESTAMPTZ,
+  hidden_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  hidden_reason TEXT,
+  deleted_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_discussion_thread
+  ON issue_discussion_comments(project_id, issue_number, thread_user_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS issue_application_answers;
+DROP TABLE IF EXISTS project_application_questions;
@@ -0,0 +1,25 @@
+-- Project-specific questions applicants must answer before a maintainer can assign them.
+CREATE TABLE IF NOT EXISTS project_application_questions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  prompt TEXT NOT NULL,
+  required BOOLEAN NOT NULL DEFAULT true,
+  position INTEGER NOT NULL DEFAULT 0,
+  archived_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_application_questions_project
+  ON project_application_questions(project_id, position) WHERE archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS issue_application_answers (
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  question_id UUID NOT NULL REFERENCES project_application_questions(id) ON DELETE CASCADE,
+  answer TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (project_id, issue_number, user_id, question_id)
+);
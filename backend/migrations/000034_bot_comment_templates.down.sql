@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scheduled_bot_comments;
+DROP TABLE IF EXISTS bot_comment_templates;
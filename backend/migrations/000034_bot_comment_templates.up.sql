@@ -0,0 +1,39 @@
+-- Reusable bot comment templates per project, with optional automatic triggers.
+CREATE TABLE IF NOT EXISTS bot_comment_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  body TEXT NOT NULL,
+  -- When set, the template is scheduled automatically on that event (e.g. 'assigned').
+  trigger_event TEXT CHECK (trigger_event IN ('assigned')),
+  delay_minutes INTEGER NOT NULL DEFAULT 0 CHECK (delay_minutes >= 0),
+  created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_comment_templates_trigger
+  ON bot_comment_templates(project_id, trigger_event) WHERE trigger_event IS NOT NULL;
+
+-- Bot comments waiting to be posted. The body is stored unrendered; variables are resolved at send time.
+CREATE TABLE IF NOT EXISTS scheduled_bot_comments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  template_id UUID REFERENCES bot_comment_templates(id) ON DELETE SET NULL,
+  body TEXT NOT NULL,
+  variables JSONB NOT NULL DEFAULT '{}'::jsonb,
+  send_at TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'cancelled')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  github_comment_id BIGINT,
+  created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_bot_comments_due ON scheduled_bot_comments(send_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_scheduled_bot_comments_issue ON scheduled_bot_comments(project_id, issue_number);
@@ -0,0 +1,4 @@
+ALTER TABLE github_issues
+  DROP COLUMN IF EXISTS deadline_at,
+  DROP COLUMN IF EXISTS points,
+  DROP COLUMN IF EXISTS status_comment_id;
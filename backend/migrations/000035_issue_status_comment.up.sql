@@ -0,0 +1,5 @@
+-- Persistent bot status comment per issue, plus the maintainer-set fields it reports.
+ALTER TABLE github_issues
+  ADD COLUMN IF NOT EXISTS status_comment_id BIGINT,
+  ADD COLUMN IF NOT EXISTS points INTEGER CHECK (points IS NULL OR points >= 0),
+  ADD COLUMN IF NOT EXISTS deadline_at TIMESTAMPTZ;
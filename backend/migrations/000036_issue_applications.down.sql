@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS quick_apply_enabled;
+DROP TABLE IF EXISTS issue_applications;
@@ -0,0 +1,25 @@
+-- Applications recorded in Grainlify. The GitHub comment remains the public record;
+-- this table tracks who applied, how, and where the application stands.
+CREATE TABLE IF NOT EXISTS issue_applications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  -- NULL when the applicant has not signed up to Grainlify (quick apply from GitHub).
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  github_login TEXT NOT NULL,
+  github_comment_id BIGINT,
+  source TEXT NOT NULL DEFAULT 'dashboard' CHECK (source IN ('dashboard', 'quick_apply')),
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'assigned', 'unassigned', 'rejected', 'withdrawn')),
+  message TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_issue_applications_comment
+  ON issue_applications(project_id, github_comment_id) WHERE github_comment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_issue_applications_issue ON issue_applications(project_id, issue_number, status);
+CREATE INDEX IF NOT EXISTS idx_issue_applications_login ON issue_applications(LOWER(github_login));
+CREATE INDEX IF NOT EXISTS idx_issue_applications_user ON issue_applications(user_id) WHERE user_id IS NOT NULL;
+
+-- Opt-in: contributors can apply by commenting "/apply" on the GitHub issue.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS quick_apply_enabled BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,23 @@
+-- Grainlify check runs on pull requests that close tracked issues.
+-- One row per PR; a new head commit gets a new check run (check runs are attached to a SHA).
+CREATE TABLE IF NOT EXISTS pr_check_runs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pr_number INTEGER NOT NULL,
+  head_sha TEXT NOT NULL,
+  author_login TEXT NOT NULL,
+  issue_numbers INTEGER[] NOT NULL DEFAULT '{}',
+  check_run_id BIGINT,
+  conclusion TEXT,
+  -- Set whenever the PR or a linked issue changes; cleared by the publisher.
+  needs_publish BOOLEAN NOT NULL DEFAULT true,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  published_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, pr_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_check_runs_pending ON pr_check_runs(updated_at) WHERE needs_publish;
+CREATE INDEX IF NOT EXISTS idx_pr_check_runs_issues ON pr_check_runs USING GIN (issue_numbers);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS github_pr_review_comments;
+DROP TABLE IF EXISTS github_pr_reviews;
@@ -0,0 +1,36 @@
+-- Pull request reviews and inline review comments, so reviewers can be credited alongside authors.
+CREATE TABLE IF NOT EXISTS github_pr_reviews (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pr_number INTEGER NOT NULL,
+  github_review_id BIGINT NOT NULL,
+  reviewer_login TEXT NOT NULL,
+  reviewer_github_id BIGINT,
+  state TEXT NOT NULL,
+  body TEXT,
+  submitted_at_github TIMESTAMPTZ,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, github_review_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_pr_reviews_pr ON github_pr_reviews(project_id, pr_number);
+CREATE INDEX IF NOT EXISTS idx_github_pr_reviews_reviewer ON github_pr_reviews(LOWER(reviewer_login));
+
+CREATE TABLE IF NOT EXISTS github_pr_review_comments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pr_number INTEGER NOT NULL,
+  github_comment_id BIGINT NOT NULL,
+  github_review_id BIGINT,
+  reviewer_login TEXT NOT NULL,
+  reviewer_github_id BIGINT,
+  body TEXT,
+  path TEXT,
+  created_at_github TIMESTAMPTZ,
+  updated_at_github TIMESTAMPTZ,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, github_comment_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_pr_review_comments_pr ON github_pr_review_comments(project_id, pr_number);
+CREATE INDEX IF NOT EXISTS idx_github_pr_review_comments_reviewer ON github_pr_review_comments(LOWER(reviewer_login));
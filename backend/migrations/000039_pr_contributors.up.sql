@@ -0,0 +1,19 @@
+-- Everyone credited on a merged PR: the author plus commit authors and Co-authored-by trailers.
+-- login is NULL for co-authors we could only identify by email.
+CREATE TABLE IF NOT EXISTS github_pr_contributors (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pr_number INTEGER NOT NULL,
+  identity TEXT NOT NULL,
+  login TEXT,
+  name TEXT,
+  email TEXT,
+  role TEXT NOT NULL CHECK (role IN ('author', 'co_author')),
+  commits INTEGER NOT NULL DEFAULT 0,
+  -- Fraction of the PR's credit (contributors of a PR sum to 1).
+  share NUMERIC(5, 4) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, pr_number, identity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_pr_contributors_login ON github_pr_contributors(LOWER(login)) WHERE login IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_response_metrics;
+ALTER TABLE issue_applications DROP COLUMN IF EXISTS decided_at;
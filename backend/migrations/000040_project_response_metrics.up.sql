@@ -0,0 +1,13 @@
+-- When a maintainer accepted or rejected an application (the basis for decision-time metrics).
+ALTER TABLE issue_applications ADD COLUMN IF NOT EXISTS decided_at TIMESTAMPTZ;
+
+-- Maintainer responsiveness per project, recomputed nightly over a trailing window.
+CREATE TABLE IF NOT EXISTS project_response_metrics (
+  project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+  window_days INTEGER NOT NULL,
+  median_application_decision_seconds BIGINT,
+  application_decisions_count INTEGER NOT NULL DEFAULT 0,
+  median_first_review_seconds BIGINT,
+  reviewed_prs_count INTEGER NOT NULL DEFAULT 0,
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_projects_stale;
+ALTER TABLE projects DROP COLUMN IF EXISTS reactivated_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS stale_at;
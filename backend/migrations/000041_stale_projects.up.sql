@@ -0,0 +1,7 @@
+-- Stale project detection: set by the staleness job when there has been no maintainer activity
+-- (application decisions, merges) for the configured number of weeks; cleared on new activity.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS stale_at TIMESTAMPTZ;
+-- Owner confirmed the project is maintained; counts as activity.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS reactivated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_projects_stale ON projects(stale_at) WHERE stale_at IS NOT NULL;
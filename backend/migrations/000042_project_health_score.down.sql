@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_project_response_metrics_health;
+ALTER TABLE project_response_metrics DROP COLUMN IF EXISTS health_score;
+ALTER TABLE project_response_metrics DROP COLUMN IF EXISTS recent_activity_count;
+ALTER TABLE project_response_metrics DROP COLUMN IF EXISTS closed_unmerged_prs_count;
+ALTER TABLE project_response_metrics DROP COLUMN IF EXISTS merged_prs_count;
+ALTER TABLE project_response_metrics DROP COLUMN IF EXISTS open_unassigned_issues;
@@ -0,0 +1,9 @@
+-- Health score inputs and result, computed alongside the response metrics.
+ALTER TABLE project_response_metrics ADD COLUMN IF NOT EXISTS open_unassigned_issues INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE project_response_metrics ADD COLUMN IF NOT EXISTS merged_prs_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE project_response_metrics ADD COLUMN IF NOT EXISTS closed_unmerged_prs_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE project_response_metrics ADD COLUMN IF NOT EXISTS recent_activity_count INTEGER NOT NULL DEFAULT 0;
+-- 0-100; see responsemetrics.HealthScore.
+ALTER TABLE project_response_metrics ADD COLUMN IF NOT EXISTS health_score INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_project_response_metrics_health ON project_response_metrics(health_score);
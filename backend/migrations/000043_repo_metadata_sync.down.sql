@@ -0,0 +1,9 @@
+DELETE FROM sync_jobs WHERE job_type = 'sync_repo';
+ALTER TABLE sync_jobs DROP CONSTRAINT IF EXISTS sync_jobs_job_type_check;
+ALTER TABLE sync_jobs ADD CONSTRAINT sync_jobs_job_type_check CHECK (job_type IN ('sync_issues', 'sync_prs'));
+
+DROP INDEX IF EXISTS idx_projects_topics;
+ALTER TABLE projects DROP COLUMN IF EXISTS repo_synced_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS license_spdx;
+ALTER TABLE projects DROP COLUMN IF EXISTS topics;
+ALTER TABLE projects DROP COLUMN IF EXISTS languages;
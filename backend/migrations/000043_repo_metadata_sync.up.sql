@@ -0,0 +1,10 @@
+-- Repository metadata refreshed by the 'sync_repo' job.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS languages JSONB NOT NULL DEFAULT '[]'::jsonb; -- [{"name":"Go","percentage":81.2}]
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS topics JSONB NOT NULL DEFAULT '[]'::jsonb;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS license_spdx TEXT;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS repo_synced_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_projects_topics ON projects USING GIN (topics);
+
+ALTER TABLE sync_jobs DROP CONSTRAINT IF EXISTS sync_jobs_job_type_check;
+ALTER TABLE sync_jobs ADD CONSTRAINT sync_jobs_job_type_check CHECK (job_type IN ('sync_issues', 'sync_prs', 'sync_repo'));
@@ -0,0 +1,9 @@
+-- Cached onboarding docs (rendered by GitHub to HTML) so the dashboard doesn't call GitHub from the browser.
+CREATE TABLE IF NOT EXISTS project_docs (
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('readme', 'contributing')),
+  html TEXT NOT NULL,
+  source_path TEXT,
+  fetched_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (project_id, kind)
+);
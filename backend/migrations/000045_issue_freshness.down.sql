@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_github_issues_freshness;
+ALTER TABLE github_issues DROP COLUMN IF EXISTS freshness_checked_at;
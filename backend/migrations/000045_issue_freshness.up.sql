@@ -0,0 +1,6 @@
+-- When an open, unassigned issue was last re-checked against GitHub by the freshness job.
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS freshness_checked_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_github_issues_freshness
+  ON github_issues (freshness_checked_at NULLS FIRST)
+  WHERE state = 'open';
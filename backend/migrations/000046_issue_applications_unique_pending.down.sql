@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_issue_applications_pending_unique;
@@ -0,0 +1,14 @@
+-- At most one pending application per applicant per issue. Duplicates from double-submits are marked
+-- withdrawn so the index can be built, keeping each applicant's most recent application.
+UPDATE issue_applications a SET status = 'withdrawn', updated_at = now()
+WHERE a.status = 'pending'
+  AND EXISTS (
+    SELECT 1 FROM issue_applications b
+    WHERE b.project_id = a.project_id AND b.issue_number = a.issue_number
+      AND LOWER(b.github_login) = LOWER(a.github_login)
+      AND b.status = 'pending'
+      AND (b.created_at, b.id) > (a.created_at, a.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_issue_applications_pending_unique
+  ON issue_applications(project_id, issue_number, LOWER(github_login)) WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS max_concurrent_assignments;
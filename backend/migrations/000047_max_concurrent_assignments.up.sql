@@ -0,0 +1,4 @@
+-- Per-ecosystem cap on how many open issues one contributor can be assigned at once.
+-- NULL falls back to the platform default (MAX_CONCURRENT_ASSIGNMENTS).
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS max_concurrent_assignments INTEGER NULL
+  CHECK (max_concurrent_assignments IS NULL OR max_concurrent_assignments > 0);
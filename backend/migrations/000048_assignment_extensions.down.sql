@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_github_issues_deadline;
+DROP TABLE IF EXISTS assignment_extension_requests;
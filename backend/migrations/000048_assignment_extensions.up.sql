@@ -0,0 +1,24 @@
+-- Contributors assigned to an issue with a deadline can ask the maintainer for more time.
+CREATE TABLE IF NOT EXISTS assignment_extension_requests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  github_login TEXT NOT NULL,
+  current_deadline TIMESTAMPTZ,
+  requested_deadline TIMESTAMPTZ NOT NULL,
+  reason TEXT,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+  decided_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  decided_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One open request per issue at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_assignment_extension_requests_pending
+  ON assignment_extension_requests(project_id, issue_number) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_assignment_extension_requests_issue
+  ON assignment_extension_requests(project_id, issue_number, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_github_issues_deadline
+  ON github_issues(deadline_at) WHERE deadline_at IS NOT NULL AND state = 'open';
@@ -0,0 +1,18 @@
+-- Contributors queueing for an issue that is already assigned. When the assignee is unassigned
+-- (by the maintainer, the overdue job, or on GitHub) the earliest waiting entry is notified.
+CREATE TABLE IF NOT EXISTS issue_waitlist (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  github_login TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'notified', 'promoted', 'left')),
+  notified_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_issue_waitlist_active
+  ON issue_waitlist(project_id, issue_number, user_id) WHERE status IN ('waiting', 'notified');
+CREATE INDEX IF NOT EXISTS idx_issue_waitlist_queue
+  ON issue_waitlist(project_id, issue_number, created_at) WHERE status = 'waiting';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bot_message_overrides;
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS bot_locale;
+ALTER TABLE projects DROP COLUMN IF EXISTS bot_locale;
@@ -0,0 +1,19 @@
+-- Locale for the bot's own comments; a project's choice wins over its ecosystem's, default is English.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS bot_locale TEXT NULL;
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS bot_locale TEXT NULL;
+
+-- Custom wording for individual bot messages, scoped to either a project or an ecosystem.
+CREATE TABLE IF NOT EXISTS bot_message_overrides (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+  ecosystem_id UUID REFERENCES ecosystems(id) ON DELETE CASCADE,
+  key TEXT NOT NULL,
+  body TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK ((project_id IS NULL) <> (ecosystem_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_message_overrides_project
+  ON bot_message_overrides(project_id, key) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_message_overrides_ecosystem
+  ON bot_message_overrides(ecosystem_id, key) WHERE ecosystem_id IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE oauth_states DROP COLUMN IF EXISTS ecosystem_id;
+ALTER TABLE oauth_states DROP COLUMN IF EXISTS github_app_id;
+DROP TABLE IF EXISTS github_app_installations;
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS github_app_id;
@@ -0,0 +1,14 @@
+-- Ecosystems may use their own branded GitHub App (configured via GITHUB_APPS); NULL means the default app.
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS github_app_id TEXT NULL;
+
+-- Which GitHub App each installation belongs to. Installations without a row belong to the default app.
+CREATE TABLE IF NOT EXISTS github_app_installations (
+  installation_id TEXT PRIMARY KEY,
+  app_id TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- An install flow started for an ecosystem remembers the app and ecosystem until the callback.
+ALTER TABLE oauth_states ADD COLUMN IF NOT EXISTS github_app_id TEXT NULL;
+ALTER TABLE oauth_states ADD COLUMN IF NOT EXISTS ecosystem_id UUID NULL REFERENCES ecosystems(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS github_event_archives;
+DROP INDEX IF EXISTS idx_github_events_received;
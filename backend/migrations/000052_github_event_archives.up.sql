@@ -0,0 +1,15 @@
+-- Pruning walks github_events oldest-first.
+CREATE INDEX IF NOT EXISTS idx_github_events_received ON github_events(received_at, delivery_id);
+
+-- One row per archive object written before pruning; the range lets admins find which objects hold a period.
+CREATE TABLE IF NOT EXISTS github_event_archives (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  object_key TEXT NOT NULL UNIQUE,
+  from_received_at TIMESTAMPTZ NOT NULL,
+  to_received_at TIMESTAMPTZ NOT NULL,
+  event_count INTEGER NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_event_archives_range ON github_event_archives(from_received_at, to_received_at);
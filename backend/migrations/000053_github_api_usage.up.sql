@@ -0,0 +1,17 @@
+-- Hourly GitHub API usage per calling identity (App installation, user token, App JWT), written by the
+-- instrumentation layer in every process that calls GitHub.
+CREATE TABLE IF NOT EXISTS github_api_usage (
+  bucket_start TIMESTAMPTZ NOT NULL,
+  identity_kind TEXT NOT NULL,
+  identity_id TEXT NOT NULL,
+  calls INTEGER NOT NULL DEFAULT 0,
+  errors INTEGER NOT NULL DEFAULT 0,
+  rate_limited INTEGER NOT NULL DEFAULT 0,
+  min_remaining INTEGER,
+  rate_limit INTEGER,
+  reset_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bucket_start, identity_kind, identity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_api_usage_identity ON github_api_usage(identity_kind, identity_id, bucket_start DESC);
@@ -0,0 +1,12 @@
+-- GitHub mutations intercepted in dry-run mode (GITHUB_DRY_RUN, or a request sent with ?dry_run=true):
+-- what would have been sent, for review in staging.
+CREATE TABLE IF NOT EXISTS github_dry_run_mutations (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  method TEXT NOT NULL,
+  url TEXT NOT NULL,
+  body JSONB,
+  scope TEXT NOT NULL, -- environment | request
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_dry_run_mutations_created ON github_dry_run_mutations(created_at DESC);
@@ -0,0 +1,23 @@
+DROP POLICY IF EXISTS tenant_isolation ON issue_applications;
+ALTER TABLE issue_applications NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE issue_applications DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON github_pull_requests;
+ALTER TABLE github_pull_requests NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE github_pull_requests DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON github_issues;
+ALTER TABLE github_issues NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE github_issues DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON projects;
+ALTER TABLE projects NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE projects DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON ecosystems;
+ALTER TABLE ecosystems NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE ecosystems DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE oauth_states DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS tenant_id;
+DROP INDEX IF EXISTS idx_ecosystems_tenant_slug;
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE ecosystems ADD CONSTRAINT ecosystems_slug_key UNIQUE (slug);
+DROP FUNCTION IF EXISTS current_tenant_id();
+DROP TABLE IF EXISTS tenants;
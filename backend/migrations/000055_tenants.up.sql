@@ -0,0 +1,83 @@
+-- Tenants: independent grant programs served by one deployment. Each has its own hosts, branding,
+-- GitHub App, ecosystems and projects. Users are shared: one GitHub login works on every tenant.
+CREATE TABLE IF NOT EXISTS tenants (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  slug TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  hosts TEXT[] NOT NULL DEFAULT '{}', -- hostnames (frontend or API) that resolve to this tenant
+  branding JSONB NOT NULL DEFAULT '{}'::jsonb,
+  github_app_id TEXT NULL, -- NULL means the default app (see GITHUB_APPS)
+  frontend_url TEXT NULL, -- where GitHub install redirects land; NULL means FRONTEND_BASE_URL
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenants_default ON tenants(is_default) WHERE is_default;
+
+-- Existing data belongs to the default tenant, which also serves every unknown host.
+INSERT INTO tenants (id, slug, name, is_default)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Grainlify', true)
+ON CONFLICT (id) DO NOTHING;
+
+-- The tenant of the current request, set per connection by the API (db.Connect). NULL when unset or
+-- empty: background jobs and webhook ingestion work across all tenants.
+CREATE OR REPLACE FUNCTION current_tenant_id() RETURNS UUID
+LANGUAGE sql STABLE AS $$
+  SELECT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+$$;
+
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE ecosystems SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE ecosystems ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), '00000000-0000-0000-0000-000000000001'::uuid);
+ALTER TABLE ecosystems ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_ecosystems_tenant ON ecosystems(tenant_id);
+
+-- Ecosystem slugs only need to be unique within a tenant.
+ALTER TABLE ecosystems DROP CONSTRAINT IF EXISTS ecosystems_slug_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ecosystems_tenant_slug ON ecosystems(tenant_id, slug);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+UPDATE projects SET tenant_id = '00000000-0000-0000-0000-000000000001' WHERE tenant_id IS NULL;
+ALTER TABLE projects ALTER COLUMN tenant_id SET DEFAULT COALESCE(current_tenant_id(), '00000000-0000-0000-0000-000000000001'::uuid);
+ALTER TABLE projects ALTER COLUMN tenant_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_tenant ON projects(tenant_id);
+
+-- An install flow remembers the tenant it started on until GitHub redirects back to the API host.
+ALTER TABLE oauth_states ADD COLUMN IF NOT EXISTS tenant_id UUID NULL REFERENCES tenants(id) ON DELETE CASCADE;
+ALTER TABLE oauth_states ALTER COLUMN tenant_id SET DEFAULT current_tenant_id();
+
+-- Isolation is enforced by Postgres, so every existing query is scoped without changes.
+-- FORCE applies the policies to the table owner too; superusers and BYPASSRLS roles still see everything.
+ALTER TABLE ecosystems ENABLE ROW LEVEL SECURITY;
+ALTER TABLE ecosystems FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON ecosystems;
+CREATE POLICY tenant_isolation ON ecosystems
+  USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+  WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id());
+
+ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+ALTER TABLE projects FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON projects;
+CREATE POLICY tenant_isolation ON projects
+  USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+  WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id());
+
+-- Project data follows its project (the subquery is itself filtered by the projects policy).
+ALTER TABLE github_issues ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_issues FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON github_issues;
+CREATE POLICY tenant_isolation ON github_issues
+  USING (current_tenant_id() IS NULL OR EXISTS (SELECT 1 FROM projects p WHERE p.id = project_id));
+
+ALTER TABLE github_pull_requests ENABLE ROW LEVEL SECURITY;
+ALTER TABLE github_pull_requests FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON github_pull_requests;
+CREATE POLICY tenant_isolation ON github_pull_requests
+  USING (current_tenant_id() IS NULL OR EXISTS (SELECT 1 FROM projects p WHERE p.id = project_id));
+
+ALTER TABLE issue_applications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE issue_applications FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON issue_applications;
+CREATE POLICY tenant_isolation ON issue_applications
+  USING (current_tenant_id() IS NULL OR EXISTS (SELECT 1 FROM projects p WHERE p.id = project_id));
@@ -0,0 +1,25 @@
+-- Exports too large to generate inline are queued here and generated in the background.
+-- The file is kept in the row until it expires.
+CREATE TABLE IF NOT EXISTS export_jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  tenant_id UUID NULL DEFAULT current_tenant_id() REFERENCES tenants(id) ON DELETE CASCADE,
+  requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  dataset TEXT NOT NULL CHECK (dataset IN ('applications', 'contributions', 'payouts')),
+  format TEXT NOT NULL CHECK (format IN ('csv', 'json')),
+  project_id UUID NULL REFERENCES projects(id) ON DELETE CASCADE,
+  ecosystem_id UUID NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  range_from TIMESTAMPTZ NOT NULL,
+  range_to TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'ready', 'failed')),
+  row_count INT NULL,
+  content BYTEA NULL,
+  error TEXT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  started_at TIMESTAMPTZ NULL,
+  finished_at TIMESTAMPTZ NULL,
+  expires_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_queue ON export_jobs(created_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_export_jobs_requester ON export_jobs(requested_by, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs(expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ecosystem_digests;
+DROP TABLE IF EXISTS ecosystem_report_subscribers;
@@ -0,0 +1,24 @@
+-- Who receives an ecosystem's weekly report: an admin, in-app and (with an address) by email.
+CREATE TABLE IF NOT EXISTS ecosystem_report_subscribers (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+  email TEXT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (ecosystem_id, user_id)
+);
+
+-- One row per ecosystem and week, claimed before sending so a report goes out once.
+-- skipped marks weeks with nothing to report (no email is sent).
+CREATE TABLE IF NOT EXISTS ecosystem_digests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  period_start TIMESTAMPTZ NOT NULL,
+  period_end TIMESTAMPTZ NOT NULL,
+  content JSONB NOT NULL,
+  skipped BOOLEAN NOT NULL DEFAULT false,
+  emails_sent INTEGER NOT NULL DEFAULT 0,
+  sent_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (ecosystem_id, period_start)
+);
@@ -0,0 +1 @@
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS program_id;
@@ -0,0 +1,3 @@
+-- The program escrow (onchain_events.program_id) that pays an ecosystem's rewards. Public stats use it
+-- to report rewards paid per ecosystem; NULL means payouts aren't attributed to the ecosystem.
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS program_id TEXT NULL;
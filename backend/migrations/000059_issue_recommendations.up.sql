@@ -0,0 +1,13 @@
+-- Per-contributor issue recommendations, recomputed periodically by the recommend job. reasons is a
+-- JSON array of {kind, detail, text} explaining the score.
+CREATE TABLE IF NOT EXISTS issue_recommendations (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  issue_id UUID NOT NULL REFERENCES github_issues(id) ON DELETE CASCADE,
+  score DOUBLE PRECISION NOT NULL,
+  reasons JSONB NOT NULL DEFAULT '[]',
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, issue_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_recommendations_user_score ON issue_recommendations(user_id, score DESC);
+CREATE INDEX IF NOT EXISTS idx_issue_recommendations_computed_at ON issue_recommendations(computed_at);
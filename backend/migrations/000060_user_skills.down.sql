@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_skills;
+ALTER TABLE users DROP COLUMN IF EXISTS skills;
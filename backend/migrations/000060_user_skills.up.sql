@@ -0,0 +1,4 @@
+-- Skills contributors declare on their profile: ids from the controlled vocabulary in package skills.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS skills TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_users_skills ON users USING GIN(skills);
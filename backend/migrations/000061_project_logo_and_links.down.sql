@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS links;
+ALTER TABLE projects DROP COLUMN IF EXISTS logo_url;
@@ -0,0 +1,4 @@
+-- Maintainer-editable project metadata beyond what GitHub provides: a logo and contact links
+-- ({"website": "...", "discord": "...", ...}; keys are limited to the set the metadata endpoint accepts).
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS logo_url TEXT;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '{}'::jsonb;
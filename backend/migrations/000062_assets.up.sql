@@ -0,0 +1,14 @@
+-- Uploaded images (see package assets). The object lives in asset storage under key; this table records
+-- who uploaded what, for auditing and clean-up.
+CREATE TABLE IF NOT EXISTS assets (
+  key TEXT PRIMARY KEY,
+  kind TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size_bytes INTEGER NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assets_kind_created ON assets(kind, created_at DESC);
@@ -0,0 +1,21 @@
+-- Versioned ecosystem page content (see package ecocontent). Each ecosystem has at most one draft and one
+-- published version; earlier published versions are archived and can be rolled back to.
+CREATE TABLE IF NOT EXISTS ecosystem_content_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('draft', 'published', 'archived')),
+  blocks JSONB NOT NULL DEFAULT '[]'::jsonb,
+  note TEXT,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  published_at TIMESTAMPTZ,
+  UNIQUE (ecosystem_id, version)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ecosystem_content_one_draft
+  ON ecosystem_content_versions(ecosystem_id) WHERE status = 'draft';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ecosystem_content_one_published
+  ON ecosystem_content_versions(ecosystem_id) WHERE status = 'published';
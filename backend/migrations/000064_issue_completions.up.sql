@@ -0,0 +1,20 @@
+-- Assigned issues closed by a merged pull request, rebuilt periodically by the completions job.
+-- duration_seconds runs from the Grainlify assignment to the merge; complexity is one of the estimate
+-- package's levels.
+CREATE TABLE IF NOT EXISTS issue_completions (
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  assignee_login TEXT NOT NULL,
+  pr_number INTEGER NOT NULL,
+  assigned_at TIMESTAMPTZ NOT NULL,
+  merged_at TIMESTAMPTZ NOT NULL,
+  duration_seconds BIGINT NOT NULL CHECK (duration_seconds >= 0),
+  points INTEGER,
+  labels TEXT[] NOT NULL DEFAULT '{}',
+  complexity TEXT NOT NULL,
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (project_id, issue_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_completions_merged ON issue_completions(merged_at);
+CREATE INDEX IF NOT EXISTS idx_issue_completions_labels ON issue_completions USING GIN (labels);
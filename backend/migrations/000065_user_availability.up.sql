@@ -0,0 +1,3 @@
+-- Contributor availability (see package availability): {timezone, weekly, unavailable}. NULL means never
+-- set, which is treated as weekdays in UTC.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS availability JSONB;
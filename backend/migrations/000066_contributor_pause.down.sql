@@ -0,0 +1,6 @@
+ALTER TABLE issue_applications DROP COLUMN IF EXISTS held_at;
+DROP INDEX IF EXISTS idx_users_paused_until;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS pause_reason,
+  DROP COLUMN IF EXISTS paused_until,
+  DROP COLUMN IF EXISTS paused_at;
@@ -0,0 +1,10 @@
+-- Vacation mode: while paused_until is in the future the contributor's pending applications are held
+-- (held_at set) and scheduled bot comments addressed to them wait until they are back.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS paused_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS paused_until TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS pause_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_paused_until ON users(paused_until) WHERE paused_until IS NOT NULL;
+
+ALTER TABLE issue_applications ADD COLUMN IF NOT EXISTS held_at TIMESTAMPTZ;
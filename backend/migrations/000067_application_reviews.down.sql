@@ -0,0 +1,3 @@
+ALTER TABLE issue_applications
+  DROP COLUMN IF EXISTS reviewed_by_user_id,
+  DROP COLUMN IF EXISTS reviewed_at;
@@ -0,0 +1,4 @@
+-- Maintainers can mark applications as reviewed (individually or in bulk) to keep track of triage.
+ALTER TABLE issue_applications
+  ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS reviewed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+ALTER TABLE github_issues DROP COLUMN IF EXISTS state_reason;
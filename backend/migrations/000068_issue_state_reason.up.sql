@@ -0,0 +1,2 @@
+-- Why an issue was closed (completed / not_planned) or that it was reopened, as reported by GitHub.
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS state_reason TEXT;
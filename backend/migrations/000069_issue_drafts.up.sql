@@ -0,0 +1,18 @@
+-- Issues drafted in Grainlify by maintainers and published to GitHub through the App.
+CREATE TABLE IF NOT EXISTS issue_drafts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  title TEXT NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  labels TEXT[] NOT NULL DEFAULT '{}',
+  points INT,
+  -- Set once published.
+  github_issue_id BIGINT,
+  github_issue_number INT,
+  published_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_drafts_project ON issue_drafts(project_id, created_at DESC);
@@ -0,0 +1,16 @@
+-- Ecosystem issue templates. A row with the key of a built-in template (see internal/issuetemplates)
+-- replaces it within the ecosystem; other keys add templates.
+CREATE TABLE IF NOT EXISTS issue_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  key TEXT NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  title TEXT NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  labels TEXT[] NOT NULL DEFAULT '{}',
+  points INT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (ecosystem_id, key)
+);
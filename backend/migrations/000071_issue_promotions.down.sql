@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS issue_promotions;
+ALTER TABLE github_issues
+  DROP COLUMN IF EXISTS promoted_by_user_id,
+  DROP COLUMN IF EXISTS promoted_at;
+DROP TABLE IF EXISTS promotion_channels;
@@ -0,0 +1,37 @@
+-- Promotion channels: Discord/Slack incoming webhooks (or any JSON webhook, e.g. a relay that posts to
+-- Twitter) that an ecosystem's promoted issues are cross-posted to. URLs embed credentials, so they are
+-- stored encrypted with TOKEN_ENC_KEY_B64.
+CREATE TABLE IF NOT EXISTS promotion_channels (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('discord', 'slack', 'webhook')),
+  name TEXT NOT NULL,
+  url_enc BYTEA NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_channels_ecosystem ON promotion_channels(ecosystem_id);
+
+ALTER TABLE github_issues
+  ADD COLUMN IF NOT EXISTS promoted_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS promoted_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- One row per channel and issue: promoting again never posts twice.
+CREATE TABLE IF NOT EXISTS issue_promotions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  channel_id UUID NOT NULL REFERENCES promotion_channels(id) ON DELETE CASCADE,
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INT NOT NULL,
+  scheduled_for TIMESTAMPTZ NOT NULL DEFAULT now(),
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed', 'cancelled')),
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (channel_id, project_id, issue_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_promotions_due ON issue_promotions(scheduled_for) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_issue_promotions_issue ON issue_promotions(project_id, issue_number);
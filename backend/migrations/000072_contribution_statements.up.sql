@@ -0,0 +1,13 @@
+-- Signed contribution statements (internal/statements). payload is the exact JSON that was signed, kept
+-- as text so it verifies byte for byte; revoking keeps the row so the verification page can say so.
+CREATE TABLE IF NOT EXISTS contribution_statements (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  github_login TEXT NOT NULL,
+  payload TEXT NOT NULL,
+  signature TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_contribution_statements_user ON contribution_statements(user_id, created_at DESC);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS magic_links;
+DROP TABLE IF EXISTS user_identities;
+
+DELETE FROM oauth_states WHERE kind = 'google_login';
+ALTER TABLE oauth_states
+  DROP CONSTRAINT IF EXISTS oauth_states_kind_check;
+ALTER TABLE oauth_states
+  ADD CONSTRAINT oauth_states_kind_check CHECK (kind IN ('github_link', 'github_login', 'github_app_install'));
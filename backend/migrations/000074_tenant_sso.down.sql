@@ -0,0 +1,16 @@
+DELETE FROM user_identities WHERE provider = 'oidc';
+ALTER TABLE user_identities
+  DROP CONSTRAINT IF EXISTS user_identities_provider_check;
+ALTER TABLE user_identities
+  ADD CONSTRAINT user_identities_provider_check CHECK (provider IN ('google', 'email'));
+
+DELETE FROM oauth_states WHERE kind = 'sso_login';
+ALTER TABLE oauth_states
+  DROP CONSTRAINT IF EXISTS oauth_states_kind_check;
+ALTER TABLE oauth_states
+  ADD CONSTRAINT oauth_states_kind_check CHECK (kind IN ('github_link', 'github_login', 'github_app_install', 'google_login'));
+ALTER TABLE oauth_states
+  DROP COLUMN IF EXISTS code_verifier,
+  DROP COLUMN IF EXISTS nonce;
+
+DROP TABLE IF EXISTS tenant_sso;
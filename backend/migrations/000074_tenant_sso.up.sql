@@ -0,0 +1,29 @@
+-- OpenID Connect single sign-on per tenant. When require_for_admins is set, admin endpoints on the
+-- tenant's hosts only accept sessions that signed in through this IdP.
+CREATE TABLE IF NOT EXISTS tenant_sso (
+  tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+  issuer TEXT NOT NULL,
+  client_id TEXT NOT NULL,
+  client_secret_enc BYTEA NOT NULL,
+  allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  require_for_admins BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The SSO flow keeps its nonce and PKCE verifier with the state.
+ALTER TABLE oauth_states
+  ADD COLUMN IF NOT EXISTS nonce TEXT,
+  ADD COLUMN IF NOT EXISTS code_verifier TEXT;
+
+ALTER TABLE oauth_states
+  DROP CONSTRAINT IF EXISTS oauth_states_kind_check;
+ALTER TABLE oauth_states
+  ADD CONSTRAINT oauth_states_kind_check CHECK (kind IN ('github_link', 'github_login', 'github_app_install', 'google_login', 'sso_login'));
+
+-- SSO identities use the subject "<issuer>|<sub>".
+ALTER TABLE user_identities
+  DROP CONSTRAINT IF EXISTS user_identities_provider_check;
+ALTER TABLE user_identities
+  ADD CONSTRAINT user_identities_provider_check CHECK (provider IN ('google', 'email', 'oidc'));
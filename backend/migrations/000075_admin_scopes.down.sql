@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_admin_scopes;
+ALTER TABLE users DROP COLUMN IF EXISTS admin_scopes;
@@ -0,0 +1,6 @@
+-- Scoped admin capabilities for operational staff. Users with role 'admin' hold every scope; anyone else
+-- may reach the admin endpoints their scopes cover.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS admin_scopes TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_users_admin_scopes ON users(id) WHERE cardinality(admin_scopes) > 0;
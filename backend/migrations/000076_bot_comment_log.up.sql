@@ -0,0 +1,12 @@
+-- Bot comments posted (or folded into the status comment) per issue, for the per-issue rate limit.
+CREATE TABLE IF NOT EXISTS bot_comment_log (
+  id BIGSERIAL PRIMARY KEY,
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INT NOT NULL,
+  github_comment_id BIGINT NULL,
+  body TEXT NOT NULL,
+  coalesced BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_comment_log_issue ON bot_comment_log(project_id, issue_number, created_at DESC);
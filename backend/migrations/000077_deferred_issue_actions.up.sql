@@ -0,0 +1,19 @@
+-- Assign/reject requests held for an undo window before anything happens on GitHub.
+CREATE TABLE IF NOT EXISTS deferred_issue_actions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INT NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('assign', 'reject')),
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  requested_by_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  requested_by_role TEXT NOT NULL,
+  execute_at TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'failed', 'cancelled')),
+  result_status INT NULL,
+  result JSONB NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deferred_issue_actions_due ON deferred_issue_actions(execute_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_deferred_issue_actions_issue ON deferred_issue_actions(project_id, issue_number, created_at DESC);
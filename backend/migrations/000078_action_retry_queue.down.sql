@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_deferred_issue_actions_user;
+
+DELETE FROM deferred_issue_actions WHERE action = 'comment';
+ALTER TABLE deferred_issue_actions
+  DROP CONSTRAINT IF EXISTS deferred_issue_actions_action_check;
+ALTER TABLE deferred_issue_actions
+  ADD CONSTRAINT deferred_issue_actions_action_check CHECK (action IN ('assign', 'reject'));
+
+ALTER TABLE deferred_issue_actions
+  DROP CONSTRAINT IF EXISTS deferred_issue_actions_reason_check;
+ALTER TABLE deferred_issue_actions
+  DROP COLUMN IF EXISTS last_error,
+  DROP COLUMN IF EXISTS attempts,
+  DROP COLUMN IF EXISTS reason;
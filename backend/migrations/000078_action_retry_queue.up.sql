@@ -0,0 +1,17 @@
+-- Maintainer actions that failed on GitHub's side are kept and retried instead of being lost.
+ALTER TABLE deferred_issue_actions
+  ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT 'undo',
+  ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS last_error TEXT NULL;
+
+ALTER TABLE deferred_issue_actions
+  DROP CONSTRAINT IF EXISTS deferred_issue_actions_reason_check;
+ALTER TABLE deferred_issue_actions
+  ADD CONSTRAINT deferred_issue_actions_reason_check CHECK (reason IN ('undo', 'retry'));
+
+ALTER TABLE deferred_issue_actions
+  DROP CONSTRAINT IF EXISTS deferred_issue_actions_action_check;
+ALTER TABLE deferred_issue_actions
+  ADD CONSTRAINT deferred_issue_actions_action_check CHECK (action IN ('assign', 'reject', 'comment'));
+
+CREATE INDEX IF NOT EXISTS idx_deferred_issue_actions_user ON deferred_issue_actions(requested_by_user_id, created_at DESC);
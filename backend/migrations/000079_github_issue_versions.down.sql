@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS github_issues_row_version ON github_issues;
+DROP FUNCTION IF EXISTS bump_row_version();
+ALTER TABLE github_issues DROP COLUMN IF EXISTS row_version;
@@ -0,0 +1,16 @@
+-- Row version on github_issues, bumped by every update, so writers that read the row before calling GitHub
+-- can tell whether a webhook or sync changed it in the meantime (compare-and-set).
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS row_version BIGINT NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION bump_row_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.row_version := OLD.row_version + 1;
+  RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS github_issues_row_version ON github_issues;
+CREATE TRIGGER github_issues_row_version
+  BEFORE UPDATE ON github_issues
+  FOR EACH ROW EXECUTE FUNCTION bump_row_version();
@@ -0,0 +1,5 @@
+-- Where dashboard applications are visible: 'public' posts them as GitHub comments (message included),
+-- 'private' keeps them in Grainlify only, 'both' posts a comment announcing the application while the
+-- message stays in Grainlify.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS application_visibility TEXT NOT NULL DEFAULT 'public'
+  CHECK (application_visibility IN ('public', 'private', 'both'));
@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_anonymous_applications_private;
+ALTER TABLE projects DROP COLUMN IF EXISTS anonymous_applications;
@@ -0,0 +1,6 @@
+-- Anonymous applications: maintainers see an application's message and the applicant's stats, but not who
+-- applied, until they assign it. Only for private applications, which never show the applicant on GitHub.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS anonymous_applications BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_anonymous_applications_private;
+ALTER TABLE projects ADD CONSTRAINT projects_anonymous_applications_private
+  CHECK (NOT anonymous_applications OR application_visibility = 'private');
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS application_stage_events;
+ALTER TABLE issue_applications
+  DROP COLUMN IF EXISTS stage_changed_at,
+  DROP COLUMN IF EXISTS stage_due_at,
+  DROP COLUMN IF EXISTS stage_details,
+  DROP COLUMN IF EXISTS stage;
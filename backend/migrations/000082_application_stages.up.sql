@@ -0,0 +1,23 @@
+-- Optional stage between applying and being assigned: the maintainer asks for a small trial task or a call
+-- before deciding. The application stays pending throughout; stage tracks where it is.
+ALTER TABLE issue_applications
+  ADD COLUMN IF NOT EXISTS stage TEXT NOT NULL DEFAULT 'applied'
+    CHECK (stage IN ('applied', 'trial_requested', 'trial_submitted', 'interview_requested', 'interview_done')),
+  ADD COLUMN IF NOT EXISTS stage_details TEXT,
+  ADD COLUMN IF NOT EXISTS stage_due_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS stage_changed_at TIMESTAMPTZ;
+
+-- Every stage transition, for both parties to look back on.
+CREATE TABLE IF NOT EXISTS application_stage_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  application_id UUID NOT NULL REFERENCES issue_applications(id) ON DELETE CASCADE,
+  from_stage TEXT NOT NULL,
+  to_stage TEXT NOT NULL,
+  details TEXT,
+  due_at TIMESTAMPTZ,
+  actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_stage_events_application
+  ON application_stage_events(application_id, created_at);
@@ -0,0 +1,5 @@
+-- Private calendar feed (GET /me/deadlines.ics?token=...) for calendar apps, which can't send a session
+-- token. Only the token's hash is stored; rotating it replaces the hash and breaks old subscriptions.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_token_hash TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_calendar_token_hash
+  ON users(calendar_token_hash) WHERE calendar_token_hash IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reminders_sent;
+ALTER TABLE users DROP COLUMN IF EXISTS reminder_preferences;
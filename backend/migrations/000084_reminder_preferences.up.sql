@@ -0,0 +1,15 @@
+-- Reminder preferences (see package reminders): {timezone, quiet_hours, deadline, pending_review}. NULL
+-- means never set, and the defaults apply.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reminder_preferences JSONB;
+
+-- One row per reminder sent, so each goes out once. ref identifies what it was about (e.g. an issue and
+-- its deadline, so an extended deadline gets a new reminder).
+CREATE TABLE IF NOT EXISTS reminders_sent (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  ref TEXT NOT NULL,
+  sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, kind, ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_sent_at ON reminders_sent(sent_at);
@@ -0,0 +1,6 @@
+DELETE FROM sync_jobs WHERE job_type = 'sync_discussions';
+ALTER TABLE sync_jobs DROP CONSTRAINT IF EXISTS sync_jobs_job_type_check;
+ALTER TABLE sync_jobs ADD CONSTRAINT sync_jobs_job_type_check CHECK (job_type IN ('sync_issues', 'sync_prs', 'sync_repo'));
+
+DROP TABLE IF EXISTS github_discussions;
+ALTER TABLE projects DROP COLUMN IF EXISTS track_discussions;
@@ -0,0 +1,27 @@
+-- GitHub Discussions, synced for projects that opt in (community Q&A) so answered questions can be credited.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS track_discussions BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS github_discussions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  node_id TEXT NOT NULL,
+  number INT NOT NULL,
+  title TEXT NOT NULL,
+  url TEXT NOT NULL,
+  author_login TEXT,
+  category TEXT NOT NULL,
+  answerable BOOLEAN NOT NULL DEFAULT false,
+  closed BOOLEAN NOT NULL DEFAULT false,
+  answer_author_login TEXT,
+  answer_url TEXT,
+  answered_at TIMESTAMPTZ,
+  created_at_github TIMESTAMPTZ,
+  updated_at_github TIMESTAMPTZ,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_discussions_answerer ON github_discussions (LOWER(answer_author_login)) WHERE answer_author_login IS NOT NULL;
+
+ALTER TABLE sync_jobs DROP CONSTRAINT IF EXISTS sync_jobs_job_type_check;
+ALTER TABLE sync_jobs ADD CONSTRAINT sync_jobs_job_type_check CHECK (job_type IN ('sync_issues', 'sync_prs', 'sync_repo', 'sync_discussions'));
@@ -0,0 +1,4 @@
+DELETE FROM github_issues WHERE task_type <> 'code';
+DROP INDEX IF EXISTS idx_github_issues_native_tasks;
+ALTER TABLE github_issues DROP COLUMN IF EXISTS created_by_user_id;
+ALTER TABLE github_issues DROP COLUMN IF EXISTS task_type;
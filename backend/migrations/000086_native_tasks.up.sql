@@ -0,0 +1,8 @@
+-- Native tasks: documentation, translation and design work created in Grainlify without a GitHub issue.
+-- They live in github_issues so applications, assignments and points treat them like issues; their
+-- numbers start at 1000000000 and their github_issue_id is the negated number.
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS task_type TEXT NOT NULL DEFAULT 'code'
+  CHECK (task_type IN ('code', 'docs', 'translation', 'design', 'other'));
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_github_issues_native_tasks ON github_issues (project_id, task_type) WHERE task_type <> 'code';
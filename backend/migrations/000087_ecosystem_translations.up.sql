@@ -0,0 +1,13 @@
+-- Per-locale variants of an ecosystem's public text. Fields left NULL fall back to the ecosystem's own
+-- (default language) content.
+CREATE TABLE IF NOT EXISTS ecosystem_translations (
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  locale TEXT NOT NULL,
+  description TEXT,
+  about TEXT,
+  key_areas JSONB,
+  updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (ecosystem_id, locale)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS reward_currency;
+ALTER TABLE onchain_events DROP COLUMN IF EXISTS currency_code;
+DROP TABLE IF EXISTS currency_rates;
+DROP TABLE IF EXISTS currencies;
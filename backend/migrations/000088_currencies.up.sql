@@ -0,0 +1,42 @@
+-- Currencies rewards and payouts are denominated in: fiat currencies, and tokens with the chain and
+-- contract they live on. Amounts are stored in base units (cents, stroops), so decimals converts them.
+CREATE TABLE IF NOT EXISTS currencies (
+  code TEXT PRIMARY KEY CHECK (code ~ '^[A-Z0-9]{2,12}$'),
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('fiat', 'token')),
+  decimals INT NOT NULL CHECK (decimals BETWEEN 0 AND 38),
+  chain TEXT,
+  contract_id TEXT,
+  icon_url TEXT,
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (kind = 'token' OR (chain IS NULL AND contract_id IS NULL)),
+  CHECK (kind = 'fiat' OR chain IS NOT NULL)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_currencies_contract ON currencies (chain, contract_id) WHERE contract_id IS NOT NULL;
+
+INSERT INTO currencies (code, name, kind, decimals, chain) VALUES
+  ('USD', 'US Dollar', 'fiat', 2, NULL),
+  ('XLM', 'Stellar Lumens', 'token', 7, 'stellar'),
+  ('USDC', 'USD Coin', 'token', 7, 'stellar')
+ON CONFLICT (code) DO NOTHING;
+
+-- Exchange-rate snapshots: one unit of currency_code is worth rate units of quote_code at captured_at.
+-- Reports convert each payout with the snapshot in effect when it was paid.
+CREATE TABLE IF NOT EXISTS currency_rates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE ON DELETE CASCADE,
+  quote_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE ON DELETE CASCADE,
+  rate NUMERIC(38,18) NOT NULL CHECK (rate > 0),
+  source TEXT,
+  captured_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  CHECK (currency_code <> quote_code)
+);
+CREATE INDEX IF NOT EXISTS idx_currency_rates_pair ON currency_rates (currency_code, quote_code, captured_at DESC);
+
+-- The currency of a payout. NULL on an event means the ecosystem's reward currency, then the currency of
+-- the configured token contract.
+ALTER TABLE onchain_events ADD COLUMN IF NOT EXISTS currency_code TEXT REFERENCES currencies(code) ON UPDATE CASCADE;
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS reward_currency TEXT REFERENCES currencies(code) ON UPDATE CASCADE;
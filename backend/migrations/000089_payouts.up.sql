@@ -0,0 +1,29 @@
+-- Payouts owed to contributors. Admins create and approve them; approved payouts are executed by the
+-- configured payout executor (see package payouts), which records the transaction hash.
+CREATE TABLE IF NOT EXISTS payouts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  recipient_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  recipient_address TEXT,
+  project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+  issue_number INT,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  amount NUMERIC(78,0) NOT NULL CHECK (amount > 0), -- base units
+  note TEXT,
+  status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'approved', 'executing', 'paid', 'failed', 'cancelled')),
+  executor TEXT,
+  external_id TEXT,
+  tx_hash TEXT,
+  error TEXT,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  approved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  approved_at TIMESTAMPTZ,
+  cancelled_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  executed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_payouts_recipient ON payouts (recipient_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payouts_due ON payouts (status, next_attempt_at) WHERE status IN ('approved', 'executing');
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reward_ledger;
+DROP TABLE IF EXISTS reward_locks;
+DROP TABLE IF EXISTS ecosystem_budgets;
+ALTER TABLE github_issues DROP COLUMN IF EXISTS reward_currency;
+ALTER TABLE github_issues DROP COLUMN IF EXISTS reward_amount;
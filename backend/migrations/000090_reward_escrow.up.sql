@@ -0,0 +1,45 @@
+-- Issue rewards, escrowed against ecosystem budgets: assigning a rewarded issue locks the reward in the
+-- ecosystem's budget, completing it releases the reward to the assignee (as a pending payout) and
+-- unassigning returns it. Every movement is recorded in reward_ledger.
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS reward_amount NUMERIC(78,0) CHECK (reward_amount > 0); -- base units
+ALTER TABLE github_issues ADD COLUMN IF NOT EXISTS reward_currency TEXT REFERENCES currencies(code) ON UPDATE CASCADE;
+
+CREATE TABLE IF NOT EXISTS ecosystem_budgets (
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  available NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (available >= 0),
+  locked NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (locked >= 0),
+  released NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (released >= 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (ecosystem_id, currency_code)
+);
+
+CREATE TABLE IF NOT EXISTS reward_locks (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INT NOT NULL,
+  assignee_login TEXT NOT NULL,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  amount NUMERIC(78,0) NOT NULL CHECK (amount > 0),
+  status TEXT NOT NULL DEFAULT 'locked' CHECK (status IN ('locked', 'released', 'returned')),
+  payout_id UUID REFERENCES payouts(id) ON DELETE SET NULL,
+  locked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  settled_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reward_locks_active ON reward_locks (project_id, issue_number) WHERE status = 'locked';
+
+CREATE TABLE IF NOT EXISTS reward_ledger (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('deposit', 'withdraw', 'lock', 'release', 'return')),
+  amount NUMERIC(78,0) NOT NULL CHECK (amount > 0),
+  lock_id UUID REFERENCES reward_locks(id) ON DELETE SET NULL,
+  project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+  issue_number INT,
+  note TEXT,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_reward_ledger_ecosystem ON reward_ledger (ecosystem_id, created_at DESC);
@@ -0,0 +1,32 @@
+-- The 000090 budget tables come back empty; balances lived in the ledger.
+CREATE TABLE IF NOT EXISTS ecosystem_budgets (
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  available NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (available >= 0),
+  locked NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (locked >= 0),
+  released NUMERIC(78,0) NOT NULL DEFAULT 0 CHECK (released >= 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (ecosystem_id, currency_code)
+);
+
+CREATE TABLE IF NOT EXISTS reward_ledger (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  ecosystem_id UUID NOT NULL REFERENCES ecosystems(id) ON DELETE CASCADE,
+  currency_code TEXT NOT NULL REFERENCES currencies(code) ON UPDATE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('deposit', 'withdraw', 'lock', 'release', 'return')),
+  amount NUMERIC(78,0) NOT NULL CHECK (amount > 0),
+  lock_id UUID REFERENCES reward_locks(id) ON DELETE SET NULL,
+  project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+  issue_number INT,
+  note TEXT,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_reward_ledger_ecosystem ON reward_ledger (ecosystem_id, created_at DESC);
+
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS ledger_accounts;
+DROP FUNCTION IF EXISTS ledger_append_only();
+DROP FUNCTION IF EXISTS ledger_check_balanced();
+DROP FUNCTION IF EXISTS ledger_apply_entry();
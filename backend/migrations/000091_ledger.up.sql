@@ -0,0 +1,122 @@
+-- Double-entry ledger for contributor points and reward money. Every movement is a transaction whose
+-- entries sum to zero per asset; entries are append-only and each account's balance is kept in step with
+-- them by trigger. It replaces ecosystem_budgets/reward_ledger (their history is replayed below) and the
+-- points tallied from issue_completions (the completions job posts those).
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  kind TEXT NOT NULL CHECK (kind IN ('external', 'ecosystem_budget', 'ecosystem_escrow', 'contributor')),
+  owner TEXT NOT NULL DEFAULT '', -- ecosystem id, lowercased GitHub login, or '' for external
+  asset TEXT NOT NULL,            -- currency code, or POINTS
+  balance NUMERIC(78,0) NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (kind, owner, asset),
+  -- Only the outside world can go negative: it is where deposits come from and payouts go.
+  CONSTRAINT ledger_accounts_funded CHECK (kind = 'external' OR balance >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  kind TEXT NOT NULL CHECK (kind IN ('deposit', 'withdraw', 'lock', 'release', 'return', 'payout', 'points_award')),
+  idempotency_key TEXT UNIQUE,
+  ecosystem_id UUID,
+  project_id UUID,
+  issue_number INT,
+  memo TEXT,
+  created_by UUID,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_ecosystem ON ledger_transactions (ecosystem_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_issue ON ledger_transactions (project_id, issue_number);
+
+-- amount is the change to the account's balance: positive credits it, negative debits it.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id BIGSERIAL PRIMARY KEY,
+  transaction_id UUID NOT NULL REFERENCES ledger_transactions(id),
+  account_id UUID NOT NULL REFERENCES ledger_accounts(id),
+  amount NUMERIC(78,0) NOT NULL CHECK (amount <> 0)
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries (account_id, id DESC);
+
+CREATE OR REPLACE FUNCTION ledger_apply_entry() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  UPDATE ledger_accounts SET balance = balance + NEW.amount, updated_at = now() WHERE id = NEW.account_id;
+  RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS ledger_entries_apply ON ledger_entries;
+CREATE TRIGGER ledger_entries_apply
+  AFTER INSERT ON ledger_entries
+  FOR EACH ROW EXECUTE FUNCTION ledger_apply_entry();
+
+-- Checked at commit, once all of a transaction's entries are in.
+CREATE OR REPLACE FUNCTION ledger_check_balanced() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM ledger_entries e JOIN ledger_accounts a ON a.id = e.account_id
+    WHERE e.transaction_id = NEW.transaction_id
+    GROUP BY a.asset HAVING SUM(e.amount) <> 0
+  ) THEN
+    RAISE EXCEPTION 'ledger transaction % does not balance', NEW.transaction_id;
+  END IF;
+  RETURN NULL;
+END
+$$;
+
+DROP TRIGGER IF EXISTS ledger_entries_balanced ON ledger_entries;
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+  AFTER INSERT ON ledger_entries
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+CREATE OR REPLACE FUNCTION ledger_append_only() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END
+$$;
+
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON ledger_entries;
+CREATE TRIGGER ledger_entries_append_only
+  BEFORE UPDATE OR DELETE ON ledger_entries
+  FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+DROP TRIGGER IF EXISTS ledger_transactions_append_only ON ledger_transactions;
+CREATE TRIGGER ledger_transactions_append_only
+  BEFORE UPDATE OR DELETE ON ledger_transactions
+  FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+-- Replay the reward ledger, oldest first, as two-entry transactions.
+CREATE TEMP TABLE ledger_replay AS
+SELECT r.id, r.created_at, r.kind, r.amount, r.currency_code AS asset, r.ecosystem_id, r.project_id, r.issue_number,
+       r.note, r.created_by,
+       CASE r.kind WHEN 'deposit' THEN 'external' WHEN 'withdraw' THEN 'ecosystem_budget' WHEN 'lock' THEN 'ecosystem_budget'
+                   ELSE 'ecosystem_escrow' END AS from_kind,
+       CASE r.kind WHEN 'deposit' THEN 'ecosystem_budget' WHEN 'withdraw' THEN 'external' WHEN 'lock' THEN 'ecosystem_escrow'
+                   WHEN 'release' THEN 'contributor' ELSE 'ecosystem_budget' END AS to_kind,
+       LOWER(COALESCE(l.assignee_login, '')) AS assignee
+FROM reward_ledger r
+LEFT JOIN reward_locks l ON l.id = r.lock_id;
+
+INSERT INTO ledger_accounts (kind, owner, asset)
+SELECT DISTINCT k, CASE k WHEN 'external' THEN '' WHEN 'contributor' THEN assignee ELSE ecosystem_id::text END, asset
+FROM ledger_replay, LATERAL (VALUES (from_kind), (to_kind)) v(k)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ledger_transactions (id, kind, ecosystem_id, project_id, issue_number, memo, created_by, created_at)
+SELECT id, kind, ecosystem_id, project_id, issue_number, note, created_by, created_at FROM ledger_replay;
+
+INSERT INTO ledger_entries (transaction_id, account_id, amount)
+SELECT r.id, a.id, CASE WHEN v.side = 0 THEN -r.amount ELSE r.amount END
+FROM ledger_replay r
+CROSS JOIN LATERAL (VALUES (0, r.from_kind), (1, r.to_kind)) v(side, k)
+JOIN ledger_accounts a ON a.kind = v.k AND a.asset = r.asset
+ AND a.owner = CASE v.k WHEN 'external' THEN '' WHEN 'contributor' THEN r.assignee ELSE r.ecosystem_id::text END
+ORDER BY r.created_at, r.id, v.side DESC;
+
+DROP TABLE ledger_replay;
+DROP TABLE IF EXISTS reward_ledger;
+DROP TABLE IF EXISTS ecosystem_budgets;
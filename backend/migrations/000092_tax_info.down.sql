@@ -0,0 +1,2 @@
+ALTER TABLE ecosystems DROP COLUMN IF EXISTS requires_tax_info;
+DROP TABLE IF EXISTS tax_profiles;
@@ -0,0 +1,25 @@
+-- Tax information for programs paying fiat. Identifying fields are encrypted one by one with
+-- TAX_ENC_KEY_B64 (AES-256-GCM, nonce||ciphertext); only the country, form type and the tax ID's last
+-- four characters are stored in the clear, for review and display. Ecosystems opt in with
+-- requires_tax_info: their fiat payouts can't be approved until the recipient's tax info is verified.
+CREATE TABLE IF NOT EXISTS tax_profiles (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  form_type TEXT NOT NULL CHECK (form_type IN ('w9', 'w8ben', 'w8bene', 'other')),
+  country TEXT NOT NULL CHECK (country ~ '^[A-Z]{2}$'),
+  legal_name_enc BYTEA NOT NULL,
+  tax_id_enc BYTEA NOT NULL,
+  tax_id_last4 TEXT NOT NULL,
+  address_enc BYTEA NOT NULL,
+  -- The name typed to certify the form, and when.
+  signature_enc BYTEA NOT NULL,
+  certified_at TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'submitted' CHECK (status IN ('submitted', 'verified', 'rejected')),
+  review_note TEXT,
+  reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+  submitted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_tax_profiles_status ON tax_profiles (status, submitted_at);
+
+ALTER TABLE ecosystems ADD COLUMN IF NOT EXISTS requires_tax_info BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS kyc_events;
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_updated_at;
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_provider;
+ALTER TABLE currencies DROP COLUMN IF EXISTS kyc_threshold;
@@ -0,0 +1,18 @@
+-- Payouts above a currency's KYC threshold (in base units) need a verified recipient; NULL means none do.
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS kyc_threshold NUMERIC(78,0) CHECK (kyc_threshold >= 0);
+
+-- Which provider verified the user, and when their status last changed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_provider TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_updated_at TIMESTAMPTZ;
+
+-- Every status report from a KYC provider's webhook, for auditing how a user got their status.
+CREATE TABLE IF NOT EXISTS kyc_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL,
+  session_id TEXT NOT NULL,
+  status TEXT NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}'::jsonb,
+  received_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_kyc_events_user ON kyc_events (user_id, received_at DESC);
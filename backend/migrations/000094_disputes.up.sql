@@ -0,0 +1,38 @@
+-- Contributors dispute a rejected application, a failed or cancelled payout, or a completed issue that
+-- was never paid. A dispute is assigned to a staff member, who resolves it by upholding or denying it.
+CREATE TABLE IF NOT EXISTS disputes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('application', 'payout', 'non_payment')),
+  application_id UUID REFERENCES issue_applications(id) ON DELETE SET NULL,
+  payout_id UUID REFERENCES payouts(id) ON DELETE SET NULL,
+  project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+  issue_number INTEGER,
+  ecosystem_id UUID REFERENCES ecosystems(id) ON DELETE SET NULL,
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'upheld', 'denied', 'withdrawn')),
+  assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  resolution TEXT,
+  resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  resolved_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- One open dispute per subject.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_open_application ON disputes (application_id) WHERE status = 'open';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_open_payout ON disputes (payout_id) WHERE status = 'open';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_open_issue ON disputes (user_id, project_id, issue_number)
+  WHERE status = 'open' AND kind = 'non_payment';
+CREATE INDEX IF NOT EXISTS idx_disputes_user ON disputes (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_disputes_queue ON disputes (status, assignee_id, created_at);
+
+-- The dispute's thread: the contributor's evidence and staff replies.
+CREATE TABLE IF NOT EXISTS dispute_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+  author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  body TEXT NOT NULL,
+  links TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_dispute_messages_dispute ON dispute_messages (dispute_id, created_at);
@@ -0,0 +1,20 @@
+-- An assignee can ask to hand their issue over to another contributor (e.g. a teammate). The maintainer
+-- approves or denies; approval moves the assignment on GitHub and here, and the row stays as history.
+CREATE TABLE IF NOT EXISTS issue_handoffs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  from_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  from_login TEXT NOT NULL,
+  to_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  to_login TEXT NOT NULL,
+  reason TEXT,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied', 'cancelled')),
+  decided_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  decided_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One open request per issue at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_issue_handoffs_pending ON issue_handoffs(project_id, issue_number) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_issue_handoffs_issue ON issue_handoffs(project_id, issue_number, created_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE issue_completions DROP COLUMN IF EXISTS credits;
+DROP TABLE IF EXISTS issue_credit_splits;
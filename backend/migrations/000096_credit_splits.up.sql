@@ -0,0 +1,15 @@
+-- A maintainer can split an issue's points between contributors when it was completed across several
+-- pull requests or by several people. Percentages for an issue add up to 100 (checked by the API).
+CREATE TABLE IF NOT EXISTS issue_credit_splits (
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  issue_number INTEGER NOT NULL,
+  login TEXT NOT NULL,
+  percent INTEGER NOT NULL CHECK (percent BETWEEN 1 AND 100),
+  set_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (project_id, issue_number, login)
+);
+
+-- The completions job records the split it applied: [{login, points, percent}], empty when the assignee
+-- gets all the points.
+ALTER TABLE issue_completions ADD COLUMN IF NOT EXISTS credits JSONB NOT NULL DEFAULT '[]'::jsonb;
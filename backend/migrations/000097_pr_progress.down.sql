@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_github_prs_open_author;
+ALTER TABLE github_pull_requests DROP COLUMN IF EXISTS last_pushed_at;
+ALTER TABLE github_pull_requests DROP COLUMN IF EXISTS draft;
@@ -0,0 +1,7 @@
+-- Draft state and last push of pull requests, so maintainers can see an assignee's work in progress.
+-- last_pushed_at is set from pull_request webhooks (opened, synchronize, reopened); synced PRs that never
+-- had one fall back to their creation time.
+ALTER TABLE github_pull_requests ADD COLUMN IF NOT EXISTS draft BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE github_pull_requests ADD COLUMN IF NOT EXISTS last_pushed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_github_prs_open_author ON github_pull_requests(project_id, LOWER(author_login)) WHERE state = 'open';
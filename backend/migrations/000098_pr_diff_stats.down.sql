@@ -0,0 +1,3 @@
+ALTER TABLE github_pull_requests DROP COLUMN IF EXISTS changed_files;
+ALTER TABLE github_pull_requests DROP COLUMN IF EXISTS deletions;
+ALTER TABLE github_pull_requests DROP COLUMN IF EXISTS additions;
@@ -0,0 +1,5 @@
+-- Diff size of pull requests, for comparing points with the work behind them. NULL until known: the
+-- sync fetches it per pull request and pull_request webhooks carry it.
+ALTER TABLE github_pull_requests ADD COLUMN IF NOT EXISTS additions INTEGER;
+ALTER TABLE github_pull_requests ADD COLUMN IF NOT EXISTS deletions INTEGER;
+ALTER TABLE github_pull_requests ADD COLUMN IF NOT EXISTS changed_files INTEGER;
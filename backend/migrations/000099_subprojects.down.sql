@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS subproject_maintainers;
+DROP TABLE IF EXISTS project_subprojects;
@@ -0,0 +1,28 @@
+-- Sub-projects split a monorepo project by path or label (see package subprojects). Each has its own
+-- maintainers, who can act on the issues that belong to it, and its own ecosystem.
+CREATE TABLE IF NOT EXISTS project_subprojects (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  slug TEXT NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT,
+  ecosystem_id UUID REFERENCES ecosystems(id) ON DELETE SET NULL,
+  paths TEXT[] NOT NULL DEFAULT '{}',
+  labels TEXT[] NOT NULL DEFAULT '{}',
+  exclude_labels TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (project_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_subprojects_ecosystem ON project_subprojects(ecosystem_id) WHERE ecosystem_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS subproject_maintainers (
+  subproject_id UUID NOT NULL REFERENCES project_subprojects(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  added_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (subproject_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subproject_maintainers_user ON subproject_maintainers(user_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pr_check_runs_flagged;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS flags_reviewed_by_user_id;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS flags_reviewed_at;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS flags;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS head_repo_owner;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS head_repo_full_name;
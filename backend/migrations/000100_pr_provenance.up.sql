@@ -0,0 +1,10 @@
+-- Where a pull request's branch lives, and the provenance flags the check run publisher found (see
+-- checkruns.Review): foreign fork, commits by others, author not the assignee. A maintainer review
+-- clears them until the flags change.
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS head_repo_full_name TEXT;
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS head_repo_owner TEXT;
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS flags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS flags_reviewed_at TIMESTAMPTZ;
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS flags_reviewed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pr_check_runs_flagged ON pr_check_runs(project_id, updated_at DESC) WHERE flags <> '{}';
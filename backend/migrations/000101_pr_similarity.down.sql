@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pr_similarity_flags;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS fingerprint_sha;
+ALTER TABLE pr_check_runs DROP COLUMN IF EXISTS fingerprint;
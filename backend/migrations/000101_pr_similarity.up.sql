@@ -0,0 +1,20 @@
+-- Diff fingerprints of pull requests closing tracked issues (see package similarity), computed by the
+-- check run publisher once per head commit, and the pairs of pull requests on the same issue whose diffs
+-- are alike enough for a maintainer to look at. pr_number is the later of the pair.
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS fingerprint BIGINT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pr_check_runs ADD COLUMN IF NOT EXISTS fingerprint_sha TEXT;
+
+CREATE TABLE IF NOT EXISTS pr_similarity_flags (
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  pr_number INTEGER NOT NULL,
+  similar_pr_number INTEGER NOT NULL,
+  issue_numbers INTEGER[] NOT NULL DEFAULT '{}',
+  similarity REAL NOT NULL CHECK (similarity BETWEEN 0 AND 1),
+  reviewed_at TIMESTAMPTZ,
+  reviewed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (project_id, pr_number, similar_pr_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_similarity_flags_issues ON pr_similarity_flags USING GIN (issue_numbers);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_github_issues_open;
+DROP INDEX IF EXISTS idx_github_prs_project_author;
+DROP INDEX IF EXISTS idx_github_issues_project_author;
+DROP INDEX IF EXISTS idx_projects_ecosystem_listed;
@@ -0,0 +1,18 @@
+-- Indexes for the ecosystem page stats (see EcosystemsPublicHandler.GetByID): the listed projects of an
+-- ecosystem, and per-project authors and open issues, so the counts come from index-only scans instead of
+-- reading every issue and pull request of the ecosystem.
+CREATE INDEX IF NOT EXISTS idx_projects_ecosystem_listed ON projects(ecosystem_id)
+  WHERE deleted_at IS NULL AND status = 'verified' AND needs_metadata = false;
+CREATE INDEX IF NOT EXISTS idx_github_issues_project_author ON github_issues(project_id, author_login);
+CREATE INDEX IF NOT EXISTS idx_github_prs_project_author ON github_pull_requests(project_id, author_login);
+CREATE INDEX IF NOT EXISTS idx_github_issues_open ON github_issues(project_id) WHERE state = 'open';
+
+-- Query statistics for GET /admin/query-stats. The module must also be in shared_preload_libraries; where
+-- it isn't available (or we may not create extensions) the report says so instead of failing.
+DO $$
+BEGIN
+  CREATE EXTENSION IF NOT EXISTS pg_stat_statements;
+EXCEPTION WHEN OTHERS THEN
+  RAISE NOTICE 'pg_stat_statements not available: %', SQLERRM;
+END
+$$;
@@ -0,0 +1,10 @@
+UPDATE issue_promotions SET status = 'pending' WHERE status = 'sending';
+
+DROP INDEX IF EXISTS idx_issue_promotions_due;
+CREATE INDEX IF NOT EXISTS idx_issue_promotions_due ON issue_promotions(scheduled_for) WHERE status = 'pending';
+
+ALTER TABLE issue_promotions
+  DROP CONSTRAINT IF EXISTS issue_promotions_status_check;
+
+ALTER TABLE issue_promotions
+  ADD CONSTRAINT issue_promotions_status_check CHECK (status IN ('pending', 'sent', 'failed', 'cancelled'));
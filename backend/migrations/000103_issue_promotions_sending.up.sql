@@ -0,0 +1,11 @@
+-- Deliveries are claimed before they are posted ('sending'), so two workers never post the same one.
+-- scheduled_for doubles as the claim's lease: a delivery still 'sending' once it has passed (its worker
+-- stopped mid-post) is claimed again.
+ALTER TABLE issue_promotions
+  DROP CONSTRAINT IF EXISTS issue_promotions_status_check;
+
+ALTER TABLE issue_promotions
+  ADD CONSTRAINT issue_promotions_status_check CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'cancelled'));
+
+DROP INDEX IF EXISTS idx_issue_promotions_due;
+CREATE INDEX IF NOT EXISTS idx_issue_promotions_due ON issue_promotions(scheduled_for) WHERE status IN ('pending', 'sending');